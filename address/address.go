@@ -0,0 +1,93 @@
+package address
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+type ParseError struct {
+	Address string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("%q is not a valid email address", e.Address)
+}
+
+type Address struct {
+	Name   string
+	Local  string
+	Domain string
+}
+
+// Parse accepts an RFC 5322 address, with or without a display name, and
+// normalizes its domain to lowercase ASCII, converting IDN domains to
+// punycode.
+func Parse(raw string) (Address, error) {
+	raw = strings.TrimSpace(raw)
+
+	parsed, err := mail.ParseAddress(raw)
+	if err != nil {
+		return Address{}, ParseError{Address: raw}
+	}
+
+	at := strings.LastIndex(parsed.Address, "@")
+	if at <= 0 || at == len(parsed.Address)-1 {
+		return Address{}, ParseError{Address: raw}
+	}
+
+	domain, err := NormalizeDomain(parsed.Address[at+1:])
+	if err != nil {
+		return Address{}, ParseError{Address: raw}
+	}
+
+	return Address{
+		Name:   parsed.Name,
+		Local:  parsed.Address[:at],
+		Domain: domain,
+	}, nil
+}
+
+// NormalizeDomain converts a domain to the lowercase ASCII form used by Parse.
+func NormalizeDomain(domain string) (string, error) {
+	domain, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(domain), nil
+}
+
+// Primary picks the first of a user's addresses that can be parsed, falling
+// back to the first address so that it is reported as malformed.
+func Primary(emails []string) string {
+	for _, email := range emails {
+		if _, err := Parse(email); err == nil {
+			return email
+		}
+	}
+
+	if len(emails) > 0 {
+		return emails[0]
+	}
+
+	return ""
+}
+
+// Email returns the bare addr-spec, quoting the local part when required.
+func (a Address) Email() string {
+	formatted := (&mail.Address{Address: a.Local + "@" + a.Domain}).String()
+
+	return strings.TrimSuffix(strings.TrimPrefix(formatted, "<"), ">")
+}
+
+// String returns the address as it should appear in a message header.
+func (a Address) String() string {
+	if a.Name == "" {
+		return a.Email()
+	}
+
+	return (&mail.Address{Name: a.Name, Address: a.Local + "@" + a.Domain}).String()
+}
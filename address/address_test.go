@@ -0,0 +1,80 @@
+package address_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/address"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Address", func() {
+	Describe("Parse", func() {
+		It("parses a bare address", func() {
+			parsed, err := address.Parse("user@example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(address.Address{
+				Local:  "user",
+				Domain: "example.com",
+			}))
+			Expect(parsed.Email()).To(Equal("user@example.com"))
+			Expect(parsed.String()).To(Equal("user@example.com"))
+		})
+
+		It("parses an address with a display name", func() {
+			parsed, err := address.Parse(" The User <user@Example.COM> ")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Name).To(Equal("The User"))
+			Expect(parsed.Email()).To(Equal("user@example.com"))
+			Expect(parsed.String()).To(Equal(`"The User" <user@example.com>`))
+		})
+
+		It("keeps quoted local parts quoted", func() {
+			parsed, err := address.Parse(`"first last"@example.com`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Local).To(Equal("first last"))
+			Expect(parsed.Email()).To(Equal(`"first last"@example.com`))
+		})
+
+		It("converts IDN domains to punycode", func() {
+			parsed, err := address.Parse("Jürgen <jürgen@bücher.example>")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Domain).To(Equal("xn--bcher-kva.example"))
+			Expect(parsed.Email()).To(Equal("jürgen@xn--bcher-kva.example"))
+			Expect(parsed.String()).To(Equal("=?utf-8?q?J=C3=BCrgen?= <jürgen@xn--bcher-kva.example>"))
+		})
+
+		It("rejects invalid addresses, naming them in the error", func() {
+			for _, raw := range []string{"invalidemail.com", "<invalid email", "user@", "user@exa_mple..com", ""} {
+				_, err := address.Parse(raw)
+				Expect(err).To(MatchError(address.ParseError{Address: raw}))
+			}
+		})
+
+		It("names the address in the error message", func() {
+			_, err := address.Parse("nope")
+			Expect(err).To(MatchError(`"nope" is not a valid email address`))
+		})
+	})
+
+	Describe("NormalizeDomain", func() {
+		It("lowercases the domain and converts it to punycode", func() {
+			domain, err := address.NormalizeDomain("Bücher.Example")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(domain).To(Equal("xn--bcher-kva.example"))
+		})
+	})
+
+	Describe("Primary", func() {
+		It("picks the first address that can be parsed", func() {
+			Expect(address.Primary([]string{"not an address", "user@example.com", "other@example.com"})).To(Equal("user@example.com"))
+		})
+
+		It("falls back to the first address when none can be parsed", func() {
+			Expect(address.Primary([]string{"not an address", "nope"})).To(Equal("not an address"))
+		})
+
+		It("returns an empty string when there are no addresses", func() {
+			Expect(address.Primary(nil)).To(Equal(""))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package address_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAddressSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "address")
+}
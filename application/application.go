@@ -157,10 +157,11 @@ func (a Application) StartMessageGC() {
 	messageLifetime := 24 * time.Hour
 	db := a.dbProvider.Database()
 	messagesRepo := a.dbProvider.MessagesRepo()
+	dedupEntriesRepo := models.NewDedupEntriesRepo()
 	pollingInterval := 1 * time.Hour
 
 	logger := log.New(os.Stdout, "", 0)
-	messageGC := postal.NewMessageGC(messageLifetime, db, messagesRepo, pollingInterval, logger)
+	messageGC := postal.NewMessageGC(messageLifetime, db, messagesRepo, dedupEntriesRepo, pollingInterval, logger)
 	messageGC.Run()
 }
 
@@ -181,6 +182,8 @@ func (a Application) StartServer(logger lager.Logger, validator *uaa.TokenValida
 		UAAClientSecret:   a.env.UAAClientSecret,
 		DefaultUAAScopes:  a.env.DefaultUAAScopes,
 		CCHost:            a.env.CCHost,
+
+		AllowedSenderDomains: a.env.AllowedSenderDomains,
 	})
 }
 
@@ -7,11 +7,13 @@ import (
 	"path"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/address"
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/ryanmoran/viron"
 )
 
 type Environment struct {
+	AllowedSenderDomainsList           string `env:"ALLOWED_SENDER_DOMAINS"`
 	CCHost                             string `env:"CC_HOST" env-required:"true"`
 	CORSOrigin                         string `env:"CORS_ORIGIN" env-default:"*"`
 	DBLoggingEnabled                   bool   `env:"DB_LOGGING_ENABLED"`
@@ -49,6 +51,7 @@ type Environment struct {
 	ModelMigrationsPath  string
 	GobbleMigrationsPath string
 	DefaultUAAScopes     []string
+	AllowedSenderDomains []string
 }
 
 type EnvironmentError struct {
@@ -80,6 +83,7 @@ func NewEnvironment() (Environment, error) {
 
 	env.inferMigrationsDirs()
 	env.parseDefaultUAAScopes()
+	env.parseAllowedSenderDomains()
 
 	return env, nil
 }
@@ -88,6 +92,24 @@ func (env *Environment) parseDefaultUAAScopes() {
 	env.DefaultUAAScopes = strings.Split(env.DefaultUAAScopesList, ",")
 }
 
+func (env *Environment) parseAllowedSenderDomains() {
+	env.AllowedSenderDomains = []string{}
+	for _, domain := range strings.Split(env.AllowedSenderDomainsList, ",") {
+		domain = strings.TrimSpace(domain)
+		if domain != "" {
+			env.AllowedSenderDomains = append(env.AllowedSenderDomains, domain)
+		}
+	}
+
+	if len(env.AllowedSenderDomains) > 0 {
+		return
+	}
+
+	if sender, err := address.Parse(env.Sender); err == nil {
+		env.AllowedSenderDomains = append(env.AllowedSenderDomains, sender.Domain)
+	}
+}
+
 func (env *Environment) expandRoot() {
 	env.RootPath = os.ExpandEnv(env.RootPath)
 }
@@ -14,6 +14,7 @@ import (
 var _ = Describe("Environment", func() {
 	var variables = map[string]string{}
 	var envVars = []string{
+		"ALLOWED_SENDER_DOMAINS",
 		"CC_HOST",
 		"CORS_ORIGIN",
 		"DATABASE_URL",
@@ -491,6 +492,28 @@ var _ = Describe("Environment", func() {
 		})
 	})
 
+	Describe("Allowed sender domains", func() {
+		It("sets the value if present", func() {
+			os.Setenv("ALLOWED_SENDER_DOMAINS", "example.com, billing.example.com")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.AllowedSenderDomains).To(Equal([]string{
+				"example.com",
+				"billing.example.com",
+			}))
+		})
+
+		It("defaults to the domain of the sender", func() {
+			os.Setenv("ALLOWED_SENDER_DOMAINS", "")
+			os.Setenv("SENDER", "Notifications <no-reply@notifications.example.com>")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.AllowedSenderDomains).To(Equal([]string{"notifications.example.com"}))
+		})
+	})
+
 	Describe("Domain", func() {
 		It("sets the Domain", func() {
 			os.Setenv("DOMAIN", "example.com")
@@ -1,13 +1,23 @@
 package cf
 
 import (
+	"crypto/tls"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/pivotal-cf-experimental/rainmaker"
 )
 
+// requestTimeout bounds the requests the CloudController makes itself, so
+// that a Cloud Controller that stops responding does not hold a send
+// forever.
+const requestTimeout = 30 * time.Second
+
 type CloudController struct {
-	client rainmaker.Client
+	client     rainmaker.Client
+	host       string
+	httpClient *http.Client
 }
 
 func NewCloudController(host string, skipVerifySSL bool) CloudController {
@@ -16,6 +26,13 @@ func NewCloudController(host string, skipVerifySSL bool) CloudController {
 			Host:          host,
 			SkipVerifySSL: skipVerifySSL,
 		}),
+		host: host,
+		httpClient: &http.Client{
+			Timeout: requestTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: skipVerifySSL},
+			},
+		},
 	}
 }
 
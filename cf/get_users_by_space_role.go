@@ -0,0 +1,81 @@
+package cf
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+func (cc CloudController) GetDevelopersBySpaceGuid(guid, token string) ([]CloudControllerUser, error) {
+	return cc.getUsersBySpaceRole(guid, "developers", token)
+}
+
+func (cc CloudController) GetManagersBySpaceGuid(guid, token string) ([]CloudControllerUser, error) {
+	return cc.getUsersBySpaceRole(guid, "managers", token)
+}
+
+func (cc CloudController) GetAuditorsBySpaceGuid(guid, token string) ([]CloudControllerUser, error) {
+	return cc.getUsersBySpaceRole(guid, "auditors", token)
+}
+
+type usersListPage struct {
+	NextURL   string `json:"next_url"`
+	Resources []struct {
+		Metadata struct {
+			GUID string `json:"guid"`
+		} `json:"metadata"`
+	} `json:"resources"`
+}
+
+// getUsersBySpaceRole lists the members of a space role. The rainmaker client
+// does not cover the space role routes, so they are requested directly,
+// following the listing across all of its pages.
+func (cc CloudController) getUsersBySpaceRole(guid, role, token string) ([]CloudControllerUser, error) {
+	then := time.Now()
+
+	ccUsers := []CloudControllerUser{}
+	path := "/v2/spaces/" + guid + "/" + role
+	for path != "" {
+		request, err := http.NewRequest("GET", cc.host+path, nil)
+		if err != nil {
+			return []CloudControllerUser{}, NewFailure(0, err.Error())
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		response, err := cc.httpClient.Do(request)
+		if err != nil {
+			return []CloudControllerUser{}, NewFailure(0, err.Error())
+		}
+
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return []CloudControllerUser{}, NewFailure(0, err.Error())
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return []CloudControllerUser{}, NewFailure(response.StatusCode, string(body))
+		}
+
+		var page usersListPage
+		err = json.Unmarshal(body, &page)
+		if err != nil {
+			return []CloudControllerUser{}, NewFailure(0, err.Error())
+		}
+
+		for _, resource := range page.Resources {
+			ccUsers = append(ccUsers, CloudControllerUser{
+				GUID: resource.Metadata.GUID,
+			})
+		}
+
+		path = page.NextURL
+	}
+
+	metrics.GetOrRegisterTimer("notifications.external-requests.cc."+role+"-by-space-guid", nil).Update(time.Since(then))
+
+	return ccUsers, nil
+}
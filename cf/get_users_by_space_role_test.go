@@ -0,0 +1,105 @@
+package cf_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetUsersBySpaceRole", func() {
+	var (
+		CCServer        *httptest.Server
+		cloudController cf.CloudController
+		requestedPaths  []string
+	)
+
+	userResource := func(guid string) string {
+		return `{"metadata": {"guid": "` + guid + `", "url": "/v2/users/` + guid + `"}, "entity": {"admin": false, "active": true}}`
+	}
+
+	BeforeEach(func() {
+		requestedPaths = []string{}
+
+		CCServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+			if token != testUAAToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"code":10002,"description":"Authentication error","error_code":"CF-NotAuthenticated"}`))
+				return
+			}
+
+			requestedPaths = append(requestedPaths, req.URL.RequestURI())
+
+			switch req.URL.RequestURI() {
+			case "/v2/spaces/test-space-guid/developers":
+				w.Write([]byte(`{
+					"total_results": 2,
+					"total_pages": 2,
+					"prev_url": null,
+					"next_url": "/v2/spaces/test-space-guid/developers?page=2",
+					"resources": [` + userResource("user-123") + `]
+				}`))
+			case "/v2/spaces/test-space-guid/developers?page=2":
+				w.Write([]byte(`{
+					"total_results": 2,
+					"total_pages": 2,
+					"prev_url": "/v2/spaces/test-space-guid/developers?page=1",
+					"next_url": null,
+					"resources": [` + userResource("user-456") + `]
+				}`))
+			case "/v2/spaces/test-space-guid/managers":
+				w.Write([]byte(`{"total_results": 1, "total_pages": 1, "resources": [` + userResource("user-789") + `]}`))
+			case "/v2/spaces/test-space-guid/auditors":
+				w.Write([]byte(`{"total_results": 1, "total_pages": 1, "resources": [` + userResource("user-000") + `]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code":40004,"description":"The app space could not be found","error_code":"CF-SpaceNotFound"}`))
+			}
+		}))
+
+		cloudController = cf.NewCloudController(CCServer.URL, false)
+	})
+
+	AfterEach(func() {
+		CCServer.Close()
+	})
+
+	It("returns the developers of the space across every page", func() {
+		users, err := cloudController.GetDevelopersBySpaceGuid(testSpaceGuid, testUAAToken)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(users).To(Equal([]cf.CloudControllerUser{
+			{GUID: "user-123"},
+			{GUID: "user-456"},
+		}))
+		Expect(requestedPaths).To(Equal([]string{
+			"/v2/spaces/test-space-guid/developers",
+			"/v2/spaces/test-space-guid/developers?page=2",
+		}))
+	})
+
+	It("returns the managers of the space", func() {
+		users, err := cloudController.GetManagersBySpaceGuid(testSpaceGuid, testUAAToken)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(Equal([]cf.CloudControllerUser{{GUID: "user-789"}}))
+	})
+
+	It("returns the auditors of the space", func() {
+		users, err := cloudController.GetAuditorsBySpaceGuid(testSpaceGuid, testUAAToken)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(Equal([]cf.CloudControllerUser{{GUID: "user-000"}}))
+	})
+
+	It("returns an error when the Cloud Controller returns an error status code", func() {
+		_, err := cloudController.GetAuditorsBySpaceGuid(testSpaceGuid, "bad-token")
+		Expect(err).To(BeAssignableToTypeOf(cf.Failure{}))
+
+		_, err = cloudController.GetDevelopersBySpaceGuid("missing-space-guid", testUAAToken)
+		Expect(err).To(Equal(cf.NewFailure(http.StatusNotFound, `{"code":40004,"description":"The app space could not be found","error_code":"CF-SpaceNotFound"}`)))
+	})
+})
@@ -0,0 +1,225 @@
+// Package cssinline moves the rules of the <style> elements of an HTML email
+// into the style attributes of the elements they apply to, as many mail
+// clients drop <style> elements. Rules that cannot be expressed in a style
+// attribute, such as @media blocks and rules with pseudo-classes, are kept in
+// a <style> element in the head.
+package cssinline
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type match struct {
+	declaration declaration
+	specificity int
+	order       int
+	inline      bool
+}
+
+// Inline returns the document with its stylesheet rules inlined.
+func Inline(document string) (string, error) {
+	parsed, err := goquery.NewDocumentFromReader(strings.NewReader(document))
+	if err != nil {
+		return "", err
+	}
+
+	root := parsed.Nodes[0]
+	matches := map[*html.Node][]match{}
+	var preserved []string
+	order := 0
+
+	for _, style := range parsed.Find("style").Nodes {
+		if !inlinable(style) {
+			continue
+		}
+
+		for _, rule := range parseStylesheet(text(style)) {
+			if rule.verbatim != "" {
+				preserved = append(preserved, rule.verbatim)
+				continue
+			}
+
+			var kept []string
+
+			for _, selector := range rule.selectors {
+				compiled, err := compile(selector)
+				if err != nil {
+					kept = append(kept, selector)
+					continue
+				}
+
+				for _, node := range compiled.MatchAll(root) {
+					for _, declaration := range rule.declarations {
+						matches[node] = append(matches[node], match{
+							declaration: declaration,
+							specificity: specificity(selector),
+							order:       order,
+						})
+						order++
+					}
+				}
+			}
+
+			if len(kept) > 0 {
+				preserved = append(preserved, strings.Join(kept, ", ")+" {"+formatDeclarations(rule.declarations)+"}")
+			}
+		}
+
+		style.Parent.RemoveChild(style)
+	}
+
+	for node, nodeMatches := range matches {
+		setStyle(node, nodeMatches)
+	}
+
+	if len(preserved) > 0 {
+		addStyle(parsed, strings.Join(preserved, "\n"))
+	}
+
+	var rendered bytes.Buffer
+	err = html.Render(&rendered, root)
+	if err != nil {
+		return "", err
+	}
+
+	return rendered.String(), nil
+}
+
+// inlinable reports whether a <style> element applies to every medium a
+// message is displayed on, so that its rules can be inlined.
+func inlinable(style *html.Node) bool {
+	media := strings.ToLower(strings.TrimSpace(attribute(style, "media")))
+	return media == "" || media == "all" || media == "screen"
+}
+
+// compile compiles a selector that can be inlined. Selectors with pseudo
+// classes or elements, such as :hover, only apply in some states of an
+// element and are reported as errors so that they are kept.
+func compile(selector string) (cascadia.Selector, error) {
+	if strings.Contains(selector, ":") {
+		return nil, errors.New("pseudo-classes and pseudo-elements cannot be inlined")
+	}
+
+	return cascadia.Compile(selector)
+}
+
+// setStyle merges the matching declarations into the style attribute of a
+// node, following the cascade: the style attribute wins over the
+// stylesheet unless the stylesheet marks a declaration !important.
+func setStyle(node *html.Node, matches []match) {
+	for _, declaration := range parseDeclarations(attribute(node, "style")) {
+		matches = append(matches, match{
+			declaration: declaration,
+			inline:      true,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if level(a) != level(b) {
+			return level(a) < level(b)
+		}
+		if a.specificity != b.specificity {
+			return a.specificity < b.specificity
+		}
+		return a.order < b.order
+	})
+
+	var properties []string
+	values := map[string]declaration{}
+	for _, m := range matches {
+		if _, ok := values[m.declaration.property]; !ok {
+			properties = append(properties, m.declaration.property)
+		}
+		values[m.declaration.property] = m.declaration
+	}
+
+	var declarations []declaration
+	for _, property := range properties {
+		declaration := values[property]
+		declaration.important = false
+		declarations = append(declarations, declaration)
+	}
+
+	setAttribute(node, "style", formatDeclarations(declarations))
+}
+
+// level orders the origins of declarations in the cascade.
+func level(m match) int {
+	switch {
+	case m.inline && m.declaration.important:
+		return 3
+	case m.declaration.important:
+		return 2
+	case m.inline:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func formatDeclarations(declarations []declaration) string {
+	var formatted []string
+	for _, declaration := range declarations {
+		value := declaration.value
+		if declaration.important {
+			value += " !important"
+		}
+		formatted = append(formatted, declaration.property+": "+value)
+	}
+
+	return strings.Join(formatted, "; ")
+}
+
+// addStyle adds a <style> element holding the rules that were not inlined
+// to the head of the document.
+func addStyle(document *goquery.Document, rules string) {
+	head := document.Find("head").Nodes
+	if len(head) == 0 {
+		return
+	}
+
+	style := &html.Node{Type: html.ElementNode, DataAtom: atom.Style, Data: "style"}
+	style.AppendChild(&html.Node{Type: html.TextNode, Data: rules})
+	head[0].AppendChild(style)
+}
+
+func text(node *html.Node) string {
+	var content strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.TextNode {
+			content.WriteString(child.Data)
+		}
+	}
+
+	return content.String()
+}
+
+func attribute(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+func setAttribute(node *html.Node, key, value string) {
+	for i, attr := range node.Attr {
+		if attr.Key == key {
+			node.Attr[i].Val = value
+			return
+		}
+	}
+
+	node.Attr = append(node.Attr, html.Attribute{Key: key, Val: value})
+}
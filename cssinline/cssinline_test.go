@@ -0,0 +1,46 @@
+package cssinline_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/cssinline"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Inline", func() {
+	inline := func(document string) string {
+		inlined, err := cssinline.Inline(document)
+		Expect(err).NotTo(HaveOccurred())
+		return inlined
+	}
+
+	It("moves stylesheet rules into style attributes", func() {
+		Expect(inline(`<html><head><style>p { color: red; margin: 0 } .note { color: blue }</style></head><body><p>plain</p><p class="note">note</p></body></html>`)).To(Equal(
+			`<html><head></head><body><p style="color: red; margin: 0">plain</p><p class="note" style="color: blue; margin: 0">note</p></body></html>`))
+	})
+
+	It("applies rules by specificity and then by order", func() {
+		Expect(inline(`<style>#title { color: green } h1.big { color: blue } h1 { color: red; font-size: 20px } h1 { font-size: 30px }</style><h1 id="title" class="big">Hi</h1>`)).To(Equal(
+			`<html><head></head><body><h1 id="title" class="big" style="color: green; font-size: 30px">Hi</h1></body></html>`))
+	})
+
+	It("lets the style attribute win unless the stylesheet is !important", func() {
+		Expect(inline(`<style>td { color: red; padding: 4px !important }</style><table><tr><td style="color: black; padding: 0">cell</td></tr></table>`)).To(Equal(
+			`<html><head></head><body><table><tbody><tr><td style="color: black; padding: 4px">cell</td></tr></tbody></table></body></html>`))
+	})
+
+	It("keeps media queries and pseudo-classes in the head", func() {
+		Expect(inline(`<html><head><style>/* comment */ a { color: red } a:hover { color: blue } @media (max-width: 600px) { a { color: green } }</style></head><body><a href="https://example.com">link</a></body></html>`)).To(Equal(
+			"<html><head><style>a:hover {color: blue}\n@media (max-width: 600px) { a { color: green } }</style></head><body><a href=\"https://example.com\" style=\"color: red\">link</a></body></html>"))
+	})
+
+	It("leaves style elements for other media alone", func() {
+		Expect(inline(`<html><head><style media="print">p { color: black }</style></head><body><p>text</p></body></html>`)).To(Equal(
+			`<html><head><style media="print">p { color: black }</style></head><body><p>text</p></body></html>`))
+	})
+
+	It("keeps values containing semicolons and selectors it cannot parse", func() {
+		Expect(inline(`<style>div { background: url("data:image/png;base64,AAA") } div[ { color: red }</style><div>x</div>`)).To(Equal(
+			"<html><head><style>div[ {color: red}</style></head><body><div style=\"background: url(&#34;data:image/png;base64,AAA&#34;)\">x</div></body></html>"))
+	})
+})
@@ -0,0 +1,13 @@
+package cssinline_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCSSInlineSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "cssinline")
+}
@@ -0,0 +1,207 @@
+package cssinline
+
+import (
+	"regexp"
+	"strings"
+)
+
+var commentPattern = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+// rule is a style rule of a stylesheet, or, when verbatim is set, a
+// statement such as an @media block that can only be kept as it is.
+type rule struct {
+	selectors    []string
+	declarations []declaration
+	verbatim     string
+}
+
+type declaration struct {
+	property  string
+	value     string
+	important bool
+}
+
+// parseStylesheet splits a stylesheet into its rules, in order.
+func parseStylesheet(source string) []rule {
+	source = commentPattern.ReplaceAllString(source, "")
+
+	var rules []rule
+
+	for i := 0; i < len(source); {
+		end := scanUntil(source, i, "{;")
+		prelude := strings.TrimSpace(source[i:end])
+
+		if end >= len(source) {
+			if prelude != "" {
+				rules = append(rules, rule{verbatim: prelude})
+			}
+			break
+		}
+
+		if source[end] == ';' {
+			if prelude != "" {
+				rules = append(rules, rule{verbatim: prelude + ";"})
+			}
+			i = end + 1
+			continue
+		}
+
+		closing := matchingBrace(source, end)
+		body := source[end+1 : closing]
+
+		switch {
+		case strings.HasPrefix(prelude, "@"):
+			rules = append(rules, rule{verbatim: prelude + " {" + body + "}"})
+		case prelude != "":
+			rules = append(rules, rule{
+				selectors:    splitOutside(prelude, ','),
+				declarations: parseDeclarations(body),
+			})
+		}
+
+		i = closing + 1
+	}
+
+	return rules
+}
+
+// parseDeclarations parses the declarations of a rule or of a style
+// attribute, dropping any it cannot make sense of.
+func parseDeclarations(source string) []declaration {
+	var declarations []declaration
+
+	for _, part := range splitOutside(source, ';') {
+		colon := strings.Index(part, ":")
+		if colon <= 0 {
+			continue
+		}
+
+		property := strings.ToLower(strings.TrimSpace(part[:colon]))
+		value := strings.TrimSpace(part[colon+1:])
+
+		important := false
+		if bang := strings.LastIndex(value, "!"); bang >= 0 && strings.EqualFold(strings.TrimSpace(value[bang+1:]), "important") {
+			important = true
+			value = strings.TrimSpace(value[:bang])
+		}
+
+		if property == "" || value == "" {
+			continue
+		}
+
+		declarations = append(declarations, declaration{
+			property:  property,
+			value:     value,
+			important: important,
+		})
+	}
+
+	return declarations
+}
+
+// scanUntil returns the index of the first of the stop characters at or
+// after start that is not inside a string or parentheses, or the length of
+// source when there is none.
+func scanUntil(source string, start int, stops string) int {
+	depth := 0
+	var quote byte
+
+	for i := start; i < len(source); i++ {
+		c := source[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case depth == 0 && strings.IndexByte(stops, c) >= 0:
+			return i
+		}
+	}
+
+	return len(source)
+}
+
+// matchingBrace returns the index of the "}" closing the block opened at
+// open, or the length of source when the block is not closed.
+func matchingBrace(source string, open int) int {
+	depth := 0
+
+	for i := open; i < len(source); {
+		i = scanUntil(source, i, "{}")
+		if i >= len(source) {
+			break
+		}
+
+		if source[i] == '{' {
+			depth++
+		} else {
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+		i++
+	}
+
+	return len(source)
+}
+
+func splitOutside(source string, separator byte) []string {
+	var parts []string
+
+	for i := 0; i <= len(source); {
+		end := scanUntil(source, i, string(separator))
+		if part := strings.TrimSpace(source[i:end]); part != "" {
+			parts = append(parts, part)
+		}
+		i = end + 1
+	}
+
+	return parts
+}
+
+// specificity computes the specificity of a selector as a single number
+// ordering ids over classes and attributes over element names.
+func specificity(selector string) int {
+	ids, classes, elements := 0, 0, 0
+	compoundStart := true
+
+	for i := 0; i < len(selector); i++ {
+		c := selector[i]
+
+		switch {
+		case c == '#':
+			ids++
+			compoundStart = false
+		case c == '.':
+			classes++
+			compoundStart = false
+		case c == '[':
+			classes++
+			i = scanUntil(selector, i, "]")
+			compoundStart = false
+		case c == ' ' || c == '>' || c == '+' || c == '~':
+			compoundStart = true
+		case c == '*':
+			compoundStart = false
+		case compoundStart && isNameStart(c):
+			elements++
+			compoundStart = false
+		}
+	}
+
+	return ids*10000 + classes*100 + elements
+}
+
+func isNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
@@ -0,0 +1,18 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `sender_identities` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `client_id` varchar(255) NOT NULL,
+      `kind_id` varchar(255) NOT NULL DEFAULT '',
+      `name` varchar(255) DEFAULT NULL,
+      `address` varchar(255) NOT NULL,
+      `reply_to` varchar(255) DEFAULT NULL,
+      `created_at` datetime DEFAULT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`primary`),
+      UNIQUE KEY `client_id_kind_id` (`client_id`,`kind_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `sender_identities`;
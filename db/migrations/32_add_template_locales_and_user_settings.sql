@@ -0,0 +1,29 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `template_locales` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `template_id` varchar(255) NOT NULL,
+      `locale` varchar(35) NOT NULL,
+      `subject` varchar(255) DEFAULT NULL,
+      `text` longtext DEFAULT NULL,
+      `html` longtext DEFAULT NULL,
+      `created_at` datetime DEFAULT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`primary`),
+      UNIQUE KEY `template_id_locale` (`template_id`,`locale`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `user_settings` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `user_id` varchar(255) NOT NULL,
+      `locale` varchar(35) NOT NULL DEFAULT '',
+      `created_at` datetime DEFAULT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`primary`),
+      UNIQUE KEY `user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `user_settings`;
+DROP TABLE `template_locales`;
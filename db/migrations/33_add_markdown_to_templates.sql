@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `templates` ADD COLUMN `markdown` longtext DEFAULT NULL;
+ALTER TABLE `template_versions` ADD COLUMN `markdown` longtext DEFAULT NULL;
+ALTER TABLE `template_locales` ADD COLUMN `markdown` longtext DEFAULT NULL;
+
+UPDATE `templates` SET `markdown` = '';
+UPDATE `template_versions` SET `markdown` = '';
+UPDATE `template_locales` SET `markdown` = '';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `template_locales` DROP COLUMN `markdown`;
+ALTER TABLE `template_versions` DROP COLUMN `markdown`;
+ALTER TABLE `templates` DROP COLUMN `markdown`;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `kinds` ADD COLUMN `skip_text_alternative` tinyint(1) NOT NULL DEFAULT 0;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `kinds` DROP COLUMN `skip_text_alternative`;
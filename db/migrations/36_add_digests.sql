@@ -0,0 +1,33 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `user_settings` ADD COLUMN `digest` varchar(10) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS `digest_preferences` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `user_id` varchar(255) NOT NULL,
+      `client_id` varchar(255) NOT NULL,
+      `kind_id` varchar(255) NOT NULL,
+      `digest` varchar(10) NOT NULL,
+      `created_at` datetime DEFAULT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`primary`),
+      UNIQUE KEY `user_id_client_id_kind_id` (`user_id`,`client_id`,`kind_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `digest_items` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `user_guid` varchar(255) NOT NULL,
+      `digest` varchar(10) NOT NULL,
+      `message_id` varchar(255) NOT NULL,
+      `delivery` longtext NOT NULL,
+      `due_at` datetime NOT NULL,
+      `created_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`primary`),
+      KEY `due_at` (`due_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `digest_items`;
+DROP TABLE `digest_preferences`;
+ALTER TABLE `user_settings` DROP COLUMN `digest`;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `user_settings` ADD COLUMN `timezone` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `user_settings` ADD COLUMN `quiet_hours_start` varchar(5) NOT NULL DEFAULT '';
+ALTER TABLE `user_settings` ADD COLUMN `quiet_hours_end` varchar(5) NOT NULL DEFAULT '';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `user_settings` DROP COLUMN `quiet_hours_end`;
+ALTER TABLE `user_settings` DROP COLUMN `quiet_hours_start`;
+ALTER TABLE `user_settings` DROP COLUMN `timezone`;
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `kinds` ADD COLUMN `dedup_window` int(11) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS `dedup_entries` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `recipient` varchar(255) NOT NULL,
+      `client_id` varchar(255) NOT NULL,
+      `kind_id` varchar(255) NOT NULL,
+      `dedup_key` varchar(255) NOT NULL DEFAULT '',
+      `message_id` varchar(255) NOT NULL,
+      `expires_at` datetime NOT NULL,
+      PRIMARY KEY (`primary`),
+      UNIQUE KEY `recipient_client_id_kind_id_dedup_key` (`recipient`,`client_id`,`kind_id`,`dedup_key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `dedup_entries`;
+ALTER TABLE `kinds` DROP COLUMN `dedup_window`;
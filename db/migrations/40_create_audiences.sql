@@ -0,0 +1,18 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `audiences` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `id` varchar(255) NOT NULL,
+      `client_id` varchar(255) NOT NULL,
+      `name` varchar(255) NOT NULL,
+      `targets` longtext NOT NULL,
+      `created_at` datetime DEFAULT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`primary`),
+      UNIQUE KEY `id` (`id`),
+      KEY `client_id` (`client_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `audiences`;
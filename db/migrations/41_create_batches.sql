@@ -0,0 +1,26 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `batches` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `id` varchar(255) NOT NULL,
+      `client_id` varchar(255) NOT NULL,
+      `kind_id` varchar(255) NOT NULL,
+      `total_messages` int(11) NOT NULL DEFAULT 0,
+      `queued_messages` int(11) NOT NULL DEFAULT 0,
+      `sent_messages` int(11) NOT NULL DEFAULT 0,
+      `failed_messages` int(11) NOT NULL DEFAULT 0,
+      `undeliverable_messages` int(11) NOT NULL DEFAULT 0,
+      `expired_messages` int(11) NOT NULL DEFAULT 0,
+      `start_time` datetime DEFAULT NULL,
+      `completed_time` datetime DEFAULT NULL,
+      PRIMARY KEY (`primary`),
+      UNIQUE KEY `id` (`id`),
+      KEY `client_id_start_time` (`client_id`, `start_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `messages` ADD COLUMN `batch_id` varchar(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `messages` DROP COLUMN `batch_id`;
+DROP TABLE `batches`;
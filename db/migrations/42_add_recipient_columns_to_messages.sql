@@ -0,0 +1,31 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `messages` ADD COLUMN `user_guid` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `messages` ADD COLUMN `email` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `messages` ADD COLUMN `client_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `messages` ADD COLUMN `kind_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `messages` ADD COLUMN `vcap_request_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `messages` ADD COLUMN `created_at` datetime DEFAULT NULL;
+UPDATE `messages` SET `created_at` = `updated_at`;
+ALTER TABLE `messages` MODIFY COLUMN `created_at` datetime NOT NULL;
+
+ALTER TABLE `messages` ADD KEY `client_id_created_at` (`client_id`, `created_at`);
+ALTER TABLE `messages` ADD KEY `client_id_user_guid` (`client_id`, `user_guid`);
+ALTER TABLE `messages` ADD KEY `client_id_email` (`client_id`, `email`);
+ALTER TABLE `messages` ADD KEY `client_id_kind_id` (`client_id`, `kind_id`);
+ALTER TABLE `messages` ADD KEY `client_id_vcap_request_id` (`client_id`, `vcap_request_id`);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `messages` DROP KEY `client_id_vcap_request_id`;
+ALTER TABLE `messages` DROP KEY `client_id_kind_id`;
+ALTER TABLE `messages` DROP KEY `client_id_email`;
+ALTER TABLE `messages` DROP KEY `client_id_user_guid`;
+ALTER TABLE `messages` DROP KEY `client_id_created_at`;
+
+ALTER TABLE `messages` DROP COLUMN `created_at`;
+ALTER TABLE `messages` DROP COLUMN `vcap_request_id`;
+ALTER TABLE `messages` DROP COLUMN `kind_id`;
+ALTER TABLE `messages` DROP COLUMN `client_id`;
+ALTER TABLE `messages` DROP COLUMN `email`;
+ALTER TABLE `messages` DROP COLUMN `user_guid`;
@@ -0,0 +1,8 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `template_locales` ADD COLUMN `template_version` int(11) NOT NULL DEFAULT 0;
+UPDATE `template_locales` JOIN `templates` ON `templates`.`id` = `template_locales`.`template_id` SET `template_locales`.`template_version` = `templates`.`version`;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `template_locales` DROP COLUMN `template_version`;
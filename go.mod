@@ -5,6 +5,7 @@ go 1.20
 require (
 	github.com/DATA-DOG/go-sqlmock v0.0.0-20180221072120-a6b4b164c6d1
 	github.com/PuerkitoBio/goquery v0.0.0-20150328133056-269246d9e7d4
+	github.com/andybalholm/cascadia v0.0.0-20150328005534-54abbbf07a45
 	github.com/chrj/smtpd v0.0.0-20140720195347-c6fe39d4dcdd
 	github.com/dgrijalva/jwt-go v0.0.0-20141103211122-47b263f02057
 	github.com/go-sql-driver/mysql v1.4.1
@@ -20,13 +21,13 @@ require (
 	github.com/rubenv/sql-migrate v0.0.0-20150713140751-53184e1edfb4
 	github.com/ryanmoran/stack v0.0.0-20140916210556-3debe7a5953a
 	github.com/ryanmoran/viron v0.0.0-20150922192335-f3865b4826c8
+	golang.org/x/net v0.9.0
 	gopkg.in/gomail.v1 v1.0.0-20150120141108-d7294067b867
 	gopkg.in/gorp.v1 v1.7.1
 )
 
 require (
 	bitbucket.org/chrj/smtpd v0.0.0-20170817182725-9ddcdbda0f7a // indirect
-	github.com/andybalholm/cascadia v0.0.0-20150328005534-54abbbf07a45 // indirect
 	github.com/go-logr/logr v1.2.4 // indirect
 	github.com/go-task/slim-sprig v0.0.0-20230315185526-52ccab3ef572 // indirect
 	github.com/google/go-cmp v0.5.9 // indirect
@@ -37,7 +38,6 @@ require (
 	github.com/nu7hatch/gouuid v0.0.0-20131221200532-179d4d0c4d8d // indirect
 	github.com/onsi/ginkgo v1.16.5 // indirect
 	github.com/ziutek/mymysql v1.5.4 // indirect
-	golang.org/x/net v0.9.0 // indirect
 	golang.org/x/sys v0.7.0 // indirect
 	golang.org/x/text v0.9.0 // indirect
 	golang.org/x/tools v0.8.0 // indirect
@@ -0,0 +1,420 @@
+// Package htmltext derives the plain text alternative of an email from its
+// HTML part. Links are replaced by numbered references listed at the end of
+// the text, lists keep their markers and tables are flattened into lines.
+package htmltext
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var whitespacePattern = regexp.MustCompile(`[ \t\n\r\f]+`)
+
+var skippedElements = map[string]bool{
+	"head":     true,
+	"script":   true,
+	"style":    true,
+	"title":    true,
+	"template": true,
+	"noscript": true,
+}
+
+var blockElements = map[string]bool{
+	"address":    true,
+	"article":    true,
+	"aside":      true,
+	"blockquote": true,
+	"body":       true,
+	"center":     true,
+	"dd":         true,
+	"div":        true,
+	"dl":         true,
+	"dt":         true,
+	"fieldset":   true,
+	"figcaption": true,
+	"figure":     true,
+	"footer":     true,
+	"form":       true,
+	"h1":         true,
+	"h2":         true,
+	"h3":         true,
+	"h4":         true,
+	"h5":         true,
+	"h6":         true,
+	"header":     true,
+	"hr":         true,
+	"html":       true,
+	"li":         true,
+	"main":       true,
+	"nav":        true,
+	"ol":         true,
+	"p":          true,
+	"pre":        true,
+	"section":    true,
+	"table":      true,
+	"ul":         true,
+}
+
+// Text converts an HTML document or fragment into plain text.
+func Text(source string) (string, error) {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(source))
+	if err != nil {
+		return "", err
+	}
+
+	c := &converter{}
+	var blocks []string
+	for _, node := range document.Find("body").Nodes {
+		blocks = append(blocks, c.blocks(node)...)
+	}
+
+	text := strings.Join(blocks, "\n\n")
+	if len(c.links) > 0 {
+		var references []string
+		for i, link := range c.links {
+			references = append(references, fmt.Sprintf("[%d] %s", i+1, link))
+		}
+		text += "\n\n" + strings.Join(references, "\n")
+	}
+
+	return strings.TrimSpace(text), nil
+}
+
+type converter struct {
+	links []string
+}
+
+// blocks renders the children of a node as a list of blocks, which are
+// separated by blank lines in the text. Inline content between block
+// elements becomes a block of its own.
+func (c *converter) blocks(node *html.Node) []string {
+	var blocks []string
+	var inline strings.Builder
+
+	flush := func() {
+		if text := normalizeInline(inline.String()); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline.Reset()
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && skippedElements[child.Data] {
+			continue
+		}
+
+		if child.Type == html.ElementNode && blockElements[child.Data] {
+			flush()
+			blocks = append(blocks, c.block(child)...)
+			continue
+		}
+
+		inline.WriteString(c.inline(child))
+	}
+	flush()
+
+	return blocks
+}
+
+func (c *converter) block(node *html.Node) []string {
+	switch node.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		heading := normalizeInline(c.inlineChildren(node))
+		if heading == "" {
+			return nil
+		}
+
+		switch node.Data {
+		case "h1":
+			heading += "\n" + strings.Repeat("=", utf8.RuneCountInString(heading))
+		case "h2":
+			heading += "\n" + strings.Repeat("-", utf8.RuneCountInString(heading))
+		}
+		return []string{heading}
+
+	case "hr":
+		return []string{"---"}
+
+	case "pre":
+		text := strings.Trim(textContent(node), "\n")
+		if text == "" {
+			return nil
+		}
+		return []string{text}
+
+	case "blockquote":
+		quoted := strings.Join(c.blocks(node), "\n\n")
+		if quoted == "" {
+			return nil
+		}
+		return []string{prefixLines(quoted, "> ", "> ")}
+
+	case "ul", "ol":
+		list := c.list(node)
+		if list == "" {
+			return nil
+		}
+		return []string{list}
+
+	case "table":
+		return c.table(node)
+
+	default:
+		return c.blocks(node)
+	}
+}
+
+// list renders the items of a list one per line, indenting the lines of
+// nested content to align with the text after the marker.
+func (c *converter) list(node *html.Node) string {
+	number := 1
+	if node.Data == "ol" {
+		if start, err := strconv.Atoi(attribute(node, "start")); err == nil {
+			number = start
+		}
+	}
+
+	var items []string
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || child.Data != "li" {
+			continue
+		}
+
+		marker := "- "
+		if node.Data == "ol" {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		contents := strings.Join(c.blocks(child), "\n")
+		items = append(items, prefixLines(contents, marker, strings.Repeat(" ", len(marker))))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// table flattens a table. Rows that only hold inline content become a line
+// with their cells separated by " | ", consecutive rows forming one block.
+// Rows whose cells hold block content, as in tables used for layout, are
+// rendered cell by cell instead.
+func (c *converter) table(node *html.Node) []string {
+	var blocks []string
+	var lines []string
+
+	flush := func() {
+		if len(lines) > 0 {
+			blocks = append(blocks, strings.Join(lines, "\n"))
+			lines = nil
+		}
+	}
+
+	for _, row := range tableRows(node) {
+		cells := rowCells(row)
+
+		if hasBlockContent(cells) {
+			flush()
+			for _, cell := range cells {
+				blocks = append(blocks, c.blocks(cell)...)
+			}
+			continue
+		}
+
+		var values []string
+		for _, cell := range cells {
+			if value := normalizeInline(c.inlineChildren(cell)); value != "" {
+				values = append(values, strings.Replace(value, "\n", " ", -1))
+			}
+		}
+		if len(values) > 0 {
+			lines = append(lines, strings.Join(values, " | "))
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+func tableRows(node *html.Node) []*html.Node {
+	var rows []*html.Node
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+
+		switch {
+		case child.Data == "tr":
+			rows = append(rows, child)
+		case isTableSection(child.Data):
+			rows = append(rows, tableRows(child)...)
+		}
+	}
+
+	return rows
+}
+
+func rowCells(row *html.Node) []*html.Node {
+	var cells []*html.Node
+	for child := row.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && (child.Data == "td" || child.Data == "th") {
+			cells = append(cells, child)
+		}
+	}
+
+	return cells
+}
+
+func hasBlockContent(nodes []*html.Node) bool {
+	for _, node := range nodes {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && !skippedElements[child.Data] && (blockElements[child.Data] || hasBlockContent([]*html.Node{child})) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isTableSection(name string) bool {
+	return name == "thead" || name == "tbody" || name == "tfoot"
+}
+
+// inline renders a node within a line of text. Whitespace is kept as it is
+// until the line is normalized; "\n" only comes from line breaks.
+func (c *converter) inline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return whitespacePattern.ReplaceAllString(node.Data, " ")
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch node.Data {
+	case "br":
+		return "\n"
+
+	case "img":
+		return attribute(node, "alt")
+
+	case "a":
+		label := c.inlineChildren(node)
+		href := strings.TrimSpace(attribute(node, "href"))
+		trimmed := strings.TrimSpace(label)
+		if !linkable(href) || href == trimmed || href == "mailto:"+trimmed {
+			return label
+		}
+		if trimmed == "" {
+			return label + href
+		}
+		return label + fmt.Sprintf(" [%d]", c.reference(href))
+
+	default:
+		if skippedElements[node.Data] {
+			return ""
+		}
+
+		if blockElements[node.Data] {
+			return "\n" + strings.Join(c.block(node), "\n") + "\n"
+		}
+
+		return c.inlineChildren(node)
+	}
+}
+
+func (c *converter) inlineChildren(node *html.Node) string {
+	var text strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text.WriteString(c.inline(child))
+	}
+
+	return text.String()
+}
+
+// reference returns the number of a link in the list of references, adding
+// it when it has not been seen before.
+func (c *converter) reference(href string) int {
+	for i, link := range c.links {
+		if link == href {
+			return i + 1
+		}
+	}
+
+	c.links = append(c.links, href)
+	return len(c.links)
+}
+
+// linkable reports whether a link is worth listing: fragments and links
+// that cannot be followed from an email are left out.
+func linkable(href string) bool {
+	lower := strings.ToLower(href)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:")
+}
+
+// normalizeInline collapses runs of spaces and trims every line.
+func normalizeInline(text string) string {
+	lines := strings.Split(text, "\n")
+
+	var kept []string
+	for _, line := range lines {
+		line = strings.TrimSpace(strings.Join(strings.Fields(line), " "))
+		if line != "" {
+			kept = append(kept, line)
+		}
+	}
+
+	return strings.Join(kept, "\n")
+}
+
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+
+	if node.Type == html.ElementNode && node.Data == "br" {
+		return "\n"
+	}
+
+	var text strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text.WriteString(textContent(child))
+	}
+
+	return text.String()
+}
+
+func attribute(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+// prefixLines prefixes the first line of text with first and every other
+// non-empty line with rest.
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
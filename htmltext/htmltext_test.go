@@ -0,0 +1,65 @@
+package htmltext_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/htmltext"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Text", func() {
+	text := func(source string) string {
+		converted, err := htmltext.Text(source)
+		Expect(err).NotTo(HaveOccurred())
+		return converted
+	}
+
+	It("renders headings and paragraphs, collapsing whitespace", func() {
+		Expect(text("<h1>Your app   crashed</h1>\n<p>The app\n   crashed.<br>It <b>restarted</b>.</p><h2>Status</h2><h3>Details</h3>")).To(Equal(
+			"Your app crashed\n================\n\nThe app crashed.\nIt restarted.\n\nStatus\n------\n\nDetails"))
+	})
+
+	It("leaves out the head, scripts and styles", func() {
+		Expect(text("<html><head><title>Title</title><style>p { color: red }</style></head><body><script>alert(1)</script><p>Hello &amp; welcome</p></body></html>")).To(Equal(
+			"Hello & welcome"))
+	})
+
+	It("lists links as numbered references", func() {
+		Expect(text(`<p>See <a href="https://example.com/logs">the logs</a>, the <a href="https://example.com/logs">same logs</a> and <a href="https://example.com/apps">apps</a>.</p>`)).To(Equal(
+			"See the logs [1], the same logs [1] and apps [2].\n\n[1] https://example.com/logs\n[2] https://example.com/apps"))
+	})
+
+	It("does not reference links that repeat their label or cannot be followed", func() {
+		Expect(text(`<a href="https://example.com">https://example.com</a> <a href="mailto:ops@example.com">ops@example.com</a> <a href="#top">top</a> <a href="javascript:alert(1)">click</a>`)).To(Equal(
+			"https://example.com ops@example.com top click"))
+	})
+
+	It("renders images by their alt text", func() {
+		Expect(text(`<p><img src="/logo.png" alt="Cloud Foundry"> logo</p>`)).To(Equal("Cloud Foundry logo"))
+	})
+
+	It("renders nested lists", func() {
+		Expect(text("<ul><li>one</li><li>two<ul><li>nested</li></ul></li></ul><ol start=\"3\"><li>three</li><li><p>four</p></li></ol>")).To(Equal(
+			"- one\n- two\n  - nested\n\n3. three\n4. four"))
+	})
+
+	It("flattens tables into lines", func() {
+		Expect(text("<table><thead><tr><th>App</th><th>State</th></tr></thead><tbody><tr><td>dora</td><td><b>crashed</b></td></tr><tr><td></td><td></td></tr></tbody></table>")).To(Equal(
+			"App | State\ndora | crashed"))
+	})
+
+	It("renders layout tables cell by cell", func() {
+		Expect(text("<table><tr><td><table><tr><td><h1>Alert</h1></td></tr></table></td></tr><tr><td><p>First</p><p>Second</p></td><td>Side</td></tr></table>")).To(Equal(
+			"Alert\n=====\n\nFirst\n\nSecond\n\nSide"))
+	})
+
+	It("keeps preformatted text, quotes and rules", func() {
+		Expect(text("<pre>line one\n  line two</pre><blockquote><p>quoted</p><p>text</p></blockquote><hr><p>after</p>")).To(Equal(
+			"line one\n  line two\n\n> quoted\n>\n> text\n\n---\n\nafter"))
+	})
+
+	It("converts fragments without a body", func() {
+		Expect(text("plain <em>fragment</em>")).To(Equal("plain fragment"))
+		Expect(text("")).To(Equal(""))
+	})
+})
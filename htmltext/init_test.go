@@ -0,0 +1,13 @@
+package htmltext_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHTMLTextSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "htmltext")
+}
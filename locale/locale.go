@@ -0,0 +1,133 @@
+package locale
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+var tagPattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+type ParseError struct {
+	Locale string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("%q is not a valid locale", e.Locale)
+}
+
+// Parse accepts a BCP 47 style language tag, using either "-" or "_" as a
+// separator, and returns it in canonical form: a lowercase language, a
+// titlecase script and an uppercase region, e.g. "zh-Hant-TW".
+func Parse(raw string) (string, error) {
+	tag := strings.Replace(strings.TrimSpace(raw), "_", "-", -1)
+	if !tagPattern.MatchString(tag) {
+		return "", ParseError{Locale: raw}
+	}
+
+	subtags := strings.Split(tag, "-")
+	subtags[0] = strings.ToLower(subtags[0])
+	for i, subtag := range subtags[1:] {
+		switch {
+		case len(subtag) == 4 && isAlpha(subtag):
+			subtags[i+1] = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+		case len(subtag) == 2 && isAlpha(subtag), len(subtag) == 3 && isDigit(subtag):
+			subtags[i+1] = strings.ToUpper(subtag)
+		default:
+			subtags[i+1] = strings.ToLower(subtag)
+		}
+	}
+
+	return strings.Join(subtags, "-"), nil
+}
+
+// Fallbacks returns the chain of locales to try for the given tag, from the
+// most to the least specific, e.g. "de-CH" yields ["de-CH", "de"]. The
+// default (unlocalized) variant is implied at the end of every chain.
+func Fallbacks(tag string) []string {
+	if tag == "" {
+		return []string{}
+	}
+
+	subtags := strings.Split(tag, "-")
+	chain := make([]string, 0, len(subtags))
+	for i := len(subtags); i > 0; i-- {
+		chain = append(chain, strings.Join(subtags[:i], "-"))
+	}
+
+	return chain
+}
+
+// Catalog maps the stable ID of a message to its translations keyed by
+// locale. IDs keep the translations attached to a message when its source
+// text is reworded.
+type Catalog map[string]map[string]string
+
+// LoadCatalog reads a catalog from a JSON file mapping message IDs to their
+// translations. The locales of the file are canonicalized so that they are
+// found along the chains returned by Fallbacks.
+func LoadCatalog(path string) (Catalog, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]map[string]string
+	err = json.Unmarshal(contents, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := Catalog{}
+	for id, translations := range raw {
+		catalog[id] = map[string]string{}
+		for rawTag, translation := range translations {
+			tag, err := Parse(rawTag)
+			if err != nil {
+				return nil, err
+			}
+
+			catalog[id][tag] = translation
+		}
+	}
+
+	return catalog, nil
+}
+
+// Translate returns the most specific translation of the message with the
+// given ID for the given locale, or the message itself when no translation
+// exists.
+func (c Catalog) Translate(id, message, tag string) string {
+	translations, ok := c[id]
+	if !ok {
+		return message
+	}
+
+	for _, candidate := range Fallbacks(tag) {
+		if translation, ok := translations[candidate]; ok {
+			return translation
+		}
+	}
+
+	return message
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
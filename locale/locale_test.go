@@ -0,0 +1,124 @@
+package locale_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry-incubator/notifications/locale"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Locale", func() {
+	Describe("Parse", func() {
+		It("canonicalizes the casing of each subtag", func() {
+			for raw, expected := range map[string]string{
+				"de":          "de",
+				"DE-ch":       "de-CH",
+				"pt_br":       "pt-BR",
+				"zh-hant-tw":  "zh-Hant-TW",
+				"es-419":      "es-419",
+				" en-GB ":     "en-GB",
+				"sl-rozaj-x1": "sl-rozaj-x1",
+			} {
+				tag, err := locale.Parse(raw)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tag).To(Equal(expected))
+			}
+		})
+
+		It("rejects malformed tags, naming them in the error", func() {
+			for _, raw := range []string{"", "d", "german", "de-", "de--CH", "de CH", "../en"} {
+				_, err := locale.Parse(raw)
+				Expect(err).To(MatchError(locale.ParseError{Locale: raw}))
+			}
+
+			_, err := locale.Parse("nope!")
+			Expect(err).To(MatchError(`"nope!" is not a valid locale`))
+		})
+	})
+
+	Describe("Fallbacks", func() {
+		It("returns the chain from the most to the least specific locale", func() {
+			Expect(locale.Fallbacks("zh-Hant-TW")).To(Equal([]string{"zh-Hant-TW", "zh-Hant", "zh"}))
+			Expect(locale.Fallbacks("de-CH")).To(Equal([]string{"de-CH", "de"}))
+			Expect(locale.Fallbacks("de")).To(Equal([]string{"de"}))
+		})
+
+		It("returns an empty chain when there is no locale", func() {
+			Expect(locale.Fallbacks("")).To(BeEmpty())
+		})
+	})
+
+	Describe("Catalog", func() {
+		var catalog locale.Catalog
+
+		BeforeEach(func() {
+			catalog = locale.Catalog{
+				"greeting": {
+					"de":    "Hallo",
+					"de-CH": "Grüezi",
+				},
+			}
+		})
+
+		It("translates into the most specific locale available", func() {
+			Expect(catalog.Translate("greeting", "Hello", "de-CH")).To(Equal("Grüezi"))
+			Expect(catalog.Translate("greeting", "Hello", "de-AT")).To(Equal("Hallo"))
+		})
+
+		It("looks translations up by ID rather than by the source message", func() {
+			Expect(catalog.Translate("greeting", "Hello there", "de")).To(Equal("Hallo"))
+			Expect(catalog.Translate("", "greeting", "de")).To(Equal("greeting"))
+		})
+
+		It("falls back to the source message", func() {
+			Expect(catalog.Translate("greeting", "Hello", "fr")).To(Equal("Hello"))
+			Expect(catalog.Translate("greeting", "Hello", "")).To(Equal("Hello"))
+			Expect(catalog.Translate("farewell", "Goodbye", "de")).To(Equal("Goodbye"))
+		})
+	})
+
+	Describe("LoadCatalog", func() {
+		var path string
+
+		BeforeEach(func() {
+			path = filepath.Join(GinkgoT().TempDir(), "catalog.json")
+		})
+
+		It("reads the translations of each message, canonicalizing their locales", func() {
+			err := os.WriteFile(path, []byte(`{"greeting": {"de": "Hallo", "de_ch": "Grüezi"}}`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			catalog, err := locale.LoadCatalog(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(catalog).To(Equal(locale.Catalog{
+				"greeting": {
+					"de":    "Hallo",
+					"de-CH": "Grüezi",
+				},
+			}))
+			Expect(catalog.Translate("greeting", "Hello", "de-CH")).To(Equal("Grüezi"))
+		})
+
+		It("rejects translations into malformed locales", func() {
+			err := os.WriteFile(path, []byte(`{"greeting": {"german": "Hallo"}}`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = locale.LoadCatalog(path)
+			Expect(err).To(MatchError(locale.ParseError{Locale: "german"}))
+		})
+
+		It("returns errors reading or parsing the file", func() {
+			_, err := locale.LoadCatalog(path)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+
+			err = os.WriteFile(path, []byte(`{"greeting": "Hallo"}`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = locale.LoadCatalog(path)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
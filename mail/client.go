@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/address"
 	"github.com/pivotal-golang/lager"
 )
 
@@ -146,7 +147,7 @@ func (c *Client) Send(msg Message, logger lager.Logger) error {
 	}
 
 	c.PrintLog(logger, "setting-msg-from", lager.Data{"from": msg.From})
-	err = c.client.Mail(msg.From)
+	err = c.client.Mail(envelopeAddress(msg.From))
 	if err != nil {
 		return c.Error(logger, err)
 	}
@@ -174,6 +175,17 @@ func (c *Client) Send(msg Message, logger lager.Logger) error {
 	return nil
 }
 
+// The SMTP envelope only accepts a bare address, so any display name
+// present in the From header is dropped here.
+func envelopeAddress(from string) string {
+	parsed, err := address.Parse(from)
+	if err != nil {
+		return from
+	}
+
+	return parsed.Email()
+}
+
 func (c *Client) Hello() error {
 	err := c.client.Hello("localhost")
 	if err != nil {
@@ -207,6 +207,31 @@ var _ = Describe("Mail", func() {
 			Expect(delivery.UsedTLS).To(BeTrue())
 		})
 
+		It("uses the bare address of a named sender for the envelope", func() {
+			msg := mail.Message{
+				From:    `"Autoscaler" <autoscaler@example.com>`,
+				To:      "you@example.com",
+				Subject: "Scaled up",
+				Body: []mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "Your app now has 3 instances.",
+					},
+				},
+			}
+
+			err := client.Send(msg, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(mailServer.Deliveries)
+			}).Should(Equal(1))
+			delivery := mailServer.Deliveries[0]
+
+			Expect(delivery.Sender).To(Equal("autoscaler@example.com"))
+			Expect(delivery.Data).To(ContainElement(`From: "Autoscaler" <autoscaler@example.com>`))
+		})
+
 		It("can make multiple requests", func() {
 			firstMsg := mail.Message{
 				From:    "me@example.com",
@@ -0,0 +1,377 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type inlineKind int
+
+const (
+	textInline inlineKind = iota
+	codeInline
+	emphasisInline
+	strongInline
+	linkInline
+	imageInline
+	softBreakInline
+	hardBreakInline
+)
+
+type inline struct {
+	kind     inlineKind
+	text     string
+	url      string
+	children []inline
+}
+
+var autolinkPattern = regexp.MustCompile(`^<((?:https?://|mailto:)[^\s<>]+|[^\s<>@]+@[^\s<>@]+\.[^\s<>@]+)>`)
+
+func parseInline(source string) []inline {
+	var nodes []inline
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, inline{kind: textInline, text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(source); {
+		c := source[i]
+
+		switch {
+		case c == '\\' && i+1 < len(source) && source[i+1] == '\n':
+			flush()
+			nodes = append(nodes, inline{kind: hardBreakInline})
+			i += 2
+
+		case c == '\\' && i+1 < len(source) && isPunctuation(source[i+1]):
+			text.WriteByte(source[i+1])
+			i += 2
+
+		case c == '`':
+			run := runLength(source, i, '`')
+			end := findCodeSpanEnd(source, i+run, run)
+			if end < 0 {
+				text.WriteString(source[i : i+run])
+				i += run
+				continue
+			}
+
+			flush()
+			nodes = append(nodes, inline{kind: codeInline, text: codeSpan(source[i+run : end])})
+			i = end + run
+
+		case c == '!' && i+1 < len(source) && source[i+1] == '[':
+			label, url, length, ok := parseLink(source[i+1:])
+			if !ok {
+				text.WriteByte(c)
+				i++
+				continue
+			}
+
+			flush()
+			nodes = append(nodes, inline{kind: imageInline, url: url, children: parseInline(label)})
+			i += 1 + length
+
+		case c == '[':
+			label, url, length, ok := parseLink(source[i:])
+			if !ok {
+				text.WriteByte(c)
+				i++
+				continue
+			}
+
+			flush()
+			nodes = append(nodes, inline{kind: linkInline, url: url, children: parseInline(label)})
+			i += length
+
+		case c == '<' && autolinkPattern.MatchString(source[i:]):
+			matches := autolinkPattern.FindStringSubmatch(source[i:])
+			url := matches[1]
+			if !strings.Contains(url, ":") {
+				url = "mailto:" + url
+			}
+
+			flush()
+			nodes = append(nodes, inline{kind: linkInline, url: url, children: []inline{{kind: textInline, text: matches[1]}}})
+			i += len(matches[0])
+
+		case c == '*' || c == '_':
+			node, length, ok := parseEmphasis(source, i)
+			if !ok {
+				run := runLength(source, i, c)
+				text.WriteString(source[i : i+run])
+				i += run
+				continue
+			}
+
+			flush()
+			nodes = append(nodes, node)
+			i += length
+
+		case c == '\n':
+			line := text.String()
+			trimmed := strings.TrimRight(line, " ")
+			text.Reset()
+			text.WriteString(trimmed)
+			flush()
+
+			if len(line)-len(trimmed) >= 2 {
+				nodes = append(nodes, inline{kind: hardBreakInline})
+			} else {
+				nodes = append(nodes, inline{kind: softBreakInline})
+			}
+			i++
+
+		default:
+			text.WriteByte(c)
+			i++
+		}
+	}
+
+	flush()
+
+	return nodes
+}
+
+// parseEmphasis parses the emphasis or strong emphasis opened by the
+// delimiter run at start, returning the node and the length of source it
+// covers.
+func parseEmphasis(source string, start int) (inline, int, bool) {
+	delimiter := source[start]
+	run := runLength(source, start, delimiter)
+
+	if !canOpen(source, start, run) {
+		return inline{}, 0, false
+	}
+
+	if run >= 2 {
+		if end := findCloser(source, start+2, delimiter, 2); end >= 0 {
+			return inline{kind: strongInline, children: parseInline(source[start+2 : end])}, end + 2 - start, true
+		}
+	}
+
+	if run >= 2 {
+		return inline{}, 0, false
+	}
+
+	if end := findCloser(source, start+1, delimiter, 1); end >= 0 {
+		return inline{kind: emphasisInline, children: parseInline(source[start+1 : end])}, end + 1 - start, true
+	}
+
+	return inline{}, 0, false
+}
+
+func canOpen(source string, start, run int) bool {
+	if start+run >= len(source) {
+		return false
+	}
+
+	next, _ := utf8.DecodeRuneInString(source[start+run:])
+	if unicode.IsSpace(next) {
+		return false
+	}
+
+	if source[start] == '_' && start > 0 {
+		previous, _ := utf8.DecodeLastRuneInString(source[:start])
+		if isWordRune(previous) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findCloser finds a run of exactly size delimiters that can close emphasis,
+// skipping escaped characters and code spans.
+func findCloser(source string, from int, delimiter byte, size int) int {
+	for j := from; j < len(source); j++ {
+		switch source[j] {
+		case '\\':
+			j++
+			continue
+		case '`':
+			run := runLength(source, j, '`')
+			if end := findCodeSpanEnd(source, j+run, run); end >= 0 {
+				j = end + run - 1
+			} else {
+				j += run - 1
+			}
+			continue
+		}
+
+		if source[j] != delimiter {
+			continue
+		}
+
+		run := runLength(source, j, delimiter)
+		if j == from || run < size {
+			j += run - 1
+			continue
+		}
+
+		previous, _ := utf8.DecodeLastRuneInString(source[:j])
+		if unicode.IsSpace(previous) {
+			j += run - 1
+			continue
+		}
+
+		if delimiter == '_' && j+run < len(source) {
+			next, _ := utf8.DecodeRuneInString(source[j+run:])
+			if isWordRune(next) {
+				j += run - 1
+				continue
+			}
+		}
+
+		if run == size || size == 2 {
+			return j + run - size
+		}
+
+		j += run - 1
+	}
+
+	return -1
+}
+
+// findCodeSpanEnd finds the run of exactly size backticks that closes a code
+// span.
+func findCodeSpanEnd(source string, from, size int) int {
+	for j := from; j < len(source); {
+		if source[j] != '`' {
+			j++
+			continue
+		}
+
+		run := runLength(source, j, '`')
+		if run == size {
+			return j
+		}
+		j += run
+	}
+
+	return -1
+}
+
+// parseLink parses "[label](destination)" at the start of source, returning
+// the label, the destination and the length of source it covers.
+func parseLink(source string) (string, string, int, bool) {
+	depth := 0
+	closing := -1
+	for i := 0; i < len(source) && closing < 0; i++ {
+		switch source[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closing = i
+			}
+		}
+	}
+
+	if closing < 0 || closing+1 >= len(source) || source[closing+1] != '(' {
+		return "", "", 0, false
+	}
+
+	rest := source[closing+2:]
+	end := closingParenthesis(rest)
+	if end < 0 {
+		return "", "", 0, false
+	}
+
+	destination := strings.TrimSpace(rest[:end])
+	if space := strings.IndexAny(destination, " \t\n"); space >= 0 {
+		title := strings.TrimSpace(destination[space:])
+		if !isQuoted(title) {
+			return "", "", 0, false
+		}
+		destination = destination[:space]
+	}
+	if strings.HasPrefix(destination, "<") && strings.HasSuffix(destination, ">") {
+		destination = destination[1 : len(destination)-1]
+	}
+
+	return source[1:closing], destination, closing + 2 + end + 1, true
+}
+
+// closingParenthesis finds the ")" that ends a link destination, allowing
+// balanced parentheses inside it.
+func closingParenthesis(source string) int {
+	depth := 0
+	for i := 0; i < len(source); i++ {
+		switch source[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
+
+func isQuoted(title string) bool {
+	if len(title) < 2 {
+		return false
+	}
+
+	first, last := title[0], title[len(title)-1]
+	return (first == '"' && last == '"') || (first == '\'' && last == '\'') || (first == '(' && last == ')')
+}
+
+// codeSpan normalizes the contents of a code span: line endings become
+// spaces and a single surrounding space is removed.
+func codeSpan(contents string) string {
+	contents = strings.Replace(contents, "\n", " ", -1)
+	if len(contents) >= 2 && contents[0] == ' ' && contents[len(contents)-1] == ' ' && strings.Trim(contents, " ") != "" {
+		contents = contents[1 : len(contents)-1]
+	}
+
+	return contents
+}
+
+func runLength(source string, start int, c byte) int {
+	n := 0
+	for start+n < len(source) && source[start+n] == c {
+		n++
+	}
+
+	return n
+}
+
+func isPunctuation(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// safeURL reports whether a link destination may be used: it must be
+// relative or use the http, https or mailto scheme.
+func safeURL(url string) bool {
+	colon := strings.IndexByte(url, ':')
+	if colon < 0 || strings.ContainsAny(url[:colon], "/?#") {
+		return true
+	}
+
+	switch strings.ToLower(url[:colon]) {
+	case "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
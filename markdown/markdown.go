@@ -0,0 +1,279 @@
+// Package markdown converts the commonly used subset of Markdown into the
+// HTML and plain text parts of an email. Raw HTML in the source is always
+// escaped and links are restricted to http, https, mailto and relative URLs,
+// so the HTML it produces is safe to send whatever the source contains.
+//
+// Supported are ATX and setext headings, paragraphs with soft and hard line
+// breaks, emphasis, strong emphasis, code spans, fenced and indented code
+// blocks, block quotes, nested bullet and ordered lists, thematic breaks,
+// links, autolinks and images.
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type blockKind int
+
+const (
+	paragraphBlock blockKind = iota
+	headingBlock
+	codeBlock
+	quoteBlock
+	listBlock
+	ruleBlock
+)
+
+type block struct {
+	kind     blockKind
+	level    int
+	text     string
+	children []block
+
+	ordered bool
+	start   int
+	tight   bool
+	items   [][]block
+}
+
+var (
+	headingPattern    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	rulePattern       = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	setextOnePattern  = regexp.MustCompile(`^ {0,3}=+[ \t]*$`)
+	setextTwoPattern  = regexp.MustCompile(`^ {0,3}-+[ \t]*$`)
+	fencePattern      = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})")
+	quotePattern      = regexp.MustCompile(`^ {0,3}> ?`)
+	listItemPattern   = regexp.MustCompile(`^( {0,3})([-*+]|(\d{1,9})[.)])([ \t]+|$)`)
+	indentCodePattern = regexp.MustCompile(`^(?: {4}|\t)`)
+)
+
+func parse(source string) []block {
+	source = strings.ToValidUTF8(source, "\uFFFD")
+	source = strings.Replace(source, "\r\n", "\n", -1)
+	source = strings.Replace(source, "\r", "\n", -1)
+
+	return parseBlocks(strings.Split(source, "\n"))
+}
+
+func parseBlocks(lines []string) []block {
+	var blocks []block
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case isBlank(line):
+			i++
+
+		case fencePattern.MatchString(line):
+			var code block
+			code, i = parseFence(lines, i)
+			blocks = append(blocks, code)
+
+		case headingPattern.MatchString(line):
+			matches := headingPattern.FindStringSubmatch(line)
+			blocks = append(blocks, block{kind: headingBlock, level: len(matches[1]), text: matches[2]})
+			i++
+
+		case rulePattern.MatchString(line):
+			blocks = append(blocks, block{kind: ruleBlock})
+			i++
+
+		case quotePattern.MatchString(line):
+			var quoted []string
+			for i < len(lines) && quotePattern.MatchString(lines[i]) {
+				quoted = append(quoted, quotePattern.ReplaceAllString(lines[i], ""))
+				i++
+			}
+			blocks = append(blocks, block{kind: quoteBlock, children: parseBlocks(quoted)})
+
+		case listItemPattern.MatchString(line):
+			var list block
+			list, i = parseList(lines, i)
+			blocks = append(blocks, list)
+
+		case indentCodePattern.MatchString(line):
+			var code []string
+			for i < len(lines) && (indentCodePattern.MatchString(lines[i]) || isBlank(lines[i])) {
+				code = append(code, indentCodePattern.ReplaceAllString(lines[i], ""))
+				i++
+			}
+			for len(code) > 0 && isBlank(code[len(code)-1]) {
+				code = code[:len(code)-1]
+			}
+			blocks = append(blocks, block{kind: codeBlock, text: strings.Join(code, "\n")})
+
+		default:
+			var paragraph block
+			paragraph, i = parseParagraph(lines, i)
+			blocks = append(blocks, paragraph)
+		}
+	}
+
+	return blocks
+}
+
+func parseFence(lines []string, i int) (block, int) {
+	matches := fencePattern.FindStringSubmatch(lines[i])
+	indent, fence := len(matches[1]), matches[2]
+	i++
+
+	var code []string
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+
+		line := lines[i]
+		for n := 0; n < indent && strings.HasPrefix(line, " "); n++ {
+			line = line[1:]
+		}
+		code = append(code, line)
+	}
+
+	return block{kind: codeBlock, text: strings.Join(code, "\n")}, i
+}
+
+func parseParagraph(lines []string, i int) (block, int) {
+	text := []string{strings.TrimLeft(lines[i], " \t")}
+	i++
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+
+		if setextOnePattern.MatchString(line) {
+			return block{kind: headingBlock, level: 1, text: joinParagraph(text)}, i + 1
+		}
+
+		if setextTwoPattern.MatchString(line) {
+			return block{kind: headingBlock, level: 2, text: joinParagraph(text)}, i + 1
+		}
+
+		if isBlank(line) || startsBlock(line) {
+			break
+		}
+
+		text = append(text, strings.TrimLeft(line, " \t"))
+	}
+
+	return block{kind: paragraphBlock, text: joinParagraph(text)}, i
+}
+
+func joinParagraph(lines []string) string {
+	last := len(lines) - 1
+	lines[last] = strings.TrimRight(lines[last], " \t")
+
+	return strings.Join(lines, "\n")
+}
+
+func startsBlock(line string) bool {
+	return fencePattern.MatchString(line) ||
+		headingPattern.MatchString(line) ||
+		rulePattern.MatchString(line) ||
+		quotePattern.MatchString(line) ||
+		listItemPattern.MatchString(line)
+}
+
+func parseList(lines []string, i int) (block, int) {
+	first := listItemPattern.FindStringSubmatch(lines[i])
+	list := block{
+		kind:    listBlock,
+		ordered: first[3] != "",
+		start:   1,
+		tight:   true,
+	}
+	if list.ordered {
+		list.start, _ = strconv.Atoi(first[3])
+	}
+
+	for i < len(lines) {
+		matches := listItemPattern.FindStringSubmatch(lines[i])
+		if matches == nil || (matches[3] != "") != list.ordered {
+			break
+		}
+
+		contentIndent := len(matches[0])
+		if len(matches[4]) > 4 {
+			contentIndent = len(matches[1]) + len(matches[2]) + 1
+		}
+		if contentIndent > len(lines[i]) {
+			contentIndent = len(lines[i])
+		}
+
+		item := []string{lines[i][contentIndent:]}
+		i++
+
+		for i < len(lines) {
+			line := lines[i]
+
+			if isBlank(line) {
+				next := i + 1
+				for next < len(lines) && isBlank(lines[next]) {
+					next++
+				}
+				if next < len(lines) && indentation(lines[next]) >= contentIndent {
+					item = append(item, lines[i:next]...)
+					i = next
+					continue
+				}
+				break
+			}
+
+			if indentation(line) >= contentIndent {
+				item = append(item, line[contentIndent:])
+				i++
+				continue
+			}
+
+			if startsBlock(line) || isBlank(item[len(item)-1]) {
+				break
+			}
+
+			item = append(item, strings.TrimLeft(line, " \t"))
+			i++
+		}
+
+		for _, line := range item[:len(item)-1] {
+			if isBlank(line) {
+				list.tight = false
+			}
+		}
+
+		list.items = append(list.items, parseBlocks(item))
+
+		if i < len(lines) && isBlank(lines[i]) {
+			next := i
+			for next < len(lines) && isBlank(lines[next]) {
+				next++
+			}
+			matches := listItemPattern.FindStringSubmatch(lineAt(lines, next))
+			if matches == nil || (matches[3] != "") != list.ordered {
+				break
+			}
+			list.tight = false
+			i = next
+		}
+	}
+
+	return list, i
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+
+	return ""
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
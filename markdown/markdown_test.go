@@ -0,0 +1,204 @@
+package markdown_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/markdown"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Markdown", func() {
+	Describe("HTML", func() {
+		It("renders headings and paragraphs", func() {
+			Expect(markdown.HTML("# Your app crashed\n\nThe app crashed.\nIt restarted.\n\nStatus\n------")).To(Equal(
+				"<h1>Your app crashed</h1>\n<p>The app crashed.\nIt restarted.</p>\n<h2>Status</h2>"))
+		})
+
+		It("renders inline markup", func() {
+			Expect(markdown.HTML("The app **dora** is *down* in `dev`, see app_name and __logs__.  \nThanks")).To(Equal(
+				"<p>The app <strong>dora</strong> is <em>down</em> in <code>dev</code>, see app_name and <strong>logs</strong>.<br>\nThanks</p>"))
+		})
+
+		It("renders links, autolinks and images", func() {
+			Expect(markdown.HTML(`[the logs](https://example.com/logs?a=1&b=2 "Logs") <ops@example.com> ![logo](/logo.png)`)).To(Equal(
+				`<p><a href="https://example.com/logs?a=1&amp;b=2">the logs</a> <a href="mailto:ops@example.com">ops@example.com</a> <img src="/logo.png" alt="logo"></p>`))
+		})
+
+		It("renders lists", func() {
+			Expect(markdown.HTML("- one\n- two\n  - nested\n\n3. three\n4. four")).To(Equal(
+				"<ul>\n<li>one</li>\n<li>two\n<ul>\n<li>nested</li>\n</ul>\n</li>\n</ul>\n<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>"))
+		})
+
+		It("renders loose lists with paragraphs", func() {
+			Expect(markdown.HTML("- one\n\n- two")).To(Equal("<ul>\n<li>\n<p>one</p>\n</li>\n<li>\n<p>two</p>\n</li>\n</ul>"))
+		})
+
+		It("renders code blocks, quotes and rules", func() {
+			Expect(markdown.HTML("```go\nfmt.Println(\"<hi>\")\n```\n\n    indented\n\n> quoted\n> *text*\n\n***")).To(Equal(
+				"<pre><code>fmt.Println(&#34;&lt;hi&gt;&#34;)\n</code></pre>\n<pre><code>indented\n</code></pre>\n<blockquote>\n<p>quoted\n<em>text</em></p>\n</blockquote>\n<hr>"))
+		})
+
+		It("escapes raw HTML", func() {
+			Expect(markdown.HTML(`<script>alert("hi")</script> & <b>bold</b>`)).To(Equal(
+				`<p>&lt;script&gt;alert(&#34;hi&#34;)&lt;/script&gt; &amp; &lt;b&gt;bold&lt;/b&gt;</p>`))
+		})
+
+		It("drops links and images with unsafe schemes", func() {
+			Expect(markdown.HTML(`[click](javascript:alert(1)) ![x](data:image/png;base64,AAAA) [ok](mailto:a@example.com)`)).To(Equal(
+				`<p>click x <a href="mailto:a@example.com">ok</a></p>`))
+		})
+
+		It("leaves unmatched markup as text", func() {
+			Expect(markdown.HTML("2 * 3 = 6, a_b_c, **open and `tick")).To(Equal("<p>2 * 3 = 6, a_b_c, **open and `tick</p>"))
+		})
+
+		It("honours backslash escapes", func() {
+			Expect(markdown.HTML(`\*not emphasis\* \# \[not a link\]`)).To(Equal(`<p>*not emphasis* # [not a link]</p>`))
+		})
+
+		It("renders nothing for empty source", func() {
+			Expect(markdown.HTML("")).To(Equal(""))
+		})
+	})
+
+	DescribeTable("HTML nesting",
+		func(source, expected string) {
+			Expect(markdown.HTML(source)).To(Equal(expected))
+		},
+		Entry("emphasis inside strong", "**bold *and* more**", "<p><strong>bold <em>and</em> more</strong></p>"),
+		Entry("strong inside emphasis", "*it **is** here*", "<p><em>it <strong>is</strong> here</em></p>"),
+		Entry("emphasis inside a link", "[see *the* logs](/logs)", `<p><a href="/logs">see <em>the</em> logs</a></p>`),
+		Entry("a link inside emphasis", "*see [logs](/logs)*", `<p><em>see <a href="/logs">logs</a></em></p>`),
+		Entry("code inside strong", "**run `cf push`**", "<p><strong>run <code>cf push</code></strong></p>"),
+		Entry("delimiters inside code", "`**not bold**`", "<p><code>**not bold**</code></p>"),
+		Entry("brackets inside a link label", "[a [b] c](/x)", `<p><a href="/x">a [b] c</a></p>`),
+		Entry("parentheses inside a link destination", "[wiki](/wiki/Go_(language))", `<p><a href="/wiki/Go_(language)">wiki</a></p>`),
+		Entry("an image inside a link", "[![logo](/logo.png)](/home)", `<p><a href="/home"><img src="/logo.png" alt="logo"></a></p>`),
+		Entry("a list inside a quote", "> - one\n> - two", "<blockquote>\n<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n</blockquote>"),
+		Entry("a quote inside a list", "- item\n\n  > quoted", "<ul>\n<li>\n<p>item</p>\n<blockquote>\n<p>quoted</p>\n</blockquote>\n</li>\n</ul>"),
+		Entry("a quote inside a quote", "> outer\n>\n> > inner", "<blockquote>\n<p>outer</p>\n<blockquote>\n<p>inner</p>\n</blockquote>\n</blockquote>"),
+		Entry("lists nested three deep", "- a\n  - b\n    - c", "<ul>\n<li>a\n<ul>\n<li>b\n<ul>\n<li>c</li>\n</ul>\n</li>\n</ul>\n</li>\n</ul>"),
+		Entry("an ordered list inside a bullet list", "- a\n  1. b\n  2. c", "<ul>\n<li>a\n<ol>\n<li>b</li>\n<li>c</li>\n</ol>\n</li>\n</ul>"),
+	)
+
+	DescribeTable("HTML escaping",
+		func(source, expected string) {
+			Expect(markdown.HTML(source)).To(Equal(expected))
+		},
+		Entry("ampersands and angle brackets", "a < b && c > d", "<p>a &lt; b &amp;&amp; c &gt; d</p>"),
+		Entry("quotes", `say "hi" & 'bye'`, "<p>say &#34;hi&#34; &amp; &#39;bye&#39;</p>"),
+		Entry("entities are not decoded", "&lt;b&gt; &#106;", "<p>&amp;lt;b&amp;gt; &amp;#106;</p>"),
+		Entry("escaped emphasis delimiters", `\*a\* \_b\_`, "<p>*a* _b_</p>"),
+		Entry("escaped backslashes", `a\\b`, `<p>a\b</p>`),
+		Entry("escaped backticks", "\\`not code\\`", "<p>`not code`</p>"),
+		Entry("escaped autolinks", `\<https://example.com>`, "<p>&lt;https://example.com&gt;</p>"),
+		Entry("escaped link brackets", `\[a](/b)`, "<p>[a](/b)</p>"),
+		Entry("a backslash before other characters", `C:\path`, `<p>C:\path</p>`),
+		Entry("a backslash at the end of a line", "one\\\ntwo", "<p>one<br>\ntwo</p>"),
+		Entry("markup inside code spans", "`<b>&amp;</b>`", "<p><code>&lt;b&gt;&amp;amp;&lt;/b&gt;</code></p>"),
+		Entry("markup inside code blocks", "```\n<script>*x*</script>\n```", "<pre><code>&lt;script&gt;*x*&lt;/script&gt;\n</code></pre>"),
+		Entry("quotes in link destinations", `[x](/a"onclick="b)`, `<p><a href="/a&#34;onclick=&#34;b">x</a></p>`),
+		Entry("quotes in image alt text", `![a "quoted" alt](/i.png)`, `<p><img src="/i.png" alt="a &#34;quoted&#34; alt"></p>`),
+	)
+
+	DescribeTable("HTML links with unsafe schemes",
+		func(source, expected string) {
+			Expect(markdown.HTML(source)).To(Equal(expected))
+		},
+		Entry("javascript", "[x](javascript:alert(1))", "<p>x</p>"),
+		Entry("mixed case javascript", "[x](JaVaScRiPt:alert(1))", "<p>x</p>"),
+		Entry("javascript in angle brackets", "[x](<javascript:alert(1)>)", "<p>x</p>"),
+		Entry("javascript with surrounding space", "[x](  javascript:alert(1)  )", "<p>x</p>"),
+		Entry("javascript with a title", `[x](javascript:alert(1) "t")`, "<p>x</p>"),
+		Entry("javascript with an escaped colon", `[x](javascript\:alert(1))`, "<p>x</p>"),
+		Entry("javascript with an entity colon", "[x](javascript&#58;alert(1))", `<p><a href="javascript&amp;#58;alert(1)">x</a></p>`),
+		Entry("javascript with a leading control character", "[x](\x01javascript:alert(1))", "<p>x</p>"),
+		Entry("vbscript", "[x](vbscript:msgbox)", "<p>x</p>"),
+		Entry("data", "[x](data:text/html;base64,PHNjcmlwdD4=)", "<p>x</p>"),
+		Entry("file", "[x](file:///etc/passwd)", "<p>x</p>"),
+		Entry("an unsafe image", "![pic](javascript:alert(1))", "<p>pic</p>"),
+		Entry("an unsafe autolink", "<javascript:alert(1)>", "<p>&lt;javascript:alert(1)&gt;</p>"),
+		Entry("a relative path with a colon", "[x](/a:b)", `<p><a href="/a:b">x</a></p>`),
+		Entry("a query with a colon", "[x](?next=javascript:alert(1))", `<p><a href="?next=javascript:alert(1)">x</a></p>`),
+		Entry("upper case https", "[x](HTTPS://example.com)", `<p><a href="HTTPS://example.com">x</a></p>`),
+	)
+
+	DescribeTable("HTML injection",
+		func(source, expected string) {
+			Expect(markdown.HTML(source)).To(Equal(expected))
+		},
+		Entry("a script block", "<script>\nalert(1)\n</script>", "<p>&lt;script&gt;\nalert(1)\n&lt;/script&gt;</p>"),
+		Entry("an event handler", `<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>"),
+		Entry("a comment", "<!-- hidden -->", "<p>&lt;!-- hidden --&gt;</p>"),
+		Entry("a tag inside emphasis", "*<i>x</i>*", "<p><em>&lt;i&gt;x&lt;/i&gt;</em></p>"),
+		Entry("a tag inside a link label", "[<b>x</b>](/y)", `<p><a href="/y">&lt;b&gt;x&lt;/b&gt;</a></p>`),
+		Entry("a tag inside a heading", "# <h1>x</h1>", "<h1>&lt;h1&gt;x&lt;/h1&gt;</h1>"),
+		Entry("a tag inside a list", "- <iframe src=x>", "<ul>\n<li>&lt;iframe src=x&gt;</li>\n</ul>"),
+		Entry("a tag inside a quote", "> <style>x</style>", "<blockquote>\n<p>&lt;style&gt;x&lt;/style&gt;</p>\n</blockquote>"),
+		Entry("an attribute breakout in a link", `[x](https://example.com/"><script>)`, `<p><a href="https://example.com/&#34;&gt;&lt;script&gt;">x</a></p>`),
+		Entry("an attribute breakout in an autolink", `<https://example.com/"onmouseover="x>`, `<p><a href="https://example.com/&#34;onmouseover=&#34;x">https://example.com/&#34;onmouseover=&#34;x</a></p>`),
+	)
+
+	DescribeTable("HTML from malformed input",
+		func(source, expected string) {
+			Expect(markdown.HTML(source)).To(Equal(expected))
+		},
+		Entry("only whitespace", " \n\t\n", ""),
+		Entry("an unclosed emphasis", "*open", "<p>*open</p>"),
+		Entry("an unclosed strong", "**open *and* more", "<p>**open <em>and</em> more</p>"),
+		Entry("mismatched delimiters", "_a* *b_c", "<p>_a* *b_c</p>"),
+		Entry("a strong opener without a closer", "**a*", "<p>**a*</p>"),
+		Entry("a lone delimiter run", "***", "<hr>"),
+		Entry("delimiters surrounded by space", "a * b * c", "<p>a * b * c</p>"),
+		Entry("an unclosed code span", "``code`", "<p>``code`</p>"),
+		Entry("an unclosed link label", "[label(/x)", "<p>[label(/x)</p>"),
+		Entry("an unclosed link destination", "[label](/x", "<p>[label](/x</p>"),
+		Entry("a link without a destination", "[label]", "<p>[label]</p>"),
+		Entry("an empty link", "[]()", `<p><a href=""></a></p>`),
+		Entry("a link with an unquoted title", "[x](/a b)", "<p>[x](/a b)</p>"),
+		Entry("an unclosed image", "![alt(/x.png)", "<p>![alt(/x.png)</p>"),
+		Entry("an unclosed autolink", "<https://example.com", "<p>&lt;https://example.com</p>"),
+		Entry("an unclosed fence", "```\ncode", "<pre><code>code\n</code></pre>"),
+		Entry("an empty fence", "```\n```", "<pre><code></code></pre>"),
+		Entry("an empty heading", "#", "<h1></h1>"),
+		Entry("too many heading markers", "####### seven", "<p>####### seven</p>"),
+		Entry("an empty list item", "-\n- b", "<ul>\n<li></li>\n<li>b</li>\n</ul>"),
+		Entry("an empty quote", ">", "<blockquote>\n\n</blockquote>"),
+		Entry("carriage returns", "a\r\nb\rc", "<p>a\nb\nc</p>"),
+		Entry("invalid UTF-8", "*\xff*", "<p><em>\ufffd</em></p>"),
+	)
+
+	Describe("Text", func() {
+		It("removes markup and underlines headings", func() {
+			Expect(markdown.Text("# Your app crashed\n\nThe app **dora** is *down* in `dev`.\n\n## Next steps\n\n### Details")).To(Equal(
+				"Your app crashed\n================\n\nThe app dora is down in dev.\n\nNext steps\n----------\n\nDetails"))
+		})
+
+		It("follows links with their URL", func() {
+			Expect(markdown.Text("See [the logs](https://example.com/logs), <https://example.com> or <ops@example.com>.")).To(Equal(
+				"See the logs (https://example.com/logs), https://example.com or ops@example.com."))
+		})
+
+		It("renders lists, quotes, code and rules", func() {
+			Expect(markdown.Text("- one\n- two\n  - nested\n\n1. first\n2. second\n\n> quoted\n>\n> again\n\n```\ncode\n```\n\n---")).To(Equal(
+				"- one\n- two\n  - nested\n\n1. first\n2. second\n\n> quoted\n>\n> again\n\n    code\n\n---"))
+		})
+
+		DescribeTable("unsafe and malformed input",
+			func(source, expected string) {
+				Expect(markdown.Text(source)).To(Equal(expected))
+			},
+			Entry("a javascript link", "[x](JavaScript:alert(1))", "x"),
+			Entry("a data image", "![pic](data:image/png;base64,AAAA)", "pic"),
+			Entry("a script tag", "<script>alert(1)</script>", "<script>alert(1)</script>"),
+			Entry("nested emphasis in a link", "[see **the *logs***](/logs)", "see the logs (/logs)"),
+			Entry("an unclosed strong", "**open *and* more", "**open and more"),
+			Entry("an unclosed link", "[label](/x", "[label](/x"),
+			Entry("only whitespace", " \n\t\n", ""),
+		)
+
+		It("keeps raw HTML and unsafe links as text", func() {
+			Expect(markdown.Text(`<b>bold</b> [click](javascript:alert(1))`)).To(Equal(`<b>bold</b> click`))
+		})
+	})
+})
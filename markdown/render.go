@@ -0,0 +1,225 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// HTML renders Markdown source as an HTML fragment suitable for the body of
+// an email.
+func HTML(source string) string {
+	return renderBlocksHTML(parse(source), false)
+}
+
+// Text renders Markdown source as readable plain text: markup is removed,
+// headings are underlined and links are followed by their URL.
+func Text(source string) string {
+	return renderBlocksText(parse(source), false)
+}
+
+func renderBlocksHTML(blocks []block, tight bool) string {
+	var rendered []string
+
+	for _, b := range blocks {
+		switch b.kind {
+		case paragraphBlock:
+			if tight {
+				rendered = append(rendered, renderInlineHTML(parseInline(b.text)))
+			} else {
+				rendered = append(rendered, "<p>"+renderInlineHTML(parseInline(b.text))+"</p>")
+			}
+
+		case headingBlock:
+			rendered = append(rendered, fmt.Sprintf("<h%d>%s</h%d>", b.level, renderInlineHTML(parseInline(b.text)), b.level))
+
+		case codeBlock:
+			code := html.EscapeString(b.text)
+			if code != "" {
+				code += "\n"
+			}
+			rendered = append(rendered, "<pre><code>"+code+"</code></pre>")
+
+		case quoteBlock:
+			rendered = append(rendered, "<blockquote>\n"+renderBlocksHTML(b.children, false)+"\n</blockquote>")
+
+		case listBlock:
+			rendered = append(rendered, renderListHTML(b))
+
+		case ruleBlock:
+			rendered = append(rendered, "<hr>")
+		}
+	}
+
+	return strings.Join(rendered, "\n")
+}
+
+func renderListHTML(list block) string {
+	tag, open := "ul", "<ul>"
+	if list.ordered {
+		tag, open = "ol", "<ol>"
+		if list.start != 1 {
+			open = fmt.Sprintf(`<ol start="%d">`, list.start)
+		}
+	}
+
+	items := []string{open}
+	for _, item := range list.items {
+		contents := renderBlocksHTML(item, list.tight)
+
+		switch {
+		case len(item) == 0:
+			items = append(items, "<li></li>")
+		case list.tight && item[0].kind == paragraphBlock && len(item) == 1:
+			items = append(items, "<li>"+contents+"</li>")
+		case list.tight && item[0].kind == paragraphBlock:
+			items = append(items, "<li>"+contents+"\n</li>")
+		default:
+			items = append(items, "<li>\n"+contents+"\n</li>")
+		}
+	}
+	items = append(items, "</"+tag+">")
+
+	return strings.Join(items, "\n")
+}
+
+func renderInlineHTML(nodes []inline) string {
+	var rendered strings.Builder
+
+	for _, node := range nodes {
+		switch node.kind {
+		case textInline:
+			rendered.WriteString(html.EscapeString(node.text))
+		case codeInline:
+			rendered.WriteString("<code>" + html.EscapeString(node.text) + "</code>")
+		case emphasisInline:
+			rendered.WriteString("<em>" + renderInlineHTML(node.children) + "</em>")
+		case strongInline:
+			rendered.WriteString("<strong>" + renderInlineHTML(node.children) + "</strong>")
+		case linkInline:
+			if !safeURL(node.url) {
+				rendered.WriteString(renderInlineHTML(node.children))
+				continue
+			}
+			rendered.WriteString(`<a href="` + html.EscapeString(node.url) + `">` + renderInlineHTML(node.children) + "</a>")
+		case imageInline:
+			if !safeURL(node.url) {
+				rendered.WriteString(html.EscapeString(renderInlineText(node.children)))
+				continue
+			}
+			rendered.WriteString(`<img src="` + html.EscapeString(node.url) + `" alt="` + html.EscapeString(renderInlineText(node.children)) + `">`)
+		case softBreakInline:
+			rendered.WriteString("\n")
+		case hardBreakInline:
+			rendered.WriteString("<br>\n")
+		}
+	}
+
+	return rendered.String()
+}
+
+func renderBlocksText(blocks []block, tight bool) string {
+	var rendered []string
+
+	for _, b := range blocks {
+		switch b.kind {
+		case paragraphBlock:
+			rendered = append(rendered, renderInlineText(parseInline(b.text)))
+
+		case headingBlock:
+			heading := renderInlineText(parseInline(b.text))
+			switch b.level {
+			case 1:
+				heading += "\n" + strings.Repeat("=", utf8.RuneCountInString(heading))
+			case 2:
+				heading += "\n" + strings.Repeat("-", utf8.RuneCountInString(heading))
+			}
+			rendered = append(rendered, heading)
+
+		case codeBlock:
+			rendered = append(rendered, prefixLines(b.text, "    ", "    "))
+
+		case quoteBlock:
+			rendered = append(rendered, prefixLines(renderBlocksText(b.children, false), "> ", "> "))
+
+		case listBlock:
+			rendered = append(rendered, renderListText(b))
+
+		case ruleBlock:
+			rendered = append(rendered, "---")
+		}
+	}
+
+	separator := "\n\n"
+	if tight {
+		separator = "\n"
+	}
+
+	return strings.Join(rendered, separator)
+}
+
+func renderListText(list block) string {
+	var items []string
+
+	for i, item := range list.items {
+		marker := "- "
+		if list.ordered {
+			marker = fmt.Sprintf("%d. ", list.start+i)
+		}
+
+		contents := renderBlocksText(item, list.tight)
+		items = append(items, prefixLines(contents, marker, strings.Repeat(" ", len(marker))))
+	}
+
+	if list.tight {
+		return strings.Join(items, "\n")
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+func renderInlineText(nodes []inline) string {
+	var rendered strings.Builder
+
+	for _, node := range nodes {
+		switch node.kind {
+		case textInline, codeInline:
+			rendered.WriteString(node.text)
+		case emphasisInline, strongInline:
+			rendered.WriteString(renderInlineText(node.children))
+		case linkInline:
+			label := renderInlineText(node.children)
+			rendered.WriteString(label)
+			if safeURL(node.url) && node.url != label && node.url != "mailto:"+label {
+				rendered.WriteString(" (" + node.url + ")")
+			}
+		case imageInline:
+			rendered.WriteString(renderInlineText(node.children))
+		case softBreakInline, hardBreakInline:
+			rendered.WriteString("\n")
+		}
+	}
+
+	return rendered.String()
+}
+
+// prefixLines prefixes the first line of text with first and every other
+// non-empty line with rest.
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -3,6 +3,8 @@ package postal
 import (
 	"crypto/rand"
 	"database/sql"
+	"encoding/json"
+	"io/ioutil"
 	"log"
 	"os"
 	"path"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/locale"
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/postal/v1"
@@ -20,6 +23,11 @@ import (
 	"github.com/pivotal-golang/lager"
 )
 
+// DigestPollingInterval is how often the first instance looks for digests
+// that are due. Digests are due at the start of an hour, so they go out
+// within a few minutes of it.
+const DigestPollingInterval = 5 * time.Minute
+
 type Config struct {
 	UAAClientID          string
 	UAAClientSecret      string
@@ -76,12 +84,20 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 	unsubscribesRepo := v1models.NewUnsubscribesRepo()
 	globalUnsubscribesRepo := v1models.NewGlobalUnsubscribesRepo()
 	messagesRepo := v1models.NewMessagesRepo(guidGenerator.Generate)
+	batchesRepo := v1models.NewBatchesRepo(guidGenerator.Generate)
 	clientsRepo := v1models.NewClientsRepo()
 	kindsRepo := v1models.NewKindsRepo()
 	templatesRepo := v1models.NewTemplatesRepo()
-	v1TemplateLoader := v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo)
+	senderIdentitiesRepo := v1models.NewSenderIdentitiesRepo()
+	templateLocalesRepo := v1models.NewTemplateLocalesRepo()
+	templatePartialsRepo := v1models.NewTemplatePartialsRepo()
+	userSettingsRepo := v1models.NewUserSettingsRepo()
+	digestPreferencesRepo := v1models.NewDigestPreferencesRepo()
+	digestItemsRepo := v1models.NewDigestItemsRepo()
+	v1TemplateLoader := v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo, templateLocalesRepo, templatePartialsRepo)
+	senderIdentityLoader := v1.NewSenderIdentityLoader(database, senderIdentitiesRepo)
 	deliveryFailureHandler := common.NewDeliveryFailureHandler()
-	messageStatusUpdater := v1.NewMessageStatusUpdater(messagesRepo)
+	messageStatusUpdater := v1.NewMessageStatusUpdater(messagesRepo, batchesRepo)
 	userLoader := common.NewUserLoader(uaaClient)
 	tokenLoader := uaa.NewTokenLoader(uaaClient)
 	packager := common.NewPackager(v1TemplateLoader, cloak)
@@ -103,10 +119,16 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 			TokenLoader: tokenLoader,
 			UserLoader:  userLoader,
 
+			SenderIdentityLoader: senderIdentityLoader,
+			Endorsements:         endorsements(config.RootPath),
+
 			KindsRepo:              kindsRepo,
 			ReceiptsRepo:           receiptsRepo,
 			UnsubscribesRepo:       unsubscribesRepo,
 			GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+			UserSettingsRepo:       userSettingsRepo,
+			DigestPreferencesRepo:  digestPreferencesRepo,
+			DigestItemsRepo:        digestItemsRepo,
 			MessageStatusUpdater:   messageStatusUpdater,
 			DeliveryFailureHandler: deliveryFailureHandler,
 		})
@@ -124,4 +146,46 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 
 		return &worker
 	})
+
+	if config.InstanceIndex == 0 {
+		digestSender := v1.NewDigestSender(v1.DigestSenderConfig{
+			Sender:          config.Sender,
+			Domain:          config.Domain,
+			Templates:       digestTemplates(config.RootPath),
+			PollingInterval: DigestPollingInterval,
+
+			Cloak:                cloak,
+			MailClient:           mailClient(),
+			Database:             database,
+			DigestItemsRepo:      digestItemsRepo,
+			UserSettingsRepo:     userSettingsRepo,
+			MessageStatusUpdater: messageStatusUpdater,
+			Logger:               logger.Session("digest-sender"),
+		})
+		digestSender.Run()
+	}
+}
+
+func digestTemplates(rootPath string) common.DigestTemplates {
+	contents, err := ioutil.ReadFile(path.Join(rootPath, "templates", "digest.json"))
+	if err != nil {
+		panic(err)
+	}
+
+	var templates common.DigestTemplates
+	err = json.Unmarshal(contents, &templates)
+	if err != nil {
+		panic(err)
+	}
+
+	return templates
+}
+
+func endorsements(rootPath string) locale.Catalog {
+	catalog, err := locale.LoadCatalog(path.Join(rootPath, "templates", "endorsements.json"))
+	if err != nil {
+		panic(err)
+	}
+
+	return catalog
 }
@@ -13,6 +13,16 @@ type Retryable interface {
 	State() (retryCount int, activeAt time.Time)
 }
 
+// MaxRetries is how many times a failed delivery is retried before it is
+// given up on.
+const MaxRetries = 10
+
+// RetryDelay is how long a job that has been retried the given number of
+// times waits before it is retried again.
+func RetryDelay(retryCount int) time.Duration {
+	return time.Duration(int64(math.Pow(2, float64(retryCount)))) * time.Minute
+}
+
 type DeliveryFailureHandler struct{}
 
 func NewDeliveryFailureHandler() DeliveryFailureHandler {
@@ -21,12 +31,11 @@ func NewDeliveryFailureHandler() DeliveryFailureHandler {
 
 func (h DeliveryFailureHandler) Handle(job Retryable, logger lager.Logger) {
 	retryCount, _ := job.State()
-	if retryCount > 9 {
+	if retryCount >= MaxRetries {
 		return
 	}
 
-	duration := time.Duration(int64(math.Pow(2, float64(retryCount)))) * time.Minute
-	job.Retry(duration)
+	job.Retry(RetryDelay(retryCount))
 
 	retryCount, activeAt := job.State()
 	logger.Info("delivery-failed-retrying", lager.Data{
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/pivotal-golang/conceal"
+)
+
+// DigestTemplates are the templates a digest is rendered with. They are
+// rendered against a DigestContext.
+type DigestTemplates struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// DigestContext holds every delivery collected for one recipient over one
+// digest period. Each item carries the fields a regular message template
+// would see, including the UnsubscribeID for the kind it was sent as.
+type DigestContext struct {
+	From   string
+	To     string
+	Digest string
+	Domain string
+	Items  []MessageContext
+}
+
+func NewDigestContext(deliveries []Delivery, digest, sender, domain string, cloak conceal.CloakInterface) DigestContext {
+	context := DigestContext{
+		From:   sender,
+		Digest: digest,
+		Domain: domain,
+	}
+
+	for _, delivery := range deliveries {
+		if context.To == "" {
+			context.To = delivery.Email
+		}
+
+		context.Items = append(context.Items, NewMessageContext(delivery, sender, domain, cloak, Templates{}))
+	}
+
+	return context
+}
+
+// PackDigest renders a digest into a single message. The HTML template is
+// rendered with html/template; the HTML body of every item is trusted as it
+// is, just as it is for a regular message.
+func PackDigest(templates DigestTemplates, context DigestContext) (mail.Message, error) {
+	subject, err := compileDigestTemplate(templates.Subject, context)
+	if err != nil {
+		return mail.Message{}, err
+	}
+
+	var parts []mail.Part
+	if templates.Text != "" {
+		text, err := compileDigestTemplate(templates.Text, context)
+		if err != nil {
+			return mail.Message{}, err
+		}
+
+		parts = append(parts, mail.Part{
+			ContentType: "text/plain",
+			Content:     text,
+		})
+	}
+
+	if templates.HTML != "" {
+		html, err := compileDigestHTMLTemplate(templates.HTML, context)
+		if err != nil {
+			return mail.Message{}, err
+		}
+
+		parts = append(parts, mail.Part{
+			ContentType: "text/html",
+			Content:     html,
+		})
+	}
+
+	return mail.Message{
+		From:    context.From,
+		To:      context.To,
+		Subject: subject,
+		Body:    parts,
+		Headers: []string{
+			fmt.Sprintf("X-CF-Notification-Digest: %s", context.Digest),
+			fmt.Sprintf("X-CF-Notification-Timestamp: %s", time.Now().Format(time.RFC3339Nano)),
+		},
+	}, nil
+}
+
+func compileDigestTemplate(theTemplate string, context DigestContext) (string, error) {
+	buffer := bytes.NewBuffer([]byte{})
+
+	source, err := parseTextTemplate("compileDigestTemplate", theTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	err = source.Execute(buffer, context)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+func compileDigestHTMLTemplate(theTemplate string, context DigestContext) (string, error) {
+	buffer := bytes.NewBuffer([]byte{})
+
+	source, err := htmltemplate.New("compileDigestTemplate").Funcs(htmltemplate.FuncMap(TemplateFuncs())).Parse(theTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	err = source.Execute(buffer, newHTMLDigestContext(context))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+type htmlDigestContext struct {
+	DigestContext
+	Items []htmlContext
+}
+
+func newHTMLDigestContext(context DigestContext) htmlDigestContext {
+	htmlDigest := htmlDigestContext{DigestContext: context}
+	for _, item := range context.Items {
+		htmlDigest.Items = append(htmlDigest.Items, newHTMLContext(item))
+	}
+
+	return htmlDigest
+}
@@ -0,0 +1,118 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Digest", func() {
+	var (
+		cloak      *mocks.Cloak
+		deliveries []common.Delivery
+	)
+
+	BeforeEach(func() {
+		cloak = mocks.NewCloak()
+		cloak.VeilCall.Returns.CipherText = []byte("some-unsubscribe-id")
+
+		deliveries = []common.Delivery{
+			{
+				MessageID: "message-1",
+				UserGUID:  "user-123",
+				Email:     "user-123@example.com",
+				ClientID:  "some-client",
+				Options: common.Options{
+					KindID:  "some-kind",
+					Subject: "Deploy <finished>",
+					Text:    "your app is running",
+					HTML:    common.HTML{BodyContent: "<p>your app is <b>running</b></p>"},
+				},
+			},
+			{
+				MessageID: "message-2",
+				UserGUID:  "user-123",
+				Email:     "user-123@example.com",
+				ClientID:  "some-client",
+				Options: common.Options{
+					KindID:          "other-kind",
+					KindDescription: "Quota warnings",
+					Markdown:        "you are *close* to your quota",
+				},
+			},
+		}
+	})
+
+	Describe("NewDigestContext", func() {
+		It("builds a message context for every delivery", func() {
+			context := common.NewDigestContext(deliveries, "daily", "from@example.com", "example.com", cloak)
+
+			Expect(context.From).To(Equal("from@example.com"))
+			Expect(context.To).To(Equal("user-123@example.com"))
+			Expect(context.Digest).To(Equal("daily"))
+			Expect(context.Domain).To(Equal("example.com"))
+			Expect(context.Items).To(HaveLen(2))
+
+			Expect(context.Items[0].Subject).To(Equal("Deploy <finished>"))
+			Expect(context.Items[0].KindDescription).To(Equal("some-kind"))
+			Expect(context.Items[0].UnsubscribeID).To(Equal("some-unsubscribe-id"))
+			Expect(context.Items[1].Subject).To(Equal("[no subject]"))
+			Expect(context.Items[1].KindDescription).To(Equal("Quota warnings"))
+			Expect(context.Items[1].Text).To(Equal("you are close to your quota"))
+			Expect(string(cloak.VeilCall.Receives.PlainText)).To(Equal("user-123|some-client|other-kind"))
+		})
+	})
+
+	Describe("PackDigest", func() {
+		var context common.DigestContext
+
+		BeforeEach(func() {
+			context = common.NewDigestContext(deliveries, "daily", "from@example.com", "example.com", cloak)
+		})
+
+		It("renders every item into a single message", func() {
+			message, err := common.PackDigest(common.DigestTemplates{
+				Subject: `{{len .Items}} {{pluralize (len .Items) "notification" "notifications"}}`,
+				Text:    `{{range .Items}}{{.Subject}} {{link $.Domain "/unsubscribe" "id" .UnsubscribeID}}|{{end}}`,
+				HTML:    `{{range .Items}}<h3>{{.Subject}}</h3>{{.HTML}}{{end}}`,
+			}, context)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(message.From).To(Equal("from@example.com"))
+			Expect(message.To).To(Equal("user-123@example.com"))
+			Expect(message.Subject).To(Equal("2 notifications"))
+			Expect(message.Headers).To(ContainElement("X-CF-Notification-Digest: daily"))
+			Expect(message.Body).To(Equal([]mail.Part{
+				{
+					ContentType: "text/plain",
+					Content:     "Deploy <finished> https://example.com/unsubscribe?id=some-unsubscribe-id|[no subject] https://example.com/unsubscribe?id=some-unsubscribe-id|",
+				},
+				{
+					ContentType: "text/html",
+					Content:     "<h3>Deploy &lt;finished&gt;</h3><p>your app is <b>running</b></p><h3>[no subject]</h3><p>you are <em>close</em> to your quota</p>",
+				},
+			}))
+		})
+
+		It("leaves out parts without a template", func() {
+			message, err := common.PackDigest(common.DigestTemplates{
+				Subject: "digest",
+				Text:    "{{range .Items}}{{.Text}}{{end}}",
+			}, context)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(message.Body).To(HaveLen(1))
+			Expect(message.Body[0].ContentType).To(Equal("text/plain"))
+		})
+
+		It("returns an error when a template fails to execute", func() {
+			_, err := common.PackDigest(common.DigestTemplates{
+				Subject: "{{.Missing}}",
+			}, context)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -1,10 +1,13 @@
 package common
 
 import (
+	"bytes"
+	"encoding/json"
 	"html"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/markdown"
 	"github.com/pivotal-golang/conceal"
 )
 
@@ -15,11 +18,38 @@ type Options struct {
 	SourceDescription string
 	Text              string
 	HTML              HTML
+	Markdown          string
 	KindID            string
 	To                string
 	Role              string
+	SpaceRole         string
 	Endorsement       string
+	EndorsementID     string
 	TemplateID        string
+	Locale            string
+	Data              Data
+	ExpiresAt         time.Time
+
+	SkipTextAlternative bool
+}
+
+// Data holds the structured values a client supplied with its notify
+// request. Numbers are kept as json.Number so that they render in templates
+// exactly as they were sent.
+type Data map[string]interface{}
+
+func (data *Data) UnmarshalJSON(raw []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var values map[string]interface{}
+	err := decoder.Decode(&values)
+	if err != nil {
+		return err
+	}
+
+	*data = values
+	return nil
 }
 
 type Delivery struct {
@@ -38,10 +68,50 @@ type Delivery struct {
 }
 
 type Templates struct {
-	Name    string
-	Subject string
-	Text    string
-	HTML    string
+	ID       string
+	Version  int
+	Locale   string
+	Name     string
+	Subject  string
+	Text     string
+	HTML     string
+	Markdown string
+	Layout   string
+
+	InlineCSS          bool
+	LegacyHTMLEscaping bool
+}
+
+// Localize applies a locale variant to the templates. Parts that the variant
+// leaves empty keep the templates' content, except that a variant written as
+// text or HTML replaces a Markdown body outright, as the Markdown would
+// otherwise be rendered in its place.
+func Localize(templates, variant Templates) Templates {
+	templates.Locale = variant.Locale
+	if variant.Subject != "" {
+		templates.Subject = variant.Subject
+	}
+
+	if templates.Markdown != "" && variant.Markdown == "" && (variant.Text != "" || variant.HTML != "") {
+		templates.Markdown = ""
+		templates.Text = variant.Text
+		templates.HTML = variant.HTML
+		return templates
+	}
+
+	if variant.Text != "" {
+		templates.Text = variant.Text
+	}
+
+	if variant.HTML != "" {
+		templates.HTML = variant.HTML
+	}
+
+	if variant.Markdown != "" {
+		templates.Markdown = variant.Markdown
+	}
+
+	return templates
 }
 
 type HTML struct {
@@ -59,9 +129,12 @@ type MessageContext struct {
 	Text              string
 	HTML              string
 	HTMLComponents    HTML
+	Markdown          string
 	TextTemplate      string
 	HTMLTemplate      string
+	MarkdownTemplate  string
 	SubjectTemplate   string
+	Layout            string
 	KindDescription   string
 	SourceDescription string
 	UserGUID          string
@@ -75,8 +148,17 @@ type MessageContext struct {
 	Scope             string
 	Endorsement       string
 	OrganizationRole  string
+	SpaceRole         string
 	RequestReceived   time.Time
 	Domain            string
+	TemplateID        string
+	TemplateVersion   int
+	Locale            string
+	Data              Data
+
+	SkipTextAlternative bool
+	InlineCSS           bool
+	LegacyHTMLEscaping  bool
 }
 
 func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.CloakInterface, templates Templates) MessageContext {
@@ -104,9 +186,14 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 		Text:              options.Text,
 		HTML:              options.HTML.BodyContent,
 		HTMLComponents:    options.HTML,
+		Markdown:          options.Markdown,
 		TextTemplate:      templates.Text,
 		HTMLTemplate:      templates.HTML,
+		MarkdownTemplate:  templates.Markdown,
 		SubjectTemplate:   templates.Subject,
+		Layout:            templates.Layout,
+		TemplateID:        templates.ID,
+		TemplateVersion:   templates.Version,
 		KindDescription:   kindDescription,
 		SourceDescription: sourceDescription,
 		UserGUID:          delivery.UserGUID,
@@ -119,8 +206,26 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 		Scope:             delivery.Scope,
 		Endorsement:       options.Endorsement,
 		OrganizationRole:  options.Role,
+		SpaceRole:         options.SpaceRole,
 		RequestReceived:   delivery.RequestReceived,
 		Domain:            domain,
+		Locale:            options.Locale,
+		Data:              options.Data,
+
+		SkipTextAlternative: options.SkipTextAlternative,
+		InlineCSS:           templates.InlineCSS,
+		LegacyHTMLEscaping:  templates.LegacyHTMLEscaping,
+	}
+
+	if options.Markdown != "" {
+		if messageContext.Text == "" {
+			messageContext.Text = markdown.Text(options.Markdown)
+		}
+
+		if messageContext.HTML == "" {
+			messageContext.HTML = markdown.HTML(options.Markdown)
+			messageContext.HTMLComponents.BodyContent = messageContext.HTML
+		}
 	}
 
 	if messageContext.Subject == "" {
@@ -136,12 +241,15 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 	return messageContext
 }
 
+// Escape escapes the fields of the context that HTML templates rendered
+// with legacy escaping show as they are.
 func (context *MessageContext) Escape() {
 	context.From = html.EscapeString(context.From)
 	context.To = html.EscapeString(context.To)
 	context.ReplyTo = html.EscapeString(context.ReplyTo)
 	context.Subject = html.EscapeString(context.Subject)
 	context.Text = html.EscapeString(context.Text)
+	context.Markdown = html.EscapeString(context.Markdown)
 	context.KindDescription = html.EscapeString(context.KindDescription)
 	context.SourceDescription = html.EscapeString(context.SourceDescription)
 	context.ClientID = html.EscapeString(context.ClientID)
@@ -149,4 +257,36 @@ func (context *MessageContext) Escape() {
 	context.Space = html.EscapeString(context.Space)
 	context.Organization = html.EscapeString(context.Organization)
 	context.Endorsement = html.EscapeString(context.Endorsement)
+	context.Data = escapeData(context.Data)
+}
+
+// escapeData returns a copy of the data with every string escaped, leaving
+// the original untouched for the plain text parts.
+func escapeData(data Data) Data {
+	if data == nil {
+		return nil
+	}
+
+	return Data(escapeValue(map[string]interface{}(data)).(map[string]interface{}))
+}
+
+func escapeValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return html.EscapeString(value)
+	case map[string]interface{}:
+		escaped := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			escaped[key] = escapeValue(item)
+		}
+		return escaped
+	case []interface{}:
+		escaped := make([]interface{}, len(value))
+		for i, item := range value {
+			escaped[i] = escapeValue(item)
+		}
+		return escaped
+	default:
+		return value
+	}
 }
@@ -1,6 +1,7 @@
 package common_test
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
@@ -29,6 +30,9 @@ var _ = Describe("MessageContext", func() {
 			Text:    "the plainText email < template",
 			HTML:    "the html <h1> email < template</h1>",
 			Subject: "the subject < template",
+
+			InlineCSS:          true,
+			LegacyHTMLEscaping: true,
 		}
 
 		html = common.HTML{
@@ -45,6 +49,10 @@ var _ = Describe("MessageContext", func() {
 			KindID:            "the-kind-id",
 			Endorsement:       "this is the endorsement",
 			Role:              "OrgRole",
+			SpaceRole:         "SpaceRole",
+			Data:              common.Data{"app_name": "banana"},
+
+			SkipTextAlternative: true,
 		}
 
 		reqReceived, _ = time.Parse(time.RFC3339Nano, "2015-06-08T14:40:12.207187819-07:00")
@@ -100,8 +108,13 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.Scope).To(Equal("this.scope"))
 			Expect(context.Endorsement).To(Equal("this is the endorsement"))
 			Expect(context.OrganizationRole).To(Equal("OrgRole"))
+			Expect(context.SpaceRole).To(Equal("SpaceRole"))
 			Expect(context.RequestReceived).To(Equal(reqReceived))
 			Expect(context.Domain).To(Equal(domain))
+			Expect(context.Data).To(Equal(common.Data{"app_name": "banana"}))
+			Expect(context.SkipTextAlternative).To(BeTrue())
+			Expect(context.InlineCSS).To(BeTrue())
+			Expect(context.LegacyHTMLEscaping).To(BeTrue())
 		})
 
 		It("falls back to Kind if KindDescription is missing", func() {
@@ -118,6 +131,26 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.SourceDescription).To(Equal("the-client-id"))
 		})
 
+		It("renders the text and html from markdown when they are not supplied", func() {
+			delivery.Options.Text = ""
+			delivery.Options.HTML = common.HTML{}
+			delivery.Options.Markdown = "The app **dora** <crashed>"
+			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+
+			Expect(context.Markdown).To(Equal("The app **dora** <crashed>"))
+			Expect(context.Text).To(Equal("The app dora <crashed>"))
+			Expect(context.HTML).To(Equal("<p>The app <strong>dora</strong> &lt;crashed&gt;</p>"))
+			Expect(context.HTMLComponents.BodyContent).To(Equal(context.HTML))
+		})
+
+		It("prefers the supplied text and html over markdown", func() {
+			delivery.Options.Markdown = "The app **dora** crashed"
+			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+
+			Expect(context.Text).To(Equal(options.Text))
+			Expect(context.HTML).To(Equal(options.HTML.BodyContent))
+		})
+
 		It("fills in subject when subject is not specified", func() {
 			delivery.Options.Subject = ""
 			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
@@ -137,6 +170,11 @@ var _ = Describe("MessageContext", func() {
 				KindID:            "the & kind",
 				Endorsement:       "this & is the endorsement",
 				Role:              "OrgRole",
+				Data: common.Data{
+					"app_name":  "<banana>",
+					"instances": []interface{}{"a & b", json.Number("3")},
+					"nested":    map[string]interface{}{"name": `"quoted"`},
+				},
 			}
 
 			delivery.Options = options
@@ -170,6 +208,33 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.Scope).To(Equal(""))
 			Expect(context.Endorsement).To(Equal("this &amp; is the endorsement"))
 			Expect(context.OrganizationRole).To(Equal("OrgRole"))
+			Expect(context.Data).To(Equal(common.Data{
+				"app_name":  "&lt;banana&gt;",
+				"instances": []interface{}{"a &amp; b", json.Number("3")},
+				"nested":    map[string]interface{}{"name": "&#34;quoted&#34;"},
+			}))
+		})
+
+		It("does not modify the data it was given", func() {
+			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+			context.Escape()
+
+			Expect(delivery.Options.Data["app_name"]).To(Equal("<banana>"))
+			Expect(delivery.Options.Data["nested"]).To(Equal(map[string]interface{}{"name": `"quoted"`}))
+		})
+	})
+
+	Describe("Data", func() {
+		It("keeps numbers as they were written when decoded", func() {
+			var data common.Data
+			err := json.Unmarshal([]byte(`{"count": 1000000, "ratio": 0.5, "items": [1, "two"]}`), &data)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(data).To(Equal(common.Data{
+				"count": json.Number("1000000"),
+				"ratio": json.Number("0.5"),
+				"items": []interface{}{json.Number("1"), "two"},
+			}))
 		})
 	})
 })
@@ -3,11 +3,14 @@ package common
 import (
 	"bytes"
 	"fmt"
+	htmltemplate "html/template"
 	"strings"
-	"text/template"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/cssinline"
+	"github.com/cloudfoundry-incubator/notifications/htmltext"
 	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/markdown"
 	"github.com/pivotal-golang/conceal"
 )
 
@@ -20,7 +23,7 @@ const HTMLWrapperTemplate = `{{.HTMLComponents.Doctype}}
 </html>`
 
 type templatesLoader interface {
-	LoadTemplates(clientID, kindID, templateID string) (Templates, error)
+	LoadTemplates(clientID, kindID, templateID, locale string) (Templates, error)
 }
 
 type Packager struct {
@@ -36,7 +39,7 @@ func NewPackager(templates templatesLoader, cloak conceal.CloakInterface) Packag
 }
 
 func (packager Packager) PrepareContext(delivery Delivery, sender, domain string) (MessageContext, error) {
-	templates, err := packager.templates.LoadTemplates(delivery.ClientID, delivery.Options.KindID, delivery.Options.TemplateID)
+	templates, err := packager.templates.LoadTemplates(delivery.ClientID, delivery.Options.KindID, delivery.Options.TemplateID, delivery.Options.Locale)
 	if err != nil {
 		return MessageContext{}, err
 	}
@@ -50,7 +53,7 @@ func (packager Packager) Pack(context MessageContext) (mail.Message, error) {
 		return mail.Message{}, err
 	}
 
-	compiledSubject, err := packager.compileTemplate(context, context.SubjectTemplate, false)
+	compiledSubject, err := packager.CompileSubject(context)
 	if err != nil {
 		return mail.Message{}, err
 	}
@@ -70,6 +73,10 @@ func (packager Packager) Pack(context MessageContext) (mail.Message, error) {
 	}, nil
 }
 
+func (packager Packager) CompileSubject(context MessageContext) (string, error) {
+	return packager.compileTemplate(context, context.SubjectTemplate, false)
+}
+
 func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, error) {
 	var parts []mail.Part
 	var err error
@@ -79,6 +86,10 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 		return parts, err
 	}
 
+	if context.MarkdownTemplate != "" {
+		return packager.compileMarkdownParts(context)
+	}
+
 	if context.Text != "" {
 		plainText, err := packager.compileTemplate(context, context.TextTemplate, false)
 		if err != nil {
@@ -95,16 +106,28 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 	if context.HTML != "" {
 		var err error
 
-		context.HTMLComponents.BodyContent, err = packager.compileTemplate(context, context.HTMLTemplate, true)
+		context.HTMLComponents.BodyContent, err = packager.compileHTMLTemplate(context, context.HTMLTemplate)
 		if err != nil {
 			return parts, err
 		}
 
-		htmlPart, err := packager.compileTemplate(context, HTMLWrapperTemplate, true)
+		htmlPart, err := packager.compileHTMLPart(context)
 		if err != nil {
 			return parts, err
 		}
 
+		if context.Text == "" && !context.SkipTextAlternative {
+			plainText, err := htmltext.Text(context.HTMLComponents.BodyContent)
+			if err != nil {
+				return parts, err
+			}
+
+			parts = append(parts, mail.Part{
+				ContentType: "text/plain",
+				Content:     plainText,
+			})
+		}
+
 		parts = append(parts, mail.Part{
 			ContentType: "text/html",
 			Content:     htmlPart,
@@ -114,19 +137,131 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 	return parts, nil
 }
 
+// compileMarkdownParts renders a Markdown template once and converts the
+// result into both the plain text and the HTML part. The template is rendered
+// without escaping the context as the conversion escapes any HTML it contains.
+func (packager Packager) compileMarkdownParts(context MessageContext) ([]mail.Part, error) {
+	source, err := packager.compileTemplate(context, context.MarkdownTemplate, false)
+	if err != nil {
+		return nil, err
+	}
+
+	context.HTMLComponents.BodyContent = markdown.HTML(source)
+
+	htmlPart, err := packager.compileHTMLPart(context)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mail.Part{
+		{
+			ContentType: "text/plain",
+			Content:     markdown.Text(source),
+		},
+		{
+			ContentType: "text/html",
+			Content:     htmlPart,
+		},
+	}, nil
+}
+
+// compileHTMLPart wraps the compiled HTML body into a document, inlining its
+// stylesheet rules when the template asks for it.
+func (packager Packager) compileHTMLPart(context MessageContext) (string, error) {
+	htmlPart, err := packager.compileHTMLTemplate(context, HTMLWrapperTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	if !context.InlineCSS {
+		return htmlPart, nil
+	}
+
+	return cssinline.Inline(htmlPart)
+}
+
+// compileHTMLTemplate renders an HTML template with html/template, which
+// escapes every value for the context it appears in, such as an attribute or
+// a URL. Templates that opt into legacy escaping are rendered with
+// text/template against a context whose fields are escaped up front.
+func (packager Packager) compileHTMLTemplate(context MessageContext, theTemplate string) (string, error) {
+	if context.LegacyHTMLEscaping {
+		return packager.compileTemplate(context, theTemplate, true)
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+
+	source, err := htmltemplate.New(ContentPartialName).Funcs(htmltemplate.FuncMap(TemplateFuncs())).Parse(theTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	if layout := source.Lookup(context.Layout); layout != nil {
+		source = layout
+	}
+
+	err = source.Execute(buffer, newHTMLContext(context))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// htmlContext is the context HTML templates are rendered against. The HTML
+// body supplied by the client and the components of the document it is
+// wrapped into are HTML already, so they are marked as safe to be included
+// as they are; every other field is escaped by html/template.
+type htmlContext struct {
+	MessageContext
+	HTML           htmltemplate.HTML
+	HTMLComponents htmlComponents
+}
+
+type htmlComponents struct {
+	BodyContent    htmltemplate.HTML
+	BodyAttributes htmltemplate.HTMLAttr
+	Head           htmltemplate.HTML
+	Doctype        htmltemplate.HTML
+}
+
+func newHTMLContext(context MessageContext) htmlContext {
+	return htmlContext{
+		MessageContext: context,
+		HTML:           htmltemplate.HTML(context.HTML),
+		HTMLComponents: htmlComponents{
+			BodyContent:    htmltemplate.HTML(context.HTMLComponents.BodyContent),
+			BodyAttributes: htmltemplate.HTMLAttr(context.HTMLComponents.BodyAttributes),
+			Head:           htmltemplate.HTML(context.HTMLComponents.Head),
+			Doctype:        htmltemplate.HTML(context.HTMLComponents.Doctype),
+		},
+	}
+}
+
+// compileTemplate parses a template as the "content" template. Parts whose
+// partials are resolved against a layout define it next to the content, and
+// are rendered through it.
 func (packager Packager) compileTemplate(context MessageContext, theTemplate string, escapeContext bool) (string, error) {
 	buffer := bytes.NewBuffer([]byte{})
 
-	source, err := template.New("compileTemplate").Parse(theTemplate)
+	source, err := parseTextTemplate(ContentPartialName, theTemplate)
 	if err != nil {
 		return "", err
 	}
 
+	if layout := source.Lookup(context.Layout); layout != nil {
+		source = layout
+	}
+
 	if escapeContext {
 		context.Escape()
 	}
 
-	source.Execute(buffer, context)
+	err = source.Execute(buffer, context)
+	if err != nil {
+		return "", err
+	}
+
 	compiledTemplate := strings.TrimSuffix(buffer.String(), "\n")
 
 	return compiledTemplate, nil
@@ -33,6 +33,7 @@ var _ = Describe("Packager", func() {
 				Subject:    "Some crazy subject",
 				TemplateID: "some-template-id",
 				KindID:     "some-kind-id",
+				Locale:     "de-CH",
 				HTML: common.HTML{
 					BodyContent:    "<p>user supplied banana html</p>",
 					BodyAttributes: "class=\"bananaBody\"",
@@ -93,6 +94,7 @@ var _ = Describe("Packager", func() {
 			Expect(templatesLoader.LoadTemplatesCall.Receives.ClientID).To(Equal("some-client-id"))
 			Expect(templatesLoader.LoadTemplatesCall.Receives.KindID).To(Equal("some-kind-id"))
 			Expect(templatesLoader.LoadTemplatesCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(templatesLoader.LoadTemplatesCall.Receives.Locale).To(Equal("de-CH"))
 
 			Expect(cloak.VeilCall.Receives.PlainText).To(Equal([]byte("some-user-guid|some-client-id|some-kind-id")))
 
@@ -116,6 +118,7 @@ var _ = Describe("Packager", func() {
 				SubjectTemplate:   "subject template: {{.Subject}}",
 				KindDescription:   "some-kind-id",
 				SourceDescription: "some-client-id",
+				Locale:            "de-CH",
 			}))
 		})
 
@@ -167,6 +170,29 @@ var _ = Describe("Packager", func() {
 		})
 	})
 
+	Describe("CompileSubject", func() {
+		It("compiles the subject template", func() {
+			subject, err := packager.CompileSubject(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subject).To(Equal("The Subject: we will be eaten"))
+		})
+
+		It("renders data the request left out as nothing", func() {
+			context.SubjectTemplate = "[{{.Data.app_name}}] {{.Subject}}"
+
+			subject, err := packager.CompileSubject(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subject).To(Equal("[] we will be eaten"))
+		})
+
+		It("returns an error when the subject template is malformed", func() {
+			context.SubjectTemplate = "{{.Subject"
+
+			_, err := packager.CompileSubject(context)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("CompileParts", func() {
 		It("returns the compiled parts containing both the plaintext and html portions, escaping variables for the html portion only", func() {
 			parts, err := packager.CompileParts(context)
@@ -195,6 +221,218 @@ Banana preamble <p>user supplied banana html</p> User &lt;supplied&gt; &#34;bana
 			}))
 		})
 
+		It("renders the structured data supplied with the request, escaping it for the html portion only", func() {
+			context.Data = common.Data{
+				"app_name":  "<banana>",
+				"instances": []interface{}{"one", "two & three"},
+			}
+			context.TextTemplate = "{{.Data.app_name}}:{{range .Data.instances}} {{.}}{{end}}"
+			context.HTMLTemplate = "{{.Data.app_name}}:{{range .Data.instances}} {{.}}{{end}}"
+
+			parts, err := packager.CompileParts(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parts).To(ContainElement(mail.Part{
+				ContentType: "text/plain",
+				Content:     "<banana>: one two & three",
+			}))
+			Expect(parts).To(ContainElement(mail.Part{
+				ContentType: "text/html",
+				Content: `<!DOCTYPE html>
+<head><title>The title</title></head>
+<html>
+	<body class="bananaBody">
+		&lt;banana&gt;: one two &amp; three
+	</body>
+</html>`,
+			}))
+		})
+
+		It("renders data the request left out as nothing", func() {
+			context.Data = common.Data{"instances": []interface{}{"one", nil}, "app": map[string]interface{}{}}
+			context.TextTemplate = strings.Join([]string{
+				`[{{.Data.app_name}}][{{.Data.app.name}}][{{.Data.org.name}}]`,
+				`[{{range .Data.instances}}{{.}},{{end}}][{{range .Data.missing}}{{.}}{{end}}]`,
+				`[{{with .Data.app_name}}{{.}}{{else}}none{{end}}]`,
+				`[{{.Data.app_name | upper}}][{{upper .Data.app_name | default "?"}}]`,
+			}, "")
+			context.HTMLTemplate = "[{{.Data.app_name}}][{{.Data.app.name}}][{{.Data.org.name}}]"
+
+			parts, err := packager.CompileParts(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parts).To(ContainElement(mail.Part{
+				ContentType: "text/plain",
+				Content:     "[][][][one,,][][none][][?]",
+			}))
+			Expect(parts).To(ContainElement(mail.Part{
+				ContentType: "text/html",
+				Content: `<!DOCTYPE html>
+<head><title>The title</title></head>
+<html>
+	<body class="bananaBody">
+		[][][]
+	</body>
+</html>`,
+			}))
+		})
+
+		It("renders data the request left out as nothing in markdown", func() {
+			context.MarkdownTemplate = "Hello [{{.Data.app_name}}]"
+			context.HTML = ""
+
+			parts, err := packager.CompileParts(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parts[0]).To(Equal(mail.Part{
+				ContentType: "text/plain",
+				Content:     "Hello []",
+			}))
+			Expect(parts[1].Content).NotTo(ContainSubstring("no value"))
+		})
+
+		It("renders data the request left out as nothing in legacy escaped html", func() {
+			context.LegacyHTMLEscaping = true
+			context.HTMLTemplate = "[{{.Data.app_name}}]"
+
+			parts, err := packager.CompileParts(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parts).To(ContainElement(mail.Part{
+				ContentType: "text/html",
+				Content: `<!DOCTYPE html>
+<head><title>The title</title></head>
+<html>
+	<body class="bananaBody">
+		[]
+	</body>
+</html>`,
+			}))
+		})
+
+		It("renders the templates through their layout, which includes them as content", func() {
+			templates, err := common.ResolvePartials(common.Templates{
+				Layout: "branded",
+				Text:   `{{block "greeting" .}}Hi {{.To}}{{end}}, {{.Text}}`,
+				HTML:   `{{define "greeting"}}<p>Hi {{.To}}</p>{{end}}{{template "greeting" .}}`,
+			}, map[string]common.Partial{
+				"branded": {
+					Name: "branded",
+					Text: `{{template "content" .}} -- {{.Organization}}`,
+					HTML: `<main>{{template "content" .}}</main>`,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			context.TextTemplate = templates.Text
+			context.HTMLTemplate = templates.HTML
+			context.Layout = templates.Layout
+
+			parts, err := packager.CompileParts(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parts).To(ContainElement(mail.Part{
+				ContentType: "text/plain",
+				Content:     `Hi endless monkeys, User <supplied> "banana" text -- banana`,
+			}))
+			Expect(parts).To(ContainElement(mail.Part{
+				ContentType: "text/html",
+				Content: `<!DOCTYPE html>
+<head><title>The title</title></head>
+<html>
+	<body class="bananaBody">
+		<main><p>Hi endless monkeys</p></main>
+	</body>
+</html>`,
+			}))
+		})
+
+		Context("when values appear in attributes and links", func() {
+			BeforeEach(func() {
+				context.UserGUID = `user" onmouseover="alert(1)`
+				context.Data = common.Data{"url": "javascript:alert(1)"}
+				context.HTMLTemplate = `<a title="{{.UserGUID}}" href="{{.Data.url}}">{{.Space}}</a>`
+			})
+
+			It("escapes them for the context they appear in", func() {
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(parts).To(HaveLen(2))
+				Expect(parts[1].Content).To(ContainSubstring(`<a title="user&#34; onmouseover=&#34;alert(1)" href="#ZgotmplZ">development</a>`))
+			})
+
+			Context("when the template uses legacy escaping", func() {
+				It("only escapes the fields it always escaped", func() {
+					context.LegacyHTMLEscaping = true
+
+					parts, err := packager.CompileParts(context)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(parts).To(HaveLen(2))
+					Expect(parts[1].Content).To(ContainSubstring(`<a title="user" onmouseover="alert(1)" href="javascript:alert(1)">development</a>`))
+				})
+			})
+		})
+
+		It("returns an error when the html template leaves a value in an ambiguous context", func() {
+			context.HTMLTemplate = `<a href="{{.Data.url}}`
+
+			_, err := packager.CompileParts(context)
+			Expect(err).To(HaveOccurred())
+		})
+
+		Context("when the template is written in markdown", func() {
+			It("renders the markdown once into both the plaintext and html portions", func() {
+				context.MarkdownTemplate = "# {{.Subject}}\n\n{{.Text}} from **{{.Organization}}**\n\n{{.Endorsement}}"
+				context.HTML = ""
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(parts).To(ConsistOf([]mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "we will be eaten\n================\n\nUser <supplied> \"banana\" text from banana\n\nThis is an endorsement for the development space and banana org.",
+					},
+					{
+						ContentType: "text/html",
+						Content: `<!DOCTYPE html>
+<head><title>The title</title></head>
+<html>
+	<body class="bananaBody">
+		<h1>we will be eaten</h1>
+<p>User &lt;supplied&gt; &#34;banana&#34; text from <strong>banana</strong></p>
+<p>This is an endorsement for the development space and banana org.</p>
+	</body>
+</html>`,
+					},
+				}))
+			})
+
+			It("returns an error when the markdown template is malformed", func() {
+				context.MarkdownTemplate = "{{.Planet}}"
+
+				_, err := packager.CompileParts(context)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("when the template inlines CSS", func() {
+			It("moves the stylesheet of the head into style attributes", func() {
+				context.InlineCSS = true
+				context.HTMLComponents.Head = "<style>p { color: red } @media (max-width: 600px) { p { color: blue } }</style>"
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(parts).To(HaveLen(2))
+				Expect(parts[1].ContentType).To(Equal("text/html"))
+				Expect(parts[1].Content).To(HavePrefix(`<!DOCTYPE html><html><head><style>@media (max-width: 600px) { p { color: blue } }</style></head>`))
+				Expect(parts[1].Content).To(ContainSubstring(`Banana preamble <p style="color: red">user supplied banana html</p>`))
+			})
+		})
+
 		Context("when no html is set", func() {
 			It("only sends a plaintext of the email", func() {
 				context.HTML = ""
@@ -216,15 +454,12 @@ This is an endorsement for the development space and banana org.`
 		})
 
 		Context("when no text is set", func() {
-			It("omits the plaintext portion of the email", func() {
-				context.Text = ""
+			var htmlBody string
 
-				parts, err := packager.CompileParts(context)
-				if err != nil {
-					panic(err)
-				}
+			BeforeEach(func() {
+				context.Text = ""
 
-				htmlBody := `<!DOCTYPE html>
+				htmlBody = `<!DOCTYPE html>
 <head><title>The title</title></head>
 <html>
 	<body class="bananaBody">
@@ -232,13 +467,43 @@ This is an endorsement for the development space and banana org.`
 Banana preamble <p>user supplied banana html</p>  3&amp;3 4&#39;4 user-123
 	</body>
 </html>`
-				Expect(parts).To(ConsistOf([]mail.Part{
+			})
+
+			It("derives the plaintext portion of the email from the HTML", func() {
+				parts, err := packager.CompileParts(context)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(parts).To(Equal([]mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "This is an endorsement for the development space and banana org.\n\nBanana preamble\n\nuser supplied banana html\n\n3&3 4'4 user-123",
+					},
 					{
 						ContentType: "text/html",
 						Content:     htmlBody,
 					},
 				}))
 			})
+
+			Context("when the kind skips the text alternative", func() {
+				It("omits the plaintext portion of the email", func() {
+					context.SkipTextAlternative = true
+
+					parts, err := packager.CompileParts(context)
+					if err != nil {
+						panic(err)
+					}
+
+					Expect(parts).To(ConsistOf([]mail.Part{
+						{
+							ContentType: "text/html",
+							Content:     htmlBody,
+						},
+					}))
+				})
+			})
 		})
 	})
 })
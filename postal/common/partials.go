@@ -0,0 +1,306 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// ContentPartialName is the name under which a layout includes the template
+// it wraps, as in {{template "content" .}}.
+const ContentPartialName = "content"
+
+// Partial is a named piece of template shared between templates. A partial
+// used as the layout of a template includes the template as "content".
+type Partial struct {
+	Name string
+	Text string
+	HTML string
+}
+
+type PartialError struct {
+	Err error
+}
+
+func (e PartialError) Error() string {
+	return e.Err.Error()
+}
+
+// UsesPartials reports whether the templates declare a layout or include
+// templates they do not define themselves.
+func UsesPartials(templates Templates) bool {
+	if templates.Layout != "" {
+		return true
+	}
+
+	for _, source := range []string{templates.Text, templates.HTML, templates.Markdown} {
+		references, err := PartialReferences(source)
+		if err != nil || len(references) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PartialReferences returns the names of the templates a template source
+// includes without defining them itself.
+func PartialReferences(source string) ([]string, error) {
+	parsed, err := template.New("references").Funcs(TemplateFuncs()).Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	defined := map[string]bool{}
+	for _, t := range parsed.Templates() {
+		defined[t.Name()] = true
+	}
+
+	found := map[string]bool{}
+	for _, t := range parsed.Templates() {
+		if t.Tree != nil {
+			collectReferences(t.Tree.Root, found)
+		}
+	}
+
+	var references []string
+	for name := range found {
+		if !defined[name] {
+			references = append(references, name)
+		}
+	}
+	sort.Strings(references)
+
+	return references, nil
+}
+
+func collectReferences(node parse.Node, found map[string]bool) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, child := range node.Nodes {
+			collectReferences(child, found)
+		}
+	case *parse.TemplateNode:
+		found[node.Name] = true
+	case *parse.IfNode:
+		collectReferences(node.List, found)
+		collectReferences(node.ElseList, found)
+	case *parse.RangeNode:
+		collectReferences(node.List, found)
+		collectReferences(node.ElseList, found)
+	case *parse.WithNode:
+		collectReferences(node.List, found)
+		collectReferences(node.ElseList, found)
+	}
+}
+
+// ValidatePartials checks that every partial parses, does not define
+// templates of its own, only includes partials that exist and does not
+// include itself, directly or through others.
+func ValidatePartials(partials map[string]Partial) error {
+	var names []string
+	for name := range partials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == ContentPartialName {
+			return PartialError{fmt.Errorf("%q is reserved for the content of layouts", ContentPartialName)}
+		}
+
+		for _, source := range []string{partials[name].Text, partials[name].HTML} {
+			parsed, err := template.New(name).Funcs(TemplateFuncs()).Parse(source)
+			if err != nil {
+				return PartialError{fmt.Errorf("partial %q is malformed: %s", name, err)}
+			}
+
+			if len(parsed.Templates()) > 1 {
+				return PartialError{fmt.Errorf("partial %q must not define templates", name)}
+			}
+		}
+	}
+
+	for _, part := range []string{"text", "html"} {
+		resolver := newPartialResolver(partials, part)
+		resolver.anyContent = true
+		for _, name := range names {
+			if err := resolver.visit(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ResolvePartials appends the definitions of the layout and partials the
+// text, HTML and Markdown templates include, so that each part can be
+// compiled on its own. A part compiled this way is parsed as the "content"
+// template and rendered through its layout, when it has one. Markdown
+// templates use the text of partials and layouts.
+func ResolvePartials(templates Templates, partials map[string]Partial) (Templates, error) {
+	var err error
+
+	templates.Text, err = resolvePart(templates.Text, templates.Layout, partials, "text")
+	if err != nil {
+		return Templates{}, err
+	}
+
+	templates.HTML, err = resolvePart(templates.HTML, templates.Layout, partials, "html")
+	if err != nil {
+		return Templates{}, err
+	}
+
+	templates.Markdown, err = resolvePart(templates.Markdown, templates.Layout, partials, "text")
+	if err != nil {
+		return Templates{}, err
+	}
+
+	return templates, nil
+}
+
+// resolvePart leaves the source at the top of the part rather than wrapping
+// it into a definition of its own, so that it may define templates itself.
+func resolvePart(source, layout string, partials map[string]Partial, part string) (string, error) {
+	if source == "" {
+		return "", nil
+	}
+
+	resolver := newPartialResolver(partials, part)
+
+	var err error
+	if layout != "" {
+		if _, ok := partials[layout]; !ok {
+			return "", PartialError{fmt.Errorf("layout %q does not exist", layout)}
+		}
+
+		resolver.content = &source
+		err = resolver.visit(layout)
+	} else {
+		err = resolver.visitSource(source, nil)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	definitions := []string{source}
+	for _, name := range resolver.order {
+		definitions = append(definitions, fmt.Sprintf("{{define %q}}%s{{end}}", name, resolver.definitions[name]))
+	}
+
+	return strings.Join(definitions, ""), nil
+}
+
+// partialResolver walks the partials included by a template. The content
+// of a layout is only known when resolving a template; when validating
+// partials on their own, anyContent allows any of them to include it.
+type partialResolver struct {
+	partials    map[string]Partial
+	part        string
+	content     *string
+	anyContent  bool
+	visited     map[string]bool
+	order       []string
+	definitions map[string]string
+}
+
+func newPartialResolver(partials map[string]Partial, part string) *partialResolver {
+	return &partialResolver{
+		partials:    partials,
+		part:        part,
+		visited:     map[string]bool{},
+		definitions: map[string]string{},
+	}
+}
+
+func (r *partialResolver) source(partial Partial) string {
+	if r.part == "html" {
+		return partial.HTML
+	}
+
+	return partial.Text
+}
+
+// visit walks the partials included by the named partial depth first,
+// recording the order in which they have to be defined and failing on
+// partials that do not exist or include themselves.
+func (r *partialResolver) visit(name string) error {
+	return r.visitPath(name, nil)
+}
+
+func (r *partialResolver) visitPath(name string, path []string) error {
+	for i, ancestor := range path {
+		if ancestor == name {
+			cycle := append(append([]string{}, path[i:]...), name)
+			return PartialError{fmt.Errorf("partials form a cycle: %s", quoteAll(cycle, " -> "))}
+		}
+	}
+
+	if r.visited[name] {
+		return nil
+	}
+
+	var source string
+	switch {
+	case name == ContentPartialName && r.anyContent:
+		r.visited[name] = true
+		return nil
+	case name == ContentPartialName && r.content != nil:
+		source = *r.content
+	default:
+		partial, ok := r.partials[name]
+		if !ok {
+			return PartialError{fmt.Errorf("partial %q does not exist", name)}
+		}
+		source = r.source(partial)
+	}
+
+	err := r.visitSource(source, append(path, name))
+	if err != nil {
+		return err
+	}
+
+	r.visited[name] = true
+	if name == ContentPartialName {
+		return nil
+	}
+
+	r.order = append(r.order, name)
+	r.definitions[name] = source
+
+	return nil
+}
+
+func (r *partialResolver) visitSource(source string, path []string) error {
+	references, err := PartialReferences(source)
+	if err != nil {
+		if len(path) > 0 {
+			return PartialError{fmt.Errorf("partial %q is malformed: %s", path[len(path)-1], err)}
+		}
+		return PartialError{err}
+	}
+
+	for _, reference := range references {
+		err := r.visitPath(reference, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func quoteAll(names []string, separator string) string {
+	var quoted []string
+	for _, name := range names {
+		quoted = append(quoted, fmt.Sprintf("%q", name))
+	}
+
+	return strings.Join(quoted, separator)
+}
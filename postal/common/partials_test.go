@@ -0,0 +1,188 @@
+package common_test
+
+import (
+	"bytes"
+	"errors"
+	"text/template"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Partials", func() {
+	var partials map[string]common.Partial
+
+	render := func(source, layout string) string {
+		parsed := template.Must(template.New(common.ContentPartialName).Parse(source))
+		if parsed.Lookup(layout) != nil {
+			parsed = parsed.Lookup(layout)
+		}
+
+		var rendered bytes.Buffer
+		err := parsed.Execute(&rendered, map[string]string{"Name": "Jo"})
+		Expect(err).NotTo(HaveOccurred())
+
+		return rendered.String()
+	}
+
+	BeforeEach(func() {
+		partials = map[string]common.Partial{
+			"layout": {
+				Name: "layout",
+				Text: `{{template "header" .}} {{template "content" .}} {{template "footer" .}}`,
+				HTML: `<main>{{template "content" .}}</main>{{template "footer" .}}`,
+			},
+			"header":    {Name: "header", Text: "Hi {{.Name}},", HTML: "<h1>Hi {{.Name}}</h1>"},
+			"footer":    {Name: "footer", Text: "-- {{template \"signature\"}}", HTML: "<footer>{{template \"signature\"}}</footer>"},
+			"signature": {Name: "signature", Text: "The Team", HTML: "<b>The Team</b>"},
+		}
+	})
+
+	Describe("PartialReferences", func() {
+		It("returns the templates a source includes without defining them", func() {
+			references, err := common.PartialReferences(`{{define "local"}}x{{end}}{{if .A}}{{template "footer"}}{{else}}{{template "local"}}{{end}}{{range .B}}{{template "header" .}}{{end}}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(references).To(Equal([]string{"footer", "header"}))
+		})
+
+		It("returns an error when the source is malformed", func() {
+			_, err := common.PartialReferences("{{template")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("UsesPartials", func() {
+		It("is true when the templates declare a layout", func() {
+			Expect(common.UsesPartials(common.Templates{Layout: "layout", Text: "text"})).To(BeTrue())
+		})
+
+		It("is true when a template includes a partial", func() {
+			Expect(common.UsesPartials(common.Templates{HTML: `{{template "footer"}}`})).To(BeTrue())
+		})
+
+		It("is false for templates that stand on their own", func() {
+			Expect(common.UsesPartials(common.Templates{Text: "{{.Name}}", HTML: `{{define "x"}}y{{end}}{{template "x"}}`})).To(BeFalse())
+		})
+	})
+
+	Describe("ResolvePartials", func() {
+		It("defines the partials a template includes, including nested ones", func() {
+			resolved, err := common.ResolvePartials(common.Templates{
+				Text: `Hello {{.Name}} {{template "footer"}}`,
+				HTML: `<p>Hello</p>{{template "footer"}}`,
+			}, partials)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(render(resolved.Text, resolved.Layout)).To(Equal("Hello Jo -- The Team"))
+			Expect(render(resolved.HTML, resolved.Layout)).To(Equal("<p>Hello</p><footer><b>The Team</b></footer>"))
+		})
+
+		It("wraps the templates into their layout", func() {
+			resolved, err := common.ResolvePartials(common.Templates{
+				Layout: "layout",
+				Text:   "Your app crashed.",
+				HTML:   "<p>Your app crashed.</p>",
+			}, partials)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(resolved.Layout).To(Equal("layout"))
+			Expect(render(resolved.Text, resolved.Layout)).To(Equal("Hi Jo, Your app crashed. -- The Team"))
+			Expect(render(resolved.HTML, resolved.Layout)).To(Equal("<main><p>Your app crashed.</p></main><footer><b>The Team</b></footer>"))
+		})
+
+		It("uses the text of partials for Markdown templates", func() {
+			resolved, err := common.ResolvePartials(common.Templates{
+				Layout:   "layout",
+				Markdown: "**crashed**",
+			}, partials)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(resolved.Text).To(BeEmpty())
+			Expect(resolved.HTML).To(BeEmpty())
+			Expect(render(resolved.Markdown, resolved.Layout)).To(Equal("Hi Jo, **crashed** -- The Team"))
+		})
+
+		It("leaves templates their own definitions when wrapping them into a layout", func() {
+			resolved, err := common.ResolvePartials(common.Templates{
+				Layout: "layout",
+				Text:   `{{block "greeting" .}}Your app crashed.{{end}}{{define "unused"}}unused{{end}}`,
+				HTML:   `{{define "greeting"}}<p>Your app crashed.</p>{{end}}{{template "greeting" .}}`,
+			}, partials)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(render(resolved.Text, resolved.Layout)).To(Equal("Hi Jo, Your app crashed. -- The Team"))
+			Expect(render(resolved.HTML, resolved.Layout)).To(Equal("<main><p>Your app crashed.</p></main><footer><b>The Team</b></footer>"))
+		})
+
+		It("returns an error when the layout does not exist", func() {
+			_, err := common.ResolvePartials(common.Templates{Layout: "missing", Text: "text"}, partials)
+			Expect(err).To(MatchError(common.PartialError{Err: errors.New(`layout "missing" does not exist`)}))
+		})
+
+		It("returns an error when an included partial does not exist", func() {
+			_, err := common.ResolvePartials(common.Templates{Text: `{{template "missing"}}`}, partials)
+			Expect(err).To(MatchError(common.PartialError{Err: errors.New(`partial "missing" does not exist`)}))
+		})
+
+		It("returns an error when a template without a layout includes the content", func() {
+			_, err := common.ResolvePartials(common.Templates{Text: `{{template "content"}}`}, partials)
+			Expect(err).To(MatchError(common.PartialError{Err: errors.New(`partial "content" does not exist`)}))
+		})
+
+		It("returns an error when the content includes itself through its layout", func() {
+			_, err := common.ResolvePartials(common.Templates{Layout: "layout", Text: `{{template "content"}}`}, partials)
+			Expect(err).To(MatchError(common.PartialError{Err: errors.New(`partials form a cycle: "content" -> "content"`)}))
+		})
+
+		It("returns an error when partials include each other", func() {
+			partials["signature"] = common.Partial{Name: "signature", Text: `{{template "footer"}}`}
+
+			_, err := common.ResolvePartials(common.Templates{Text: `{{template "footer"}}`}, partials)
+			Expect(err).To(MatchError(common.PartialError{Err: errors.New(`partials form a cycle: "footer" -> "signature" -> "footer"`)}))
+		})
+	})
+
+	Describe("ValidatePartials", func() {
+		It("accepts partials that include each other and the content of layouts", func() {
+			Expect(common.ValidatePartials(partials)).To(Succeed())
+		})
+
+		It("rejects partials that include themselves", func() {
+			partials["signature"] = common.Partial{Name: "signature", HTML: `{{template "signature"}}`}
+
+			err := common.ValidatePartials(partials)
+			Expect(err).To(MatchError(common.PartialError{Err: errors.New(`partials form a cycle: "signature" -> "signature"`)}))
+		})
+
+		It("rejects partials that include partials that do not exist", func() {
+			partials["footer"] = common.Partial{Name: "footer", Text: `{{template "missing"}}`}
+
+			err := common.ValidatePartials(partials)
+			Expect(err).To(MatchError(common.PartialError{Err: errors.New(`partial "missing" does not exist`)}))
+		})
+
+		It("rejects malformed partials", func() {
+			partials["footer"] = common.Partial{Name: "footer", Text: `{{.Name`}
+
+			err := common.ValidatePartials(partials)
+			Expect(err).To(BeAssignableToTypeOf(common.PartialError{}))
+			Expect(err.Error()).To(HavePrefix(`partial "footer" is malformed`))
+		})
+
+		It("rejects partials that define templates", func() {
+			partials["footer"] = common.Partial{Name: "footer", Text: `{{define "x"}}y{{end}}`}
+
+			err := common.ValidatePartials(partials)
+			Expect(err).To(MatchError(common.PartialError{Err: errors.New(`partial "footer" must not define templates`)}))
+		})
+
+		It("rejects a partial named after the content of layouts", func() {
+			partials["content"] = common.Partial{Name: "content", Text: "text"}
+
+			err := common.ValidatePartials(partials)
+			Expect(err).To(MatchError(common.PartialError{Err: errors.New(`"content" is reserved for the content of layouts`)}))
+		})
+	})
+})
@@ -0,0 +1,289 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// TemplateFuncs returns the functions available to subject, text and HTML
+// templates in addition to the text/template builtins (such as urlquery).
+// Every function only depends on its arguments so that rendering a template
+// has no side effects and always gives the same result for the same context.
+// Functions given a missing value render nothing rather than failing, so
+// that templates referencing optional .Data keys still render.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"formatTime":   formatTime,
+		"inZone":       inZone,
+		"formatNumber": formatNumber,
+		"formatBytes":  formatBytes,
+		"pluralize":    pluralize,
+		"upper":        upper,
+		"lower":        lower,
+		"title":        title,
+		"trim":         trim,
+		"truncate":     truncate,
+		"replace":      replace,
+		"join":         join,
+		"default":      defaultValue,
+		"link":         link,
+	}
+}
+
+func formatTime(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(layout)
+}
+
+func inZone(name string, t time.Time) (time.Time, error) {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown time zone %q", name)
+	}
+
+	return t.In(location), nil
+}
+
+func toFloat(value interface{}) (float64, bool, error) {
+	switch value := value.(type) {
+	case nil:
+		return 0, false, nil
+	case int:
+		return float64(value), true, nil
+	case int64:
+		return float64(value), true, nil
+	case float64:
+		return value, true, nil
+	case json.Number:
+		number, err := value.Float64()
+		if err != nil {
+			return 0, false, fmt.Errorf("%q is not a number", value)
+		}
+		return number, true, nil
+	case string:
+		if value == "" {
+			return 0, false, nil
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("%q is not a number", value)
+		}
+		return number, true, nil
+	default:
+		return 0, false, fmt.Errorf("%v is not a number", value)
+	}
+}
+
+// formatNumber renders a number with thousands separators, keeping up to two
+// decimal places, e.g. 1234567.891 becomes "1,234,567.89".
+func formatNumber(value interface{}) (string, error) {
+	number, ok, err := toFloat(value)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	formatted := strconv.FormatFloat(number, 'f', 2, 64)
+	formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+
+	integer, fraction := formatted, ""
+	if i := strings.Index(formatted, "."); i >= 0 {
+		integer, fraction = formatted[:i], formatted[i:]
+	}
+
+	var grouped []string
+	for len(integer) > 3 {
+		grouped = append([]string{integer[len(integer)-3:]}, grouped...)
+		integer = integer[:len(integer)-3]
+	}
+	grouped = append([]string{integer}, grouped...)
+
+	return sign + strings.Join(grouped, ",") + fraction, nil
+}
+
+// formatBytes renders a byte count using binary units, e.g. 1536 becomes
+// "1.5 KiB".
+func formatBytes(value interface{}) (string, error) {
+	size, ok, err := toFloat(value)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	unit := 0
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d %s", int64(size), units[unit]), nil
+	}
+
+	formatted := strings.TrimSuffix(strconv.FormatFloat(size, 'f', 1, 64), ".0")
+	return formatted + " " + units[unit], nil
+}
+
+// pluralize picks the singular form when the count is exactly one.
+func pluralize(count interface{}, singular, plural string) (string, error) {
+	number, _, err := toFloat(count)
+	if err != nil {
+		return "", err
+	}
+
+	if number == 1 {
+		return singular, nil
+	}
+
+	return plural, nil
+}
+
+func upper(value interface{}) string {
+	return strings.ToUpper(toString(value))
+}
+
+func lower(value interface{}) string {
+	return strings.ToLower(toString(value))
+}
+
+func trim(value interface{}) string {
+	return strings.TrimSpace(toString(value))
+}
+
+// title capitalizes the first letter of every word and leaves the rest of
+// the string as it is. Apostrophes do not start a new word, so "o'neil's"
+// becomes "O'neil's" rather than "O'Neil'S".
+func title(value interface{}) string {
+	var titled strings.Builder
+	inWord := false
+
+	for _, r := range toString(value) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			if !inWord {
+				r = unicode.ToTitle(r)
+			}
+			inWord = true
+		case r == '\'' || r == '’':
+		default:
+			inWord = false
+		}
+
+		titled.WriteRune(r)
+	}
+
+	return titled.String()
+}
+
+// truncate shortens a string to at most length characters, ending it with
+// "..." when anything was removed.
+func truncate(length int, value interface{}) string {
+	text := toString(value)
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+
+	if length <= 3 {
+		return string(runes[:length])
+	}
+
+	return string(runes[:length-3]) + "..."
+}
+
+func replace(old, new string, value interface{}) string {
+	return strings.Replace(toString(value), old, new, -1)
+}
+
+func join(separator string, values interface{}) (string, error) {
+	switch values := values.(type) {
+	case nil:
+		return "", nil
+	case []string:
+		return strings.Join(values, separator), nil
+	case []interface{}:
+		var items []string
+		for _, value := range values {
+			items = append(items, toString(value))
+		}
+		return strings.Join(items, separator), nil
+	default:
+		return "", fmt.Errorf("cannot join %v", values)
+	}
+}
+
+// defaultValue returns fallback when value is missing or empty, so that
+// {{.Data.name | default "there"}} renders "there" without a name.
+func defaultValue(fallback, value interface{}) interface{} {
+	switch value := value.(type) {
+	case nil:
+		return fallback
+	case string:
+		if value == "" {
+			return fallback
+		}
+	}
+
+	return value
+}
+
+// link builds an http or https URL from a base such as .Domain, a path and
+// pairs of query parameter names and values. The path and parameters are
+// escaped, and a base with any other scheme is rejected so that data from
+// a request cannot produce a javascript: or similar link.
+func link(base, path string, params ...interface{}) (string, error) {
+	if !strings.Contains(base, "://") {
+		base = "https://" + base
+	}
+
+	target, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a valid link base", base)
+	}
+
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return "", fmt.Errorf("%q is not an http or https link", base)
+	}
+
+	if target.Host == "" {
+		return "", fmt.Errorf("%q has no host", base)
+	}
+
+	if len(params)%2 != 0 {
+		return "", errors.New("link parameters must be given as name and value pairs")
+	}
+
+	if path != "" {
+		target.Path = strings.TrimSuffix(target.Path, "/") + "/" + strings.TrimPrefix(path, "/")
+	}
+
+	query := target.Query()
+	for i := 0; i < len(params); i += 2 {
+		query.Add(toString(params[i]), toString(params[i+1]))
+	}
+	target.RawQuery = query.Encode()
+
+	return target.String(), nil
+}
+
+func toString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
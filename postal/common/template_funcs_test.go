@@ -0,0 +1,143 @@
+package common_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateFuncs", func() {
+	var (
+		packager common.Packager
+		context  common.MessageContext
+	)
+
+	render := func(subjectTemplate string) (string, error) {
+		context.SubjectTemplate = subjectTemplate
+		return packager.CompileSubject(context)
+	}
+
+	BeforeEach(func() {
+		packager = common.NewPackager(nil, nil)
+
+		received, err := time.Parse(time.RFC3339, "2015-06-08T21:40:12Z")
+		Expect(err).NotTo(HaveOccurred())
+
+		context = common.MessageContext{
+			Subject:         "  the subject  ",
+			Domain:          "example.com",
+			RequestReceived: received,
+			Data: common.Data{
+				"count":     json.Number("1234567.891"),
+				"one":       json.Number("1"),
+				"size":      json.Number("1536"),
+				"name":      "",
+				"instances": []interface{}{"a", json.Number("2")},
+				"app_guid":  "some guid&more",
+			},
+		}
+	})
+
+	Describe("date and time functions", func() {
+		It("formats times in UTC by default", func() {
+			Expect(render(`{{.RequestReceived | formatTime "2006-01-02 15:04 MST"}}`)).To(Equal("2015-06-08 21:40 UTC"))
+		})
+
+		It("converts times to a time zone", func() {
+			Expect(render(`{{.RequestReceived | inZone "Europe/Berlin" | formatTime "15:04 MST"}}`)).To(Equal("23:40 CEST"))
+		})
+
+		It("fails on an unknown time zone", func() {
+			_, err := render(`{{.RequestReceived | inZone "Mars/Olympus"}}`)
+			Expect(err).To(MatchError(ContainSubstring(`unknown time zone "Mars/Olympus"`)))
+		})
+	})
+
+	Describe("number functions", func() {
+		It("formats numbers with separators", func() {
+			Expect(render(`{{formatNumber .Data.count}} {{formatNumber 1000}} {{formatNumber -42.5}}`)).To(Equal("1,234,567.89 1,000 -42.5"))
+		})
+
+		It("formats byte counts", func() {
+			Expect(render(`{{formatBytes .Data.size}} {{formatBytes 512}} {{formatBytes 3221225472}}`)).To(Equal("1.5 KiB 512 B 3 GiB"))
+		})
+
+		It("pluralizes by count", func() {
+			Expect(render(`{{pluralize .Data.one "instance" "instances"}} {{pluralize .Data.size "instance" "instances"}}`)).To(Equal("instance instances"))
+		})
+
+		It("renders nothing for missing values", func() {
+			Expect(render(`[{{formatNumber .Data.missing}}{{formatBytes .Data.missing}}]`)).To(Equal("[]"))
+		})
+
+		It("fails on values that are not numbers", func() {
+			_, err := render(`{{formatNumber .Subject}}`)
+			Expect(err).To(MatchError(ContainSubstring(`"  the subject  " is not a number`)))
+		})
+	})
+
+	Describe("string functions", func() {
+		It("changes case and trims", func() {
+			Expect(render(`{{.Subject | trim | upper}}|{{"MiXeD" | lower}}|{{"hello there" | title}}`)).To(Equal("THE SUBJECT|mixed|Hello There"))
+		})
+
+		It("capitalizes words without splitting them at apostrophes", func() {
+			Expect(render(`{{"o'neil's well-known app_name" | title}}|{{"élan vital, ǆungla" | title}}|{{"API 2nd try" | title}}`)).To(Equal(
+				"O'neil's Well-Known App_Name|Élan Vital, ǅungla|API 2nd Try"))
+		})
+
+		It("truncates", func() {
+			Expect(render(`{{truncate 8 "a rather long subject"}}|{{truncate 30 "short"}}`)).To(Equal("a rat...|short"))
+		})
+
+		It("replaces and joins", func() {
+			Expect(render(`{{replace "-" " " "a-b-c"}}|{{join ", " .Data.instances}}`)).To(Equal("a b c|a, 2"))
+		})
+
+		It("defaults empty values", func() {
+			Expect(render(`{{.Data.name | default "there"}} {{.Data.missing | default "friend"}} {{.Data.one | default "none"}}`)).To(Equal("there friend 1"))
+		})
+
+		It("renders nothing for missing values", func() {
+			Expect(render(`[{{.Data.missing | upper}}|{{.Data.missing | lower}}|{{.Data.missing | title}}|{{.Data.missing | trim}}|{{.Data.missing | truncate 5}}|{{.Data.missing | replace "a" "b"}}]`)).To(Equal("[|||||]"))
+		})
+
+		It("renders nothing for keys of missing data", func() {
+			context.Data = nil
+
+			Expect(render(`[{{.Data.name | upper}}|{{.Data.name | title | truncate 5}}]`)).To(Equal("[|]"))
+		})
+
+		It("formats values that are not strings", func() {
+			Expect(render(`{{.Data.count | upper}}|{{.Data.one | replace "1" "one"}}`)).To(Equal("1234567.891|one"))
+		})
+
+		It("keeps the urlquery builtin", func() {
+			Expect(render(`{{urlquery .Data.app_guid}}`)).To(Equal("some+guid%26more"))
+		})
+	})
+
+	Describe("link", func() {
+		It("builds an https link from the domain", func() {
+			Expect(render(`{{link .Domain "/apps" "guid" .Data.app_guid}}`)).To(Equal("https://example.com/apps?guid=some+guid%26more"))
+		})
+
+		It("keeps an explicit http scheme and base path", func() {
+			Expect(render(`{{link "http://example.com/console/" "spaces/dev"}}`)).To(Equal("http://example.com/console/spaces/dev"))
+		})
+
+		It("rejects other schemes", func() {
+			_, err := render(`{{link "ftp://example.com" "/files"}}`)
+			Expect(err).To(MatchError(ContainSubstring(`"ftp://example.com" is not an http or https link`)))
+		})
+
+		It("requires parameters in pairs", func() {
+			_, err := render(`{{link .Domain "/apps" "guid"}}`)
+			Expect(err).To(MatchError(ContainSubstring("link parameters must be given as name and value pairs")))
+		})
+	})
+})
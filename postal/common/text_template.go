@@ -0,0 +1,71 @@
+package common
+
+import (
+	"text/template"
+	"text/template/parse"
+)
+
+// emptyIfMissingFunc is appended to every action of a text template so that
+// missing values, such as keys a client left out of .Data, render as nothing
+// instead of "<no value>". html/template already renders them as nothing.
+// The missingkey option does not help here: for a map of interface values
+// its zero value is still missing.
+const emptyIfMissingFunc = "emptyIfMissing"
+
+func emptyIfMissing(value interface{}) interface{} {
+	if value == nil {
+		return ""
+	}
+
+	return value
+}
+
+// parseTextTemplate parses a text template whose actions render missing
+// values as nothing.
+func parseTextTemplate(name, source string) (*template.Template, error) {
+	parsed, err := template.New(name).Funcs(TemplateFuncs()).Funcs(template.FuncMap{
+		emptyIfMissingFunc: emptyIfMissing,
+	}).Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range parsed.Templates() {
+		if t.Tree != nil {
+			renderMissingAsEmpty(t.Tree.Root)
+		}
+	}
+
+	return parsed, nil
+}
+
+func renderMissingAsEmpty(node parse.Node) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, child := range node.Nodes {
+			renderMissingAsEmpty(child)
+		}
+	case *parse.ActionNode:
+		// Actions that declare or assign variables print nothing.
+		if len(node.Pipe.Decl) > 0 {
+			return
+		}
+		node.Pipe.Cmds = append(node.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      node.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(emptyIfMissingFunc).SetPos(node.Pos)},
+		})
+	case *parse.IfNode:
+		renderMissingAsEmpty(node.List)
+		renderMissingAsEmpty(node.ElseList)
+	case *parse.RangeNode:
+		renderMissingAsEmpty(node.List)
+		renderMissingAsEmpty(node.ElseList)
+	case *parse.WithNode:
+		renderMissingAsEmpty(node.List)
+		renderMissingAsEmpty(node.ElseList)
+	}
+}
@@ -12,8 +12,13 @@ type messagesDeleter interface {
 	DeleteBefore(models.ConnectionInterface, time.Time) (int, error)
 }
 
+type dedupEntriesDeleter interface {
+	DeleteExpired(models.ConnectionInterface, time.Time) (int, error)
+}
+
 type MessageGC struct {
 	messages        messagesDeleter
+	dedupEntries    dedupEntriesDeleter
 	db              db.DatabaseInterface
 	lifetime        time.Duration
 	logger          *log.Logger
@@ -21,9 +26,10 @@ type MessageGC struct {
 	pollingInterval time.Duration
 }
 
-func NewMessageGC(lifetime time.Duration, db db.DatabaseInterface, messages messagesDeleter, pollingInterval time.Duration, logger *log.Logger) MessageGC {
+func NewMessageGC(lifetime time.Duration, db db.DatabaseInterface, messages messagesDeleter, dedupEntries dedupEntriesDeleter, pollingInterval time.Duration, logger *log.Logger) MessageGC {
 	return MessageGC{
 		messages:        messages,
+		dedupEntries:    dedupEntries,
 		db:              db,
 		lifetime:        lifetime,
 		logger:          logger,
@@ -33,11 +39,17 @@ func NewMessageGC(lifetime time.Duration, db db.DatabaseInterface, messages mess
 }
 
 func (gc MessageGC) Collect() {
-	threshold := time.Now().Add(-1 * gc.lifetime)
+	now := time.Now()
+	threshold := now.Add(-1 * gc.lifetime)
 	_, err := gc.messages.DeleteBefore(gc.db.Connection(), threshold)
 	if err != nil {
 		gc.logger.Printf("MessageGC.Collect() failed: " + err.Error())
 	}
+
+	_, err = gc.dedupEntries.DeleteExpired(gc.db.Connection(), now)
+	if err != nil {
+		gc.logger.Printf("MessageGC.Collect() failed to delete expired dedup entries: " + err.Error())
+	}
 }
 
 func (gc MessageGC) Run() {
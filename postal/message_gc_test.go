@@ -18,6 +18,7 @@ var _ = Describe("MessageGC", func() {
 	var (
 		messageGC       postal.MessageGC
 		repo            *mocks.MessagesRepo
+		dedupEntries    *mocks.DedupEntriesRepo
 		database        *mocks.Database
 		conn            db.ConnectionInterface
 		loggerBuffer    *bytes.Buffer
@@ -34,11 +35,12 @@ var _ = Describe("MessageGC", func() {
 		database.ConnectionCall.Returns.Connection = conn
 
 		repo = mocks.NewMessagesRepo()
+		dedupEntries = mocks.NewDedupEntriesRepo()
 
 		lifetime = 2 * time.Minute
 		pollingInterval = 500 * time.Millisecond
 
-		messageGC = postal.NewMessageGC(lifetime, database, repo, pollingInterval, logger)
+		messageGC = postal.NewMessageGC(lifetime, database, repo, dedupEntries, pollingInterval, logger)
 	})
 
 	Describe("Run", func() {
@@ -64,6 +66,13 @@ var _ = Describe("MessageGC", func() {
 			Expect(repo.DeleteBeforeCall.Receives.ThresholdTime).To(BeTemporally("~", time.Now().Add(-2*time.Minute), 10*time.Second))
 		})
 
+		It("Deletes dedup entries that have expired", func() {
+			messageGC.Collect()
+
+			Expect(dedupEntries.DeleteExpiredCall.Receives.Connection).To(Equal(conn))
+			Expect(dedupEntries.DeleteExpiredCall.Receives.Now).To(BeTemporally("~", time.Now(), 10*time.Second))
+		})
+
 		Context("When the repo errors unexpectantly", func() {
 			It("logs the error", func() {
 				repo.DeleteBeforeCall.Returns.Error = errors.New("messages table is totally corrupt")
@@ -72,6 +81,24 @@ var _ = Describe("MessageGC", func() {
 
 				Expect(loggerBuffer.String()).To(ContainSubstring("messages table is totally corrupt"))
 			})
+
+			It("still deletes expired dedup entries", func() {
+				repo.DeleteBeforeCall.Returns.Error = errors.New("messages table is totally corrupt")
+
+				messageGC.Collect()
+
+				Expect(dedupEntries.DeleteExpiredCall.CallCount).To(Equal(1))
+			})
+		})
+
+		Context("When the dedup entries cannot be deleted", func() {
+			It("logs the error", func() {
+				dedupEntries.DeleteExpiredCall.Returns.Error = errors.New("dedup_entries table is totally corrupt")
+
+				messageGC.Collect()
+
+				Expect(loggerBuffer.String()).To(ContainSubstring("dedup_entries table is totally corrupt"))
+			})
 		})
 
 	})
@@ -1,10 +1,13 @@
 package v1
 
 import (
-	"strings"
+	"encoding/json"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/address"
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/locale"
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
@@ -13,6 +16,11 @@ import (
 	"github.com/rcrowley/go-metrics"
 )
 
+// statusUnrenderable is what process returns for a message whose templates
+// cannot be rendered. Rendering the same message again fails the same way, so
+// it is given up on right away instead of being retried.
+const statusUnrenderable = "unrenderable"
+
 type tokenLoader interface {
 	Load(string) (string, error)
 }
@@ -28,6 +36,10 @@ type userLoader interface {
 
 type messageStatusUpdater interface {
 	Update(conn db.ConnectionInterface, messageID, messageStatus, campaignID string, logger lager.Logger)
+	Fail(conn db.ConnectionInterface, messageID string, err error, logger lager.Logger)
+	SetTemplate(conn db.ConnectionInterface, messageID, templateID string, templateVersion int, logger lager.Logger)
+	SetEmail(conn db.ConnectionInterface, messageID, email string, logger lager.Logger)
+	GiveUp(conn db.ConnectionInterface, messageID string, logger lager.Logger)
 }
 
 type deliveryFailureHandler interface {
@@ -50,6 +62,22 @@ type globalUnsubscribesGetter interface {
 	Get(connection models.ConnectionInterface, userGUID string) (bool, error)
 }
 
+type senderIdentityLoader interface {
+	Load(clientID, kindID string) (models.SenderIdentity, error)
+}
+
+type userSettingsFinder interface {
+	Find(connection models.ConnectionInterface, userID string) (models.UserSettings, error)
+}
+
+type digestPreferencesGetter interface {
+	Get(connection models.ConnectionInterface, userID, clientID, kindID string) (string, error)
+}
+
+type digestItemsCreator interface {
+	Create(connection models.ConnectionInterface, item models.DigestItem) (models.DigestItem, error)
+}
+
 type DeliveryJobProcessorConfig struct {
 	DBTrace bool
 	UAAHost string
@@ -62,10 +90,16 @@ type DeliveryJobProcessorConfig struct {
 	TokenLoader tokenLoader
 	UserLoader  userLoader
 
+	SenderIdentityLoader senderIdentityLoader
+	Endorsements         locale.Catalog
+
 	KindsRepo              kindsFinder
 	ReceiptsRepo           receiptsCreator
 	UnsubscribesRepo       unsubscribesGetter
 	GlobalUnsubscribesRepo globalUnsubscribesGetter
+	UserSettingsRepo       userSettingsFinder
+	DigestPreferencesRepo  digestPreferencesGetter
+	DigestItemsRepo        digestItemsCreator
 	MessageStatusUpdater   messageStatusUpdater
 	DeliveryFailureHandler deliveryFailureHandler
 }
@@ -82,10 +116,16 @@ type DeliveryJobProcessor struct {
 	tokenLoader tokenLoader
 	userLoader  userLoader
 
+	senderIdentityLoader senderIdentityLoader
+	endorsements         locale.Catalog
+
 	kindsRepo              kindsFinder
 	receiptsRepo           receiptsCreator
 	unsubscribesRepo       unsubscribesGetter
 	globalUnsubscribesRepo globalUnsubscribesGetter
+	userSettingsRepo       userSettingsFinder
+	digestPreferencesRepo  digestPreferencesGetter
+	digestItemsRepo        digestItemsCreator
 	messageStatusUpdater   messageStatusUpdater
 	deliveryFailureHandler deliveryFailureHandler
 }
@@ -103,10 +143,16 @@ func NewDeliveryJobProcessor(config DeliveryJobProcessorConfig) DeliveryJobProce
 		tokenLoader: config.TokenLoader,
 		userLoader:  config.UserLoader,
 
+		senderIdentityLoader: config.SenderIdentityLoader,
+		endorsements:         config.Endorsements,
+
 		kindsRepo:              config.KindsRepo,
 		receiptsRepo:           config.ReceiptsRepo,
 		unsubscribesRepo:       config.UnsubscribesRepo,
 		globalUnsubscribesRepo: config.GlobalUnsubscribesRepo,
+		userSettingsRepo:       config.UserSettingsRepo,
+		digestPreferencesRepo:  config.DigestPreferencesRepo,
+		digestItemsRepo:        config.DigestItemsRepo,
 		messageStatusUpdater:   config.MessageStatusUpdater,
 		deliveryFailureHandler: config.DeliveryFailureHandler,
 	}
@@ -131,9 +177,14 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 		p.database.TraceOn("", gorpCompatibleLogger{logger})
 	}
 
+	if expired(delivery, time.Now()) {
+		p.expire(delivery, logger)
+		return nil
+	}
+
 	err = p.receiptsRepo.CreateReceipts(p.database.Connection(), []string{delivery.UserGUID}, delivery.ClientID, delivery.Options.KindID)
 	if err != nil {
-		p.deliveryFailureHandler.Handle(job, logger)
+		p.retry(job, delivery, logger)
 		return nil
 	}
 
@@ -142,34 +193,62 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 
 		token, err = p.tokenLoader.Load(p.uaaHost)
 		if err != nil {
-			p.deliveryFailureHandler.Handle(job, logger)
+			p.retry(job, delivery, logger)
 			return nil
 		}
 
 		users, err := p.userLoader.Load([]string{delivery.UserGUID}, token)
 		if err != nil || len(users) < 1 {
-			p.deliveryFailureHandler.Handle(job, logger)
+			p.retry(job, delivery, logger)
 			return nil
 		}
 
-		emails := users[delivery.UserGUID].Emails
-		if len(emails) > 0 {
-			delivery.Email = emails[0]
-		}
+		delivery.Email = address.Primary(users[delivery.UserGUID].Emails)
+		p.messageStatusUpdater.SetEmail(p.database.Connection(), delivery.MessageID, delivery.Email, logger)
 	}
 
 	logger = logger.WithData(lager.Data{
 		"recipient": delivery.Email,
 	})
 
-	if p.shouldDeliver(delivery, logger) {
-		status := p.process(delivery, logger)
+	critical := p.isCritical(p.database.Connection(), delivery.Options.KindID, delivery.ClientID)
+	if p.shouldDeliver(&delivery, critical, logger) {
+		settings := p.recipientSettings(delivery, logger)
 
-		if status != common.StatusDelivered {
-			p.deliveryFailureHandler.Handle(job, logger)
+		if digest := p.recipientDigest(delivery, settings, critical, logger); digest != "" {
+			err = p.collect(delivery, digest, settings, logger)
+			if err != nil {
+				logger.Error("digest-collect-failed", err)
+				p.retry(job, delivery, logger)
+				return nil
+			}
+
+			logger.Info("message-digested", lager.Data{"digest": digest})
+			metrics.GetOrRegisterCounter("notifications.worker.digested", nil).Inc(1)
 			return nil
-		} else {
+		}
+
+		if activeAt, quiet := p.quietUntil(settings, critical, logger); quiet {
+			if expired(delivery, activeAt) {
+				p.expire(delivery, logger)
+				return nil
+			}
+
+			job.Defer(activeAt)
+			logger.Info("delivery-deferred", lager.Data{"active_at": activeAt.Format(time.RFC3339)})
+			metrics.GetOrRegisterCounter("notifications.worker.deferred", nil).Inc(1)
+			return nil
+		}
+
+		status := p.process(delivery, settings, logger)
+
+		switch status {
+		case common.StatusDelivered:
 			metrics.GetOrRegisterCounter("notifications.worker.delivered", nil).Inc(1)
+		case statusUnrenderable:
+			p.messageStatusUpdater.GiveUp(p.database.Connection(), delivery.MessageID, logger)
+		default:
+			p.retry(job, delivery, logger)
 		}
 	} else {
 		metrics.GetOrRegisterCounter("notifications.worker.unsubscribed", nil).Inc(1)
@@ -178,17 +257,73 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 	return nil
 }
 
-func (p DeliveryJobProcessor) process(delivery common.Delivery, logger lager.Logger) string {
-	context, err := p.packager.PrepareContext(delivery, p.sender, p.domain)
+// retry puts the job back on the queue unless the delivery would expire
+// before it is attempted again or has used up its retries.
+func (p DeliveryJobProcessor) retry(job *gobble.Job, delivery common.Delivery, logger lager.Logger) {
+	retryCount, _ := job.State()
+	if expired(delivery, time.Now().Add(common.RetryDelay(retryCount))) {
+		p.expire(delivery, logger)
+		return
+	}
+
+	if retryCount >= common.MaxRetries {
+		logger.Info("delivery-retries-exhausted", lager.Data{"retry_count": retryCount})
+		p.messageStatusUpdater.GiveUp(p.database.Connection(), delivery.MessageID, logger)
+		return
+	}
+
+	p.deliveryFailureHandler.Handle(job, logger)
+}
+
+func (p DeliveryJobProcessor) expire(delivery common.Delivery, logger lager.Logger) {
+	logger.Info("delivery-expired", lager.Data{"expires_at": delivery.Options.ExpiresAt.Format(time.RFC3339)})
+	p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusExpired, "", logger)
+	metrics.GetOrRegisterCounter("notifications.worker.expired", nil).Inc(1)
+}
+
+// expired tells whether the delivery is no longer worth sending at the
+// given time.
+func expired(delivery common.Delivery, at time.Time) bool {
+	return !delivery.Options.ExpiresAt.IsZero() && !at.Before(delivery.Options.ExpiresAt)
+}
+
+func (p DeliveryJobProcessor) process(delivery common.Delivery, settings models.UserSettings, logger lager.Logger) string {
+	sender := p.sender
+	identity, err := p.senderIdentityLoader.Load(delivery.ClientID, delivery.Options.KindID)
+	switch err.(type) {
+	case nil:
+		sender = identity.From()
+	case models.NotFoundError:
+	default:
+		logger.Error("sender-identity-load-failed", err)
+		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusFailed, "", logger)
+		return common.StatusFailed
+	}
+
+	delivery.Options.Locale = recipientLocale(delivery, settings)
+	delivery.Options.Endorsement = p.endorsements.Translate(delivery.Options.EndorsementID, delivery.Options.Endorsement, delivery.Options.Locale)
+
+	context, err := p.packager.PrepareContext(delivery, sender, p.domain)
 	if err != nil {
-		panic(err)
+		logger.Error("template-prepare-failed", err)
+		p.messageStatusUpdater.Fail(p.database.Connection(), delivery.MessageID, err, logger)
+		if _, ok := err.(common.PartialError); ok {
+			return statusUnrenderable
+		}
+		return common.StatusFailed
 	}
 
+	if context.ReplyTo == "" {
+		context.ReplyTo = identity.ReplyTo
+	}
+
+	p.messageStatusUpdater.SetTemplate(p.database.Connection(), delivery.MessageID, context.TemplateID, context.TemplateVersion, logger)
+
 	message, err := p.packager.Pack(context)
 	if err != nil {
-		logger.Info("template-pack-failed")
-		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusFailed, "", logger)
-		return common.StatusFailed
+		logger.Info("template-pack-failed", lager.Data{"error": err.Error()})
+		p.messageStatusUpdater.Fail(p.database.Connection(), delivery.MessageID, err, logger)
+		return statusUnrenderable
 	}
 
 	status := p.sendMail(delivery.MessageID, message, logger)
@@ -197,9 +332,27 @@ func (p DeliveryJobProcessor) process(delivery common.Delivery, logger lager.Log
 	return status
 }
 
-func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, logger lager.Logger) bool {
+// shouldDeliver reports whether the delivery can be sent, normalizing the
+// recipient's email address on the way. Critical notifications ignore the
+// recipient's unsubscribes, but still need a valid email address.
+func (p DeliveryJobProcessor) shouldDeliver(delivery *common.Delivery, critical bool, logger lager.Logger) bool {
 	conn := p.database.Connection()
-	if p.isCritical(conn, delivery.Options.KindID, delivery.ClientID) {
+
+	if delivery.Email == "" {
+		logger.Info("no-email-address-for-user")
+		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, "", logger)
+		return false
+	}
+
+	recipient, err := address.Parse(delivery.Email)
+	if err != nil {
+		logger.Info("malformatted-email-address", lager.Data{"error": err.Error()})
+		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, "", logger)
+		return false
+	}
+	delivery.Email = recipient.Email()
+
+	if critical {
 		return true
 	}
 
@@ -217,19 +370,105 @@ func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, logger lag
 		return false
 	}
 
-	if delivery.Email == "" {
-		logger.Info("no-email-address-for-user")
-		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, "", logger)
-		return false
+	return true
+}
+
+// recipientSettings loads the settings of the recipient. Recipients without
+// settings, and those whose settings cannot be loaded, get the defaults.
+func (p DeliveryJobProcessor) recipientSettings(delivery common.Delivery, logger lager.Logger) models.UserSettings {
+	if delivery.UserGUID == "" {
+		return models.UserSettings{}
 	}
 
-	if !strings.Contains(delivery.Email, "@") {
-		logger.Info("malformatted-email-address")
-		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, "", logger)
-		return false
+	settings, err := p.userSettingsRepo.Find(p.database.Connection(), delivery.UserGUID)
+	switch err.(type) {
+	case nil:
+		return settings
+	case models.NotFoundError:
+	default:
+		logger.Error("user-settings-load-failed", err)
 	}
 
-	return true
+	return models.UserSettings{}
+}
+
+// recipientLocale prefers the locale the user has chosen in their settings
+// over the one given on the request. Without either the message is rendered
+// unlocalized.
+func recipientLocale(delivery common.Delivery, settings models.UserSettings) string {
+	if settings.Locale != "" {
+		return settings.Locale
+	}
+
+	return delivery.Options.Locale
+}
+
+// quietUntil returns when the recipient's quiet hours end if the delivery
+// falls into them. Critical notifications are never held back.
+func (p DeliveryJobProcessor) quietUntil(settings models.UserSettings, critical bool, logger lager.Logger) (time.Time, bool) {
+	if critical {
+		return time.Time{}, false
+	}
+
+	activeAt, quiet, err := settings.QuietUntil(time.Now())
+	if err != nil {
+		logger.Error("quiet-hours-invalid", err)
+		return time.Time{}, false
+	}
+
+	return activeAt, quiet
+}
+
+// recipientDigest returns the digest a delivery is held back for, or an
+// empty string when it is sent right away. A digest chosen for the kind
+// takes precedence over the one the user has chosen in their settings.
+// Critical notifications are never held back.
+func (p DeliveryJobProcessor) recipientDigest(delivery common.Delivery, settings models.UserSettings, critical bool, logger lager.Logger) string {
+	if critical || delivery.UserGUID == "" {
+		return ""
+	}
+
+	digest, err := p.digestPreferencesRepo.Get(p.database.Connection(), delivery.UserGUID, delivery.ClientID, delivery.Options.KindID)
+	if err != nil {
+		logger.Error("digest-preference-load-failed", err)
+		return ""
+	}
+
+	if digest == "" {
+		digest = settings.Digest
+	}
+
+	if digest == models.DigestImmediate {
+		return ""
+	}
+
+	return digest
+}
+
+// collect stores the delivery so that the DigestSender sends it with the
+// rest of the recipient's digest once it is due. Digests fall due in the
+// recipient's timezone, or in UTC when it is not valid.
+func (p DeliveryJobProcessor) collect(delivery common.Delivery, digest string, settings models.UserSettings, logger lager.Logger) error {
+	payload, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	location, err := settings.Location()
+	if err != nil {
+		logger.Error("timezone-invalid", err)
+		location = time.UTC
+	}
+
+	_, err = p.digestItemsRepo.Create(p.database.Connection(), models.DigestItem{
+		UserGUID:  delivery.UserGUID,
+		Digest:    digest,
+		MessageID: delivery.MessageID,
+		Delivery:  string(payload),
+		DueAt:     models.DigestDueAt(digest, time.Now(), location),
+	})
+
+	return err
 }
 
 func (p DeliveryJobProcessor) sendMail(messageID string, message mail.Message, logger lager.Logger) string {
@@ -3,11 +3,13 @@ package v1_test
 import (
 	"bytes"
 	"crypto/md5"
+	"encoding/json"
 	"errors"
 	"strings"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/locale"
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/postal/v1"
@@ -30,6 +32,9 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		delivery               common.Delivery
 		unsubscribesRepo       *mocks.UnsubscribesRepo
 		globalUnsubscribesRepo *mocks.GlobalUnsubscribesRepo
+		userSettingsRepo       *mocks.UserSettingsRepo
+		digestPreferencesRepo  *mocks.DigestPreferencesRepo
+		digestItemsRepo        *mocks.DigestItemsRepo
 		kindsRepo              *mocks.KindsRepo
 		database               *mocks.Database
 		conn                   *mocks.Connection
@@ -37,6 +42,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		userGUID               string
 		fakeUserEmail          string
 		templateLoader         *mocks.TemplatesLoader
+		senderIdentityLoader   *mocks.SenderIdentityLoader
 		receiptsRepo           *mocks.ReceiptsRepo
 		tokenLoader            *mocks.TokenLoader
 		messageID              string
@@ -54,6 +60,9 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		mailClient = mocks.NewMailClient()
 		unsubscribesRepo = mocks.NewUnsubscribesRepo()
 		globalUnsubscribesRepo = mocks.NewGlobalUnsubscribesRepo()
+		userSettingsRepo = mocks.NewUserSettingsRepo()
+		digestPreferencesRepo = mocks.NewDigestPreferencesRepo()
+		digestItemsRepo = mocks.NewDigestItemsRepo()
 
 		kindsRepo = mocks.NewKindsRepo()
 		kindsRepo.FindCall.Returns.Kinds = []models.Kind{
@@ -79,7 +88,11 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		}
 		tokenLoader = mocks.NewTokenLoader()
 		templateLoader = mocks.NewTemplatesLoader()
+		senderIdentityLoader = mocks.NewSenderIdentityLoader()
+		senderIdentityLoader.LoadCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
 		templateLoader.LoadTemplatesCall.Returns.Templates = common.Templates{
+			ID:      "some-template-id",
+			Version: 2,
 			Text:    "{{.Text}} {{.Domain}}",
 			HTML:    "<p>{{.HTML}}</p>",
 			Subject: "{{.Subject}}",
@@ -103,10 +116,20 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			TokenLoader: tokenLoader,
 			UserLoader:  userLoader,
 
+			SenderIdentityLoader: senderIdentityLoader,
+			Endorsements: locale.Catalog{
+				"endorsement.user": {
+					"de": "Diese Nachricht wurde an Sie gesendet.",
+				},
+			},
+
 			KindsRepo:              kindsRepo,
 			ReceiptsRepo:           receiptsRepo,
 			UnsubscribesRepo:       unsubscribesRepo,
 			GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+			UserSettingsRepo:       userSettingsRepo,
+			DigestPreferencesRepo:  digestPreferencesRepo,
+			DigestItemsRepo:        digestItemsRepo,
 			MessageStatusUpdater:   messageStatusUpdater,
 			DeliveryFailureHandler: deliveryFailureHandler,
 		})
@@ -162,6 +185,111 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Expect(templateLoader.LoadTemplatesCall.Receives.TemplateID).To(Equal("some-template-id"))
 		})
 
+		Context("when localizing the message", func() {
+			BeforeEach(func() {
+				templateLoader.LoadTemplatesCall.Returns.Templates.Text = "{{.Text}} {{.Endorsement}}"
+				delivery.Options.Endorsement = "This message was sent to you."
+				delivery.Options.EndorsementID = "endorsement.user"
+			})
+
+			It("uses the locale from the user's settings", func() {
+				userSettingsRepo.FindCall.Returns.Settings = models.UserSettings{
+					UserID: userGUID,
+					Locale: "de-CH",
+				}
+
+				processor.Process(gobble.NewJob(delivery), logger)
+
+				Expect(userSettingsRepo.FindCall.Receives.Connection).To(Equal(conn))
+				Expect(userSettingsRepo.FindCall.Receives.UserID).To(Equal(userGUID))
+				Expect(templateLoader.LoadTemplatesCall.Receives.Locale).To(Equal("de-CH"))
+			})
+
+			It("prefers the locale from the user's settings over the one given on the request", func() {
+				delivery.Options.Locale = "fr"
+				userSettingsRepo.FindCall.Returns.Settings = models.UserSettings{Locale: "de-CH"}
+
+				processor.Process(gobble.NewJob(delivery), logger)
+
+				Expect(templateLoader.LoadTemplatesCall.Receives.Locale).To(Equal("de-CH"))
+			})
+
+			It("uses the locale given on the request when the user has not chosen one", func() {
+				delivery.Options.Locale = "fr"
+				userSettingsRepo.FindCall.Returns.Settings = models.UserSettings{UserID: userGUID}
+
+				processor.Process(gobble.NewJob(delivery), logger)
+
+				Expect(templateLoader.LoadTemplatesCall.Receives.Locale).To(Equal("fr"))
+			})
+
+			It("uses the locale given on the request when the user has no settings", func() {
+				delivery.Options.Locale = "fr"
+				userSettingsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				processor.Process(gobble.NewJob(delivery), logger)
+
+				Expect(templateLoader.LoadTemplatesCall.Receives.Locale).To(Equal("fr"))
+			})
+
+			It("delivers unlocalized when the user has no settings", func() {
+				userSettingsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				processor.Process(gobble.NewJob(delivery), logger)
+
+				Expect(templateLoader.LoadTemplatesCall.Receives.Locale).To(BeEmpty())
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+			})
+
+			It("translates the endorsement into the recipient's locale", func() {
+				delivery.Options.Locale = "de-AT"
+
+				processor.Process(gobble.NewJob(delivery), logger)
+
+				Expect(mailClient.SendCall.Receives.Message.Body).To(ContainElement(mail.Part{
+					ContentType: "text/plain",
+					Content:     "body content Diese Nachricht wurde an Sie gesendet.",
+				}))
+			})
+
+			It("leaves endorsements queued without an ID untranslated", func() {
+				delivery.Options.Locale = "de"
+				delivery.Options.EndorsementID = ""
+
+				processor.Process(gobble.NewJob(delivery), logger)
+
+				Expect(mailClient.SendCall.Receives.Message.Body).To(ContainElement(mail.Part{
+					ContentType: "text/plain",
+					Content:     "body content This message was sent to you.",
+				}))
+			})
+		})
+
+		It("records the template version that rendered the message", func() {
+			processor.Process(job, logger)
+
+			Expect(messageStatusUpdater.SetTemplateCall.Receives.Connection).To(Equal(conn))
+			Expect(messageStatusUpdater.SetTemplateCall.Receives.MessageID).To(Equal(messageID))
+			Expect(messageStatusUpdater.SetTemplateCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(messageStatusUpdater.SetTemplateCall.Receives.TemplateVersion).To(Equal(2))
+		})
+
+		It("records the email address the user was resolved to", func() {
+			processor.Process(job, logger)
+
+			Expect(messageStatusUpdater.SetEmailCall.Receives.Connection).To(Equal(conn))
+			Expect(messageStatusUpdater.SetEmailCall.Receives.MessageID).To(Equal(messageID))
+			Expect(messageStatusUpdater.SetEmailCall.Receives.Email).To(Equal("user-123@example.com"))
+		})
+
+		It("does not record the email address when it was given on the request", func() {
+			delivery.Email = "someone@example.com"
+
+			processor.Process(gobble.NewJob(delivery), logger)
+
+			Expect(messageStatusUpdater.SetEmailCall.WasCalled).To(BeFalse())
+		})
+
 		It("logs successful delivery", func() {
 			processor.Process(job, logger)
 
@@ -199,10 +327,15 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				TokenLoader: tokenLoader,
 				UserLoader:  userLoader,
 
+				SenderIdentityLoader: senderIdentityLoader,
+
 				KindsRepo:              kindsRepo,
 				ReceiptsRepo:           receiptsRepo,
 				UnsubscribesRepo:       unsubscribesRepo,
 				GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+				UserSettingsRepo:       userSettingsRepo,
+				DigestPreferencesRepo:  digestPreferencesRepo,
+				DigestItemsRepo:        digestItemsRepo,
 				MessageStatusUpdater:   messageStatusUpdater,
 				DeliveryFailureHandler: deliveryFailureHandler,
 			})
@@ -246,6 +379,73 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the delivery has expired", func() {
+			BeforeEach(func() {
+				delivery.Options.ExpiresAt = time.Now().Add(-1 * time.Minute)
+				job = gobble.NewJob(delivery)
+			})
+
+			It("marks the message as expired without sending it", func() {
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(receiptsRepo.CreateReceiptsCall.Receives.UserGUIDs).To(BeEmpty())
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusExpired))
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		Context("when the delivery would expire before it is retried", func() {
+			BeforeEach(func() {
+				delivery.Options.ExpiresAt = time.Now().Add(30 * time.Second)
+				job = gobble.NewJob(delivery)
+				receiptsRepo.CreateReceiptsCall.Returns.Error = errors.New("something happened")
+			})
+
+			It("marks the message as expired instead of retrying", func() {
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusExpired))
+			})
+
+			It("retries the job when the delivery expires later", func() {
+				delivery.Options.ExpiresAt = time.Now().Add(time.Hour)
+				job = gobble.NewJob(delivery)
+
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeTrue())
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(BeEmpty())
+			})
+		})
+
+		Context("when the delivery has used up its retries", func() {
+			BeforeEach(func() {
+				job = gobble.NewJob(delivery)
+				job.RetryCount = common.MaxRetries
+				receiptsRepo.CreateReceiptsCall.Returns.Error = errors.New("something happened")
+			})
+
+			It("gives up on the message instead of retrying", func() {
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+				Expect(messageStatusUpdater.GiveUpCall.Receives.Connection).To(Equal(conn))
+				Expect(messageStatusUpdater.GiveUpCall.Receives.MessageID).To(Equal(messageID))
+			})
+
+			It("keeps retrying deliveries that have retries left", func() {
+				job.RetryCount = common.MaxRetries - 1
+
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeTrue())
+				Expect(messageStatusUpdater.GiveUpCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("when loading a zoned token fails", func() {
 			It("retries the job", func() {
 				job := gobble.NewJob(delivery)
@@ -291,6 +491,54 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Expect(timestamp).To(BeTemporally("~", time.Now(), 2*time.Second))
 		})
 
+		Context("when a sender identity is registered", func() {
+			BeforeEach(func() {
+				senderIdentityLoader.LoadCall.Returns.Error = nil
+				senderIdentityLoader.LoadCall.Returns.SenderIdentity = models.SenderIdentity{
+					ClientID: "some-client",
+					Name:     "Some Client",
+					Address:  "some-client@example.com",
+					ReplyTo:  "some-client-support@example.com",
+				}
+			})
+
+			It("sends the message from the sender identity", func() {
+				processor.Process(job, logger)
+
+				Expect(senderIdentityLoader.LoadCall.Receives.ClientID).To(Equal("some-client"))
+				Expect(senderIdentityLoader.LoadCall.Receives.KindID).To(Equal("some-kind"))
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+				msg := mailClient.SendCall.Receives.Message
+				Expect(msg.From).To(Equal(`"Some Client" <some-client@example.com>`))
+				Expect(msg.ReplyTo).To(Equal("thesender@example.com"))
+			})
+
+			It("defaults the reply to address to the one of the sender identity", func() {
+				delivery.Options.ReplyTo = ""
+				job = gobble.NewJob(delivery)
+
+				processor.Process(job, logger)
+
+				msg := mailClient.SendCall.Receives.Message
+				Expect(msg.ReplyTo).To(Equal("some-client-support@example.com"))
+			})
+		})
+
+		Context("when the sender identity cannot be loaded", func() {
+			BeforeEach(func() {
+				senderIdentityLoader.LoadCall.Returns.Error = errors.New("database is down")
+			})
+
+			It("marks the job for retry and the message as failed", func() {
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+			})
+		})
+
 		It("should connect and send the message with the worker's logger session", func() {
 			processor.Process(job, logger)
 			Expect(mailClient.ConnectCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
@@ -477,6 +725,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 						Data: map[string]interface{}{
 							"session":         "1",
 							"recipient":       "nope",
+							"error":           `"nope" is not a valid email address`,
 							"worker_id":       float64(1234),
 							"message_id":      "randomly-generated-guid",
 							"vcap_request_id": "some-request-id",
@@ -493,6 +742,53 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the notification is critical", func() {
+			BeforeEach(func() {
+				kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+					{
+						ID:       "some-kind",
+						ClientID: "some-client",
+						Critical: true,
+					},
+				}
+			})
+
+			It("still refuses a malformed email address", func() {
+				delivery.Email = "nope"
+				job := gobble.NewJob(delivery)
+
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+			})
+
+			It("still normalizes the email address", func() {
+				delivery.Email = "The User <user-123@Bücher.example>"
+				job := gobble.NewJob(delivery)
+
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+				Expect(mailClient.SendCall.Receives.Message.To).To(Equal("user-123@xn--bcher-kva.example"))
+			})
+		})
+
+		Context("when the recipient has several email addresses", func() {
+			It("sends to the first address that can be parsed", func() {
+				delivery.Email = ""
+				userLoader.LoadCall.Returns.Users = map[string]uaa.User{
+					"user-123": {Emails: []string{"not-an-address", "The User <user-123@Bücher.example>"}},
+				}
+				job := gobble.NewJob(delivery)
+
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+				Expect(mailClient.SendCall.Receives.Message.To).To(Equal("user-123@xn--bcher-kva.example"))
+			})
+		})
+
 		Context("when recipient has unsubscribed", func() {
 			BeforeEach(func() {
 				unsubscribesRepo.GetCall.Returns.Unsubscribed = true
@@ -572,6 +868,236 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the recipient receives a digest", func() {
+			BeforeEach(func() {
+				userSettingsRepo.FindCall.Returns.Settings = models.UserSettings{
+					UserID: userGUID,
+					Digest: models.DigestDaily,
+				}
+			})
+
+			It("collects the delivery instead of sending it", func() {
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(digestPreferencesRepo.GetCall.Receives.Connection).To(Equal(conn))
+				Expect(digestPreferencesRepo.GetCall.Receives.UserID).To(Equal(userGUID))
+				Expect(digestPreferencesRepo.GetCall.Receives.ClientID).To(Equal("some-client"))
+				Expect(digestPreferencesRepo.GetCall.Receives.KindID).To(Equal("some-kind"))
+
+				item := digestItemsRepo.CreateCall.Receives.Item
+				Expect(digestItemsRepo.CreateCall.Receives.Connection).To(Equal(conn))
+				Expect(item.UserGUID).To(Equal(userGUID))
+				Expect(item.Digest).To(Equal(models.DigestDaily))
+				Expect(item.MessageID).To(Equal(messageID))
+				Expect(item.DueAt).To(Equal(models.DigestDueAt(models.DigestDaily, time.Now(), time.UTC)))
+
+				var collected common.Delivery
+				Expect(json.Unmarshal([]byte(item.Delivery), &collected)).To(Succeed())
+				Expect(collected.Email).To(Equal(fakeUserEmail))
+				Expect(collected.Options.Subject).To(Equal("the subject"))
+			})
+
+			It("schedules the digest in the recipient's timezone", func() {
+				userSettingsRepo.FindCall.Returns.Settings.Timezone = "Asia/Tokyo"
+				tokyo, err := time.LoadLocation("Asia/Tokyo")
+				Expect(err).NotTo(HaveOccurred())
+
+				processor.Process(job, logger)
+
+				dueAt := digestItemsRepo.CreateCall.Receives.Item.DueAt
+				Expect(dueAt).To(Equal(models.DigestDueAt(models.DigestDaily, time.Now(), tokyo)))
+				Expect(dueAt.In(tokyo).Hour()).To(Equal(0))
+			})
+
+			It("schedules the digest in UTC when the recipient's timezone is not valid", func() {
+				userSettingsRepo.FindCall.Returns.Settings.Timezone = "Not/A_Zone"
+
+				processor.Process(job, logger)
+
+				Expect(digestItemsRepo.CreateCall.Receives.Item.DueAt).To(Equal(models.DigestDueAt(models.DigestDaily, time.Now(), time.UTC)))
+			})
+
+			It("leaves the message queued", func() {
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(BeEmpty())
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+			})
+
+			It("prefers the digest chosen for the kind", func() {
+				digestPreferencesRepo.GetCall.Returns.Digest = models.DigestHourly
+
+				processor.Process(job, logger)
+
+				Expect(digestItemsRepo.CreateCall.Receives.Item.Digest).To(Equal(models.DigestHourly))
+			})
+
+			It("sends the message when the kind is delivered immediately", func() {
+				digestPreferencesRepo.GetCall.Returns.Digest = models.DigestImmediate
+
+				processor.Process(job, logger)
+
+				Expect(digestItemsRepo.CreateCall.WasCalled).To(BeFalse())
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+			})
+
+			It("sends critical notifications immediately", func() {
+				kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+					{
+						ID:       "some-kind",
+						ClientID: "some-client",
+						Critical: true,
+					},
+				}
+
+				processor.Process(job, logger)
+
+				Expect(digestItemsRepo.CreateCall.WasCalled).To(BeFalse())
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+			})
+
+			It("does not collect deliveries to users who have unsubscribed", func() {
+				unsubscribesRepo.GetCall.Returns.Unsubscribed = true
+
+				processor.Process(job, logger)
+
+				Expect(digestItemsRepo.CreateCall.WasCalled).To(BeFalse())
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+			})
+
+			Context("when the delivery cannot be collected", func() {
+				BeforeEach(func() {
+					digestItemsRepo.CreateCall.Returns.Error = errors.New("database is gone")
+				})
+
+				It("retries the job", func() {
+					processor.Process(job, logger)
+
+					Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeTrue())
+					Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				})
+			})
+
+			Context("when the digest preference cannot be loaded", func() {
+				BeforeEach(func() {
+					digestPreferencesRepo.GetCall.Returns.Error = errors.New("database is gone")
+				})
+
+				It("sends the message immediately", func() {
+					processor.Process(job, logger)
+
+					Expect(digestItemsRepo.CreateCall.WasCalled).To(BeFalse())
+					Expect(mailClient.SendCall.CallCount).To(Equal(1))
+				})
+			})
+		})
+
+		Context("when the delivery falls into the recipient's quiet hours", func() {
+			var quietHoursEnd time.Time
+
+			BeforeEach(func() {
+				now := time.Now().UTC()
+				quietHoursEnd = now.Add(2 * time.Hour).Truncate(time.Minute)
+				userSettingsRepo.FindCall.Returns.Settings = models.UserSettings{
+					UserID:          userGUID,
+					QuietHoursStart: now.Add(-1 * time.Hour).Format(models.QuietHoursLayout),
+					QuietHoursEnd:   quietHoursEnd.Format(models.QuietHoursLayout),
+				}
+			})
+
+			It("defers the job until the quiet hours end", func() {
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(job.ShouldRetry).To(BeTrue())
+				Expect(job.RetryCount).To(Equal(0))
+				Expect(job.ActiveAt.Equal(quietHoursEnd)).To(BeTrue())
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(BeEmpty())
+			})
+
+			It("sends critical notifications immediately", func() {
+				kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+					{
+						ID:       "some-kind",
+						ClientID: "some-client",
+						Critical: true,
+					},
+				}
+
+				processor.Process(job, logger)
+
+				Expect(job.ShouldRetry).To(BeFalse())
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+			})
+
+			It("expires the delivery when it would expire before the quiet hours end", func() {
+				delivery.Options.ExpiresAt = quietHoursEnd.Add(-1 * time.Minute)
+
+				processor.Process(gobble.NewJob(delivery), logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusExpired))
+			})
+
+			It("sends the message when the quiet hours cannot be read", func() {
+				userSettingsRepo.FindCall.Returns.Settings.Timezone = "Mars/Olympus_Mons"
+
+				processor.Process(job, logger)
+
+				Expect(job.ShouldRetry).To(BeFalse())
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+			})
+
+			It("collects the delivery when the recipient receives a digest", func() {
+				userSettingsRepo.FindCall.Returns.Settings.Digest = models.DigestHourly
+
+				processor.Process(job, logger)
+
+				Expect(job.ShouldRetry).To(BeFalse())
+				Expect(digestItemsRepo.CreateCall.WasCalled).To(BeTrue())
+			})
+		})
+
+		Context("when the templates cannot be loaded", func() {
+			BeforeEach(func() {
+				templateLoader.LoadTemplatesCall.Returns.Error = common.PartialError{Err: errors.New(`layout "wrapper" does not exist`)}
+				job = gobble.NewJob(delivery)
+			})
+
+			It("does not panic", func() {
+				Expect(func() {
+					processor.Process(job, logger)
+				}).ToNot(Panic())
+			})
+
+			It("marks the message as failed with the error", func() {
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.FailCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.FailCall.Receives.Error).To(MatchError(`layout "wrapper" does not exist`))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("gives up on the message instead of retrying", func() {
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+				Expect(messageStatusUpdater.GiveUpCall.Receives.Connection).To(Equal(conn))
+				Expect(messageStatusUpdater.GiveUpCall.Receives.MessageID).To(Equal(messageID))
+			})
+
+			It("marks the job for retry later when the error is not caused by the template", func() {
+				templateLoader.LoadTemplatesCall.Returns.Error = errors.New("database is down")
+
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(messageStatusUpdater.GiveUpCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("when the template contains syntax errors", func() {
 			BeforeEach(func() {
 				templateLoader.LoadTemplatesCall.Returns.Templates = common.Templates{
@@ -588,11 +1114,11 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				}).ToNot(Panic())
 			})
 
-			It("marks the job for retry later", func() {
+			It("gives up on the message instead of retrying", func() {
 				processor.Process(job, logger)
 
-				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
-				Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+				Expect(messageStatusUpdater.GiveUpCall.Receives.MessageID).To(Equal(messageID))
 			})
 
 			It("logs that the packer errored", func() {
@@ -607,6 +1133,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 					LogLevel: int(lager.INFO),
 					Data: map[string]interface{}{
 						"session":         "1",
+						"error":           "template: content:6: bad character U+007D '}'",
 						"recipient":       "user-123@example.com",
 						"worker_id":       float64(1234),
 						"message_id":      "randomly-generated-guid",
@@ -615,13 +1142,44 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				}))
 			})
 
-			It("updates the message status as failed", func() {
+			It("marks the message as failed with the template error", func() {
 				processor.Process(job, logger)
 
-				Expect(messageStatusUpdater.UpdateCall.Receives.Connection).To(Equal(conn))
-				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
-				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
-				Expect(messageStatusUpdater.UpdateCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
+				Expect(messageStatusUpdater.FailCall.Receives.Connection).To(Equal(conn))
+				Expect(messageStatusUpdater.FailCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.FailCall.Receives.Error).To(MatchError(ContainSubstring("bad character")))
+				Expect(messageStatusUpdater.FailCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
+			})
+		})
+
+		Context("when the template fails to execute", func() {
+			BeforeEach(func() {
+				templateLoader.LoadTemplatesCall.Returns.Templates = common.Templates{
+					Text:    "{{.Text}} {{.MissingField}}",
+					HTML:    "<p>{{.HTML}}</p>",
+					Subject: "{{.Subject}}",
+				}
+				job = gobble.NewJob(delivery)
+			})
+
+			It("does not send the truncated message", func() {
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("marks the message as failed with the template error", func() {
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.FailCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.FailCall.Receives.Error).To(MatchError(ContainSubstring("can't evaluate field MissingField")))
+			})
+
+			It("gives up on the message instead of retrying", func() {
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+				Expect(messageStatusUpdater.GiveUpCall.Receives.MessageID).To(Equal(messageID))
 			})
 		})
 
@@ -0,0 +1,220 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/pivotal-golang/conceal"
+	"github.com/pivotal-golang/lager"
+)
+
+type digestItemsRepo interface {
+	FindDue(connection models.ConnectionInterface, now time.Time) ([]models.DigestItem, error)
+	Postpone(connection models.ConnectionInterface, items []models.DigestItem, dueAt time.Time) error
+	Delete(connection models.ConnectionInterface, items []models.DigestItem) error
+}
+
+type DigestSenderConfig struct {
+	Sender          string
+	Domain          string
+	Templates       common.DigestTemplates
+	PollingInterval time.Duration
+
+	Cloak                conceal.CloakInterface
+	MailClient           mailSender
+	Database             db.DatabaseInterface
+	DigestItemsRepo      digestItemsRepo
+	UserSettingsRepo     userSettingsFinder
+	MessageStatusUpdater messageStatusUpdater
+	Logger               lager.Logger
+}
+
+// DigestSender periodically sends the deliveries the DeliveryJobProcessor
+// collected for users who receive their notifications as a digest. Every
+// recipient gets a single message per digest period, held back while the
+// recipient is in their quiet hours.
+type DigestSender struct {
+	sender          string
+	domain          string
+	templates       common.DigestTemplates
+	pollingInterval time.Duration
+
+	cloak                conceal.CloakInterface
+	mailClient           mailSender
+	database             db.DatabaseInterface
+	digestItemsRepo      digestItemsRepo
+	userSettingsRepo     userSettingsFinder
+	messageStatusUpdater messageStatusUpdater
+	logger               lager.Logger
+}
+
+func NewDigestSender(config DigestSenderConfig) DigestSender {
+	return DigestSender{
+		sender:          config.Sender,
+		domain:          config.Domain,
+		templates:       config.Templates,
+		pollingInterval: config.PollingInterval,
+
+		cloak:                config.Cloak,
+		mailClient:           config.MailClient,
+		database:             config.Database,
+		digestItemsRepo:      config.DigestItemsRepo,
+		userSettingsRepo:     config.UserSettingsRepo,
+		messageStatusUpdater: config.MessageStatusUpdater,
+		logger:               config.Logger,
+	}
+}
+
+func (s DigestSender) Run() {
+	go func() {
+		timer := time.After(0)
+		for {
+			<-timer
+			s.Send(time.Now())
+			timer = time.After(s.pollingInterval)
+		}
+	}()
+}
+
+// Send sends every digest that is due at the given time. Items that could
+// not be sent because the mail server was unavailable are kept and retried
+// on the next run.
+func (s DigestSender) Send(now time.Time) {
+	conn := s.database.Connection()
+
+	items, err := s.digestItemsRepo.FindDue(conn, now)
+	if err != nil {
+		s.logger.Error("digest-load-failed", err)
+		return
+	}
+
+	for _, group := range groupDigestItems(items) {
+		s.sendDigest(conn, group, now)
+	}
+}
+
+func (s DigestSender) sendDigest(conn db.ConnectionInterface, items []models.DigestItem, now time.Time) {
+	logger := s.logger.WithData(lager.Data{
+		"user_guid": items[0].UserGUID,
+		"digest":    items[0].Digest,
+	})
+
+	if activeAt, quiet := s.quietUntil(conn, items[0].UserGUID, now, logger); quiet {
+		err := s.digestItemsRepo.Postpone(conn, items, activeAt)
+		if err != nil {
+			logger.Error("digest-postpone-failed", err)
+			return
+		}
+
+		logger.Info("digest-deferred", lager.Data{"active_at": activeAt.Format(time.RFC3339)})
+		return
+	}
+
+	var deliveries []common.Delivery
+	var settled []models.DigestItem
+	for _, item := range items {
+		var delivery common.Delivery
+		err := json.Unmarshal([]byte(item.Delivery), &delivery)
+		if err != nil {
+			logger.Error("digest-item-unmarshal-failed", err, lager.Data{"message_id": item.MessageID})
+			s.messageStatusUpdater.Fail(conn, item.MessageID, err, logger)
+			s.messageStatusUpdater.GiveUp(conn, item.MessageID, logger)
+			settled = append(settled, item)
+			continue
+		}
+
+		if expired(delivery, now) {
+			logger.Info("digest-item-expired", lager.Data{"message_id": item.MessageID})
+			s.messageStatusUpdater.Update(conn, item.MessageID, common.StatusExpired, "", logger)
+			settled = append(settled, item)
+			continue
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if len(deliveries) > 0 {
+		context := common.NewDigestContext(deliveries, items[0].Digest, s.sender, s.domain, s.cloak)
+
+		message, err := common.PackDigest(s.templates, context)
+		if err != nil {
+			logger.Info("digest-pack-failed", lager.Data{"error": err.Error()})
+			for _, delivery := range deliveries {
+				s.messageStatusUpdater.Fail(conn, delivery.MessageID, err, logger)
+				s.messageStatusUpdater.GiveUp(conn, delivery.MessageID, logger)
+			}
+		} else {
+			err = s.mailClient.Connect(logger)
+			if err == nil {
+				err = s.mailClient.Send(message, logger)
+			}
+
+			if err != nil {
+				logger.Error("digest-delivery-failed", err)
+
+				// Only the items that made it into the digest are retried;
+				// the others have been settled already.
+				if len(settled) > 0 {
+					err = s.digestItemsRepo.Delete(conn, settled)
+					if err != nil {
+						logger.Error("digest-delete-failed", err)
+					}
+				}
+				return
+			}
+
+			logger.Info("digest-sent", lager.Data{"count": len(deliveries)})
+			for _, delivery := range deliveries {
+				s.messageStatusUpdater.Update(conn, delivery.MessageID, common.StatusDelivered, "", logger)
+			}
+		}
+	}
+
+	err := s.digestItemsRepo.Delete(conn, items)
+	if err != nil {
+		logger.Error("digest-delete-failed", err)
+	}
+}
+
+// quietUntil returns when the recipient's quiet hours end if the given time
+// falls into them. Digests of recipients whose settings cannot be loaded are
+// sent right away.
+func (s DigestSender) quietUntil(conn db.ConnectionInterface, userGUID string, now time.Time, logger lager.Logger) (time.Time, bool) {
+	settings, err := s.userSettingsRepo.Find(conn, userGUID)
+	switch err.(type) {
+	case nil:
+	case models.NotFoundError:
+		return time.Time{}, false
+	default:
+		logger.Error("user-settings-load-failed", err)
+		return time.Time{}, false
+	}
+
+	activeAt, quiet, err := settings.QuietUntil(now)
+	if err != nil {
+		logger.Error("quiet-hours-invalid", err)
+		return time.Time{}, false
+	}
+
+	return activeAt, quiet
+}
+
+// groupDigestItems splits items, which are ordered by recipient and digest,
+// into one group per digest message.
+func groupDigestItems(items []models.DigestItem) [][]models.DigestItem {
+	var groups [][]models.DigestItem
+	for _, item := range items {
+		last := len(groups) - 1
+		if last >= 0 && groups[last][0].UserGUID == item.UserGUID && groups[last][0].Digest == item.Digest {
+			groups[last] = append(groups[last], item)
+			continue
+		}
+
+		groups = append(groups, []models.DigestItem{item})
+	}
+
+	return groups
+}
@@ -0,0 +1,293 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/postal/v1"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DigestSender", func() {
+	var (
+		sender               v1.DigestSender
+		mailClient           *mocks.MailClient
+		database             *mocks.Database
+		conn                 *mocks.Connection
+		digestItemsRepo      *mocks.DigestItemsRepo
+		userSettingsRepo     *mocks.UserSettingsRepo
+		messageStatusUpdater *mocks.MessageStatusUpdater
+		cloak                *mocks.Cloak
+		now                  time.Time
+	)
+
+	digestItem := func(userGUID, messageID, subject string) models.DigestItem {
+		payload, err := json.Marshal(common.Delivery{
+			MessageID: messageID,
+			UserGUID:  userGUID,
+			Email:     userGUID + "@example.com",
+			ClientID:  "some-client",
+			Options: common.Options{
+				KindID:  "some-kind",
+				Subject: subject,
+				Text:    "the text of " + subject,
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		return models.DigestItem{
+			UserGUID:  userGUID,
+			Digest:    models.DigestDaily,
+			MessageID: messageID,
+			Delivery:  string(payload),
+		}
+	}
+
+	BeforeEach(func() {
+		mailClient = mocks.NewMailClient()
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		digestItemsRepo = mocks.NewDigestItemsRepo()
+		userSettingsRepo = mocks.NewUserSettingsRepo()
+		messageStatusUpdater = mocks.NewMessageStatusUpdater()
+		cloak = mocks.NewCloak()
+		cloak.VeilCall.Returns.CipherText = []byte("some-unsubscribe-id")
+		now = time.Date(2016, time.March, 4, 0, 0, 0, 0, time.UTC)
+
+		sender = v1.NewDigestSender(v1.DigestSenderConfig{
+			Sender: "from@example.com",
+			Domain: "example.com",
+			Templates: common.DigestTemplates{
+				Subject: "{{len .Items}} notifications",
+				Text:    "{{range .Items}}{{.Subject}};{{end}}",
+			},
+			PollingInterval: time.Minute,
+
+			Cloak:                cloak,
+			MailClient:           mailClient,
+			Database:             database,
+			DigestItemsRepo:      digestItemsRepo,
+			UserSettingsRepo:     userSettingsRepo,
+			MessageStatusUpdater: messageStatusUpdater,
+			Logger:               lager.NewLogger("notifications"),
+		})
+	})
+
+	Describe("Send", func() {
+		It("sends one message per recipient and digest", func() {
+			digestItemsRepo.FindDueCall.Returns.Items = []models.DigestItem{
+				digestItem("user-123", "message-1", "first"),
+				digestItem("user-123", "message-2", "second"),
+				digestItem("user-456", "message-3", "third"),
+			}
+
+			sender.Send(now)
+
+			Expect(digestItemsRepo.FindDueCall.Receives.Connection).To(Equal(conn))
+			Expect(digestItemsRepo.FindDueCall.Receives.Now).To(Equal(now))
+			Expect(mailClient.SendCall.CallCount).To(Equal(2))
+
+			message := mailClient.SendCall.Receives.Message
+			Expect(message.From).To(Equal("from@example.com"))
+			Expect(message.To).To(Equal("user-456@example.com"))
+			Expect(message.Subject).To(Equal("1 notifications"))
+			Expect(message.Body).To(ConsistOf(mail.Part{
+				ContentType: "text/plain",
+				Content:     "third;",
+			}))
+		})
+
+		It("marks the messages as delivered and removes the items", func() {
+			items := []models.DigestItem{digestItem("user-123", "message-1", "first")}
+			digestItemsRepo.FindDueCall.Returns.Items = items
+
+			sender.Send(now)
+
+			Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal("message-1"))
+			Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusDelivered))
+			Expect(digestItemsRepo.DeleteCall.Receives.Connection).To(Equal(conn))
+			Expect(digestItemsRepo.DeleteCall.Receives.Items).To(Equal(items))
+		})
+
+		Context("when the message cannot be sent", func() {
+			BeforeEach(func() {
+				digestItemsRepo.FindDueCall.Returns.Items = []models.DigestItem{digestItem("user-123", "message-1", "first")}
+				mailClient.SendCall.Returns.Error = errors.New("smtp is gone")
+			})
+
+			It("keeps the items for the next run", func() {
+				sender.Send(now)
+
+				Expect(digestItemsRepo.DeleteCall.WasCalled).To(BeFalse())
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(BeEmpty())
+			})
+		})
+
+		Context("when the recipient is in their quiet hours", func() {
+			var items []models.DigestItem
+
+			BeforeEach(func() {
+				now = time.Date(2016, time.March, 4, 4, 0, 0, 0, time.UTC)
+				items = []models.DigestItem{digestItem("user-123", "message-1", "first")}
+				digestItemsRepo.FindDueCall.Returns.Items = items
+				userSettingsRepo.FindCall.Returns.Settings = models.UserSettings{
+					UserID:          "user-123",
+					Timezone:        "America/New_York",
+					QuietHoursStart: "22:00",
+					QuietHoursEnd:   "07:00",
+				}
+			})
+
+			It("holds the digest until their quiet hours end", func() {
+				sender.Send(now)
+
+				Expect(userSettingsRepo.FindCall.Receives.Connection).To(Equal(conn))
+				Expect(userSettingsRepo.FindCall.Receives.UserID).To(Equal("user-123"))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(digestItemsRepo.PostponeCall.Receives.Items).To(Equal(items))
+				Expect(digestItemsRepo.PostponeCall.Receives.DueAt).To(BeTemporally("==", time.Date(2016, time.March, 4, 12, 0, 0, 0, time.UTC)))
+				Expect(digestItemsRepo.DeleteCall.WasCalled).To(BeFalse())
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(BeEmpty())
+			})
+
+			It("keeps the items when they cannot be postponed", func() {
+				digestItemsRepo.PostponeCall.Returns.Error = errors.New("database is gone")
+
+				sender.Send(now)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(digestItemsRepo.DeleteCall.WasCalled).To(BeFalse())
+			})
+
+			It("sends the digest once their quiet hours are over", func() {
+				sender.Send(now.Add(9 * time.Hour))
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+				Expect(digestItemsRepo.PostponeCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		It("sends the digest when the recipient's settings cannot be loaded", func() {
+			digestItemsRepo.FindDueCall.Returns.Items = []models.DigestItem{digestItem("user-123", "message-1", "first")}
+			userSettingsRepo.FindCall.Returns.Error = errors.New("database is gone")
+
+			sender.Send(now)
+
+			Expect(mailClient.SendCall.CallCount).To(Equal(1))
+		})
+
+		Context("when the digest template fails to execute", func() {
+			BeforeEach(func() {
+				digestItemsRepo.FindDueCall.Returns.Items = []models.DigestItem{digestItem("user-123", "message-1", "first")}
+				sender = v1.NewDigestSender(v1.DigestSenderConfig{
+					Templates: common.DigestTemplates{
+						Subject: "{{.Missing}}",
+					},
+					Cloak:                cloak,
+					MailClient:           mailClient,
+					Database:             database,
+					DigestItemsRepo:      digestItemsRepo,
+					UserSettingsRepo:     userSettingsRepo,
+					MessageStatusUpdater: messageStatusUpdater,
+					Logger:               lager.NewLogger("notifications"),
+				})
+			})
+
+			It("marks the messages as failed and removes the items", func() {
+				sender.Send(now)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(messageStatusUpdater.FailCall.Receives.MessageID).To(Equal("message-1"))
+				Expect(messageStatusUpdater.GiveUpCall.Receives.MessageID).To(Equal("message-1"))
+				Expect(digestItemsRepo.DeleteCall.WasCalled).To(BeTrue())
+			})
+		})
+
+		Context("when an item cannot be read", func() {
+			It("marks its message as failed", func() {
+				digestItemsRepo.FindDueCall.Returns.Items = []models.DigestItem{
+					{UserGUID: "user-123", Digest: models.DigestDaily, MessageID: "message-1", Delivery: "%%%"},
+				}
+
+				sender.Send(now)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(messageStatusUpdater.FailCall.Receives.MessageID).To(Equal("message-1"))
+				Expect(messageStatusUpdater.GiveUpCall.Receives.MessageID).To(Equal("message-1"))
+				Expect(digestItemsRepo.DeleteCall.WasCalled).To(BeTrue())
+			})
+
+			It("removes it even when the rest of the digest cannot be sent", func() {
+				badItem := models.DigestItem{UserGUID: "user-123", Digest: models.DigestDaily, MessageID: "message-1", Delivery: "%%%"}
+				digestItemsRepo.FindDueCall.Returns.Items = []models.DigestItem{
+					badItem,
+					digestItem("user-123", "message-2", "second"),
+				}
+				mailClient.SendCall.Returns.Error = errors.New("mail server is gone")
+
+				sender.Send(now)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+				Expect(messageStatusUpdater.GiveUpCall.Receives.MessageID).To(Equal("message-1"))
+				Expect(digestItemsRepo.DeleteCall.Receives.Items).To(Equal([]models.DigestItem{badItem}))
+			})
+		})
+
+		Context("when an item has expired", func() {
+			expiringAt := func(item models.DigestItem, expiresAt time.Time) models.DigestItem {
+				var delivery common.Delivery
+				Expect(json.Unmarshal([]byte(item.Delivery), &delivery)).To(Succeed())
+				delivery.Options.ExpiresAt = expiresAt
+				payload, err := json.Marshal(delivery)
+				Expect(err).NotTo(HaveOccurred())
+				item.Delivery = string(payload)
+
+				return item
+			}
+
+			It("marks its message as expired and leaves it out of the digest", func() {
+				digestItemsRepo.FindDueCall.Returns.Items = []models.DigestItem{
+					expiringAt(digestItem("user-123", "message-1", "first"), now.Add(-1*time.Minute)),
+				}
+
+				sender.Send(now)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal("message-1"))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusExpired))
+				Expect(digestItemsRepo.DeleteCall.WasCalled).To(BeTrue())
+			})
+
+			It("judges the expiry by the time the digest is sent for", func() {
+				digestItemsRepo.FindDueCall.Returns.Items = []models.DigestItem{
+					expiringAt(digestItem("user-123", "message-1", "first"), now.Add(1*time.Minute)),
+				}
+
+				sender.Send(now)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusDelivered))
+			})
+		})
+
+		Context("when the due items cannot be loaded", func() {
+			It("sends nothing", func() {
+				digestItemsRepo.FindDueCall.Returns.Error = errors.New("database is gone")
+
+				sender.Send(now)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(digestItemsRepo.DeleteCall.WasCalled).To(BeFalse())
+			})
+		})
+	})
+})
@@ -1,33 +1,195 @@
 package v1
 
 import (
+	"time"
+	"unicode/utf8"
+
 	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/pivotal-golang/lager"
 )
 
+const maxMessageErrorLength = 1024
+
 type MessageStatusUpdater struct {
 	messagesRepo MessageUpserter
+	batchesRepo  BatchCounter
 }
 
 type MessageUpserter interface {
+	FindByID(conn models.ConnectionInterface, messageID string) (models.Message, error)
 	Upsert(conn models.ConnectionInterface, message models.Message) (models.Message, error)
+	SetTemplate(conn models.ConnectionInterface, messageID, templateID string, templateVersion int) error
+	SetEmail(conn models.ConnectionInterface, messageID, email string) error
+}
+
+type BatchCounter interface {
+	Transition(conn models.ConnectionInterface, id, from, to string, at time.Time) error
 }
 
-func NewMessageStatusUpdater(messagesRepo MessageUpserter) MessageStatusUpdater {
+func NewMessageStatusUpdater(messagesRepo MessageUpserter, batchesRepo BatchCounter) MessageStatusUpdater {
 	return MessageStatusUpdater{
 		messagesRepo: messagesRepo,
+		batchesRepo:  batchesRepo,
 	}
 }
 
 func (mu MessageStatusUpdater) Update(conn db.ConnectionInterface, messageID, messageStatus, campaignID string, logger lager.Logger) {
-	_, err := mu.messagesRepo.Upsert(conn, models.Message{
-		ID:         messageID,
-		Status:     messageStatus,
-	})
+	mu.upsert(conn, models.Message{
+		ID:     messageID,
+		Status: messageStatus,
+	}, logger)
+}
+
+// Fail marks the message as failed and records the reason so that it can be
+// reported back through the messages API.
+func (mu MessageStatusUpdater) Fail(conn db.ConnectionInterface, messageID string, failure error, logger lager.Logger) {
+	reason := failure.Error()
+	if len(reason) > maxMessageErrorLength {
+		end := maxMessageErrorLength
+		for end > 0 && !utf8.RuneStart(reason[end]) {
+			end--
+		}
+		reason = reason[:end]
+	}
+
+	mu.upsert(conn, models.Message{
+		ID:     messageID,
+		Status: common.StatusFailed,
+		Error:  reason,
+	}, logger)
+}
+
+// SetTemplate records which version of which template rendered the message.
+func (mu MessageStatusUpdater) SetTemplate(conn db.ConnectionInterface, messageID, templateID string, templateVersion int, logger lager.Logger) {
+	err := mu.messagesRepo.SetTemplate(conn, messageID, templateID, templateVersion)
+	if err != nil {
+		logger.Session("message-updater").Error("failed-message-template-update", err, lager.Data{
+			"template_id":      templateID,
+			"template_version": templateVersion,
+		})
+	}
+}
+
+// SetEmail records the address the message is sent to once the recipient
+// has been resolved, so that the message can be searched by email.
+func (mu MessageStatusUpdater) SetEmail(conn db.ConnectionInterface, messageID, email string, logger lager.Logger) {
+	err := mu.messagesRepo.SetEmail(conn, messageID, email)
+	if err != nil {
+		logger.Session("message-updater").Error("failed-message-email-update", err)
+	}
+}
+
+// GiveUp records that the message will not be retried again. Until then a
+// failed message is waiting to be retried, and its batch keeps counting it
+// as queued; from now on the batch counts it as failed. Giving up on a
+// message more than once has no further effect.
+func (mu MessageStatusUpdater) GiveUp(conn db.ConnectionInterface, messageID string, logger lager.Logger) {
+	transaction := conn.Transaction()
+	err := transaction.Begin()
+	if err != nil {
+		logger.Session("message-updater").Error("failed-transaction-begin", err)
+		return
+	}
+
+	message, err := mu.messagesRepo.FindByID(transaction, messageID)
+	if err != nil {
+		logger.Session("message-updater").Error("failed-message-find", err)
+		transaction.Rollback()
+		return
+	}
+
+	if message.GivenUp || batchStatus(message) != common.StatusQueued {
+		transaction.Rollback()
+		return
+	}
+
+	failed := models.Message{
+		ID:      message.ID,
+		Status:  common.StatusFailed,
+		Error:   message.Error,
+		GivenUp: true,
+	}
+
+	if !mu.record(transaction, failed, message.BatchID, common.StatusQueued, common.StatusFailed, logger) {
+		transaction.Rollback()
+		return
+	}
+
+	mu.commit(transaction, logger)
+}
+
+// upsert records the status of the message and moves it between the
+// counters of the batch it was queued in, within one transaction so that
+// the counters always agree with the messages.
+func (mu MessageStatusUpdater) upsert(conn db.ConnectionInterface, message models.Message, logger lager.Logger) {
+	transaction := conn.Transaction()
+	err := transaction.Begin()
+	if err != nil {
+		logger.Session("message-updater").Error("failed-transaction-begin", err)
+		return
+	}
+
+	previous, err := mu.messagesRepo.FindByID(transaction, message.ID)
+	switch err.(type) {
+	case nil, models.NotFoundError:
+	default:
+		logger.Session("message-updater").Error("failed-message-find", err)
+		transaction.Rollback()
+		return
+	}
+
+	message.GivenUp = previous.GivenUp
+	if !mu.record(transaction, message, previous.BatchID, batchStatus(previous), batchStatus(message), logger) {
+		transaction.Rollback()
+		return
+	}
+
+	mu.commit(transaction, logger)
+}
+
+// record saves the message and moves it between the given counters of its
+// batch. It reports whether both succeeded.
+func (mu MessageStatusUpdater) record(transaction db.TransactionInterface, message models.Message, batchID, from, to string, logger lager.Logger) bool {
+	_, err := mu.messagesRepo.Upsert(transaction, message)
 	if err != nil {
 		logger.Session("message-updater").Error("failed-message-status-upsert", err, lager.Data{
-			"status": messageStatus,
+			"status": message.Status,
+		})
+		return false
+	}
+
+	if batchID == "" || from == to {
+		return true
+	}
+
+	err = mu.batchesRepo.Transition(transaction, batchID, from, to, time.Now())
+	if err != nil {
+		logger.Session("message-updater").Error("failed-batch-counters-update", err, lager.Data{
+			"batch_id": batchID,
+			"status":   to,
 		})
+		return false
+	}
+
+	return true
+}
+
+func (mu MessageStatusUpdater) commit(transaction db.TransactionInterface, logger lager.Logger) {
+	err := transaction.Commit()
+	if err != nil {
+		logger.Session("message-updater").Error("failed-transaction-commit", err)
 	}
 }
+
+// batchStatus is the status a batch counts a message under. A failed
+// message is waiting to be retried until it is given up on, so it is still
+// counted as queued.
+func batchStatus(message models.Message) string {
+	if message.Status == common.StatusFailed && !message.GivenUp {
+		return common.StatusQueued
+	}
+
+	return message.Status
+}
@@ -3,7 +3,11 @@ package v1_test
 import (
 	"bytes"
 	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/postal/v1"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -17,13 +21,17 @@ var _ = Describe("MessageStatusUpdater", func() {
 	var (
 		updater      v1.MessageStatusUpdater
 		messagesRepo *mocks.MessagesRepo
+		batchesRepo  *mocks.BatchesRepo
 		logger       lager.Logger
 		buffer       *bytes.Buffer
 		conn         *mocks.Connection
+		transaction  *mocks.Transaction
 	)
 
 	BeforeEach(func() {
 		conn = mocks.NewConnection()
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
 		messagesRepo = mocks.NewMessagesRepo()
 		messagesRepo.UpsertCall.Returns.Messages = []models.Message{
 			{
@@ -36,17 +44,265 @@ var _ = Describe("MessageStatusUpdater", func() {
 		logger = lager.NewLogger("notifications")
 		logger.RegisterSink(lager.NewWriterSink(buffer, lager.INFO))
 
-		updater = v1.NewMessageStatusUpdater(messagesRepo)
+		batchesRepo = mocks.NewBatchesRepo()
+
+		updater = v1.NewMessageStatusUpdater(messagesRepo, batchesRepo)
 	})
 
 	It("updates the status of the message", func() {
 		updater.Update(conn, "some-message-id", "message-status", "campaign-id", logger)
 
-		Expect(messagesRepo.UpsertCall.Receives.Connection).To(Equal(conn))
+		Expect(messagesRepo.UpsertCall.Receives.Connection).To(Equal(transaction))
 		Expect(messagesRepo.UpsertCall.Receives.Messages[0]).To(Equal(models.Message{
 			ID:     "some-message-id",
 			Status: "message-status",
 		}))
+		Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+		Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+	})
+
+	Describe("batch counters", func() {
+		BeforeEach(func() {
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{
+				ID:      "some-message-id",
+				Status:  "queued",
+				BatchID: "some-batch-id",
+			}
+		})
+
+		It("moves the message between the counters of its batch", func() {
+			before := time.Now()
+
+			updater.Update(conn, "some-message-id", common.StatusDelivered, "", logger)
+
+			Expect(messagesRepo.FindByIDCall.Receives.Connection).To(Equal(transaction))
+			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(Equal("some-message-id"))
+			Expect(batchesRepo.TransitionCall.Receives.Connection).To(Equal(transaction))
+			Expect(batchesRepo.TransitionCall.Receives.ID).To(Equal("some-batch-id"))
+			Expect(batchesRepo.TransitionCall.Receives.From).To(Equal(common.StatusQueued))
+			Expect(batchesRepo.TransitionCall.Receives.To).To(Equal(common.StatusDelivered))
+			Expect(batchesRepo.TransitionCall.Receives.At).To(BeTemporally(">=", before))
+		})
+
+		It("keeps counting failed messages as queued while they wait to be retried", func() {
+			updater.Fail(conn, "some-message-id", errors.New("BOOM!"), logger)
+
+			Expect(batchesRepo.TransitionCall.WasCalled).To(BeFalse())
+		})
+
+		It("counts a retry that succeeds as sent", func() {
+			messagesRepo.FindByIDCall.Returns.Message.Status = common.StatusFailed
+
+			updater.Update(conn, "some-message-id", common.StatusDelivered, "", logger)
+
+			Expect(batchesRepo.TransitionCall.Receives.From).To(Equal(common.StatusQueued))
+			Expect(batchesRepo.TransitionCall.Receives.To).To(Equal(common.StatusDelivered))
+		})
+
+		It("keeps counting a message that was given up on as failed", func() {
+			messagesRepo.FindByIDCall.Returns.Message.Status = common.StatusFailed
+			messagesRepo.FindByIDCall.Returns.Message.GivenUp = true
+
+			updater.Fail(conn, "some-message-id", errors.New("BOOM!"), logger)
+
+			Expect(messagesRepo.UpsertCall.Receives.Messages[0].GivenUp).To(BeTrue())
+			Expect(batchesRepo.TransitionCall.WasCalled).To(BeFalse())
+		})
+
+		It("counts a message that was given up on and then sent as sent", func() {
+			messagesRepo.FindByIDCall.Returns.Message.Status = common.StatusFailed
+			messagesRepo.FindByIDCall.Returns.Message.GivenUp = true
+
+			updater.Update(conn, "some-message-id", common.StatusDelivered, "", logger)
+
+			Expect(batchesRepo.TransitionCall.Receives.From).To(Equal(common.StatusFailed))
+			Expect(batchesRepo.TransitionCall.Receives.To).To(Equal(common.StatusDelivered))
+		})
+
+		It("leaves the counters alone when the status does not change", func() {
+			updater.Update(conn, "some-message-id", common.StatusQueued, "", logger)
+
+			Expect(batchesRepo.TransitionCall.WasCalled).To(BeFalse())
+		})
+
+		It("leaves the counters alone for messages queued outside of a batch", func() {
+			messagesRepo.FindByIDCall.Returns.Message.BatchID = ""
+
+			updater.Update(conn, "some-message-id", common.StatusDelivered, "", logger)
+
+			Expect(batchesRepo.TransitionCall.WasCalled).To(BeFalse())
+		})
+
+		It("leaves the counters alone when the status cannot be recorded", func() {
+			messagesRepo.UpsertCall.Returns.Error = errors.New("failed to upsert")
+
+			updater.Update(conn, "some-message-id", common.StatusDelivered, "", logger)
+
+			Expect(batchesRepo.TransitionCall.WasCalled).To(BeFalse())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("records nothing when the message cannot be found", func() {
+			messagesRepo.FindByIDCall.Returns.Error = errors.New("failed to find")
+
+			updater.Update(conn, "some-message-id", common.StatusDelivered, "", logger)
+
+			Expect(messagesRepo.UpsertCall.Receives.Messages).To(BeEmpty())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		})
+
+		It("logs the error when the counters cannot be updated", func() {
+			batchesRepo.TransitionCall.Returns.Error = errors.New("failed to count")
+
+			updater.Update(conn, "some-message-id", common.StatusDelivered, "", logger)
+
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+
+			lines, err := parseLogLines(buffer.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lines).To(HaveLen(1))
+			Expect(lines[0].Message).To(Equal("notifications.message-updater.failed-batch-counters-update"))
+			Expect(lines[0].Data["batch_id"]).To(Equal("some-batch-id"))
+		})
+	})
+
+	Describe("SetTemplate", func() {
+		It("records the template version on the message", func() {
+			updater.SetTemplate(conn, "some-message-id", "some-template-id", 4, logger)
+
+			Expect(messagesRepo.SetTemplateCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.SetTemplateCall.Receives.MessageID).To(Equal("some-message-id"))
+			Expect(messagesRepo.SetTemplateCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(messagesRepo.SetTemplateCall.Receives.TemplateVersion).To(Equal(4))
+		})
+
+		It("logs the error when the repository fails", func() {
+			messagesRepo.SetTemplateCall.Returns.Error = errors.New("failed to update")
+
+			updater.SetTemplate(conn, "some-message-id", "some-template-id", 4, logger)
+
+			lines, err := parseLogLines(buffer.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lines).To(HaveLen(1))
+			Expect(lines[0].Message).To(Equal("notifications.message-updater.failed-message-template-update"))
+		})
+	})
+
+	Describe("SetEmail", func() {
+		It("records the email address on the message", func() {
+			updater.SetEmail(conn, "some-message-id", "user@example.com", logger)
+
+			Expect(messagesRepo.SetEmailCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.SetEmailCall.Receives.MessageID).To(Equal("some-message-id"))
+			Expect(messagesRepo.SetEmailCall.Receives.Email).To(Equal("user@example.com"))
+		})
+
+		It("logs the error when the repository fails", func() {
+			messagesRepo.SetEmailCall.Returns.Error = errors.New("failed to update")
+
+			updater.SetEmail(conn, "some-message-id", "user@example.com", logger)
+
+			lines, err := parseLogLines(buffer.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lines).To(HaveLen(1))
+			Expect(lines[0].Message).To(Equal("notifications.message-updater.failed-message-email-update"))
+		})
+	})
+
+	Describe("GiveUp", func() {
+		BeforeEach(func() {
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{
+				ID:      "some-message-id",
+				Status:  common.StatusFailed,
+				Error:   "BOOM!",
+				BatchID: "some-batch-id",
+			}
+		})
+
+		It("marks the message as failed and counts it as failed in its batch", func() {
+			updater.GiveUp(conn, "some-message-id", logger)
+
+			Expect(messagesRepo.FindByIDCall.Receives.Connection).To(Equal(transaction))
+			Expect(messagesRepo.UpsertCall.Receives.Connection).To(Equal(transaction))
+			Expect(messagesRepo.UpsertCall.Receives.Messages[0]).To(Equal(models.Message{
+				ID:      "some-message-id",
+				Status:  common.StatusFailed,
+				Error:   "BOOM!",
+				GivenUp: true,
+			}))
+			Expect(batchesRepo.TransitionCall.Receives.Connection).To(Equal(transaction))
+			Expect(batchesRepo.TransitionCall.Receives.ID).To(Equal("some-batch-id"))
+			Expect(batchesRepo.TransitionCall.Receives.From).To(Equal(common.StatusQueued))
+			Expect(batchesRepo.TransitionCall.Receives.To).To(Equal(common.StatusFailed))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("gives up on messages that are still queued", func() {
+			messagesRepo.FindByIDCall.Returns.Message.Status = common.StatusQueued
+
+			updater.GiveUp(conn, "some-message-id", logger)
+
+			Expect(batchesRepo.TransitionCall.Receives.From).To(Equal(common.StatusQueued))
+			Expect(batchesRepo.TransitionCall.Receives.To).To(Equal(common.StatusFailed))
+		})
+
+		It("leaves messages that were already settled alone", func() {
+			messagesRepo.FindByIDCall.Returns.Message.Status = common.StatusDelivered
+
+			updater.GiveUp(conn, "some-message-id", logger)
+
+			Expect(messagesRepo.UpsertCall.Receives.Messages).To(BeEmpty())
+			Expect(batchesRepo.TransitionCall.WasCalled).To(BeFalse())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		})
+
+		It("leaves messages that were already given up on alone", func() {
+			messagesRepo.FindByIDCall.Returns.Message.GivenUp = true
+
+			updater.GiveUp(conn, "some-message-id", logger)
+
+			Expect(messagesRepo.UpsertCall.Receives.Messages).To(BeEmpty())
+			Expect(batchesRepo.TransitionCall.WasCalled).To(BeFalse())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		})
+
+		It("rolls back when the counters cannot be updated", func() {
+			batchesRepo.TransitionCall.Returns.Error = errors.New("failed to count")
+
+			updater.GiveUp(conn, "some-message-id", logger)
+
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Describe("Fail", func() {
+		It("marks the message as failed and records the error", func() {
+			updater.Fail(conn, "some-message-id", errors.New("template: compileTemplate:1:2: executing failed"), logger)
+
+			Expect(messagesRepo.UpsertCall.Receives.Connection).To(Equal(transaction))
+			Expect(messagesRepo.UpsertCall.Receives.Messages[0]).To(Equal(models.Message{
+				ID:     "some-message-id",
+				Status: common.StatusFailed,
+				Error:  "template: compileTemplate:1:2: executing failed",
+			}))
+		})
+
+		It("truncates long errors to fit the column", func() {
+			updater.Fail(conn, "some-message-id", errors.New(strings.Repeat("x", 2000)), logger)
+
+			Expect(messagesRepo.UpsertCall.Receives.Messages[0].Error).To(HaveLen(1024))
+		})
+
+		It("truncates long errors without splitting a character", func() {
+			updater.Fail(conn, "some-message-id", errors.New("x"+strings.Repeat("é", 1000)), logger)
+
+			reason := messagesRepo.UpsertCall.Receives.Messages[0].Error
+			Expect(reason).To(HaveLen(1023))
+			Expect(utf8.ValidString(reason)).To(BeTrue())
+		})
 	})
 
 	Context("failure cases", func() {
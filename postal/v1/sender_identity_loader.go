@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type senderIdentityFinder interface {
+	Find(connection models.ConnectionInterface, clientID, kindID string) (models.SenderIdentity, error)
+}
+
+type SenderIdentityLoader struct {
+	database             db.DatabaseInterface
+	senderIdentitiesRepo senderIdentityFinder
+}
+
+func NewSenderIdentityLoader(database db.DatabaseInterface, senderIdentitiesRepo senderIdentityFinder) SenderIdentityLoader {
+	return SenderIdentityLoader{
+		database:             database,
+		senderIdentitiesRepo: senderIdentitiesRepo,
+	}
+}
+
+func (loader SenderIdentityLoader) Load(clientID, kindID string) (models.SenderIdentity, error) {
+	conn := loader.database.Connection()
+
+	if kindID != "" {
+		identity, err := loader.senderIdentitiesRepo.Find(conn, clientID, kindID)
+		if _, ok := err.(models.NotFoundError); !ok {
+			return identity, err
+		}
+	}
+
+	return loader.senderIdentitiesRepo.Find(conn, clientID, "")
+}
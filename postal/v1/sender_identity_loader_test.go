@@ -0,0 +1,64 @@
+package v1_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/v1"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SenderIdentityLoader", func() {
+	var (
+		loader               v1.SenderIdentityLoader
+		senderIdentitiesRepo *mocks.SenderIdentitiesRepo
+		conn                 *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		senderIdentitiesRepo = mocks.NewSenderIdentitiesRepo()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		loader = v1.NewSenderIdentityLoader(database, senderIdentitiesRepo)
+	})
+
+	It("loads the sender identity of the kind", func() {
+		senderIdentitiesRepo.FindCall.Returns.SenderIdentity = models.SenderIdentity{
+			ClientID: "my-client",
+			KindID:   "my-kind",
+			Address:  "kind@example.com",
+		}
+
+		identity, err := loader.Load("my-client", "my-kind")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(identity.Address).To(Equal("kind@example.com"))
+
+		Expect(senderIdentitiesRepo.FindCall.Receives.Connection).To(Equal(conn))
+		Expect(senderIdentitiesRepo.FindCall.Receives.ClientID).To(Equal("my-client"))
+		Expect(senderIdentitiesRepo.FindCall.Receives.KindID).To(Equal("my-kind"))
+	})
+
+	It("falls back to the sender identity of the client", func() {
+		senderIdentitiesRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+		_, err := loader.Load("my-client", "my-kind")
+		Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+
+		Expect(senderIdentitiesRepo.FindCall.Receives.ClientID).To(Equal("my-client"))
+		Expect(senderIdentitiesRepo.FindCall.Receives.KindID).To(Equal(""))
+	})
+
+	It("returns other errors without falling back", func() {
+		senderIdentitiesRepo.FindCall.Returns.Error = errors.New("database is down")
+
+		_, err := loader.Load("my-client", "my-kind")
+		Expect(err).To(MatchError(errors.New("database is down")))
+		Expect(senderIdentitiesRepo.FindCall.Receives.KindID).To(Equal("my-kind"))
+	})
+})
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/locale"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
@@ -18,24 +19,36 @@ type templateFinder interface {
 	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
 }
 
+type templateLocaleFinder interface {
+	Find(connection models.ConnectionInterface, templateID, locale string) (models.TemplateLocale, error)
+}
+
+type templatePartialsLister interface {
+	List(connection models.ConnectionInterface) ([]models.TemplatePartial, error)
+}
+
 type TemplatesLoader struct {
 	database db.DatabaseInterface
 
-	clientsRepo   clientFinder
-	kindsRepo     kindFinder
-	templatesRepo templateFinder
+	clientsRepo         clientFinder
+	kindsRepo           kindFinder
+	templatesRepo       templateFinder
+	templateLocalesRepo templateLocaleFinder
+	partialsRepo        templatePartialsLister
 }
 
-func NewTemplatesLoader(database db.DatabaseInterface, clientsRepo clientFinder, kindsRepo kindFinder, templatesRepo templateFinder) TemplatesLoader {
+func NewTemplatesLoader(database db.DatabaseInterface, clientsRepo clientFinder, kindsRepo kindFinder, templatesRepo templateFinder, templateLocalesRepo templateLocaleFinder, partialsRepo templatePartialsLister) TemplatesLoader {
 	return TemplatesLoader{
-		database:      database,
-		clientsRepo:   clientsRepo,
-		kindsRepo:     kindsRepo,
-		templatesRepo: templatesRepo,
+		database:            database,
+		clientsRepo:         clientsRepo,
+		kindsRepo:           kindsRepo,
+		templatesRepo:       templatesRepo,
+		templateLocalesRepo: templateLocalesRepo,
+		partialsRepo:        partialsRepo,
 	}
 }
 
-func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string) (common.Templates, error) {
+func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID, recipientLocale string) (common.Templates, error) {
 	conn := loader.database.Connection()
 
 	if kindID != "" {
@@ -45,7 +58,7 @@ func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string)
 		}
 
 		if kind.TemplateID != models.DefaultTemplateID {
-			return loader.loadTemplate(conn, kind.TemplateID)
+			return loader.loadTemplate(conn, kind.TemplateID, recipientLocale)
 		}
 	}
 
@@ -54,18 +67,82 @@ func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string)
 		return common.Templates{}, err
 	}
 
-	return loader.loadTemplate(conn, client.TemplateID)
+	return loader.loadTemplate(conn, client.TemplateID, recipientLocale)
 }
 
-func (loader TemplatesLoader) loadTemplate(conn db.ConnectionInterface, templateID string) (common.Templates, error) {
+func (loader TemplatesLoader) loadTemplate(conn db.ConnectionInterface, templateID, recipientLocale string) (common.Templates, error) {
 	template, err := loader.templatesRepo.FindByID(conn, templateID)
 	if err != nil {
 		return common.Templates{}, err
 	}
 
-	return common.Templates{
-		Subject: template.Subject,
-		Text:    template.Text,
-		HTML:    template.HTML,
-	}, nil
+	templates := common.Templates{
+		ID:       template.ID,
+		Version:  template.Version,
+		Subject:  template.Subject,
+		Text:     template.Text,
+		HTML:     template.HTML,
+		Markdown: template.Markdown,
+		Layout:   template.Layout,
+
+		InlineCSS:          template.InlinesCSS(),
+		LegacyHTMLEscaping: template.UsesLegacyHTMLEscaping(),
+	}
+
+	templates, err = loader.localize(conn, templates, recipientLocale)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	return loader.resolvePartials(conn, templates)
+}
+
+// resolvePartials wraps the templates into their layout and defines the
+// partials they include. Partials are resolved after localization so that
+// locale variants can use them as well.
+func (loader TemplatesLoader) resolvePartials(conn db.ConnectionInterface, templates common.Templates) (common.Templates, error) {
+	if !common.UsesPartials(templates) {
+		return templates, nil
+	}
+
+	partials, err := loader.partialsRepo.List(conn)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	byName := map[string]common.Partial{}
+	for _, partial := range partials {
+		byName[partial.Name] = common.Partial{
+			Name: partial.Name,
+			Text: partial.Text,
+			HTML: partial.HTML,
+		}
+	}
+
+	return common.ResolvePartials(templates, byName)
+}
+
+// localize applies the most specific locale variant of the template along the
+// recipient's fallback chain.
+func (loader TemplatesLoader) localize(conn db.ConnectionInterface, templates common.Templates, recipientLocale string) (common.Templates, error) {
+	for _, candidate := range locale.Fallbacks(recipientLocale) {
+		variant, err := loader.templateLocalesRepo.Find(conn, templates.ID, candidate)
+		if err != nil {
+			if _, ok := err.(models.NotFoundError); ok {
+				continue
+			}
+
+			return common.Templates{}, err
+		}
+
+		return common.Localize(templates, common.Templates{
+			Locale:   variant.Locale,
+			Subject:  variant.Subject,
+			Text:     variant.Text,
+			HTML:     variant.HTML,
+			Markdown: variant.Markdown,
+		}), nil
+	}
+
+	return templates, nil
 }
@@ -19,6 +19,8 @@ var _ = Describe("TemplateLoader", func() {
 		clientsRepo   *mocks.ClientsRepository
 		kindsRepo     *mocks.KindsRepo
 		templatesRepo *mocks.TemplatesRepo
+		localesRepo   *mocks.TemplateLocalesRepo
+		partialsRepo  *mocks.TemplatePartialsRepo
 		conn          db.ConnectionInterface
 		database      *mocks.Database
 	)
@@ -27,12 +29,14 @@ var _ = Describe("TemplateLoader", func() {
 		clientsRepo = mocks.NewClientsRepository()
 		kindsRepo = mocks.NewKindsRepo()
 		templatesRepo = mocks.NewTemplatesRepo()
+		localesRepo = mocks.NewTemplateLocalesRepo()
+		partialsRepo = mocks.NewTemplatePartialsRepo()
 
 		conn = mocks.NewConnection()
 		database = mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
-		loader = v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo)
+		loader = v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo, localesRepo, partialsRepo)
 	})
 
 	Describe("LoadTemplates", func() {
@@ -62,11 +66,14 @@ var _ = Describe("TemplateLoader", func() {
 		Context("when the kind has a template", func() {
 			BeforeEach(func() {
 				templatesRepo.FindByIDCall.Returns.Template = models.Template{
-					ID:      "my-kind-template",
-					Name:    "my-kind-template",
-					HTML:    "<p>kind template</p>",
-					Text:    "some kind template text",
-					Subject: "kind subject",
+					ID:       "my-kind-template",
+					Name:     "my-kind-template",
+					HTML:     "<p>kind template</p>",
+					Text:     "some kind template text",
+					Markdown: "some *kind* template",
+					Subject:  "kind subject",
+					Metadata: `{"inline_css": true, "legacy_html_escaping": true}`,
+					Version:  3,
 				}
 
 				kindsRepo.FindCall.Returns.Kinds = []models.Kind{
@@ -79,12 +86,18 @@ var _ = Describe("TemplateLoader", func() {
 			})
 
 			It("returns the template belonging to the kind", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
-					HTML:    "<p>kind template</p>",
-					Text:    "some kind template text",
-					Subject: "kind subject",
+					ID:       "my-kind-template",
+					Version:  3,
+					HTML:     "<p>kind template</p>",
+					Text:     "some kind template text",
+					Markdown: "some *kind* template",
+					Subject:  "kind subject",
+
+					InlineCSS:          true,
+					LegacyHTMLEscaping: true,
 				}))
 
 				Expect(templatesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
@@ -109,9 +122,10 @@ var _ = Describe("TemplateLoader", func() {
 			})
 
 			It("returns the template belonging to the client", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
+					ID:      "my-client-template",
 					HTML:    "<p>client template</p>",
 					Text:    "some client template text",
 					Subject: "client subject",
@@ -124,9 +138,10 @@ var _ = Describe("TemplateLoader", func() {
 
 		Context("when the neither client nor kind has a template", func() {
 			It("returns the default template", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
+					ID:      models.DefaultTemplateID,
 					HTML:    "<p>The default template</p>",
 					Text:    "The default template",
 					Subject: "default subject",
@@ -136,13 +151,167 @@ var _ = Describe("TemplateLoader", func() {
 
 		Context("when kindID is an empty string", func() {
 			It("does not look for a template belonging to the kind", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "", "")
+				templates, err := loader.LoadTemplates("my-client-id", "", "", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates).To(Equal(common.Templates{
+					ID:      models.DefaultTemplateID,
+					HTML:    "<p>The default template</p>",
+					Text:    "The default template",
+					Subject: "default subject",
+				}))
+			})
+		})
+
+		Context("when a recipient locale is given", func() {
+			It("applies the most specific locale variant in the fallback chain", func() {
+				localesRepo.FindCall.Returns.Variants = []models.TemplateLocale{
+					{
+						TemplateID: models.DefaultTemplateID,
+						Locale:     "de",
+						Subject:    "Betreff",
+						Text:       "Die Standardvorlage",
+					},
+				}
+
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "de-CH")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates).To(Equal(common.Templates{
+					ID:      models.DefaultTemplateID,
+					Locale:  "de",
+					HTML:    "<p>The default template</p>",
+					Text:    "Die Standardvorlage",
+					Subject: "Betreff",
+				}))
+
+				Expect(localesRepo.FindCall.Receives.Connection).To(Equal(conn))
+				Expect(localesRepo.FindCall.Receives.TemplateID).To(Equal(models.DefaultTemplateID))
+				Expect(localesRepo.FindCall.Receives.Locales).To(Equal([]string{"de-CH", "de"}))
+			})
+
+			It("applies the Markdown of a locale variant", func() {
+				localesRepo.FindCall.Returns.Variants = []models.TemplateLocale{
+					{
+						TemplateID: models.DefaultTemplateID,
+						Locale:     "fr",
+						Markdown:   "# Le modèle",
+					},
+				}
+
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "fr")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates.Markdown).To(Equal("# Le modèle"))
+				Expect(templates.Text).To(Equal("The default template"))
+			})
+
+			It("replaces a Markdown body with the HTML of a locale variant", func() {
+				templatesRepo.FindByIDCall.Returns.Template.Markdown = "# The default template"
+				localesRepo.FindCall.Returns.Variants = []models.TemplateLocale{
+					{
+						TemplateID: models.DefaultTemplateID,
+						Locale:     "fr",
+						HTML:       "<p>Le modèle</p>",
+					},
+				}
+
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "fr")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates).To(Equal(common.Templates{
+					ID:      models.DefaultTemplateID,
+					Locale:  "fr",
+					HTML:    "<p>Le modèle</p>",
+					Subject: "default subject",
+				}))
+			})
+
+			It("falls back to the default template when no variant matches", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "fr-CA")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
+					ID:      models.DefaultTemplateID,
 					HTML:    "<p>The default template</p>",
 					Text:    "The default template",
 					Subject: "default subject",
 				}))
+
+				Expect(localesRepo.FindCall.Receives.Locales).To(Equal([]string{"fr-CA", "fr"}))
+			})
+
+			It("bubbles up errors from the locales repo", func() {
+				localesRepo.FindCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "de")
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
+		})
+
+		Context("when no recipient locale is given", func() {
+			It("does not look for locale variants", func() {
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(localesRepo.FindCall.Receives.Locales).To(BeEmpty())
+			})
+		})
+
+		Context("when the template uses partials", func() {
+			BeforeEach(func() {
+				templatesRepo.FindByIDCall.Returns.Template = models.Template{
+					ID:      models.DefaultTemplateID,
+					Layout:  "layout",
+					HTML:    "<p>The default template</p>",
+					Text:    `The default template {{template "footer"}}`,
+					Subject: "default subject",
+				}
+
+				partialsRepo.ListCall.Returns.Partials = []models.TemplatePartial{
+					{Name: "layout", Text: `{{template "content" .}}`, HTML: `<main>{{template "content" .}}</main>`},
+					{Name: "footer", Text: "-- The Team"},
+				}
+			})
+
+			It("wraps the template into its layout and defines the partials it includes", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates.Layout).To(Equal("layout"))
+				Expect(templates.Text).To(Equal(`The default template {{template "footer"}}{{define "footer"}}-- The Team{{end}}{{define "layout"}}{{template "content" .}}{{end}}`))
+				Expect(templates.HTML).To(Equal(`<p>The default template</p>{{define "layout"}}<main>{{template "content" .}}</main>{{end}}`))
+
+				Expect(partialsRepo.ListCall.Receives.Connection).To(Equal(conn))
+			})
+
+			It("resolves the partials of locale variants", func() {
+				localesRepo.FindCall.Returns.Variants = []models.TemplateLocale{
+					{
+						TemplateID: models.DefaultTemplateID,
+						Locale:     "de",
+						Text:       "Die Standardvorlage",
+					},
+				}
+
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "de")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates.Text).To(Equal(`Die Standardvorlage{{define "layout"}}{{template "content" .}}{{end}}`))
+			})
+
+			It("returns an error when a partial does not exist", func() {
+				partialsRepo.ListCall.Returns.Partials = partialsRepo.ListCall.Returns.Partials[:1]
+
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).To(MatchError(common.PartialError{Err: errors.New(`partial "footer" does not exist`)}))
+			})
+
+			It("bubbles up errors from the partials repo", func() {
+				partialsRepo.ListCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
+		})
+
+		Context("when the template does not use partials", func() {
+			It("does not load them", func() {
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(partialsRepo.ListCall.CallCount).To(Equal(0))
 			})
 		})
 
@@ -150,7 +319,7 @@ var _ = Describe("TemplateLoader", func() {
 			It("bubbles up the error", func() {
 				kindsRepo.FindCall.Returns.Error = errors.New("BOOM!")
 
-				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
 				Expect(err).To(HaveOccurred())
 			})
 
@@ -160,7 +329,7 @@ var _ = Describe("TemplateLoader", func() {
 			It("bubbles up the error", func() {
 				clientsRepo.FindCall.Returns.Error = errors.New("BOOM!")
 
-				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -0,0 +1,116 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+type AudienceStore struct {
+	CreateCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			ClientID   string
+			Audience   services.Audience
+		}
+		Returns struct {
+			Audience services.Audience
+			Error    error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection services.ConnectionInterface
+			ClientID   string
+			ID         string
+		}
+		Returns struct {
+			Audience services.Audience
+			Error    error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection services.ConnectionInterface
+			ClientID   string
+			Page       models.Page
+		}
+		Returns struct {
+			Audiences []services.Audience
+			Next      []string
+			Error     error
+		}
+	}
+
+	UpdateCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			ClientID   string
+			Audience   services.Audience
+		}
+		Returns struct {
+			Audience services.Audience
+			Error    error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection services.ConnectionInterface
+			ClientID   string
+			ID         string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewAudienceStore() *AudienceStore {
+	return &AudienceStore{}
+}
+
+func (s *AudienceStore) Create(conn services.ConnectionInterface, clientID string, audience services.Audience) (services.Audience, error) {
+	s.CreateCall.WasCalled = true
+	s.CreateCall.Receives.Connection = conn
+	s.CreateCall.Receives.ClientID = clientID
+	s.CreateCall.Receives.Audience = audience
+
+	return s.CreateCall.Returns.Audience, s.CreateCall.Returns.Error
+}
+
+func (s *AudienceStore) Get(conn services.ConnectionInterface, clientID, id string) (services.Audience, error) {
+	s.GetCall.Receives.Connection = conn
+	s.GetCall.Receives.ClientID = clientID
+	s.GetCall.Receives.ID = id
+
+	return s.GetCall.Returns.Audience, s.GetCall.Returns.Error
+}
+
+func (s *AudienceStore) List(conn services.ConnectionInterface, clientID string, page models.Page) ([]services.Audience, []string, error) {
+	s.ListCall.Receives.Connection = conn
+	s.ListCall.Receives.ClientID = clientID
+	s.ListCall.Receives.Page = page
+
+	return s.ListCall.Returns.Audiences, s.ListCall.Returns.Next, s.ListCall.Returns.Error
+}
+
+func (s *AudienceStore) Update(conn services.ConnectionInterface, clientID string, audience services.Audience) (services.Audience, error) {
+	s.UpdateCall.WasCalled = true
+	s.UpdateCall.Receives.Connection = conn
+	s.UpdateCall.Receives.ClientID = clientID
+	s.UpdateCall.Receives.Audience = audience
+
+	return s.UpdateCall.Returns.Audience, s.UpdateCall.Returns.Error
+}
+
+func (s *AudienceStore) Delete(conn services.ConnectionInterface, clientID, id string) error {
+	s.DeleteCall.Receives.Connection = conn
+	s.DeleteCall.Receives.ClientID = clientID
+	s.DeleteCall.Receives.ID = id
+
+	return s.DeleteCall.Returns.Error
+}
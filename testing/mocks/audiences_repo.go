@@ -0,0 +1,104 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type AudiencesRepo struct {
+	CreateCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Audience   models.Audience
+		}
+		Returns struct {
+			Audience models.Audience
+			Error    error
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+			ID         string
+		}
+		Returns struct {
+			Audience models.Audience
+			Error    error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+			Page       models.Page
+		}
+		Returns struct {
+			Audiences []models.Audience
+			Error     error
+		}
+	}
+
+	UpdateCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Audience   models.Audience
+		}
+		Returns struct {
+			Audience models.Audience
+			Error    error
+		}
+	}
+
+	DestroyCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+			ID         string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewAudiencesRepo() *AudiencesRepo {
+	return &AudiencesRepo{}
+}
+
+func (r *AudiencesRepo) Create(conn models.ConnectionInterface, audience models.Audience) (models.Audience, error) {
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Audience = audience
+
+	return r.CreateCall.Returns.Audience, r.CreateCall.Returns.Error
+}
+
+func (r *AudiencesRepo) Find(conn models.ConnectionInterface, clientID, id string) (models.Audience, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.ClientID = clientID
+	r.FindCall.Receives.ID = id
+
+	return r.FindCall.Returns.Audience, r.FindCall.Returns.Error
+}
+
+func (r *AudiencesRepo) List(conn models.ConnectionInterface, clientID string, page models.Page) ([]models.Audience, error) {
+	r.ListCall.Receives.Connection = conn
+	r.ListCall.Receives.ClientID = clientID
+	r.ListCall.Receives.Page = page
+
+	return r.ListCall.Returns.Audiences, r.ListCall.Returns.Error
+}
+
+func (r *AudiencesRepo) Update(conn models.ConnectionInterface, audience models.Audience) (models.Audience, error) {
+	r.UpdateCall.Receives.Connection = conn
+	r.UpdateCall.Receives.Audience = audience
+
+	return r.UpdateCall.Returns.Audience, r.UpdateCall.Returns.Error
+}
+
+func (r *AudiencesRepo) Destroy(conn models.ConnectionInterface, clientID, id string) error {
+	r.DestroyCall.Receives.Connection = conn
+	r.DestroyCall.Receives.ClientID = clientID
+	r.DestroyCall.Receives.ID = id
+
+	return r.DestroyCall.Returns.Error
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type BatchDispatcher struct {
+	DispatchCall struct {
+		WasCalled bool
+		Receives  struct {
+			Dispatch services.Dispatch
+			Targets  []services.BatchTarget
+		}
+		Returns struct {
+			Result services.BatchResult
+			Error  error
+		}
+	}
+
+	ResolveCall struct {
+		WasCalled bool
+		Receives  struct {
+			Dispatch services.Dispatch
+			Targets  []services.BatchTarget
+		}
+		Returns struct {
+			Resolution    services.Resolution
+			TargetResults []services.BatchTargetResult
+		}
+	}
+}
+
+func NewBatchDispatcher() *BatchDispatcher {
+	return &BatchDispatcher{}
+}
+
+func (d *BatchDispatcher) Dispatch(dispatch services.Dispatch, targets []services.BatchTarget) (services.BatchResult, error) {
+	d.DispatchCall.WasCalled = true
+	d.DispatchCall.Receives.Dispatch = dispatch
+	d.DispatchCall.Receives.Targets = targets
+
+	return d.DispatchCall.Returns.Result, d.DispatchCall.Returns.Error
+}
+
+func (d *BatchDispatcher) Resolve(dispatch services.Dispatch, targets []services.BatchTarget) (services.Resolution, []services.BatchTargetResult) {
+	d.ResolveCall.WasCalled = true
+	d.ResolveCall.Receives.Dispatch = dispatch
+	d.ResolveCall.Receives.Targets = targets
+
+	return d.ResolveCall.Returns.Resolution, d.ResolveCall.Returns.TargetResults
+}
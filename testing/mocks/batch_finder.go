@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+type BatchFinder struct {
+	FindCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			BatchID  string
+		}
+		Returns struct {
+			Batch services.Batch
+			Error error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			Filter   models.BatchesFilter
+			Page     models.Page
+		}
+		Returns struct {
+			Batches []services.Batch
+			Next    []string
+			Error   error
+		}
+	}
+}
+
+func NewBatchFinder() *BatchFinder {
+	return &BatchFinder{}
+}
+
+func (f *BatchFinder) Find(database services.DatabaseInterface, batchID string) (services.Batch, error) {
+	f.FindCall.Receives.Database = database
+	f.FindCall.Receives.BatchID = batchID
+
+	return f.FindCall.Returns.Batch, f.FindCall.Returns.Error
+}
+
+func (f *BatchFinder) List(database services.DatabaseInterface, filter models.BatchesFilter, page models.Page) ([]services.Batch, []string, error) {
+	f.ListCall.Receives.Database = database
+	f.ListCall.Receives.Filter = filter
+	f.ListCall.Receives.Page = page
+
+	return f.ListCall.Returns.Batches, f.ListCall.Returns.Next, f.ListCall.Returns.Error
+}
@@ -0,0 +1,115 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type BatchesRepo struct {
+	CreateCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Batch      models.Batch
+		}
+		Returns struct {
+			Batch models.Batch
+			Error error
+		}
+	}
+
+	UpdateCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Batch      models.Batch
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ID         string
+		}
+		Returns struct {
+			Batch models.Batch
+			Error error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Filter     models.BatchesFilter
+			Page       models.Page
+		}
+		Returns struct {
+			Batches []models.Batch
+			Error   error
+		}
+	}
+
+	TransitionCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			ID         string
+			From       string
+			To         string
+			At         time.Time
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewBatchesRepo() *BatchesRepo {
+	return &BatchesRepo{}
+}
+
+func (r *BatchesRepo) Create(conn models.ConnectionInterface, batch models.Batch) (models.Batch, error) {
+	r.CreateCall.WasCalled = true
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Batch = batch
+
+	return r.CreateCall.Returns.Batch, r.CreateCall.Returns.Error
+}
+
+func (r *BatchesRepo) Update(conn models.ConnectionInterface, batch models.Batch) (models.Batch, error) {
+	r.UpdateCall.WasCalled = true
+	r.UpdateCall.Receives.Connection = conn
+	r.UpdateCall.Receives.Batch = batch
+
+	return batch, r.UpdateCall.Returns.Error
+}
+
+func (r *BatchesRepo) Find(conn models.ConnectionInterface, id string) (models.Batch, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.ID = id
+
+	return r.FindCall.Returns.Batch, r.FindCall.Returns.Error
+}
+
+func (r *BatchesRepo) List(conn models.ConnectionInterface, filter models.BatchesFilter, page models.Page) ([]models.Batch, error) {
+	r.ListCall.Receives.Connection = conn
+	r.ListCall.Receives.Filter = filter
+	r.ListCall.Receives.Page = page
+
+	return r.ListCall.Returns.Batches, r.ListCall.Returns.Error
+}
+
+func (r *BatchesRepo) Transition(conn models.ConnectionInterface, id, from, to string, at time.Time) error {
+	r.TransitionCall.WasCalled = true
+	r.TransitionCall.Receives.Connection = conn
+	r.TransitionCall.Receives.ID = id
+	r.TransitionCall.Receives.From = from
+	r.TransitionCall.Receives.To = to
+	r.TransitionCall.Receives.At = at
+
+	return r.TransitionCall.Returns.Error
+}
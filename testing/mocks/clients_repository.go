@@ -14,9 +14,23 @@ type ClientsRepository struct {
 		}
 	}
 
-	FindAllCall struct {
+	ListCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
+			Filter     models.ClientsFilter
+			Page       models.Page
+		}
+		Returns struct {
+			Clients []models.Client
+			Error   error
+		}
+	}
+
+	ListWithoutKindsCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Page       models.Page
 		}
 		Returns struct {
 			Clients []models.Client
@@ -69,12 +83,6 @@ func (cr *ClientsRepository) Find(conn models.ConnectionInterface, clientID stri
 	return cr.FindCall.Returns.Client, cr.FindCall.Returns.Error
 }
 
-func (cr *ClientsRepository) FindAll(conn models.ConnectionInterface) ([]models.Client, error) {
-	cr.FindAllCall.Receives.Connection = conn
-
-	return cr.FindAllCall.Returns.Clients, cr.FindAllCall.Returns.Error
-}
-
 func (cr *ClientsRepository) FindAllByTemplateID(conn models.ConnectionInterface, templateID string) ([]models.Client, error) {
 	cr.FindAllByTemplateIDCall.Receives.Connection = conn
 	cr.FindAllByTemplateIDCall.Receives.TemplateID = templateID
@@ -95,3 +103,19 @@ func (cr *ClientsRepository) Upsert(conn models.ConnectionInterface, client mode
 
 	return cr.UpsertCall.Returns.Client, cr.UpsertCall.Returns.Error
 }
+
+func (cr *ClientsRepository) List(conn models.ConnectionInterface, filter models.ClientsFilter, page models.Page) ([]models.Client, error) {
+	cr.ListCall.Receives.Connection = conn
+	cr.ListCall.Receives.Filter = filter
+	cr.ListCall.Receives.Page = page
+
+	return cr.ListCall.Returns.Clients, cr.ListCall.Returns.Error
+}
+
+func (cr *ClientsRepository) ListWithoutKinds(conn models.ConnectionInterface, page models.Page) ([]models.Client, error) {
+	cr.ListWithoutKindsCall.WasCalled = true
+	cr.ListWithoutKindsCall.Receives.Connection = conn
+	cr.ListWithoutKindsCall.Receives.Page = page
+
+	return cr.ListWithoutKindsCall.Returns.Clients, cr.ListWithoutKindsCall.Returns.Error
+}
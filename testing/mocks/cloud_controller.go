@@ -14,6 +14,39 @@ type CloudController struct {
 		}
 	}
 
+	GetAuditorsBySpaceGuidCall struct {
+		Receives struct {
+			SpaceGUID string
+			Token     string
+		}
+		Returns struct {
+			Users []cf.CloudControllerUser
+			Error error
+		}
+	}
+
+	GetDevelopersBySpaceGuidCall struct {
+		Receives struct {
+			SpaceGUID string
+			Token     string
+		}
+		Returns struct {
+			Users []cf.CloudControllerUser
+			Error error
+		}
+	}
+
+	GetManagersBySpaceGuidCall struct {
+		Receives struct {
+			SpaceGUID string
+			Token     string
+		}
+		Returns struct {
+			Users []cf.CloudControllerUser
+			Error error
+		}
+	}
+
 	GetBillingManagersByOrgGuidCall struct {
 		Receives struct {
 			OrgGUID string
@@ -92,6 +125,27 @@ func (cc *CloudController) GetAuditorsByOrgGuid(orgGUID, token string) ([]cf.Clo
 	return cc.GetAuditorsByOrgGuidCall.Returns.Users, cc.GetAuditorsByOrgGuidCall.Returns.Error
 }
 
+func (cc *CloudController) GetAuditorsBySpaceGuid(spaceGUID, token string) ([]cf.CloudControllerUser, error) {
+	cc.GetAuditorsBySpaceGuidCall.Receives.SpaceGUID = spaceGUID
+	cc.GetAuditorsBySpaceGuidCall.Receives.Token = token
+
+	return cc.GetAuditorsBySpaceGuidCall.Returns.Users, cc.GetAuditorsBySpaceGuidCall.Returns.Error
+}
+
+func (cc *CloudController) GetDevelopersBySpaceGuid(spaceGUID, token string) ([]cf.CloudControllerUser, error) {
+	cc.GetDevelopersBySpaceGuidCall.Receives.SpaceGUID = spaceGUID
+	cc.GetDevelopersBySpaceGuidCall.Receives.Token = token
+
+	return cc.GetDevelopersBySpaceGuidCall.Returns.Users, cc.GetDevelopersBySpaceGuidCall.Returns.Error
+}
+
+func (cc *CloudController) GetManagersBySpaceGuid(spaceGUID, token string) ([]cf.CloudControllerUser, error) {
+	cc.GetManagersBySpaceGuidCall.Receives.SpaceGUID = spaceGUID
+	cc.GetManagersBySpaceGuidCall.Receives.Token = token
+
+	return cc.GetManagersBySpaceGuidCall.Returns.Users, cc.GetManagersBySpaceGuidCall.Returns.Error
+}
+
 func (cc *CloudController) GetBillingManagersByOrgGuid(orgGUID, token string) ([]cf.CloudControllerUser, error) {
 	cc.GetBillingManagersByOrgGuidCall.Receives.OrgGUID = orgGUID
 	cc.GetBillingManagersByOrgGuidCall.Receives.Token = token
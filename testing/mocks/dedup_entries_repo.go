@@ -0,0 +1,75 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type DedupEntriesRepo struct {
+	ClaimCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Entry      models.DedupEntry
+			Now        time.Time
+		}
+		Returns struct {
+			Entry   models.DedupEntry
+			Claimed bool
+			Error   error
+		}
+	}
+
+	SetMessageIDCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Entries    []models.DedupEntry
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	DeleteExpiredCall struct {
+		CallCount int
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Now        time.Time
+		}
+		Returns struct {
+			Count int
+			Error error
+		}
+	}
+}
+
+func NewDedupEntriesRepo() *DedupEntriesRepo {
+	return &DedupEntriesRepo{}
+}
+
+func (r *DedupEntriesRepo) Claim(conn models.ConnectionInterface, entry models.DedupEntry, now time.Time) (models.DedupEntry, bool, error) {
+	r.ClaimCall.WasCalled = true
+	r.ClaimCall.Receives.Connection = conn
+	r.ClaimCall.Receives.Entry = entry
+	r.ClaimCall.Receives.Now = now
+
+	return r.ClaimCall.Returns.Entry, r.ClaimCall.Returns.Claimed, r.ClaimCall.Returns.Error
+}
+
+func (r *DedupEntriesRepo) SetMessageID(conn models.ConnectionInterface, entry models.DedupEntry) error {
+	r.SetMessageIDCall.WasCalled = true
+	r.SetMessageIDCall.Receives.Connection = conn
+	r.SetMessageIDCall.Receives.Entries = append(r.SetMessageIDCall.Receives.Entries, entry)
+
+	return r.SetMessageIDCall.Returns.Error
+}
+
+func (r *DedupEntriesRepo) DeleteExpired(conn models.ConnectionInterface, now time.Time) (int, error) {
+	r.DeleteExpiredCall.CallCount++
+	r.DeleteExpiredCall.Receives.Connection = conn
+	r.DeleteExpiredCall.Receives.Now = now
+
+	return r.DeleteExpiredCall.Returns.Count, r.DeleteExpiredCall.Returns.Error
+}
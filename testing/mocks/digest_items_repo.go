@@ -0,0 +1,91 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type DigestItemsRepo struct {
+	CreateCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Item       models.DigestItem
+		}
+		Returns struct {
+			Item  models.DigestItem
+			Error error
+		}
+	}
+
+	FindDueCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Now        time.Time
+		}
+		Returns struct {
+			Items []models.DigestItem
+			Error error
+		}
+	}
+
+	PostponeCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Items      []models.DigestItem
+			DueAt      time.Time
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	DeleteCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Items      []models.DigestItem
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewDigestItemsRepo() *DigestItemsRepo {
+	return &DigestItemsRepo{}
+}
+
+func (r *DigestItemsRepo) Create(conn models.ConnectionInterface, item models.DigestItem) (models.DigestItem, error) {
+	r.CreateCall.WasCalled = true
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Item = item
+
+	return r.CreateCall.Returns.Item, r.CreateCall.Returns.Error
+}
+
+func (r *DigestItemsRepo) FindDue(conn models.ConnectionInterface, now time.Time) ([]models.DigestItem, error) {
+	r.FindDueCall.Receives.Connection = conn
+	r.FindDueCall.Receives.Now = now
+
+	return r.FindDueCall.Returns.Items, r.FindDueCall.Returns.Error
+}
+
+func (r *DigestItemsRepo) Postpone(conn models.ConnectionInterface, items []models.DigestItem, dueAt time.Time) error {
+	r.PostponeCall.WasCalled = true
+	r.PostponeCall.Receives.Connection = conn
+	r.PostponeCall.Receives.Items = items
+	r.PostponeCall.Receives.DueAt = dueAt
+
+	return r.PostponeCall.Returns.Error
+}
+
+func (r *DigestItemsRepo) Delete(conn models.ConnectionInterface, items []models.DigestItem) error {
+	r.DeleteCall.WasCalled = true
+	r.DeleteCall.Receives.Connection = conn
+	r.DeleteCall.Receives.Items = items
+
+	return r.DeleteCall.Returns.Error
+}
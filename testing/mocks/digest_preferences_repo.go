@@ -0,0 +1,56 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type DigestPreferencesRepo struct {
+	GetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			UserID     string
+			ClientID   string
+			KindID     string
+		}
+		Returns struct {
+			Digest string
+			Error  error
+		}
+	}
+
+	SetCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			UserID     string
+			ClientID   string
+			KindID     string
+			Digest     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewDigestPreferencesRepo() *DigestPreferencesRepo {
+	return &DigestPreferencesRepo{}
+}
+
+func (r *DigestPreferencesRepo) Get(conn models.ConnectionInterface, userID, clientID, kindID string) (string, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.UserID = userID
+	r.GetCall.Receives.ClientID = clientID
+	r.GetCall.Receives.KindID = kindID
+
+	return r.GetCall.Returns.Digest, r.GetCall.Returns.Error
+}
+
+func (r *DigestPreferencesRepo) Set(conn models.ConnectionInterface, userID, clientID, kindID, digest string) error {
+	r.SetCall.WasCalled = true
+	r.SetCall.Receives.Connection = conn
+	r.SetCall.Receives.UserID = userID
+	r.SetCall.Receives.ClientID = clientID
+	r.SetCall.Receives.KindID = kindID
+	r.SetCall.Receives.Digest = digest
+
+	return r.SetCall.Returns.Error
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 )
 
@@ -27,6 +28,19 @@ type Enqueuer struct {
 			Err       error
 		}
 	}
+
+	EnqueueDeliveriesCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			Batch      models.Batch
+			Deliveries []services.Delivery
+		}
+		Returns struct {
+			Responses []services.Response
+			Error     error
+		}
+	}
 }
 
 func NewEnqueuer() *Enqueuer {
@@ -59,3 +73,12 @@ func (m *Enqueuer) Enqueue(
 	m.EnqueueCall.WasCalled = true
 	return m.EnqueueCall.Returns.Responses, m.EnqueueCall.Returns.Err
 }
+
+func (m *Enqueuer) EnqueueDeliveries(conn services.ConnectionInterface, batch models.Batch, deliveries []services.Delivery) ([]services.Response, error) {
+	m.EnqueueDeliveriesCall.WasCalled = true
+	m.EnqueueDeliveriesCall.Receives.Connection = conn
+	m.EnqueueDeliveriesCall.Receives.Batch = batch
+	m.EnqueueDeliveriesCall.Receives.Deliveries = deliveries
+
+	return m.EnqueueDeliveriesCall.Returns.Responses, m.EnqueueDeliveriesCall.Returns.Error
+}
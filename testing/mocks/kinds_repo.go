@@ -26,6 +26,18 @@ type KindsRepo struct {
 		}
 	}
 
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Filter     models.KindsFilter
+			Page       models.Page
+		}
+		Returns struct {
+			Kinds []models.Kind
+			Error error
+		}
+	}
+
 	FindAllByTemplateIDCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
@@ -121,3 +133,11 @@ func (kr *KindsRepo) Upsert(conn models.ConnectionInterface, kind models.Kind) (
 
 	return kr.UpsertCall.Returns.Kind, kr.UpsertCall.Returns.Error
 }
+
+func (kr *KindsRepo) List(conn models.ConnectionInterface, filter models.KindsFilter, page models.Page) ([]models.Kind, error) {
+	kr.ListCall.Receives.Connection = conn
+	kr.ListCall.Receives.Filter = filter
+	kr.ListCall.Receives.Page = page
+
+	return kr.ListCall.Returns.Kinds, kr.ListCall.Returns.Error
+}
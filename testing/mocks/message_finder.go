@@ -1,6 +1,9 @@
 package mocks
 
-import "github.com/cloudfoundry-incubator/notifications/v1/services"
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
 
 type MessageFinder struct {
 	FindCall struct {
@@ -13,6 +16,19 @@ type MessageFinder struct {
 			Error   error
 		}
 	}
+
+	ListCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			Filter   models.MessagesFilter
+			Page     models.Page
+		}
+		Returns struct {
+			Messages []services.Message
+			Next     []string
+			Error    error
+		}
+	}
 }
 
 func NewMessageFinder() *MessageFinder {
@@ -25,3 +41,11 @@ func (f *MessageFinder) Find(database services.DatabaseInterface, messageID stri
 
 	return f.FindCall.Returns.Message, f.FindCall.Returns.Error
 }
+
+func (f *MessageFinder) List(database services.DatabaseInterface, filter models.MessagesFilter, page models.Page) ([]services.Message, []string, error) {
+	f.ListCall.Receives.Database = database
+	f.ListCall.Receives.Filter = filter
+	f.ListCall.Receives.Page = page
+
+	return f.ListCall.Returns.Messages, f.ListCall.Returns.Next, f.ListCall.Returns.Error
+}
@@ -15,6 +15,45 @@ type MessageStatusUpdater struct {
 			Logger        lager.Logger
 		}
 	}
+
+	SetTemplateCall struct {
+		Receives struct {
+			Connection      db.ConnectionInterface
+			MessageID       string
+			TemplateID      string
+			TemplateVersion int
+			Logger          lager.Logger
+		}
+	}
+
+	SetEmailCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection db.ConnectionInterface
+			MessageID  string
+			Email      string
+			Logger     lager.Logger
+		}
+	}
+
+	GiveUpCall struct {
+		CallCount int
+		Receives  struct {
+			Connection db.ConnectionInterface
+			MessageID  string
+			Logger     lager.Logger
+		}
+	}
+
+	FailCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection db.ConnectionInterface
+			MessageID  string
+			Error      error
+			Logger     lager.Logger
+		}
+	}
 }
 
 func NewMessageStatusUpdater() *MessageStatusUpdater {
@@ -28,3 +67,34 @@ func (msu *MessageStatusUpdater) Update(conn db.ConnectionInterface, messageID,
 	msu.UpdateCall.Receives.CampaignID = campaignID
 	msu.UpdateCall.Receives.Logger = logger
 }
+
+func (msu *MessageStatusUpdater) Fail(conn db.ConnectionInterface, messageID string, err error, logger lager.Logger) {
+	msu.FailCall.WasCalled = true
+	msu.FailCall.Receives.Connection = conn
+	msu.FailCall.Receives.MessageID = messageID
+	msu.FailCall.Receives.Error = err
+	msu.FailCall.Receives.Logger = logger
+}
+
+func (msu *MessageStatusUpdater) SetTemplate(conn db.ConnectionInterface, messageID, templateID string, templateVersion int, logger lager.Logger) {
+	msu.SetTemplateCall.Receives.Connection = conn
+	msu.SetTemplateCall.Receives.MessageID = messageID
+	msu.SetTemplateCall.Receives.TemplateID = templateID
+	msu.SetTemplateCall.Receives.TemplateVersion = templateVersion
+	msu.SetTemplateCall.Receives.Logger = logger
+}
+
+func (msu *MessageStatusUpdater) SetEmail(conn db.ConnectionInterface, messageID, email string, logger lager.Logger) {
+	msu.SetEmailCall.WasCalled = true
+	msu.SetEmailCall.Receives.Connection = conn
+	msu.SetEmailCall.Receives.MessageID = messageID
+	msu.SetEmailCall.Receives.Email = email
+	msu.SetEmailCall.Receives.Logger = logger
+}
+
+func (msu *MessageStatusUpdater) GiveUp(conn db.ConnectionInterface, messageID string, logger lager.Logger) {
+	msu.GiveUpCall.CallCount++
+	msu.GiveUpCall.Receives.Connection = conn
+	msu.GiveUpCall.Receives.MessageID = messageID
+	msu.GiveUpCall.Receives.Logger = logger
+}
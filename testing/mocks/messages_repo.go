@@ -30,6 +30,41 @@ type MessagesRepo struct {
 		}
 	}
 
+	SetTemplateCall struct {
+		Receives struct {
+			Connection      models.ConnectionInterface
+			MessageID       string
+			TemplateID      string
+			TemplateVersion int
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	SetEmailCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			MessageID  string
+			Email      string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Filter     models.MessagesFilter
+			Page       models.Page
+		}
+		Returns struct {
+			Messages []models.Message
+			Error    error
+		}
+	}
+
 	FindByIDCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
@@ -83,6 +118,14 @@ func (mr *MessagesRepo) FindByID(conn models.ConnectionInterface, messageID stri
 	return mr.FindByIDCall.Returns.Message, mr.FindByIDCall.Returns.Error
 }
 
+func (mr *MessagesRepo) List(conn models.ConnectionInterface, filter models.MessagesFilter, page models.Page) ([]models.Message, error) {
+	mr.ListCall.Receives.Connection = conn
+	mr.ListCall.Receives.Filter = filter
+	mr.ListCall.Receives.Page = page
+
+	return mr.ListCall.Returns.Messages, mr.ListCall.Returns.Error
+}
+
 func (mr *MessagesRepo) DeleteBefore(conn models.ConnectionInterface, thresholdTime time.Time) (int, error) {
 	mr.DeleteBeforeCall.Receives.Connection = conn
 	mr.DeleteBeforeCall.Receives.ThresholdTime = thresholdTime
@@ -91,3 +134,20 @@ func (mr *MessagesRepo) DeleteBefore(conn models.ConnectionInterface, thresholdT
 
 	return mr.DeleteBeforeCall.Returns.RowsAffected, mr.DeleteBeforeCall.Returns.Error
 }
+
+func (mr *MessagesRepo) SetTemplate(conn models.ConnectionInterface, messageID, templateID string, templateVersion int) error {
+	mr.SetTemplateCall.Receives.Connection = conn
+	mr.SetTemplateCall.Receives.MessageID = messageID
+	mr.SetTemplateCall.Receives.TemplateID = templateID
+	mr.SetTemplateCall.Receives.TemplateVersion = templateVersion
+
+	return mr.SetTemplateCall.Returns.Error
+}
+
+func (mr *MessagesRepo) SetEmail(conn models.ConnectionInterface, messageID, email string) error {
+	mr.SetEmailCall.Receives.Connection = conn
+	mr.SetEmailCall.Receives.MessageID = messageID
+	mr.SetEmailCall.Receives.Email = email
+
+	return mr.SetEmailCall.Returns.Error
+}
@@ -6,13 +6,16 @@ import (
 )
 
 type NotificationsFinder struct {
-	AllClientsAndNotificationsCall struct {
+	ListClientsAndNotificationsCall struct {
 		Receives struct {
 			Database services.DatabaseInterface
+			Filter   models.KindsFilter
+			Page     models.Page
 		}
 		Returns struct {
 			Clients []models.Client
 			Kinds   []models.Kind
+			Next    []string
 			Error   error
 		}
 	}
@@ -35,10 +38,12 @@ func NewNotificationsFinder() *NotificationsFinder {
 	return &NotificationsFinder{}
 }
 
-func (f *NotificationsFinder) AllClientsAndNotifications(database services.DatabaseInterface) ([]models.Client, []models.Kind, error) {
-	f.AllClientsAndNotificationsCall.Receives.Database = database
+func (f *NotificationsFinder) ListClientsAndNotifications(database services.DatabaseInterface, filter models.KindsFilter, page models.Page) ([]models.Client, []models.Kind, []string, error) {
+	f.ListClientsAndNotificationsCall.Receives.Database = database
+	f.ListClientsAndNotificationsCall.Receives.Filter = filter
+	f.ListClientsAndNotificationsCall.Receives.Page = page
 
-	return f.AllClientsAndNotificationsCall.Returns.Clients, f.AllClientsAndNotificationsCall.Returns.Kinds, f.AllClientsAndNotificationsCall.Returns.Error
+	return f.ListClientsAndNotificationsCall.Returns.Clients, f.ListClientsAndNotificationsCall.Returns.Kinds, f.ListClientsAndNotificationsCall.Returns.Next, f.ListClientsAndNotificationsCall.Returns.Error
 }
 
 func (f *NotificationsFinder) ClientAndKind(database services.DatabaseInterface, clientID, kindID string) (models.Client, models.Kind, error) {
@@ -3,6 +3,8 @@ package mocks
 import (
 	"net/http"
 
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
 	"github.com/ryanmoran/stack"
 )
@@ -23,6 +25,86 @@ type Notify struct {
 			Error    error
 		}
 	}
+
+	DryRunCall struct {
+		Receives struct {
+			Connection    notify.ConnectionInterface
+			Request       *http.Request
+			Context       stack.Context
+			GUID          string
+			Strategy      notify.Dispatcher
+			Validator     notify.ValidatorInterface
+			VCAPRequestID string
+			Page          models.Page
+		}
+		Returns struct {
+			Response []byte
+			Next     []string
+			Error    error
+		}
+	}
+
+	ExecuteAudienceCall struct {
+		Receives struct {
+			Connection    notify.ConnectionInterface
+			Request       *http.Request
+			Context       stack.Context
+			Audience      services.Audience
+			Dispatcher    notify.BatchDispatcher
+			VCAPRequestID string
+		}
+		Returns struct {
+			Response []byte
+			Error    error
+		}
+	}
+
+	DryRunAudienceCall struct {
+		Receives struct {
+			Connection    notify.ConnectionInterface
+			Request       *http.Request
+			Context       stack.Context
+			Audience      services.Audience
+			Dispatcher    notify.BatchDispatcher
+			VCAPRequestID string
+			Page          models.Page
+		}
+		Returns struct {
+			Response []byte
+			Next     []string
+			Error    error
+		}
+	}
+
+	ExecuteBatchCall struct {
+		Receives struct {
+			Connection    notify.ConnectionInterface
+			Request       *http.Request
+			Context       stack.Context
+			Dispatcher    notify.BatchDispatcher
+			VCAPRequestID string
+		}
+		Returns struct {
+			Response []byte
+			Error    error
+		}
+	}
+
+	DryRunBatchCall struct {
+		Receives struct {
+			Connection    notify.ConnectionInterface
+			Request       *http.Request
+			Context       stack.Context
+			Dispatcher    notify.BatchDispatcher
+			VCAPRequestID string
+			Page          models.Page
+		}
+		Returns struct {
+			Response []byte
+			Next     []string
+			Error    error
+		}
+	}
 }
 
 func NewNotify() *Notify {
@@ -42,3 +124,70 @@ func (n *Notify) Execute(connection notify.ConnectionInterface, req *http.Reques
 
 	return n.ExecuteCall.Returns.Response, n.ExecuteCall.Returns.Error
 }
+
+func (n *Notify) DryRun(connection notify.ConnectionInterface, req *http.Request, context stack.Context,
+	guid string, strategy notify.Dispatcher, validator notify.ValidatorInterface, vcapRequestID string, page models.Page) ([]byte, []string, error) {
+
+	n.DryRunCall.Receives.Connection = connection
+	n.DryRunCall.Receives.Request = req
+	n.DryRunCall.Receives.Context = context
+	n.DryRunCall.Receives.GUID = guid
+	n.DryRunCall.Receives.Strategy = strategy
+	n.DryRunCall.Receives.Validator = validator
+	n.DryRunCall.Receives.VCAPRequestID = vcapRequestID
+	n.DryRunCall.Receives.Page = page
+
+	return n.DryRunCall.Returns.Response, n.DryRunCall.Returns.Next, n.DryRunCall.Returns.Error
+}
+
+func (n *Notify) ExecuteBatch(connection notify.ConnectionInterface, req *http.Request, context stack.Context,
+	dispatcher notify.BatchDispatcher, vcapRequestID string) ([]byte, error) {
+
+	n.ExecuteBatchCall.Receives.Connection = connection
+	n.ExecuteBatchCall.Receives.Request = req
+	n.ExecuteBatchCall.Receives.Context = context
+	n.ExecuteBatchCall.Receives.Dispatcher = dispatcher
+	n.ExecuteBatchCall.Receives.VCAPRequestID = vcapRequestID
+
+	return n.ExecuteBatchCall.Returns.Response, n.ExecuteBatchCall.Returns.Error
+}
+
+func (n *Notify) ExecuteAudience(connection notify.ConnectionInterface, req *http.Request, context stack.Context,
+	audience services.Audience, dispatcher notify.BatchDispatcher, vcapRequestID string) ([]byte, error) {
+
+	n.ExecuteAudienceCall.Receives.Connection = connection
+	n.ExecuteAudienceCall.Receives.Request = req
+	n.ExecuteAudienceCall.Receives.Context = context
+	n.ExecuteAudienceCall.Receives.Audience = audience
+	n.ExecuteAudienceCall.Receives.Dispatcher = dispatcher
+	n.ExecuteAudienceCall.Receives.VCAPRequestID = vcapRequestID
+
+	return n.ExecuteAudienceCall.Returns.Response, n.ExecuteAudienceCall.Returns.Error
+}
+
+func (n *Notify) DryRunBatch(connection notify.ConnectionInterface, req *http.Request, context stack.Context,
+	dispatcher notify.BatchDispatcher, vcapRequestID string, page models.Page) ([]byte, []string, error) {
+
+	n.DryRunBatchCall.Receives.Connection = connection
+	n.DryRunBatchCall.Receives.Request = req
+	n.DryRunBatchCall.Receives.Context = context
+	n.DryRunBatchCall.Receives.Dispatcher = dispatcher
+	n.DryRunBatchCall.Receives.VCAPRequestID = vcapRequestID
+	n.DryRunBatchCall.Receives.Page = page
+
+	return n.DryRunBatchCall.Returns.Response, n.DryRunBatchCall.Returns.Next, n.DryRunBatchCall.Returns.Error
+}
+
+func (n *Notify) DryRunAudience(connection notify.ConnectionInterface, req *http.Request, context stack.Context,
+	audience services.Audience, dispatcher notify.BatchDispatcher, vcapRequestID string, page models.Page) ([]byte, []string, error) {
+
+	n.DryRunAudienceCall.Receives.Connection = connection
+	n.DryRunAudienceCall.Receives.Request = req
+	n.DryRunAudienceCall.Receives.Context = context
+	n.DryRunAudienceCall.Receives.Audience = audience
+	n.DryRunAudienceCall.Receives.Dispatcher = dispatcher
+	n.DryRunAudienceCall.Receives.VCAPRequestID = vcapRequestID
+	n.DryRunAudienceCall.Receives.Page = page
+
+	return n.DryRunAudienceCall.Returns.Response, n.DryRunAudienceCall.Returns.Next, n.DryRunAudienceCall.Returns.Error
+}
@@ -17,6 +17,55 @@ type PreferenceUpdater struct {
 			Error error
 		}
 	}
+
+	SetLocaleCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			UserID     string
+			Locale     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	SetDigestCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			UserID     string
+			Digest     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	SetTimezoneCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			UserID     string
+			Timezone   string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	SetQuietHoursCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			UserID     string
+			Start      string
+			End        string
+		}
+		Returns struct {
+			Error error
+		}
+	}
 }
 
 func NewPreferenceUpdater() *PreferenceUpdater {
@@ -31,3 +80,40 @@ func (pu *PreferenceUpdater) Update(conn services.ConnectionInterface, preferenc
 
 	return pu.UpdateCall.Returns.Error
 }
+
+func (pu *PreferenceUpdater) SetLocale(conn services.ConnectionInterface, userID, locale string) error {
+	pu.SetLocaleCall.WasCalled = true
+	pu.SetLocaleCall.Receives.Connection = conn
+	pu.SetLocaleCall.Receives.UserID = userID
+	pu.SetLocaleCall.Receives.Locale = locale
+
+	return pu.SetLocaleCall.Returns.Error
+}
+
+func (pu *PreferenceUpdater) SetDigest(conn services.ConnectionInterface, userID, digest string) error {
+	pu.SetDigestCall.WasCalled = true
+	pu.SetDigestCall.Receives.Connection = conn
+	pu.SetDigestCall.Receives.UserID = userID
+	pu.SetDigestCall.Receives.Digest = digest
+
+	return pu.SetDigestCall.Returns.Error
+}
+
+func (pu *PreferenceUpdater) SetTimezone(conn services.ConnectionInterface, userID, timezone string) error {
+	pu.SetTimezoneCall.WasCalled = true
+	pu.SetTimezoneCall.Receives.Connection = conn
+	pu.SetTimezoneCall.Receives.UserID = userID
+	pu.SetTimezoneCall.Receives.Timezone = timezone
+
+	return pu.SetTimezoneCall.Returns.Error
+}
+
+func (pu *PreferenceUpdater) SetQuietHours(conn services.ConnectionInterface, userID, start, end string) error {
+	pu.SetQuietHoursCall.WasCalled = true
+	pu.SetQuietHoursCall.Receives.Connection = conn
+	pu.SetQuietHoursCall.Receives.UserID = userID
+	pu.SetQuietHoursCall.Receives.Start = start
+	pu.SetQuietHoursCall.Receives.End = end
+
+	return pu.SetQuietHoursCall.Returns.Error
+}
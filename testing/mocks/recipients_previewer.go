@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+type RecipientsPreviewer struct {
+	PreviewCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			Dispatch   services.Dispatch
+			Resolution services.Resolution
+			Page       models.Page
+		}
+		Returns struct {
+			Preview services.RecipientsPreview
+			Next    []string
+			Error   error
+		}
+	}
+}
+
+func NewRecipientsPreviewer() *RecipientsPreviewer {
+	return &RecipientsPreviewer{}
+}
+
+func (p *RecipientsPreviewer) Preview(conn services.ConnectionInterface, dispatch services.Dispatch, resolution services.Resolution, page models.Page) (services.RecipientsPreview, []string, error) {
+	p.PreviewCall.WasCalled = true
+	p.PreviewCall.Receives.Connection = conn
+	p.PreviewCall.Receives.Dispatch = dispatch
+	p.PreviewCall.Receives.Resolution = resolution
+	p.PreviewCall.Receives.Page = page
+
+	return p.PreviewCall.Returns.Preview, p.PreviewCall.Returns.Next, p.PreviewCall.Returns.Error
+}
@@ -0,0 +1,66 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type SenderIdentitiesRepo struct {
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+			KindID     string
+		}
+		Returns struct {
+			SenderIdentity models.SenderIdentity
+			Error          error
+		}
+	}
+
+	UpsertCall struct {
+		Receives struct {
+			Connection     models.ConnectionInterface
+			SenderIdentity models.SenderIdentity
+		}
+		Returns struct {
+			SenderIdentity models.SenderIdentity
+			Error          error
+		}
+	}
+
+	DestroyCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+			KindID     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewSenderIdentitiesRepo() *SenderIdentitiesRepo {
+	return &SenderIdentitiesRepo{}
+}
+
+func (r *SenderIdentitiesRepo) Find(conn models.ConnectionInterface, clientID, kindID string) (models.SenderIdentity, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.ClientID = clientID
+	r.FindCall.Receives.KindID = kindID
+
+	return r.FindCall.Returns.SenderIdentity, r.FindCall.Returns.Error
+}
+
+func (r *SenderIdentitiesRepo) Upsert(conn models.ConnectionInterface, identity models.SenderIdentity) (models.SenderIdentity, error) {
+	r.UpsertCall.Receives.Connection = conn
+	r.UpsertCall.Receives.SenderIdentity = identity
+
+	return r.UpsertCall.Returns.SenderIdentity, r.UpsertCall.Returns.Error
+}
+
+func (r *SenderIdentitiesRepo) Destroy(conn models.ConnectionInterface, clientID, kindID string) error {
+	r.DestroyCall.Receives.Connection = conn
+	r.DestroyCall.Receives.ClientID = clientID
+	r.DestroyCall.Receives.KindID = kindID
+
+	return r.DestroyCall.Returns.Error
+}
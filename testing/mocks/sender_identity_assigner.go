@@ -0,0 +1,85 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type SenderIdentityAssigner struct {
+	AssignToClientCall struct {
+		Receives struct {
+			Connection     collections.ConnectionInterface
+			ClientID       string
+			SenderIdentity collections.SenderIdentity
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	AssignToNotificationCall struct {
+		Receives struct {
+			Connection     collections.ConnectionInterface
+			ClientID       string
+			NotificationID string
+			SenderIdentity collections.SenderIdentity
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	UnassignFromClientCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ClientID   string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	UnassignFromNotificationCall struct {
+		Receives struct {
+			Connection     collections.ConnectionInterface
+			ClientID       string
+			NotificationID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewSenderIdentityAssigner() *SenderIdentityAssigner {
+	return &SenderIdentityAssigner{}
+}
+
+func (a *SenderIdentityAssigner) AssignToClient(connection collections.ConnectionInterface, clientID string, identity collections.SenderIdentity) error {
+	a.AssignToClientCall.Receives.Connection = connection
+	a.AssignToClientCall.Receives.ClientID = clientID
+	a.AssignToClientCall.Receives.SenderIdentity = identity
+
+	return a.AssignToClientCall.Returns.Error
+}
+
+func (a *SenderIdentityAssigner) AssignToNotification(connection collections.ConnectionInterface, clientID, notificationID string, identity collections.SenderIdentity) error {
+	a.AssignToNotificationCall.Receives.Connection = connection
+	a.AssignToNotificationCall.Receives.ClientID = clientID
+	a.AssignToNotificationCall.Receives.NotificationID = notificationID
+	a.AssignToNotificationCall.Receives.SenderIdentity = identity
+
+	return a.AssignToNotificationCall.Returns.Error
+}
+
+func (a *SenderIdentityAssigner) UnassignFromClient(connection collections.ConnectionInterface, clientID string) error {
+	a.UnassignFromClientCall.Receives.Connection = connection
+	a.UnassignFromClientCall.Receives.ClientID = clientID
+
+	return a.UnassignFromClientCall.Returns.Error
+}
+
+func (a *SenderIdentityAssigner) UnassignFromNotification(connection collections.ConnectionInterface, clientID, notificationID string) error {
+	a.UnassignFromNotificationCall.Receives.Connection = connection
+	a.UnassignFromNotificationCall.Receives.ClientID = clientID
+	a.UnassignFromNotificationCall.Receives.NotificationID = notificationID
+
+	return a.UnassignFromNotificationCall.Returns.Error
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type SenderIdentityLoader struct {
+	LoadCall struct {
+		Receives struct {
+			ClientID string
+			KindID   string
+		}
+		Returns struct {
+			SenderIdentity models.SenderIdentity
+			Error          error
+		}
+	}
+}
+
+func NewSenderIdentityLoader() *SenderIdentityLoader {
+	return &SenderIdentityLoader{}
+}
+
+func (l *SenderIdentityLoader) Load(clientID, kindID string) (models.SenderIdentity, error) {
+	l.LoadCall.Receives.ClientID = clientID
+	l.LoadCall.Receives.KindID = kindID
+
+	return l.LoadCall.Returns.SenderIdentity, l.LoadCall.Returns.Error
+}
@@ -5,6 +5,9 @@ import "github.com/cloudfoundry-incubator/notifications/v1/services"
 type Strategy struct {
 	DispatchCalls      []StrategyDispatchCall
 	DispatchCallsCount int
+
+	ResolveCalls      []StrategyResolveCall
+	ResolveCallsCount int
 }
 
 type StrategyDispatchCall struct {
@@ -17,6 +20,24 @@ type StrategyDispatchCall struct {
 	}
 }
 
+type StrategyResolveCall struct {
+	Receives struct {
+		Dispatch services.Dispatch
+	}
+	Returns struct {
+		Resolution services.Resolution
+		Error      error
+	}
+}
+
+func NewStrategyResolveCall(resolution services.Resolution, err error) StrategyResolveCall {
+	call := StrategyResolveCall{}
+	call.Returns.Resolution = resolution
+	call.Returns.Error = err
+
+	return call
+}
+
 func NewStrategyDispatchCall(responses []services.Response, err error) StrategyDispatchCall {
 	call := StrategyDispatchCall{}
 	call.Returns.Responses = responses
@@ -40,3 +61,15 @@ func (s *Strategy) Dispatch(dispatch services.Dispatch) ([]services.Response, er
 
 	return call.Returns.Responses, call.Returns.Error
 }
+
+func (s *Strategy) Resolve(dispatch services.Dispatch) (services.Resolution, error) {
+	if len(s.ResolveCalls) <= s.ResolveCallsCount {
+		s.ResolveCalls = append(s.ResolveCalls, StrategyResolveCall{})
+	}
+
+	call := s.ResolveCalls[s.ResolveCallsCount]
+	s.ResolveCalls[s.ResolveCallsCount].Receives.Dispatch = dispatch
+	s.ResolveCallsCount++
+
+	return call.Returns.Resolution, call.Returns.Error
+}
@@ -1,15 +1,20 @@
 package mocks
 
-import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
 
 type TemplateAssociationLister struct {
 	ListCall struct {
 		Receives struct {
 			Connection collections.ConnectionInterface
 			TemplateID string
+			Page       models.Page
 		}
 		Returns struct {
 			Associations []collections.TemplateAssociation
+			Next         []string
 			Error        error
 		}
 	}
@@ -19,9 +24,10 @@ func NewTemplateAssociationLister() *TemplateAssociationLister {
 	return &TemplateAssociationLister{}
 }
 
-func (l *TemplateAssociationLister) ListAssociations(connection collections.ConnectionInterface, templateID string) ([]collections.TemplateAssociation, error) {
+func (l *TemplateAssociationLister) ListAssociations(connection collections.ConnectionInterface, templateID string, page models.Page) ([]collections.TemplateAssociation, []string, error) {
 	l.ListCall.Receives.Connection = connection
 	l.ListCall.Receives.TemplateID = templateID
+	l.ListCall.Receives.Page = page
 
-	return l.ListCall.Returns.Associations, l.ListCall.Returns.Error
+	return l.ListCall.Returns.Associations, l.ListCall.Returns.Next, l.ListCall.Returns.Error
 }
@@ -0,0 +1,49 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TemplateBundlesCollection struct {
+	ExportCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+		}
+		Returns struct {
+			Bundle collections.TemplateBundle
+			Error  error
+		}
+	}
+
+	ImportCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection collections.ConnectionInterface
+			Bundle     collections.TemplateBundle
+			Options    collections.TemplateImportOptions
+			ClientID   string
+		}
+		Returns struct {
+			Result collections.TemplateImportResult
+			Error  error
+		}
+	}
+}
+
+func NewTemplateBundlesCollection() *TemplateBundlesCollection {
+	return &TemplateBundlesCollection{}
+}
+
+func (c *TemplateBundlesCollection) Export(conn collections.ConnectionInterface) (collections.TemplateBundle, error) {
+	c.ExportCall.Receives.Connection = conn
+
+	return c.ExportCall.Returns.Bundle, c.ExportCall.Returns.Error
+}
+
+func (c *TemplateBundlesCollection) Import(conn collections.ConnectionInterface, bundle collections.TemplateBundle, options collections.TemplateImportOptions, clientID string) (collections.TemplateImportResult, error) {
+	c.ImportCall.WasCalled = true
+	c.ImportCall.Receives.Connection = conn
+	c.ImportCall.Receives.Bundle = bundle
+	c.ImportCall.Receives.Options = options
+	c.ImportCall.Receives.ClientID = clientID
+
+	return c.ImportCall.Returns.Result, c.ImportCall.Returns.Error
+}
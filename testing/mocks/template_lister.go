@@ -1,14 +1,20 @@
 package mocks
 
-import "github.com/cloudfoundry-incubator/notifications/v1/services"
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
 
 type TemplateLister struct {
 	ListCall struct {
 		Receives struct {
 			Database services.DatabaseInterface
+			Filter   models.TemplatesFilter
+			Page     models.Page
 		}
 		Returns struct {
 			TemplateSummaries map[string]services.TemplateSummary
+			Next              []string
 			Error             error
 		}
 	}
@@ -18,8 +24,10 @@ func NewTemplateLister() *TemplateLister {
 	return &TemplateLister{}
 }
 
-func (tl *TemplateLister) List(database services.DatabaseInterface) (map[string]services.TemplateSummary, error) {
+func (tl *TemplateLister) List(database services.DatabaseInterface, filter models.TemplatesFilter, page models.Page) (map[string]services.TemplateSummary, []string, error) {
 	tl.ListCall.Receives.Database = database
+	tl.ListCall.Receives.Filter = filter
+	tl.ListCall.Receives.Page = page
 
-	return tl.ListCall.Returns.TemplateSummaries, tl.ListCall.Returns.Error
+	return tl.ListCall.Returns.TemplateSummaries, tl.ListCall.Returns.Next, tl.ListCall.Returns.Error
 }
@@ -0,0 +1,86 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TemplateLocalesCollection struct {
+	ListCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Locales []collections.TemplateLocale
+			Error   error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			Locale     string
+		}
+		Returns struct {
+			Locale collections.TemplateLocale
+			Error  error
+		}
+	}
+
+	SetCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection collections.ConnectionInterface
+			Locale     collections.TemplateLocale
+		}
+		Returns struct {
+			Locale collections.TemplateLocale
+			Error  error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			Locale     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTemplateLocalesCollection() *TemplateLocalesCollection {
+	return &TemplateLocalesCollection{}
+}
+
+func (c *TemplateLocalesCollection) List(conn collections.ConnectionInterface, templateID string) ([]collections.TemplateLocale, error) {
+	c.ListCall.Receives.Connection = conn
+	c.ListCall.Receives.TemplateID = templateID
+
+	return c.ListCall.Returns.Locales, c.ListCall.Returns.Error
+}
+
+func (c *TemplateLocalesCollection) Get(conn collections.ConnectionInterface, templateID, locale string) (collections.TemplateLocale, error) {
+	c.GetCall.Receives.Connection = conn
+	c.GetCall.Receives.TemplateID = templateID
+	c.GetCall.Receives.Locale = locale
+
+	return c.GetCall.Returns.Locale, c.GetCall.Returns.Error
+}
+
+func (c *TemplateLocalesCollection) Set(conn collections.ConnectionInterface, locale collections.TemplateLocale) (collections.TemplateLocale, error) {
+	c.SetCall.WasCalled = true
+	c.SetCall.Receives.Connection = conn
+	c.SetCall.Receives.Locale = locale
+
+	return c.SetCall.Returns.Locale, c.SetCall.Returns.Error
+}
+
+func (c *TemplateLocalesCollection) Delete(conn collections.ConnectionInterface, templateID, locale string) error {
+	c.DeleteCall.Receives.Connection = conn
+	c.DeleteCall.Receives.TemplateID = templateID
+	c.DeleteCall.Receives.Locale = locale
+
+	return c.DeleteCall.Returns.Error
+}
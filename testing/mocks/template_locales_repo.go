@@ -0,0 +1,115 @@
+package mocks
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type TemplateLocalesRepo struct {
+	UpsertCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Variant    models.TemplateLocale
+		}
+		Returns struct {
+			Variant models.TemplateLocale
+			Error   error
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+			Locales    []string
+		}
+		Returns struct {
+			Variants []models.TemplateLocale
+			Error    error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Variants []models.TemplateLocale
+			Error    error
+		}
+	}
+
+	DestroyCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+			Locale     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	DestroyAllCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTemplateLocalesRepo() *TemplateLocalesRepo {
+	return &TemplateLocalesRepo{}
+}
+
+func (r *TemplateLocalesRepo) Upsert(conn models.ConnectionInterface, variant models.TemplateLocale) (models.TemplateLocale, error) {
+	r.UpsertCall.Receives.Connection = conn
+	r.UpsertCall.Receives.Variant = variant
+
+	return r.UpsertCall.Returns.Variant, r.UpsertCall.Returns.Error
+}
+
+func (r *TemplateLocalesRepo) Find(conn models.ConnectionInterface, templateID, locale string) (models.TemplateLocale, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.TemplateID = templateID
+	r.FindCall.Receives.Locales = append(r.FindCall.Receives.Locales, locale)
+
+	if r.FindCall.Returns.Error != nil {
+		return models.TemplateLocale{}, r.FindCall.Returns.Error
+	}
+
+	for _, variant := range r.FindCall.Returns.Variants {
+		if variant.Locale == locale {
+			return variant, nil
+		}
+	}
+
+	return models.TemplateLocale{}, models.NotFoundError{Err: fmt.Errorf("Locale %q of template %q could not be found", locale, templateID)}
+}
+
+func (r *TemplateLocalesRepo) List(conn models.ConnectionInterface, templateID string) ([]models.TemplateLocale, error) {
+	r.ListCall.Receives.Connection = conn
+	r.ListCall.Receives.TemplateID = templateID
+
+	return r.ListCall.Returns.Variants, r.ListCall.Returns.Error
+}
+
+func (r *TemplateLocalesRepo) Destroy(conn models.ConnectionInterface, templateID, locale string) error {
+	r.DestroyCall.Receives.Connection = conn
+	r.DestroyCall.Receives.TemplateID = templateID
+	r.DestroyCall.Receives.Locale = locale
+
+	return r.DestroyCall.Returns.Error
+}
+
+func (r *TemplateLocalesRepo) DestroyAll(conn models.ConnectionInterface, templateID string) error {
+	r.DestroyAllCall.Receives.Connection = conn
+	r.DestroyAllCall.Receives.TemplateID = templateID
+
+	return r.DestroyAllCall.Returns.Error
+}
@@ -0,0 +1,82 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TemplatePartialsCollection struct {
+	ListCall struct {
+		CallCount int
+		Receives  struct {
+			Connection collections.ConnectionInterface
+		}
+		Returns struct {
+			Partials []collections.TemplatePartial
+			Error    error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Partial collections.TemplatePartial
+			Error   error
+		}
+	}
+
+	SetCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection collections.ConnectionInterface
+			Partial    collections.TemplatePartial
+		}
+		Returns struct {
+			Partial collections.TemplatePartial
+			Error   error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTemplatePartialsCollection() *TemplatePartialsCollection {
+	return &TemplatePartialsCollection{}
+}
+
+func (c *TemplatePartialsCollection) List(conn collections.ConnectionInterface) ([]collections.TemplatePartial, error) {
+	c.ListCall.CallCount++
+	c.ListCall.Receives.Connection = conn
+
+	return c.ListCall.Returns.Partials, c.ListCall.Returns.Error
+}
+
+func (c *TemplatePartialsCollection) Get(conn collections.ConnectionInterface, name string) (collections.TemplatePartial, error) {
+	c.GetCall.Receives.Connection = conn
+	c.GetCall.Receives.Name = name
+
+	return c.GetCall.Returns.Partial, c.GetCall.Returns.Error
+}
+
+func (c *TemplatePartialsCollection) Set(conn collections.ConnectionInterface, partial collections.TemplatePartial) (collections.TemplatePartial, error) {
+	c.SetCall.WasCalled = true
+	c.SetCall.Receives.Connection = conn
+	c.SetCall.Receives.Partial = partial
+
+	return c.SetCall.Returns.Partial, c.SetCall.Returns.Error
+}
+
+func (c *TemplatePartialsCollection) Delete(conn collections.ConnectionInterface, name string) error {
+	c.DeleteCall.Receives.Connection = conn
+	c.DeleteCall.Receives.Name = name
+
+	return c.DeleteCall.Returns.Error
+}
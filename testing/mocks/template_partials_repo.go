@@ -0,0 +1,80 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type TemplatePartialsRepo struct {
+	UpsertCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Partial    models.TemplatePartial
+		}
+		Returns struct {
+			Partial models.TemplatePartial
+			Error   error
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Partial models.TemplatePartial
+			Error   error
+		}
+	}
+
+	ListCall struct {
+		CallCount int
+		Receives  struct {
+			Connection models.ConnectionInterface
+		}
+		Returns struct {
+			Partials []models.TemplatePartial
+			Error    error
+		}
+	}
+
+	DestroyCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTemplatePartialsRepo() *TemplatePartialsRepo {
+	return &TemplatePartialsRepo{}
+}
+
+func (r *TemplatePartialsRepo) Upsert(conn models.ConnectionInterface, partial models.TemplatePartial) (models.TemplatePartial, error) {
+	r.UpsertCall.Receives.Connection = conn
+	r.UpsertCall.Receives.Partial = partial
+
+	return r.UpsertCall.Returns.Partial, r.UpsertCall.Returns.Error
+}
+
+func (r *TemplatePartialsRepo) Find(conn models.ConnectionInterface, name string) (models.TemplatePartial, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.Name = name
+
+	return r.FindCall.Returns.Partial, r.FindCall.Returns.Error
+}
+
+func (r *TemplatePartialsRepo) List(conn models.ConnectionInterface) ([]models.TemplatePartial, error) {
+	r.ListCall.CallCount++
+	r.ListCall.Receives.Connection = conn
+
+	return r.ListCall.Returns.Partials, r.ListCall.Returns.Error
+}
+
+func (r *TemplatePartialsRepo) Destroy(conn models.ConnectionInterface, name string) error {
+	r.DestroyCall.Receives.Connection = conn
+	r.DestroyCall.Receives.Name = name
+
+	return r.DestroyCall.Returns.Error
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+type TemplatePreviewer struct {
+	PreviewCall struct {
+		Receives struct {
+			Template models.Template
+			Context  common.MessageContext
+		}
+		Returns struct {
+			Preview services.TemplatePreview
+		}
+	}
+}
+
+func NewTemplatePreviewer() *TemplatePreviewer {
+	return &TemplatePreviewer{}
+}
+
+func (p *TemplatePreviewer) Preview(template models.Template, context common.MessageContext) services.TemplatePreview {
+	p.PreviewCall.Receives.Template = template
+	p.PreviewCall.Receives.Context = context
+
+	return p.PreviewCall.Returns.Preview
+}
@@ -11,6 +11,7 @@ type TemplateUpdater struct {
 			Database   services.DatabaseInterface
 			TemplateID string
 			Template   models.Template
+			ClientID   string
 		}
 		Returns struct {
 			Error error
@@ -22,10 +23,11 @@ func NewTemplateUpdater() *TemplateUpdater {
 	return &TemplateUpdater{}
 }
 
-func (tu *TemplateUpdater) Update(database services.DatabaseInterface, templateID string, template models.Template) error {
+func (tu *TemplateUpdater) Update(database services.DatabaseInterface, templateID string, template models.Template, clientID string) error {
 	tu.UpdateCall.Receives.Database = database
 	tu.UpdateCall.Receives.TemplateID = templateID
 	tu.UpdateCall.Receives.Template = template
+	tu.UpdateCall.Receives.ClientID = clientID
 
 	return tu.UpdateCall.Returns.Error
 }
@@ -0,0 +1,91 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TemplateVersionsCollection struct {
+	ListCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Versions []collections.TemplateVersion
+			Error    error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			Version    int
+		}
+		Returns struct {
+			Version collections.TemplateVersion
+			Error   error
+		}
+	}
+
+	DiffCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			From       int
+			To         int
+		}
+		Returns struct {
+			Diff  collections.TemplateDiff
+			Error error
+		}
+	}
+
+	RollbackCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			Version    int
+			ClientID   string
+		}
+		Returns struct {
+			Version collections.TemplateVersion
+			Error   error
+		}
+	}
+}
+
+func NewTemplateVersionsCollection() *TemplateVersionsCollection {
+	return &TemplateVersionsCollection{}
+}
+
+func (c *TemplateVersionsCollection) List(conn collections.ConnectionInterface, templateID string) ([]collections.TemplateVersion, error) {
+	c.ListCall.Receives.Connection = conn
+	c.ListCall.Receives.TemplateID = templateID
+
+	return c.ListCall.Returns.Versions, c.ListCall.Returns.Error
+}
+
+func (c *TemplateVersionsCollection) Get(conn collections.ConnectionInterface, templateID string, version int) (collections.TemplateVersion, error) {
+	c.GetCall.Receives.Connection = conn
+	c.GetCall.Receives.TemplateID = templateID
+	c.GetCall.Receives.Version = version
+
+	return c.GetCall.Returns.Version, c.GetCall.Returns.Error
+}
+
+func (c *TemplateVersionsCollection) Diff(conn collections.ConnectionInterface, templateID string, from, to int) (collections.TemplateDiff, error) {
+	c.DiffCall.Receives.Connection = conn
+	c.DiffCall.Receives.TemplateID = templateID
+	c.DiffCall.Receives.From = from
+	c.DiffCall.Receives.To = to
+
+	return c.DiffCall.Returns.Diff, c.DiffCall.Returns.Error
+}
+
+func (c *TemplateVersionsCollection) Rollback(conn collections.ConnectionInterface, templateID string, version int, clientID string) (collections.TemplateVersion, error) {
+	c.RollbackCall.Receives.Connection = conn
+	c.RollbackCall.Receives.TemplateID = templateID
+	c.RollbackCall.Receives.Version = version
+	c.RollbackCall.Receives.ClientID = clientID
+
+	return c.RollbackCall.Returns.Version, c.RollbackCall.Returns.Error
+}
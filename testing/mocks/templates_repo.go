@@ -35,7 +35,18 @@ type TemplatesRepo struct {
 		}
 	}
 
-	ListIDsAndNamesCall struct {
+	FindForUpdateCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Template models.Template
+			Error    error
+		}
+	}
+
+	FindAllCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
 		}
@@ -45,6 +56,18 @@ type TemplatesRepo struct {
 		}
 	}
 
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Filter     models.TemplatesFilter
+			Page       models.Page
+		}
+		Returns struct {
+			Templates []models.Template
+			Error     error
+		}
+	}
+
 	UpdateCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
@@ -83,10 +106,25 @@ func (tr *TemplatesRepo) FindByID(conn models.ConnectionInterface, templateID st
 	return tr.FindByIDCall.Returns.Template, tr.FindByIDCall.Returns.Error
 }
 
-func (tr *TemplatesRepo) ListIDsAndNames(conn models.ConnectionInterface) ([]models.Template, error) {
-	tr.ListIDsAndNamesCall.Receives.Connection = conn
+func (tr *TemplatesRepo) FindForUpdate(conn models.ConnectionInterface, templateID string) (models.Template, error) {
+	tr.FindForUpdateCall.Receives.Connection = conn
+	tr.FindForUpdateCall.Receives.TemplateID = templateID
+
+	return tr.FindForUpdateCall.Returns.Template, tr.FindForUpdateCall.Returns.Error
+}
+
+func (tr *TemplatesRepo) FindAll(conn models.ConnectionInterface) ([]models.Template, error) {
+	tr.FindAllCall.Receives.Connection = conn
+
+	return tr.FindAllCall.Returns.Templates, tr.FindAllCall.Returns.Error
+}
+
+func (tr *TemplatesRepo) List(conn models.ConnectionInterface, filter models.TemplatesFilter, page models.Page) ([]models.Template, error) {
+	tr.ListCall.Receives.Connection = conn
+	tr.ListCall.Receives.Filter = filter
+	tr.ListCall.Receives.Page = page
 
-	return tr.ListIDsAndNamesCall.Returns.Templates, tr.ListIDsAndNamesCall.Returns.Error
+	return tr.ListCall.Returns.Templates, tr.ListCall.Returns.Error
 }
 
 func (tr *TemplatesRepo) Update(conn models.ConnectionInterface, templateID string, template models.Template) (models.Template, error) {
@@ -0,0 +1,47 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type UserSettingsRepo struct {
+	FindCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			UserID     string
+		}
+		Returns struct {
+			Settings models.UserSettings
+			Error    error
+		}
+	}
+
+	UpsertCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Settings   models.UserSettings
+		}
+		Returns struct {
+			Settings models.UserSettings
+			Error    error
+		}
+	}
+}
+
+func NewUserSettingsRepo() *UserSettingsRepo {
+	return &UserSettingsRepo{}
+}
+
+func (r *UserSettingsRepo) Find(conn models.ConnectionInterface, userID string) (models.UserSettings, error) {
+	r.FindCall.WasCalled = true
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.UserID = userID
+
+	return r.FindCall.Returns.Settings, r.FindCall.Returns.Error
+}
+
+func (r *UserSettingsRepo) Upsert(conn models.ConnectionInterface, settings models.UserSettings) (models.UserSettings, error) {
+	r.UpsertCall.Receives.Connection = conn
+	r.UpsertCall.Receives.Settings = settings
+
+	return r.UpsertCall.Returns.Settings, r.UpsertCall.Returns.Error
+}
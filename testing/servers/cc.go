@@ -25,6 +25,9 @@ func NewCC(userNameToIdMap map[string]string) CC {
 	}
 
 	router.HandleFunc("/v2/spaces/{guid}", cc.GetSpace).Methods("GET")
+	router.HandleFunc("/v2/spaces/{guid}/developers", cc.GetSpaceDevelopers).Methods("GET")
+	router.HandleFunc("/v2/spaces/{guid}/managers", cc.GetSpaceManagers).Methods("GET")
+	router.HandleFunc("/v2/spaces/{guid}/auditors", cc.GetSpaceAuditors).Methods("GET")
 	router.HandleFunc("/v2/organizations/{guid}/users", cc.GetOrgUsers).Methods("GET")
 	router.HandleFunc("/v2/organizations/{guid}/managers", cc.GetOrgManagers).Methods("GET")
 	router.HandleFunc("/v2/organizations/{guid}/auditors", cc.GetOrgAuditors).Methods("GET")
@@ -147,6 +150,42 @@ func (cc CC) GetOrgUsers(w http.ResponseWriter, req *http.Request) {
 		desiredUsers = []string{}
 	}
 
+	cc.writeUsers(w, desiredUsers)
+}
+
+func (cc CC) GetSpaceDevelopers(w http.ResponseWriter, req *http.Request) {
+	cc.writeSpaceRole(w, req, map[string][]string{
+		"space-123": {"user-789", "user-000"},
+		"space-456": {"user-123"},
+	})
+}
+
+func (cc CC) GetSpaceManagers(w http.ResponseWriter, req *http.Request) {
+	cc.writeSpaceRole(w, req, map[string][]string{
+		"space-123": {"user-456"},
+		"space-456": {"user-456"},
+	})
+}
+
+func (cc CC) GetSpaceAuditors(w http.ResponseWriter, req *http.Request) {
+	cc.writeSpaceRole(w, req, map[string][]string{
+		"space-123": {"user-000"},
+		"space-456": {},
+	})
+}
+
+func (cc CC) writeSpaceRole(w http.ResponseWriter, req *http.Request, usersBySpace map[string][]string) {
+	desiredUsers, ok := usersBySpace[mux.Vars(req)["guid"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":40004,"description":"The app space could not be found","error_code":"CF-SpaceNotFound"}`))
+		return
+	}
+
+	cc.writeUsers(w, desiredUsers)
+}
+
+func (cc CC) writeUsers(w http.ResponseWriter, desiredUsers []string) {
 	users := []map[string]interface{}{}
 	for _, userName := range desiredUsers {
 		guid, ok := cc.userNameToIdMap[userName]
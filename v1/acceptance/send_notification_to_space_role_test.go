@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/acceptance/support"
+	"github.com/pivotal-cf/uaa-sso-golang/uaa"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sending notifications to users with certain roles in a space", func() {
+	var (
+		clientID    string
+		clientToken uaa.Token
+		client      *support.Client
+	)
+
+	BeforeEach(func() {
+		clientID = "notifications-sender"
+		clientToken = GetClientTokenFor(clientID)
+		client = support.NewClient(Servers.Notifications.URL())
+		Servers.SMTP.Reset()
+
+		By("registering a notification", func() {
+			status, err := client.Notifications.Register(clientToken.Access, support.RegisterClient{
+				SourceName: "Notifications Sender",
+				Notifications: map[string]support.RegisterNotification{
+					"space-role-test": {
+						Description: "Security Advisory",
+					},
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+	})
+
+	It("sends a notification to each SpaceManager in a space", func() {
+		var response support.NotifyResponse
+
+		By("sending a notification to the SpaceManager role", func() {
+			status, responses, err := client.Notify.SpaceRole(clientToken.Access, "space-123", "SpaceManager", support.Notify{
+				KindID:  "space-role-test",
+				Text:    "this is a space role test",
+				Subject: "space-role-subject",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(responses).To(HaveLen(1))
+
+			response = responses[0]
+			Expect(response.Recipient).To(Equal("user-456"))
+			Expect(response.Status).To(Equal("queued"))
+			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
+		})
+
+		By("confirming the message was sent to the manager only", func() {
+			Eventually(func() int {
+				return len(Servers.SMTP.Deliveries)
+			}, 10*time.Second).Should(Equal(1))
+			delivery := Servers.SMTP.Deliveries[0]
+
+			Expect(delivery.Recipients).To(Equal([]string{"user-456@example.com"}))
+
+			data := strings.Split(string(delivery.Data), "\n")
+			Expect(data).To(ContainElement("X-CF-Notification-ID: " + response.NotificationID))
+			Expect(data).To(ContainElement("this is a space role test"))
+		})
+	})
+
+	It("sends a notification to each SpaceDeveloper in a space", func() {
+		status, responses, err := client.Notify.SpaceRole(clientToken.Access, "space-123", "SpaceDeveloper", support.Notify{
+			KindID:  "space-role-test",
+			Text:    "this is a space role test",
+			Subject: "space-role-subject",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(responses).To(HaveLen(2))
+
+		var recipients []string
+		for _, response := range responses {
+			recipients = append(recipients, response.Recipient)
+		}
+		Expect(recipients).To(ConsistOf("user-789", "user-000"))
+	})
+
+	It("rejects roles that do not belong to a space", func() {
+		status, _, err := client.Notify.SpaceRole(clientToken.Access, "space-123", "OrgManager", support.Notify{
+			KindID:  "space-role-test",
+			Text:    "this is a space role test",
+			Subject: "space-role-subject",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(422))
+	})
+})
@@ -84,3 +84,9 @@ func (s NotifyService) Scope(token, scope string, notify Notify) (int, []NotifyR
 func (s NotifyService) Space(token, spaceGUID string, notify Notify) (int, []NotifyResponse, error) {
 	return s.notify(token, s.client.SpacesPath(spaceGUID), notify, notifyRequest{})
 }
+
+func (s NotifyService) SpaceRole(token, spaceGUID, role string, notify Notify) (int, []NotifyResponse, error) {
+	return s.notify(token, s.client.SpacesPath(spaceGUID), notify, notifyRequest{
+		Role: role,
+	})
+}
@@ -0,0 +1,108 @@
+package collections
+
+import "strings"
+
+// maxLineDiffCells bounds the size of the table lineDiff builds to match up
+// the lines of its inputs. Changes that would need a larger table are shown
+// as the removal of the old lines followed by the addition of the new ones.
+const maxLineDiffCells = 1 << 20
+
+// lineDiff returns a line-by-line diff of two strings in which unchanged lines
+// are prefixed with " ", removed lines with "-" and added lines with "+". It
+// returns an empty string when the inputs are equal.
+func lineDiff(from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+
+	// Lines shared at the start and the end are left out of the table, so
+	// that small edits to large fields stay cheap.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var lines []string
+	for _, line := range a[:prefix] {
+		lines = append(lines, " "+line)
+	}
+
+	lines = append(lines, diffLines(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, " "+line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// diffLines matches up the lines of a and b along their longest common
+// subsequence.
+func diffLines(a, b []string) []string {
+	var lines []string
+
+	if (len(a)+1)*(len(b)+1) > maxLineDiffCells {
+		for _, line := range a {
+			lines = append(lines, "-"+line)
+		}
+
+		for _, line := range b {
+			lines = append(lines, "+"+line)
+		}
+
+		return lines
+	}
+
+	// lengths[i][j] holds the length of the longest common subsequence of
+	// a[i:] and b[j:].
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		lines = append(lines, "-"+a[i])
+	}
+
+	for ; j < len(b); j++ {
+		lines = append(lines, "+"+b[j])
+	}
+
+	return lines
+}
@@ -0,0 +1,126 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/address"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type SenderIdentityError struct {
+	Err error
+}
+
+func (e SenderIdentityError) Error() string {
+	return e.Err.Error()
+}
+
+type senderIdentitiesRepository interface {
+	Upsert(connection models.ConnectionInterface, identity models.SenderIdentity) (models.SenderIdentity, error)
+	Destroy(connection models.ConnectionInterface, clientID, kindID string) error
+}
+
+type SenderIdentity struct {
+	Name    string
+	Address string
+	ReplyTo string
+}
+
+type SenderIdentitiesCollection struct {
+	clientsRepo          clientsRepository
+	kindsRepo            kindsRepository
+	senderIdentitiesRepo senderIdentitiesRepository
+	allowedDomains       []string
+}
+
+func NewSenderIdentitiesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, senderIdentitiesRepo senderIdentitiesRepository, allowedDomains []string) SenderIdentitiesCollection {
+	return SenderIdentitiesCollection{
+		clientsRepo:          clientsRepo,
+		kindsRepo:            kindsRepo,
+		senderIdentitiesRepo: senderIdentitiesRepo,
+		allowedDomains:       allowedDomains,
+	}
+}
+
+func (c SenderIdentitiesCollection) AssignToClient(conn ConnectionInterface, clientID string, identity SenderIdentity) error {
+	_, err := c.clientsRepo.Find(conn, clientID)
+	if err != nil {
+		return err
+	}
+
+	return c.assign(conn, clientID, "", identity)
+}
+
+func (c SenderIdentitiesCollection) AssignToNotification(conn ConnectionInterface, clientID, notificationID string, identity SenderIdentity) error {
+	_, err := c.clientsRepo.Find(conn, clientID)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.kindsRepo.Find(conn, notificationID, clientID)
+	if err != nil {
+		return err
+	}
+
+	return c.assign(conn, clientID, notificationID, identity)
+}
+
+func (c SenderIdentitiesCollection) UnassignFromClient(conn ConnectionInterface, clientID string) error {
+	return c.senderIdentitiesRepo.Destroy(conn, clientID, "")
+}
+
+func (c SenderIdentitiesCollection) UnassignFromNotification(conn ConnectionInterface, clientID, notificationID string) error {
+	return c.senderIdentitiesRepo.Destroy(conn, clientID, notificationID)
+}
+
+func (c SenderIdentitiesCollection) assign(conn ConnectionInterface, clientID, kindID string, identity SenderIdentity) error {
+	from, err := c.validateAddress(identity.Address)
+	if err != nil {
+		return err
+	}
+
+	var replyTo string
+	if identity.ReplyTo != "" {
+		parsedReplyTo, err := address.Parse(identity.ReplyTo)
+		if err != nil {
+			return SenderIdentityError{fmt.Errorf(`"reply_to" is improperly formatted: %s`, err)}
+		}
+		replyTo = parsedReplyTo.Email()
+	}
+
+	_, err = c.senderIdentitiesRepo.Upsert(conn, models.SenderIdentity{
+		ClientID: clientID,
+		KindID:   kindID,
+		Name:     strings.TrimSpace(identity.Name),
+		Address:  from,
+		ReplyTo:  replyTo,
+	})
+
+	return err
+}
+
+func (c SenderIdentitiesCollection) validateAddress(rawAddress string) (string, error) {
+	if rawAddress == "" {
+		return "", SenderIdentityError{errors.New(`"address" is a required field`)}
+	}
+
+	parsedAddress, err := address.Parse(rawAddress)
+	if err != nil {
+		return "", SenderIdentityError{fmt.Errorf(`"address" is improperly formatted: %s`, err)}
+	}
+
+	if parsedAddress.Name != "" {
+		return "", SenderIdentityError{fmt.Errorf(`"address" is improperly formatted: %q must not include a display name`, rawAddress)}
+	}
+
+	for _, allowedDomain := range c.allowedDomains {
+		allowedDomain, err := address.NormalizeDomain(allowedDomain)
+		if err == nil && parsedAddress.Domain == allowedDomain {
+			return parsedAddress.Email(), nil
+		}
+	}
+
+	return "", SenderIdentityError{fmt.Errorf("Sender domain %q is not one of the allowed domains: %s", parsedAddress.Domain, strings.Join(c.allowedDomains, ", "))}
+}
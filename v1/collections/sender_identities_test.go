@@ -0,0 +1,131 @@
+package collections_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SenderIdentitiesCollection", func() {
+	var (
+		kindsRepo            *mocks.KindsRepo
+		clientsRepo          *mocks.ClientsRepository
+		senderIdentitiesRepo *mocks.SenderIdentitiesRepo
+		conn                 *mocks.Connection
+
+		collection collections.SenderIdentitiesCollection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+
+		clientsRepo = mocks.NewClientsRepository()
+		kindsRepo = mocks.NewKindsRepo()
+		senderIdentitiesRepo = mocks.NewSenderIdentitiesRepo()
+
+		collection = collections.NewSenderIdentitiesCollection(clientsRepo, kindsRepo, senderIdentitiesRepo, []string{"example.com"})
+	})
+
+	Describe("AssignToClient", func() {
+		It("stores the sender identity for the client", func() {
+			err := collection.AssignToClient(conn, "my-client", collections.SenderIdentity{
+				Name:    "Autoscaler",
+				Address: "autoscaler@EXAMPLE.com",
+				ReplyTo: "Support <support@elsewhere.com>",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(clientsRepo.FindCall.Receives.ClientID).To(Equal("my-client"))
+			Expect(senderIdentitiesRepo.UpsertCall.Receives.Connection).To(Equal(conn))
+			Expect(senderIdentitiesRepo.UpsertCall.Receives.SenderIdentity).To(Equal(models.SenderIdentity{
+				ClientID: "my-client",
+				Name:     "Autoscaler",
+				Address:  "autoscaler@example.com",
+				ReplyTo:  "support@elsewhere.com",
+			}))
+		})
+
+		It("reports that the client cannot be found", func() {
+			clientsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			err := collection.AssignToClient(conn, "missing-client", collections.SenderIdentity{Address: "me@example.com"})
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+
+		It("requires an address", func() {
+			err := collection.AssignToClient(conn, "my-client", collections.SenderIdentity{Name: "Autoscaler"})
+			Expect(err).To(MatchError(collections.SenderIdentityError{Err: errors.New(`"address" is a required field`)}))
+		})
+
+		It("rejects malformed addresses", func() {
+			err := collection.AssignToClient(conn, "my-client", collections.SenderIdentity{Address: "Autoscaler <autoscaler@example.com>"})
+			Expect(err).To(MatchError(collections.SenderIdentityError{Err: errors.New(`"address" is improperly formatted: "Autoscaler <autoscaler@example.com>" must not include a display name`)}))
+
+			err = collection.AssignToClient(conn, "my-client", collections.SenderIdentity{Address: "me@"})
+			Expect(err).To(MatchError(collections.SenderIdentityError{Err: errors.New(`"address" is improperly formatted: "me@" is not a valid email address`)}))
+
+			err = collection.AssignToClient(conn, "my-client", collections.SenderIdentity{Address: "me@example.com", ReplyTo: "nope"})
+			Expect(err).To(MatchError(collections.SenderIdentityError{Err: errors.New(`"reply_to" is improperly formatted: "nope" is not a valid email address`)}))
+		})
+
+		It("rejects addresses outside of the allowed domains", func() {
+			err := collection.AssignToClient(conn, "my-client", collections.SenderIdentity{Address: "billing@evil.com"})
+			Expect(err).To(MatchError(collections.SenderIdentityError{Err: errors.New(`Sender domain "evil.com" is not one of the allowed domains: example.com`)}))
+			Expect(senderIdentitiesRepo.UpsertCall.Receives.SenderIdentity).To(Equal(models.SenderIdentity{}))
+		})
+	})
+
+	Describe("AssignToNotification", func() {
+		BeforeEach(func() {
+			kindsRepo.FindCall.Returns.Kinds = []models.Kind{{ID: "my-kind", ClientID: "my-client"}}
+		})
+
+		It("stores the sender identity for the notification", func() {
+			err := collection.AssignToNotification(conn, "my-client", "my-kind", collections.SenderIdentity{
+				Address: "autoscaler@example.com",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(kindsRepo.FindCall.Receives.KindID).To(Equal("my-kind"))
+			Expect(kindsRepo.FindCall.Receives.ClientID).To(Equal("my-client"))
+			Expect(senderIdentitiesRepo.UpsertCall.Receives.SenderIdentity).To(Equal(models.SenderIdentity{
+				ClientID: "my-client",
+				KindID:   "my-kind",
+				Address:  "autoscaler@example.com",
+			}))
+		})
+
+		It("reports that the notification cannot be found", func() {
+			kindsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			err := collection.AssignToNotification(conn, "my-client", "missing-kind", collections.SenderIdentity{Address: "me@example.com"})
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("UnassignFromClient", func() {
+		It("removes the sender identity for the client", func() {
+			err := collection.UnassignFromClient(conn, "my-client")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(senderIdentitiesRepo.DestroyCall.Receives.Connection).To(Equal(conn))
+			Expect(senderIdentitiesRepo.DestroyCall.Receives.ClientID).To(Equal("my-client"))
+			Expect(senderIdentitiesRepo.DestroyCall.Receives.KindID).To(Equal(""))
+		})
+	})
+
+	Describe("UnassignFromNotification", func() {
+		It("removes the sender identity for the notification", func() {
+			err := collection.UnassignFromNotification(conn, "my-client", "my-kind")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(senderIdentitiesRepo.DestroyCall.Receives.ClientID).To(Equal("my-client"))
+			Expect(senderIdentitiesRepo.DestroyCall.Receives.KindID).To(Equal("my-kind"))
+		})
+	})
+})
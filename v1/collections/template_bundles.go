@@ -0,0 +1,360 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+// TemplateBundleVersion is the version of the bundle format written by
+// Export. Import rejects bundles of any other version.
+const TemplateBundleVersion = 1
+
+// The strategies for importing a template whose ID is already taken.
+const (
+	ImportConflictSkip      = "skip"
+	ImportConflictOverwrite = "overwrite"
+	ImportConflictRename    = "rename"
+)
+
+// The actions taken for a template or association by an import.
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionRename    = "rename"
+	ImportActionSkip      = "skip"
+	ImportActionUnchanged = "unchanged"
+	ImportActionAssign    = "assign"
+	ImportActionMissing   = "missing"
+)
+
+type TemplateImportError struct {
+	Err error
+}
+
+func (e TemplateImportError) Error() string {
+	return e.Err.Error()
+}
+
+// TemplateBundle holds templates together with the clients and
+// notifications they are assigned to, so that they can be moved between
+// deployments.
+type TemplateBundle struct {
+	Version   int
+	Templates []BundledTemplate
+}
+
+type BundledTemplate struct {
+	Template
+	Associations []TemplateAssociation
+}
+
+type TemplateImportOptions struct {
+	Conflict string
+	DryRun   bool
+}
+
+type TemplateImportResult struct {
+	DryRun    bool
+	Templates []TemplateImportChange
+}
+
+// TemplateImportChange describes what an import did, or would do, with a
+// template of the bundle. ImportedID differs from ID when the template was
+// renamed, and Diff holds the changes made to a template that was
+// overwritten.
+type TemplateImportChange struct {
+	ID           string
+	ImportedID   string
+	Name         string
+	Action       string
+	Diff         TemplateDiff
+	Associations []TemplateAssociationChange
+}
+
+type TemplateAssociationChange struct {
+	TemplateAssociation
+	Action string
+}
+
+// Export bundles every template but the default template along with its
+// associations.
+func (c TemplatesCollection) Export(conn ConnectionInterface) (TemplateBundle, error) {
+	bundle := TemplateBundle{
+		Version:   TemplateBundleVersion,
+		Templates: []BundledTemplate{},
+	}
+
+	templates, err := c.templatesRepo.FindAll(conn)
+	if err != nil {
+		return TemplateBundle{}, err
+	}
+
+	for _, template := range templates {
+		if template.ID == models.DefaultTemplateID {
+			continue
+		}
+
+		associations, err := c.findAssociations(conn, template.ID)
+		if err != nil {
+			return TemplateBundle{}, err
+		}
+
+		bundle.Templates = append(bundle.Templates, BundledTemplate{
+			Template: Template{
+				ID:       template.ID,
+				Name:     template.Name,
+				Text:     template.Text,
+				HTML:     template.HTML,
+				Markdown: template.Markdown,
+				Layout:   template.Layout,
+				Subject:  template.Subject,
+				Metadata: template.Metadata,
+			},
+			Associations: associations,
+		})
+	}
+
+	return bundle, nil
+}
+
+// Import applies a bundle in a single transaction, so that either all of it
+// is imported or none of it. A dry run makes the same changes and reports
+// them, then rolls the transaction back.
+func (c TemplatesCollection) Import(conn ConnectionInterface, bundle TemplateBundle, options TemplateImportOptions, clientID string) (TemplateImportResult, error) {
+	switch options.Conflict {
+	case ImportConflictSkip, ImportConflictOverwrite, ImportConflictRename:
+	default:
+		return TemplateImportResult{}, TemplateImportError{fmt.Errorf("conflict strategy %q is not one of %q, %q or %q", options.Conflict, ImportConflictSkip, ImportConflictOverwrite, ImportConflictRename)}
+	}
+
+	err := validateBundle(bundle)
+	if err != nil {
+		return TemplateImportResult{}, err
+	}
+
+	result := TemplateImportResult{
+		DryRun:    options.DryRun,
+		Templates: []TemplateImportChange{},
+	}
+
+	transaction := conn.Transaction()
+
+	err = transaction.Begin()
+	if err != nil {
+		return TemplateImportResult{}, err
+	}
+
+	for _, template := range bundle.Templates {
+		change, err := c.importTemplate(transaction, template, options.Conflict, clientID)
+		if err != nil {
+			transaction.Rollback()
+			return TemplateImportResult{}, err
+		}
+
+		result.Templates = append(result.Templates, change)
+	}
+
+	if options.DryRun {
+		err = transaction.Rollback()
+	} else {
+		err = transaction.Commit()
+	}
+	if err != nil {
+		return TemplateImportResult{}, err
+	}
+
+	return result, nil
+}
+
+func validateBundle(bundle TemplateBundle) error {
+	if bundle.Version != TemplateBundleVersion {
+		return TemplateImportError{fmt.Errorf("bundle version %d is not supported, expected %d", bundle.Version, TemplateBundleVersion)}
+	}
+
+	ids := map[string]bool{}
+	for _, template := range bundle.Templates {
+		if template.ID == models.DefaultTemplateID {
+			return TemplateImportError{errors.New("the default template cannot be imported")}
+		}
+
+		if template.ID == "" {
+			continue
+		}
+
+		if ids[template.ID] {
+			return TemplateImportError{fmt.Errorf("template %q appears more than once in the bundle", template.ID)}
+		}
+		ids[template.ID] = true
+	}
+
+	return nil
+}
+
+func (c TemplatesCollection) importTemplate(conn ConnectionInterface, template BundledTemplate, conflict, clientID string) (TemplateImportChange, error) {
+	change := TemplateImportChange{
+		ID:           template.ID,
+		Name:         template.Name,
+		Associations: []TemplateAssociationChange{},
+	}
+
+	existing, err := c.templatesRepo.FindByID(conn, template.ID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); !ok {
+			return TemplateImportChange{}, err
+		}
+	}
+	exists := err == nil
+
+	var imported models.Template
+	switch {
+	case !exists:
+		change.Action = ImportActionCreate
+		imported, err = c.createTemplate(conn, template.ID, template.Template, clientID)
+
+	case conflict == ImportConflictSkip:
+		change.Action = ImportActionSkip
+		change.ImportedID = existing.ID
+		return change, nil
+
+	case conflict == ImportConflictOverwrite:
+		change.Diff = templateDiff(existing, template.Template)
+		if change.Diff == (TemplateDiff{TemplateID: existing.ID}) {
+			change.Action = ImportActionUnchanged
+			imported = existing
+			break
+		}
+
+		change.Action = ImportActionUpdate
+		imported, err = c.updateTemplate(conn, existing.ID, template.Template, clientID)
+
+	default:
+		change.Action = ImportActionRename
+		imported, err = c.createTemplate(conn, "", template.Template, clientID)
+	}
+	if err != nil {
+		return TemplateImportChange{}, err
+	}
+
+	change.ImportedID = imported.ID
+
+	for _, association := range template.Associations {
+		action, err := c.importAssociation(conn, association, imported.ID)
+		if err != nil {
+			return TemplateImportChange{}, err
+		}
+
+		change.Associations = append(change.Associations, TemplateAssociationChange{
+			TemplateAssociation: association,
+			Action:              action,
+		})
+	}
+
+	return change, nil
+}
+
+func (c TemplatesCollection) createTemplate(conn ConnectionInterface, templateID string, template Template, clientID string) (models.Template, error) {
+	created, err := c.templatesRepo.Create(conn, models.Template{
+		ID:       templateID,
+		Name:     template.Name,
+		Text:     template.Text,
+		HTML:     template.HTML,
+		Markdown: template.Markdown,
+		Layout:   template.Layout,
+		Subject:  template.Subject,
+		Metadata: template.Metadata,
+	})
+	if err != nil {
+		return models.Template{}, err
+	}
+
+	_, err = c.versionsRepo.Create(conn, models.NewTemplateVersion(created, clientID))
+	if err != nil {
+		return models.Template{}, err
+	}
+
+	return created, nil
+}
+
+func (c TemplatesCollection) updateTemplate(conn ConnectionInterface, templateID string, template Template, clientID string) (models.Template, error) {
+	updated, err := c.templatesRepo.Update(conn, templateID, models.Template{
+		Name:     template.Name,
+		Text:     template.Text,
+		HTML:     template.HTML,
+		Markdown: template.Markdown,
+		Layout:   template.Layout,
+		Subject:  template.Subject,
+		Metadata: template.Metadata,
+	})
+	if err != nil {
+		return models.Template{}, err
+	}
+
+	_, err = c.versionsRepo.Create(conn, models.NewTemplateVersion(updated, clientID))
+	if err != nil {
+		return models.Template{}, err
+	}
+
+	return updated, nil
+}
+
+// importAssociation assigns the imported template to a client or to one of
+// its notifications. Clients and notifications that are not registered in
+// this deployment are reported as missing rather than failing the import.
+func (c TemplatesCollection) importAssociation(conn ConnectionInterface, association TemplateAssociation, templateID string) (string, error) {
+	if association.NotificationID == "" {
+		client, err := c.clientsRepo.Find(conn, association.ClientID)
+		if err != nil {
+			if _, ok := err.(models.NotFoundError); ok {
+				return ImportActionMissing, nil
+			}
+			return "", err
+		}
+
+		if client.TemplateID == templateID {
+			return ImportActionUnchanged, nil
+		}
+
+		client.TemplateID = templateID
+		_, err = c.clientsRepo.Update(conn, client)
+		if err != nil {
+			return "", err
+		}
+
+		return ImportActionAssign, nil
+	}
+
+	kind, err := c.kindsRepo.Find(conn, association.NotificationID, association.ClientID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); ok {
+			return ImportActionMissing, nil
+		}
+		return "", err
+	}
+
+	if kind.TemplateID == templateID {
+		return ImportActionUnchanged, nil
+	}
+
+	kind.TemplateID = templateID
+	_, err = c.kindsRepo.Update(conn, kind)
+	if err != nil {
+		return "", err
+	}
+
+	return ImportActionAssign, nil
+}
+
+func templateDiff(existing models.Template, template Template) TemplateDiff {
+	return TemplateDiff{
+		TemplateID: existing.ID,
+		Name:       lineDiff(existing.Name, template.Name),
+		Subject:    lineDiff(existing.Subject, template.Subject),
+		Text:       lineDiff(existing.Text, template.Text),
+		HTML:       lineDiff(existing.HTML, template.HTML),
+		Markdown:   lineDiff(existing.Markdown, template.Markdown),
+		Layout:     lineDiff(existing.Layout, template.Layout),
+		Metadata:   lineDiff(existing.Metadata, template.Metadata),
+	}
+}
@@ -0,0 +1,355 @@
+package collections_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template bundles", func() {
+	var (
+		kindsRepo     *mocks.KindsRepo
+		clientsRepo   *mocks.ClientsRepository
+		templatesRepo *mocks.TemplatesRepo
+		versionsRepo  *mocks.TemplateVersionsRepo
+		conn          *mocks.Connection
+		transaction   *mocks.Transaction
+
+		collection collections.TemplatesCollection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
+
+		clientsRepo = mocks.NewClientsRepository()
+		kindsRepo = mocks.NewKindsRepo()
+		templatesRepo = mocks.NewTemplatesRepo()
+		versionsRepo = mocks.NewTemplateVersionsRepo()
+
+		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, versionsRepo, mocks.NewTemplateLocalesRepo())
+	})
+
+	Describe("Export", func() {
+		BeforeEach(func() {
+			templatesRepo.FindAllCall.Returns.Templates = []models.Template{
+				{ID: "default", Name: "Default"},
+				{
+					ID:       "some-template-id",
+					Name:     "Some template",
+					Subject:  "{{.Subject}}",
+					Text:     "some-text",
+					HTML:     "some-html",
+					Markdown: "some-markdown",
+					Layout:   "some-layout",
+					Metadata: `{"inline_css": true}`,
+				},
+			}
+			clientsRepo.FindAllByTemplateIDCall.Returns.Clients = []models.Client{{ID: "some-client"}}
+			kindsRepo.FindAllByTemplateIDCall.Returns.Kinds = []models.Kind{{ID: "some-kind", ClientID: "other-client"}}
+		})
+
+		It("bundles every template but the default template with its associations", func() {
+			bundle, err := collection.Export(conn)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(bundle).To(Equal(collections.TemplateBundle{
+				Version: collections.TemplateBundleVersion,
+				Templates: []collections.BundledTemplate{
+					{
+						Template: collections.Template{
+							ID:       "some-template-id",
+							Name:     "Some template",
+							Subject:  "{{.Subject}}",
+							Text:     "some-text",
+							HTML:     "some-html",
+							Markdown: "some-markdown",
+							Layout:   "some-layout",
+							Metadata: `{"inline_css": true}`,
+						},
+						Associations: []collections.TemplateAssociation{
+							{ClientID: "some-client"},
+							{ClientID: "other-client", NotificationID: "some-kind"},
+						},
+					},
+				},
+			}))
+
+			Expect(templatesRepo.FindAllCall.Receives.Connection).To(Equal(conn))
+			Expect(clientsRepo.FindAllByTemplateIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(kindsRepo.FindAllByTemplateIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		It("propagates errors from the repos", func() {
+			kindsRepo.FindAllByTemplateIDCall.Returns.Error = errors.New("kinds boom")
+
+			_, err := collection.Export(conn)
+			Expect(err).To(MatchError(errors.New("kinds boom")))
+		})
+	})
+
+	Describe("Import", func() {
+		var (
+			bundle  collections.TemplateBundle
+			options collections.TemplateImportOptions
+		)
+
+		BeforeEach(func() {
+			bundle = collections.TemplateBundle{
+				Version: collections.TemplateBundleVersion,
+				Templates: []collections.BundledTemplate{
+					{
+						Template: collections.Template{
+							ID:       "some-template-id",
+							Name:     "Some template",
+							Subject:  "{{.Subject}}",
+							HTML:     "new html",
+							Metadata: "{}",
+						},
+						Associations: []collections.TemplateAssociation{
+							{ClientID: "some-client"},
+						},
+					},
+				},
+			}
+			options = collections.TemplateImportOptions{Conflict: collections.ImportConflictSkip}
+
+			clientsRepo.FindCall.Returns.Client = models.Client{ID: "some-client", TemplateID: "default"}
+		})
+
+		Context("when the template does not exist", func() {
+			BeforeEach(func() {
+				templatesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+				templatesRepo.CreateCall.Returns.Template = models.Template{
+					ID:      "some-template-id",
+					Name:    "Some template",
+					HTML:    "new html",
+					Version: 1,
+				}
+			})
+
+			It("creates it with the id from the bundle and assigns it in a single transaction", func() {
+				result, err := collection.Import(conn, bundle, options, "importing-client")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result).To(Equal(collections.TemplateImportResult{
+					Templates: []collections.TemplateImportChange{
+						{
+							ID:         "some-template-id",
+							ImportedID: "some-template-id",
+							Name:       "Some template",
+							Action:     collections.ImportActionCreate,
+							Associations: []collections.TemplateAssociationChange{
+								{
+									TemplateAssociation: collections.TemplateAssociation{ClientID: "some-client"},
+									Action:              collections.ImportActionAssign,
+								},
+							},
+						},
+					},
+				}))
+
+				Expect(templatesRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+				Expect(templatesRepo.CreateCall.Receives.Template).To(Equal(models.Template{
+					ID:       "some-template-id",
+					Name:     "Some template",
+					Subject:  "{{.Subject}}",
+					HTML:     "new html",
+					Metadata: "{}",
+				}))
+
+				Expect(versionsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+				Expect(versionsRepo.CreateCall.Receives.Version.TemplateID).To(Equal("some-template-id"))
+				Expect(versionsRepo.CreateCall.Receives.Version.ClientID).To(Equal("importing-client"))
+
+				Expect(clientsRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
+				Expect(clientsRepo.UpdateCall.Receives.Client).To(Equal(models.Client{
+					ID:         "some-client",
+					TemplateID: "some-template-id",
+				}))
+
+				Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+				Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+			})
+
+			It("reports associations with clients that are not registered as missing", func() {
+				clientsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("no client")}
+
+				result, err := collection.Import(conn, bundle, options, "importing-client")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Templates[0].Associations[0].Action).To(Equal(collections.ImportActionMissing))
+				Expect(clientsRepo.UpdateCall.Receives.Client).To(Equal(models.Client{}))
+			})
+
+			It("assigns the template to notifications", func() {
+				bundle.Templates[0].Associations = []collections.TemplateAssociation{
+					{ClientID: "some-client", NotificationID: "some-kind"},
+				}
+				kindsRepo.FindCall.Returns.Kinds = []models.Kind{{ID: "some-kind", ClientID: "some-client"}}
+
+				result, err := collection.Import(conn, bundle, options, "importing-client")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Templates[0].Associations[0].Action).To(Equal(collections.ImportActionAssign))
+				Expect(kindsRepo.FindCall.Receives.KindID).To(Equal("some-kind"))
+				Expect(kindsRepo.FindCall.Receives.ClientID).To(Equal("some-client"))
+				Expect(kindsRepo.UpdateCall.Receives.Kind).To(Equal(models.Kind{
+					ID:         "some-kind",
+					ClientID:   "some-client",
+					TemplateID: "some-template-id",
+				}))
+			})
+
+			Context("when it is a dry run", func() {
+				It("reports the changes and rolls them back", func() {
+					options.DryRun = true
+
+					result, err := collection.Import(conn, bundle, options, "importing-client")
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(result.DryRun).To(BeTrue())
+					Expect(result.Templates[0].Action).To(Equal(collections.ImportActionCreate))
+
+					Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+					Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+				})
+			})
+
+			It("rolls back everything when a template cannot be created", func() {
+				templatesRepo.CreateCall.Returns.Error = errors.New("create boom")
+
+				_, err := collection.Import(conn, bundle, options, "importing-client")
+				Expect(err).To(MatchError(errors.New("create boom")))
+
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		Context("when the template already exists", func() {
+			BeforeEach(func() {
+				templatesRepo.FindByIDCall.Returns.Template = models.Template{
+					ID:       "some-template-id",
+					Name:     "Some template",
+					Subject:  "{{.Subject}}",
+					HTML:     "old html",
+					Metadata: "{}",
+					Version:  2,
+				}
+			})
+
+			It("skips it and its associations", func() {
+				result, err := collection.Import(conn, bundle, options, "importing-client")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Templates).To(Equal([]collections.TemplateImportChange{
+					{
+						ID:           "some-template-id",
+						ImportedID:   "some-template-id",
+						Name:         "Some template",
+						Action:       collections.ImportActionSkip,
+						Associations: []collections.TemplateAssociationChange{},
+					},
+				}))
+
+				Expect(templatesRepo.CreateCall.Receives.Template).To(Equal(models.Template{}))
+				Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+				Expect(clientsRepo.UpdateCall.Receives.Client).To(Equal(models.Client{}))
+			})
+
+			Context("when the conflict strategy is overwrite", func() {
+				BeforeEach(func() {
+					options.Conflict = collections.ImportConflictOverwrite
+					templatesRepo.UpdateCall.Returns.Template = models.Template{
+						ID:      "some-template-id",
+						Name:    "Some template",
+						HTML:    "new html",
+						Version: 3,
+					}
+				})
+
+				It("updates it as a new version and reports the diff", func() {
+					result, err := collection.Import(conn, bundle, options, "importing-client")
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(result.Templates[0].Action).To(Equal(collections.ImportActionUpdate))
+					Expect(result.Templates[0].Diff).To(Equal(collections.TemplateDiff{
+						TemplateID: "some-template-id",
+						HTML:       "-old html\n+new html",
+					}))
+
+					Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
+					Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("some-template-id"))
+					Expect(templatesRepo.UpdateCall.Receives.Template.HTML).To(Equal("new html"))
+					Expect(versionsRepo.CreateCall.Receives.Version.Version).To(Equal(3))
+					Expect(clientsRepo.UpdateCall.Receives.Client.TemplateID).To(Equal("some-template-id"))
+				})
+
+				It("leaves an identical template alone", func() {
+					bundle.Templates[0].HTML = "old html"
+
+					result, err := collection.Import(conn, bundle, options, "importing-client")
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(result.Templates[0].Action).To(Equal(collections.ImportActionUnchanged))
+					Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+					Expect(result.Templates[0].Associations[0].Action).To(Equal(collections.ImportActionAssign))
+				})
+			})
+
+			Context("when the conflict strategy is rename", func() {
+				It("creates it with a new id", func() {
+					options.Conflict = collections.ImportConflictRename
+					templatesRepo.CreateCall.Returns.Template = models.Template{ID: "new-template-id"}
+
+					result, err := collection.Import(conn, bundle, options, "importing-client")
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(result.Templates[0].Action).To(Equal(collections.ImportActionRename))
+					Expect(result.Templates[0].ImportedID).To(Equal("new-template-id"))
+					Expect(templatesRepo.CreateCall.Receives.Template.ID).To(BeEmpty())
+					Expect(clientsRepo.UpdateCall.Receives.Client.TemplateID).To(Equal("new-template-id"))
+				})
+			})
+		})
+
+		Context("when the bundle cannot be imported", func() {
+			It("rejects other bundle versions", func() {
+				bundle.Version = 2
+
+				_, err := collection.Import(conn, bundle, options, "importing-client")
+				Expect(err).To(MatchError(collections.TemplateImportError{Err: errors.New("bundle version 2 is not supported, expected 1")}))
+				Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+			})
+
+			It("rejects the default template", func() {
+				bundle.Templates[0].ID = "default"
+
+				_, err := collection.Import(conn, bundle, options, "importing-client")
+				Expect(err).To(MatchError(collections.TemplateImportError{Err: errors.New("the default template cannot be imported")}))
+			})
+
+			It("rejects templates that appear twice", func() {
+				bundle.Templates = append(bundle.Templates, bundle.Templates[0])
+
+				_, err := collection.Import(conn, bundle, options, "importing-client")
+				Expect(err).To(MatchError(collections.TemplateImportError{Err: errors.New(`template "some-template-id" appears more than once in the bundle`)}))
+			})
+
+			It("rejects unknown conflict strategies", func() {
+				options.Conflict = "merge"
+
+				_, err := collection.Import(conn, bundle, options, "importing-client")
+				Expect(err).To(MatchError(collections.TemplateImportError{Err: errors.New(`conflict strategy "merge" is not one of "skip", "overwrite" or "rename"`)}))
+			})
+		})
+	})
+})
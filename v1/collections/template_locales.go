@@ -0,0 +1,124 @@
+package collections
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type templateFinder interface {
+	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
+	FindForUpdate(connection models.ConnectionInterface, templateID string) (models.Template, error)
+}
+
+type templateLocalesRepository interface {
+	Upsert(connection models.ConnectionInterface, variant models.TemplateLocale) (models.TemplateLocale, error)
+	Find(connection models.ConnectionInterface, templateID, locale string) (models.TemplateLocale, error)
+	List(connection models.ConnectionInterface, templateID string) ([]models.TemplateLocale, error)
+	Destroy(connection models.ConnectionInterface, templateID, locale string) error
+}
+
+type TemplateLocale struct {
+	TemplateID string
+	Locale     string
+	Subject    string
+	Text       string
+	HTML       string
+	Markdown   string
+	UpdatedAt  time.Time
+}
+
+type TemplateLocalesCollection struct {
+	templatesRepo templateFinder
+	localesRepo   templateLocalesRepository
+}
+
+func NewTemplateLocalesCollection(templatesRepo templateFinder, localesRepo templateLocalesRepository) TemplateLocalesCollection {
+	return TemplateLocalesCollection{
+		templatesRepo: templatesRepo,
+		localesRepo:   localesRepo,
+	}
+}
+
+func (c TemplateLocalesCollection) List(conn ConnectionInterface, templateID string) ([]TemplateLocale, error) {
+	_, err := c.templatesRepo.FindByID(conn, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := c.localesRepo.List(conn, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []TemplateLocale{}
+	for _, variant := range variants {
+		result = append(result, newTemplateLocale(variant))
+	}
+
+	return result, nil
+}
+
+func (c TemplateLocalesCollection) Get(conn ConnectionInterface, templateID, locale string) (TemplateLocale, error) {
+	variant, err := c.localesRepo.Find(conn, templateID, locale)
+	if err != nil {
+		return TemplateLocale{}, err
+	}
+
+	return newTemplateLocale(variant), nil
+}
+
+// Set saves a locale variant against the current version of its template.
+// The template is locked while the variant is saved, so that a rollback of
+// the template sees every variant written for the versions it replaces.
+func (c TemplateLocalesCollection) Set(conn ConnectionInterface, variant TemplateLocale) (TemplateLocale, error) {
+	transaction := conn.Transaction()
+
+	err := transaction.Begin()
+	if err != nil {
+		return TemplateLocale{}, err
+	}
+
+	template, err := c.templatesRepo.FindForUpdate(transaction, variant.TemplateID)
+	if err != nil {
+		transaction.Rollback()
+		return TemplateLocale{}, err
+	}
+
+	saved, err := c.localesRepo.Upsert(transaction, models.TemplateLocale{
+		TemplateID:      variant.TemplateID,
+		TemplateVersion: template.Version,
+		Locale:          variant.Locale,
+		Subject:         variant.Subject,
+		Text:            variant.Text,
+		HTML:            variant.HTML,
+		Markdown:        variant.Markdown,
+	})
+	if err != nil {
+		transaction.Rollback()
+		return TemplateLocale{}, err
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return TemplateLocale{}, err
+	}
+
+	return newTemplateLocale(saved), nil
+}
+
+func (c TemplateLocalesCollection) Delete(conn ConnectionInterface, templateID, locale string) error {
+	return c.localesRepo.Destroy(conn, templateID, locale)
+}
+
+func newTemplateLocale(variant models.TemplateLocale) TemplateLocale {
+	return TemplateLocale{
+		TemplateID: variant.TemplateID,
+		Locale:     variant.Locale,
+		Subject:    variant.Subject,
+		Text:       variant.Text,
+		HTML:       variant.HTML,
+		Markdown:   variant.Markdown,
+		UpdatedAt:  variant.UpdatedAt,
+	}
+}
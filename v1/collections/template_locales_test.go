@@ -0,0 +1,161 @@
+package collections_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateLocalesCollection", func() {
+	var (
+		templatesRepo *mocks.TemplatesRepo
+		localesRepo   *mocks.TemplateLocalesRepo
+		conn          *mocks.Connection
+		transaction   *mocks.Transaction
+		updatedAt     time.Time
+
+		collection collections.TemplateLocalesCollection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
+		updatedAt = time.Now().Truncate(time.Second)
+
+		templatesRepo = mocks.NewTemplatesRepo()
+		localesRepo = mocks.NewTemplateLocalesRepo()
+		localesRepo.FindCall.Returns.Variants = []models.TemplateLocale{
+			{
+				TemplateID: "some-template-id",
+				Locale:     "de",
+				Subject:    "Betreff: {{.Subject}}",
+				Text:       "Hallo",
+				UpdatedAt:  updatedAt,
+			},
+		}
+
+		collection = collections.NewTemplateLocalesCollection(templatesRepo, localesRepo)
+	})
+
+	Describe("List", func() {
+		It("returns the locale variants of the template", func() {
+			localesRepo.ListCall.Returns.Variants = localesRepo.FindCall.Returns.Variants
+
+			variants, err := collection.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variants).To(Equal([]collections.TemplateLocale{
+				{
+					TemplateID: "some-template-id",
+					Locale:     "de",
+					Subject:    "Betreff: {{.Subject}}",
+					Text:       "Hallo",
+					UpdatedAt:  updatedAt,
+				},
+			}))
+
+			Expect(templatesRepo.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(localesRepo.ListCall.Receives.Connection).To(Equal(conn))
+			Expect(localesRepo.ListCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		It("returns an error when the template does not exist", func() {
+			templatesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.List(conn, "missing-template-id")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the locale variant", func() {
+			variant, err := collection.Get(conn, "some-template-id", "de")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variant.Text).To(Equal("Hallo"))
+			Expect(localesRepo.FindCall.Receives.Locales).To(Equal([]string{"de"}))
+		})
+
+		It("returns a not found error when the variant does not exist", func() {
+			_, err := collection.Get(conn, "some-template-id", "fr")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+	})
+
+	Describe("Set", func() {
+		BeforeEach(func() {
+			templatesRepo.FindForUpdateCall.Returns.Template = models.Template{ID: "some-template-id", Version: 4}
+		})
+
+		It("saves the locale variant against the current version of the template", func() {
+			localesRepo.UpsertCall.Returns.Variant = models.TemplateLocale{
+				TemplateID: "some-template-id",
+				Locale:     "fr",
+				Text:       "Bonjour",
+				Markdown:   "# Bonjour",
+				UpdatedAt:  updatedAt,
+			}
+
+			variant, err := collection.Set(conn, collections.TemplateLocale{
+				TemplateID: "some-template-id",
+				Locale:     "fr",
+				Text:       "Bonjour",
+				Markdown:   "# Bonjour",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variant).To(Equal(collections.TemplateLocale{
+				TemplateID: "some-template-id",
+				Locale:     "fr",
+				Text:       "Bonjour",
+				Markdown:   "# Bonjour",
+				UpdatedAt:  updatedAt,
+			}))
+
+			Expect(templatesRepo.FindForUpdateCall.Receives.Connection).To(Equal(transaction))
+			Expect(templatesRepo.FindForUpdateCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(localesRepo.UpsertCall.Receives.Connection).To(Equal(transaction))
+			Expect(localesRepo.UpsertCall.Receives.Variant).To(Equal(models.TemplateLocale{
+				TemplateID:      "some-template-id",
+				TemplateVersion: 4,
+				Locale:          "fr",
+				Text:            "Bonjour",
+				Markdown:        "# Bonjour",
+			}))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("does not save variants of templates that do not exist", func() {
+			templatesRepo.FindForUpdateCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.Set(conn, collections.TemplateLocale{TemplateID: "missing-template-id", Locale: "fr"})
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+			Expect(localesRepo.UpsertCall.Receives.Connection).To(BeNil())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		})
+
+		It("returns errors from the repo", func() {
+			localesRepo.UpsertCall.Returns.Error = errors.New("BOOM!")
+
+			_, err := collection.Set(conn, collections.TemplateLocale{TemplateID: "some-template-id", Locale: "fr"})
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Describe("Delete", func() {
+		It("removes the locale variant", func() {
+			err := collection.Delete(conn, "some-template-id", "de")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(localesRepo.DestroyCall.Receives.Connection).To(Equal(conn))
+			Expect(localesRepo.DestroyCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(localesRepo.DestroyCall.Receives.Locale).To(Equal("de"))
+		})
+	})
+})
@@ -0,0 +1,231 @@
+package collections
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type TemplatePartialError struct {
+	Err error
+}
+
+func (e TemplatePartialError) Error() string {
+	return e.Err.Error()
+}
+
+type templatePartialsRepository interface {
+	Upsert(connection models.ConnectionInterface, partial models.TemplatePartial) (models.TemplatePartial, error)
+	Find(connection models.ConnectionInterface, name string) (models.TemplatePartial, error)
+	List(connection models.ConnectionInterface) ([]models.TemplatePartial, error)
+	Destroy(connection models.ConnectionInterface, name string) error
+}
+
+type templatesLister interface {
+	FindAll(connection models.ConnectionInterface) ([]models.Template, error)
+}
+
+type templateLocalesLister interface {
+	List(connection models.ConnectionInterface, templateID string) ([]models.TemplateLocale, error)
+}
+
+type TemplatePartial struct {
+	Name      string
+	Text      string
+	HTML      string
+	UpdatedAt time.Time
+}
+
+type TemplatePartialsCollection struct {
+	partialsRepo        templatePartialsRepository
+	templatesRepo       templatesLister
+	templateLocalesRepo templateLocalesLister
+}
+
+func NewTemplatePartialsCollection(partialsRepo templatePartialsRepository, templatesRepo templatesLister, templateLocalesRepo templateLocalesLister) TemplatePartialsCollection {
+	return TemplatePartialsCollection{
+		partialsRepo:        partialsRepo,
+		templatesRepo:       templatesRepo,
+		templateLocalesRepo: templateLocalesRepo,
+	}
+}
+
+func (c TemplatePartialsCollection) List(conn ConnectionInterface) ([]TemplatePartial, error) {
+	partials, err := c.partialsRepo.List(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []TemplatePartial{}
+	for _, partial := range partials {
+		result = append(result, newTemplatePartial(partial))
+	}
+
+	return result, nil
+}
+
+func (c TemplatePartialsCollection) Get(conn ConnectionInterface, name string) (TemplatePartial, error) {
+	partial, err := c.partialsRepo.Find(conn, name)
+	if err != nil {
+		return TemplatePartial{}, err
+	}
+
+	return newTemplatePartial(partial), nil
+}
+
+// Set saves the partial once the partials, including the new version of
+// this one, have been checked to resolve without missing partials or
+// cycles.
+func (c TemplatePartialsCollection) Set(conn ConnectionInterface, partial TemplatePartial) (TemplatePartial, error) {
+	partials, err := c.load(conn)
+	if err != nil {
+		return TemplatePartial{}, err
+	}
+
+	partials[partial.Name] = common.Partial{
+		Name: partial.Name,
+		Text: partial.Text,
+		HTML: partial.HTML,
+	}
+
+	err = validatePartials(partials)
+	if err != nil {
+		return TemplatePartial{}, err
+	}
+
+	saved, err := c.partialsRepo.Upsert(conn, models.TemplatePartial{
+		Name: partial.Name,
+		Text: partial.Text,
+		HTML: partial.HTML,
+	})
+	if err != nil {
+		return TemplatePartial{}, err
+	}
+
+	return newTemplatePartial(saved), nil
+}
+
+// Delete removes the partial unless other partials, templates or their
+// locale variants still include it or use it as their layout.
+func (c TemplatePartialsCollection) Delete(conn ConnectionInterface, name string) error {
+	partials, err := c.load(conn)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := partials[name]; ok {
+		remaining := map[string]common.Partial{}
+		for partialName, partial := range partials {
+			if partialName != name {
+				remaining[partialName] = partial
+			}
+		}
+
+		err = validatePartials(remaining)
+		if err != nil {
+			return err
+		}
+
+		users, err := c.templatesBrokenBy(conn, partials, remaining)
+		if err != nil {
+			return err
+		}
+
+		if len(users) > 0 {
+			return TemplatePartialError{fmt.Errorf("partial %q is still used by templates: %s", name, strings.Join(users, ", "))}
+		}
+	}
+
+	return c.partialsRepo.Destroy(conn, name)
+}
+
+// templatesBrokenBy lists the templates that resolve against the partials
+// but not against the remaining ones, along with their locale variants,
+// which are rendered in the layout of their template.
+func (c TemplatePartialsCollection) templatesBrokenBy(conn ConnectionInterface, partials, remaining map[string]common.Partial) ([]string, error) {
+	templates, err := c.templatesRepo.FindAll(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []string
+	for _, template := range templates {
+		sources := []common.Templates{{
+			Text:     template.Text,
+			HTML:     template.HTML,
+			Markdown: template.Markdown,
+			Layout:   template.Layout,
+		}}
+
+		variants, err := c.templateLocalesRepo.List(conn, template.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, variant := range variants {
+			sources = append(sources, common.Templates{
+				Text:     variant.Text,
+				HTML:     variant.HTML,
+				Markdown: variant.Markdown,
+				Layout:   template.Layout,
+			})
+		}
+
+		for _, source := range sources {
+			if resolves(source, partials) && !resolves(source, remaining) {
+				users = append(users, fmt.Sprintf("%q (%s)", template.Name, template.ID))
+				break
+			}
+		}
+	}
+
+	return users, nil
+}
+
+func resolves(templates common.Templates, partials map[string]common.Partial) bool {
+	if !common.UsesPartials(templates) {
+		return true
+	}
+
+	_, err := common.ResolvePartials(templates, partials)
+	return err == nil
+}
+
+func (c TemplatePartialsCollection) load(conn ConnectionInterface) (map[string]common.Partial, error) {
+	partials, err := c.partialsRepo.List(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]common.Partial{}
+	for _, partial := range partials {
+		byName[partial.Name] = common.Partial{
+			Name: partial.Name,
+			Text: partial.Text,
+			HTML: partial.HTML,
+		}
+	}
+
+	return byName, nil
+}
+
+func validatePartials(partials map[string]common.Partial) error {
+	err := common.ValidatePartials(partials)
+	if partialErr, ok := err.(common.PartialError); ok {
+		return TemplatePartialError{partialErr.Err}
+	}
+
+	return err
+}
+
+func newTemplatePartial(partial models.TemplatePartial) TemplatePartial {
+	return TemplatePartial{
+		Name:      partial.Name,
+		Text:      partial.Text,
+		HTML:      partial.HTML,
+		UpdatedAt: partial.UpdatedAt,
+	}
+}
@@ -0,0 +1,202 @@
+package collections_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplatePartialsCollection", func() {
+	var (
+		partialsRepo        *mocks.TemplatePartialsRepo
+		templatesRepo       *mocks.TemplatesRepo
+		templateLocalesRepo *mocks.TemplateLocalesRepo
+		conn                *mocks.Connection
+		updatedAt           time.Time
+
+		collection collections.TemplatePartialsCollection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		updatedAt = time.Now().Truncate(time.Second)
+
+		partialsRepo = mocks.NewTemplatePartialsRepo()
+		partialsRepo.ListCall.Returns.Partials = []models.TemplatePartial{
+			{
+				Name:      "footer",
+				Text:      `-- {{template "signature"}}`,
+				HTML:      `<footer>{{template "signature"}}</footer>`,
+				UpdatedAt: updatedAt,
+			},
+			{
+				Name: "signature",
+				Text: "The Team",
+			},
+		}
+
+		templatesRepo = mocks.NewTemplatesRepo()
+		templateLocalesRepo = mocks.NewTemplateLocalesRepo()
+
+		collection = collections.NewTemplatePartialsCollection(partialsRepo, templatesRepo, templateLocalesRepo)
+	})
+
+	Describe("List", func() {
+		It("returns the partials", func() {
+			partials, err := collection.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(Equal([]collections.TemplatePartial{
+				{
+					Name:      "footer",
+					Text:      `-- {{template "signature"}}`,
+					HTML:      `<footer>{{template "signature"}}</footer>`,
+					UpdatedAt: updatedAt,
+				},
+				{
+					Name: "signature",
+					Text: "The Team",
+				},
+			}))
+
+			Expect(partialsRepo.ListCall.Receives.Connection).To(Equal(conn))
+		})
+
+		It("returns errors from the repo", func() {
+			partialsRepo.ListCall.Returns.Error = errors.New("BOOM!")
+
+			_, err := collection.List(conn)
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the partial", func() {
+			partialsRepo.FindCall.Returns.Partial = models.TemplatePartial{Name: "footer", Text: "-- The Team"}
+
+			partial, err := collection.Get(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(collections.TemplatePartial{Name: "footer", Text: "-- The Team"}))
+			Expect(partialsRepo.FindCall.Receives.Name).To(Equal("footer"))
+		})
+
+		It("returns an error when the partial does not exist", func() {
+			partialsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.Get(conn, "missing")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("Set", func() {
+		It("saves the partial", func() {
+			partialsRepo.UpsertCall.Returns.Partial = models.TemplatePartial{
+				Name:      "layout",
+				Text:      `{{template "content" .}} {{template "footer"}}`,
+				UpdatedAt: updatedAt,
+			}
+
+			partial, err := collection.Set(conn, collections.TemplatePartial{
+				Name: "layout",
+				Text: `{{template "content" .}} {{template "footer"}}`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(collections.TemplatePartial{
+				Name:      "layout",
+				Text:      `{{template "content" .}} {{template "footer"}}`,
+				UpdatedAt: updatedAt,
+			}))
+
+			Expect(partialsRepo.UpsertCall.Receives.Connection).To(Equal(conn))
+			Expect(partialsRepo.UpsertCall.Receives.Partial).To(Equal(models.TemplatePartial{
+				Name: "layout",
+				Text: `{{template "content" .}} {{template "footer"}}`,
+			}))
+		})
+
+		It("rejects partials that include partials that do not exist", func() {
+			_, err := collection.Set(conn, collections.TemplatePartial{Name: "header", Text: `{{template "logo"}}`})
+			Expect(err).To(MatchError(collections.TemplatePartialError{Err: errors.New(`partial "logo" does not exist`)}))
+			Expect(partialsRepo.UpsertCall.Receives.Partial).To(Equal(models.TemplatePartial{}))
+		})
+
+		It("rejects changes that make partials include each other", func() {
+			_, err := collection.Set(conn, collections.TemplatePartial{Name: "signature", Text: `{{template "footer"}}`})
+			Expect(err).To(MatchError(collections.TemplatePartialError{Err: errors.New(`partials form a cycle: "footer" -> "signature" -> "footer"`)}))
+		})
+
+		It("returns errors from the repo", func() {
+			partialsRepo.UpsertCall.Returns.Error = errors.New("BOOM!")
+
+			_, err := collection.Set(conn, collections.TemplatePartial{Name: "header", Text: "Hi"})
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the partial", func() {
+			err := collection.Delete(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partialsRepo.DestroyCall.Receives.Connection).To(Equal(conn))
+			Expect(partialsRepo.DestroyCall.Receives.Name).To(Equal("footer"))
+		})
+
+		It("refuses to delete a partial that other partials include", func() {
+			err := collection.Delete(conn, "signature")
+			Expect(err).To(MatchError(collections.TemplatePartialError{Err: errors.New(`partial "signature" does not exist`)}))
+			Expect(partialsRepo.DestroyCall.Receives.Name).To(BeEmpty())
+		})
+
+		It("refuses to delete a partial that templates include", func() {
+			templatesRepo.FindAllCall.Returns.Templates = []models.Template{
+				{ID: "template-1", Name: "Welcome", Text: `Hi {{template "footer"}}`},
+				{ID: "template-2", Name: "Plain", Text: "Hi"},
+			}
+
+			err := collection.Delete(conn, "footer")
+			Expect(err).To(MatchError(collections.TemplatePartialError{Err: errors.New(`partial "footer" is still used by templates: "Welcome" (template-1)`)}))
+			Expect(partialsRepo.DestroyCall.Receives.Name).To(BeEmpty())
+		})
+
+		It("refuses to delete a partial that templates use as their layout", func() {
+			templatesRepo.FindAllCall.Returns.Templates = []models.Template{
+				{ID: "template-1", Name: "Welcome", Text: "Hi", Layout: "footer"},
+			}
+
+			err := collection.Delete(conn, "footer")
+			Expect(err).To(MatchError(collections.TemplatePartialError{Err: errors.New(`partial "footer" is still used by templates: "Welcome" (template-1)`)}))
+		})
+
+		It("refuses to delete a partial that locale variants of templates include", func() {
+			templatesRepo.FindAllCall.Returns.Templates = []models.Template{
+				{ID: "template-1", Name: "Welcome", Text: "Hi"},
+			}
+			templateLocalesRepo.ListCall.Returns.Variants = []models.TemplateLocale{
+				{TemplateID: "template-1", Locale: "de", Text: `Hallo {{template "footer"}}`},
+			}
+
+			err := collection.Delete(conn, "footer")
+			Expect(err).To(MatchError(collections.TemplatePartialError{Err: errors.New(`partial "footer" is still used by templates: "Welcome" (template-1)`)}))
+			Expect(templateLocalesRepo.ListCall.Receives.TemplateID).To(Equal("template-1"))
+		})
+
+		It("returns errors from the templates repo", func() {
+			templatesRepo.FindAllCall.Returns.Error = errors.New("BOOM!")
+
+			err := collection.Delete(conn, "footer")
+			Expect(err).To(MatchError("BOOM!"))
+		})
+
+		It("returns errors from the repo", func() {
+			partialsRepo.DestroyCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			err := collection.Delete(conn, "missing")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+})
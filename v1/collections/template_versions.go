@@ -0,0 +1,266 @@
+package collections
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type templateVersionsFinder interface {
+	Create(connection models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error)
+	Find(connection models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error)
+	List(connection models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error)
+}
+
+type templatesUpdater interface {
+	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
+	Update(connection models.ConnectionInterface, templateID string, template models.Template) (models.Template, error)
+}
+
+type templatePartialsLister interface {
+	List(connection models.ConnectionInterface) ([]models.TemplatePartial, error)
+}
+
+type TemplateRollbackError struct {
+	Err error
+}
+
+func (e TemplateRollbackError) Error() string {
+	return e.Err.Error()
+}
+
+type TemplateVersion struct {
+	TemplateID string
+	Version    int
+	Name       string
+	Subject    string
+	Text       string
+	HTML       string
+	Markdown   string
+	Layout     string
+	Metadata   string
+	ClientID   string
+	CreatedAt  time.Time
+}
+
+// TemplateDiff holds a line diff of each field that differs between two
+// versions of a template. Fields that did not change are left empty.
+type TemplateDiff struct {
+	TemplateID string
+	From       int
+	To         int
+	Name       string
+	Subject    string
+	Text       string
+	HTML       string
+	Markdown   string
+	Layout     string
+	Metadata   string
+}
+
+type TemplateVersionsCollection struct {
+	templatesRepo templatesUpdater
+	versionsRepo  templateVersionsFinder
+	partialsRepo  templatePartialsLister
+	localesRepo   templateLocalesLister
+}
+
+func NewTemplateVersionsCollection(templatesRepo templatesUpdater, versionsRepo templateVersionsFinder, partialsRepo templatePartialsLister, localesRepo templateLocalesLister) TemplateVersionsCollection {
+	return TemplateVersionsCollection{
+		templatesRepo: templatesRepo,
+		versionsRepo:  versionsRepo,
+		partialsRepo:  partialsRepo,
+		localesRepo:   localesRepo,
+	}
+}
+
+func (c TemplateVersionsCollection) List(conn ConnectionInterface, templateID string) ([]TemplateVersion, error) {
+	_, err := c.templatesRepo.FindByID(conn, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := c.versionsRepo.List(conn, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []TemplateVersion{}
+	for _, version := range versions {
+		result = append(result, newTemplateVersion(version))
+	}
+
+	return result, nil
+}
+
+func (c TemplateVersionsCollection) Get(conn ConnectionInterface, templateID string, version int) (TemplateVersion, error) {
+	templateVersion, err := c.versionsRepo.Find(conn, templateID, version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return newTemplateVersion(templateVersion), nil
+}
+
+func (c TemplateVersionsCollection) Diff(conn ConnectionInterface, templateID string, from, to int) (TemplateDiff, error) {
+	fromVersion, err := c.versionsRepo.Find(conn, templateID, from)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+
+	toVersion, err := c.versionsRepo.Find(conn, templateID, to)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+
+	return TemplateDiff{
+		TemplateID: templateID,
+		From:       from,
+		To:         to,
+		Name:       lineDiff(fromVersion.Name, toVersion.Name),
+		Subject:    lineDiff(fromVersion.Subject, toVersion.Subject),
+		Text:       lineDiff(fromVersion.Text, toVersion.Text),
+		HTML:       lineDiff(fromVersion.HTML, toVersion.HTML),
+		Markdown:   lineDiff(fromVersion.Markdown, toVersion.Markdown),
+		Layout:     lineDiff(fromVersion.Layout, toVersion.Layout),
+		Metadata:   lineDiff(fromVersion.Metadata, toVersion.Metadata),
+	}, nil
+}
+
+// Rollback restores the contents of an earlier version. The restored template
+// is saved as a new version so that the history is never rewritten. As when a
+// template is created or updated, its layout and the partials it includes
+// must still exist and resolve. Locale variants are not part of a version, so
+// a version cannot be restored once variants have been saved against the
+// versions that replaced it. Both are checked while the template is locked by
+// the update, so that neither can change before the rollback is recorded.
+func (c TemplateVersionsCollection) Rollback(conn ConnectionInterface, templateID string, version int, clientID string) (TemplateVersion, error) {
+	previousVersion, err := c.versionsRepo.Find(conn, templateID, version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	transaction := conn.Transaction()
+
+	err = transaction.Begin()
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	template, err := c.templatesRepo.Update(transaction, templateID, models.Template{
+		Name:     previousVersion.Name,
+		Subject:  previousVersion.Subject,
+		Text:     previousVersion.Text,
+		HTML:     previousVersion.HTML,
+		Markdown: previousVersion.Markdown,
+		Layout:   previousVersion.Layout,
+		Metadata: previousVersion.Metadata,
+	})
+	if err != nil {
+		transaction.Rollback()
+		return TemplateVersion{}, err
+	}
+
+	err = c.validatePartials(transaction, previousVersion)
+	if err != nil {
+		transaction.Rollback()
+		return TemplateVersion{}, err
+	}
+
+	err = c.validateLocales(transaction, templateID, version)
+	if err != nil {
+		transaction.Rollback()
+		return TemplateVersion{}, err
+	}
+
+	restoredVersion, err := c.versionsRepo.Create(transaction, models.NewTemplateVersion(template, clientID))
+	if err != nil {
+		transaction.Rollback()
+		return TemplateVersion{}, err
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return newTemplateVersion(restoredVersion), nil
+}
+
+func (c TemplateVersionsCollection) validatePartials(conn models.ConnectionInterface, version models.TemplateVersion) error {
+	templates := common.Templates{
+		Text:     version.Text,
+		HTML:     version.HTML,
+		Markdown: version.Markdown,
+		Layout:   version.Layout,
+	}
+
+	if !common.UsesPartials(templates) {
+		return nil
+	}
+
+	partials, err := c.partialsRepo.List(conn)
+	if err != nil {
+		return err
+	}
+
+	byName := map[string]common.Partial{}
+	for _, partial := range partials {
+		byName[partial.Name] = common.Partial{
+			Name: partial.Name,
+			Text: partial.Text,
+			HTML: partial.HTML,
+		}
+	}
+
+	_, err = common.ResolvePartials(templates, byName)
+	if partialErr, ok := err.(common.PartialError); ok {
+		return TemplatePartialError{partialErr.Err}
+	}
+
+	return err
+}
+
+// validateLocales fails when locale variants of the template were saved
+// against a version newer than the one being restored: they were written for
+// that version and would no longer match the restored template. Variants that
+// were deleted since do not matter, as the restored template is then used in
+// their place.
+func (c TemplateVersionsCollection) validateLocales(conn models.ConnectionInterface, templateID string, version int) error {
+	variants, err := c.localesRepo.List(conn, templateID)
+	if err != nil {
+		return err
+	}
+
+	var changed []string
+	for _, variant := range variants {
+		if variant.TemplateVersion > version {
+			changed = append(changed, variant.Locale)
+		}
+	}
+
+	if len(changed) > 0 {
+		return TemplateRollbackError{fmt.Errorf("locale variants were saved against versions newer than %d: %s", version, strings.Join(changed, ", "))}
+	}
+
+	return nil
+}
+
+func newTemplateVersion(version models.TemplateVersion) TemplateVersion {
+	return TemplateVersion{
+		TemplateID: version.TemplateID,
+		Version:    version.Version,
+		Name:       version.Name,
+		Subject:    version.Subject,
+		Text:       version.Text,
+		HTML:       version.HTML,
+		Markdown:   version.Markdown,
+		Layout:     version.Layout,
+		Metadata:   version.Metadata,
+		ClientID:   version.ClientID,
+		CreatedAt:  version.CreatedAt,
+	}
+}
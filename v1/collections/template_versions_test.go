@@ -0,0 +1,350 @@
+package collections_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionsCollection", func() {
+	var (
+		templatesRepo *mocks.TemplatesRepo
+		versionsRepo  *mocks.TemplateVersionsRepo
+		partialsRepo  *mocks.TemplatePartialsRepo
+		localesRepo   *mocks.TemplateLocalesRepo
+		conn          *mocks.Connection
+		transaction   *mocks.Transaction
+		createdAt     time.Time
+
+		collection collections.TemplateVersionsCollection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
+		createdAt = time.Now().Truncate(time.Second)
+
+		templatesRepo = mocks.NewTemplatesRepo()
+		versionsRepo = mocks.NewTemplateVersionsRepo()
+		partialsRepo = mocks.NewTemplatePartialsRepo()
+		localesRepo = mocks.NewTemplateLocalesRepo()
+		versionsRepo.FindCall.Returns.Versions = []models.TemplateVersion{
+			{
+				TemplateID: "some-template-id",
+				Version:    1,
+				Name:       "Raptors",
+				Subject:    "{{.Subject}}",
+				Text:       "run\nhide",
+				HTML:       "<p>run</p>",
+				Markdown:   "# Raptors",
+				Metadata:   "{}",
+				ClientID:   "first-client",
+				CreatedAt:  createdAt,
+			},
+			{
+				TemplateID: "some-template-id",
+				Version:    2,
+				Name:       "Raptors",
+				Subject:    "Alert: {{.Subject}}",
+				Text:       "run\nclimb\nhide",
+				HTML:       "<p>run</p>",
+				Markdown:   "# Raptors\nrun",
+				Layout:     "branded",
+				Metadata:   "{}",
+				ClientID:   "second-client",
+				CreatedAt:  createdAt,
+			},
+		}
+
+		collection = collections.NewTemplateVersionsCollection(templatesRepo, versionsRepo, partialsRepo, localesRepo)
+	})
+
+	Describe("List", func() {
+		It("returns the versions of the template", func() {
+			versionsRepo.ListCall.Returns.Versions = []models.TemplateVersion{
+				{TemplateID: "some-template-id", Version: 2, ClientID: "second-client", CreatedAt: createdAt},
+				{TemplateID: "some-template-id", Version: 1, ClientID: "first-client", CreatedAt: createdAt},
+			}
+
+			versions, err := collection.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]collections.TemplateVersion{
+				{TemplateID: "some-template-id", Version: 2, ClientID: "second-client", CreatedAt: createdAt},
+				{TemplateID: "some-template-id", Version: 1, ClientID: "first-client", CreatedAt: createdAt},
+			}))
+
+			Expect(templatesRepo.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(versionsRepo.ListCall.Receives.Connection).To(Equal(conn))
+			Expect(versionsRepo.ListCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		It("returns an error when the template does not exist", func() {
+			templatesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.List(conn, "missing-template-id")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the requested version", func() {
+			version, err := collection.Get(conn, "some-template-id", 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Version).To(Equal(2))
+			Expect(version.Subject).To(Equal("Alert: {{.Subject}}"))
+			Expect(version.ClientID).To(Equal("second-client"))
+
+			Expect(versionsRepo.FindCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(versionsRepo.FindCall.Receives.Versions).To(Equal([]int{2}))
+		})
+
+		It("propagates errors from the repo", func() {
+			versionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.Get(conn, "some-template-id", 7)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("Diff", func() {
+		It("returns a line diff of the fields that changed", func() {
+			diff, err := collection.Diff(conn, "some-template-id", 1, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(Equal(collections.TemplateDiff{
+				TemplateID: "some-template-id",
+				From:       1,
+				To:         2,
+				Subject:    "-{{.Subject}}\n+Alert: {{.Subject}}",
+				Text:       " run\n+climb\n hide",
+				Markdown:   " # Raptors\n+run",
+				Layout:     "-\n+branded",
+			}))
+		})
+
+		It("can diff in either direction", func() {
+			diff, err := collection.Diff(conn, "some-template-id", 2, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.Text).To(Equal(" run\n-climb\n hide"))
+		})
+
+		Context("when the fields are large", func() {
+			numberedLines := func(prefix string, count int) []string {
+				var lines []string
+				for i := 0; i < count; i++ {
+					lines = append(lines, fmt.Sprintf("%s %d", prefix, i))
+				}
+				return lines
+			}
+
+			It("diffs a small change to a large field line by line", func() {
+				lines := numberedLines("line", 5000)
+				versionsRepo.FindCall.Returns.Versions[0].Text = strings.Join(lines, "\n")
+				lines[2500] = "changed"
+				versionsRepo.FindCall.Returns.Versions[1].Text = strings.Join(lines, "\n")
+
+				diff, err := collection.Diff(conn, "some-template-id", 1, 2)
+				Expect(err).NotTo(HaveOccurred())
+
+				diffLines := strings.Split(diff.Text, "\n")
+				Expect(diffLines).To(HaveLen(5001))
+				Expect(diffLines[2499]).To(Equal(" line 2499"))
+				Expect(diffLines[2500]).To(Equal("-line 2500"))
+				Expect(diffLines[2501]).To(Equal("+changed"))
+				Expect(diffLines[2502]).To(Equal(" line 2501"))
+			})
+
+			It("shows a large rewrite as the old lines replaced by the new ones", func() {
+				versionsRepo.FindCall.Returns.Versions[0].Text = "title\n" + strings.Join(numberedLines("old", 2000), "\n")
+				versionsRepo.FindCall.Returns.Versions[1].Text = "title\n" + strings.Join(numberedLines("new", 2000), "\n")
+
+				diff, err := collection.Diff(conn, "some-template-id", 1, 2)
+				Expect(err).NotTo(HaveOccurred())
+
+				diffLines := strings.Split(diff.Text, "\n")
+				Expect(diffLines).To(HaveLen(4001))
+				Expect(diffLines[0]).To(Equal(" title"))
+				Expect(diffLines[1]).To(Equal("-old 0"))
+				Expect(diffLines[2000]).To(Equal("-old 1999"))
+				Expect(diffLines[2001]).To(Equal("+new 0"))
+				Expect(diffLines[4000]).To(Equal("+new 1999"))
+			})
+		})
+
+		It("propagates errors from the repo", func() {
+			versionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.Diff(conn, "some-template-id", 1, 5)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("Rollback", func() {
+		BeforeEach(func() {
+			templatesRepo.UpdateCall.Returns.Template = models.Template{
+				ID:       "some-template-id",
+				Name:     "Raptors",
+				Subject:  "{{.Subject}}",
+				Text:     "run\nhide",
+				HTML:     "<p>run</p>",
+				Markdown: "# Raptors",
+				Metadata: "{}",
+				Version:  3,
+			}
+			versionsRepo.CreateCall.Returns.Version = models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Version:    3,
+				ClientID:   "rollback-client",
+			}
+		})
+
+		It("restores the contents of the version as a new version", func() {
+			version, err := collection.Rollback(conn, "some-template-id", 1, "rollback-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Version).To(Equal(3))
+
+			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{
+				Name:     "Raptors",
+				Subject:  "{{.Subject}}",
+				Text:     "run\nhide",
+				HTML:     "<p>run</p>",
+				Markdown: "# Raptors",
+				Metadata: "{}",
+			}))
+
+			Expect(versionsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(versionsRepo.CreateCall.Receives.Version).To(Equal(models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Version:    3,
+				Name:       "Raptors",
+				Subject:    "{{.Subject}}",
+				Text:       "run\nhide",
+				HTML:       "<p>run</p>",
+				Markdown:   "# Raptors",
+				Metadata:   "{}",
+				ClientID:   "rollback-client",
+			}))
+
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			Expect(partialsRepo.ListCall.CallCount).To(Equal(0))
+		})
+
+		It("restores a version whose layout still exists", func() {
+			partialsRepo.ListCall.Returns.Partials = []models.TemplatePartial{
+				{Name: "branded", Text: `{{template "content" .}}`, HTML: `<div>{{template "content" .}}</div>`},
+			}
+
+			_, err := collection.Rollback(conn, "some-template-id", 2, "rollback-client")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(partialsRepo.ListCall.Receives.Connection).To(Equal(transaction))
+			Expect(templatesRepo.UpdateCall.Receives.Template.Layout).To(Equal("branded"))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("refuses to restore a version whose layout no longer exists", func() {
+			_, err := collection.Rollback(conn, "some-template-id", 2, "rollback-client")
+			Expect(err).To(MatchError(collections.TemplatePartialError{Err: errors.New(`layout "branded" does not exist`)}))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("refuses to restore a version whose partials no longer exist", func() {
+			versionsRepo.FindCall.Returns.Versions[0].Text = `run {{template "signature"}}`
+
+			_, err := collection.Rollback(conn, "some-template-id", 1, "rollback-client")
+			Expect(err).To(MatchError(collections.TemplatePartialError{Err: errors.New(`partial "signature" does not exist`)}))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("returns an error when the partials cannot be loaded", func() {
+			partialsRepo.ListCall.Returns.Error = errors.New("boom")
+
+			_, err := collection.Rollback(conn, "some-template-id", 2, "rollback-client")
+			Expect(err).To(MatchError(errors.New("boom")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("restores a version whose locale variants were saved against it or an earlier version", func() {
+			partialsRepo.ListCall.Returns.Partials = []models.TemplatePartial{
+				{Name: "branded", Text: `{{template "content" .}}`, HTML: `<div>{{template "content" .}}</div>`},
+			}
+			localesRepo.ListCall.Returns.Variants = []models.TemplateLocale{
+				{TemplateID: "some-template-id", TemplateVersion: 1, Locale: "de", UpdatedAt: createdAt},
+				{TemplateID: "some-template-id", TemplateVersion: 2, Locale: "fr", UpdatedAt: createdAt},
+			}
+
+			_, err := collection.Rollback(conn, "some-template-id", 2, "rollback-client")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(localesRepo.ListCall.Receives.Connection).To(Equal(transaction))
+			Expect(localesRepo.ListCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("refuses to restore a version once locale variants were saved against a newer version", func() {
+			localesRepo.ListCall.Returns.Variants = []models.TemplateLocale{
+				{TemplateID: "some-template-id", TemplateVersion: 2, Locale: "de", UpdatedAt: createdAt},
+				{TemplateID: "some-template-id", TemplateVersion: 1, Locale: "es", UpdatedAt: createdAt},
+				{TemplateID: "some-template-id", TemplateVersion: 3, Locale: "fr", UpdatedAt: createdAt},
+			}
+
+			_, err := collection.Rollback(conn, "some-template-id", 1, "rollback-client")
+			Expect(err).To(MatchError(collections.TemplateRollbackError{Err: errors.New("locale variants were saved against versions newer than 1: de, fr")}))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("tells apart variants saved against consecutive versions within the same second", func() {
+			localesRepo.ListCall.Returns.Variants = []models.TemplateLocale{
+				{TemplateID: "some-template-id", TemplateVersion: 1, Locale: "de", UpdatedAt: createdAt},
+			}
+
+			_, err := collection.Rollback(conn, "some-template-id", 1, "rollback-client")
+			Expect(err).NotTo(HaveOccurred())
+
+			localesRepo.ListCall.Returns.Variants[0].TemplateVersion = 2
+
+			_, err = collection.Rollback(conn, "some-template-id", 1, "rollback-client")
+			Expect(err).To(MatchError(collections.TemplateRollbackError{Err: errors.New("locale variants were saved against versions newer than 1: de")}))
+		})
+
+		It("returns an error when the locale variants cannot be loaded", func() {
+			localesRepo.ListCall.Returns.Error = errors.New("boom")
+
+			_, err := collection.Rollback(conn, "some-template-id", 1, "rollback-client")
+			Expect(err).To(MatchError(errors.New("boom")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("returns an error when the version does not exist", func() {
+			versionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.Rollback(conn, "some-template-id", 9, "rollback-client")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+			Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+		})
+
+		It("rolls back the transaction when the version cannot be recorded", func() {
+			versionsRepo.CreateCall.Returns.Error = errors.New("boom")
+
+			_, err := collection.Rollback(conn, "some-template-id", 1, "rollback-client")
+			Expect(err).To(MatchError(errors.New("boom")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+	})
+})
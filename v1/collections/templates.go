@@ -25,21 +25,34 @@ func (e TemplateAssignmentError) Error() string {
 type clientsRepository interface {
 	Find(connection models.ConnectionInterface, clientID string) (models.Client, error)
 	FindAllByTemplateID(connection models.ConnectionInterface, templateID string) ([]models.Client, error)
+	List(connection models.ConnectionInterface, filter models.ClientsFilter, page models.Page) ([]models.Client, error)
 	Update(connection models.ConnectionInterface, client models.Client) (models.Client, error)
 }
 
 type kindsRepository interface {
 	Find(connection models.ConnectionInterface, kindID string, clientID string) (models.Kind, error)
 	FindAllByTemplateID(connection models.ConnectionInterface, templateID string) ([]models.Kind, error)
+	List(connection models.ConnectionInterface, filter models.KindsFilter, page models.Page) ([]models.Kind, error)
 	Update(connection models.ConnectionInterface, kind models.Kind) (models.Kind, error)
 }
 
 type templatesRepository interface {
 	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
+	FindAll(connection models.ConnectionInterface) ([]models.Template, error)
+	Update(connection models.ConnectionInterface, templateID string, template models.Template) (models.Template, error)
 	Create(connection models.ConnectionInterface, template models.Template) (models.Template, error)
 	Destroy(connection models.ConnectionInterface, templateID string) error
 }
 
+type templateVersionsRepository interface {
+	Create(connection models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error)
+	DestroyAll(connection models.ConnectionInterface, templateID string) error
+}
+
+type templateLocalesDestroyer interface {
+	DestroyAll(connection models.ConnectionInterface, templateID string) error
+}
+
 type TemplateAssociation struct {
 	ClientID       string
 	NotificationID string
@@ -50,6 +63,8 @@ type Template struct {
 	Name     string
 	Text     string
 	HTML     string
+	Markdown string
+	Layout   string
 	Subject  string
 	Metadata string
 }
@@ -58,13 +73,17 @@ type TemplatesCollection struct {
 	clientsRepo   clientsRepository
 	kindsRepo     kindsRepository
 	templatesRepo templatesRepository
+	versionsRepo  templateVersionsRepository
+	localesRepo   templateLocalesDestroyer
 }
 
-func NewTemplatesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, templatesRepo templatesRepository) TemplatesCollection {
+func NewTemplatesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, templatesRepo templatesRepository, versionsRepo templateVersionsRepository, localesRepo templateLocalesDestroyer) TemplatesCollection {
 	return TemplatesCollection{
 		clientsRepo:   clientsRepo,
 		kindsRepo:     kindsRepo,
 		templatesRepo: templatesRepo,
+		versionsRepo:  versionsRepo,
+		localesRepo:   localesRepo,
 	}
 }
 
@@ -139,14 +158,94 @@ func (c TemplatesCollection) findTemplate(conn ConnectionInterface, templateID s
 	return nil
 }
 
-func (c TemplatesCollection) ListAssociations(conn ConnectionInterface, templateID string) ([]TemplateAssociation, error) {
+// Associations are listed clients first, then notifications. Their page
+// keys are prefixed with the kind of association they end on.
+const (
+	associationKeyClient       = "client"
+	associationKeyNotification = "notification"
+)
+
+// ListAssociations returns the clients and notifications using the template
+// on the given page, along with the key of the page that follows, which is
+// nil on the last page.
+func (c TemplatesCollection) ListAssociations(conn ConnectionInterface, templateID string, page models.Page) ([]TemplateAssociation, []string, error) {
 	associations := []TemplateAssociation{}
 
 	_, err := c.templatesRepo.FindByID(conn, templateID)
 	if err != nil {
-		return associations, err
+		return associations, nil, err
+	}
+
+	if !page.Paginated() {
+		associations, err = c.findAssociations(conn, templateID)
+		return associations, nil, err
+	}
+
+	var clientsAfter, kindsAfter []string
+	if len(page.After) > 0 {
+		switch page.After[0] {
+		case associationKeyClient:
+			clientsAfter = page.After[1:]
+		case associationKeyNotification:
+			kindsAfter = page.After[1:]
+		default:
+			return associations, nil, models.NewPageKeyError()
+		}
 	}
 
+	if kindsAfter == nil {
+		clients, err := c.clientsRepo.List(conn, models.ClientsFilter{TemplateID: templateID}, models.Page{
+			After: clientsAfter,
+			Limit: page.Limit,
+		}.Peek())
+		if err != nil {
+			return associations, nil, err
+		}
+
+		for _, client := range clients {
+			associations = append(associations, TemplateAssociation{
+				ClientID: client.ID,
+			})
+		}
+	}
+
+	if !page.More(len(associations)) {
+		kindsPage := models.Page{After: kindsAfter}
+		if page.Limit > 0 {
+			kindsPage.Limit = page.Limit - len(associations) + 1
+		}
+
+		kinds, err := c.kindsRepo.List(conn, models.KindsFilter{TemplateID: templateID}, kindsPage)
+		if err != nil {
+			return associations, nil, err
+		}
+
+		for _, kind := range kinds {
+			associations = append(associations, TemplateAssociation{
+				ClientID:       kind.ClientID,
+				NotificationID: kind.ID,
+			})
+		}
+	}
+
+	var next []string
+	if page.More(len(associations)) {
+		associations = associations[:page.Limit]
+
+		last := associations[len(associations)-1]
+		if last.NotificationID == "" {
+			next = []string{associationKeyClient, last.ClientID}
+		} else {
+			next = []string{associationKeyNotification, last.ClientID, last.NotificationID}
+		}
+	}
+
+	return associations, next, nil
+}
+
+func (c TemplatesCollection) findAssociations(conn ConnectionInterface, templateID string) ([]TemplateAssociation, error) {
+	associations := []TemplateAssociation{}
+
 	clients, err := c.clientsRepo.FindAllByTemplateID(conn, templateID)
 	if err != nil {
 		return associations, err
@@ -173,14 +272,35 @@ func (c TemplatesCollection) ListAssociations(conn ConnectionInterface, template
 	return associations, nil
 }
 
-func (c TemplatesCollection) Create(connection ConnectionInterface, template Template) (Template, error) {
-	tmpl, err := c.templatesRepo.Create(connection, models.Template{
+func (c TemplatesCollection) Create(connection ConnectionInterface, template Template, clientID string) (Template, error) {
+	transaction := connection.Transaction()
+
+	err := transaction.Begin()
+	if err != nil {
+		return Template{}, err
+	}
+
+	tmpl, err := c.templatesRepo.Create(transaction, models.Template{
 		Name:     template.Name,
 		Text:     template.Text,
 		HTML:     template.HTML,
+		Markdown: template.Markdown,
+		Layout:   template.Layout,
 		Subject:  template.Subject,
 		Metadata: template.Metadata,
 	})
+	if err != nil {
+		transaction.Rollback()
+		return Template{}, err
+	}
+
+	_, err = c.versionsRepo.Create(transaction, models.NewTemplateVersion(tmpl, clientID))
+	if err != nil {
+		transaction.Rollback()
+		return Template{}, err
+	}
+
+	err = transaction.Commit()
 	if err != nil {
 		return Template{}, err
 	}
@@ -190,11 +310,40 @@ func (c TemplatesCollection) Create(connection ConnectionInterface, template Tem
 		Name:     tmpl.Name,
 		Text:     tmpl.Text,
 		HTML:     tmpl.HTML,
+		Markdown: tmpl.Markdown,
+		Layout:   tmpl.Layout,
 		Subject:  tmpl.Subject,
 		Metadata: tmpl.Metadata,
 	}, nil
 }
 
+// Delete removes the template along with its versions and locale variants,
+// all or nothing.
 func (c TemplatesCollection) Delete(connection ConnectionInterface, templateID string) error {
-	return c.templatesRepo.Destroy(connection, templateID)
+	transaction := connection.Transaction()
+
+	err := transaction.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = c.templatesRepo.Destroy(transaction, templateID)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	err = c.versionsRepo.DestroyAll(transaction, templateID)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	err = c.localesRepo.DestroyAll(transaction, templateID)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	return transaction.Commit()
 }
@@ -16,19 +16,26 @@ var _ = Describe("TemplatesCollection", func() {
 		kindsRepo     *mocks.KindsRepo
 		clientsRepo   *mocks.ClientsRepository
 		templatesRepo *mocks.TemplatesRepo
+		versionsRepo  *mocks.TemplateVersionsRepo
+		localesRepo   *mocks.TemplateLocalesRepo
 		conn          *mocks.Connection
+		transaction   *mocks.Transaction
 
 		collection collections.TemplatesCollection
 	)
 
 	BeforeEach(func() {
 		conn = mocks.NewConnection()
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
 
 		clientsRepo = mocks.NewClientsRepository()
 		kindsRepo = mocks.NewKindsRepo()
 		templatesRepo = mocks.NewTemplatesRepo()
+		versionsRepo = mocks.NewTemplateVersionsRepo()
+		localesRepo = mocks.NewTemplateLocalesRepo()
 
-		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo)
+		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, versionsRepo, localesRepo)
 	})
 
 	Describe("AssignToClient", func() {
@@ -274,7 +281,7 @@ var _ = Describe("TemplatesCollection", func() {
 			})
 
 			It("returns the full list of associations", func() {
-				associations, err := collection.ListAssociations(conn, "some-template-id")
+				associations, next, err := collection.ListAssociations(conn, "some-template-id", models.Page{})
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(associations).To(Equal([]collections.TemplateAssociation{
@@ -290,17 +297,91 @@ var _ = Describe("TemplatesCollection", func() {
 						NotificationID: "another-notification",
 					},
 				}))
+				Expect(next).To(BeNil())
 				Expect(templatesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
 				Expect(templatesRepo.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
 			})
 		})
 
+		Context("when the associations are paginated", func() {
+			BeforeEach(func() {
+				clientsRepo.ListCall.Returns.Clients = []models.Client{
+					{ID: "client-a"},
+					{ID: "client-b"},
+				}
+
+				kindsRepo.ListCall.Returns.Kinds = []models.Kind{
+					{ID: "some-notification", ClientID: "client-a"},
+				}
+			})
+
+			It("lists the clients, then the notifications, up to the limit", func() {
+				associations, next, err := collection.ListAssociations(conn, "some-template-id", models.Page{Limit: 2})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(associations).To(Equal([]collections.TemplateAssociation{
+					{ClientID: "client-a"},
+					{ClientID: "client-b"},
+				}))
+				Expect(next).To(Equal([]string{"client", "client-b"}))
+
+				Expect(clientsRepo.ListCall.Receives.Filter).To(Equal(models.ClientsFilter{TemplateID: "some-template-id"}))
+				Expect(clientsRepo.ListCall.Receives.Page).To(Equal(models.Page{Limit: 3}))
+				Expect(kindsRepo.ListCall.Receives.Filter).To(Equal(models.KindsFilter{TemplateID: "some-template-id"}))
+				Expect(kindsRepo.ListCall.Receives.Page).To(Equal(models.Page{Limit: 1}))
+			})
+
+			It("keys pages ending on a notification by its client and ID", func() {
+				clientsRepo.ListCall.Returns.Clients = []models.Client{{ID: "client-b"}}
+				kindsRepo.ListCall.Returns.Kinds = []models.Kind{
+					{ID: "some-notification", ClientID: "client-a"},
+					{ID: "another-notification", ClientID: "client-a"},
+				}
+
+				associations, next, err := collection.ListAssociations(conn, "some-template-id", models.Page{Limit: 2})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(associations).To(Equal([]collections.TemplateAssociation{
+					{ClientID: "client-b"},
+					{ClientID: "client-a", NotificationID: "some-notification"},
+				}))
+				Expect(next).To(Equal([]string{"notification", "client-a", "some-notification"}))
+			})
+
+			It("resumes after the page key", func() {
+				associations, next, err := collection.ListAssociations(conn, "some-template-id", models.Page{
+					After: []string{"notification", "client-a", "first-notification"},
+					Limit: 2,
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(associations).To(Equal([]collections.TemplateAssociation{
+					{ClientID: "client-a", NotificationID: "some-notification"},
+				}))
+				Expect(next).To(BeNil())
+
+				Expect(clientsRepo.ListCall.Receives.Connection).To(BeNil())
+				Expect(kindsRepo.ListCall.Receives.Page).To(Equal(models.Page{
+					After: []string{"client-a", "first-notification"},
+					Limit: 3,
+				}))
+			})
+
+			It("rejects page keys of another listing", func() {
+				_, _, err := collection.ListAssociations(conn, "some-template-id", models.Page{
+					After: []string{"some-template-id"},
+					Limit: 2,
+				})
+				Expect(err).To(MatchError(models.NewPageKeyError()))
+			})
+		})
+
 		Context("when errors occur", func() {
 			Context("when the clients repo returns an error", func() {
 				It("returns the underlying error", func() {
 					clientsRepo.FindAllByTemplateIDCall.Returns.Error = errors.New("something bad happened")
 
-					_, err := collection.ListAssociations(conn, "some-template-id")
+					_, _, err := collection.ListAssociations(conn, "some-template-id", models.Page{})
 					Expect(err).To(MatchError(errors.New("something bad happened")))
 				})
 			})
@@ -309,7 +390,7 @@ var _ = Describe("TemplatesCollection", func() {
 				It("returns the underlying error", func() {
 					kindsRepo.FindAllByTemplateIDCall.Returns.Error = errors.New("more bad happened")
 
-					_, err := collection.ListAssociations(conn, "some-template-id")
+					_, _, err := collection.ListAssociations(conn, "some-template-id", models.Page{})
 					Expect(err).To(MatchError(errors.New("more bad happened")))
 				})
 			})
@@ -318,7 +399,7 @@ var _ = Describe("TemplatesCollection", func() {
 				It("returns the underlying error", func() {
 					templatesRepo.FindByIDCall.Returns.Error = errors.New("something terrible happened")
 
-					_, err := collection.ListAssociations(conn, "some-template-id")
+					_, _, err := collection.ListAssociations(conn, "some-template-id", models.Page{})
 					Expect(err).To(MatchError(errors.New("something terrible happened")))
 				})
 			})
@@ -332,6 +413,8 @@ var _ = Describe("TemplatesCollection", func() {
 				Name:     "some-template-name",
 				Text:     "some-text",
 				HTML:     "some-html",
+				Markdown: "some-markdown",
+				Layout:   "some-layout",
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
 			}
@@ -340,34 +423,72 @@ var _ = Describe("TemplatesCollection", func() {
 				Name:     "some-template-name",
 				Text:     "some-text",
 				HTML:     "some-html",
+				Markdown: "some-markdown",
+				Layout:   "some-layout",
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
-			})
+			}, "some-client-id")
 			Expect(err).ToNot(HaveOccurred())
 			Expect(template).To(Equal(collections.Template{
 				ID:       "some-template-guid",
 				Name:     "some-template-name",
 				Text:     "some-text",
 				HTML:     "some-html",
+				Markdown: "some-markdown",
+				Layout:   "some-layout",
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
 			}))
 
-			Expect(templatesRepo.CreateCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.CreateCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesRepo.CreateCall.Receives.Template).To(Equal(models.Template{
 				Name:     "some-template-name",
 				Text:     "some-text",
 				HTML:     "some-html",
+				Markdown: "some-markdown",
+				Layout:   "some-layout",
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
 			}))
+
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("records the new template as its first version", func() {
+			templatesRepo.CreateCall.Returns.Template = models.Template{
+				ID:      "some-template-guid",
+				Name:    "some-template-name",
+				Version: 1,
+			}
+
+			_, err := collection.Create(conn, collections.Template{Name: "some-template-name"}, "some-client-id")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(versionsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(versionsRepo.CreateCall.Receives.Version).To(Equal(models.TemplateVersion{
+				TemplateID: "some-template-guid",
+				Version:    1,
+				Name:       "some-template-name",
+				ClientID:   "some-client-id",
+			}))
 		})
 
 		It("propagates errors from repo", func() {
 			templatesRepo.CreateCall.Returns.Error = errors.New("Boom!")
 
-			_, err := collection.Create(conn, collections.Template{})
+			_, err := collection.Create(conn, collections.Template{}, "some-client-id")
 			Expect(err).To(Equal(errors.New("Boom!")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		})
+
+		It("does not create the template when its version cannot be recorded", func() {
+			versionsRepo.CreateCall.Returns.Error = errors.New("version boom")
+
+			_, err := collection.Create(conn, collections.Template{}, "some-client-id")
+			Expect(err).To(Equal(errors.New("version boom")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
 		})
 	})
 
@@ -376,15 +497,58 @@ var _ = Describe("TemplatesCollection", func() {
 			err := collection.Delete(conn, "templateID")
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(templatesRepo.DestroyCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.DestroyCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesRepo.DestroyCall.Receives.TemplateID).To(Equal("templateID"))
 		})
 
+		It("removes the versions of the template", func() {
+			err := collection.Delete(conn, "templateID")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsRepo.DestroyAllCall.Receives.Connection).To(Equal(transaction))
+			Expect(versionsRepo.DestroyAllCall.Receives.TemplateID).To(Equal("templateID"))
+		})
+
+		It("removes the locale variants of the template", func() {
+			err := collection.Delete(conn, "templateID")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(localesRepo.DestroyAllCall.Receives.Connection).To(Equal(transaction))
+			Expect(localesRepo.DestroyAllCall.Receives.TemplateID).To(Equal("templateID"))
+		})
+
+		It("removes everything within one transaction", func() {
+			err := collection.Delete(conn, "templateID")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+		})
+
 		It("returns an error if repo destroy returns an error", func() {
 			templatesRepo.DestroyCall.Returns.Error = errors.New("Boom!!")
 
 			err := collection.Delete(conn, "templateID")
 			Expect(err).To(MatchError(errors.New("Boom!!")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		})
+
+		It("keeps the template when its locale variants cannot be removed", func() {
+			localesRepo.DestroyAllCall.Returns.Error = errors.New("Boom!!")
+
+			err := collection.Delete(conn, "templateID")
+			Expect(err).To(MatchError(errors.New("Boom!!")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("returns an error if the transaction cannot begin", func() {
+			transaction.BeginCall.Returns.Error = errors.New("no transaction")
+
+			err := collection.Delete(conn, "templateID")
+			Expect(err).To(MatchError(errors.New("no transaction")))
+			Expect(templatesRepo.DestroyCall.Receives.TemplateID).To(BeEmpty())
 		})
 	})
 })
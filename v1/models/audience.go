@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+// Audience is a named list of targets owned by a client. The targets are
+// kept as the JSON they were given in and resolved only when the audience
+// is sent to.
+type Audience struct {
+	Primary   int       `db:"primary"`
+	ID        string    `db:"id"`
+	ClientID  string    `db:"client_id"`
+	Name      string    `db:"name"`
+	Targets   string    `db:"targets"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (a *Audience) PreInsert(s gorp.SqlExecutor) error {
+	a.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	a.UpdatedAt = a.CreatedAt
+
+	return nil
+}
+
+func (a *Audience) PreUpdate(s gorp.SqlExecutor) error {
+	a.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
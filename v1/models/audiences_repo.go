@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type AudiencesRepo struct {
+	generateID IDGeneratorFunc
+}
+
+func NewAudiencesRepo(guidGenerator IDGeneratorFunc) AudiencesRepo {
+	return AudiencesRepo{
+		generateID: guidGenerator,
+	}
+}
+
+func (repo AudiencesRepo) Create(conn ConnectionInterface, audience Audience) (Audience, error) {
+	id, err := repo.generateID()
+	if err != nil {
+		return Audience{}, err
+	}
+	audience.ID = id
+
+	err = conn.Insert(&audience)
+	if err != nil {
+		return Audience{}, err
+	}
+
+	return audience, nil
+}
+
+// Find returns the audience only when it is owned by the given client, so
+// that clients cannot see each other's audiences.
+func (repo AudiencesRepo) Find(conn ConnectionInterface, clientID, id string) (Audience, error) {
+	audience := Audience{}
+	err := conn.SelectOne(&audience, "SELECT * FROM `audiences` WHERE `client_id` = ? AND `id` = ?", clientID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Audience{}, NotFoundError{fmt.Errorf("Audience with ID %q could not be found", id)}
+		}
+		return Audience{}, err
+	}
+
+	return audience, nil
+}
+
+func (repo AudiencesRepo) List(conn ConnectionInterface, clientID string, page Page) ([]Audience, error) {
+	var query listQuery
+	query.where("`client_id` = ?", clientID)
+
+	statement, args, err := query.build("SELECT * FROM `audiences`", page, "id")
+	if err != nil {
+		return []Audience{}, err
+	}
+
+	audiences := []Audience{}
+	_, err = conn.Select(&audiences, statement, args...)
+	if err != nil {
+		return []Audience{}, err
+	}
+
+	return audiences, nil
+}
+
+func (repo AudiencesRepo) Update(conn ConnectionInterface, audience Audience) (Audience, error) {
+	existing, err := repo.Find(conn, audience.ClientID, audience.ID)
+	if err != nil {
+		return Audience{}, err
+	}
+
+	existing.Name = audience.Name
+	existing.Targets = audience.Targets
+
+	_, err = conn.Update(&existing)
+	if err != nil {
+		return Audience{}, err
+	}
+
+	return existing, nil
+}
+
+func (repo AudiencesRepo) Destroy(conn ConnectionInterface, clientID, id string) error {
+	audience, err := repo.Find(conn, clientID, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Delete(&audience)
+
+	return err
+}
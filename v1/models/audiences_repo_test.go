@@ -0,0 +1,121 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AudiencesRepo", func() {
+	var (
+		repo          models.AudiencesRepo
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid"}
+
+		repo = models.NewAudiencesRepo(guidGenerator.Generate)
+	})
+
+	It("creates an audience with a generated ID", func() {
+		audience, err := repo.Create(conn, models.Audience{
+			ClientID: "some-client",
+			Name:     "managers",
+			Targets:  `[{"scope":"sre"}]`,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(audience.ID).To(Equal("first-random-guid"))
+
+		found, err := repo.Find(conn, "some-client", "first-random-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Name).To(Equal("managers"))
+		Expect(found.Targets).To(Equal(`[{"scope":"sre"}]`))
+		Expect(found.CreatedAt).NotTo(BeZero())
+	})
+
+	It("does not find the audiences of another client", func() {
+		_, err := repo.Create(conn, models.Audience{ClientID: "some-client", Name: "managers", Targets: "[]"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = repo.Find(conn, "other-client", "first-random-guid")
+		Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+
+		audiences, err := repo.List(conn, "other-client", models.Page{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(audiences).To(BeEmpty())
+	})
+
+	It("lists the audiences of a client by ID", func() {
+		_, err := repo.Create(conn, models.Audience{ClientID: "some-client", Name: "sre", Targets: "[]"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = repo.Create(conn, models.Audience{ClientID: "some-client", Name: "managers", Targets: "[]"})
+		Expect(err).NotTo(HaveOccurred())
+
+		audiences, err := repo.List(conn, "some-client", models.Page{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(audiences).To(HaveLen(2))
+		Expect(audiences[0].ID).To(Equal("first-random-guid"))
+		Expect(audiences[1].ID).To(Equal("second-random-guid"))
+	})
+
+	It("returns the audiences after the page key, up to the limit", func() {
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid", "third-random-guid"}
+
+		for _, name := range []string{"sre", "managers", "auditors"} {
+			_, err := repo.Create(conn, models.Audience{ClientID: "some-client", Name: name, Targets: "[]"})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		audiences, err := repo.List(conn, "some-client", models.Page{
+			After: []string{"first-random-guid"},
+			Limit: 1,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(audiences).To(HaveLen(1))
+		Expect(audiences[0].Name).To(Equal("managers"))
+	})
+
+	It("rejects page keys of another listing", func() {
+		_, err := repo.List(conn, "some-client", models.Page{
+			After: []string{"some-client", "some-kind"},
+		})
+		Expect(err).To(BeAssignableToTypeOf(models.PageKeyError{}))
+	})
+
+	It("updates the name and targets of an audience", func() {
+		_, err := repo.Create(conn, models.Audience{ClientID: "some-client", Name: "managers", Targets: "[]"})
+		Expect(err).NotTo(HaveOccurred())
+
+		audience, err := repo.Update(conn, models.Audience{
+			ID:       "first-random-guid",
+			ClientID: "some-client",
+			Name:     "all managers",
+			Targets:  `[{"scope":"sre"}]`,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(audience.Name).To(Equal("all managers"))
+		Expect(audience.Targets).To(Equal(`[{"scope":"sre"}]`))
+	})
+
+	It("destroys an audience", func() {
+		_, err := repo.Create(conn, models.Audience{ClientID: "some-client", Name: "managers", Targets: "[]"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = repo.Destroy(conn, "some-client", "first-random-guid")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = repo.Find(conn, "some-client", "first-random-guid")
+		Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+	})
+})
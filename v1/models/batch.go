@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+// Batch records one notify request along with how far the messages it
+// queued have come. QueuedMessages counts the messages still waiting to be
+// sent; the batch is completed once none are left.
+type Batch struct {
+	Primary               int        `db:"primary"`
+	ID                    string     `db:"id"`
+	ClientID              string     `db:"client_id"`
+	KindID                string     `db:"kind_id"`
+	TotalMessages         int        `db:"total_messages"`
+	QueuedMessages        int        `db:"queued_messages"`
+	SentMessages          int        `db:"sent_messages"`
+	FailedMessages        int        `db:"failed_messages"`
+	UndeliverableMessages int        `db:"undeliverable_messages"`
+	ExpiredMessages       int        `db:"expired_messages"`
+	StartTime             time.Time  `db:"start_time"`
+	CompletedTime         *time.Time `db:"completed_time"`
+}
+
+func (b *Batch) PreInsert(s gorp.SqlExecutor) error {
+	b.StartTime = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
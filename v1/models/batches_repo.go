@@ -0,0 +1,122 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// batchCounters maps the message statuses a batch keeps count of to the
+// column counting them.
+var batchCounters = map[string]string{
+	"queued":        "queued_messages",
+	"delivered":     "sent_messages",
+	"failed":        "failed_messages",
+	"undeliverable": "undeliverable_messages",
+	"expired":       "expired_messages",
+}
+
+type BatchesRepo struct {
+	generateID IDGeneratorFunc
+}
+
+func NewBatchesRepo(guidGenerator IDGeneratorFunc) BatchesRepo {
+	return BatchesRepo{
+		generateID: guidGenerator,
+	}
+}
+
+func (repo BatchesRepo) Create(conn ConnectionInterface, batch Batch) (Batch, error) {
+	if batch.ID == "" {
+		var err error
+		batch.ID, err = repo.generateID()
+		if err != nil {
+			return Batch{}, err
+		}
+	}
+
+	err := conn.Insert(&batch)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	return batch, nil
+}
+
+func (repo BatchesRepo) Update(conn ConnectionInterface, batch Batch) (Batch, error) {
+	_, err := conn.Update(&batch)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	return batch, nil
+}
+
+func (repo BatchesRepo) Find(conn ConnectionInterface, id string) (Batch, error) {
+	batch := Batch{}
+	err := conn.SelectOne(&batch, "SELECT * FROM `batches` WHERE `id` = ?", id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Batch{}, NotFoundError{fmt.Errorf("Batch with ID %q could not be found", id)}
+		}
+		return Batch{}, err
+	}
+
+	return batch, nil
+}
+
+// BatchesFilter narrows a listing of batches to those of a client.
+type BatchesFilter struct {
+	ClientID string
+}
+
+// List returns the batches matching the filter, oldest first.
+func (repo BatchesRepo) List(conn ConnectionInterface, filter BatchesFilter, page Page) ([]Batch, error) {
+	var query listQuery
+	if filter.ClientID != "" {
+		query.where("`client_id` = ?", filter.ClientID)
+	}
+
+	statement, args, err := query.build("SELECT * FROM `batches`", page, "start_time", "id")
+	if err != nil {
+		return []Batch{}, err
+	}
+
+	batches := []Batch{}
+	_, err = conn.Select(&batches, statement, args...)
+	if err != nil {
+		return []Batch{}, err
+	}
+	return batches, nil
+}
+
+// Transition moves one message of the batch from the counter of its old
+// status to the counter of its new one, and completes the batch at the
+// given time when that leaves no message queued. Messages waiting to be
+// retried are to be counted as queued, and only counted as failed once they
+// are given up on, so that the batch does not complete while retries are
+// pending. Statuses the batch does not count are ignored.
+func (repo BatchesRepo) Transition(conn ConnectionInterface, id, from, to string, at time.Time) error {
+	var assignments []string
+
+	if column, ok := batchCounters[from]; ok {
+		assignments = append(assignments, fmt.Sprintf("`%[1]s` = IF(`%[1]s` > 0, `%[1]s` - 1, 0)", column))
+	}
+
+	if column, ok := batchCounters[to]; ok {
+		assignments = append(assignments, fmt.Sprintf("`%[1]s` = `%[1]s` + 1", column))
+	}
+
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	// MySQL assigns from left to right, so the completion check sees the
+	// counters as they were just updated.
+	assignments = append(assignments, "`completed_time` = IF(`completed_time` IS NULL AND `queued_messages` = 0, ?, `completed_time`)")
+
+	_, err := conn.Exec("UPDATE `batches` SET "+strings.Join(assignments, ", ")+" WHERE `id` = ?", at.UTC(), id)
+
+	return err
+}
@@ -0,0 +1,143 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BatchesRepo", func() {
+	var (
+		repo          models.BatchesRepo
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid"}
+
+		repo = models.NewBatchesRepo(guidGenerator.Generate)
+	})
+
+	It("creates a batch with a generated ID", func() {
+		batch, err := repo.Create(conn, models.Batch{ClientID: "some-client", KindID: "some-kind"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(batch.ID).To(Equal("first-random-guid"))
+
+		found, err := repo.Find(conn, "first-random-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.ClientID).To(Equal("some-client"))
+		Expect(found.KindID).To(Equal("some-kind"))
+		Expect(found.StartTime).NotTo(BeZero())
+		Expect(found.CompletedTime).To(BeNil())
+	})
+
+	It("keeps the ID it was given", func() {
+		batch, err := repo.Create(conn, models.Batch{ID: "some-batch-id", ClientID: "some-client"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(batch.ID).To(Equal("some-batch-id"))
+	})
+
+	It("returns a not found error for unknown batches", func() {
+		_, err := repo.Find(conn, "missing-batch-id")
+		Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+	})
+
+	Describe("Transition", func() {
+		BeforeEach(func() {
+			batch, err := repo.Create(conn, models.Batch{ClientID: "some-client"})
+			Expect(err).NotTo(HaveOccurred())
+
+			batch.TotalMessages = 2
+			batch.QueuedMessages = 2
+			_, err = repo.Update(conn, batch)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("moves messages between counters and completes the batch once none are queued", func() {
+			completedAt := time.Now().Truncate(time.Second).UTC()
+
+			Expect(repo.Transition(conn, "first-random-guid", "queued", "delivered", completedAt)).To(Succeed())
+
+			batch, err := repo.Find(conn, "first-random-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batch.QueuedMessages).To(Equal(1))
+			Expect(batch.SentMessages).To(Equal(1))
+			Expect(batch.CompletedTime).To(BeNil())
+
+			Expect(repo.Transition(conn, "first-random-guid", "queued", "failed", completedAt)).To(Succeed())
+
+			batch, err = repo.Find(conn, "first-random-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batch.QueuedMessages).To(Equal(0))
+			Expect(batch.FailedMessages).To(Equal(1))
+			Expect(batch.CompletedTime).NotTo(BeNil())
+			Expect(batch.CompletedTime.Equal(completedAt)).To(BeTrue())
+
+			Expect(repo.Transition(conn, "first-random-guid", "failed", "delivered", completedAt.Add(time.Hour))).To(Succeed())
+
+			batch, err = repo.Find(conn, "first-random-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batch.FailedMessages).To(Equal(0))
+			Expect(batch.SentMessages).To(Equal(2))
+			Expect(batch.CompletedTime.Equal(completedAt)).To(BeTrue())
+		})
+
+		It("ignores statuses it does not count", func() {
+			Expect(repo.Transition(conn, "first-random-guid", "unknown", "other", time.Now())).To(Succeed())
+
+			batch, err := repo.Find(conn, "first-random-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batch.QueuedMessages).To(Equal(2))
+		})
+	})
+
+	Describe("List", func() {
+		BeforeEach(func() {
+			_, err := repo.Create(conn, models.Batch{ClientID: "some-client"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Batch{ClientID: "other-client"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("lists every batch", func() {
+			batches, err := repo.List(conn, models.BatchesFilter{}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batches).To(HaveLen(2))
+		})
+
+		It("filters the batches by client", func() {
+			batches, err := repo.List(conn, models.BatchesFilter{ClientID: "other-client"}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batches).To(HaveLen(1))
+			Expect(batches[0].ID).To(Equal("second-random-guid"))
+		})
+
+		It("pages through the batches", func() {
+			batches, err := repo.List(conn, models.BatchesFilter{}, models.Page{Limit: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batches).To(HaveLen(1))
+
+			last := batches[0]
+			batches, err = repo.List(conn, models.BatchesFilter{}, models.Page{
+				Limit: 1,
+				After: []string{last.StartTime.UTC().Format("2006-01-02 15:04:05"), last.ID},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batches).To(HaveLen(1))
+			Expect(batches[0].ID).NotTo(Equal(last.ID))
+		})
+	})
+})
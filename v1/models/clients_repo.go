@@ -25,16 +25,6 @@ func (repo ClientsRepo) Find(conn ConnectionInterface, id string) (Client, error
 	return client, nil
 }
 
-func (repo ClientsRepo) FindAll(conn ConnectionInterface) ([]Client, error) {
-	clients := []Client{}
-	_, err := conn.Select(&clients, "SELECT * FROM `clients`")
-	if err != nil {
-		return []Client{}, err
-	}
-
-	return clients, nil
-}
-
 func (repo ClientsRepo) Update(conn ConnectionInterface, client Client) (Client, error) {
 	if client.TemplateID == DoNotSetTemplateID {
 		existingClient, err := repo.Find(conn, client.ID)
@@ -83,6 +73,64 @@ func (repo ClientsRepo) FindAllByTemplateID(conn ConnectionInterface, templateID
 	return clients, nil
 }
 
+// ClientsFilter narrows a listing of clients to those with the given IDs or
+// of a template. IDs is ignored when nil; an empty IDs matches no client.
+type ClientsFilter struct {
+	IDs        []string
+	TemplateID string
+}
+
+// List returns the clients matching the filter, ordered by ID.
+func (repo ClientsRepo) List(conn ConnectionInterface, filter ClientsFilter, page Page) ([]Client, error) {
+	var query listQuery
+	if filter.IDs != nil {
+		if len(filter.IDs) == 0 {
+			return []Client{}, nil
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(filter.IDs)), ", ")
+		var ids []interface{}
+		for _, id := range filter.IDs {
+			ids = append(ids, id)
+		}
+		query.where("`id` IN ("+placeholders+")", ids...)
+	}
+
+	if filter.TemplateID != "" {
+		query.where("`template_id` = ?", filter.TemplateID)
+	}
+
+	statement, args, err := query.build("SELECT * FROM `clients`", page, "id")
+	if err != nil {
+		return []Client{}, err
+	}
+
+	clients := []Client{}
+	_, err = conn.Select(&clients, statement, args...)
+	if err != nil {
+		return []Client{}, err
+	}
+	return clients, nil
+}
+
+// ListWithoutKinds returns the clients that have no kinds, ordered by ID.
+func (repo ClientsRepo) ListWithoutKinds(conn ConnectionInterface, page Page) ([]Client, error) {
+	var query listQuery
+	query.where("NOT EXISTS (SELECT 1 FROM `kinds` WHERE `kinds`.`client_id` = `clients`.`id`)")
+
+	statement, args, err := query.build("SELECT * FROM `clients`", page, "id")
+	if err != nil {
+		return []Client{}, err
+	}
+
+	clients := []Client{}
+	_, err = conn.Select(&clients, statement, args...)
+	if err != nil {
+		return []Client{}, err
+	}
+	return clients, nil
+}
+
 func (repo ClientsRepo) create(conn ConnectionInterface, client Client) (Client, error) {
 	err := conn.Insert(&client)
 	if err != nil {
@@ -26,37 +26,6 @@ var _ = Describe("ClientsRepo", func() {
 		conn = database.Connection()
 	})
 
-	Describe("FindAll", func() {
-		It("returns all the records in the database", func() {
-			client1 := models.Client{
-				ID:          "client1",
-				Description: "client1-description",
-			}
-
-			client2 := models.Client{
-				ID:          "client2",
-				Description: "client2-description",
-			}
-
-			firstClient, err := repo.Upsert(conn, client1)
-			if err != nil {
-				panic(err)
-			}
-
-			secondClient, err := repo.Upsert(conn, client2)
-			if err != nil {
-				panic(err)
-			}
-
-			clients, err := repo.FindAll(conn)
-			if err != nil {
-				panic(err)
-			}
-
-			Expect(clients).To(Equal([]models.Client{firstClient, secondClient}))
-		})
-	})
-
 	Describe("Update", func() {
 		Context("when the template id is meant to be updated", func() {
 			It("updates the record in the database", func() {
@@ -222,4 +191,70 @@ var _ = Describe("ClientsRepo", func() {
 			Expect(returnedClients).To(ContainElement(client1))
 		})
 	})
+
+	Describe("List", func() {
+		BeforeEach(func() {
+			for _, client := range []models.Client{
+				{ID: "client-b", TemplateID: "some-template-id"},
+				{ID: "client-a", TemplateID: "some-template-id"},
+				{ID: "client-c"},
+			} {
+				_, err := repo.Upsert(conn, client)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("returns the clients ordered by ID", func() {
+			clients, err := repo.List(conn, models.ClientsFilter{}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients).To(HaveLen(3))
+			Expect(clients[0].ID).To(Equal("client-a"))
+			Expect(clients[1].ID).To(Equal("client-b"))
+			Expect(clients[2].ID).To(Equal("client-c"))
+		})
+
+		It("returns the clients matching the filter", func() {
+			clients, err := repo.List(conn, models.ClientsFilter{IDs: []string{"client-c", "client-a"}}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients).To(HaveLen(2))
+			Expect(clients[0].ID).To(Equal("client-a"))
+			Expect(clients[1].ID).To(Equal("client-c"))
+
+			clients, err = repo.List(conn, models.ClientsFilter{IDs: []string{}}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients).To(BeEmpty())
+
+			clients, err = repo.List(conn, models.ClientsFilter{TemplateID: "some-template-id"}, models.Page{
+				After: []string{"client-a"},
+				Limit: 5,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients).To(HaveLen(1))
+			Expect(clients[0].ID).To(Equal("client-b"))
+		})
+	})
+
+	Describe("ListWithoutKinds", func() {
+		It("returns the clients that have no kinds, ordered by ID", func() {
+			for _, client := range []models.Client{{ID: "client-c"}, {ID: "client-b"}, {ID: "client-a"}, {ID: "client-d"}} {
+				_, err := repo.Upsert(conn, client)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := models.NewKindsRepo().Upsert(conn, models.Kind{ID: "some-kind", ClientID: "client-b"})
+			Expect(err).NotTo(HaveOccurred())
+
+			clients, err := repo.ListWithoutKinds(conn, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients).To(HaveLen(3))
+			Expect(clients[0].ID).To(Equal("client-a"))
+			Expect(clients[1].ID).To(Equal("client-c"))
+			Expect(clients[2].ID).To(Equal("client-d"))
+
+			clients, err = repo.ListWithoutKinds(conn, models.Page{After: []string{"client-a"}, Limit: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients).To(HaveLen(1))
+			Expect(clients[0].ID).To(Equal("client-c"))
+		})
+	})
 })
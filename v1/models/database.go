@@ -35,4 +35,14 @@ func Setup(database *db.DB) {
 	database.TableMap().AddTableWithName(GlobalUnsubscribe{}, "global_unsubscribes").SetKeys(true, "Primary").ColMap("UserID").SetUnique(true)
 	database.TableMap().AddTableWithName(Template{}, "templates").SetKeys(true, "Primary").ColMap("Name").SetUnique(true)
 	database.TableMap().AddTableWithName(Message{}, "messages").SetKeys(false, "ID")
+	database.TableMap().AddTableWithName(TemplateVersion{}, "template_versions").SetKeys(true, "Primary").SetUniqueTogether("template_id", "version")
+	database.TableMap().AddTableWithName(SenderIdentity{}, "sender_identities").SetKeys(true, "Primary").SetUniqueTogether("client_id", "kind_id")
+	database.TableMap().AddTableWithName(TemplateLocale{}, "template_locales").SetKeys(true, "Primary").SetUniqueTogether("template_id", "locale")
+	database.TableMap().AddTableWithName(TemplatePartial{}, "template_partials").SetKeys(true, "Primary").ColMap("Name").SetUnique(true)
+	database.TableMap().AddTableWithName(UserSettings{}, "user_settings").SetKeys(true, "Primary").ColMap("UserID").SetUnique(true)
+	database.TableMap().AddTableWithName(DigestPreference{}, "digest_preferences").SetKeys(true, "Primary").SetUniqueTogether("user_id", "client_id", "kind_id")
+	database.TableMap().AddTableWithName(DigestItem{}, "digest_items").SetKeys(true, "Primary")
+	database.TableMap().AddTableWithName(Audience{}, "audiences").SetKeys(true, "Primary").ColMap("ID").SetUnique(true)
+	database.TableMap().AddTableWithName(Batch{}, "batches").SetKeys(true, "Primary").ColMap("ID").SetUnique(true)
+	database.TableMap().AddTableWithName(DedupEntry{}, "dedup_entries").SetKeys(true, "Primary").SetUniqueTogether("recipient", "client_id", "kind_id", "dedup_key")
 }
@@ -27,6 +27,7 @@ func (d DatabaseMigrator) Migrate(db *sql.DB, migrationsPath string) {
 
 func (d DatabaseMigrator) Seed(database DatabaseInterface, defaultTemplatePath string) {
 	repo := NewTemplatesRepo()
+	versionsRepo := NewTemplateVersionsRepo()
 	bytes, err := ioutil.ReadFile(defaultTemplatePath)
 	if err != nil {
 		panic(err)
@@ -37,6 +38,7 @@ func (d DatabaseMigrator) Seed(database DatabaseInterface, defaultTemplatePath s
 		Subject  string          `json:"subject"`
 		Text     string          `json:"text"`
 		HTML     string          `json:"html"`
+		Markdown string          `json:"markdown"`
 		Metadata json.RawMessage `json:"metadata"`
 	}
 
@@ -52,31 +54,44 @@ func (d DatabaseMigrator) Seed(database DatabaseInterface, defaultTemplatePath s
 			panic(err)
 		}
 
-		_, err = repo.Create(conn, Template{
+		defaultTemplate, err := repo.Create(conn, Template{
 			ID:       DefaultTemplateID,
 			Name:     template.Name,
 			Subject:  template.Subject,
 			HTML:     template.HTML,
 			Text:     template.Text,
+			Markdown: template.Markdown,
 			Metadata: string(template.Metadata),
 		})
 		if err != nil {
 			panic(err)
 		}
 
+		_, err = versionsRepo.Create(conn, NewTemplateVersion(defaultTemplate, ""))
+		if err != nil {
+			panic(err)
+		}
+
 		return
 	}
 
-	if !existingTemplate.Overridden {
+	if !existingTemplate.Overridden && !existingTemplate.matches(template.Name, template.Subject, template.HTML, template.Text, template.Markdown, string(template.Metadata)) {
 		existingTemplate.Name = template.Name
 		existingTemplate.Subject = template.Subject
 		existingTemplate.HTML = template.HTML
 		existingTemplate.Text = template.Text
+		existingTemplate.Markdown = template.Markdown
 		existingTemplate.Metadata = string(template.Metadata)
 		existingTemplate.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+		existingTemplate.Version++
 		_, err = conn.Update(&existingTemplate)
 		if err != nil {
 			panic(err)
 		}
+
+		_, err = versionsRepo.Create(conn, NewTemplateVersion(existingTemplate, ""))
+		if err != nil {
+			panic(err)
+		}
 	}
 }
@@ -63,6 +63,11 @@ var _ = Describe("DatabaseMigrator", func() {
 			Expect(tables).To(ContainElement("unsubscribes"))
 			Expect(tables).To(ContainElement("global_unsubscribes"))
 			Expect(tables).To(ContainElement("templates"))
+			Expect(tables).To(ContainElement("template_versions"))
+			Expect(tables).To(ContainElement("template_locales"))
+			Expect(tables).To(ContainElement("user_settings"))
+			Expect(tables).To(ContainElement("digest_preferences"))
+			Expect(tables).To(ContainElement("digest_items"))
 		})
 	})
 
@@ -87,6 +92,17 @@ var _ = Describe("DatabaseMigrator", func() {
 			Expect(template.Metadata).To(Equal("{}"))
 		})
 
+		It("records the seeded template as its first version", func() {
+			dbMigrator.Seed(database, defaultTemplatePath)
+			dbMigrator.Seed(database, defaultTemplatePath)
+
+			versions, err := models.NewTemplateVersionsRepo().List(connection, models.DefaultTemplateID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(1))
+			Expect(versions[0].Version).To(Equal(1))
+			Expect(versions[0].Name).To(Equal("Default Template"))
+		})
+
 		It("can be called multiple times without panicking", func() {
 			Expect(func() {
 				dbMigrator.Seed(database, defaultTemplatePath)
@@ -121,6 +137,7 @@ var _ = Describe("DatabaseMigrator", func() {
 				Expect(template.Text).To(Equal("{{.Endorsement}}\n{{.Text}}"))
 				Expect(template.Metadata).To(Equal("{}"))
 				Expect(template.Overridden).To(BeFalse())
+				Expect(template.Version).To(Equal(2))
 			})
 		})
 
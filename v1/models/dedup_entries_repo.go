@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type DedupEntriesRepo struct{}
+
+func NewDedupEntriesRepo() DedupEntriesRepo {
+	return DedupEntriesRepo{}
+}
+
+// FindActive returns the entry for the recipient, client, kind and dedup key
+// when it has not expired at the given time.
+func (repo DedupEntriesRepo) FindActive(conn ConnectionInterface, entry DedupEntry, now time.Time) (DedupEntry, error) {
+	found := DedupEntry{}
+	err := conn.SelectOne(&found, "SELECT * FROM `dedup_entries` WHERE `recipient` = ? AND `client_id` = ? AND `kind_id` = ? AND `dedup_key` = ? AND `expires_at` > ?",
+		entry.Recipient, entry.ClientID, entry.KindID, entry.DedupKey, now.UTC())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DedupEntry{}, NotFoundError{fmt.Errorf("Dedup entry for %q could not be found", entry.Recipient)}
+		}
+		return DedupEntry{}, err
+	}
+
+	return found, nil
+}
+
+// Claim records the entry unless an entry for the same recipient, client,
+// kind and dedup key is still active at the given time, in which case it
+// returns that entry and false. The check and the write are one statement,
+// so concurrent claims of the same key cannot both succeed; an expired entry
+// is replaced.
+func (repo DedupEntriesRepo) Claim(conn ConnectionInterface, entry DedupEntry, now time.Time) (DedupEntry, bool, error) {
+	query := "INSERT INTO `dedup_entries` (`recipient`, `client_id`, `kind_id`, `dedup_key`, `message_id`, `expires_at`) VALUES (?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE `message_id` = IF(`expires_at` <= ?, VALUES(`message_id`), `message_id`), `expires_at` = IF(`expires_at` <= ?, VALUES(`expires_at`), `expires_at`)"
+	result, err := conn.Exec(query, entry.Recipient, entry.ClientID, entry.KindID, entry.DedupKey, entry.MessageID, entry.ExpiresAt.UTC(), now.UTC(), now.UTC())
+	if err != nil {
+		return DedupEntry{}, false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return DedupEntry{}, false, err
+	}
+
+	if affected > 0 {
+		return entry, true, nil
+	}
+
+	active, err := repo.FindActive(conn, entry, now)
+	if err != nil {
+		return DedupEntry{}, false, err
+	}
+
+	return active, false, nil
+}
+
+// SetMessageID records the message of an entry that has been claimed.
+func (repo DedupEntriesRepo) SetMessageID(conn ConnectionInterface, entry DedupEntry) error {
+	_, err := conn.Exec("UPDATE `dedup_entries` SET `message_id` = ? WHERE `recipient` = ? AND `client_id` = ? AND `kind_id` = ? AND `dedup_key` = ?",
+		entry.MessageID, entry.Recipient, entry.ClientID, entry.KindID, entry.DedupKey)
+
+	return err
+}
+
+// DeleteExpired removes the entries that have expired at the given time.
+func (repo DedupEntriesRepo) DeleteExpired(conn ConnectionInterface, now time.Time) (int, error) {
+	result, err := conn.Exec("DELETE FROM `dedup_entries` WHERE `expires_at` <= ?", now.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
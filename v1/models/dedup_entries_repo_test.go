@@ -0,0 +1,154 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DedupEntriesRepo", func() {
+	var (
+		repo  models.DedupEntriesRepo
+		conn  db.ConnectionInterface
+		now   time.Time
+		entry models.DedupEntry
+	)
+
+	BeforeEach(func() {
+		repo = models.NewDedupEntriesRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+		now = time.Date(2015, time.June, 8, 14, 0, 0, 0, time.UTC)
+
+		entry = models.DedupEntry{
+			Recipient: "user-1",
+			ClientID:  "some-client",
+			KindID:    "some-kind",
+			DedupKey:  "disk-full",
+		}
+	})
+
+	It("finds the entry until it expires", func() {
+		recorded := entry
+		recorded.MessageID = "message-1"
+		recorded.ExpiresAt = now.Add(5 * time.Minute)
+
+		_, claimed, err := repo.Claim(conn, recorded, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claimed).To(BeTrue())
+
+		found, err := repo.FindActive(conn, entry, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.MessageID).To(Equal("message-1"))
+
+		_, err = repo.FindActive(conn, entry, now.Add(5*time.Minute))
+		Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+	})
+
+	It("does not find the entry of another dedup key", func() {
+		recorded := entry
+		recorded.MessageID = "message-1"
+		recorded.ExpiresAt = now.Add(5 * time.Minute)
+
+		_, _, err := repo.Claim(conn, recorded, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		entry.DedupKey = "disk-slow"
+		_, err = repo.FindActive(conn, entry, now)
+		Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+	})
+
+	Describe("Claim", func() {
+		It("returns the active entry instead of replacing it", func() {
+			first := entry
+			first.MessageID = "message-1"
+			first.ExpiresAt = now.Add(5 * time.Minute)
+
+			_, claimed, err := repo.Claim(conn, first, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claimed).To(BeTrue())
+
+			second := entry
+			second.MessageID = "message-2"
+			second.ExpiresAt = now.Add(6 * time.Minute)
+
+			active, claimed, err := repo.Claim(conn, second, now.Add(time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claimed).To(BeFalse())
+			Expect(active.MessageID).To(Equal("message-1"))
+			Expect(active.ExpiresAt).To(BeTemporally("==", now.Add(5*time.Minute)))
+		})
+
+		It("replaces an expired entry", func() {
+			recorded := entry
+			recorded.MessageID = "message-1"
+			recorded.ExpiresAt = now.Add(-time.Minute)
+
+			_, _, err := repo.Claim(conn, recorded, now.Add(-5*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+
+			recorded.MessageID = "message-2"
+			recorded.ExpiresAt = now.Add(5 * time.Minute)
+
+			_, claimed, err := repo.Claim(conn, recorded, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claimed).To(BeTrue())
+
+			found, err := repo.FindActive(conn, entry, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.MessageID).To(Equal("message-2"))
+		})
+	})
+
+	Describe("SetMessageID", func() {
+		It("records the message of a claimed entry", func() {
+			claimed := entry
+			claimed.ExpiresAt = now.Add(5 * time.Minute)
+
+			_, _, err := repo.Claim(conn, claimed, now)
+			Expect(err).NotTo(HaveOccurred())
+
+			claimed.MessageID = "message-1"
+			err = repo.SetMessageID(conn, claimed)
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err := repo.FindActive(conn, entry, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.MessageID).To(Equal("message-1"))
+		})
+	})
+
+	Describe("DeleteExpired", func() {
+		It("deletes the entries that have expired", func() {
+			expired := entry
+			expired.MessageID = "message-1"
+			expired.ExpiresAt = now.Add(-time.Minute)
+
+			active := entry
+			active.DedupKey = "disk-slow"
+			active.MessageID = "message-2"
+			active.ExpiresAt = now.Add(time.Minute)
+
+			for _, e := range []models.DedupEntry{expired, active} {
+				_, _, err := repo.Claim(conn, e, now.Add(-time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			count, err := repo.DeleteExpired(conn, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+
+			var remaining []models.DedupEntry
+			_, err = conn.Select(&remaining, "SELECT * FROM `dedup_entries`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remaining).To(HaveLen(1))
+			Expect(remaining[0].MessageID).To(Equal("message-2"))
+		})
+	})
+})
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// DedupEntry records the message that was queued for a recipient, client,
+// kind and dedup key. Identical deliveries are collapsed into that message
+// until the entry expires.
+type DedupEntry struct {
+	Primary   int       `db:"primary"`
+	Recipient string    `db:"recipient"`
+	ClientID  string    `db:"client_id"`
+	KindID    string    `db:"kind_id"`
+	DedupKey  string    `db:"dedup_key"`
+	MessageID string    `db:"message_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
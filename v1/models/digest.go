@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+// The digests a user can choose to receive their non-critical notifications
+// in. Immediate delivery sends every notification as it arrives.
+const (
+	DigestImmediate = "immediate"
+	DigestHourly    = "hourly"
+	DigestDaily     = "daily"
+)
+
+var DigestFrequencies = []string{DigestImmediate, DigestHourly, DigestDaily}
+
+// DigestPreference overrides the digest of the user's settings for one kind
+// of notification.
+type DigestPreference struct {
+	Primary   int       `db:"primary"`
+	UserID    string    `db:"user_id"`
+	ClientID  string    `db:"client_id"`
+	KindID    string    `db:"kind_id"`
+	Digest    string    `db:"digest"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (p *DigestPreference) PreInsert(s gorp.SqlExecutor) error {
+	p.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	p.UpdatedAt = p.CreatedAt
+
+	return nil
+}
+
+func (p *DigestPreference) PreUpdate(s gorp.SqlExecutor) error {
+	p.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
+
+// DigestItem is a delivery held back for the digest of its recipient. The
+// delivery is kept as the JSON of the job it was taken from and is sent
+// once DueAt has passed.
+type DigestItem struct {
+	Primary   int       `db:"primary"`
+	UserGUID  string    `db:"user_guid"`
+	Digest    string    `db:"digest"`
+	MessageID string    `db:"message_id"`
+	Delivery  string    `db:"delivery"`
+	DueAt     time.Time `db:"due_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (i *DigestItem) PreInsert(s gorp.SqlExecutor) error {
+	i.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
+
+// DigestDueAt returns when a digest collecting items at the given time is
+// sent: at the start of the next hour or the next day in the given
+// location, which is the timezone of the recipient.
+func DigestDueAt(digest string, at time.Time, location *time.Location) time.Time {
+	local := at.In(location)
+
+	switch digest {
+	case DigestHourly:
+		return time.Date(local.Year(), local.Month(), local.Day(), local.Hour()+1, 0, 0, 0, location).UTC()
+	case DigestDaily:
+		return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, location).UTC()
+	default:
+		return at.UTC()
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+type DigestItemsRepo struct{}
+
+func NewDigestItemsRepo() DigestItemsRepo {
+	return DigestItemsRepo{}
+}
+
+func (repo DigestItemsRepo) Create(conn ConnectionInterface, item DigestItem) (DigestItem, error) {
+	err := conn.Insert(&item)
+	if err != nil {
+		return DigestItem{}, err
+	}
+
+	return item, nil
+}
+
+// FindDue returns the items of every digest due at the given time, grouped
+// by recipient and digest and in the order they were collected.
+func (repo DigestItemsRepo) FindDue(conn ConnectionInterface, now time.Time) ([]DigestItem, error) {
+	items := []DigestItem{}
+	_, err := conn.Select(&items, "SELECT * FROM `digest_items` WHERE `due_at` <= ? ORDER BY `user_guid`, `digest`, `primary`", now.UTC())
+	if err != nil {
+		return []DigestItem{}, err
+	}
+
+	return items, nil
+}
+
+// Postpone moves the items to a digest due at the given time.
+func (repo DigestItemsRepo) Postpone(conn ConnectionInterface, items []DigestItem, dueAt time.Time) error {
+	for _, item := range items {
+		item := item
+		item.DueAt = dueAt.UTC()
+		_, err := conn.Update(&item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo DigestItemsRepo) Delete(conn ConnectionInterface, items []DigestItem) error {
+	for _, item := range items {
+		item := item
+		_, err := conn.Delete(&item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
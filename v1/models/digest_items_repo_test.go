@@ -0,0 +1,102 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DigestItemsRepo", func() {
+	var (
+		repo models.DigestItemsRepo
+		conn db.ConnectionInterface
+		now  time.Time
+	)
+
+	BeforeEach(func() {
+		repo = models.NewDigestItemsRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+		now = time.Date(2015, time.June, 8, 14, 0, 0, 0, time.UTC)
+	})
+
+	It("finds the items of the digests that are due", func() {
+		for _, item := range []models.DigestItem{
+			{UserGUID: "user-2", Digest: models.DigestHourly, MessageID: "message-1", Delivery: "{}", DueAt: now},
+			{UserGUID: "user-1", Digest: models.DigestDaily, MessageID: "message-2", Delivery: "{}", DueAt: now.Add(-time.Hour)},
+			{UserGUID: "user-1", Digest: models.DigestDaily, MessageID: "message-3", Delivery: "{}", DueAt: now.Add(-time.Hour)},
+			{UserGUID: "user-1", Digest: models.DigestHourly, MessageID: "message-4", Delivery: "{}", DueAt: now.Add(time.Hour)},
+		} {
+			_, err := repo.Create(conn, item)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		items, err := repo.FindDue(conn, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		var messageIDs []string
+		for _, item := range items {
+			messageIDs = append(messageIDs, item.MessageID)
+		}
+		Expect(messageIDs).To(Equal([]string{"message-2", "message-3", "message-1"}))
+	})
+
+	It("postpones items to a later digest", func() {
+		item, err := repo.Create(conn, models.DigestItem{UserGUID: "user-1", Digest: models.DigestHourly, MessageID: "message-1", Delivery: "{}", DueAt: now})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = repo.Postpone(conn, []models.DigestItem{item}, now.Add(2*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+
+		items, err := repo.FindDue(conn, now.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(BeEmpty())
+
+		items, err = repo.FindDue(conn, now.Add(2*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].MessageID).To(Equal("message-1"))
+	})
+
+	It("deletes items", func() {
+		item, err := repo.Create(conn, models.DigestItem{UserGUID: "user-1", Digest: models.DigestHourly, MessageID: "message-1", Delivery: "{}", DueAt: now})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = repo.Delete(conn, []models.DigestItem{item})
+		Expect(err).NotTo(HaveOccurred())
+
+		items, err := repo.FindDue(conn, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(BeEmpty())
+	})
+})
+
+var _ = Describe("DigestDueAt", func() {
+	It("sends hourly digests at the start of the next hour", func() {
+		at := time.Date(2015, time.June, 8, 14, 32, 11, 0, time.UTC)
+		Expect(models.DigestDueAt(models.DigestHourly, at, time.UTC)).To(Equal(time.Date(2015, time.June, 8, 15, 0, 0, 0, time.UTC)))
+	})
+
+	It("sends hourly digests at the start of the next hour in the recipient's timezone", func() {
+		at := time.Date(2015, time.June, 8, 14, 32, 11, 0, time.UTC)
+		kolkata := time.FixedZone("IST", 5*60*60+30*60)
+		Expect(models.DigestDueAt(models.DigestHourly, at, kolkata)).To(Equal(time.Date(2015, time.June, 8, 15, 30, 0, 0, time.UTC)))
+	})
+
+	It("sends daily digests at the start of the next day in UTC", func() {
+		at := time.Date(2015, time.June, 30, 20, 32, 11, 0, time.FixedZone("PDT", -7*60*60))
+		Expect(models.DigestDueAt(models.DigestDaily, at, time.UTC)).To(Equal(time.Date(2015, time.July, 2, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("sends daily digests at midnight in the recipient's timezone", func() {
+		at := time.Date(2015, time.June, 30, 20, 32, 11, 0, time.FixedZone("PDT", -7*60*60))
+		pacific := time.FixedZone("PDT", -7*60*60)
+		Expect(models.DigestDueAt(models.DigestDaily, at, pacific)).To(Equal(time.Date(2015, time.July, 1, 7, 0, 0, 0, time.UTC)))
+	})
+})
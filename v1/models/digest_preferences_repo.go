@@ -0,0 +1,73 @@
+package models
+
+import "database/sql"
+
+type DigestPreferencesRepo struct{}
+
+func NewDigestPreferencesRepo() DigestPreferencesRepo {
+	return DigestPreferencesRepo{}
+}
+
+// Get returns the digest the user chose for the kind, which is empty when
+// the kind follows the digest of the user's settings.
+func (repo DigestPreferencesRepo) Get(conn ConnectionInterface, userID, clientID, kindID string) (string, error) {
+	preference, err := repo.find(conn, userID, clientID, kindID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return preference.Digest, nil
+}
+
+// Set records the digest the user chose for the kind. An empty digest
+// removes the choice.
+func (repo DigestPreferencesRepo) Set(conn ConnectionInterface, userID, clientID, kindID, digest string) error {
+	preference, err := repo.find(conn, userID, clientID, kindID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if digest == "" {
+			return nil
+		}
+
+		return conn.Insert(&DigestPreference{
+			UserID:   userID,
+			ClientID: clientID,
+			KindID:   kindID,
+			Digest:   digest,
+		})
+	}
+
+	if digest == "" {
+		_, err = conn.Delete(&preference)
+		return err
+	}
+
+	preference.Digest = digest
+	_, err = conn.Update(&preference)
+
+	return err
+}
+
+func (repo DigestPreferencesRepo) FindAllByUserID(conn ConnectionInterface, userID string) ([]DigestPreference, error) {
+	preferences := []DigestPreference{}
+	_, err := conn.Select(&preferences, "SELECT * FROM `digest_preferences` WHERE `user_id` = ?", userID)
+	if err != nil {
+		return []DigestPreference{}, err
+	}
+
+	return preferences, nil
+}
+
+func (repo DigestPreferencesRepo) find(conn ConnectionInterface, userID, clientID, kindID string) (DigestPreference, error) {
+	preference := DigestPreference{}
+	err := conn.SelectOne(&preference, "SELECT * FROM `digest_preferences` WHERE `user_id` = ? AND `client_id` = ? AND `kind_id` = ?", userID, clientID, kindID)
+
+	return preference, err
+}
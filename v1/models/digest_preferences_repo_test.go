@@ -0,0 +1,69 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DigestPreferencesRepo", func() {
+	var (
+		repo models.DigestPreferencesRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		repo = models.NewDigestPreferencesRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+	})
+
+	It("returns an empty digest for kinds the user has not chosen one for", func() {
+		digest, err := repo.Get(conn, "some-user-id", "some-client-id", "some-kind-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(BeEmpty())
+	})
+
+	It("sets, updates and removes the digest of a kind", func() {
+		err := repo.Set(conn, "some-user-id", "some-client-id", "some-kind-id", models.DigestDaily)
+		Expect(err).NotTo(HaveOccurred())
+
+		digest, err := repo.Get(conn, "some-user-id", "some-client-id", "some-kind-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal(models.DigestDaily))
+
+		err = repo.Set(conn, "some-user-id", "some-client-id", "some-kind-id", models.DigestImmediate)
+		Expect(err).NotTo(HaveOccurred())
+
+		digest, err = repo.Get(conn, "some-user-id", "some-client-id", "some-kind-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal(models.DigestImmediate))
+
+		err = repo.Set(conn, "some-user-id", "some-client-id", "some-kind-id", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		digest, err = repo.Get(conn, "some-user-id", "some-client-id", "some-kind-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(BeEmpty())
+	})
+
+	It("finds every digest the user has chosen", func() {
+		Expect(repo.Set(conn, "some-user-id", "some-client-id", "some-kind-id", models.DigestDaily)).To(Succeed())
+		Expect(repo.Set(conn, "some-user-id", "some-client-id", "other-kind-id", models.DigestHourly)).To(Succeed())
+		Expect(repo.Set(conn, "other-user-id", "some-client-id", "some-kind-id", models.DigestHourly)).To(Succeed())
+
+		preferences, err := repo.FindAllByUserID(conn, "some-user-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preferences).To(HaveLen(2))
+
+		var kinds []string
+		for _, preference := range preferences {
+			kinds = append(kinds, preference.KindID+":"+preference.Digest)
+		}
+		Expect(kinds).To(ConsistOf("some-kind-id:daily", "other-kind-id:hourly"))
+	})
+})
@@ -6,6 +6,10 @@ import (
 	"gopkg.in/gorp.v1"
 )
 
+// MaxTTL is the longest TTL, in seconds, a kind or a notification may be
+// given: a year.
+const MaxTTL = 365 * 24 * 60 * 60
+
 type Kind struct {
 	Primary     int       `db:"primary"`
 	ID          string    `db:"id"`
@@ -15,6 +19,23 @@ type Kind struct {
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
 	TemplateID  string    `db:"template_id"`
+
+	SkipTextAlternative bool `db:"skip_text_alternative"`
+
+	// TTL is how many seconds notifications of this kind stay meaningful
+	// when the request does not say otherwise. Zero means they never expire.
+	// It is at most MaxTTL.
+	TTL int `db:"ttl"`
+
+	// DedupWindow is how many seconds an identical notification of this kind
+	// to the same recipient is collapsed into the first one. Zero turns
+	// deduplication off.
+	DedupWindow int `db:"dedup_window"`
+
+	// DeliverySettingsGiven marks a kind whose SkipTextAlternative, TTL and
+	// DedupWindow were supplied by the caller. Updating a kind without it,
+	// as the legacy registration endpoint does, keeps the stored settings.
+	DeliverySettingsGiven bool `db:"-" json:"-"`
 }
 
 func (k Kind) TemplateToUse() string {
@@ -71,6 +71,11 @@ func (repo KindsRepo) Update(conn ConnectionInterface, kind Kind) (Kind, error)
 	if kind.TemplateID == DoNotSetTemplateID {
 		kind.TemplateID = existingKind.TemplateID
 	}
+	if !kind.DeliverySettingsGiven {
+		kind.SkipTextAlternative = existingKind.SkipTextAlternative
+		kind.TTL = existingKind.TTL
+		kind.DedupWindow = existingKind.DedupWindow
+	}
 
 	_, err = conn.Update(&kind)
 	if err != nil {
@@ -138,3 +143,39 @@ func (repo KindsRepo) FindAllByTemplateID(conn ConnectionInterface, templateID s
 	}
 	return kinds, nil
 }
+
+// KindsFilter narrows a listing of kinds to those of a client, of a
+// template or of the given criticality.
+type KindsFilter struct {
+	ClientID   string
+	TemplateID string
+	Critical   *bool
+}
+
+// List returns the kinds matching the filter, ordered by client ID and ID.
+func (repo KindsRepo) List(conn ConnectionInterface, filter KindsFilter, page Page) ([]Kind, error) {
+	var query listQuery
+	if filter.ClientID != "" {
+		query.where("`client_id` = ?", filter.ClientID)
+	}
+
+	if filter.TemplateID != "" {
+		query.where("`template_id` = ?", filter.TemplateID)
+	}
+
+	if filter.Critical != nil {
+		query.where("`critical` = ?", *filter.Critical)
+	}
+
+	statement, args, err := query.build("SELECT * FROM `kinds`", page, "client_id", "id")
+	if err != nil {
+		return []Kind{}, err
+	}
+
+	kinds := []Kind{}
+	_, err = conn.Select(&kinds, statement, args...)
+	if err != nil {
+		return []Kind{}, err
+	}
+	return kinds, nil
+}
@@ -132,6 +132,65 @@ var _ = Describe("KindsRepo", func() {
 				Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Notification with ID \"my-kind\" belonging to client \"my-client\" could not be found")}))
 			})
 		})
+
+		Context("when the delivery settings are not given", func() {
+			It("keeps the existing delivery settings", func() {
+				kind, err := repo.Upsert(conn, models.Kind{
+					ID:       "my-kind",
+					ClientID: "my-client",
+
+					SkipTextAlternative:   true,
+					TTL:                   300,
+					DedupWindow:           60,
+					DeliverySettingsGiven: true,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(kind.TTL).To(Equal(300))
+
+				_, err = repo.Update(conn, models.Kind{
+					ID:          "my-kind",
+					ClientID:    "my-client",
+					Description: "Re-registered",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				kind, err = repo.Find(conn, "my-kind", "my-client")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(kind.Description).To(Equal("Re-registered"))
+				Expect(kind.SkipTextAlternative).To(BeTrue())
+				Expect(kind.TTL).To(Equal(300))
+				Expect(kind.DedupWindow).To(Equal(60))
+			})
+		})
+
+		Context("when the delivery settings are given", func() {
+			It("updates the delivery settings", func() {
+				_, err := repo.Upsert(conn, models.Kind{
+					ID:       "my-kind",
+					ClientID: "my-client",
+
+					SkipTextAlternative:   true,
+					TTL:                   300,
+					DedupWindow:           60,
+					DeliverySettingsGiven: true,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = repo.Update(conn, models.Kind{
+					ID:       "my-kind",
+					ClientID: "my-client",
+
+					DeliverySettingsGiven: true,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				kind, err := repo.Find(conn, "my-kind", "my-client")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(kind.SkipTextAlternative).To(BeFalse())
+				Expect(kind.TTL).To(BeZero())
+				Expect(kind.DedupWindow).To(BeZero())
+			})
+		})
 	})
 
 	Describe("Upsert", func() {
@@ -376,4 +435,62 @@ var _ = Describe("KindsRepo", func() {
 			Expect(kinds).To(ContainElement(kind))
 		})
 	})
+
+	Describe("List", func() {
+		BeforeEach(func() {
+			for _, kind := range []models.Kind{
+				{ID: "kind-b", ClientID: "client-a", Critical: true},
+				{ID: "kind-a", ClientID: "client-b", TemplateID: "some-template"},
+				{ID: "kind-a", ClientID: "client-a"},
+				{ID: "kind-c", ClientID: "client-b", Critical: true},
+			} {
+				_, err := repo.Upsert(conn, kind)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		keys := func(kinds []models.Kind) []string {
+			var keys []string
+			for _, kind := range kinds {
+				keys = append(keys, kind.ClientID+"/"+kind.ID)
+			}
+			return keys
+		}
+
+		It("returns the kinds ordered by client ID and ID", func() {
+			kinds, err := repo.List(conn, models.KindsFilter{}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys(kinds)).To(Equal([]string{"client-a/kind-a", "client-a/kind-b", "client-b/kind-a", "client-b/kind-c"}))
+		})
+
+		It("returns the kinds after the page key, up to the limit", func() {
+			kinds, err := repo.List(conn, models.KindsFilter{}, models.Page{
+				After: []string{"client-a", "kind-b"},
+				Limit: 1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys(kinds)).To(Equal([]string{"client-b/kind-a"}))
+		})
+
+		It("returns the kinds matching the filter", func() {
+			critical := true
+			kinds, err := repo.List(conn, models.KindsFilter{Critical: &critical}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys(kinds)).To(Equal([]string{"client-a/kind-b", "client-b/kind-c"}))
+
+			critical = false
+			kinds, err = repo.List(conn, models.KindsFilter{ClientID: "client-b", Critical: &critical}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys(kinds)).To(Equal([]string{"client-b/kind-a"}))
+
+			kinds, err = repo.List(conn, models.KindsFilter{TemplateID: "some-template"}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys(kinds)).To(Equal([]string{"client-b/kind-a"}))
+		})
+
+		It("rejects page keys of another listing", func() {
+			_, err := repo.List(conn, models.KindsFilter{}, models.Page{After: []string{"client-a"}})
+			Expect(err).To(BeAssignableToTypeOf(models.PageKeyError{}))
+		})
+	})
 })
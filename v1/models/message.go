@@ -7,13 +7,25 @@ import (
 )
 
 type Message struct {
-	ID         string    `db:"id"`
-	Status     string    `db:"status"`
-	UpdatedAt  time.Time `db:"updated_at"`
+	ID              string    `db:"id"`
+	Status          string    `db:"status"`
+	Error           string    `db:"error"`
+	GivenUp         bool      `db:"given_up"`
+	TemplateID      string    `db:"template_id"`
+	TemplateVersion int       `db:"template_version"`
+	BatchID         string    `db:"batch_id"`
+	UserGUID        string    `db:"user_guid"`
+	Email           string    `db:"email"`
+	ClientID        string    `db:"client_id"`
+	KindID          string    `db:"kind_id"`
+	VCAPRequestID   string    `db:"vcap_request_id"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
 }
 
 func (m *Message) PreInsert(s gorp.SqlExecutor) error {
 	m.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	m.CreatedAt = m.UpdatedAt
 
 	return nil
 }
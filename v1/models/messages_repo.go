@@ -55,19 +55,109 @@ func (repo MessagesRepo) Update(conn ConnectionInterface, message Message) (Mess
 	return repo.FindByID(conn, message.ID)
 }
 
+// Upsert creates the message, or updates the status and error of an existing
+// message while leaving the rest of its record untouched. A message that
+// has been given up on stays given up on.
 func (repo MessagesRepo) Upsert(conn ConnectionInterface, message Message) (Message, error) {
-	_, err := repo.FindByID(conn, message.ID)
+	existingMessage, err := repo.FindByID(conn, message.ID)
 
 	switch err.(type) {
 	case NotFoundError:
 		return repo.Create(conn, message)
 	case nil:
-		return repo.Update(conn, message)
+		existingMessage.Status = message.Status
+		existingMessage.Error = message.Error
+		existingMessage.GivenUp = existingMessage.GivenUp || message.GivenUp
+		return repo.Update(conn, existingMessage)
 	default:
 		return message, err
 	}
 }
 
+func (repo MessagesRepo) SetTemplate(conn ConnectionInterface, messageID, templateID string, templateVersion int) error {
+	_, err := conn.Exec("UPDATE `messages` SET `template_id` = ?, `template_version` = ? WHERE `id` = ?", templateID, templateVersion, messageID)
+
+	return err
+}
+
+// SetEmail records the address the message is sent to once it is known,
+// which for messages sent to users is only when the worker loads them.
+func (repo MessagesRepo) SetEmail(conn ConnectionInterface, messageID, email string) error {
+	_, err := conn.Exec("UPDATE `messages` SET `email` = ? WHERE `id` = ?", email, messageID)
+
+	return err
+}
+
+// MessagesFilter narrows a listing of messages. ClientID is always set by
+// the API, so that each client only lists its own messages. The creation
+// time range includes CreatedSince and excludes CreatedBefore.
+type MessagesFilter struct {
+	ClientID      string
+	UserGUID      string
+	Email         string
+	KindID        string
+	VCAPRequestID string
+	Status        string
+	CreatedSince  time.Time
+	CreatedBefore time.Time
+}
+
+// List returns the messages matching the filter, oldest first.
+func (repo MessagesRepo) List(conn ConnectionInterface, filter MessagesFilter, page Page) ([]Message, error) {
+	var query listQuery
+	if filter.ClientID != "" {
+		query.where("`client_id` = ?", filter.ClientID)
+	}
+
+	if filter.UserGUID != "" {
+		query.where("`user_guid` = ?", filter.UserGUID)
+	}
+
+	if filter.Email != "" {
+		query.where("`email` = ?", filter.Email)
+	}
+
+	if filter.KindID != "" {
+		query.where("`kind_id` = ?", filter.KindID)
+	}
+
+	if filter.VCAPRequestID != "" {
+		query.where("`vcap_request_id` = ?", filter.VCAPRequestID)
+	}
+
+	// A failed message is retried until it is given up on, and until then
+	// counts as queued, as it does in the counters of its batch.
+	switch filter.Status {
+	case "":
+	case "queued":
+		query.where("(`status` = ? OR (`status` = ? AND `given_up` = ?))", "queued", "failed", false)
+	case "failed":
+		query.where("`status` = ? AND `given_up` = ?", "failed", true)
+	default:
+		query.where("`status` = ?", filter.Status)
+	}
+
+	if !filter.CreatedSince.IsZero() {
+		query.where("`created_at` >= ?", filter.CreatedSince.UTC())
+	}
+
+	if !filter.CreatedBefore.IsZero() {
+		query.where("`created_at` < ?", filter.CreatedBefore.UTC())
+	}
+
+	statement, args, err := query.build("SELECT * FROM `messages`", page, "created_at", "id")
+	if err != nil {
+		return []Message{}, err
+	}
+
+	messages := []Message{}
+	_, err = conn.Select(&messages, statement, args...)
+	if err != nil {
+		return []Message{}, err
+	}
+	return messages, nil
+}
+
 func (repo MessagesRepo) DeleteBefore(conn ConnectionInterface, threshold time.Time) (int, error) {
 	result, err := conn.Exec("DELETE FROM `messages` WHERE `updated_at` < ?", threshold.UTC())
 	if err != nil {
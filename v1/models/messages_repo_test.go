@@ -109,6 +109,153 @@ var _ = Describe("MessagesRepo", func() {
 				Expect(messageFound.ID).To(Equal(message.ID))
 				Expect(messageFound.Status).To(Equal(message.Status))
 			})
+
+			It("keeps the template that rendered the message", func() {
+				message, err := repo.Create(conn, message)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = repo.SetTemplate(conn, message.ID, "some-template-id", 3)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = repo.Upsert(conn, models.Message{
+					ID:     message.ID,
+					Status: common.StatusFailed,
+					Error:  "smtp went away",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				messageFound, err := repo.FindByID(conn, message.ID)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(messageFound.Status).To(Equal(common.StatusFailed))
+				Expect(messageFound.Error).To(Equal("smtp went away"))
+				Expect(messageFound.TemplateID).To(Equal("some-template-id"))
+				Expect(messageFound.TemplateVersion).To(Equal(3))
+			})
+
+			It("keeps the message given up on", func() {
+				message.Status = common.StatusFailed
+				message.GivenUp = true
+				message, err := repo.Create(conn, message)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = repo.Upsert(conn, models.Message{
+					ID:     message.ID,
+					Status: common.StatusFailed,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				messageFound, err := repo.FindByID(conn, message.ID)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(messageFound.GivenUp).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("SetEmail", func() {
+		It("records the address the message is sent to", func() {
+			message, err := repo.Create(conn, models.Message{Status: common.StatusQueued, UserGUID: "some-user"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.SetEmail(conn, message.ID, "user@example.com")
+			Expect(err).NotTo(HaveOccurred())
+
+			messageFound, err := repo.FindByID(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messageFound.UserGUID).To(Equal("some-user"))
+			Expect(messageFound.Email).To(Equal("user@example.com"))
+		})
+	})
+
+	Describe("List", func() {
+		BeforeEach(func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"message-1", "message-2", "message-3"}
+
+			for _, message := range []models.Message{
+				{Status: common.StatusDelivered, ClientID: "some-client", KindID: "some-kind", UserGUID: "user-1", VCAPRequestID: "request-1"},
+				{Status: common.StatusFailed, GivenUp: true, ClientID: "some-client", KindID: "other-kind", Email: "user@example.com", VCAPRequestID: "request-1"},
+				{Status: common.StatusDelivered, ClientID: "other-client", KindID: "some-kind", UserGUID: "user-1", VCAPRequestID: "request-2"},
+			} {
+				_, err := repo.Create(conn, message)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("lists only the messages of the client", func() {
+			messages, err := repo.List(conn, models.MessagesFilter{ClientID: "some-client"}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(2))
+			Expect(messages[0].ID).To(Equal("message-1"))
+			Expect(messages[1].ID).To(Equal("message-2"))
+		})
+
+		It("filters the messages by their fields", func() {
+			for filter, id := range map[models.MessagesFilter]string{
+				{ClientID: "some-client", UserGUID: "user-1"}:          "message-1",
+				{ClientID: "some-client", Email: "user@example.com"}:   "message-2",
+				{ClientID: "some-client", KindID: "other-kind"}:        "message-2",
+				{ClientID: "some-client", Status: common.StatusFailed}: "message-2",
+				{ClientID: "other-client", VCAPRequestID: "request-2"}: "message-3",
+			} {
+				messages, err := repo.List(conn, filter, models.Page{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(messages).To(HaveLen(1))
+				Expect(messages[0].ID).To(Equal(id))
+			}
+		})
+
+		It("lists failed messages that are still retried as queued", func() {
+			guidGenerator.GenerateCall.Returns.IDs = append(guidGenerator.GenerateCall.Returns.IDs, "message-4", "message-5")
+			for _, message := range []models.Message{
+				{Status: common.StatusFailed, ClientID: "some-client"},
+				{Status: common.StatusQueued, ClientID: "some-client"},
+			} {
+				_, err := repo.Create(conn, message)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			messages, err := repo.List(conn, models.MessagesFilter{ClientID: "some-client", Status: common.StatusQueued}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(2))
+			Expect(messages[0].ID).To(Equal("message-4"))
+			Expect(messages[1].ID).To(Equal("message-5"))
+
+			messages, err = repo.List(conn, models.MessagesFilter{ClientID: "some-client", Status: common.StatusFailed}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0].ID).To(Equal("message-2"))
+		})
+
+		It("filters the messages by the time they were created", func() {
+			now := time.Now()
+
+			messages, err := repo.List(conn, models.MessagesFilter{ClientID: "some-client", CreatedSince: now.Add(-time.Hour), CreatedBefore: now.Add(time.Hour)}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(2))
+
+			messages, err = repo.List(conn, models.MessagesFilter{ClientID: "some-client", CreatedSince: now.Add(time.Hour)}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+
+			messages, err = repo.List(conn, models.MessagesFilter{ClientID: "some-client", CreatedBefore: now.Add(-time.Hour)}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+		})
+
+		It("pages through the messages", func() {
+			messages, err := repo.List(conn, models.MessagesFilter{ClientID: "some-client"}, models.Page{Limit: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0].ID).To(Equal("message-1"))
+
+			messages, err = repo.List(conn, models.MessagesFilter{ClientID: "some-client"}, models.Page{
+				Limit: 1,
+				After: []string{messages[0].CreatedAt.UTC().Format("2006-01-02 15:04:05"), messages[0].ID},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0].ID).To(Equal("message-2"))
 		})
 	})
 
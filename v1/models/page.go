@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// Page selects part of a listing ordered by its key. After holds the key of
+// the last row of the previous page and is empty for the first page. A zero
+// Limit selects every remaining row.
+type Page struct {
+	After []string
+	Limit int
+}
+
+func (p Page) Paginated() bool {
+	return p.Limit > 0 || len(p.After) > 0
+}
+
+// Peek returns the page with room for one more row, whose presence tells
+// the caller that another page follows.
+func (p Page) Peek() Page {
+	if p.Limit > 0 {
+		p.Limit++
+	}
+
+	return p
+}
+
+// More reports whether the given number of rows, fetched with Peek, runs
+// past the end of the page.
+func (p Page) More(count int) bool {
+	return p.Limit > 0 && count > p.Limit
+}
+
+// PageKeyError reports a page key whose shape does not match the key of the
+// listing it was passed to, which happens when a cursor is reused across
+// endpoints.
+type PageKeyError struct {
+	Err error
+}
+
+func (e PageKeyError) Error() string {
+	return e.Err.Error()
+}
+
+func NewPageKeyError() PageKeyError {
+	return PageKeyError{errors.New("cursor does not belong to this listing")}
+}
+
+// keysetClause returns the condition selecting the rows that sort after the
+// page key on the given columns, along with its arguments.
+func (p Page) keysetClause(columns ...string) (string, []interface{}, error) {
+	if len(p.After) == 0 {
+		return "", nil, nil
+	}
+
+	if len(p.After) != len(columns) {
+		return "", nil, NewPageKeyError()
+	}
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	for i := range columns {
+		var condition []string
+		for j := 0; j < i; j++ {
+			condition = append(condition, "`"+columns[j]+"` = ?")
+			args = append(args, p.After[j])
+		}
+		condition = append(condition, "`"+columns[i]+"` > ?")
+		args = append(args, p.After[i])
+
+		conditions = append(conditions, "("+strings.Join(condition, " AND ")+")")
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+}
+
+// listQuery builds the SELECT of a listing from its filter conditions and
+// the page it returns.
+type listQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (q *listQuery) where(condition string, args ...interface{}) {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+}
+
+// build orders the rows by the key columns and restricts them to the page.
+func (q listQuery) build(selection string, page Page, key ...string) (string, []interface{}, error) {
+	keyset, keysetArgs, err := page.keysetClause(key...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if keyset != "" {
+		q.where(keyset, keysetArgs...)
+	}
+
+	query := selection
+	if len(q.conditions) > 0 {
+		query += " WHERE " + strings.Join(q.conditions, " AND ")
+	}
+
+	var order []string
+	for _, column := range key {
+		order = append(order, "`"+column+"`")
+	}
+	query += " ORDER BY " + strings.Join(order, ", ")
+
+	if page.Limit > 0 {
+		query += " LIMIT ?"
+		q.args = append(q.args, page.Limit)
+	}
+
+	return query, q.args, nil
+}
+
+// likePattern matches values containing the given text, escaping the LIKE
+// wildcards within it.
+func likePattern(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(text) + "%"
+}
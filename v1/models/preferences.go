@@ -6,4 +6,5 @@ type Preference struct {
 	KindDescription   string `db:"kind_description"`
 	SourceDescription string `db:"source_description"`
 	Email             bool
+	Digest            *string
 }
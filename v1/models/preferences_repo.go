@@ -1,7 +1,8 @@
 package models
 
 type PreferencesRepo struct {
-	unsubscribesRepo UnsubscribesRepo
+	unsubscribesRepo      UnsubscribesRepo
+	digestPreferencesRepo DigestPreferencesRepo
 }
 
 func NewPreferencesRepo() PreferencesRepo {
@@ -33,9 +34,21 @@ func (repo PreferencesRepo) FindNonCriticalPreferences(conn ConnectionInterface,
 		return preferences, err
 	}
 
+	digests, err := repo.digestPreferencesRepo.FindAllByUserID(conn, userGUID)
+	if err != nil {
+		return preferences, err
+	}
+
 	unsubscribes := Unsubscribes(unsubs)
 	for index, preference := range preferences {
 		preferences[index].Email = !unsubscribes.Contains(preference.ClientID, preference.KindID)
+
+		for _, digest := range digests {
+			if digest.ClientID == preference.ClientID && digest.KindID == preference.KindID {
+				value := digest.Digest
+				preferences[index].Digest = &value
+			}
+		}
 	}
 
 	return preferences, nil
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type SenderIdentitiesRepo struct{}
+
+func NewSenderIdentitiesRepo() SenderIdentitiesRepo {
+	return SenderIdentitiesRepo{}
+}
+
+func (repo SenderIdentitiesRepo) Find(conn ConnectionInterface, clientID, kindID string) (SenderIdentity, error) {
+	identity := SenderIdentity{}
+	err := conn.SelectOne(&identity, "SELECT * FROM `sender_identities` WHERE `client_id` = ? AND `kind_id` = ?", clientID, kindID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			if kindID == "" {
+				err = NotFoundError{fmt.Errorf("Sender identity for client %q could not be found", clientID)}
+			} else {
+				err = NotFoundError{fmt.Errorf("Sender identity for notification %q belonging to client %q could not be found", kindID, clientID)}
+			}
+		}
+		return identity, err
+	}
+
+	return identity, nil
+}
+
+func (repo SenderIdentitiesRepo) Upsert(conn ConnectionInterface, identity SenderIdentity) (SenderIdentity, error) {
+	existingIdentity, err := repo.Find(conn, identity.ClientID, identity.KindID)
+
+	switch err.(type) {
+	case NotFoundError:
+		createdIdentity, err := repo.create(conn, identity)
+		if _, ok := err.(DuplicateError); ok {
+			existingIdentity, err = repo.Find(conn, identity.ClientID, identity.KindID)
+			if err != nil {
+				return identity, err
+			}
+
+			return repo.update(conn, existingIdentity, identity)
+		}
+
+		return createdIdentity, err
+	case nil:
+		return repo.update(conn, existingIdentity, identity)
+	default:
+		return identity, err
+	}
+}
+
+func (repo SenderIdentitiesRepo) Destroy(conn ConnectionInterface, clientID, kindID string) error {
+	identity, err := repo.Find(conn, clientID, kindID)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Delete(&identity)
+	return err
+}
+
+func (repo SenderIdentitiesRepo) update(conn ConnectionInterface, existingIdentity, identity SenderIdentity) (SenderIdentity, error) {
+	identity.Primary = existingIdentity.Primary
+	identity.CreatedAt = existingIdentity.CreatedAt
+
+	_, err := conn.Update(&identity)
+	if err != nil {
+		return identity, err
+	}
+
+	return repo.Find(conn, identity.ClientID, identity.KindID)
+}
+
+func (repo SenderIdentitiesRepo) create(conn ConnectionInterface, identity SenderIdentity) (SenderIdentity, error) {
+	err := conn.Insert(&identity)
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			err = DuplicateError{errors.New("duplicate record")}
+		}
+		return identity, err
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,118 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SenderIdentitiesRepo", func() {
+	var (
+		repo models.SenderIdentitiesRepo
+		conn *db.Connection
+	)
+
+	BeforeEach(func() {
+		repo = models.NewSenderIdentitiesRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection().(*db.Connection)
+	})
+
+	Describe("Upsert", func() {
+		Context("when the record is new", func() {
+			It("inserts the record in the database", func() {
+				identity, err := repo.Upsert(conn, models.SenderIdentity{
+					ClientID: "my-client",
+					KindID:   "my-kind",
+					Name:     "Autoscaler",
+					Address:  "autoscaler@example.com",
+					ReplyTo:  "autoscaler-support@example.com",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				identity, err = repo.Find(conn, "my-client", "my-kind")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(identity.Name).To(Equal("Autoscaler"))
+				Expect(identity.Address).To(Equal("autoscaler@example.com"))
+				Expect(identity.ReplyTo).To(Equal("autoscaler-support@example.com"))
+				Expect(identity.CreatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
+			})
+
+			It("keeps client and kind identities apart", func() {
+				_, err := repo.Upsert(conn, models.SenderIdentity{
+					ClientID: "my-client",
+					Address:  "client@example.com",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = repo.Upsert(conn, models.SenderIdentity{
+					ClientID: "my-client",
+					KindID:   "my-kind",
+					Address:  "kind@example.com",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				identity, err := repo.Find(conn, "my-client", "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(identity.Address).To(Equal("client@example.com"))
+
+				identity, err = repo.Find(conn, "my-client", "my-kind")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(identity.Address).To(Equal("kind@example.com"))
+			})
+		})
+
+		Context("when the record exists", func() {
+			It("updates the record in the database", func() {
+				original, err := repo.Upsert(conn, models.SenderIdentity{
+					ClientID: "my-client",
+					Address:  "old@example.com",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				identity, err := repo.Upsert(conn, models.SenderIdentity{
+					ClientID: "my-client",
+					Name:     "Billing",
+					Address:  "billing@example.com",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(identity.Primary).To(Equal(original.Primary))
+				Expect(identity.Name).To(Equal("Billing"))
+				Expect(identity.Address).To(Equal("billing@example.com"))
+			})
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns a not found error when the record does not exist", func() {
+			_, err := repo.Find(conn, "my-client", "my-kind")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Sender identity for notification \"my-kind\" belonging to client \"my-client\" could not be found")}))
+
+			_, err = repo.Find(conn, "my-client", "")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Sender identity for client \"my-client\" could not be found")}))
+		})
+	})
+
+	Describe("Destroy", func() {
+		It("removes the record from the database", func() {
+			_, err := repo.Upsert(conn, models.SenderIdentity{
+				ClientID: "my-client",
+				Address:  "client@example.com",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Destroy(conn, "my-client", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Find(conn, "my-client", "")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+	})
+})
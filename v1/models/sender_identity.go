@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/address"
+	"gopkg.in/gorp.v1"
+)
+
+type SenderIdentity struct {
+	Primary   int       `db:"primary"`
+	ClientID  string    `db:"client_id"`
+	KindID    string    `db:"kind_id"`
+	Name      string    `db:"name"`
+	Address   string    `db:"address"`
+	ReplyTo   string    `db:"reply_to"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (s *SenderIdentity) PreInsert(e gorp.SqlExecutor) error {
+	now := time.Now().Truncate(1 * time.Second).UTC()
+	s.CreatedAt = now
+	s.UpdatedAt = now
+
+	return nil
+}
+
+func (s *SenderIdentity) PreUpdate(e gorp.SqlExecutor) error {
+	s.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
+
+func (s SenderIdentity) From() string {
+	from, err := address.Parse(s.Address)
+	if err != nil {
+		return s.Address
+	}
+	from.Name = s.Name
+
+	return from.String()
+}
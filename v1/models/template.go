@@ -2,6 +2,7 @@ package models
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/util"
@@ -13,6 +14,15 @@ const (
 	DoNotSetTemplateID = ""
 )
 
+// InlineCSSMetadataKey is the metadata key that opts a template into having
+// the stylesheet rules of its HTML inlined into style attributes.
+const InlineCSSMetadataKey = "inline_css"
+
+// LegacyHTMLEscapingMetadataKey is the metadata key that keeps a template on
+// the escaping HTML parts had before they were rendered with html/template:
+// a fixed set of fields is escaped up front and the rest is left as it is.
+const LegacyHTMLEscapingMetadataKey = "legacy_html_escaping"
+
 type Template struct {
 	Primary    int       `db:"primary"`
 	ID         string    `db:"id"`
@@ -20,12 +30,42 @@ type Template struct {
 	Subject    string    `db:"subject"`
 	Text       string    `db:"text"`
 	HTML       string    `db:"html"`
+	Markdown   string    `db:"markdown"`
+	Layout     string    `db:"layout"`
 	Metadata   string    `db:"metadata"`
+	Version    int       `db:"version"`
 	CreatedAt  time.Time `db:"created_at"`
 	UpdatedAt  time.Time `db:"updated_at"`
 	Overridden bool      `db:"overridden"`
 }
 
+func (t Template) matches(name, subject, html, text, markdown, metadata string) bool {
+	return t.Name == name && t.Subject == subject && t.HTML == html && t.Text == text && t.Markdown == markdown && t.Metadata == metadata
+}
+
+// InlinesCSS reports whether the template's metadata opts into CSS
+// inlining.
+func (t Template) InlinesCSS() bool {
+	return t.metadataFlag(InlineCSSMetadataKey)
+}
+
+// UsesLegacyHTMLEscaping reports whether the template's metadata opts out of
+// contextual escaping of its HTML.
+func (t Template) UsesLegacyHTMLEscaping() bool {
+	return t.metadataFlag(LegacyHTMLEscapingMetadataKey)
+}
+
+func (t Template) metadataFlag(key string) bool {
+	var metadata map[string]interface{}
+	err := json.Unmarshal([]byte(t.Metadata), &metadata)
+	if err != nil {
+		return false
+	}
+
+	flag, _ := metadata[key].(bool)
+	return flag
+}
+
 func (t *Template) PreInsert(s gorp.SqlExecutor) error {
 	if t.ID == "" {
 		var err error
@@ -35,6 +75,10 @@ func (t *Template) PreInsert(s gorp.SqlExecutor) error {
 		}
 	}
 
+	if t.Version == 0 {
+		t.Version = 1
+	}
+
 	if (t.CreatedAt == time.Time{}) {
 		t.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
 	}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+// TemplateLocale is a locale variant of a template. TemplateVersion is the
+// version of the template the variant was last saved against.
+type TemplateLocale struct {
+	Primary         int       `db:"primary"`
+	TemplateID      string    `db:"template_id"`
+	TemplateVersion int       `db:"template_version"`
+	Locale          string    `db:"locale"`
+	Subject         string    `db:"subject"`
+	Text            string    `db:"text"`
+	HTML            string    `db:"html"`
+	Markdown        string    `db:"markdown"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+func (l *TemplateLocale) PreInsert(s gorp.SqlExecutor) error {
+	l.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	l.UpdatedAt = l.CreatedAt
+
+	return nil
+}
+
+func (l *TemplateLocale) PreUpdate(s gorp.SqlExecutor) error {
+	l.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
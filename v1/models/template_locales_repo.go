@@ -0,0 +1,81 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type TemplateLocalesRepo struct{}
+
+func NewTemplateLocalesRepo() TemplateLocalesRepo {
+	return TemplateLocalesRepo{}
+}
+
+func (repo TemplateLocalesRepo) Upsert(conn ConnectionInterface, variant TemplateLocale) (TemplateLocale, error) {
+	existing, err := repo.Find(conn, variant.TemplateID, variant.Locale)
+	if err != nil {
+		if _, ok := err.(NotFoundError); !ok {
+			return TemplateLocale{}, err
+		}
+
+		err = conn.Insert(&variant)
+		if err != nil {
+			return TemplateLocale{}, err
+		}
+
+		return variant, nil
+	}
+
+	existing.TemplateVersion = variant.TemplateVersion
+	existing.Subject = variant.Subject
+	existing.Text = variant.Text
+	existing.HTML = variant.HTML
+	existing.Markdown = variant.Markdown
+
+	_, err = conn.Update(&existing)
+	if err != nil {
+		return TemplateLocale{}, err
+	}
+
+	return existing, nil
+}
+
+func (repo TemplateLocalesRepo) Find(conn ConnectionInterface, templateID, locale string) (TemplateLocale, error) {
+	variant := TemplateLocale{}
+	err := conn.SelectOne(&variant, "SELECT * FROM `template_locales` WHERE `template_id` = ? AND `locale` = ?", templateID, locale)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return variant, NotFoundError{fmt.Errorf("Locale %q of template %q could not be found", locale, templateID)}
+		}
+		return variant, err
+	}
+
+	return variant, nil
+}
+
+func (repo TemplateLocalesRepo) List(conn ConnectionInterface, templateID string) ([]TemplateLocale, error) {
+	variants := []TemplateLocale{}
+	_, err := conn.Select(&variants, "SELECT * FROM `template_locales` WHERE `template_id` = ? ORDER BY `locale`", templateID)
+	if err != nil {
+		return []TemplateLocale{}, err
+	}
+
+	return variants, nil
+}
+
+func (repo TemplateLocalesRepo) Destroy(conn ConnectionInterface, templateID, locale string) error {
+	variant, err := repo.Find(conn, templateID, locale)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Delete(&variant)
+
+	return err
+}
+
+func (repo TemplateLocalesRepo) DestroyAll(conn ConnectionInterface, templateID string) error {
+	_, err := conn.Exec("DELETE FROM `template_locales` WHERE `template_id` = ?", templateID)
+
+	return err
+}
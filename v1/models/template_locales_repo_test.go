@@ -0,0 +1,117 @@
+package models_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateLocalesRepo", func() {
+	var (
+		repo models.TemplateLocalesRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		repo = models.NewTemplateLocalesRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+	})
+
+	Describe("Upsert", func() {
+		It("inserts a new locale variant", func() {
+			_, err := repo.Upsert(conn, models.TemplateLocale{
+				TemplateID: "some-template-id",
+				Locale:     "de-CH",
+				Subject:    "Grüezi {{.Subject}}",
+				Text:       "text",
+				HTML:       "<p>html</p>",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			variant, err := repo.Find(conn, "some-template-id", "de-CH")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variant.Subject).To(Equal("Grüezi {{.Subject}}"))
+			Expect(variant.Text).To(Equal("text"))
+			Expect(variant.HTML).To(Equal("<p>html</p>"))
+		})
+
+		It("updates an existing locale variant", func() {
+			_, err := repo.Upsert(conn, models.TemplateLocale{TemplateID: "some-template-id", TemplateVersion: 1, Locale: "de", Text: "alt"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Upsert(conn, models.TemplateLocale{TemplateID: "some-template-id", TemplateVersion: 2, Locale: "de", Text: "neu"})
+			Expect(err).NotTo(HaveOccurred())
+
+			variants, err := repo.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variants).To(HaveLen(1))
+			Expect(variants[0].Text).To(Equal("neu"))
+			Expect(variants[0].TemplateVersion).To(Equal(2))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns a not found error when the variant does not exist", func() {
+			_, err := repo.Find(conn, "some-template-id", "fr")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Locale \"fr\" of template \"some-template-id\" could not be found")}))
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the variants of the template ordered by locale", func() {
+			for _, locale := range []string{"fr", "de-CH", "de"} {
+				_, err := repo.Upsert(conn, models.TemplateLocale{TemplateID: "some-template-id", Locale: locale})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := repo.Upsert(conn, models.TemplateLocale{TemplateID: "other-template-id", Locale: "it"})
+			Expect(err).NotTo(HaveOccurred())
+
+			variants, err := repo.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variants).To(HaveLen(3))
+			Expect(variants[0].Locale).To(Equal("de"))
+			Expect(variants[1].Locale).To(Equal("de-CH"))
+			Expect(variants[2].Locale).To(Equal("fr"))
+		})
+	})
+
+	Describe("Destroy", func() {
+		It("removes the variant", func() {
+			_, err := repo.Upsert(conn, models.TemplateLocale{TemplateID: "some-template-id", Locale: "de"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Destroy(conn, "some-template-id", "de")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Find(conn, "some-template-id", "de")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+
+		It("returns a not found error when the variant does not exist", func() {
+			err := repo.Destroy(conn, "some-template-id", "de")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+	})
+
+	Describe("DestroyAll", func() {
+		It("removes every variant of the template", func() {
+			_, err := repo.Upsert(conn, models.TemplateLocale{TemplateID: "some-template-id", Locale: "de"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.DestroyAll(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			variants, err := repo.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variants).To(BeEmpty())
+		})
+	})
+})
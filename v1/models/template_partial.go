@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+type TemplatePartial struct {
+	Primary   int       `db:"primary"`
+	Name      string    `db:"name"`
+	Text      string    `db:"text"`
+	HTML      string    `db:"html"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (p *TemplatePartial) PreInsert(s gorp.SqlExecutor) error {
+	p.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	p.UpdatedAt = p.CreatedAt
+
+	return nil
+}
+
+func (p *TemplatePartial) PreUpdate(s gorp.SqlExecutor) error {
+	p.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type TemplatePartialsRepo struct{}
+
+func NewTemplatePartialsRepo() TemplatePartialsRepo {
+	return TemplatePartialsRepo{}
+}
+
+func (repo TemplatePartialsRepo) Upsert(conn ConnectionInterface, partial TemplatePartial) (TemplatePartial, error) {
+	existing, err := repo.Find(conn, partial.Name)
+	if err != nil {
+		if _, ok := err.(NotFoundError); !ok {
+			return TemplatePartial{}, err
+		}
+
+		err = conn.Insert(&partial)
+		if err != nil {
+			return TemplatePartial{}, err
+		}
+
+		return partial, nil
+	}
+
+	existing.Text = partial.Text
+	existing.HTML = partial.HTML
+
+	_, err = conn.Update(&existing)
+	if err != nil {
+		return TemplatePartial{}, err
+	}
+
+	return existing, nil
+}
+
+func (repo TemplatePartialsRepo) Find(conn ConnectionInterface, name string) (TemplatePartial, error) {
+	partial := TemplatePartial{}
+	err := conn.SelectOne(&partial, "SELECT * FROM `template_partials` WHERE `name` = ?", name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return partial, NotFoundError{fmt.Errorf("Partial %q could not be found", name)}
+		}
+		return partial, err
+	}
+
+	return partial, nil
+}
+
+func (repo TemplatePartialsRepo) List(conn ConnectionInterface) ([]TemplatePartial, error) {
+	partials := []TemplatePartial{}
+	_, err := conn.Select(&partials, "SELECT * FROM `template_partials` ORDER BY `name`")
+	if err != nil {
+		return []TemplatePartial{}, err
+	}
+
+	return partials, nil
+}
+
+func (repo TemplatePartialsRepo) Destroy(conn ConnectionInterface, name string) error {
+	partial, err := repo.Find(conn, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Delete(&partial)
+
+	return err
+}
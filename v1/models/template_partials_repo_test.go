@@ -0,0 +1,96 @@
+package models_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplatePartialsRepo", func() {
+	var (
+		repo models.TemplatePartialsRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		repo = models.NewTemplatePartialsRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+	})
+
+	Describe("Upsert", func() {
+		It("inserts a new partial", func() {
+			_, err := repo.Upsert(conn, models.TemplatePartial{
+				Name: "footer",
+				Text: "Sent by {{.SpaceName}}",
+				HTML: "<p>Sent by {{.SpaceName}}</p>",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			partial, err := repo.Find(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial.Text).To(Equal("Sent by {{.SpaceName}}"))
+			Expect(partial.HTML).To(Equal("<p>Sent by {{.SpaceName}}</p>"))
+		})
+
+		It("updates an existing partial", func() {
+			_, err := repo.Upsert(conn, models.TemplatePartial{Name: "footer", Text: "old"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Upsert(conn, models.TemplatePartial{Name: "footer", Text: "new"})
+			Expect(err).NotTo(HaveOccurred())
+
+			partials, err := repo.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(HaveLen(1))
+			Expect(partials[0].Text).To(Equal("new"))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns a not found error when the partial does not exist", func() {
+			_, err := repo.Find(conn, "footer")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Partial \"footer\" could not be found")}))
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the partials ordered by name", func() {
+			for _, name := range []string{"layout", "footer", "header"} {
+				_, err := repo.Upsert(conn, models.TemplatePartial{Name: name})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			partials, err := repo.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(HaveLen(3))
+			Expect(partials[0].Name).To(Equal("footer"))
+			Expect(partials[1].Name).To(Equal("header"))
+			Expect(partials[2].Name).To(Equal("layout"))
+		})
+	})
+
+	Describe("Destroy", func() {
+		It("removes the partial", func() {
+			_, err := repo.Upsert(conn, models.TemplatePartial{Name: "footer"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Destroy(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Find(conn, "footer")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+
+		It("returns a not found error when the partial does not exist", func() {
+			err := repo.Destroy(conn, "footer")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+	})
+})
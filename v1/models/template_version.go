@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+type TemplateVersion struct {
+	Primary    int       `db:"primary"`
+	TemplateID string    `db:"template_id"`
+	Version    int       `db:"version"`
+	Name       string    `db:"name"`
+	Subject    string    `db:"subject"`
+	Text       string    `db:"text"`
+	HTML       string    `db:"html"`
+	Markdown   string    `db:"markdown"`
+	Layout     string    `db:"layout"`
+	Metadata   string    `db:"metadata"`
+	ClientID   string    `db:"client_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func NewTemplateVersion(template Template, clientID string) TemplateVersion {
+	return TemplateVersion{
+		TemplateID: template.ID,
+		Version:    template.Version,
+		Name:       template.Name,
+		Subject:    template.Subject,
+		Text:       template.Text,
+		HTML:       template.HTML,
+		Markdown:   template.Markdown,
+		Layout:     template.Layout,
+		Metadata:   template.Metadata,
+		ClientID:   clientID,
+	}
+}
+
+func (v *TemplateVersion) PreInsert(s gorp.SqlExecutor) error {
+	v.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
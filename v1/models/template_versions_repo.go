@@ -0,0 +1,50 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type TemplateVersionsRepo struct{}
+
+func NewTemplateVersionsRepo() TemplateVersionsRepo {
+	return TemplateVersionsRepo{}
+}
+
+func (repo TemplateVersionsRepo) Create(conn ConnectionInterface, version TemplateVersion) (TemplateVersion, error) {
+	err := conn.Insert(&version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return version, nil
+}
+
+func (repo TemplateVersionsRepo) Find(conn ConnectionInterface, templateID string, version int) (TemplateVersion, error) {
+	templateVersion := TemplateVersion{}
+	err := conn.SelectOne(&templateVersion, "SELECT * FROM `template_versions` WHERE `template_id` = ? AND `version` = ?", templateID, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return templateVersion, NotFoundError{fmt.Errorf("Version %d of template %q could not be found", version, templateID)}
+		}
+		return templateVersion, err
+	}
+
+	return templateVersion, nil
+}
+
+func (repo TemplateVersionsRepo) List(conn ConnectionInterface, templateID string) ([]TemplateVersion, error) {
+	versions := []TemplateVersion{}
+	_, err := conn.Select(&versions, "SELECT * FROM `template_versions` WHERE `template_id` = ? ORDER BY `version` DESC", templateID)
+	if err != nil {
+		return []TemplateVersion{}, err
+	}
+
+	return versions, nil
+}
+
+func (repo TemplateVersionsRepo) DestroyAll(conn ConnectionInterface, templateID string) error {
+	_, err := conn.Exec("DELETE FROM `template_versions` WHERE `template_id` = ?", templateID)
+
+	return err
+}
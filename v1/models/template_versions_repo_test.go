@@ -0,0 +1,97 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionsRepo", func() {
+	var (
+		repo models.TemplateVersionsRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		repo = models.NewTemplateVersionsRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+	})
+
+	Describe("Create", func() {
+		It("inserts the version into the database", func() {
+			_, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Version:    1,
+				Name:       "Raptors",
+				Subject:    "{{.Subject}}",
+				Text:       "run",
+				HTML:       "<p>run</p>",
+				Metadata:   "{}",
+				ClientID:   "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			version, err := repo.Find(conn, "some-template-id", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Name).To(Equal("Raptors"))
+			Expect(version.Text).To(Equal("run"))
+			Expect(version.ClientID).To(Equal("some-client-id"))
+			Expect(version.CreatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
+		})
+
+		It("does not allow the same version to be written twice", func() {
+			_, err := repo.Create(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: 1})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns a not found error when the version does not exist", func() {
+			_, err := repo.Find(conn, "some-template-id", 4)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Version 4 of template \"some-template-id\" could not be found")}))
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the versions of the template, newest first", func() {
+			for _, version := range []int{1, 2, 3} {
+				_, err := repo.Create(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: version})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := repo.Create(conn, models.TemplateVersion{TemplateID: "other-template-id", Version: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(3))
+			Expect(versions[0].Version).To(Equal(3))
+			Expect(versions[2].Version).To(Equal(1))
+		})
+	})
+
+	Describe("DestroyAll", func() {
+		It("removes every version of the template", func() {
+			_, err := repo.Create(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.DestroyAll(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(BeEmpty())
+		})
+	})
+})
@@ -13,8 +13,12 @@ func NewTemplatesRepo() TemplatesRepo {
 }
 
 func (repo TemplatesRepo) FindByID(conn ConnectionInterface, templateID string) (Template, error) {
+	return repo.find(conn, "SELECT * FROM `templates` WHERE `id`=?", templateID)
+}
+
+func (repo TemplatesRepo) find(conn ConnectionInterface, query, templateID string) (Template, error) {
 	template := Template{}
-	err := conn.SelectOne(&template, "SELECT * FROM `templates` WHERE `id`=?", templateID)
+	err := conn.SelectOne(&template, query, templateID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return template, NotFoundError{fmt.Errorf("Template with ID %q could not be found", templateID)}
@@ -24,8 +28,17 @@ func (repo TemplatesRepo) FindByID(conn ConnectionInterface, templateID string)
 	return template, nil
 }
 
+// FindForUpdate finds the template and locks it until the surrounding
+// transaction ends.
+func (repo TemplatesRepo) FindForUpdate(conn ConnectionInterface, templateID string) (Template, error) {
+	return repo.find(conn, "SELECT * FROM `templates` WHERE `id`=? FOR UPDATE", templateID)
+}
+
+// Update saves the template as its next version. The template is locked
+// until the surrounding transaction ends, so that concurrent updates are
+// numbered one after the other instead of claiming the same version.
 func (repo TemplatesRepo) Update(conn ConnectionInterface, templateID string, template Template) (Template, error) {
-	existingTemplate, err := repo.FindByID(conn, templateID)
+	existingTemplate, err := repo.FindForUpdate(conn, templateID)
 	if err != nil {
 		return existingTemplate, err
 	}
@@ -35,6 +48,7 @@ func (repo TemplatesRepo) Update(conn ConnectionInterface, templateID string, te
 	template.CreatedAt = existingTemplate.CreatedAt
 	template.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
 	template.Overridden = true
+	template.Version = existingTemplate.Version + 1
 
 	_, err = conn.Update(&template)
 	if err != nil {
@@ -44,9 +58,45 @@ func (repo TemplatesRepo) Update(conn ConnectionInterface, templateID string, te
 	return template, nil
 }
 
-func (repo TemplatesRepo) ListIDsAndNames(conn ConnectionInterface) ([]Template, error) {
+// TemplatesFilter narrows a listing of templates. Name matches templates
+// whose name contains it and UpdatedSince those updated at or after it.
+type TemplatesFilter struct {
+	Name         string
+	UpdatedSince time.Time
+}
+
+// List returns the IDs and names of the templates other than the default
+// template, ordered by ID.
+func (repo TemplatesRepo) List(conn ConnectionInterface, filter TemplatesFilter, page Page) ([]Template, error) {
+	var query listQuery
+	query.where("`id` != ?", DefaultTemplateID)
+
+	if filter.Name != "" {
+		query.where("`name` LIKE ?", likePattern(filter.Name))
+	}
+
+	if !filter.UpdatedSince.IsZero() {
+		query.where("`updated_at` >= ?", filter.UpdatedSince.UTC())
+	}
+
+	statement, args, err := query.build("SELECT `id`, `name` FROM `templates`", page, "id")
+	if err != nil {
+		return []Template{}, err
+	}
+
+	templates := []Template{}
+	_, err = conn.Select(&templates, statement, args...)
+	if err != nil {
+		return []Template{}, err
+	}
+	return templates, nil
+}
+
+// FindAll returns every template, ordered by name so that listings of the
+// same templates compare equal between deployments.
+func (repo TemplatesRepo) FindAll(conn ConnectionInterface) ([]Template, error) {
 	templates := []Template{}
-	_, err := conn.Select(&templates, "SELECT ID, Name FROM `templates`")
+	_, err := conn.Select(&templates, "SELECT * FROM `templates` ORDER BY `name`, `id`")
 	if err != nil {
 		return []Template{}, err
 	}
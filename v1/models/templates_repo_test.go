@@ -60,6 +60,23 @@ var _ = Describe("TemplatesRepo", func() {
 		})
 	})
 
+	Describe("FindForUpdate", func() {
+		It("returns the template", func() {
+			transaction := conn.Transaction()
+			Expect(transaction.Begin()).To(Succeed())
+
+			raptorTemplate, err := repo.FindForUpdate(transaction, "raptor_template")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(raptorTemplate.Name).To(Equal("Raptors On The Run"))
+			Expect(transaction.Commit()).To(Succeed())
+		})
+
+		It("returns a record not found error when the template does not exist", func() {
+			_, err := repo.FindForUpdate(conn, "silly_template")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Template with ID \"silly_template\" could not be found")}))
+		})
+	})
+
 	Describe("#Create", func() {
 		It("inserts a template into the database", func() {
 			newTemplate := models.Template{
@@ -122,6 +139,43 @@ var _ = Describe("TemplatesRepo", func() {
 				Expect(foundTemplate.UpdatedAt).To(BeTemporally(">", createdAt))
 				Expect(foundTemplate.Overridden).To(BeTrue())
 			})
+
+			It("increments the version of the template", func() {
+				updatedTemplate, err := repo.Update(conn, template.ID, aNewTemplate)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(updatedTemplate.Version).To(Equal(2))
+
+				updatedTemplate, err = repo.Update(conn, template.ID, aNewTemplate)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(updatedTemplate.Version).To(Equal(3))
+			})
+
+			It("numbers concurrent updates one after the other", func() {
+				first := conn.Transaction()
+				Expect(first.Begin()).To(Succeed())
+
+				updatedTemplate, err := repo.Update(first, template.ID, aNewTemplate)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(updatedTemplate.Version).To(Equal(2))
+
+				versions := make(chan int, 1)
+				go func() {
+					defer GinkgoRecover()
+
+					second := conn.Transaction()
+					Expect(second.Begin()).To(Succeed())
+
+					updatedTemplate, err := repo.Update(second, template.ID, aNewTemplate)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(second.Commit()).To(Succeed())
+
+					versions <- updatedTemplate.Version
+				}()
+
+				Consistently(versions, "200ms").ShouldNot(Receive())
+				Expect(first.Commit()).To(Succeed())
+				Eventually(versions, "10s").Should(Receive(Equal(3)))
+			})
 		})
 
 		Context("the template does not exist in the database", func() {
@@ -132,37 +186,108 @@ var _ = Describe("TemplatesRepo", func() {
 		})
 	})
 
-	Describe("#ListIDsAndNames", func() {
-		Context("there are templates in the database", func() {
-			It("returns a list of templates - ID and Name only", func() {
-				secondTemplate := models.Template{
+	Describe("#List", func() {
+		BeforeEach(func() {
+			templates := []models.Template{
+				{
 					ID:        "star_template",
 					Name:      "Shooting Stars",
 					Text:      "pretty",
 					HTML:      "<h1>Awe</h1>",
 					CreatedAt: createdAt,
-				}
+				},
+				{
+					ID:        "percent_template",
+					Name:      "100% Raptors",
+					CreatedAt: createdAt,
+				},
+				{
+					ID:        models.DefaultTemplateID,
+					Name:      "Default Template",
+					CreatedAt: createdAt,
+				},
+			}
 
-				conn.Insert(&secondTemplate)
-
-				expectedMetadata := []models.Template{
-					{
-						ID:   "raptor_template",
-						Name: "Raptors On The Run",
-					},
-					{
-						ID:   "star_template",
-						Name: "Shooting Stars",
-					},
-				}
-				templatesMetadata, err := repo.ListIDsAndNames(conn)
+			for i := range templates {
+				err := conn.Insert(&templates[i])
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
 
-				Expect(err).ToNot(HaveOccurred())
-				Expect(templatesMetadata).To(Equal(expectedMetadata))
-				Expect(templatesMetadata[0].Text).To(BeEmpty())
-				Expect(templatesMetadata[0].HTML).To(BeEmpty())
-				Expect(templatesMetadata[0].Subject).To(BeEmpty())
+		It("returns the IDs and names of the templates other than the default, ordered by ID", func() {
+			templates, err := repo.List(conn, models.TemplatesFilter{}, models.Page{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(templates).To(Equal([]models.Template{
+				{ID: "percent_template", Name: "100% Raptors"},
+				{ID: "raptor_template", Name: "Raptors On The Run"},
+				{ID: "star_template", Name: "Shooting Stars"},
+			}))
+		})
+
+		It("returns the templates after the page key, up to the limit", func() {
+			templates, err := repo.List(conn, models.TemplatesFilter{}, models.Page{
+				After: []string{"percent_template"},
+				Limit: 1,
 			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(templates).To(Equal([]models.Template{
+				{ID: "raptor_template", Name: "Raptors On The Run"},
+			}))
+		})
+
+		It("returns the templates whose name contains the filter", func() {
+			templates, err := repo.List(conn, models.TemplatesFilter{Name: "raptors"}, models.Page{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(templates).To(HaveLen(2))
+
+			templates, err = repo.List(conn, models.TemplatesFilter{Name: "0%"}, models.Page{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(templates).To(Equal([]models.Template{
+				{ID: "percent_template", Name: "100% Raptors"},
+			}))
+		})
+
+		It("returns the templates updated since the filter", func() {
+			_, err := repo.Update(conn, "star_template", models.Template{Name: "Shooting Stars"})
+			Expect(err).ToNot(HaveOccurred())
+
+			templates, err := repo.List(conn, models.TemplatesFilter{
+				UpdatedSince: time.Now().Add(-1 * time.Minute),
+			}, models.Page{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(templates).To(Equal([]models.Template{
+				{ID: "star_template", Name: "Shooting Stars"},
+			}))
+		})
+
+		It("rejects page keys of another listing", func() {
+			_, err := repo.List(conn, models.TemplatesFilter{}, models.Page{
+				After: []string{"some-client", "some-kind"},
+			})
+			Expect(err).To(BeAssignableToTypeOf(models.PageKeyError{}))
+		})
+	})
+
+	Describe("#FindAll", func() {
+		It("returns every template ordered by name", func() {
+			secondTemplate := models.Template{
+				ID:        "star_template",
+				Name:      "Aurora",
+				Text:      "pretty",
+				HTML:      "<h1>Awe</h1>",
+				CreatedAt: createdAt,
+			}
+
+			conn.Insert(&secondTemplate)
+
+			templates, err := repo.FindAll(conn)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(templates).To(HaveLen(2))
+			Expect(templates[0].ID).To(Equal("star_template"))
+			Expect(templates[0].HTML).To(Equal("<h1>Awe</h1>"))
+			Expect(templates[1].ID).To(Equal("raptor_template"))
+			Expect(templates[1].Text).To(Equal("run and hide"))
 		})
 	})
 
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+type UserSettings struct {
+	Primary         int       `db:"primary"`
+	UserID          string    `db:"user_id"`
+	Locale          string    `db:"locale"`
+	Digest          string    `db:"digest"`
+	Timezone        string    `db:"timezone"`
+	QuietHoursStart string    `db:"quiet_hours_start"`
+	QuietHoursEnd   string    `db:"quiet_hours_end"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+func (s *UserSettings) PreInsert(e gorp.SqlExecutor) error {
+	s.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	s.UpdatedAt = s.CreatedAt
+
+	return nil
+}
+
+func (s *UserSettings) PreUpdate(e gorp.SqlExecutor) error {
+	s.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
+
+// QuietHoursLayout is the layout of the times of day quiet hours start and
+// end at, such as "22:00".
+const QuietHoursLayout = "15:04"
+
+// Location returns the user's timezone, which is UTC when they have not
+// chosen one.
+func (s UserSettings) Location() (*time.Location, error) {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", s.Timezone)
+	}
+
+	return location, nil
+}
+
+// QuietUntil returns when the quiet hours the given time falls into end.
+// Quiet hours are read in the user's timezone, or in UTC when they have not
+// chosen one, and wrap around midnight when they end before they start. The
+// second value is false when the time is outside of quiet hours.
+func (s UserSettings) QuietUntil(now time.Time) (time.Time, bool, error) {
+	if s.QuietHoursStart == "" || s.QuietHoursEnd == "" {
+		return time.Time{}, false, nil
+	}
+
+	location, err := s.Location()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	start, err := time.Parse(QuietHoursLayout, s.QuietHoursStart)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	end, err := time.Parse(QuietHoursLayout, s.QuietHoursEnd)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	if start.Equal(end) {
+		return time.Time{}, false, errors.New("quiet hours must not start and end at the same time")
+	}
+
+	local := now.In(location)
+	minute := minuteOfDay(local)
+	startMinute, endMinute := minuteOfDay(start), minuteOfDay(end)
+
+	day := local.Day()
+	switch {
+	case startMinute < endMinute && minute >= startMinute && minute < endMinute:
+	case startMinute > endMinute && minute >= startMinute:
+		day++
+	case startMinute > endMinute && minute < endMinute:
+	default:
+		return time.Time{}, false, nil
+	}
+
+	return time.Date(local.Year(), local.Month(), day, end.Hour(), end.Minute(), 0, 0, location), true, nil
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
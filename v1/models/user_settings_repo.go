@@ -0,0 +1,54 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type UserSettingsRepo struct{}
+
+func NewUserSettingsRepo() UserSettingsRepo {
+	return UserSettingsRepo{}
+}
+
+func (repo UserSettingsRepo) Find(conn ConnectionInterface, userID string) (UserSettings, error) {
+	settings := UserSettings{}
+	err := conn.SelectOne(&settings, "SELECT * FROM `user_settings` WHERE `user_id` = ?", userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return settings, NotFoundError{fmt.Errorf("Settings for user %q could not be found", userID)}
+		}
+		return settings, err
+	}
+
+	return settings, nil
+}
+
+func (repo UserSettingsRepo) Upsert(conn ConnectionInterface, settings UserSettings) (UserSettings, error) {
+	existing, err := repo.Find(conn, settings.UserID)
+	if err != nil {
+		if _, ok := err.(NotFoundError); !ok {
+			return UserSettings{}, err
+		}
+
+		err = conn.Insert(&settings)
+		if err != nil {
+			return UserSettings{}, err
+		}
+
+		return settings, nil
+	}
+
+	existing.Locale = settings.Locale
+	existing.Digest = settings.Digest
+	existing.Timezone = settings.Timezone
+	existing.QuietHoursStart = settings.QuietHoursStart
+	existing.QuietHoursEnd = settings.QuietHoursEnd
+
+	_, err = conn.Update(&existing)
+	if err != nil {
+		return UserSettings{}, err
+	}
+
+	return existing, nil
+}
@@ -0,0 +1,89 @@
+package models_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UserSettingsRepo", func() {
+	var (
+		repo models.UserSettingsRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		repo = models.NewUserSettingsRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+	})
+
+	Describe("Upsert", func() {
+		It("creates the settings when the user has none", func() {
+			_, err := repo.Upsert(conn, models.UserSettings{UserID: "some-user-id", Locale: "de-CH"})
+			Expect(err).NotTo(HaveOccurred())
+
+			settings, err := repo.Find(conn, "some-user-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.Locale).To(Equal("de-CH"))
+		})
+
+		It("updates the existing settings", func() {
+			_, err := repo.Upsert(conn, models.UserSettings{UserID: "some-user-id", Locale: "de-CH"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Upsert(conn, models.UserSettings{UserID: "some-user-id", Locale: "fr"})
+			Expect(err).NotTo(HaveOccurred())
+
+			settings, err := repo.Find(conn, "some-user-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.Locale).To(Equal("fr"))
+		})
+
+		It("updates the digest of the settings", func() {
+			_, err := repo.Upsert(conn, models.UserSettings{UserID: "some-user-id", Locale: "de-CH"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Upsert(conn, models.UserSettings{UserID: "some-user-id", Locale: "de-CH", Digest: models.DigestDaily})
+			Expect(err).NotTo(HaveOccurred())
+
+			settings, err := repo.Find(conn, "some-user-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.Locale).To(Equal("de-CH"))
+			Expect(settings.Digest).To(Equal(models.DigestDaily))
+		})
+
+		It("updates the timezone and quiet hours of the settings", func() {
+			_, err := repo.Upsert(conn, models.UserSettings{UserID: "some-user-id", Locale: "de-CH"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Upsert(conn, models.UserSettings{
+				UserID:          "some-user-id",
+				Locale:          "de-CH",
+				Timezone:        "America/New_York",
+				QuietHoursStart: "22:00",
+				QuietHoursEnd:   "07:00",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			settings, err := repo.Find(conn, "some-user-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.Timezone).To(Equal("America/New_York"))
+			Expect(settings.QuietHoursStart).To(Equal("22:00"))
+			Expect(settings.QuietHoursEnd).To(Equal("07:00"))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns a not found error when the user has no settings", func() {
+			_, err := repo.Find(conn, "missing-user-id")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Settings for user \"missing-user-id\" could not be found")}))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UserSettings", func() {
+	Describe("QuietUntil", func() {
+		var (
+			settings models.UserSettings
+			newYork  *time.Location
+		)
+
+		BeforeEach(func() {
+			var err error
+			newYork, err = time.LoadLocation("America/New_York")
+			Expect(err).NotTo(HaveOccurred())
+
+			settings = models.UserSettings{
+				Timezone:        "America/New_York",
+				QuietHoursStart: "22:00",
+				QuietHoursEnd:   "07:30",
+			}
+		})
+
+		It("returns the end of quiet hours that wrap around midnight", func() {
+			until, quiet, err := settings.QuietUntil(time.Date(2016, time.March, 4, 23, 15, 0, 0, newYork))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quiet).To(BeTrue())
+			Expect(until).To(Equal(time.Date(2016, time.March, 5, 7, 30, 0, 0, newYork)))
+
+			until, quiet, err = settings.QuietUntil(time.Date(2016, time.March, 5, 8, 15, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quiet).To(BeTrue())
+			Expect(until).To(Equal(time.Date(2016, time.March, 5, 7, 30, 0, 0, newYork)))
+		})
+
+		It("returns the end of quiet hours within a day", func() {
+			settings.QuietHoursStart = "12:00"
+			settings.QuietHoursEnd = "13:00"
+
+			until, quiet, err := settings.QuietUntil(time.Date(2016, time.March, 4, 12, 0, 0, 0, newYork))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quiet).To(BeTrue())
+			Expect(until).To(Equal(time.Date(2016, time.March, 4, 13, 0, 0, 0, newYork)))
+		})
+
+		It("is not quiet outside of quiet hours", func() {
+			_, quiet, err := settings.QuietUntil(time.Date(2016, time.March, 4, 7, 30, 0, 0, newYork))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quiet).To(BeFalse())
+
+			_, quiet, err = settings.QuietUntil(time.Date(2016, time.March, 4, 21, 59, 0, 0, newYork))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quiet).To(BeFalse())
+		})
+
+		It("reads quiet hours in UTC when the user has no timezone", func() {
+			settings.Timezone = ""
+
+			until, quiet, err := settings.QuietUntil(time.Date(2016, time.March, 4, 23, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quiet).To(BeTrue())
+			Expect(until).To(Equal(time.Date(2016, time.March, 5, 7, 30, 0, 0, time.UTC)))
+		})
+
+		It("is never quiet when the user has no quiet hours", func() {
+			settings.QuietHoursStart = ""
+			settings.QuietHoursEnd = ""
+
+			_, quiet, err := settings.QuietUntil(time.Date(2016, time.March, 4, 23, 0, 0, 0, newYork))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quiet).To(BeFalse())
+		})
+
+		It("returns an error when the timezone is unknown", func() {
+			settings.Timezone = "Mars/Olympus_Mons"
+
+			_, _, err := settings.QuietUntil(time.Now())
+			Expect(err).To(MatchError(`unknown time zone "Mars/Olympus_Mons"`))
+		})
+	})
+})
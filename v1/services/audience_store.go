@@ -0,0 +1,147 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+// Audience is a named list of batch targets that a client can send to
+// without naming the targets again. The targets are resolved each time the
+// audience is sent to, so it reaches whoever they stand for at that time.
+type Audience struct {
+	ID        string
+	Name      string
+	Targets   []BatchTarget
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type audiencesRepository interface {
+	Create(models.ConnectionInterface, models.Audience) (models.Audience, error)
+	Find(conn models.ConnectionInterface, clientID, id string) (models.Audience, error)
+	List(conn models.ConnectionInterface, clientID string, page models.Page) ([]models.Audience, error)
+	Update(models.ConnectionInterface, models.Audience) (models.Audience, error)
+	Destroy(conn models.ConnectionInterface, clientID, id string) error
+}
+
+type storedAudienceTarget struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Role string `json:"role,omitempty"`
+}
+
+// AudienceStore keeps the audiences of each client apart; a client can
+// only see and change the audiences it created.
+type AudienceStore struct {
+	repo audiencesRepository
+}
+
+func NewAudienceStore(repo audiencesRepository) AudienceStore {
+	return AudienceStore{
+		repo: repo,
+	}
+}
+
+func (s AudienceStore) Create(conn ConnectionInterface, clientID string, audience Audience) (Audience, error) {
+	model, err := s.repo.Create(conn, models.Audience{
+		ClientID: clientID,
+		Name:     audience.Name,
+		Targets:  encodeAudienceTargets(audience.Targets),
+	})
+	if err != nil {
+		return Audience{}, err
+	}
+
+	return newAudience(model)
+}
+
+func (s AudienceStore) Get(conn ConnectionInterface, clientID, id string) (Audience, error) {
+	model, err := s.repo.Find(conn, clientID, id)
+	if err != nil {
+		return Audience{}, err
+	}
+
+	return newAudience(model)
+}
+
+// List returns the audiences of the client on the given page, along with
+// the key of the page that follows, which is nil on the last page.
+func (s AudienceStore) List(conn ConnectionInterface, clientID string, page models.Page) ([]Audience, []string, error) {
+	records, err := s.repo.List(conn, clientID, page.Peek())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next []string
+	if page.More(len(records)) {
+		records = records[:page.Limit]
+		next = []string{records[len(records)-1].ID}
+	}
+
+	audiences := []Audience{}
+	for _, model := range records {
+		audience, err := newAudience(model)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		audiences = append(audiences, audience)
+	}
+
+	return audiences, next, nil
+}
+
+func (s AudienceStore) Update(conn ConnectionInterface, clientID string, audience Audience) (Audience, error) {
+	model, err := s.repo.Update(conn, models.Audience{
+		ID:       audience.ID,
+		ClientID: clientID,
+		Name:     audience.Name,
+		Targets:  encodeAudienceTargets(audience.Targets),
+	})
+	if err != nil {
+		return Audience{}, err
+	}
+
+	return newAudience(model)
+}
+
+func (s AudienceStore) Delete(conn ConnectionInterface, clientID, id string) error {
+	return s.repo.Destroy(conn, clientID, id)
+}
+
+func encodeAudienceTargets(targets []BatchTarget) string {
+	stored := []storedAudienceTarget{}
+	for _, target := range targets {
+		stored = append(stored, storedAudienceTarget(target))
+	}
+
+	output, err := json.Marshal(stored)
+	if err != nil {
+		panic(err) // a list of strings always marshals
+	}
+
+	return string(output)
+}
+
+func newAudience(model models.Audience) (Audience, error) {
+	var stored []storedAudienceTarget
+	err := json.Unmarshal([]byte(model.Targets), &stored)
+	if err != nil {
+		return Audience{}, err
+	}
+
+	audience := Audience{
+		ID:        model.ID,
+		Name:      model.Name,
+		Targets:   []BatchTarget{},
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+	for _, target := range stored {
+		audience.Targets = append(audience.Targets, BatchTarget(target))
+	}
+
+	return audience, nil
+}
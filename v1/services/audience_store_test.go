@@ -0,0 +1,165 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AudienceStore", func() {
+	var (
+		store services.AudienceStore
+		repo  *mocks.AudiencesRepo
+		conn  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		repo = mocks.NewAudiencesRepo()
+		conn = mocks.NewConnection()
+		store = services.NewAudienceStore(repo)
+	})
+
+	Describe("Create", func() {
+		It("stores the targets of the audience for the client", func() {
+			repo.CreateCall.Returns.Audience = models.Audience{
+				ID:       "some-audience-id",
+				ClientID: "some-client",
+				Name:     "managers",
+				Targets:  `[{"type":"organization","id":"org-001","role":"OrgManager"},{"type":"scope","id":"sre"}]`,
+			}
+
+			audience, err := store.Create(conn, "some-client", services.Audience{
+				Name: "managers",
+				Targets: []services.BatchTarget{
+					{Type: services.BatchTargetOrganization, ID: "org-001", Role: "OrgManager"},
+					{Type: services.BatchTargetScope, ID: "sre"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(repo.CreateCall.Receives.Connection).To(Equal(conn))
+			Expect(repo.CreateCall.Receives.Audience).To(Equal(models.Audience{
+				ClientID: "some-client",
+				Name:     "managers",
+				Targets:  `[{"type":"organization","id":"org-001","role":"OrgManager"},{"type":"scope","id":"sre"}]`,
+			}))
+
+			Expect(audience).To(Equal(services.Audience{
+				ID:   "some-audience-id",
+				Name: "managers",
+				Targets: []services.BatchTarget{
+					{Type: services.BatchTargetOrganization, ID: "org-001", Role: "OrgManager"},
+					{Type: services.BatchTargetScope, ID: "sre"},
+				},
+			}))
+		})
+
+		It("returns errors from the repo", func() {
+			repo.CreateCall.Returns.Error = errors.New("database is gone")
+
+			_, err := store.Create(conn, "some-client", services.Audience{Name: "managers"})
+			Expect(err).To(MatchError("database is gone"))
+		})
+	})
+
+	Describe("Get", func() {
+		It("finds the audience of the client", func() {
+			repo.FindCall.Returns.Audience = models.Audience{
+				ID:      "some-audience-id",
+				Name:    "managers",
+				Targets: `[{"type":"user","id":"user-123"}]`,
+			}
+
+			audience, err := store.Get(conn, "some-client", "some-audience-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(repo.FindCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(repo.FindCall.Receives.ID).To(Equal("some-audience-id"))
+			Expect(audience.Targets).To(Equal([]services.BatchTarget{
+				{Type: services.BatchTargetUser, ID: "user-123"},
+			}))
+		})
+
+		It("returns errors from the repo", func() {
+			repo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := store.Get(conn, "some-client", "some-audience-id")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+	})
+
+	Describe("List", func() {
+		It("lists the audiences of the client", func() {
+			repo.ListCall.Returns.Audiences = []models.Audience{
+				{ID: "audience-1", Name: "managers", Targets: `[]`},
+				{ID: "audience-2", Name: "sre", Targets: `[{"type":"scope","id":"sre"}]`},
+			}
+
+			audiences, next, err := store.List(conn, "some-client", models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(BeNil())
+
+			Expect(repo.ListCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(audiences).To(HaveLen(2))
+			Expect(audiences[0].Targets).To(BeEmpty())
+			Expect(audiences[1].Name).To(Equal("sre"))
+		})
+
+		It("returns the key of the next page when there are more audiences", func() {
+			repo.ListCall.Returns.Audiences = []models.Audience{
+				{ID: "audience-1", Name: "managers", Targets: `[]`},
+				{ID: "audience-2", Name: "sre", Targets: `[]`},
+			}
+
+			audiences, next, err := store.List(conn, "some-client", models.Page{
+				After: []string{"audience-0"},
+				Limit: 1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(audiences).To(HaveLen(1))
+			Expect(audiences[0].ID).To(Equal("audience-1"))
+			Expect(next).To(Equal([]string{"audience-1"}))
+
+			Expect(repo.ListCall.Receives.Page).To(Equal(models.Page{
+				After: []string{"audience-0"},
+				Limit: 2,
+			}))
+		})
+	})
+
+	Describe("Update", func() {
+		It("replaces the name and targets of the audience", func() {
+			repo.UpdateCall.Returns.Audience = models.Audience{ID: "some-audience-id", Name: "sre", Targets: `[{"type":"scope","id":"sre"}]`}
+
+			audience, err := store.Update(conn, "some-client", services.Audience{
+				ID:      "some-audience-id",
+				Name:    "sre",
+				Targets: []services.BatchTarget{{Type: services.BatchTargetScope, ID: "sre"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(repo.UpdateCall.Receives.Audience).To(Equal(models.Audience{
+				ID:       "some-audience-id",
+				ClientID: "some-client",
+				Name:     "sre",
+				Targets:  `[{"type":"scope","id":"sre"}]`,
+			}))
+			Expect(audience.Name).To(Equal("sre"))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("destroys the audience of the client", func() {
+			err := store.Delete(conn, "some-client", "some-audience-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(repo.DestroyCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(repo.DestroyCall.Receives.ID).To(Equal("some-audience-id"))
+		})
+	})
+})
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+const (
+	BatchTargetUser         = "user"
+	BatchTargetEmail        = "email"
+	BatchTargetSpace        = "space"
+	BatchTargetOrganization = "organization"
+	BatchTargetScope        = "scope"
+)
+
+// BatchTarget names one recipient set of a batch. ID holds the user, space
+// or organization GUID, the email address or the scope, depending on Type.
+type BatchTarget struct {
+	Type string
+	ID   string
+	Role string
+}
+
+// BatchTargetResult reports what a batch did for one of its targets. Users
+// the target resolved to that an earlier target already reached are
+// counted as Duplicates rather than sent the message twice. A target that
+// could not be resolved carries its Error and sends nothing.
+type BatchTargetResult struct {
+	Target     BatchTarget
+	Responses  []Response
+	Duplicates int
+	Error      error
+}
+
+type BatchResult struct {
+	ID      string
+	Targets []BatchTargetResult
+}
+
+type resolver interface {
+	Resolve(dispatch Dispatch) (Resolution, error)
+}
+
+type deliveriesEnqueuer interface {
+	EnqueueDeliveries(conn ConnectionInterface, batch models.Batch, deliveries []Delivery) ([]Response, error)
+}
+
+type idGenerator interface {
+	Generate() (string, error)
+}
+
+type BatchDispatcher struct {
+	resolvers   map[string]resolver
+	enqueuer    deliveriesEnqueuer
+	idGenerator idGenerator
+}
+
+func NewBatchDispatcher(userStrategy, emailStrategy, spaceStrategy, organizationStrategy, scopeStrategy resolver, enqueuer deliveriesEnqueuer, idGenerator idGenerator) BatchDispatcher {
+	return BatchDispatcher{
+		resolvers: map[string]resolver{
+			BatchTargetUser:         userStrategy,
+			BatchTargetEmail:        emailStrategy,
+			BatchTargetSpace:        spaceStrategy,
+			BatchTargetOrganization: organizationStrategy,
+			BatchTargetScope:        scopeStrategy,
+		},
+		enqueuer:    enqueuer,
+		idGenerator: idGenerator,
+	}
+}
+
+// Dispatch resolves every target through the strategy of its type, drops
+// the users that were already reached by an earlier target, and enqueues
+// the remaining deliveries in one pass, recorded as a batch even when no
+// target reached anyone.
+func (d BatchDispatcher) Dispatch(dispatch Dispatch, targets []BatchTarget) (BatchResult, error) {
+	id, err := d.idGenerator.Generate()
+	if err != nil {
+		return BatchResult{}, err
+	}
+
+	targetResults, deliveries, owners := d.resolveTargets(dispatch, targets)
+	result := BatchResult{
+		ID:      id,
+		Targets: targetResults,
+	}
+
+	batch := models.Batch{
+		ID:       id,
+		ClientID: dispatch.Client.ID,
+		KindID:   dispatch.Kind.ID,
+	}
+
+	responses, err := d.enqueuer.EnqueueDeliveries(dispatch.Connection, batch, deliveries)
+	if err != nil {
+		return BatchResult{}, err
+	}
+
+	for i, response := range responses {
+		owner := &result.Targets[owners[i]]
+		owner.Responses = append(owner.Responses, response)
+	}
+
+	return result, nil
+}
+
+// Resolve resolves every target the way Dispatch does without enqueuing
+// anything. The resolution holds each user the batch would reach once.
+func (d BatchDispatcher) Resolve(dispatch Dispatch, targets []BatchTarget) (Resolution, []BatchTargetResult) {
+	targetResults, deliveries, _ := d.resolveTargets(dispatch, targets)
+
+	resolution := Resolution{Users: []User{}}
+	for _, delivery := range deliveries {
+		resolution.Users = append(resolution.Users, User{GUID: delivery.UserGUID, Email: delivery.Email})
+	}
+
+	return resolution, targetResults
+}
+
+// resolveTargets returns the result of each target along with the
+// deliveries of the users no earlier target reached, and the index of the
+// target each delivery belongs to. Users are told apart by GUID and email
+// targets by their address. The address of a user reached by GUID is only
+// loaded when the message is sent, so a user named both ways is sent the
+// message twice.
+func (d BatchDispatcher) resolveTargets(dispatch Dispatch, targets []BatchTarget) ([]BatchTargetResult, []Delivery, []int) {
+	var (
+		deliveries []Delivery
+		owners     []int
+	)
+	results := []BatchTargetResult{}
+	reached := map[string]bool{}
+
+	for i, target := range targets {
+		targetResult := BatchTargetResult{
+			Target:    target,
+			Responses: []Response{},
+		}
+
+		resolution, err := d.resolve(dispatch, target)
+		if err != nil {
+			targetResult.Error = err
+		}
+
+		for _, user := range resolution.Users {
+			key := user.GUID
+			if key == "" {
+				key = "email:" + strings.ToLower(user.Email)
+			}
+
+			if reached[key] {
+				targetResult.Duplicates++
+				continue
+			}
+			reached[key] = true
+
+			deliveries = append(deliveries, resolution.delivery(user, dispatch))
+			owners = append(owners, i)
+		}
+
+		results = append(results, targetResult)
+	}
+
+	return results, deliveries, owners
+}
+
+func (d BatchDispatcher) resolve(dispatch Dispatch, target BatchTarget) (Resolution, error) {
+	resolver, ok := d.resolvers[target.Type]
+	if !ok {
+		return Resolution{}, fmt.Errorf("unknown target type %q", target.Type)
+	}
+
+	dispatch.Role = target.Role
+	if target.Type == BatchTargetEmail {
+		dispatch.Message.To = target.ID
+	} else {
+		dispatch.GUID = target.ID
+	}
+
+	return resolver.Resolve(dispatch)
+}
@@ -0,0 +1,274 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BatchDispatcher", func() {
+	var (
+		dispatcher           services.BatchDispatcher
+		userStrategy         *mocks.Strategy
+		emailStrategy        *mocks.Strategy
+		spaceStrategy        *mocks.Strategy
+		organizationStrategy *mocks.Strategy
+		scopeStrategy        *mocks.Strategy
+		enqueuer             *mocks.Enqueuer
+		idGenerator          *mocks.IDGenerator
+		conn                 *mocks.Connection
+		dispatch             services.Dispatch
+		space                cf.CloudControllerSpace
+	)
+
+	BeforeEach(func() {
+		userStrategy = mocks.NewStrategy()
+		emailStrategy = mocks.NewStrategy()
+		spaceStrategy = mocks.NewStrategy()
+		organizationStrategy = mocks.NewStrategy()
+		scopeStrategy = mocks.NewStrategy()
+
+		enqueuer = mocks.NewEnqueuer()
+		idGenerator = mocks.NewIDGenerator()
+		idGenerator.GenerateCall.Returns.IDs = []string{"some-batch-id"}
+
+		conn = mocks.NewConnection()
+		dispatch = services.Dispatch{
+			Connection: conn,
+			Client:     services.DispatchClient{ID: "some-client"},
+			UAAHost:    "uaa-host",
+			Kind:       services.DispatchKind{ID: "some-kind"},
+			VCAPRequest: services.DispatchVCAPRequest{
+				ID: "some-request-id",
+			},
+		}
+
+		space = cf.CloudControllerSpace{GUID: "some-space", Name: "some-space-name"}
+
+		userStrategy.ResolveCalls = []mocks.StrategyResolveCall{
+			mocks.NewStrategyResolveCall(services.Resolution{
+				Users:   []services.User{{GUID: "user-1"}},
+				Options: services.Options{Endorsement: services.UserEndorsement},
+			}, nil),
+		}
+		emailStrategy.ResolveCalls = []mocks.StrategyResolveCall{
+			mocks.NewStrategyResolveCall(services.Resolution{
+				Users:   []services.User{{Email: "Someone@example.com"}},
+				Options: services.Options{Endorsement: services.EmailEndorsement},
+			}, nil),
+			mocks.NewStrategyResolveCall(services.Resolution{
+				Users:   []services.User{{Email: "someone@example.com"}},
+				Options: services.Options{Endorsement: services.EmailEndorsement},
+			}, nil),
+		}
+		spaceStrategy.ResolveCalls = []mocks.StrategyResolveCall{
+			mocks.NewStrategyResolveCall(services.Resolution{
+				Users:   []services.User{{GUID: "user-1"}, {GUID: "user-2"}},
+				Options: services.Options{Endorsement: services.SpaceEndorsement},
+				Space:   space,
+			}, nil),
+		}
+		organizationStrategy.ResolveCalls = []mocks.StrategyResolveCall{
+			mocks.NewStrategyResolveCall(services.Resolution{}, services.CCNotFoundError{Err: errors.New("Organization could not be found")}),
+		}
+
+		enqueuer.EnqueueDeliveriesCall.Returns.Responses = []services.Response{
+			{Status: "queued", Recipient: "user-1", NotificationID: "message-1"},
+			{Status: "queued", Recipient: "Someone@example.com", NotificationID: "message-2"},
+			{Status: "queued", Recipient: "user-2", NotificationID: "message-3"},
+		}
+
+		dispatcher = services.NewBatchDispatcher(userStrategy, emailStrategy, spaceStrategy, organizationStrategy, scopeStrategy, enqueuer, idGenerator)
+	})
+
+	It("resolves each target and enqueues every user once", func() {
+		result, err := dispatcher.Dispatch(dispatch, []services.BatchTarget{
+			{Type: services.BatchTargetUser, ID: "user-1"},
+			{Type: services.BatchTargetEmail, ID: "Someone@example.com"},
+			{Type: services.BatchTargetSpace, ID: "some-space"},
+			{Type: services.BatchTargetEmail, ID: "someone@example.com"},
+			{Type: services.BatchTargetOrganization, ID: "some-org", Role: "OrgManager"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result).To(Equal(services.BatchResult{
+			ID: "some-batch-id",
+			Targets: []services.BatchTargetResult{
+				{
+					Target:    services.BatchTarget{Type: services.BatchTargetUser, ID: "user-1"},
+					Responses: []services.Response{{Status: "queued", Recipient: "user-1", NotificationID: "message-1"}},
+				},
+				{
+					Target:    services.BatchTarget{Type: services.BatchTargetEmail, ID: "Someone@example.com"},
+					Responses: []services.Response{{Status: "queued", Recipient: "Someone@example.com", NotificationID: "message-2"}},
+				},
+				{
+					Target:     services.BatchTarget{Type: services.BatchTargetSpace, ID: "some-space"},
+					Responses:  []services.Response{{Status: "queued", Recipient: "user-2", NotificationID: "message-3"}},
+					Duplicates: 1,
+				},
+				{
+					Target:     services.BatchTarget{Type: services.BatchTargetEmail, ID: "someone@example.com"},
+					Responses:  []services.Response{},
+					Duplicates: 1,
+				},
+				{
+					Target:    services.BatchTarget{Type: services.BatchTargetOrganization, ID: "some-org", Role: "OrgManager"},
+					Responses: []services.Response{},
+					Error:     services.CCNotFoundError{Err: errors.New("Organization could not be found")},
+				},
+			},
+		}))
+
+		Expect(enqueuer.EnqueueDeliveriesCall.Receives.Connection).To(Equal(conn))
+		Expect(enqueuer.EnqueueDeliveriesCall.Receives.Batch).To(Equal(models.Batch{
+			ID:       "some-batch-id",
+			ClientID: "some-client",
+			KindID:   "some-kind",
+		}))
+		Expect(enqueuer.EnqueueDeliveriesCall.Receives.Deliveries).To(Equal([]services.Delivery{
+			{
+				Options:       services.Options{Endorsement: services.UserEndorsement},
+				UserGUID:      "user-1",
+				ClientID:      "some-client",
+				UAAHost:       "uaa-host",
+				VCAPRequestID: "some-request-id",
+			},
+			{
+				Options:       services.Options{Endorsement: services.EmailEndorsement},
+				Email:         "Someone@example.com",
+				ClientID:      "some-client",
+				UAAHost:       "uaa-host",
+				VCAPRequestID: "some-request-id",
+			},
+			{
+				Options:       services.Options{Endorsement: services.SpaceEndorsement},
+				UserGUID:      "user-2",
+				Space:         space,
+				ClientID:      "some-client",
+				UAAHost:       "uaa-host",
+				VCAPRequestID: "some-request-id",
+			},
+		}))
+	})
+
+	It("hands each strategy the dispatch for its target", func() {
+		enqueuer.EnqueueDeliveriesCall.Returns.Responses = []services.Response{
+			{Status: "queued", Recipient: "Someone@example.com", NotificationID: "message-1"},
+		}
+
+		_, err := dispatcher.Dispatch(dispatch, []services.BatchTarget{
+			{Type: services.BatchTargetEmail, ID: "someone@example.com"},
+			{Type: services.BatchTargetOrganization, ID: "some-org", Role: "OrgManager"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		emailDispatch := dispatch
+		emailDispatch.Message.To = "someone@example.com"
+		Expect(emailStrategy.ResolveCalls[0].Receives.Dispatch).To(Equal(emailDispatch))
+
+		organizationDispatch := dispatch
+		organizationDispatch.GUID = "some-org"
+		organizationDispatch.Role = "OrgManager"
+		Expect(organizationStrategy.ResolveCalls[0].Receives.Dispatch).To(Equal(organizationDispatch))
+	})
+
+	It("resolves the users of a batch once without enqueuing anything", func() {
+		resolution, targetResults := dispatcher.Resolve(dispatch, []services.BatchTarget{
+			{Type: services.BatchTargetUser, ID: "user-1"},
+			{Type: services.BatchTargetEmail, ID: "Someone@example.com"},
+			{Type: services.BatchTargetSpace, ID: "some-space"},
+			{Type: services.BatchTargetOrganization, ID: "some-org"},
+		})
+
+		Expect(resolution.Users).To(Equal([]services.User{
+			{GUID: "user-1"},
+			{Email: "Someone@example.com"},
+			{GUID: "user-2"},
+		}))
+		Expect(targetResults).To(Equal([]services.BatchTargetResult{
+			{
+				Target:    services.BatchTarget{Type: services.BatchTargetUser, ID: "user-1"},
+				Responses: []services.Response{},
+			},
+			{
+				Target:    services.BatchTarget{Type: services.BatchTargetEmail, ID: "Someone@example.com"},
+				Responses: []services.Response{},
+			},
+			{
+				Target:     services.BatchTarget{Type: services.BatchTargetSpace, ID: "some-space"},
+				Responses:  []services.Response{},
+				Duplicates: 1,
+			},
+			{
+				Target:    services.BatchTarget{Type: services.BatchTargetOrganization, ID: "some-org"},
+				Responses: []services.Response{},
+				Error:     services.CCNotFoundError{Err: errors.New("Organization could not be found")},
+			},
+		}))
+
+		Expect(idGenerator.GenerateCall.CallCount).To(Equal(0))
+		Expect(enqueuer.EnqueueDeliveriesCall.WasCalled).To(BeFalse())
+	})
+
+	It("cannot tell a user reached by GUID from the same user reached by email address", func() {
+		userStrategy.ResolveCalls = []mocks.StrategyResolveCall{
+			mocks.NewStrategyResolveCall(services.Resolution{
+				Users: []services.User{{GUID: "user-1"}},
+			}, nil),
+		}
+		emailStrategy.ResolveCalls = []mocks.StrategyResolveCall{
+			mocks.NewStrategyResolveCall(services.Resolution{
+				Users: []services.User{{Email: "user-1@example.com"}},
+			}, nil),
+		}
+
+		resolution, targetResults := dispatcher.Resolve(dispatch, []services.BatchTarget{
+			{Type: services.BatchTargetUser, ID: "user-1"},
+			{Type: services.BatchTargetEmail, ID: "user-1@example.com"},
+		})
+
+		Expect(resolution.Users).To(Equal([]services.User{
+			{GUID: "user-1"},
+			{Email: "user-1@example.com"},
+		}))
+		Expect(targetResults[1].Duplicates).To(Equal(0))
+	})
+
+	It("still records the batch when no target resolves to a user", func() {
+		enqueuer.EnqueueDeliveriesCall.Returns.Responses = []services.Response{}
+
+		result, err := dispatcher.Dispatch(dispatch, []services.BatchTarget{
+			{Type: services.BatchTargetOrganization, ID: "some-org"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Targets).To(HaveLen(1))
+		Expect(enqueuer.EnqueueDeliveriesCall.Receives.Batch.ID).To(Equal("some-batch-id"))
+		Expect(enqueuer.EnqueueDeliveriesCall.Receives.Deliveries).To(BeEmpty())
+	})
+
+	It("returns errors from the enqueuer", func() {
+		enqueuer.EnqueueDeliveriesCall.Returns.Error = errors.New("BOOM!")
+
+		_, err := dispatcher.Dispatch(dispatch, []services.BatchTarget{
+			{Type: services.BatchTargetUser, ID: "user-1"},
+		})
+		Expect(err).To(MatchError(errors.New("BOOM!")))
+	})
+
+	It("returns errors from the ID generator", func() {
+		idGenerator.GenerateCall.Returns.IDs = nil
+
+		_, err := dispatcher.Dispatch(dispatch, []services.BatchTarget{
+			{Type: services.BatchTargetUser, ID: "user-1"},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(userStrategy.ResolveCallsCount).To(Equal(0))
+	})
+})
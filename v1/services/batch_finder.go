@@ -0,0 +1,88 @@
+package services
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+// pageKeyTimeFormat writes a time into a page key the way MySQL compares it
+// against a DATETIME column.
+const pageKeyTimeFormat = "2006-01-02 15:04:05"
+
+type Batch struct {
+	ID                    string
+	ClientID              string
+	KindID                string
+	TotalMessages         int
+	QueuedMessages        int
+	SentMessages          int
+	FailedMessages        int
+	UndeliverableMessages int
+	ExpiredMessages       int
+	StartTime             time.Time
+	CompletedTime         *time.Time
+}
+
+type batchesRepoFinder interface {
+	Find(models.ConnectionInterface, string) (models.Batch, error)
+	List(models.ConnectionInterface, models.BatchesFilter, models.Page) ([]models.Batch, error)
+}
+
+type BatchFinder struct {
+	repo batchesRepoFinder
+}
+
+func NewBatchFinder(repo batchesRepoFinder) BatchFinder {
+	return BatchFinder{
+		repo: repo,
+	}
+}
+
+func (finder BatchFinder) Find(database DatabaseInterface, batchID string) (Batch, error) {
+	batch, err := finder.repo.Find(database.Connection(), batchID)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	return newBatch(batch), nil
+}
+
+// List returns the batches matching the filter on the given page, along
+// with the key of the page that follows, which is nil on the last page.
+func (finder BatchFinder) List(database DatabaseInterface, filter models.BatchesFilter, page models.Page) ([]Batch, []string, error) {
+	batches, err := finder.repo.List(database.Connection(), filter, page.Peek())
+	if err != nil {
+		return []Batch{}, nil, err
+	}
+
+	var next []string
+	if page.More(len(batches)) {
+		batches = batches[:page.Limit]
+		last := batches[len(batches)-1]
+		next = []string{last.StartTime.UTC().Format(pageKeyTimeFormat), last.ID}
+	}
+
+	list := []Batch{}
+	for _, batch := range batches {
+		list = append(list, newBatch(batch))
+	}
+
+	return list, next, nil
+}
+
+func newBatch(batch models.Batch) Batch {
+	return Batch{
+		ID:                    batch.ID,
+		ClientID:              batch.ClientID,
+		KindID:                batch.KindID,
+		TotalMessages:         batch.TotalMessages,
+		QueuedMessages:        batch.QueuedMessages,
+		SentMessages:          batch.SentMessages,
+		FailedMessages:        batch.FailedMessages,
+		UndeliverableMessages: batch.UndeliverableMessages,
+		ExpiredMessages:       batch.ExpiredMessages,
+		StartTime:             batch.StartTime,
+		CompletedTime:         batch.CompletedTime,
+	}
+}
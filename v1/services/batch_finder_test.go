@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BatchFinder", func() {
+	var (
+		finder      services.BatchFinder
+		batchesRepo *mocks.BatchesRepo
+		database    *mocks.Database
+		conn        *mocks.Connection
+		startTime   time.Time
+	)
+
+	BeforeEach(func() {
+		batchesRepo = mocks.NewBatchesRepo()
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		startTime = time.Date(2015, time.June, 8, 14, 32, 11, 0, time.UTC)
+
+		finder = services.NewBatchFinder(batchesRepo)
+	})
+
+	Describe("Find", func() {
+		It("returns the batch with its counters", func() {
+			completedTime := startTime.Add(time.Minute)
+			batchesRepo.FindCall.Returns.Batch = models.Batch{
+				ID:                    "some-batch-id",
+				ClientID:              "some-client",
+				KindID:                "some-kind",
+				TotalMessages:         10,
+				QueuedMessages:        0,
+				SentMessages:          7,
+				FailedMessages:        1,
+				UndeliverableMessages: 1,
+				ExpiredMessages:       1,
+				StartTime:             startTime,
+				CompletedTime:         &completedTime,
+			}
+
+			batch, err := finder.Find(database, "some-batch-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batch).To(Equal(services.Batch{
+				ID:                    "some-batch-id",
+				ClientID:              "some-client",
+				KindID:                "some-kind",
+				TotalMessages:         10,
+				QueuedMessages:        0,
+				SentMessages:          7,
+				FailedMessages:        1,
+				UndeliverableMessages: 1,
+				ExpiredMessages:       1,
+				StartTime:             startTime,
+				CompletedTime:         &completedTime,
+			}))
+
+			Expect(batchesRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(batchesRepo.FindCall.Receives.ID).To(Equal("some-batch-id"))
+		})
+
+		It("returns errors from the repo", func() {
+			batchesRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := finder.Find(database, "some-batch-id")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+	})
+
+	Describe("List", func() {
+		BeforeEach(func() {
+			batchesRepo.ListCall.Returns.Batches = []models.Batch{
+				{ID: "batch-1", ClientID: "some-client", StartTime: startTime},
+				{ID: "batch-2", ClientID: "some-client", StartTime: startTime.Add(time.Second)},
+				{ID: "batch-3", ClientID: "some-client", StartTime: startTime.Add(2 * time.Second)},
+			}
+		})
+
+		It("returns every batch matching the filter", func() {
+			batches, next, err := finder.List(database, models.BatchesFilter{ClientID: "some-client"}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batches).To(HaveLen(3))
+			Expect(next).To(BeNil())
+
+			Expect(batchesRepo.ListCall.Receives.Connection).To(Equal(conn))
+			Expect(batchesRepo.ListCall.Receives.Filter).To(Equal(models.BatchesFilter{ClientID: "some-client"}))
+		})
+
+		It("returns the key of the next page when more batches follow", func() {
+			batches, next, err := finder.List(database, models.BatchesFilter{}, models.Page{Limit: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batches).To(HaveLen(2))
+			Expect(batches[1].ID).To(Equal("batch-2"))
+			Expect(next).To(Equal([]string{"2015-06-08 14:32:12", "batch-2"}))
+
+			Expect(batchesRepo.ListCall.Receives.Page).To(Equal(models.Page{Limit: 3}))
+		})
+
+		It("returns errors from the repo", func() {
+			batchesRepo.ListCall.Returns.Error = errors.New("BOOM!")
+
+			_, _, err := finder.List(database, models.BatchesFilter{}, models.Page{})
+			Expect(err).To(MatchError("BOOM!"))
+		})
+	})
+})
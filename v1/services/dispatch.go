@@ -1,6 +1,10 @@
 package services
 
-import "time"
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+)
 
 type Dispatch struct {
 	JobType    string
@@ -30,11 +34,22 @@ type DispatchVCAPRequest struct {
 }
 
 type DispatchMessage struct {
-	To      string
-	ReplyTo string
-	Subject string
-	Text    string
-	HTML    HTML
+	To       string
+	ReplyTo  string
+	Subject  string
+	Text     string
+	HTML     HTML
+	Markdown string
+	Locale   string
+	Data     map[string]interface{}
+
+	// ExpiresAt is when the message stops being worth delivering. It is
+	// zero for messages that never expire.
+	ExpiresAt time.Time
+
+	// DedupKey tells apart messages of the same kind to the same recipient
+	// that must not be collapsed into one.
+	DedupKey string
 }
 
 type DispatchClient struct {
@@ -43,6 +58,47 @@ type DispatchClient struct {
 }
 
 type DispatchKind struct {
-	ID          string
-	Description string
+	ID                  string
+	Description         string
+	SkipTextAlternative bool
+	DedupWindow         time.Duration
+}
+
+// Resolution holds the users a dispatch resolves to, along with what their
+// deliveries are sent with, before any of them is enqueued.
+type Resolution struct {
+	Users        []User
+	Options      Options
+	Space        cf.CloudControllerSpace
+	Organization cf.CloudControllerOrganization
+	Scope        string
+}
+
+func (r Resolution) enqueue(enqueuer enqueuer, dispatch Dispatch) ([]Response, error) {
+	return enqueuer.Enqueue(
+		dispatch.Connection,
+		r.Users,
+		r.Options,
+		r.Space,
+		r.Organization,
+		dispatch.Client.ID,
+		dispatch.UAAHost,
+		r.Scope,
+		dispatch.VCAPRequest.ID,
+		dispatch.VCAPRequest.ReceiptTime)
+}
+
+func (r Resolution) delivery(user User, dispatch Dispatch) Delivery {
+	return Delivery{
+		Options:         r.Options,
+		UserGUID:        user.GUID,
+		Email:           user.Email,
+		Space:           r.Space,
+		Organization:    r.Organization,
+		ClientID:        dispatch.Client.ID,
+		UAAHost:         dispatch.UAAHost,
+		Scope:           r.Scope,
+		VCAPRequestID:   dispatch.VCAPRequest.ID,
+		RequestReceived: dispatch.VCAPRequest.ReceiptTime,
+	}
 }
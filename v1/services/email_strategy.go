@@ -33,16 +33,33 @@ func NewEmailStrategy(enqueuer enqueuer) EmailStrategy {
 }
 
 func (strategy EmailStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
+	resolution, err := strategy.Resolve(dispatch)
+	if err != nil {
+		return []Response{}, err
+	}
+
+	return resolution.enqueue(strategy.enqueuer, dispatch)
+}
+
+func (strategy EmailStrategy) Resolve(dispatch Dispatch) (Resolution, error) {
 	options := Options{
-		To:                dispatch.Message.To,
-		ReplyTo:           dispatch.Message.ReplyTo,
-		Subject:           dispatch.Message.Subject,
-		KindID:            dispatch.Kind.ID,
-		KindDescription:   dispatch.Kind.Description,
-		SourceDescription: dispatch.Client.Description,
-		Endorsement:       EmailEndorsement,
-		Text:              dispatch.Message.Text,
-		TemplateID:        dispatch.TemplateID,
+		To:                  dispatch.Message.To,
+		ReplyTo:             dispatch.Message.ReplyTo,
+		Subject:             dispatch.Message.Subject,
+		KindID:              dispatch.Kind.ID,
+		KindDescription:     dispatch.Kind.Description,
+		SourceDescription:   dispatch.Client.Description,
+		Endorsement:         EmailEndorsement,
+		EndorsementID:       EmailEndorsementID,
+		Text:                dispatch.Message.Text,
+		TemplateID:          dispatch.TemplateID,
+		Locale:              dispatch.Message.Locale,
+		Data:                dispatch.Message.Data,
+		Markdown:            dispatch.Message.Markdown,
+		ExpiresAt:           dispatch.Message.ExpiresAt,
+		DedupKey:            dispatch.Message.DedupKey,
+		DedupWindow:         dispatch.Kind.DedupWindow,
+		SkipTextAlternative: dispatch.Kind.SkipTextAlternative,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -53,15 +70,8 @@ func (strategy EmailStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 
 	users := []User{{Email: dispatch.Message.To}}
 
-	return strategy.enqueuer.Enqueue(
-		dispatch.Connection,
-		users,
-		options,
-		cf.CloudControllerSpace{},
-		cf.CloudControllerOrganization{},
-		dispatch.Client.ID,
-		dispatch.UAAHost,
-		"",
-		dispatch.VCAPRequest.ID,
-		dispatch.VCAPRequest.ReceiptTime)
+	return Resolution{
+		Users:   users,
+		Options: options,
+	}, nil
 }
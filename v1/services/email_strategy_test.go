@@ -42,10 +42,13 @@ var _ = Describe("EmailStrategy", func() {
 					},
 					TemplateID: "some-template-id",
 					Message: services.DispatchMessage{
-						ReplyTo: "reply-to@example.com",
-						Subject: "this is the subject",
-						To:      "dr@strangelove.com",
-						Text:    "email text",
+						ReplyTo:  "reply-to@example.com",
+						Subject:  "this is the subject",
+						Locale:   "de-CH",
+						Data:     map[string]interface{}{"app_name": "banana"},
+						Markdown: "# banana",
+						To:       "dr@strangelove.com",
+						Text:     "email text",
 						HTML: services.HTML{
 							BodyContent:    "some html body content",
 							BodyAttributes: "some html body attributes",
@@ -67,6 +70,9 @@ var _ = Describe("EmailStrategy", func() {
 				Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
 					ReplyTo:           "reply-to@example.com",
 					Subject:           "this is the subject",
+					Locale:            "de-CH",
+					Data:              map[string]interface{}{"app_name": "banana"},
+					Markdown:          "# banana",
 					KindDescription:   "description of a kind",
 					SourceDescription: "description of a client",
 					Text:              "email text",
@@ -77,10 +83,11 @@ var _ = Describe("EmailStrategy", func() {
 						Head:           "the html head tag",
 						Doctype:        "the html doctype",
 					},
-					KindID:      "some-kind-id",
-					To:          "dr@strangelove.com",
-					Role:        "",
-					Endorsement: services.EmailEndorsement,
+					KindID:        "some-kind-id",
+					To:            "dr@strangelove.com",
+					Role:          "",
+					Endorsement:   services.EmailEndorsement,
+					EndorsementID: services.EmailEndorsementID,
 				}))
 				Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{}))
 				Expect(enqueuer.EnqueueCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{}))
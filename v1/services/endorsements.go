@@ -0,0 +1,15 @@
+package services
+
+// The IDs of the endorsements, which the strategies attach to each message
+// along with the English text so that it can be translated when sent. The
+// translations are read from templates/endorsements.json.
+const (
+	SpaceEndorsementID            = "endorsement.space"
+	SpaceRoleEndorsementID        = "endorsement.space_role"
+	OrganizationEndorsementID     = "endorsement.organization"
+	OrganizationRoleEndorsementID = "endorsement.organization_role"
+	EveryoneEndorsementID         = "endorsement.everyone"
+	ScopeEndorsementID            = "endorsement.scope"
+	UserEndorsementID             = "endorsement.user"
+	EmailEndorsementID            = "endorsement.email"
+)
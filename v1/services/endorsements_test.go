@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/locale"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Endorsements", func() {
+	var endorsements locale.Catalog
+
+	placeholders := regexp.MustCompile(`{{\.\w+}}`)
+
+	english := map[string]string{
+		services.SpaceEndorsementID:            services.SpaceEndorsement,
+		services.SpaceRoleEndorsementID:        services.SpaceRoleEndorsement,
+		services.OrganizationEndorsementID:     services.OrganizationEndorsement,
+		services.OrganizationRoleEndorsementID: services.OrganizationRoleEndorsement,
+		services.EveryoneEndorsementID:         services.EveryoneEndorsement,
+		services.ScopeEndorsementID:            services.ScopeEndorsement,
+		services.UserEndorsementID:             services.UserEndorsement,
+		services.EmailEndorsementID:            services.EmailEndorsement,
+	}
+
+	BeforeEach(func() {
+		var err error
+		endorsements, err = locale.LoadCatalog("../../templates/endorsements.json")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("translates every endorsement", func() {
+		for id := range english {
+			Expect(endorsements).To(HaveKey(id))
+		}
+		Expect(endorsements).To(HaveLen(len(english)))
+	})
+
+	It("keeps the placeholders of the English endorsement in every translation", func() {
+		for id, translations := range endorsements {
+			for locale, translation := range translations {
+				Expect(placeholders.FindAllString(translation, -1)).To(ConsistOf(placeholders.FindAllString(english[id], -1)), locale+": "+translation)
+			}
+		}
+	})
+
+	It("translates the endorsements attached by the strategies along the fallback chain", func() {
+		Expect(locale.Fallbacks("fr-CA")).To(Equal([]string{"fr-CA", "fr"}))
+		Expect(endorsements[services.SpaceEndorsementID]).NotTo(HaveKey("fr-CA"))
+
+		Expect(endorsements.Translate(services.SpaceEndorsementID, services.SpaceEndorsement, "fr-CA")).To(Equal(`Vous avez reçu ce message car vous faites partie de l'espace "{{.Space}}" de l'organisation "{{.Organization}}".`))
+		Expect(endorsements.Translate(services.SpaceEndorsementID, services.SpaceEndorsement, "de-CH")).To(Equal(`Sie erhalten diese Nachricht, weil Sie zum Space "{{.Space}}" in der Organisation "{{.Organization}}" gehören.`))
+		Expect(endorsements.Translate(services.UserEndorsementID, services.UserEndorsement, "ja")).To(Equal(services.UserEndorsement))
+		Expect(endorsements.Translate(services.UserEndorsementID, services.UserEndorsement, "")).To(Equal(services.UserEndorsement))
+	})
+})
@@ -10,7 +10,10 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
 
-const StatusQueued = "queued"
+const (
+	StatusQueued       = "queued"
+	StatusDeduplicated = "deduplicated"
+)
 
 type Options struct {
 	ReplyTo           string
@@ -19,11 +22,21 @@ type Options struct {
 	SourceDescription string
 	Text              string
 	HTML              HTML
+	Markdown          string
 	KindID            string
 	To                string
 	Role              string
+	SpaceRole         string
 	Endorsement       string
+	EndorsementID     string
 	TemplateID        string
+	Locale            string
+	Data              map[string]interface{}
+	ExpiresAt         time.Time
+	DedupKey          string
+	DedupWindow       time.Duration
+
+	SkipTextAlternative bool
 }
 
 type Delivery struct {
@@ -44,6 +57,16 @@ type messagesRepoUpserter interface {
 	Upsert(models.ConnectionInterface, models.Message) (models.Message, error)
 }
 
+type dedupEntriesRepo interface {
+	Claim(models.ConnectionInterface, models.DedupEntry, time.Time) (models.DedupEntry, bool, error)
+	SetMessageID(models.ConnectionInterface, models.DedupEntry) error
+}
+
+type batchesRepo interface {
+	Create(models.ConnectionInterface, models.Batch) (models.Batch, error)
+	Update(models.ConnectionInterface, models.Batch) (models.Batch, error)
+}
+
 type queueInterface interface {
 	Enqueue(job *gobble.Job, transaction gobble.ConnectionInterface) (*gobble.Job, error)
 }
@@ -55,13 +78,17 @@ type gobbleInitializer interface {
 type Enqueuer struct {
 	queue             queueInterface
 	messagesRepo      messagesRepoUpserter
+	dedupEntriesRepo  dedupEntriesRepo
+	batchesRepo       batchesRepo
 	gobbleInitializer gobbleInitializer
 }
 
-func NewEnqueuer(queue queueInterface, messagesRepo messagesRepoUpserter, gobbleInitializer gobbleInitializer) Enqueuer {
+func NewEnqueuer(queue queueInterface, messagesRepo messagesRepoUpserter, dedupEntriesRepo dedupEntriesRepo, batchesRepo batchesRepo, gobbleInitializer gobbleInitializer) Enqueuer {
 	return Enqueuer{
 		queue:             queue,
 		messagesRepo:      messagesRepo,
+		dedupEntriesRepo:  dedupEntriesRepo,
+		batchesRepo:       batchesRepo,
 		gobbleInitializer: gobbleInitializer,
 	}
 }
@@ -78,6 +105,35 @@ func (enqueuer Enqueuer) Enqueue(
 	vcapRequestID string,
 	reqReceived time.Time) ([]Response, error) {
 
+	var deliveries []Delivery
+	for _, user := range users {
+		deliveries = append(deliveries, Delivery{
+			Options:         options,
+			UserGUID:        user.GUID,
+			Email:           user.Email,
+			Space:           space,
+			Organization:    organization,
+			ClientID:        clientID,
+			UAAHost:         uaaHost,
+			Scope:           scope,
+			VCAPRequestID:   vcapRequestID,
+			RequestReceived: reqReceived,
+		})
+	}
+
+	return enqueuer.EnqueueDeliveries(conn, models.Batch{ClientID: clientID, KindID: options.KindID}, deliveries)
+}
+
+// EnqueueDeliveries queues a message for each of the deliveries within a
+// single transaction. The deliveries may differ in everything but their
+// connection, which lets a batch of targets go out in one pass. The pass is
+// recorded as the given batch, which every queued message is linked to and
+// which is given an ID when it has none.
+//
+// A delivery whose kind has a dedup window is not queued when an identical
+// delivery was queued within that window; its response carries the ID of
+// the earlier message and the deduplicated status instead.
+func (enqueuer Enqueuer) EnqueueDeliveries(conn ConnectionInterface, batch models.Batch, deliveries []Delivery) ([]Response, error) {
 	var responses []Response
 
 	transaction := conn.Transaction()
@@ -87,28 +143,62 @@ func (enqueuer Enqueuer) Enqueue(
 		return []Response{}, err
 	}
 
-	for _, user := range users {
+	batch, err := enqueuer.batchesRepo.Create(transaction, batch)
+	if err != nil {
+		transaction.Rollback()
+		return []Response{}, err
+	}
+
+	for _, delivery := range deliveries {
+		recipient := delivery.Email
+		if recipient == "" {
+			recipient = delivery.UserGUID
+		}
+
+		now := time.Now()
+		dedupEntry := models.DedupEntry{
+			Recipient: recipient,
+			ClientID:  delivery.ClientID,
+			KindID:    delivery.Options.KindID,
+			DedupKey:  delivery.Options.DedupKey,
+			ExpiresAt: now.Add(delivery.Options.DedupWindow),
+		}
+
+		if delivery.Options.DedupWindow > 0 {
+			existing, claimed, err := enqueuer.dedupEntriesRepo.Claim(transaction, dedupEntry, now)
+			if err != nil {
+				transaction.Rollback()
+				return []Response{}, err
+			}
+
+			if !claimed {
+				responses = append(responses, Response{
+					Status:         StatusDeduplicated,
+					NotificationID: existing.MessageID,
+					Recipient:      recipient,
+					VCAPRequestID:  delivery.VCAPRequestID,
+					BatchID:        batch.ID,
+				})
+				continue
+			}
+		}
+
 		message, err := enqueuer.messagesRepo.Upsert(transaction, models.Message{
-			Status: StatusQueued,
+			Status:        StatusQueued,
+			BatchID:       batch.ID,
+			UserGUID:      delivery.UserGUID,
+			Email:         delivery.Email,
+			ClientID:      delivery.ClientID,
+			KindID:        delivery.Options.KindID,
+			VCAPRequestID: delivery.VCAPRequestID,
 		})
 		if err != nil {
 			transaction.Rollback()
 			return []Response{}, err
 		}
 
-		job := gobble.NewJob(Delivery{
-			Options:         options,
-			UserGUID:        user.GUID,
-			Email:           user.Email,
-			Space:           space,
-			Organization:    organization,
-			ClientID:        clientID,
-			MessageID:       message.ID,
-			UAAHost:         uaaHost,
-			Scope:           scope,
-			VCAPRequestID:   vcapRequestID,
-			RequestReceived: reqReceived,
-		})
+		delivery.MessageID = message.ID
+		job := gobble.NewJob(delivery)
 
 		_, err = enqueuer.queue.Enqueue(job, transaction)
 		if err != nil {
@@ -116,17 +206,36 @@ func (enqueuer Enqueuer) Enqueue(
 			return []Response{}, err
 		}
 
-		recipient := user.Email
-		if recipient == "" {
-			recipient = user.GUID
+		if delivery.Options.DedupWindow > 0 {
+			dedupEntry.MessageID = message.ID
+
+			err = enqueuer.dedupEntriesRepo.SetMessageID(transaction, dedupEntry)
+			if err != nil {
+				transaction.Rollback()
+				return []Response{}, err
+			}
 		}
 
 		responses = append(responses, Response{
 			Status:         message.Status,
 			NotificationID: message.ID,
 			Recipient:      recipient,
-			VCAPRequestID:  vcapRequestID,
+			VCAPRequestID:  delivery.VCAPRequestID,
+			BatchID:        batch.ID,
 		})
+		batch.TotalMessages++
+	}
+
+	batch.QueuedMessages = batch.TotalMessages
+	if batch.TotalMessages == 0 {
+		completedTime := batch.StartTime
+		batch.CompletedTime = &completedTime
+	}
+
+	_, err = enqueuer.batchesRepo.Update(transaction, batch)
+	if err != nil {
+		transaction.Rollback()
+		return []Response{}, err
 	}
 
 	if err := transaction.Commit(); err != nil {
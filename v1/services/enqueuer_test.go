@@ -24,6 +24,8 @@ var _ = Describe("Enqueuer", func() {
 		org               cf.CloudControllerOrganization
 		reqReceived       time.Time
 		messagesRepo      *mocks.MessagesRepo
+		dedupEntriesRepo  *mocks.DedupEntriesRepo
+		batchesRepo       *mocks.BatchesRepo
 	)
 
 	BeforeEach(func() {
@@ -61,7 +63,17 @@ var _ = Describe("Enqueuer", func() {
 			},
 		}
 
-		enqueuer = services.NewEnqueuer(queue, messagesRepo, gobbleInitializer)
+		dedupEntriesRepo = mocks.NewDedupEntriesRepo()
+		dedupEntriesRepo.ClaimCall.Returns.Claimed = true
+
+		batchesRepo = mocks.NewBatchesRepo()
+		batchesRepo.CreateCall.Returns.Batch = models.Batch{
+			ID:       "some-batch-id",
+			ClientID: "the-client",
+			KindID:   "the-kind",
+		}
+
+		enqueuer = services.NewEnqueuer(queue, messagesRepo, dedupEntriesRepo, batchesRepo, gobbleInitializer)
 	})
 
 	Describe("Enqueue", func() {
@@ -77,24 +89,28 @@ var _ = Describe("Enqueuer", func() {
 					Recipient:      "user-1",
 					NotificationID: "first-random-guid",
 					VCAPRequestID:  "some-request-id",
+					BatchID:        "some-batch-id",
 				},
 				{
 					Status:         "queued",
 					Recipient:      "user-2@example.com",
 					NotificationID: "second-random-guid",
 					VCAPRequestID:  "some-request-id",
+					BatchID:        "some-batch-id",
 				},
 				{
 					Status:         "queued",
 					Recipient:      "user-3",
 					NotificationID: "third-random-guid",
 					VCAPRequestID:  "some-request-id",
+					BatchID:        "some-batch-id",
 				},
 				{
 					Status:         "queued",
 					Recipient:      "user-4",
 					NotificationID: "fourth-random-guid",
 					VCAPRequestID:  "some-request-id",
+					BatchID:        "some-batch-id",
 				},
 			}))
 		})
@@ -172,19 +188,53 @@ var _ = Describe("Enqueuer", func() {
 		})
 
 		It("upserts a StatusQueued for each of the jobs", func() {
-			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}, {GUID: "user-3"}, {GUID: "user-4"}}
-			enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			users := []services.User{{GUID: "user-1"}, {Email: "user-2@example.com"}, {GUID: "user-3"}, {GUID: "user-4"}}
+			enqueuer.Enqueue(conn, users, services.Options{KindID: "the-kind"}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
 
 			messages := messagesRepo.UpsertCall.Receives.Messages
 			Expect(messages).To(HaveLen(4))
 			Expect(messages).To(Equal([]models.Message{
-				{Status: services.StatusQueued},
-				{Status: services.StatusQueued},
-				{Status: services.StatusQueued},
-				{Status: services.StatusQueued},
+				{Status: services.StatusQueued, BatchID: "some-batch-id", UserGUID: "user-1", ClientID: "the-client", KindID: "the-kind", VCAPRequestID: "some-request-id"},
+				{Status: services.StatusQueued, BatchID: "some-batch-id", Email: "user-2@example.com", ClientID: "the-client", KindID: "the-kind", VCAPRequestID: "some-request-id"},
+				{Status: services.StatusQueued, BatchID: "some-batch-id", UserGUID: "user-3", ClientID: "the-client", KindID: "the-kind", VCAPRequestID: "some-request-id"},
+				{Status: services.StatusQueued, BatchID: "some-batch-id", UserGUID: "user-4", ClientID: "the-client", KindID: "the-kind", VCAPRequestID: "some-request-id"},
+			}))
+		})
+
+		It("records the request as a batch of the queued messages", func() {
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			_, err := enqueuer.Enqueue(conn, users, services.Options{KindID: "the-kind"}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(batchesRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(batchesRepo.CreateCall.Receives.Batch).To(Equal(models.Batch{
+				ClientID: "the-client",
+				KindID:   "the-kind",
+			}))
+
+			Expect(batchesRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
+			Expect(batchesRepo.UpdateCall.Receives.Batch).To(Equal(models.Batch{
+				ID:             "some-batch-id",
+				ClientID:       "the-client",
+				KindID:         "the-kind",
+				TotalMessages:  2,
+				QueuedMessages: 2,
 			}))
 		})
 
+		It("completes the batch right away when there is no one to send to", func() {
+			startTime := time.Now().Truncate(time.Second)
+			batchesRepo.CreateCall.Returns.Batch.StartTime = startTime
+
+			_, err := enqueuer.Enqueue(conn, []services.User{}, services.Options{KindID: "the-kind"}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			batch := batchesRepo.UpdateCall.Receives.Batch
+			Expect(batch.TotalMessages).To(Equal(0))
+			Expect(batch.CompletedTime).NotTo(BeNil())
+			Expect(*batch.CompletedTime).To(Equal(startTime))
+		})
+
 		Context("using a transaction", func() {
 			var users []services.User
 
@@ -227,6 +277,24 @@ var _ = Describe("Enqueuer", func() {
 				Expect(err).To(HaveOccurred())
 			})
 
+			It("rolls back the transaction when the batch cannot be recorded", func() {
+				batchesRepo.CreateCall.Returns.Error = errors.New("BOOM!")
+				_, err := enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+				Expect(err).To(MatchError("BOOM!"))
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(messagesRepo.UpsertCall.Receives.Messages).To(BeEmpty())
+			})
+
+			It("rolls back the transaction when the batch counters cannot be recorded", func() {
+				batchesRepo.UpdateCall.Returns.Error = errors.New("BOOM!")
+				_, err := enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+				Expect(err).To(MatchError("BOOM!"))
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+
 			It("rolls back the transaction when there is an error in enqueuing", func() {
 				queue.EnqueueCall.Returns.Error = errors.New("BOOM!")
 				_, err := enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
@@ -265,4 +333,139 @@ var _ = Describe("Enqueuer", func() {
 			})
 		})
 	})
+
+	Describe("EnqueueDeliveries", func() {
+		It("enqueues each delivery as given, within a single transaction", func() {
+			responses, err := enqueuer.EnqueueDeliveries(conn, models.Batch{ClientID: "the-client", KindID: "the-kind"}, []services.Delivery{
+				{UserGUID: "user-1", Space: space, ClientID: "the-client", VCAPRequestID: "some-request-id"},
+				{Email: "user-2@example.com", Scope: "my.scope", ClientID: "the-client", VCAPRequestID: "some-request-id"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(responses).To(Equal([]services.Response{
+				{
+					Status:         "queued",
+					Recipient:      "user-1",
+					NotificationID: "first-random-guid",
+					VCAPRequestID:  "some-request-id",
+					BatchID:        "some-batch-id",
+				},
+				{
+					Status:         "queued",
+					Recipient:      "user-2@example.com",
+					NotificationID: "second-random-guid",
+					VCAPRequestID:  "some-request-id",
+					BatchID:        "some-batch-id",
+				},
+			}))
+
+			var deliveries []services.Delivery
+			for _, job := range queue.EnqueueCall.Receives.Jobs {
+				var delivery services.Delivery
+				err := job.Unmarshal(&delivery)
+				Expect(err).NotTo(HaveOccurred())
+				deliveries = append(deliveries, delivery)
+			}
+
+			Expect(deliveries).To(Equal([]services.Delivery{
+				{UserGUID: "user-1", Space: space, ClientID: "the-client", MessageID: "first-random-guid", VCAPRequestID: "some-request-id"},
+				{Email: "user-2@example.com", Scope: "my.scope", ClientID: "the-client", MessageID: "second-random-guid", VCAPRequestID: "some-request-id"},
+			}))
+
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			Expect(queue.EnqueueCall.Receives.Connection).To(Equal(transaction))
+		})
+
+		Context("when the kind has a dedup window", func() {
+			var delivery services.Delivery
+
+			BeforeEach(func() {
+				delivery = services.Delivery{
+					UserGUID:      "user-1",
+					ClientID:      "the-client",
+					VCAPRequestID: "some-request-id",
+					Options: services.Options{
+						KindID:      "the-kind",
+						DedupKey:    "disk-full",
+						DedupWindow: 5 * time.Minute,
+					},
+				}
+			})
+
+			It("records the queued message for the window", func() {
+				before := time.Now()
+
+				_, err := enqueuer.EnqueueDeliveries(conn, models.Batch{ClientID: "the-client", KindID: "the-kind"}, []services.Delivery{delivery})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(dedupEntriesRepo.ClaimCall.Receives.Connection).To(Equal(transaction))
+
+				claimed := dedupEntriesRepo.ClaimCall.Receives.Entry
+				Expect(claimed.Recipient).To(Equal("user-1"))
+				Expect(claimed.ClientID).To(Equal("the-client"))
+				Expect(claimed.KindID).To(Equal("the-kind"))
+				Expect(claimed.DedupKey).To(Equal("disk-full"))
+				Expect(claimed.MessageID).To(BeEmpty())
+				Expect(claimed.ExpiresAt).To(BeTemporally(">=", before.Add(5*time.Minute)))
+				Expect(claimed.ExpiresAt).To(BeTemporally("<=", time.Now().Add(5*time.Minute)))
+				Expect(dedupEntriesRepo.ClaimCall.Receives.Now).To(BeTemporally("~", claimed.ExpiresAt.Add(-5*time.Minute)))
+
+				Expect(dedupEntriesRepo.SetMessageIDCall.Receives.Connection).To(Equal(transaction))
+				Expect(dedupEntriesRepo.SetMessageIDCall.Receives.Entries).To(HaveLen(1))
+
+				entry := dedupEntriesRepo.SetMessageIDCall.Receives.Entries[0]
+				Expect(entry.Recipient).To(Equal("user-1"))
+				Expect(entry.MessageID).To(Equal("first-random-guid"))
+			})
+
+			It("collapses a delivery made within the window into the earlier message", func() {
+				dedupEntriesRepo.ClaimCall.Returns.Entry = models.DedupEntry{MessageID: "earlier-message-id"}
+				dedupEntriesRepo.ClaimCall.Returns.Claimed = false
+
+				responses, err := enqueuer.EnqueueDeliveries(conn, models.Batch{ClientID: "the-client", KindID: "the-kind"}, []services.Delivery{delivery})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(responses).To(Equal([]services.Response{
+					{
+						Status:         "deduplicated",
+						Recipient:      "user-1",
+						NotificationID: "earlier-message-id",
+						VCAPRequestID:  "some-request-id",
+						BatchID:        "some-batch-id",
+					},
+				}))
+				Expect(queue.EnqueueCall.Receives.Jobs).To(BeEmpty())
+				Expect(messagesRepo.UpsertCall.Receives.Messages).To(BeEmpty())
+				Expect(dedupEntriesRepo.SetMessageIDCall.WasCalled).To(BeFalse())
+				Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			})
+
+			It("rolls back the transaction when the entry cannot be claimed", func() {
+				dedupEntriesRepo.ClaimCall.Returns.Error = errors.New("database is gone")
+
+				_, err := enqueuer.EnqueueDeliveries(conn, models.Batch{ClientID: "the-client", KindID: "the-kind"}, []services.Delivery{delivery})
+				Expect(err).To(MatchError("database is gone"))
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+
+			It("rolls back the transaction when the entry cannot be recorded", func() {
+				dedupEntriesRepo.SetMessageIDCall.Returns.Error = errors.New("database is gone")
+
+				_, err := enqueuer.EnqueueDeliveries(conn, models.Batch{ClientID: "the-client", KindID: "the-kind"}, []services.Delivery{delivery})
+				Expect(err).To(MatchError("database is gone"))
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+
+			It("does not look for earlier deliveries when the kind has no window", func() {
+				delivery.Options.DedupWindow = 0
+
+				_, err := enqueuer.EnqueueDeliveries(conn, models.Batch{ClientID: "the-client", KindID: "the-kind"}, []services.Delivery{delivery})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(dedupEntriesRepo.ClaimCall.WasCalled).To(BeFalse())
+				Expect(dedupEntriesRepo.SetMessageIDCall.WasCalled).To(BeFalse())
+			})
+		})
+	})
 })
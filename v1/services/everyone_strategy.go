@@ -1,7 +1,5 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/cf"
-
 const EveryoneEndorsement = "This message was sent to everyone."
 
 type allUserGUIDsGetter interface {
@@ -27,18 +25,33 @@ func NewEveryoneStrategy(tokenLoader loadsTokens, allUsers allUserGUIDsGetter, e
 }
 
 func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
-	var responses []Response
+	resolution, err := strategy.Resolve(dispatch)
+	if err != nil {
+		return []Response{}, err
+	}
+
+	return resolution.enqueue(strategy.enqueuer, dispatch)
+}
 
+func (strategy EveryoneStrategy) Resolve(dispatch Dispatch) (Resolution, error) {
 	options := Options{
-		ReplyTo:           dispatch.Message.ReplyTo,
-		Subject:           dispatch.Message.Subject,
-		To:                dispatch.Message.To,
-		Endorsement:       EveryoneEndorsement,
-		KindID:            dispatch.Kind.ID,
-		KindDescription:   dispatch.Kind.Description,
-		SourceDescription: dispatch.Client.Description,
-		Text:              dispatch.Message.Text,
-		TemplateID:        dispatch.TemplateID,
+		ReplyTo:             dispatch.Message.ReplyTo,
+		Subject:             dispatch.Message.Subject,
+		To:                  dispatch.Message.To,
+		Endorsement:         EveryoneEndorsement,
+		EndorsementID:       EveryoneEndorsementID,
+		KindID:              dispatch.Kind.ID,
+		KindDescription:     dispatch.Kind.Description,
+		SourceDescription:   dispatch.Client.Description,
+		Text:                dispatch.Message.Text,
+		TemplateID:          dispatch.TemplateID,
+		Locale:              dispatch.Message.Locale,
+		Data:                dispatch.Message.Data,
+		Markdown:            dispatch.Message.Markdown,
+		ExpiresAt:           dispatch.Message.ExpiresAt,
+		DedupKey:            dispatch.Message.DedupKey,
+		DedupWindow:         dispatch.Kind.DedupWindow,
+		SkipTextAlternative: dispatch.Kind.SkipTextAlternative,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -49,13 +62,13 @@ func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 
 	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
 	if err != nil {
-		return responses, err
+		return Resolution{}, err
 	}
 
 	// split this up so that it only loads user guids
 	userGUIDs, err := strategy.allUsers.AllUserGUIDs(token)
 	if err != nil {
-		return responses, err
+		return Resolution{}, err
 	}
 
 	var users []User
@@ -63,15 +76,8 @@ func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		users = append(users, User{GUID: guid})
 	}
 
-	return strategy.enqueuer.Enqueue(
-		dispatch.Connection,
-		users,
-		options,
-		cf.CloudControllerSpace{},
-		cf.CloudControllerOrganization{},
-		dispatch.Client.ID,
-		dispatch.UAAHost,
-		"",
-		dispatch.VCAPRequest.ID,
-		dispatch.VCAPRequest.ReceiptTime)
+	return Resolution{
+		Users:   users,
+		Options: options,
+	}, nil
 }
@@ -61,10 +61,13 @@ var _ = Describe("Everyone Strategy", func() {
 						Description: "Welcome system",
 					},
 					Message: services.DispatchMessage{
-						ReplyTo: "reply-to@example.com",
-						Subject: "this is the subject",
-						To:      "dr@strangelove.com",
-						Text:    "Welcome to the system, now get off my lawn.",
+						ReplyTo:  "reply-to@example.com",
+						Subject:  "this is the subject",
+						Locale:   "de-CH",
+						Data:     map[string]interface{}{"app_name": "banana"},
+						Markdown: "# banana",
+						To:       "dr@strangelove.com",
+						Text:     "Welcome to the system, now get off my lawn.",
 						HTML: services.HTML{
 							BodyContent:    "<p>Welcome to the system, now get off my lawn.</p>",
 							BodyAttributes: "some-html-body-attributes",
@@ -90,6 +93,9 @@ var _ = Describe("Everyone Strategy", func() {
 				Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
 					ReplyTo:           "reply-to@example.com",
 					Subject:           "this is the subject",
+					Locale:            "de-CH",
+					Data:              map[string]interface{}{"app_name": "banana"},
+					Markdown:          "# banana",
 					To:                "dr@strangelove.com",
 					KindID:            "welcome_user",
 					KindDescription:   "Your Official Welcome",
@@ -102,7 +108,8 @@ var _ = Describe("Everyone Strategy", func() {
 						Head:           "<head></head>",
 						Doctype:        "<html>",
 					},
-					Endorsement: services.EveryoneEndorsement,
+					Endorsement:   services.EveryoneEndorsement,
+					EndorsementID: services.EveryoneEndorsementID,
 				}))
 				Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{}))
 				Expect(enqueuer.EnqueueCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{}))
@@ -12,6 +12,9 @@ type cloudController interface {
 	GetBillingManagersByOrgGuid(orgGUID, token string) ([]cf.CloudControllerUser, error)
 	GetUsersByOrgGuid(orgGUID, token string) ([]cf.CloudControllerUser, error)
 	GetUsersBySpaceGuid(spaceGUID, token string) ([]cf.CloudControllerUser, error)
+	GetDevelopersBySpaceGuid(spaceGUID, token string) ([]cf.CloudControllerUser, error)
+	GetManagersBySpaceGuid(spaceGUID, token string) ([]cf.CloudControllerUser, error)
+	GetAuditorsBySpaceGuid(spaceGUID, token string) ([]cf.CloudControllerUser, error)
 	LoadSpace(spaceGUID, token string) (cf.CloudControllerSpace, error)
 	LoadOrganization(orgGUID, token string) (cf.CloudControllerOrganization, error)
 }
@@ -28,10 +31,24 @@ func NewFindsUserIDs(cc cloudController, uaa uaaUsersGUIDsByScope) FindsUserIDs
 	}
 }
 
-func (finder FindsUserIDs) UserIDsBelongingToSpace(spaceGUID, token string) ([]string, error) {
-	var userIDs []string
+func (finder FindsUserIDs) UserIDsBelongingToSpace(spaceGUID, role, token string) ([]string, error) {
+	var (
+		userIDs []string
+		users   []cf.CloudControllerUser
+		err     error
+	)
+
+	switch role {
+	case "SpaceDeveloper":
+		users, err = finder.cc.GetDevelopersBySpaceGuid(spaceGUID, token)
+	case "SpaceManager":
+		users, err = finder.cc.GetManagersBySpaceGuid(spaceGUID, token)
+	case "SpaceAuditor":
+		users, err = finder.cc.GetAuditorsBySpaceGuid(spaceGUID, token)
+	default:
+		users, err = finder.cc.GetUsersBySpaceGuid(spaceGUID, token)
+	}
 
-	users, err := finder.cc.GetUsersBySpaceGuid(spaceGUID, token)
 	if err != nil {
 		return userIDs, err
 	}
@@ -58,7 +58,7 @@ var _ = Describe("FindsUserIDs", func() {
 		})
 
 		It("returns the user IDs for the space", func() {
-			guids, err := finder.UserIDsBelongingToSpace("space-001", "token")
+			guids, err := finder.UserIDsBelongingToSpace("space-001", "", "token")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(guids).To(Equal([]string{"user-123", "user-789"}))
 
@@ -70,10 +70,91 @@ var _ = Describe("FindsUserIDs", func() {
 			It("returns the error", func() {
 				cc.GetUsersBySpaceGuidCall.Returns.Error = errors.New("BOOM!")
 
-				_, err := finder.UserIDsBelongingToSpace("space-001", "token")
+				_, err := finder.UserIDsBelongingToSpace("space-001", "", "token")
 				Expect(err).To(MatchError(errors.New("BOOM!")))
 			})
 		})
+
+		Context("when the role is SpaceDeveloper", func() {
+			BeforeEach(func() {
+				cc.GetDevelopersBySpaceGuidCall.Returns.Users = []cf.CloudControllerUser{
+					{GUID: "user-dev"},
+					{GUID: "user-ops"},
+				}
+			})
+
+			It("returns the user IDs of the role in the space", func() {
+				guids, err := finder.UserIDsBelongingToSpace("space-001", "SpaceDeveloper", "token")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(guids).To(Equal([]string{"user-dev", "user-ops"}))
+
+				Expect(cc.GetDevelopersBySpaceGuidCall.Receives.SpaceGUID).To(Equal("space-001"))
+				Expect(cc.GetDevelopersBySpaceGuidCall.Receives.Token).To(Equal("token"))
+			})
+
+			Context("when CloudController causes an error", func() {
+				It("returns the error", func() {
+					cc.GetDevelopersBySpaceGuidCall.Returns.Error = errors.New("BOOM!")
+
+					_, err := finder.UserIDsBelongingToSpace("space-001", "SpaceDeveloper", "token")
+					Expect(err).To(MatchError(errors.New("BOOM!")))
+				})
+			})
+		})
+
+		Context("when the role is SpaceManager", func() {
+			BeforeEach(func() {
+				cc.GetManagersBySpaceGuidCall.Returns.Users = []cf.CloudControllerUser{
+					{GUID: "user-boss"},
+					{GUID: "user-lead"},
+				}
+			})
+
+			It("returns the user IDs of the role in the space", func() {
+				guids, err := finder.UserIDsBelongingToSpace("space-001", "SpaceManager", "token")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(guids).To(Equal([]string{"user-boss", "user-lead"}))
+
+				Expect(cc.GetManagersBySpaceGuidCall.Receives.SpaceGUID).To(Equal("space-001"))
+				Expect(cc.GetManagersBySpaceGuidCall.Receives.Token).To(Equal("token"))
+			})
+
+			Context("when CloudController causes an error", func() {
+				It("returns the error", func() {
+					cc.GetManagersBySpaceGuidCall.Returns.Error = errors.New("BOOM!")
+
+					_, err := finder.UserIDsBelongingToSpace("space-001", "SpaceManager", "token")
+					Expect(err).To(MatchError(errors.New("BOOM!")))
+				})
+			})
+		})
+
+		Context("when the role is SpaceAuditor", func() {
+			BeforeEach(func() {
+				cc.GetAuditorsBySpaceGuidCall.Returns.Users = []cf.CloudControllerUser{
+					{GUID: "user-abc"},
+					{GUID: "user-zzz"},
+				}
+			})
+
+			It("returns the user IDs of the role in the space", func() {
+				guids, err := finder.UserIDsBelongingToSpace("space-001", "SpaceAuditor", "token")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(guids).To(Equal([]string{"user-abc", "user-zzz"}))
+
+				Expect(cc.GetAuditorsBySpaceGuidCall.Receives.SpaceGUID).To(Equal("space-001"))
+				Expect(cc.GetAuditorsBySpaceGuidCall.Receives.Token).To(Equal("token"))
+			})
+
+			Context("when CloudController causes an error", func() {
+				It("returns the error", func() {
+					cc.GetAuditorsBySpaceGuidCall.Returns.Error = errors.New("BOOM!")
+
+					_, err := finder.UserIDsBelongingToSpace("space-001", "SpaceAuditor", "token")
+					Expect(err).To(MatchError(errors.New("BOOM!")))
+				})
+			})
+		})
 	})
 
 	Context("UserIDsBelongingToOrganization", func() {
@@ -1,13 +1,28 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/v1/models"
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
 
 type Message struct {
-	Status string
+	ID              string
+	Status          string
+	Error           string
+	TemplateID      string
+	TemplateVersion int
+	UserGUID        string
+	Email           string
+	ClientID        string
+	KindID          string
+	VCAPRequestID   string
+	CreatedAt       time.Time
 }
 
 type messagesRepoFinder interface {
 	FindByID(models.ConnectionInterface, string) (models.Message, error)
+	List(models.ConnectionInterface, models.MessagesFilter, models.Page) ([]models.Message, error)
 }
 
 type MessageFinder struct {
@@ -26,5 +41,44 @@ func (finder MessageFinder) Find(database DatabaseInterface, messageID string) (
 		return Message{}, err
 	}
 
-	return Message{Status: message.Status}, nil
+	return newMessage(message), nil
+}
+
+// List returns the messages matching the filter on the given page, along
+// with the key of the page that follows, which is nil on the last page.
+func (finder MessageFinder) List(database DatabaseInterface, filter models.MessagesFilter, page models.Page) ([]Message, []string, error) {
+	messages, err := finder.repo.List(database.Connection(), filter, page.Peek())
+	if err != nil {
+		return []Message{}, nil, err
+	}
+
+	var next []string
+	if page.More(len(messages)) {
+		messages = messages[:page.Limit]
+		last := messages[len(messages)-1]
+		next = []string{last.CreatedAt.UTC().Format(pageKeyTimeFormat), last.ID}
+	}
+
+	list := []Message{}
+	for _, message := range messages {
+		list = append(list, newMessage(message))
+	}
+
+	return list, next, nil
+}
+
+func newMessage(message models.Message) Message {
+	return Message{
+		ID:              message.ID,
+		Status:          message.Status,
+		Error:           message.Error,
+		TemplateID:      message.TemplateID,
+		TemplateVersion: message.TemplateVersion,
+		UserGUID:        message.UserGUID,
+		Email:           message.Email,
+		ClientID:        message.ClientID,
+		KindID:          message.KindID,
+		VCAPRequestID:   message.VCAPRequestID,
+		CreatedAt:       message.CreatedAt,
+	}
 }
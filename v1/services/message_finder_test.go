@@ -2,6 +2,7 @@ package services_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
@@ -31,12 +32,30 @@ var _ = Describe("MessageFinder.Find", func() {
 
 	Context("when a message exists with the given id", func() {
 		It("returns the right Message struct", func() {
-			messagesRepo.FindByIDCall.Returns.Message = models.Message{Status: common.StatusDelivered}
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{
+				Status:          common.StatusFailed,
+				Error:           "template error",
+				TemplateID:      "some-template-id",
+				TemplateVersion: 2,
+				UserGUID:        "some-user",
+				Email:           "user@example.com",
+				ClientID:        "some-client",
+				KindID:          "some-kind",
+				VCAPRequestID:   "some-request-id",
+			}
 
 			message, err := finder.Find(database, "a-message-id")
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(message.Status).To(Equal(common.StatusDelivered))
+			Expect(message.Status).To(Equal(common.StatusFailed))
+			Expect(message.Error).To(Equal("template error"))
+			Expect(message.TemplateID).To(Equal("some-template-id"))
+			Expect(message.TemplateVersion).To(Equal(2))
+			Expect(message.UserGUID).To(Equal("some-user"))
+			Expect(message.Email).To(Equal("user@example.com"))
+			Expect(message.ClientID).To(Equal("some-client"))
+			Expect(message.KindID).To(Equal("some-kind"))
+			Expect(message.VCAPRequestID).To(Equal("some-request-id"))
 
 			Expect(messagesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
 			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(Equal("a-message-id"))
@@ -52,3 +71,64 @@ var _ = Describe("MessageFinder.Find", func() {
 		})
 	})
 })
+
+var _ = Describe("MessageFinder.List", func() {
+	var (
+		finder       services.MessageFinder
+		messagesRepo *mocks.MessagesRepo
+		database     *mocks.Database
+		conn         *mocks.Connection
+		createdAt    time.Time
+	)
+
+	BeforeEach(func() {
+		messagesRepo = mocks.NewMessagesRepo()
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		createdAt = time.Date(2015, 6, 8, 14, 32, 11, 0, time.UTC)
+		messagesRepo.ListCall.Returns.Messages = []models.Message{
+			{ID: "message-1", ClientID: "some-client", Status: common.StatusDelivered, CreatedAt: createdAt},
+			{ID: "message-2", ClientID: "some-client", Status: common.StatusDelivered, CreatedAt: createdAt.Add(time.Second)},
+			{ID: "message-3", ClientID: "some-client", Status: common.StatusFailed, CreatedAt: createdAt.Add(2 * time.Second)},
+		}
+
+		finder = services.NewMessageFinder(messagesRepo)
+	})
+
+	It("returns every message matching the filter", func() {
+		filter := models.MessagesFilter{ClientID: "some-client", KindID: "some-kind"}
+
+		messages, next, err := finder.List(database, filter, models.Page{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(messages).To(HaveLen(3))
+		Expect(messages[2]).To(Equal(services.Message{
+			ID:        "message-3",
+			ClientID:  "some-client",
+			Status:    common.StatusFailed,
+			CreatedAt: createdAt.Add(2 * time.Second),
+		}))
+		Expect(next).To(BeNil())
+
+		Expect(messagesRepo.ListCall.Receives.Connection).To(Equal(conn))
+		Expect(messagesRepo.ListCall.Receives.Filter).To(Equal(filter))
+	})
+
+	It("returns the key of the next page when more messages follow", func() {
+		messages, next, err := finder.List(database, models.MessagesFilter{}, models.Page{Limit: 2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(messages).To(HaveLen(2))
+		Expect(messages[1].ID).To(Equal("message-2"))
+		Expect(next).To(Equal([]string{"2015-06-08 14:32:12", "message-2"}))
+
+		Expect(messagesRepo.ListCall.Receives.Page).To(Equal(models.Page{Limit: 3}))
+	})
+
+	It("returns errors from the repo", func() {
+		messagesRepo.ListCall.Returns.Error = errors.New("BOOM!")
+
+		_, _, err := finder.List(database, models.MessagesFilter{}, models.Page{})
+		Expect(err).To(MatchError("BOOM!"))
+	})
+})
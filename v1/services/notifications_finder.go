@@ -15,24 +15,78 @@ func NewNotificationsFinder(clientsRepo ClientsRepo, kindsRepo KindsRepo) Notifi
 	}
 }
 
-func (finder NotificationsFinder) AllClientsAndNotifications(database DatabaseInterface) ([]models.Client, []models.Kind, error) {
-	var (
-		clients       []models.Client
-		notifications []models.Kind
-		err           error
-	)
+// ListClientsAndNotifications returns the notifications matching the filter
+// on the given page, the clients they belong to, and the key of the page
+// that follows, which is nil on the last page. When the listing is not
+// filtered, each client without any notification takes up a row of the page
+// as well, so that every client is listed.
+func (finder NotificationsFinder) ListClientsAndNotifications(database DatabaseInterface, filter models.KindsFilter, page models.Page) ([]models.Client, []models.Kind, []string, error) {
+	connection := database.Connection()
 
-	clients, err = finder.clientsRepo.FindAll(database.Connection())
+	notifications, err := finder.kindsRepo.List(connection, filter, page.Peek())
 	if err != nil {
-		return clients, notifications, err
+		return nil, nil, nil, err
+	}
+
+	var clientsWithoutNotifications []models.Client
+	if filter == (models.KindsFilter{}) {
+		clientsPage := models.Page{Limit: page.Limit}
+		if len(page.After) > 0 {
+			clientsPage.After = page.After[:1]
+		}
+
+		clientsWithoutNotifications, err = finder.clientsRepo.ListWithoutKinds(connection, clientsPage.Peek())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	notifications, clientsWithoutNotifications, next := mergeClientsAndNotifications(notifications, clientsWithoutNotifications, page)
+
+	// The notifications are ordered by client, so each client's
+	// notifications are adjacent.
+	clientIDs := []string{}
+	for _, notification := range notifications {
+		if len(clientIDs) == 0 || clientIDs[len(clientIDs)-1] != notification.ClientID {
+			clientIDs = append(clientIDs, notification.ClientID)
+		}
 	}
 
-	notifications, err = finder.kindsRepo.FindAll(database.Connection())
+	clients, err := finder.clientsRepo.List(connection, models.ClientsFilter{IDs: clientIDs}, models.Page{})
 	if err != nil {
-		return clients, notifications, err
+		return nil, nil, nil, err
+	}
+
+	return append(clients, clientsWithoutNotifications...), notifications, next, nil
+}
+
+// mergeClientsAndNotifications orders the notifications and the clients
+// without notifications by client ID, a client without notifications being
+// keyed by its ID and an empty notification ID, and cuts them to the page.
+func mergeClientsAndNotifications(notifications []models.Kind, clients []models.Client, page models.Page) ([]models.Kind, []models.Client, []string) {
+	var (
+		pageNotifications []models.Kind
+		pageClients       []models.Client
+		last              []string
+	)
+
+	for len(notifications) > 0 || len(clients) > 0 {
+		if page.Limit > 0 && len(pageNotifications)+len(pageClients) == page.Limit {
+			return pageNotifications, pageClients, last
+		}
+
+		if len(clients) == 0 || (len(notifications) > 0 && notifications[0].ClientID < clients[0].ID) {
+			pageNotifications = append(pageNotifications, notifications[0])
+			last = []string{notifications[0].ClientID, notifications[0].ID}
+			notifications = notifications[1:]
+		} else {
+			pageClients = append(pageClients, clients[0])
+			last = []string{clients[0].ID, ""}
+			clients = clients[1:]
+		}
 	}
 
-	return clients, notifications, nil
+	return pageNotifications, pageClients, nil
 }
 
 func (finder NotificationsFinder) ClientAndKind(database DatabaseInterface, clientID, kindID string) (models.Client, models.Kind, error) {
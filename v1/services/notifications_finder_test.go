@@ -2,7 +2,6 @@ package services_test
 
 import (
 	"errors"
-	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -100,73 +99,105 @@ var _ = Describe("NotificationsFinder", func() {
 		})
 	})
 
-	Describe("AllClientsAndNotifications", func() {
-		var (
-			starWars        models.Client
-			bigHero6        models.Client
-			imitationGame   models.Client
-			multiSaber      models.Kind
-			milleniumFalcon models.Kind
-			robots          models.Kind
-		)
-
+	Describe("ListClientsAndNotifications", func() {
 		BeforeEach(func() {
-			starWars = models.Client{
-				ID:          "star-wars",
-				Description: "The Force Awakens",
-				CreatedAt:   time.Now(),
-			}
-			bigHero6 = models.Client{
-				ID:          "big-hero-6",
-				Description: "Marvel",
-				CreatedAt:   time.Now(),
-			}
-			imitationGame = models.Client{
-				ID:          "the-imitation-game",
-				Description: "Alan Turing",
-				CreatedAt:   time.Now(),
+			clientsRepo.ListCall.Returns.Clients = []models.Client{{ID: "client-a"}, {ID: "client-b"}}
+			kindsRepo.ListCall.Returns.Kinds = []models.Kind{
+				{ID: "kind-a", ClientID: "client-a"},
+				{ID: "kind-b", ClientID: "client-a"},
+				{ID: "kind-a", ClientID: "client-b"},
 			}
+		})
 
-			clientsRepo.FindAllCall.Returns.Clients = []models.Client{imitationGame, bigHero6, starWars}
+		It("lists the clients without notifications when the listing is not filtered", func() {
+			clientsRepo.ListWithoutKindsCall.Returns.Clients = []models.Client{{ID: "client-0"}, {ID: "client-c"}}
 
-			multiSaber = models.Kind{
-				ID:          "multi-light-saber",
-				ClientID:    "star-wars",
-				Description: "LOL WUT?",
-				Critical:    false,
-				CreatedAt:   time.Now(),
-			}
-			milleniumFalcon = models.Kind{
-				ID:          "millenium-falcon",
-				ClientID:    "star-wars",
-				Description: "Awesome!",
-				Critical:    true,
-				CreatedAt:   time.Now(),
-			}
+			clients, notifications, next, err := finder.ListClientsAndNotifications(database, models.KindsFilter{}, models.Page{Limit: 50})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients).To(Equal([]models.Client{{ID: "client-a"}, {ID: "client-b"}, {ID: "client-0"}, {ID: "client-c"}}))
+			Expect(notifications).To(HaveLen(3))
+			Expect(next).To(BeNil())
 
-			robots = models.Kind{
-				ID:          "robots",
-				ClientID:    "big-hero-6",
-				Description: "hero",
-				Critical:    true,
-				CreatedAt:   time.Now(),
-			}
+			Expect(kindsRepo.ListCall.Receives.Page).To(Equal(models.Page{Limit: 51}))
+			Expect(clientsRepo.ListWithoutKindsCall.Receives.Page).To(Equal(models.Page{Limit: 51}))
+			Expect(clientsRepo.ListCall.Receives.Filter).To(Equal(models.ClientsFilter{IDs: []string{"client-a", "client-b"}}))
+		})
+
+		It("counts each client without notifications as a row of the page", func() {
+			clientsRepo.ListCall.Returns.Clients = []models.Client{{ID: "client-a"}}
+			clientsRepo.ListWithoutKindsCall.Returns.Clients = []models.Client{{ID: "client-0"}, {ID: "client-c"}}
+
+			clients, notifications, next, err := finder.ListClientsAndNotifications(database, models.KindsFilter{}, models.Page{Limit: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients).To(Equal([]models.Client{{ID: "client-a"}, {ID: "client-0"}}))
+			Expect(notifications).To(Equal([]models.Kind{{ID: "kind-a", ClientID: "client-a"}}))
+			Expect(next).To(Equal([]string{"client-a", "kind-a"}))
 
-			kindsRepo.FindAllCall.Returns.Kinds = []models.Kind{multiSaber, milleniumFalcon, robots}
+			Expect(clientsRepo.ListCall.Receives.Filter).To(Equal(models.ClientsFilter{IDs: []string{"client-a"}}))
 		})
 
-		It("returns all clients with their associated notifications", func() {
-			clients, notifications, err := finder.AllClientsAndNotifications(database)
+		It("resumes the clients without notifications after the client of the cursor", func() {
+			clientsRepo.ListWithoutKindsCall.Returns.Clients = []models.Client{{ID: "client-c"}}
+
+			clients, _, next, err := finder.ListClientsAndNotifications(database, models.KindsFilter{}, models.Page{After: []string{"client-0", ""}, Limit: 3})
 			Expect(err).NotTo(HaveOccurred())
-			Expect(clients).To(HaveLen(3))
-			Expect(clients).To(ContainElement(starWars))
-			Expect(clients).To(ContainElement(bigHero6))
-			Expect(clients).To(ContainElement(imitationGame))
+			Expect(clients).To(Equal([]models.Client{{ID: "client-a"}, {ID: "client-b"}}))
+			Expect(next).To(Equal([]string{"client-b", "kind-a"}))
 
-			Expect(notifications).To(HaveLen(3))
-			Expect(notifications).To(ContainElement(multiSaber))
-			Expect(notifications).To(ContainElement(milleniumFalcon))
-			Expect(notifications).To(ContainElement(robots))
+			Expect(kindsRepo.ListCall.Receives.Page).To(Equal(models.Page{After: []string{"client-0", ""}, Limit: 4}))
+			Expect(clientsRepo.ListWithoutKindsCall.Receives.Page).To(Equal(models.Page{After: []string{"client-0"}, Limit: 4}))
+		})
+
+		It("ends the page on a client without notifications", func() {
+			kindsRepo.ListCall.Returns.Kinds = []models.Kind{{ID: "kind-a", ClientID: "client-b"}}
+			clientsRepo.ListCall.Returns.Clients = []models.Client{}
+			clientsRepo.ListWithoutKindsCall.Returns.Clients = []models.Client{{ID: "client-a"}}
+
+			clients, notifications, next, err := finder.ListClientsAndNotifications(database, models.KindsFilter{}, models.Page{Limit: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clients).To(Equal([]models.Client{{ID: "client-a"}}))
+			Expect(notifications).To(BeEmpty())
+			Expect(next).To(Equal([]string{"client-a", ""}))
+		})
+
+		It("returns a page of notifications along with their clients", func() {
+			critical := true
+			clients, notifications, next, err := finder.ListClientsAndNotifications(database, models.KindsFilter{Critical: &critical}, models.Page{Limit: 2})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(notifications).To(Equal([]models.Kind{
+				{ID: "kind-a", ClientID: "client-a"},
+				{ID: "kind-b", ClientID: "client-a"},
+			}))
+			Expect(next).To(Equal([]string{"client-a", "kind-b"}))
+			Expect(clients).To(Equal([]models.Client{{ID: "client-a"}, {ID: "client-b"}}))
+
+			Expect(kindsRepo.ListCall.Receives.Filter).To(Equal(models.KindsFilter{Critical: &critical}))
+			Expect(kindsRepo.ListCall.Receives.Page).To(Equal(models.Page{Limit: 3}))
+			Expect(clientsRepo.ListCall.Receives.Filter).To(Equal(models.ClientsFilter{IDs: []string{"client-a"}}))
+			Expect(clientsRepo.ListWithoutKindsCall.WasCalled).To(BeFalse())
+		})
+
+		It("returns only the clients of the filtered notifications", func() {
+			_, _, next, err := finder.ListClientsAndNotifications(database, models.KindsFilter{ClientID: "client-a"}, models.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(BeNil())
+
+			Expect(kindsRepo.ListCall.Receives.Page).To(Equal(models.Page{}))
+			Expect(clientsRepo.ListCall.Receives.Filter).To(Equal(models.ClientsFilter{IDs: []string{"client-a", "client-b"}}))
+		})
+
+		It("propagates errors from the repos", func() {
+			kindsRepo.ListCall.Returns.Error = errors.New("BOOM!")
+
+			_, _, _, err := finder.ListClientsAndNotifications(database, models.KindsFilter{}, models.Page{Limit: 2})
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+
+			kindsRepo.ListCall.Returns.Error = nil
+			clientsRepo.ListWithoutKindsCall.Returns.Error = errors.New("BANG!")
+
+			_, _, _, err = finder.ListClientsAndNotifications(database, models.KindsFilter{}, models.Page{Limit: 2})
+			Expect(err).To(MatchError(errors.New("BANG!")))
 		})
 	})
 })
@@ -32,18 +32,34 @@ func NewOrganizationStrategy(tokenLoader loadsTokens, organizationLoader loadsOr
 }
 
 func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
-	responses := []Response{}
+	resolution, err := strategy.Resolve(dispatch)
+	if err != nil {
+		return []Response{}, err
+	}
+
+	return resolution.enqueue(strategy.enqueuer, dispatch)
+}
+
+func (strategy OrganizationStrategy) Resolve(dispatch Dispatch) (Resolution, error) {
 	options := Options{
-		To:                dispatch.Message.To,
-		ReplyTo:           dispatch.Message.ReplyTo,
-		Subject:           dispatch.Message.Subject,
-		KindID:            dispatch.Kind.ID,
-		KindDescription:   dispatch.Kind.Description,
-		SourceDescription: dispatch.Client.Description,
-		Endorsement:       OrganizationEndorsement,
-		Text:              dispatch.Message.Text,
-		TemplateID:        dispatch.TemplateID,
-		Role:              dispatch.Role,
+		To:                  dispatch.Message.To,
+		ReplyTo:             dispatch.Message.ReplyTo,
+		Subject:             dispatch.Message.Subject,
+		KindID:              dispatch.Kind.ID,
+		KindDescription:     dispatch.Kind.Description,
+		SourceDescription:   dispatch.Client.Description,
+		Endorsement:         OrganizationEndorsement,
+		EndorsementID:       OrganizationEndorsementID,
+		Text:                dispatch.Message.Text,
+		TemplateID:          dispatch.TemplateID,
+		Locale:              dispatch.Message.Locale,
+		Data:                dispatch.Message.Data,
+		Markdown:            dispatch.Message.Markdown,
+		Role:                dispatch.Role,
+		ExpiresAt:           dispatch.Message.ExpiresAt,
+		DedupKey:            dispatch.Message.DedupKey,
+		DedupWindow:         dispatch.Kind.DedupWindow,
+		SkipTextAlternative: dispatch.Kind.SkipTextAlternative,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -54,21 +70,22 @@ func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, er
 
 	if dispatch.Role != "" {
 		options.Endorsement = OrganizationRoleEndorsement
+		options.EndorsementID = OrganizationRoleEndorsementID
 	}
 
 	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
 	if err != nil {
-		return responses, err
+		return Resolution{}, err
 	}
 
 	organization, err := strategy.organizationLoader.Load(dispatch.GUID, token)
 	if err != nil {
-		return responses, err
+		return Resolution{}, err
 	}
 
 	userGUIDs, err := strategy.findsUserIDs.UserIDsBelongingToOrganization(dispatch.GUID, options.Role, token)
 	if err != nil {
-		return responses, err
+		return Resolution{}, err
 	}
 
 	var users []User
@@ -76,15 +93,9 @@ func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, er
 		users = append(users, User{GUID: guid})
 	}
 
-	return strategy.enqueuer.Enqueue(
-		dispatch.Connection,
-		users,
-		options,
-		cf.CloudControllerSpace{},
-		organization,
-		dispatch.Client.ID,
-		dispatch.UAAHost,
-		"",
-		dispatch.VCAPRequest.ID,
-		dispatch.VCAPRequest.ReceiptTime)
+	return Resolution{
+		Users:        users,
+		Options:      options,
+		Organization: organization,
+	}, nil
 }
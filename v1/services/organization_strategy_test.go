@@ -63,10 +63,13 @@ var _ = Describe("Organization Strategy", func() {
 						GUID:       "org-001",
 						Connection: conn,
 						Message: services.DispatchMessage{
-							To:      "dr@strangelove.com",
-							ReplyTo: "reply-to@example.com",
-							Subject: "this is the subject",
-							Text:    "Please reset your password by clicking on this link...",
+							To:       "dr@strangelove.com",
+							ReplyTo:  "reply-to@example.com",
+							Subject:  "this is the subject",
+							Locale:   "de-CH",
+							Data:     map[string]interface{}{"app_name": "banana"},
+							Markdown: "# banana",
+							Text:     "Please reset your password by clicking on this link...",
 							HTML: services.HTML{
 								BodyContent:    "<p>Welcome to the system, now get off my lawn.</p>",
 								BodyAttributes: "some-html-body-attributes",
@@ -104,6 +107,9 @@ var _ = Describe("Organization Strategy", func() {
 					Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
 						ReplyTo:           "reply-to@example.com",
 						Subject:           "this is the subject",
+						Locale:            "de-CH",
+						Data:              map[string]interface{}{"app_name": "banana"},
+						Markdown:          "# banana",
 						To:                "dr@strangelove.com",
 						KindID:            "forgot_password",
 						KindDescription:   "Password reminder",
@@ -116,7 +122,8 @@ var _ = Describe("Organization Strategy", func() {
 							Head:           "<head></head>",
 							Doctype:        "<html>",
 						},
-						Endorsement: services.OrganizationEndorsement,
+						Endorsement:   services.OrganizationEndorsement,
+						EndorsementID: services.OrganizationEndorsementID,
 					}))
 					Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{}))
 					Expect(enqueuer.EnqueueCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{
@@ -143,10 +150,13 @@ var _ = Describe("Organization Strategy", func() {
 							Role:       "OrgManager",
 							Connection: conn,
 							Message: services.DispatchMessage{
-								To:      "dr@strangelove.com",
-								ReplyTo: "reply-to@example.com",
-								Subject: "this is the subject",
-								Text:    "Please reset your password by clicking on this link...",
+								To:       "dr@strangelove.com",
+								ReplyTo:  "reply-to@example.com",
+								Subject:  "this is the subject",
+								Locale:   "de-CH",
+								Data:     map[string]interface{}{"app_name": "banana"},
+								Markdown: "# banana",
+								Text:     "Please reset your password by clicking on this link...",
 								HTML: services.HTML{
 									BodyContent:    "<p>Welcome to the system, now get off my lawn.</p>",
 									BodyAttributes: "some-html-body-attributes",
@@ -172,6 +182,9 @@ var _ = Describe("Organization Strategy", func() {
 						Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
 							ReplyTo:           "reply-to@example.com",
 							Subject:           "this is the subject",
+							Locale:            "de-CH",
+							Data:              map[string]interface{}{"app_name": "banana"},
+							Markdown:          "# banana",
 							To:                "dr@strangelove.com",
 							KindID:            "forgot_password",
 							KindDescription:   "Password reminder",
@@ -184,7 +197,8 @@ var _ = Describe("Organization Strategy", func() {
 								Head:           "<head></head>",
 								Doctype:        "<html>",
 							},
-							Endorsement: services.OrganizationRoleEndorsement,
+							Endorsement:   services.OrganizationRoleEndorsement,
+							EndorsementID: services.OrganizationRoleEndorsementID,
 						}))
 
 						Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.OrgGUID).To(Equal("org-001"))
@@ -9,14 +9,18 @@ import (
 type PreferenceUpdater struct {
 	globalUnsubscribesRepo GlobalUnsubscribesRepo
 	unsubscribesRepo       UnsubscribesRepo
+	digestPreferencesRepo  DigestPreferencesRepo
 	kindsRepo              KindsRepo
+	userSettingsRepo       UserSettingsRepo
 }
 
-func NewPreferenceUpdater(globalUnsubscribesRepo GlobalUnsubscribesRepo, unsubscribesRepo UnsubscribesRepo, kindsRepo KindsRepo) PreferenceUpdater {
+func NewPreferenceUpdater(globalUnsubscribesRepo GlobalUnsubscribesRepo, unsubscribesRepo UnsubscribesRepo, digestPreferencesRepo DigestPreferencesRepo, kindsRepo KindsRepo, userSettingsRepo UserSettingsRepo) PreferenceUpdater {
 	return PreferenceUpdater{
 		globalUnsubscribesRepo: globalUnsubscribesRepo,
 		unsubscribesRepo:       unsubscribesRepo,
+		digestPreferencesRepo:  digestPreferencesRepo,
 		kindsRepo:              kindsRepo,
+		userSettingsRepo:       userSettingsRepo,
 	}
 }
 
@@ -40,6 +44,60 @@ func (updater PreferenceUpdater) Update(conn ConnectionInterface, preferences []
 		if err != nil {
 			return err
 		}
+
+		if preference.Digest != nil {
+			err = updater.digestPreferencesRepo.Set(conn, userID, preference.ClientID, preference.KindID, *preference.Digest)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
+
+func (updater PreferenceUpdater) SetLocale(conn ConnectionInterface, userID, locale string) error {
+	return updater.updateSettings(conn, userID, func(settings *models.UserSettings) {
+		settings.Locale = locale
+	})
+}
+
+// SetDigest sets the digest the user receives their non-critical
+// notifications in, unless they chose another one for a kind.
+func (updater PreferenceUpdater) SetDigest(conn ConnectionInterface, userID, digest string) error {
+	return updater.updateSettings(conn, userID, func(settings *models.UserSettings) {
+		settings.Digest = digest
+	})
+}
+
+// SetTimezone sets the timezone the user's quiet hours and digests are read in.
+func (updater PreferenceUpdater) SetTimezone(conn ConnectionInterface, userID, timezone string) error {
+	return updater.updateSettings(conn, userID, func(settings *models.UserSettings) {
+		settings.Timezone = timezone
+	})
+}
+
+// SetQuietHours sets the time of day during which the user's non-critical
+// notifications are held back.
+func (updater PreferenceUpdater) SetQuietHours(conn ConnectionInterface, userID, start, end string) error {
+	return updater.updateSettings(conn, userID, func(settings *models.UserSettings) {
+		settings.QuietHoursStart = start
+		settings.QuietHoursEnd = end
+	})
+}
+
+// updateSettings changes one of the user's settings, keeping the others.
+func (updater PreferenceUpdater) updateSettings(conn ConnectionInterface, userID string, change func(*models.UserSettings)) error {
+	settings, err := updater.userSettingsRepo.Find(conn, userID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); !ok {
+			return err
+		}
+	}
+
+	settings.UserID = userID
+	change(&settings)
+
+	_, err = updater.userSettingsRepo.Upsert(conn, settings)
+
+	return err
+}
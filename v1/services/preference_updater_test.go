@@ -15,6 +15,7 @@ var _ = Describe("PreferenceUpdater", func() {
 	Describe("Update", func() {
 		var (
 			unsubscribesRepo           *mocks.UnsubscribesRepo
+			digestPreferencesRepo      *mocks.DigestPreferencesRepo
 			kindsRepo                  *mocks.KindsRepo
 			fakeGlobalUnsubscribesRepo *mocks.GlobalUnsubscribesRepo
 			conn                       *mocks.Connection
@@ -24,9 +25,10 @@ var _ = Describe("PreferenceUpdater", func() {
 		BeforeEach(func() {
 			conn = mocks.NewConnection()
 			unsubscribesRepo = mocks.NewUnsubscribesRepo()
+			digestPreferencesRepo = mocks.NewDigestPreferencesRepo()
 			kindsRepo = mocks.NewKindsRepo()
 			fakeGlobalUnsubscribesRepo = mocks.NewGlobalUnsubscribesRepo()
-			updater = services.NewPreferenceUpdater(fakeGlobalUnsubscribesRepo, unsubscribesRepo, kindsRepo)
+			updater = services.NewPreferenceUpdater(fakeGlobalUnsubscribesRepo, unsubscribesRepo, digestPreferencesRepo, kindsRepo, mocks.NewUserSettingsRepo())
 		})
 
 		Context("when globally unsubscribing", func() {
@@ -110,6 +112,53 @@ var _ = Describe("PreferenceUpdater", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(unsubscribed).To(BeFalse())
 			})
+
+			It("sets the digest of the kinds that name one", func() {
+				digest := models.DigestDaily
+				err := updater.Update(conn, []models.Preference{
+					{
+						ClientID: "raptors",
+						KindID:   "door-open",
+						Email:    true,
+						Digest:   &digest,
+					},
+				}, false, "the-user")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(digestPreferencesRepo.SetCall.Receives.Connection).To(Equal(conn))
+				Expect(digestPreferencesRepo.SetCall.Receives.UserID).To(Equal("the-user"))
+				Expect(digestPreferencesRepo.SetCall.Receives.ClientID).To(Equal("raptors"))
+				Expect(digestPreferencesRepo.SetCall.Receives.KindID).To(Equal("door-open"))
+				Expect(digestPreferencesRepo.SetCall.Receives.Digest).To(Equal("daily"))
+			})
+
+			It("leaves the digest of the kinds that do not name one", func() {
+				err := updater.Update(conn, []models.Preference{
+					{
+						ClientID: "raptors",
+						KindID:   "door-open",
+						Email:    true,
+					},
+				}, false, "the-user")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(digestPreferencesRepo.SetCall.WasCalled).To(BeFalse())
+			})
+
+			It("returns errors from the digest preferences repo", func() {
+				digestPreferencesRepo.SetCall.Returns.Error = errors.New("BOOM!")
+
+				digest := models.DigestDaily
+				err := updater.Update(conn, []models.Preference{
+					{
+						ClientID: "raptors",
+						KindID:   "door-open",
+						Email:    true,
+						Digest:   &digest,
+					},
+				}, false, "the-user")
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
 		})
 
 		Context("when unsubscribing from missing client", func() {
@@ -205,4 +254,151 @@ var _ = Describe("PreferenceUpdater", func() {
 			})
 		})
 	})
+
+	Describe("SetLocale", func() {
+		It("stores the locale in the user's settings", func() {
+			conn := mocks.NewConnection()
+			settingsRepo := mocks.NewUserSettingsRepo()
+			updater := services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), mocks.NewUnsubscribesRepo(), mocks.NewDigestPreferencesRepo(), mocks.NewKindsRepo(), settingsRepo)
+
+			err := updater.SetLocale(conn, "user-guid", "de-CH")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(settingsRepo.UpsertCall.Receives.Connection).To(Equal(conn))
+			Expect(settingsRepo.UpsertCall.Receives.Settings).To(Equal(models.UserSettings{
+				UserID: "user-guid",
+				Locale: "de-CH",
+			}))
+		})
+
+		It("returns errors from the settings repo", func() {
+			settingsRepo := mocks.NewUserSettingsRepo()
+			settingsRepo.UpsertCall.Returns.Error = errors.New("BOOM!")
+			updater := services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), mocks.NewUnsubscribesRepo(), mocks.NewDigestPreferencesRepo(), mocks.NewKindsRepo(), settingsRepo)
+
+			err := updater.SetLocale(mocks.NewConnection(), "user-guid", "de-CH")
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+
+	Describe("SetDigest", func() {
+		It("stores the digest in the user's settings, keeping the others", func() {
+			conn := mocks.NewConnection()
+			settingsRepo := mocks.NewUserSettingsRepo()
+			settingsRepo.FindCall.Returns.Settings = models.UserSettings{
+				Primary: 42,
+				UserID:  "user-guid",
+				Locale:  "de-CH",
+			}
+			updater := services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), mocks.NewUnsubscribesRepo(), mocks.NewDigestPreferencesRepo(), mocks.NewKindsRepo(), settingsRepo)
+
+			err := updater.SetDigest(conn, "user-guid", models.DigestHourly)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(settingsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(settingsRepo.FindCall.Receives.UserID).To(Equal("user-guid"))
+			Expect(settingsRepo.UpsertCall.Receives.Settings).To(Equal(models.UserSettings{
+				Primary: 42,
+				UserID:  "user-guid",
+				Locale:  "de-CH",
+				Digest:  "hourly",
+			}))
+		})
+
+		It("creates the settings of users who have none", func() {
+			settingsRepo := mocks.NewUserSettingsRepo()
+			settingsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+			updater := services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), mocks.NewUnsubscribesRepo(), mocks.NewDigestPreferencesRepo(), mocks.NewKindsRepo(), settingsRepo)
+
+			err := updater.SetDigest(mocks.NewConnection(), "user-guid", models.DigestDaily)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(settingsRepo.UpsertCall.Receives.Settings).To(Equal(models.UserSettings{
+				UserID: "user-guid",
+				Digest: "daily",
+			}))
+		})
+
+		It("returns errors from the settings repo", func() {
+			settingsRepo := mocks.NewUserSettingsRepo()
+			settingsRepo.FindCall.Returns.Error = errors.New("BOOM!")
+			updater := services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), mocks.NewUnsubscribesRepo(), mocks.NewDigestPreferencesRepo(), mocks.NewKindsRepo(), settingsRepo)
+
+			err := updater.SetDigest(mocks.NewConnection(), "user-guid", models.DigestDaily)
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+			Expect(settingsRepo.UpsertCall.Receives.Settings).To(Equal(models.UserSettings{}))
+		})
+	})
+
+	Describe("SetTimezone", func() {
+		It("stores the timezone in the user's settings, keeping the others", func() {
+			conn := mocks.NewConnection()
+			settingsRepo := mocks.NewUserSettingsRepo()
+			settingsRepo.FindCall.Returns.Settings = models.UserSettings{
+				Primary: 42,
+				UserID:  "user-guid",
+				Locale:  "de-CH",
+			}
+			updater := services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), mocks.NewUnsubscribesRepo(), mocks.NewDigestPreferencesRepo(), mocks.NewKindsRepo(), settingsRepo)
+
+			err := updater.SetTimezone(conn, "user-guid", "America/New_York")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(settingsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(settingsRepo.UpsertCall.Receives.Settings).To(Equal(models.UserSettings{
+				Primary:  42,
+				UserID:   "user-guid",
+				Locale:   "de-CH",
+				Timezone: "America/New_York",
+			}))
+		})
+
+		It("returns errors from the settings repo", func() {
+			settingsRepo := mocks.NewUserSettingsRepo()
+			settingsRepo.FindCall.Returns.Error = errors.New("BOOM!")
+			updater := services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), mocks.NewUnsubscribesRepo(), mocks.NewDigestPreferencesRepo(), mocks.NewKindsRepo(), settingsRepo)
+
+			err := updater.SetTimezone(mocks.NewConnection(), "user-guid", "America/New_York")
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+
+	Describe("SetQuietHours", func() {
+		It("stores the quiet hours in the user's settings, keeping the others", func() {
+			conn := mocks.NewConnection()
+			settingsRepo := mocks.NewUserSettingsRepo()
+			settingsRepo.FindCall.Returns.Settings = models.UserSettings{
+				Primary:  42,
+				UserID:   "user-guid",
+				Timezone: "America/New_York",
+			}
+			updater := services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), mocks.NewUnsubscribesRepo(), mocks.NewDigestPreferencesRepo(), mocks.NewKindsRepo(), settingsRepo)
+
+			err := updater.SetQuietHours(conn, "user-guid", "22:00", "07:00")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(settingsRepo.UpsertCall.Receives.Settings).To(Equal(models.UserSettings{
+				Primary:         42,
+				UserID:          "user-guid",
+				Timezone:        "America/New_York",
+				QuietHoursStart: "22:00",
+				QuietHoursEnd:   "07:00",
+			}))
+		})
+
+		It("creates the settings of users who have none", func() {
+			settingsRepo := mocks.NewUserSettingsRepo()
+			settingsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+			updater := services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), mocks.NewUnsubscribesRepo(), mocks.NewDigestPreferencesRepo(), mocks.NewKindsRepo(), settingsRepo)
+
+			err := updater.SetQuietHours(mocks.NewConnection(), "user-guid", "22:00", "07:00")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(settingsRepo.UpsertCall.Receives.Settings).To(Equal(models.UserSettings{
+				UserID:          "user-guid",
+				QuietHoursStart: "22:00",
+				QuietHoursEnd:   "07:00",
+			}))
+		})
+	})
 })
@@ -2,22 +2,35 @@ package services
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
 
 type Kind struct {
-	Email             *bool  `json:"email"`
-	KindDescription   string `json:"kind_description"`
-	SourceDescription string `json:"source_description"`
+	Email             *bool   `json:"email"`
+	Digest            *string `json:"digest,omitempty"`
+	KindDescription   string  `json:"kind_description"`
+	SourceDescription string  `json:"source_description"`
 }
 
 type ClientMap map[string]Kind
 type ClientsMap map[string]ClientMap
 
 type PreferencesBuilder struct {
-	GlobalUnsubscribe bool       `json:"global_unsubscribe"`
-	Clients           ClientsMap `json:"clients"`
+	GlobalUnsubscribe bool        `json:"global_unsubscribe"`
+	Locale            *string     `json:"locale,omitempty"`
+	Digest            *string     `json:"digest,omitempty"`
+	Timezone          *string     `json:"timezone,omitempty"`
+	QuietHours        *QuietHours `json:"quiet_hours,omitempty"`
+	Clients           ClientsMap  `json:"clients"`
+}
+
+// QuietHours is the time of day, in the user's timezone, during which
+// non-critical notifications are held back. Both times are given as "22:00".
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
 }
 
 func NewPreferencesBuilder() PreferencesBuilder {
@@ -37,6 +50,7 @@ func (pref PreferencesBuilder) Add(preference models.Preference) {
 
 	data := Kind{
 		Email:             &preference.Email,
+		Digest:            preference.Digest,
 		KindDescription:   preference.KindDescription,
 		SourceDescription: preference.SourceDescription,
 	}
@@ -63,13 +77,76 @@ func (pref PreferencesBuilder) ToPreferences() ([]models.Preference, error) {
 				return preferences, errors.New("Missing the email field")
 			}
 
+			if kind.Digest != nil {
+				err := ValidateDigest(*kind.Digest)
+				if err != nil {
+					return preferences, err
+				}
+			}
+
 			preferences = append(preferences, models.Preference{
 				ClientID: clientID,
 				KindID:   kindID,
 				Email:    *kind.Email,
+				Digest:   kind.Digest,
 			})
 		}
 	}
 
 	return preferences, nil
 }
+
+// ValidateDigest checks a digest given in a preferences update. An empty
+// digest clears the user's choice.
+func ValidateDigest(digest string) error {
+	if digest == "" {
+		return nil
+	}
+
+	for _, frequency := range models.DigestFrequencies {
+		if digest == frequency {
+			return nil
+		}
+	}
+
+	return errors.New(`"digest" must be one of "immediate", "hourly" or "daily"`)
+}
+
+// ValidateTimezone checks a timezone given in a preferences update. An empty
+// timezone clears the user's choice.
+func ValidateTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+
+	_, err := time.LoadLocation(timezone)
+	if err != nil {
+		return errors.New(`"timezone" must be a known time zone such as "America/New_York"`)
+	}
+
+	return nil
+}
+
+// ValidateQuietHours checks quiet hours given in a preferences update. Quiet
+// hours without a start and an end clear the user's choice.
+func ValidateQuietHours(quietHours QuietHours) error {
+	if quietHours.Start == "" && quietHours.End == "" {
+		return nil
+	}
+
+	start, err := time.Parse(models.QuietHoursLayout, quietHours.Start)
+	if err != nil {
+		return errors.New(`"quiet_hours" must have a "start" and an "end" such as "22:00"`)
+	}
+
+	end, err := time.Parse(models.QuietHoursLayout, quietHours.End)
+	if err != nil {
+		return errors.New(`"quiet_hours" must have a "start" and an "end" such as "22:00"`)
+	}
+
+	if start.Equal(end) {
+		return errors.New(`"quiet_hours" must not start and end at the same time`)
+	}
+
+	return nil
+}
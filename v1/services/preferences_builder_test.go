@@ -1,6 +1,8 @@
 package services_test
 
 import (
+	"errors"
+
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 
@@ -175,6 +177,22 @@ var _ = Describe("NotificationsPreferences", func() {
 			}))
 		})
 
+		It("keeps the digest of the kinds that name one", func() {
+			digest := models.DigestHourly
+			builder.Add(models.Preference{
+				ClientID: "raptors",
+				KindID:   "door-open",
+				Email:    true,
+				Digest:   &digest,
+			})
+
+			preferences, err := builder.ToPreferences()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(preferences).To(HaveLen(1))
+			Expect(preferences[0].Digest).NotTo(BeNil())
+			Expect(*preferences[0].Digest).To(Equal("hourly"))
+		})
+
 		Context("invalid preferences", func() {
 			var badBuilder services.PreferencesBuilder
 
@@ -198,6 +216,18 @@ var _ = Describe("NotificationsPreferences", func() {
 
 			})
 
+			It("returns an error when a digest is not known", func() {
+				digest := "weekly"
+				badBuilder.Add(models.Preference{
+					ClientID: "TRex",
+					KindID:   "glass-of-water",
+					Digest:   &digest,
+				})
+
+				_, err := badBuilder.ToPreferences()
+				Expect(err).To(MatchError(errors.New(`"digest" must be one of "immediate", "hourly" or "daily"`)))
+			})
+
 			It("returns an error when the email data map is empty", func() {
 				badBuilder.Add(models.Preference{
 					ClientID: "TRex",
@@ -215,4 +245,36 @@ var _ = Describe("NotificationsPreferences", func() {
 			})
 		})
 	})
+
+	Describe("ValidateTimezone", func() {
+		It("accepts known time zones and clearing the timezone", func() {
+			Expect(services.ValidateTimezone("America/New_York")).To(Succeed())
+			Expect(services.ValidateTimezone("")).To(Succeed())
+		})
+
+		It("returns an error when the time zone is not known", func() {
+			err := services.ValidateTimezone("Mars/Olympus_Mons")
+			Expect(err).To(MatchError(errors.New(`"timezone" must be a known time zone such as "America/New_York"`)))
+		})
+	})
+
+	Describe("ValidateQuietHours", func() {
+		It("accepts quiet hours and clearing them", func() {
+			Expect(services.ValidateQuietHours(services.QuietHours{Start: "22:00", End: "07:00"})).To(Succeed())
+			Expect(services.ValidateQuietHours(services.QuietHours{})).To(Succeed())
+		})
+
+		It("returns an error when a time is missing or malformed", func() {
+			err := services.ValidateQuietHours(services.QuietHours{Start: "22:00"})
+			Expect(err).To(MatchError(errors.New(`"quiet_hours" must have a "start" and an "end" such as "22:00"`)))
+
+			err = services.ValidateQuietHours(services.QuietHours{Start: "10pm", End: "07:00"})
+			Expect(err).To(MatchError(errors.New(`"quiet_hours" must have a "start" and an "end" such as "22:00"`)))
+		})
+
+		It("returns an error when quiet hours start and end at the same time", func() {
+			err := services.ValidateQuietHours(services.QuietHours{Start: "22:00", End: "22:00"})
+			Expect(err).To(MatchError(errors.New(`"quiet_hours" must not start and end at the same time`)))
+		})
+	})
 })
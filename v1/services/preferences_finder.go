@@ -1,14 +1,18 @@
 package services
 
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
 type PreferencesFinder struct {
 	preferencesRepo        PreferencesRepo
 	globalUnsubscribesRepo GlobalUnsubscribesRepo
+	userSettingsRepo       UserSettingsRepo
 }
 
-func NewPreferencesFinder(preferencesRepo PreferencesRepo, globalUnsubscribesRepo GlobalUnsubscribesRepo) *PreferencesFinder {
+func NewPreferencesFinder(preferencesRepo PreferencesRepo, globalUnsubscribesRepo GlobalUnsubscribesRepo, userSettingsRepo UserSettingsRepo) *PreferencesFinder {
 	return &PreferencesFinder{
 		preferencesRepo:        preferencesRepo,
 		globalUnsubscribesRepo: globalUnsubscribesRepo,
+		userSettingsRepo:       userSettingsRepo,
 	}
 }
 
@@ -26,6 +30,32 @@ func (finder PreferencesFinder) Find(database DatabaseInterface, userGUID string
 		return builder, err
 	}
 
+	settings, err := finder.userSettingsRepo.Find(conn, userGUID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); !ok {
+			return builder, err
+		}
+	}
+
+	if settings.Locale != "" {
+		builder.Locale = &settings.Locale
+	}
+
+	if settings.Digest != "" {
+		builder.Digest = &settings.Digest
+	}
+
+	if settings.Timezone != "" {
+		builder.Timezone = &settings.Timezone
+	}
+
+	if settings.QuietHoursStart != "" {
+		builder.QuietHours = &QuietHours{
+			Start: settings.QuietHoursStart,
+			End:   settings.QuietHoursEnd,
+		}
+	}
+
 	builder.GlobalUnsubscribe = globallyUnsubscribed
 	for _, preference := range preferences {
 		builder.Add(preference)
@@ -15,6 +15,7 @@ var _ = Describe("PreferencesFinder", func() {
 	var (
 		finder          *services.PreferencesFinder
 		preferencesRepo *mocks.PreferencesRepo
+		settingsRepo    *mocks.UserSettingsRepo
 		preferences     []models.Preference
 		database        *mocks.Database
 		conn            *mocks.Connection
@@ -48,7 +49,10 @@ var _ = Describe("PreferencesFinder", func() {
 		database = mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
-		finder = services.NewPreferencesFinder(preferencesRepo, fakeGlobalUnsubscribesRepo)
+		settingsRepo = mocks.NewUserSettingsRepo()
+		settingsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+		finder = services.NewPreferencesFinder(preferencesRepo, fakeGlobalUnsubscribesRepo, settingsRepo)
 	})
 
 	Describe("Find", func() {
@@ -66,6 +70,63 @@ var _ = Describe("PreferencesFinder", func() {
 			Expect(preferencesRepo.FindNonCriticalPreferencesCall.Receives.UserGUID).To(Equal("correct-user"))
 		})
 
+		It("includes the locale the user has chosen", func() {
+			settingsRepo.FindCall.Returns.Error = nil
+			settingsRepo.FindCall.Returns.Settings = models.UserSettings{
+				UserID: "correct-user",
+				Locale: "de-CH",
+			}
+
+			resultPreferences, err := finder.Find(database, "correct-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resultPreferences.Locale).NotTo(BeNil())
+			Expect(*resultPreferences.Locale).To(Equal("de-CH"))
+
+			Expect(settingsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(settingsRepo.FindCall.Receives.UserID).To(Equal("correct-user"))
+		})
+
+		It("includes the digest the user has chosen", func() {
+			settingsRepo.FindCall.Returns.Error = nil
+			settingsRepo.FindCall.Returns.Settings = models.UserSettings{
+				UserID: "correct-user",
+				Digest: models.DigestDaily,
+			}
+
+			resultPreferences, err := finder.Find(database, "correct-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resultPreferences.Digest).NotTo(BeNil())
+			Expect(*resultPreferences.Digest).To(Equal("daily"))
+		})
+
+		It("includes the timezone and quiet hours the user has chosen", func() {
+			settingsRepo.FindCall.Returns.Error = nil
+			settingsRepo.FindCall.Returns.Settings = models.UserSettings{
+				UserID:          "correct-user",
+				Timezone:        "America/New_York",
+				QuietHoursStart: "22:00",
+				QuietHoursEnd:   "07:00",
+			}
+
+			resultPreferences, err := finder.Find(database, "correct-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resultPreferences.Timezone).NotTo(BeNil())
+			Expect(*resultPreferences.Timezone).To(Equal("America/New_York"))
+			Expect(resultPreferences.QuietHours).To(Equal(&services.QuietHours{
+				Start: "22:00",
+				End:   "07:00",
+			}))
+		})
+
+		Context("when the user settings repo returns an error", func() {
+			It("should propagate the error", func() {
+				settingsRepo.FindCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := finder.Find(database, "correct-user")
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
+		})
+
 		Context("when the preferences repo returns an error", func() {
 			It("should propagate the error", func() {
 				preferencesRepo.FindNonCriticalPreferencesCall.Returns.Error = errors.New("BOOM!")
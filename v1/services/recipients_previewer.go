@@ -0,0 +1,239 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/cloudfoundry-incubator/notifications/address"
+	"github.com/cloudfoundry-incubator/notifications/uaa"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+// The reasons a resolved recipient would not receive a notification. They
+// cover the address and unsubscribe checks of the delivery worker only; a
+// preview does not tell whether a recipient's copy would be deduplicated,
+// collected into a digest, deferred for quiet hours or left to expire.
+const (
+	SkipReasonUnsubscribed         = "unsubscribed"
+	SkipReasonGloballyUnsubscribed = "globally_unsubscribed"
+	SkipReasonNoEmail              = "no_email"
+	SkipReasonInvalidEmail         = "invalid_email"
+)
+
+type loadsUsers interface {
+	Load(userGUIDs []string, token string) (map[string]uaa.User, error)
+}
+
+type kindFinder interface {
+	Find(connection models.ConnectionInterface, kindID string, clientID string) (models.Kind, error)
+}
+
+type unsubscribesGetter interface {
+	Get(connection models.ConnectionInterface, userGUID string, clientID string, kindID string) (bool, error)
+}
+
+type globalUnsubscribesGetter interface {
+	Get(connection models.ConnectionInterface, userGUID string) (bool, error)
+}
+
+// RecipientPreview is a recipient a dispatch resolves to. SkipReason is
+// empty for recipients that pass the checks of the preview.
+type RecipientPreview struct {
+	GUID       string
+	Email      string
+	SkipReason string
+}
+
+func (recipient RecipientPreview) key() string {
+	if recipient.GUID != "" {
+		return recipient.GUID
+	}
+
+	return recipient.Email
+}
+
+// Suppressed reports whether the recipient is skipped because the user
+// asked not to receive the notification, rather than because it cannot be
+// delivered.
+func (recipient RecipientPreview) Suppressed() bool {
+	return recipient.SkipReason == SkipReasonUnsubscribed || recipient.SkipReason == SkipReasonGloballyUnsubscribed
+}
+
+// RecipientsPreview counts every recipient of a dispatch and lists a page of
+// them. Suppressed recipients are also counted under their skip reason.
+type RecipientsPreview struct {
+	Total       int
+	Deliverable int
+	Suppressed  int
+	Skipped     map[string]int
+	Recipients  []RecipientPreview
+}
+
+type RecipientsPreviewer struct {
+	tokenLoader            loadsTokens
+	userLoader             loadsUsers
+	kindsRepo              kindFinder
+	unsubscribesRepo       unsubscribesGetter
+	globalUnsubscribesRepo globalUnsubscribesGetter
+}
+
+func NewRecipientsPreviewer(tokenLoader loadsTokens, userLoader loadsUsers, kindsRepo kindFinder, unsubscribesRepo unsubscribesGetter, globalUnsubscribesRepo globalUnsubscribesGetter) RecipientsPreviewer {
+	return RecipientsPreviewer{
+		tokenLoader:            tokenLoader,
+		userLoader:             userLoader,
+		kindsRepo:              kindsRepo,
+		unsubscribesRepo:       unsubscribesRepo,
+		globalUnsubscribesRepo: globalUnsubscribesRepo,
+	}
+}
+
+// Preview makes the address and unsubscribe checks of the delivery worker
+// for each recipient of the resolution, without enqueuing anything. A
+// deliverable recipient may still be held back by the worker's later checks,
+// which depend on when the notification is sent. Recipients are ordered by their
+// GUID, or by their email address when they have none, and paged on it.
+func (previewer RecipientsPreviewer) Preview(conn ConnectionInterface, dispatch Dispatch, resolution Resolution, page models.Page) (RecipientsPreview, []string, error) {
+	if len(page.After) > 1 {
+		return RecipientsPreview{}, nil, models.NewPageKeyError()
+	}
+
+	emails, err := previewer.loadEmails(dispatch, resolution.Users)
+	if err != nil {
+		return RecipientsPreview{}, nil, err
+	}
+
+	critical, err := previewer.isCritical(conn, dispatch)
+	if err != nil {
+		return RecipientsPreview{}, nil, err
+	}
+
+	preview := RecipientsPreview{
+		Skipped:    map[string]int{},
+		Recipients: []RecipientPreview{},
+	}
+
+	var recipients []RecipientPreview
+	for _, user := range resolution.Users {
+		recipient := RecipientPreview{
+			GUID:  user.GUID,
+			Email: user.Email,
+		}
+		if recipient.Email == "" {
+			recipient.Email = emails[user.GUID]
+		}
+
+		recipient.SkipReason, err = previewer.skipReason(conn, dispatch, recipient, critical)
+		if err != nil {
+			return RecipientsPreview{}, nil, err
+		}
+
+		preview.Total++
+		if recipient.SkipReason == "" {
+			preview.Deliverable++
+		} else {
+			preview.Skipped[recipient.SkipReason]++
+		}
+		if recipient.Suppressed() {
+			preview.Suppressed++
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	sort.Slice(recipients, func(i, j int) bool {
+		return recipients[i].key() < recipients[j].key()
+	})
+
+	for _, recipient := range recipients {
+		if len(page.After) > 0 && recipient.key() <= page.After[0] {
+			continue
+		}
+
+		if page.Limit > 0 && len(preview.Recipients) == page.Limit {
+			last := preview.Recipients[len(preview.Recipients)-1]
+			return preview, []string{last.key()}, nil
+		}
+
+		preview.Recipients = append(preview.Recipients, recipient)
+	}
+
+	return preview, nil, nil
+}
+
+func (previewer RecipientsPreviewer) loadEmails(dispatch Dispatch, users []User) (map[string]string, error) {
+	var guids []string
+	for _, user := range users {
+		if user.Email == "" && user.GUID != "" {
+			guids = append(guids, user.GUID)
+		}
+	}
+
+	emails := map[string]string{}
+	if len(guids) == 0 {
+		return emails, nil
+	}
+
+	token, err := previewer.tokenLoader.Load(dispatch.UAAHost)
+	if err != nil {
+		return emails, err
+	}
+
+	uaaUsers, err := previewer.userLoader.Load(guids, token)
+	if err != nil {
+		return emails, err
+	}
+
+	for guid, user := range uaaUsers {
+		emails[guid] = address.Primary(user.Emails)
+	}
+
+	return emails, nil
+}
+
+func (previewer RecipientsPreviewer) isCritical(conn ConnectionInterface, dispatch Dispatch) (bool, error) {
+	kind, err := previewer.kindsRepo.Find(conn, dispatch.Kind.ID, dispatch.Client.ID)
+	switch err.(type) {
+	case nil:
+		return kind.Critical, nil
+	case models.NotFoundError:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// skipReason makes the checks of the delivery worker's shouldDeliver in the
+// same order: every notification needs a valid address to be sent to, and
+// critical notifications ignore unsubscribes.
+func (previewer RecipientsPreviewer) skipReason(conn ConnectionInterface, dispatch Dispatch, recipient RecipientPreview, critical bool) (string, error) {
+	if recipient.Email == "" {
+		return SkipReasonNoEmail, nil
+	}
+
+	if _, err := address.Parse(recipient.Email); err != nil {
+		return SkipReasonInvalidEmail, nil
+	}
+
+	if critical || recipient.GUID == "" {
+		return "", nil
+	}
+
+	globallyUnsubscribed, err := previewer.globalUnsubscribesRepo.Get(conn, recipient.GUID)
+	if err != nil {
+		return "", err
+	}
+
+	if globallyUnsubscribed {
+		return SkipReasonGloballyUnsubscribed, nil
+	}
+
+	unsubscribed, err := previewer.unsubscribesRepo.Get(conn, recipient.GUID, dispatch.Client.ID, dispatch.Kind.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if unsubscribed {
+		return SkipReasonUnsubscribed, nil
+	}
+
+	return "", nil
+}
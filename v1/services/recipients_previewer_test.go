@@ -0,0 +1,243 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/uaa"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RecipientsPreviewer", func() {
+	var (
+		previewer              services.RecipientsPreviewer
+		tokenLoader            *mocks.TokenLoader
+		userLoader             *mocks.UserLoader
+		kindsRepo              *mocks.KindsRepo
+		unsubscribesRepo       *mocks.UnsubscribesRepo
+		globalUnsubscribesRepo *mocks.GlobalUnsubscribesRepo
+		conn                   *mocks.Connection
+		dispatch               services.Dispatch
+		resolution             services.Resolution
+	)
+
+	BeforeEach(func() {
+		tokenLoader = mocks.NewTokenLoader()
+		tokenLoader.LoadCall.Returns.Token = "some-token"
+
+		userLoader = mocks.NewUserLoader()
+		userLoader.LoadCall.Returns.Users = map[string]uaa.User{
+			"user-1": {ID: "user-1", Emails: []string{"one@example.com"}},
+			"user-2": {ID: "user-2"},
+			"user-3": {ID: "user-3", Emails: []string{"not an address"}},
+		}
+
+		kindsRepo = mocks.NewKindsRepo()
+		kindsRepo.FindCall.Returns.Kinds = []models.Kind{{ID: "some-kind", ClientID: "some-client"}}
+
+		unsubscribesRepo = mocks.NewUnsubscribesRepo()
+		globalUnsubscribesRepo = mocks.NewGlobalUnsubscribesRepo()
+		conn = mocks.NewConnection()
+
+		dispatch = services.Dispatch{
+			Connection: conn,
+			UAAHost:    "uaa",
+			Kind:       services.DispatchKind{ID: "some-kind"},
+			Client:     services.DispatchClient{ID: "some-client"},
+		}
+
+		resolution = services.Resolution{
+			Users: []services.User{
+				{GUID: "user-3"},
+				{GUID: "user-1"},
+				{Email: "someone@example.com"},
+				{GUID: "user-2"},
+			},
+		}
+
+		previewer = services.NewRecipientsPreviewer(tokenLoader, userLoader, kindsRepo, unsubscribesRepo, globalUnsubscribesRepo)
+	})
+
+	It("checks every recipient without enqueuing anything", func() {
+		preview, next, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next).To(BeNil())
+
+		Expect(preview).To(Equal(services.RecipientsPreview{
+			Total:       4,
+			Deliverable: 2,
+			Skipped: map[string]int{
+				services.SkipReasonNoEmail:      1,
+				services.SkipReasonInvalidEmail: 1,
+			},
+			Recipients: []services.RecipientPreview{
+				{Email: "someone@example.com"},
+				{GUID: "user-1", Email: "one@example.com"},
+				{GUID: "user-2", SkipReason: services.SkipReasonNoEmail},
+				{GUID: "user-3", Email: "not an address", SkipReason: services.SkipReasonInvalidEmail},
+			},
+		}))
+
+		Expect(tokenLoader.LoadCall.Receives.UAAHost).To(Equal("uaa"))
+		Expect(userLoader.LoadCall.Receives.UserGUIDs).To(Equal([]string{"user-3", "user-1", "user-2"}))
+		Expect(userLoader.LoadCall.Receives.Token).To(Equal("some-token"))
+
+		Expect(kindsRepo.FindCall.Receives.Connection).To(Equal(conn))
+		Expect(kindsRepo.FindCall.Receives.KindID).To(Equal("some-kind"))
+		Expect(kindsRepo.FindCall.Receives.ClientID).To(Equal("some-client"))
+
+		Expect(unsubscribesRepo.GetCall.Receives.ClientID).To(Equal("some-client"))
+		Expect(unsubscribesRepo.GetCall.Receives.KindID).To(Equal("some-kind"))
+	})
+
+	It("suppresses recipients who unsubscribed from the notification", func() {
+		unsubscribesRepo.GetCall.Returns.Unsubscribed = true
+
+		preview, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(preview.Deliverable).To(Equal(1))
+		Expect(preview.Suppressed).To(Equal(1))
+		Expect(preview.Skipped).To(Equal(map[string]int{
+			services.SkipReasonUnsubscribed: 1,
+			services.SkipReasonNoEmail:      1,
+			services.SkipReasonInvalidEmail: 1,
+		}))
+		Expect(preview.Recipients[1].Suppressed()).To(BeTrue())
+	})
+
+	It("skips recipients without an address before checking their unsubscribes, as the worker does", func() {
+		globalUnsubscribesRepo.GetCall.Returns.Unsubscribed = true
+		resolution.Users = []services.User{{GUID: "user-2"}}
+
+		preview, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(preview.Suppressed).To(Equal(0))
+		Expect(preview.Recipients).To(Equal([]services.RecipientPreview{
+			{GUID: "user-2", SkipReason: services.SkipReasonNoEmail},
+		}))
+	})
+
+	It("suppresses recipients who unsubscribed from every notification", func() {
+		globalUnsubscribesRepo.GetCall.Returns.Unsubscribed = true
+		unsubscribesRepo.GetCall.Returns.Unsubscribed = true
+
+		preview, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(preview.Deliverable).To(Equal(1))
+		Expect(preview.Suppressed).To(Equal(1))
+		Expect(preview.Skipped).To(Equal(map[string]int{
+			services.SkipReasonGloballyUnsubscribed: 1,
+			services.SkipReasonNoEmail:              1,
+			services.SkipReasonInvalidEmail:         1,
+		}))
+	})
+
+	It("ignores unsubscribes for critical notifications", func() {
+		kindsRepo.FindCall.Returns.Kinds = []models.Kind{{ID: "some-kind", ClientID: "some-client", Critical: true}}
+		globalUnsubscribesRepo.GetCall.Returns.Unsubscribed = true
+
+		preview, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(preview.Deliverable).To(Equal(2))
+		Expect(preview.Skipped).To(Equal(map[string]int{
+			services.SkipReasonNoEmail:      1,
+			services.SkipReasonInvalidEmail: 1,
+		}))
+	})
+
+	It("treats notifications that are not registered as non-critical", func() {
+		kindsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+		unsubscribesRepo.GetCall.Returns.Unsubscribed = true
+
+		preview, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preview.Skipped[services.SkipReasonUnsubscribed]).To(Equal(1))
+	})
+
+	It("does not look up users when every recipient has an email address", func() {
+		resolution.Users = []services.User{{Email: "someone@example.com"}}
+
+		preview, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preview.Deliverable).To(Equal(1))
+
+		Expect(userLoader.LoadCall.Receives.UserGUIDs).To(BeNil())
+	})
+
+	Context("when a page is requested", func() {
+		It("lists the recipients of the page and the key of the next one", func() {
+			kindsRepo.FindCall.Returns.Kinds = []models.Kind{{}, {}}
+
+			preview, next, err := previewer.Preview(conn, dispatch, resolution, models.Page{Limit: 2})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(preview.Total).To(Equal(4))
+			Expect(preview.Recipients).To(Equal([]services.RecipientPreview{
+				{Email: "someone@example.com"},
+				{GUID: "user-1", Email: "one@example.com"},
+			}))
+			Expect(next).To(Equal([]string{"user-1"}))
+
+			preview, next, err = previewer.Preview(conn, dispatch, resolution, models.Page{After: next, Limit: 2})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(preview.Total).To(Equal(4))
+			Expect(preview.Recipients).To(Equal([]services.RecipientPreview{
+				{GUID: "user-2", SkipReason: services.SkipReasonNoEmail},
+				{GUID: "user-3", Email: "not an address", SkipReason: services.SkipReasonInvalidEmail},
+			}))
+			Expect(next).To(BeNil())
+		})
+
+		It("rejects keys of other listings", func() {
+			_, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{After: []string{"client", "kind"}, Limit: 2})
+			Expect(err).To(MatchError(models.NewPageKeyError()))
+		})
+	})
+
+	Context("failure cases", func() {
+		It("returns errors from the token loader", func() {
+			tokenLoader.LoadCall.Returns.Error = errors.New("BOOM!")
+
+			_, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+
+		It("returns errors from the user loader", func() {
+			userLoader.LoadCall.Returns.Error = errors.New("BOOM!")
+
+			_, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+
+		It("returns errors from the kinds repo", func() {
+			kindsRepo.FindCall.Returns.Error = errors.New("BOOM!")
+
+			_, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+
+		It("returns errors from the unsubscribes repos", func() {
+			kindsRepo.FindCall.Returns.Kinds = []models.Kind{{}, {}}
+
+			unsubscribesRepo.GetCall.Returns.Error = errors.New("BOOM!")
+
+			_, _, err := previewer.Preview(conn, dispatch, resolution, models.Page{})
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+
+			unsubscribesRepo.GetCall.Returns.Error = nil
+			globalUnsubscribesRepo.GetCall.Returns.Error = errors.New("BANG!")
+
+			_, _, err = previewer.Preview(conn, dispatch, resolution, models.Page{})
+			Expect(err).To(MatchError(errors.New("BANG!")))
+		})
+	})
+})
@@ -2,10 +2,13 @@ package services
 
 import "github.com/cloudfoundry-incubator/notifications/cf"
 
-const SpaceEndorsement = `You received this message because you belong to the "{{.Space}}" space in the "{{.Organization}}" organization.`
+const (
+	SpaceEndorsement     = `You received this message because you belong to the "{{.Space}}" space in the "{{.Organization}}" organization.`
+	SpaceRoleEndorsement = `You received this message because you are a {{.SpaceRole}} in the "{{.Space}}" space in the "{{.Organization}}" organization.`
+)
 
 type spaceUserIDFinder interface {
-	UserIDsBelongingToSpace(spaceGUID, token string) (userIDs []string, err error)
+	UserIDsBelongingToSpace(spaceGUID, role, token string) (userIDs []string, err error)
 }
 
 type loadsSpaces interface {
@@ -31,19 +34,34 @@ func NewSpaceStrategy(tokenLoader loadsTokens, spaceLoader loadsSpaces, organiza
 }
 
 func (strategy SpaceStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
-	var responses []Response
+	resolution, err := strategy.Resolve(dispatch)
+	if err != nil {
+		return []Response{}, err
+	}
+
+	return resolution.enqueue(strategy.enqueuer, dispatch)
+}
 
+func (strategy SpaceStrategy) Resolve(dispatch Dispatch) (Resolution, error) {
 	options := Options{
-		To:                dispatch.Message.To,
-		ReplyTo:           dispatch.Message.ReplyTo,
-		Subject:           dispatch.Message.Subject,
-		KindID:            dispatch.Kind.ID,
-		KindDescription:   dispatch.Kind.Description,
-		SourceDescription: dispatch.Client.Description,
-		Endorsement:       SpaceEndorsement,
-		Text:              dispatch.Message.Text,
-		TemplateID:        dispatch.TemplateID,
-		Role:              dispatch.Role,
+		To:                  dispatch.Message.To,
+		ReplyTo:             dispatch.Message.ReplyTo,
+		Subject:             dispatch.Message.Subject,
+		KindID:              dispatch.Kind.ID,
+		KindDescription:     dispatch.Kind.Description,
+		SourceDescription:   dispatch.Client.Description,
+		Endorsement:         SpaceEndorsement,
+		EndorsementID:       SpaceEndorsementID,
+		Text:                dispatch.Message.Text,
+		TemplateID:          dispatch.TemplateID,
+		Locale:              dispatch.Message.Locale,
+		Data:                dispatch.Message.Data,
+		Markdown:            dispatch.Message.Markdown,
+		SpaceRole:           dispatch.Role,
+		ExpiresAt:           dispatch.Message.ExpiresAt,
+		DedupKey:            dispatch.Message.DedupKey,
+		DedupWindow:         dispatch.Kind.DedupWindow,
+		SkipTextAlternative: dispatch.Kind.SkipTextAlternative,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -52,14 +70,24 @@ func (strategy SpaceStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 		},
 	}
 
+	if dispatch.Role != "" {
+		options.Endorsement = SpaceRoleEndorsement
+		options.EndorsementID = SpaceRoleEndorsementID
+	}
+
 	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
 	if err != nil {
-		return responses, err
+		return Resolution{}, err
+	}
+
+	space, err := strategy.spaceLoader.Load(dispatch.GUID, token)
+	if err != nil {
+		return Resolution{}, err
 	}
 
-	userGUIDs, err := strategy.findsUserIDs.UserIDsBelongingToSpace(dispatch.GUID, token)
+	userGUIDs, err := strategy.findsUserIDs.UserIDsBelongingToSpace(dispatch.GUID, options.SpaceRole, token)
 	if err != nil {
-		return responses, err
+		return Resolution{}, err
 	}
 
 	var users []User
@@ -67,25 +95,15 @@ func (strategy SpaceStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 		users = append(users, User{GUID: guid})
 	}
 
-	space, err := strategy.spaceLoader.Load(dispatch.GUID, token)
-	if err != nil {
-		return responses, err
-	}
-
 	org, err := strategy.organizationLoader.Load(space.OrganizationGUID, token)
 	if err != nil {
-		return responses, err
+		return Resolution{}, err
 	}
 
-	return strategy.enqueuer.Enqueue(
-		dispatch.Connection,
-		users,
-		options,
-		space,
-		org,
-		dispatch.Client.ID,
-		dispatch.UAAHost,
-		"",
-		dispatch.VCAPRequest.ID,
-		dispatch.VCAPRequest.ReceiptTime)
+	return Resolution{
+		Users:        users,
+		Options:      options,
+		Space:        space,
+		Organization: org,
+	}, nil
 }
@@ -73,10 +73,13 @@ var _ = Describe("Space Strategy", func() {
 						GUID:       "space-001",
 						Connection: conn,
 						Message: services.DispatchMessage{
-							To:      "dr@strangelove.com",
-							ReplyTo: "reply-to@example.com",
-							Subject: "this is the subject",
-							Text:    "Please reset your password by clicking on this link...",
+							To:       "dr@strangelove.com",
+							ReplyTo:  "reply-to@example.com",
+							Subject:  "this is the subject",
+							Locale:   "de-CH",
+							Data:     map[string]interface{}{"app_name": "banana"},
+							Markdown: "# banana",
+							Text:     "Please reset your password by clicking on this link...",
 							HTML: services.HTML{
 								BodyContent:    "<p>Welcome to the system, now get off my lawn.</p>",
 								BodyAttributes: "some-html-body-attributes",
@@ -114,6 +117,9 @@ var _ = Describe("Space Strategy", func() {
 					Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
 						ReplyTo:           "reply-to@example.com",
 						Subject:           "this is the subject",
+						Locale:            "de-CH",
+						Data:              map[string]interface{}{"app_name": "banana"},
+						Markdown:          "# banana",
 						To:                "dr@strangelove.com",
 						KindID:            "forgot_password",
 						KindDescription:   "Password reminder",
@@ -126,7 +132,8 @@ var _ = Describe("Space Strategy", func() {
 							Head:           "<head></head>",
 							Doctype:        "<html>",
 						},
-						Endorsement: services.SpaceEndorsement,
+						Endorsement:   services.SpaceEndorsement,
+						EndorsementID: services.SpaceEndorsementID,
 					}))
 					Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{
 						GUID:             "space-001",
@@ -146,8 +153,47 @@ var _ = Describe("Space Strategy", func() {
 					Expect(tokenLoader.LoadCall.Receives.UAAHost).To(Equal("uaa"))
 
 					Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.SpaceGUID).To(Equal("space-001"))
+					Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.Role).To(Equal(""))
 					Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.Token).To(Equal(token))
 				})
+
+				Context("when the space role field is set", func() {
+					It("sends to the users holding the role", func() {
+						_, err := strategy.Dispatch(services.Dispatch{
+							GUID:       "space-001",
+							Role:       "SpaceManager",
+							Connection: conn,
+							Message: services.DispatchMessage{
+								Subject: "this is the subject",
+								Text:    "A security advisory",
+							},
+							Kind: services.DispatchKind{
+								ID:          "security_advisory",
+								Description: "Security advisory",
+							},
+							Client: services.DispatchClient{
+								ID:          "mister-client",
+								Description: "Security team",
+							},
+						})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
+							Subject:           "this is the subject",
+							KindID:            "security_advisory",
+							KindDescription:   "Security advisory",
+							SourceDescription: "Security team",
+							Text:              "A security advisory",
+							SpaceRole:         "SpaceManager",
+							Endorsement:       services.SpaceRoleEndorsement,
+							EndorsementID:     services.SpaceRoleEndorsementID,
+						}))
+
+						Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.SpaceGUID).To(Equal("space-001"))
+						Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.Role).To(Equal("SpaceManager"))
+						Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.Token).To(Equal(token))
+					})
+				})
 			})
 		})
 
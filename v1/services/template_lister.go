@@ -16,17 +16,23 @@ func NewTemplateLister(templatesRepo TemplatesRepo) TemplateLister {
 	}
 }
 
-func (lister TemplateLister) List(database DatabaseInterface) (map[string]TemplateSummary, error) {
-	templates, err := lister.templatesRepo.ListIDsAndNames(database.Connection())
+// List returns the templates matching the filter on the given page, along
+// with the key of the page that follows, which is nil on the last page.
+func (lister TemplateLister) List(database DatabaseInterface, filter models.TemplatesFilter, page models.Page) (map[string]TemplateSummary, []string, error) {
+	templates, err := lister.templatesRepo.List(database.Connection(), filter, page.Peek())
 	if err != nil {
-		return map[string]TemplateSummary{}, err
+		return map[string]TemplateSummary{}, nil, err
+	}
+
+	var next []string
+	if page.More(len(templates)) {
+		templates = templates[:page.Limit]
+		next = []string{templates[len(templates)-1].ID}
 	}
 
 	templatesMap := map[string]TemplateSummary{}
 	for _, template := range templates {
-		if template.ID != models.DefaultTemplateID {
-			templatesMap[template.ID] = TemplateSummary{Name: template.Name}
-		}
+		templatesMap[template.ID] = TemplateSummary{Name: template.Name}
 	}
-	return templatesMap, nil
+	return templatesMap, next, nil
 }
@@ -31,7 +31,7 @@ var _ = Describe("TemplateLister", func() {
 	Describe("List", func() {
 		Context("when the templates exists in the database", func() {
 			BeforeEach(func() {
-				templatesRepo.ListIDsAndNamesCall.Returns.Templates = []models.Template{
+				templatesRepo.ListCall.Returns.Templates = []models.Template{
 					{
 						ID:      "starwarr-guid",
 						Name:    "Star Wars",
@@ -39,13 +39,6 @@ var _ = Describe("TemplateLister", func() {
 						HTML:    "<p>Millenium Falcon</p>",
 						Text:    "Millenium Falcon",
 					},
-					{
-						ID:      models.DefaultTemplateID,
-						Name:    "default name",
-						Subject: "default subject",
-						HTML:    "<h1>default</h1>",
-						Text:    "defaults!",
-					},
 					{
 						ID:      "robot-guid",
 						Name:    "Big Hero 6",
@@ -71,8 +64,9 @@ var _ = Describe("TemplateLister", func() {
 			})
 
 			It("returns a list of guids and template names", func() {
-				templates, err := lister.List(database)
+				templates, next, err := lister.List(database, models.TemplatesFilter{Name: "a"}, models.Page{})
 				Expect(err).ToNot(HaveOccurred())
+				Expect(next).To(BeNil())
 				Expect(templates).To(Equal(map[string]services.TemplateSummary{
 					"starwarr-guid":   {Name: "Star Wars"},
 					"robot-guid":      {Name: "Big Hero 6"},
@@ -80,15 +74,34 @@ var _ = Describe("TemplateLister", func() {
 					"starvation-guid": {Name: "Hungry Play"},
 				}))
 
-				Expect(templatesRepo.ListIDsAndNamesCall.Receives.Connection).To(Equal(conn))
+				Expect(templatesRepo.ListCall.Receives.Connection).To(Equal(conn))
+				Expect(templatesRepo.ListCall.Receives.Filter).To(Equal(models.TemplatesFilter{Name: "a"}))
+			})
+
+			It("returns the key of the next page when there are more templates", func() {
+				templates, next, err := lister.List(database, models.TemplatesFilter{}, models.Page{
+					After: []string{"aardvark-guid"},
+					Limit: 2,
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates).To(Equal(map[string]services.TemplateSummary{
+					"starwarr-guid": {Name: "Star Wars"},
+					"robot-guid":    {Name: "Big Hero 6"},
+				}))
+				Expect(next).To(Equal([]string{"robot-guid"}))
+
+				Expect(templatesRepo.ListCall.Receives.Page).To(Equal(models.Page{
+					After: []string{"aardvark-guid"},
+					Limit: 3,
+				}))
 			})
 		})
 
 		Context("the lister has an error", func() {
 			It("propagates the error", func() {
-				templatesRepo.ListIDsAndNamesCall.Returns.Error = errors.New("some-error")
+				templatesRepo.ListCall.Returns.Error = errors.New("some-error")
 
-				_, err := lister.List(database)
+				_, _, err := lister.List(database, models.TemplatesFilter{}, models.Page{})
 				Expect(err).To(MatchError(errors.New("some-error")))
 			})
 		})
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type templateCompiler interface {
+	CompileParts(context common.MessageContext) ([]mail.Part, error)
+	CompileSubject(context common.MessageContext) (string, error)
+}
+
+type TemplatePreview struct {
+	Subject string
+	Text    string
+	HTML    string
+	Errors  []string
+}
+
+type TemplatePreviewer struct {
+	compiler templateCompiler
+}
+
+func NewTemplatePreviewer(compiler templateCompiler) TemplatePreviewer {
+	return TemplatePreviewer{
+		compiler: compiler,
+	}
+}
+
+// Preview renders each part of the template separately so that an error in
+// one part does not hide the output of the others.
+func (previewer TemplatePreviewer) Preview(template models.Template, context common.MessageContext) TemplatePreview {
+	preview := TemplatePreview{
+		Errors: []string{},
+	}
+
+	context.SubjectTemplate = template.Subject
+	context.TextTemplate = template.Text
+	context.HTMLTemplate = template.HTML
+	context.MarkdownTemplate = template.Markdown
+	context.Layout = template.Layout
+	context.LegacyHTMLEscaping = template.UsesLegacyHTMLEscaping()
+
+	subject, err := previewer.compiler.CompileSubject(context)
+	if err != nil {
+		preview.Errors = append(preview.Errors, fmt.Sprintf("subject: %s", err))
+	}
+	preview.Subject = subject
+
+	if template.Markdown != "" {
+		preview.Text, preview.HTML, err = previewer.compileMarkdown(context)
+		if err != nil {
+			preview.Errors = append(preview.Errors, fmt.Sprintf("markdown: %s", err))
+		}
+
+		return preview
+	}
+
+	textContext := context
+	textContext.HTMLTemplate = ""
+	preview.Text, err = previewer.compilePart(textContext, "text/plain")
+	if err != nil {
+		preview.Errors = append(preview.Errors, fmt.Sprintf("text: %s", err))
+	}
+
+	htmlContext := context
+	htmlContext.TextTemplate = ""
+	preview.HTML, err = previewer.compilePart(htmlContext, "text/html")
+	if err != nil {
+		preview.Errors = append(preview.Errors, fmt.Sprintf("html: %s", err))
+	}
+
+	return preview
+}
+
+// compileMarkdown renders a Markdown template, which produces both parts at
+// once.
+func (previewer TemplatePreviewer) compileMarkdown(context common.MessageContext) (string, string, error) {
+	parts, err := previewer.compiler.CompileParts(context)
+	if err != nil {
+		return "", "", err
+	}
+
+	var text, html string
+	for _, part := range parts {
+		switch part.ContentType {
+		case "text/plain":
+			text = part.Content
+		case "text/html":
+			html = part.Content
+		}
+	}
+
+	return text, html, nil
+}
+
+func (previewer TemplatePreviewer) compilePart(context common.MessageContext, contentType string) (string, error) {
+	parts, err := previewer.compiler.CompileParts(context)
+	if err != nil {
+		return "", err
+	}
+
+	for _, part := range parts {
+		if part.ContentType == contentType {
+			return part.Content, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplatePreviewer", func() {
+	var (
+		previewer services.TemplatePreviewer
+		context   common.MessageContext
+	)
+
+	BeforeEach(func() {
+		previewer = services.NewTemplatePreviewer(common.Packager{})
+
+		context = common.MessageContext{
+			Subject:           "Your app crashed",
+			Text:              "app <dora> crashed",
+			HTML:              "<p>app dora crashed</p>",
+			KindDescription:   "App Crashes",
+			SourceDescription: "Cloud Controller",
+		}
+	})
+
+	It("renders the subject, text and html of the template", func() {
+		preview := previewer.Preview(models.Template{
+			Subject: "[{{.SourceDescription}}] {{.Subject}}",
+			Text:    "{{.KindDescription}}: {{.Text}}",
+			HTML:    "<h1>{{.KindDescription}}</h1>{{.HTML}} {{.Text}}",
+		}, context)
+
+		Expect(preview.Errors).To(BeEmpty())
+		Expect(preview.Subject).To(Equal("[Cloud Controller] Your app crashed"))
+		Expect(preview.Text).To(Equal("App Crashes: app <dora> crashed"))
+		Expect(preview.HTML).To(ContainSubstring("<h1>App Crashes</h1><p>app dora crashed</p> app &lt;dora&gt; crashed"))
+	})
+
+	It("renders the parts of a template through its layout", func() {
+		preview := previewer.Preview(models.Template{
+			Subject: "{{.Subject}}",
+			Text:    `{{.Text}}{{define "branded"}}{{template "content" .}} -- {{.SourceDescription}}{{end}}`,
+			HTML:    `{{.HTML}}{{define "branded"}}<main>{{template "content" .}}</main>{{end}}`,
+			Layout:  "branded",
+		}, context)
+
+		Expect(preview.Errors).To(BeEmpty())
+		Expect(preview.Subject).To(Equal("Your app crashed"))
+		Expect(preview.Text).To(Equal("app <dora> crashed -- Cloud Controller"))
+		Expect(preview.HTML).To(ContainSubstring("<main><p>app dora crashed</p></main>"))
+	})
+
+	It("reports errors for each part without hiding the others", func() {
+		preview := previewer.Preview(models.Template{
+			Subject: "{{.Subject",
+			Text:    "{{.KindDescription}}: {{.Text}}",
+			HTML:    "{{if}}",
+		}, context)
+
+		Expect(preview.Subject).To(BeEmpty())
+		Expect(preview.Text).To(Equal("App Crashes: app <dora> crashed"))
+		Expect(preview.HTML).To(BeEmpty())
+		Expect(preview.Errors).To(HaveLen(2))
+		Expect(preview.Errors[0]).To(HavePrefix("subject: "))
+		Expect(preview.Errors[1]).To(HavePrefix("html: "))
+	})
+
+	It("renders the html with the escaping the template opts into", func() {
+		context.ClientID = `" onclick="steal()`
+
+		preview := previewer.Preview(models.Template{
+			Subject:  "{{.Subject}}",
+			HTML:     `<a title="{{.ClientID}}">{{.KindDescription}}</a>`,
+			Metadata: `{"legacy_html_escaping": true}`,
+		}, context)
+
+		Expect(preview.Errors).To(BeEmpty())
+		Expect(preview.HTML).To(ContainSubstring(`<a title="&#34; onclick=&#34;steal()">App Crashes</a>`))
+	})
+
+	It("renders both parts from a Markdown template", func() {
+		preview := previewer.Preview(models.Template{
+			Subject:  "{{.Subject}}",
+			Markdown: "# {{.KindDescription}}\n\n{{.Text}}",
+		}, context)
+
+		Expect(preview.Errors).To(BeEmpty())
+		Expect(preview.Text).To(Equal("App Crashes\n===========\n\napp <dora> crashed"))
+		Expect(preview.HTML).To(ContainSubstring("<h1>App Crashes</h1>\n<p>app &lt;dora&gt; crashed</p>"))
+	})
+
+	It("reports errors in a Markdown template once", func() {
+		preview := previewer.Preview(models.Template{
+			Subject:  "{{.Subject}}",
+			Markdown: "{{.Planet}}",
+		}, context)
+
+		Expect(preview.Errors).To(HaveLen(1))
+		Expect(preview.Errors[0]).To(HavePrefix("markdown: "))
+	})
+})
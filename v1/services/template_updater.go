@@ -2,20 +2,43 @@ package services
 
 import "github.com/cloudfoundry-incubator/notifications/v1/models"
 
+type templateVersionsCreator interface {
+	Create(connection models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error)
+}
+
 type TemplateUpdater struct {
 	templatesRepo TemplatesRepo
+	versionsRepo  templateVersionsCreator
 }
 
-func NewTemplateUpdater(templatesRepo TemplatesRepo) TemplateUpdater {
+func NewTemplateUpdater(templatesRepo TemplatesRepo, versionsRepo templateVersionsCreator) TemplateUpdater {
 	return TemplateUpdater{
 		templatesRepo: templatesRepo,
+		versionsRepo:  versionsRepo,
 	}
 }
 
-func (updater TemplateUpdater) Update(database DatabaseInterface, templateID string, template models.Template) error {
-	_, err := updater.templatesRepo.Update(database.Connection(), templateID, template)
+// Update saves the template and records the result as a new version authored
+// by the given client.
+func (updater TemplateUpdater) Update(database DatabaseInterface, templateID string, template models.Template, clientID string) error {
+	transaction := database.Connection().Transaction()
+
+	err := transaction.Begin()
+	if err != nil {
+		return err
+	}
+
+	updatedTemplate, err := updater.templatesRepo.Update(transaction, templateID, template)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	_, err = updater.versionsRepo.Create(transaction, models.NewTemplateVersion(updatedTemplate, clientID))
 	if err != nil {
+		transaction.Rollback()
 		return err
 	}
-	return nil
+
+	return transaction.Commit()
 }
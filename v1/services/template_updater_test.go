@@ -15,18 +15,30 @@ var _ = Describe("Updater", func() {
 	Describe("Update", func() {
 		var (
 			conn          *mocks.Connection
+			transaction   *mocks.Transaction
 			database      *mocks.Database
 			templatesRepo *mocks.TemplatesRepo
+			versionsRepo  *mocks.TemplateVersionsRepo
 			updater       services.TemplateUpdater
 		)
 
 		BeforeEach(func() {
 			conn = mocks.NewConnection()
+			transaction = mocks.NewTransaction()
+			conn.TransactionCall.Returns.Transaction = transaction
 			database = mocks.NewDatabase()
 			database.ConnectionCall.Returns.Connection = conn
 			templatesRepo = mocks.NewTemplatesRepo()
+			templatesRepo.UpdateCall.Returns.Template = models.Template{
+				ID:      "my-awesome-id",
+				Name:    "gobble template",
+				Text:    "gobble",
+				HTML:    "<p>gobble</p>",
+				Version: 4,
+			}
+			versionsRepo = mocks.NewTemplateVersionsRepo()
 
-			updater = services.NewTemplateUpdater(templatesRepo)
+			updater = services.NewTemplateUpdater(templatesRepo, versionsRepo)
 		})
 
 		It("Inserts templates into the templates repo", func() {
@@ -34,23 +46,52 @@ var _ = Describe("Updater", func() {
 				Name: "gobble template",
 				Text: "gobble",
 				HTML: "<p>gobble</p>",
-			})
+			}, "some-client-id")
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("my-awesome-id"))
 			Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{
 				Name: "gobble template",
 				Text: "gobble",
 				HTML: "<p>gobble</p>",
 			}))
+
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("records the updated template as a new version", func() {
+			err := updater.Update(database, "my-awesome-id", models.Template{}, "some-client-id")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(versionsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(versionsRepo.CreateCall.Receives.Version).To(Equal(models.TemplateVersion{
+				TemplateID: "my-awesome-id",
+				Version:    4,
+				Name:       "gobble template",
+				Text:       "gobble",
+				HTML:       "<p>gobble</p>",
+				ClientID:   "some-client-id",
+			}))
 		})
 
 		It("propagates errors from repo", func() {
 			templatesRepo.UpdateCall.Returns.Error = errors.New("Boom!")
 
-			err := updater.Update(database, "unimportant", models.Template{})
+			err := updater.Update(database, "unimportant", models.Template{}, "some-client-id")
 			Expect(err).To(MatchError(errors.New("Boom!")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("rolls back the update when the version cannot be recorded", func() {
+			versionsRepo.CreateCall.Returns.Error = errors.New("version boom")
+
+			err := updater.Update(database, "unimportant", models.Template{}, "some-client-id")
+			Expect(err).To(MatchError(errors.New("version boom")))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
 		})
 	})
 })
@@ -1,7 +1,5 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/cf"
-
 const ScopeEndorsement = "You received this message because you have the {{.Scope}} scope."
 
 type scopeUserIDFinder interface {
@@ -25,17 +23,33 @@ func NewUAAScopeStrategy(tokenLoader loadsTokens, findsUserIDs scopeUserIDFinder
 }
 
 func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
-	responses := []Response{}
+	resolution, err := strategy.Resolve(dispatch)
+	if err != nil {
+		return []Response{}, err
+	}
+
+	return resolution.enqueue(strategy.enqueuer, dispatch)
+}
+
+func (strategy UAAScopeStrategy) Resolve(dispatch Dispatch) (Resolution, error) {
 	options := Options{
-		ReplyTo:           dispatch.Message.ReplyTo,
-		Subject:           dispatch.Message.Subject,
-		To:                dispatch.Message.To,
-		Endorsement:       ScopeEndorsement,
-		KindID:            dispatch.Kind.ID,
-		KindDescription:   dispatch.Kind.Description,
-		SourceDescription: dispatch.Client.Description,
-		Text:              dispatch.Message.Text,
-		TemplateID:        dispatch.TemplateID,
+		ReplyTo:             dispatch.Message.ReplyTo,
+		Subject:             dispatch.Message.Subject,
+		To:                  dispatch.Message.To,
+		Endorsement:         ScopeEndorsement,
+		EndorsementID:       ScopeEndorsementID,
+		KindID:              dispatch.Kind.ID,
+		KindDescription:     dispatch.Kind.Description,
+		SourceDescription:   dispatch.Client.Description,
+		Text:                dispatch.Message.Text,
+		TemplateID:          dispatch.TemplateID,
+		Locale:              dispatch.Message.Locale,
+		Data:                dispatch.Message.Data,
+		Markdown:            dispatch.Message.Markdown,
+		ExpiresAt:           dispatch.Message.ExpiresAt,
+		DedupKey:            dispatch.Message.DedupKey,
+		DedupWindow:         dispatch.Kind.DedupWindow,
+		SkipTextAlternative: dispatch.Kind.SkipTextAlternative,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -45,17 +59,17 @@ func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 	}
 
 	if strategy.scopeIsDefault(dispatch.GUID) {
-		return responses, DefaultScopeError{}
+		return Resolution{}, DefaultScopeError{}
 	}
 
 	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
 	if err != nil {
-		return responses, err
+		return Resolution{}, err
 	}
 
 	userGUIDs, err := strategy.findsUserIDs.UserIDsBelongingToScope(token, dispatch.GUID)
 	if err != nil {
-		return responses, err
+		return Resolution{}, err
 	}
 
 	var users []User
@@ -63,17 +77,11 @@ func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		users = append(users, User{GUID: guid})
 	}
 
-	return strategy.enqueuer.Enqueue(
-		dispatch.Connection,
-		users,
-		options,
-		cf.CloudControllerSpace{},
-		cf.CloudControllerOrganization{},
-		dispatch.Client.ID,
-		dispatch.UAAHost,
-		dispatch.GUID,
-		dispatch.VCAPRequest.ID,
-		dispatch.VCAPRequest.ReceiptTime)
+	return Resolution{
+		Users:   users,
+		Options: options,
+		Scope:   dispatch.GUID,
+	}, nil
 }
 
 func (strategy UAAScopeStrategy) scopeIsDefault(scope string) bool {
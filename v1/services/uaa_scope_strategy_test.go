@@ -70,10 +70,13 @@ var _ = Describe("UAA Scope Strategy", func() {
 						GUID:       "great.scope",
 						Connection: conn,
 						Message: services.DispatchMessage{
-							To:      "dr@strangelove.com",
-							ReplyTo: "reply-to@example.com",
-							Subject: "this is the subject",
-							Text:    "Please make sure to leave your bottle in a place that is safe and dry",
+							To:       "dr@strangelove.com",
+							ReplyTo:  "reply-to@example.com",
+							Subject:  "this is the subject",
+							Locale:   "de-CH",
+							Data:     map[string]interface{}{"app_name": "banana"},
+							Markdown: "# banana",
+							Text:     "Please make sure to leave your bottle in a place that is safe and dry",
 							HTML: services.HTML{
 								BodyContent:    "<p>The water bottle needs to be safe and dry</p>",
 								BodyAttributes: "some-html-body-attributes",
@@ -105,6 +108,9 @@ var _ = Describe("UAA Scope Strategy", func() {
 					Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
 						ReplyTo:           "reply-to@example.com",
 						Subject:           "this is the subject",
+						Locale:            "de-CH",
+						Data:              map[string]interface{}{"app_name": "banana"},
+						Markdown:          "# banana",
 						To:                "dr@strangelove.com",
 						KindID:            "forgot_waterbottle",
 						KindDescription:   "Water Bottle Reminder",
@@ -117,7 +123,8 @@ var _ = Describe("UAA Scope Strategy", func() {
 							Head:           "<head></head>",
 							Doctype:        "<html>",
 						},
-						Endorsement: services.ScopeEndorsement,
+						Endorsement:   services.ScopeEndorsement,
+						EndorsementID: services.ScopeEndorsementID,
 					}))
 					Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{}))
 					Expect(enqueuer.EnqueueCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{}))
@@ -1,7 +1,5 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/cf"
-
 const UserEndorsement = "This message was sent directly to you."
 
 type UserStrategy struct {
@@ -15,16 +13,33 @@ func NewUserStrategy(enqueuer enqueuer) UserStrategy {
 }
 
 func (strategy UserStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
+	resolution, err := strategy.Resolve(dispatch)
+	if err != nil {
+		return []Response{}, err
+	}
+
+	return resolution.enqueue(strategy.enqueuer, dispatch)
+}
+
+func (strategy UserStrategy) Resolve(dispatch Dispatch) (Resolution, error) {
 	options := Options{
-		ReplyTo:           dispatch.Message.ReplyTo,
-		Subject:           dispatch.Message.Subject,
-		To:                dispatch.Message.To,
-		Endorsement:       UserEndorsement,
-		KindID:            dispatch.Kind.ID,
-		KindDescription:   dispatch.Kind.Description,
-		SourceDescription: dispatch.Client.Description,
-		Text:              dispatch.Message.Text,
-		TemplateID:        dispatch.TemplateID,
+		ReplyTo:             dispatch.Message.ReplyTo,
+		Subject:             dispatch.Message.Subject,
+		To:                  dispatch.Message.To,
+		Endorsement:         UserEndorsement,
+		EndorsementID:       UserEndorsementID,
+		KindID:              dispatch.Kind.ID,
+		KindDescription:     dispatch.Kind.Description,
+		SourceDescription:   dispatch.Client.Description,
+		Text:                dispatch.Message.Text,
+		TemplateID:          dispatch.TemplateID,
+		Locale:              dispatch.Message.Locale,
+		Data:                dispatch.Message.Data,
+		Markdown:            dispatch.Message.Markdown,
+		ExpiresAt:           dispatch.Message.ExpiresAt,
+		DedupKey:            dispatch.Message.DedupKey,
+		DedupWindow:         dispatch.Kind.DedupWindow,
+		SkipTextAlternative: dispatch.Kind.SkipTextAlternative,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -35,15 +50,8 @@ func (strategy UserStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 
 	users := []User{{GUID: dispatch.GUID}}
 
-	return strategy.enqueuer.Enqueue(
-		dispatch.Connection,
-		users,
-		options,
-		cf.CloudControllerSpace{},
-		cf.CloudControllerOrganization{},
-		dispatch.Client.ID,
-		dispatch.UAAHost,
-		"",
-		dispatch.VCAPRequest.ID,
-		dispatch.VCAPRequest.ReceiptTime)
+	return Resolution{
+		Users:   users,
+		Options: options,
+	}, nil
 }
@@ -33,10 +33,13 @@ var _ = Describe("UserStrategy", func() {
 				GUID:       "user-123",
 				Connection: conn,
 				Message: services.DispatchMessage{
-					To:      "dr@strangelove.com",
-					ReplyTo: "reply-to@example.com",
-					Subject: "this is the subject",
-					Text:    "Please make sure to leave your bottle in a place that is safe and dry",
+					To:       "dr@strangelove.com",
+					ReplyTo:  "reply-to@example.com",
+					Subject:  "this is the subject",
+					Locale:   "de-CH",
+					Data:     map[string]interface{}{"app_name": "banana"},
+					Markdown: "# banana",
+					Text:     "Please make sure to leave your bottle in a place that is safe and dry",
 					HTML: services.HTML{
 						BodyContent:    "<p>The water bottle needs to be safe and dry</p>",
 						BodyAttributes: "some-html-body-attributes",
@@ -47,8 +50,9 @@ var _ = Describe("UserStrategy", func() {
 				TemplateID: "some-template-id",
 				UAAHost:    "uaa",
 				Kind: services.DispatchKind{
-					ID:          "forgot_waterbottle",
-					Description: "Water Bottle Reminder",
+					ID:                  "forgot_waterbottle",
+					Description:         "Water Bottle Reminder",
+					SkipTextAlternative: true,
 				},
 				Client: services.DispatchClient{
 					ID:          "mister-client",
@@ -66,21 +70,26 @@ var _ = Describe("UserStrategy", func() {
 			Expect(reflect.ValueOf(enqueuer.EnqueueCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(conn).Pointer()))
 			Expect(enqueuer.EnqueueCall.Receives.Users).To(Equal(users))
 			Expect(enqueuer.EnqueueCall.Receives.Options).To(Equal(services.Options{
-				ReplyTo:           "reply-to@example.com",
-				Subject:           "this is the subject",
-				To:                "dr@strangelove.com",
-				KindID:            "forgot_waterbottle",
-				KindDescription:   "Water Bottle Reminder",
-				SourceDescription: "The Water Bottle System",
-				Text:              "Please make sure to leave your bottle in a place that is safe and dry",
-				TemplateID:        "some-template-id",
+				ReplyTo:             "reply-to@example.com",
+				Subject:             "this is the subject",
+				Locale:              "de-CH",
+				Data:                map[string]interface{}{"app_name": "banana"},
+				Markdown:            "# banana",
+				To:                  "dr@strangelove.com",
+				KindID:              "forgot_waterbottle",
+				KindDescription:     "Water Bottle Reminder",
+				SourceDescription:   "The Water Bottle System",
+				Text:                "Please make sure to leave your bottle in a place that is safe and dry",
+				TemplateID:          "some-template-id",
+				SkipTextAlternative: true,
 				HTML: services.HTML{
 					BodyContent:    "<p>The water bottle needs to be safe and dry</p>",
 					BodyAttributes: "some-html-body-attributes",
 					Head:           "<head></head>",
 					Doctype:        "<html>",
 				},
-				Endorsement: services.UserEndorsement,
+				Endorsement:   services.UserEndorsement,
+				EndorsementID: services.UserEndorsementID,
 			}))
 			Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{}))
 			Expect(enqueuer.EnqueueCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{}))
@@ -0,0 +1,94 @@
+package batches
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type batchFinder interface {
+	Find(services.DatabaseInterface, string) (services.Batch, error)
+}
+
+type BatchDocument struct {
+	ID                    string     `json:"id"`
+	ClientID              string     `json:"client_id"`
+	KindID                string     `json:"kind_id"`
+	TotalMessages         int        `json:"total_messages"`
+	QueuedMessages        int        `json:"queued_messages"`
+	SentMessages          int        `json:"sent_messages"`
+	FailedMessages        int        `json:"failed_messages"`
+	UndeliverableMessages int        `json:"undeliverable_messages"`
+	ExpiredMessages       int        `json:"expired_messages"`
+	StartTime             time.Time  `json:"start_time"`
+	CompletedTime         *time.Time `json:"completed_time"`
+}
+
+func newBatchDocument(batch services.Batch) BatchDocument {
+	return BatchDocument{
+		ID:                    batch.ID,
+		ClientID:              batch.ClientID,
+		KindID:                batch.KindID,
+		TotalMessages:         batch.TotalMessages,
+		QueuedMessages:        batch.QueuedMessages,
+		SentMessages:          batch.SentMessages,
+		FailedMessages:        batch.FailedMessages,
+		UndeliverableMessages: batch.UndeliverableMessages,
+		ExpiredMessages:       batch.ExpiredMessages,
+		StartTime:             batch.StartTime,
+		CompletedTime:         batch.CompletedTime,
+	}
+}
+
+type GetHandler struct {
+	finder      batchFinder
+	errorWriter errorWriter
+}
+
+func NewGetHandler(finder batchFinder, errWriter errorWriter) GetHandler {
+	return GetHandler{
+		finder:      finder,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	batchID := strings.Split(req.URL.Path, "/batches/")[1]
+
+	batch, err := h.finder.Find(context.Get("database").(DatabaseInterface), batchID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	// Clients only see their own batches; the batches of other clients are
+	// reported as missing so that their IDs reveal nothing.
+	token := context.Get("token").(*jwt.Token)
+	if batch.ClientID != token.Claims["client_id"].(string) {
+		h.errorWriter.Write(w, models.NotFoundError{Err: fmt.Errorf("Batch with ID %q could not be found", batchID)})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newBatchDocument(batch))
+}
+
+func writeJSON(w http.ResponseWriter, status int, object interface{}) {
+	output, err := json.Marshal(object)
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(status)
+	w.Write(output)
+}
@@ -0,0 +1,131 @@
+package batches_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/batches"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler     batches.GetHandler
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		finder      *mocks.BatchFinder
+		database    *mocks.Database
+		context     stack.Context
+		startTime   time.Time
+	)
+
+	BeforeEach(func() {
+		errorWriter = mocks.NewErrorWriter()
+		finder = mocks.NewBatchFinder()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("token", &jwt.Token{Claims: map[string]interface{}{"client_id": "some-client"}})
+		startTime = time.Date(2015, time.June, 8, 14, 32, 11, 0, time.UTC)
+
+		var err error
+		request, err = http.NewRequest("GET", "/batches/some-batch-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = batches.NewGetHandler(finder, errorWriter)
+	})
+
+	It("reports the progress of a batch that is still sending", func() {
+		finder.FindCall.Returns.Batch = services.Batch{
+			ID:             "some-batch-id",
+			ClientID:       "some-client",
+			KindID:         "some-kind",
+			TotalMessages:  3,
+			QueuedMessages: 2,
+			SentMessages:   1,
+			StartTime:      startTime,
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-batch-id",
+			"client_id": "some-client",
+			"kind_id": "some-kind",
+			"total_messages": 3,
+			"queued_messages": 2,
+			"sent_messages": 1,
+			"failed_messages": 0,
+			"undeliverable_messages": 0,
+			"expired_messages": 0,
+			"start_time": "2015-06-08T14:32:11Z",
+			"completed_time": null
+		}`))
+
+		Expect(finder.FindCall.Receives.Database).To(Equal(database))
+		Expect(finder.FindCall.Receives.BatchID).To(Equal("some-batch-id"))
+	})
+
+	It("includes the time a completed batch finished", func() {
+		completedTime := startTime.Add(time.Minute)
+		finder.FindCall.Returns.Batch = services.Batch{
+			ID:                    "some-batch-id",
+			ClientID:              "some-client",
+			TotalMessages:         3,
+			SentMessages:          1,
+			FailedMessages:        1,
+			UndeliverableMessages: 1,
+			StartTime:             startTime,
+			CompletedTime:         &completedTime,
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-batch-id",
+			"client_id": "some-client",
+			"kind_id": "",
+			"total_messages": 3,
+			"queued_messages": 0,
+			"sent_messages": 1,
+			"failed_messages": 1,
+			"undeliverable_messages": 1,
+			"expired_messages": 0,
+			"start_time": "2015-06-08T14:32:11Z",
+			"completed_time": "2015-06-08T14:33:11Z"
+		}`))
+	})
+
+	It("reports the batches of other clients as missing", func() {
+		finder.FindCall.Returns.Batch = services.Batch{
+			ID:       "some-batch-id",
+			ClientID: "other-client",
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New(`Batch with ID "some-batch-id" could not be found`)}))
+		Expect(writer.Body.Len()).To(BeZero())
+	})
+
+	It("delegates errors to the error writer", func() {
+		finder.FindCall.Returns.Error = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError("BOOM!"))
+	})
+})
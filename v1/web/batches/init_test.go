@@ -0,0 +1,13 @@
+package batches_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestWebV1BatchesSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "v1/web/batches")
+}
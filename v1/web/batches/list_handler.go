@@ -0,0 +1,56 @@
+package batches
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type batchLister interface {
+	List(services.DatabaseInterface, models.BatchesFilter, models.Page) ([]services.Batch, []string, error)
+}
+
+type ListHandler struct {
+	lister      batchLister
+	errorWriter errorWriter
+}
+
+func NewListHandler(lister batchLister, errWriter errorWriter) ListHandler {
+	return ListHandler{
+		lister:      lister,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	query := req.URL.Query()
+
+	page, err := webutil.NewPage(query)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	filter := models.BatchesFilter{
+		ClientID: query.Get("client_id"),
+	}
+
+	batches, next, err := h.lister.List(context.Get("database").(DatabaseInterface), filter, page)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := map[string][]BatchDocument{
+		"batches": {},
+	}
+	for _, batch := range batches {
+		document["batches"] = append(document["batches"], newBatchDocument(batch))
+	}
+
+	webutil.WriteNextPageLink(w, req, page, next)
+	writeJSON(w, http.StatusOK, document)
+}
@@ -0,0 +1,122 @@
+package batches_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/batches"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler     batches.ListHandler
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		finder      *mocks.BatchFinder
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		errorWriter = mocks.NewErrorWriter()
+		finder = mocks.NewBatchFinder()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = batches.NewListHandler(finder, errorWriter)
+	})
+
+	It("lists the batches of the client it is filtered by", func() {
+		finder.ListCall.Returns.Batches = []services.Batch{
+			{
+				ID:             "some-batch-id",
+				ClientID:       "some-client",
+				KindID:         "some-kind",
+				TotalMessages:  1,
+				QueuedMessages: 1,
+				StartTime:      time.Date(2015, time.June, 8, 14, 32, 11, 0, time.UTC),
+			},
+		}
+
+		request, err := http.NewRequest("GET", "/batches?client_id=some-client", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"batches": [{
+				"id": "some-batch-id",
+				"client_id": "some-client",
+				"kind_id": "some-kind",
+				"total_messages": 1,
+				"queued_messages": 1,
+				"sent_messages": 0,
+				"failed_messages": 0,
+				"undeliverable_messages": 0,
+				"expired_messages": 0,
+				"start_time": "2015-06-08T14:32:11Z",
+				"completed_time": null
+			}]
+		}`))
+
+		Expect(finder.ListCall.Receives.Database).To(Equal(database))
+		Expect(finder.ListCall.Receives.Filter).To(Equal(models.BatchesFilter{ClientID: "some-client"}))
+		Expect(finder.ListCall.Receives.Page).To(Equal(models.Page{Limit: webutil.DefaultPageLimit}))
+	})
+
+	It("writes an empty list when there are no batches", func() {
+		request, err := http.NewRequest("GET", "/batches", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Body.String()).To(MatchJSON(`{"batches": []}`))
+	})
+
+	It("links to the next page when more batches follow", func() {
+		finder.ListCall.Returns.Batches = []services.Batch{{ID: "some-batch-id"}}
+		finder.ListCall.Returns.Next = []string{"2015-06-08 14:32:11", "some-batch-id"}
+
+		request, err := http.NewRequest("GET", "/batches?limit=1&client_id=some-client", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(finder.ListCall.Receives.Page).To(Equal(models.Page{Limit: 1}))
+		Expect(writer.Header().Get("Link")).To(ContainSubstring("client_id=some-client"))
+		Expect(writer.Header().Get("Link")).To(ContainSubstring(`rel="next"`))
+	})
+
+	It("writes validation errors for a bad page", func() {
+		request, err := http.NewRequest("GET", "/batches?limit=0", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+	})
+
+	It("delegates errors to the error writer", func() {
+		finder.ListCall.Returns.Error = errors.New("BOOM!")
+
+		request, err := http.NewRequest("GET", "/batches", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError("BOOM!"))
+	})
+})
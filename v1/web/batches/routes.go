@@ -0,0 +1,28 @@
+package batches
+
+import "github.com/ryanmoran/stack"
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type finder interface {
+	batchFinder
+	batchLister
+}
+
+type Routes struct {
+	RequestCounter                               stack.Middleware
+	RequestLogging                               stack.Middleware
+	NotificationsWriteOrEmailsWriteAuthenticator stack.Middleware
+	NotificationsManageAuthenticator             stack.Middleware
+	DatabaseAllocator                            stack.Middleware
+
+	BatchFinder finder
+	ErrorWriter errorWriter
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/batches", NewListHandler(r.BatchFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/batches/{batch_id}", NewGetHandler(r.BatchFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteAuthenticator, r.DatabaseAllocator)
+}
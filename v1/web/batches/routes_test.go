@@ -0,0 +1,57 @@
+package batches_test
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/batches"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/web"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Routes", func() {
+	var muxer web.Muxer
+
+	BeforeEach(func() {
+		muxer = web.NewMuxer()
+		batches.Routes{
+			RequestCounter:    middleware.RequestCounter{},
+			RequestLogging:    middleware.RequestLogging{},
+			DatabaseAllocator: middleware.DatabaseAllocator{},
+			NotificationsWriteOrEmailsWriteAuthenticator: middleware.Authenticator{Scopes: []string{"notifications.write", "emails.write"}},
+			NotificationsManageAuthenticator:             middleware.Authenticator{Scopes: []string{"notifications.manage"}},
+
+			ErrorWriter: mocks.NewErrorWriter(),
+			BatchFinder: mocks.NewBatchFinder(),
+		}.Register(muxer)
+	})
+
+	It("routes GET /batches", func() {
+		request, err := http.NewRequest("GET", "/batches", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(batches.ListHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.manage"}))
+	})
+
+	It("routes GET /batches/{batch_id}", func() {
+		request, err := http.NewRequest("GET", "/batches/some-batch-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(batches.GetHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
+	})
+})
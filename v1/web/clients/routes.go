@@ -14,8 +14,11 @@ type Routes struct {
 
 	ErrorWriter      errorWriter
 	TemplateAssigner assignsTemplates
+	SenderAssigner   assignsSenderIdentities
 }
 
 func (r Routes) Register(m muxer) {
 	m.Handle("PUT", "/clients/{client_id}/template", NewAssignTemplateHandler(r.TemplateAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/clients/{client_id}/sender", NewAssignSenderHandler(r.SenderAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/clients/{client_id}/sender", NewUnassignSenderHandler(r.SenderAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
 }
@@ -27,6 +27,7 @@ var _ = Describe("Routes", func() {
 
 			ErrorWriter:      mocks.NewErrorWriter(),
 			TemplateAssigner: mocks.NewTemplateAssigner(),
+			SenderAssigner:   mocks.NewSenderIdentityAssigner(),
 		}.Register(muxer)
 	})
 
@@ -41,4 +42,28 @@ var _ = Describe("Routes", func() {
 		authenticator := s.Middleware[2].(middleware.Authenticator)
 		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
 	})
+
+	It("routes PUT /clients/{client_id}/sender", func() {
+		request, err := http.NewRequest("PUT", "/clients/some-client-id/sender", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(clients.AssignSenderHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
+
+	It("routes DELETE /clients/{client_id}/sender", func() {
+		request, err := http.NewRequest("DELETE", "/clients/some-client-id/sender", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(clients.UnassignSenderHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
 })
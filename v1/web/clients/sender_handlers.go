@@ -0,0 +1,85 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type assignsSenderIdentities interface {
+	AssignToClient(connection collections.ConnectionInterface, clientID string, identity collections.SenderIdentity) error
+	UnassignFromClient(connection collections.ConnectionInterface, clientID string) error
+}
+
+type SenderIdentityAssignment struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	ReplyTo string `json:"reply_to"`
+}
+
+type AssignSenderHandler struct {
+	senderAssigner assignsSenderIdentities
+	errorWriter    errorWriter
+}
+
+func NewAssignSenderHandler(assigner assignsSenderIdentities, errWriter errorWriter) AssignSenderHandler {
+	return AssignSenderHandler{
+		senderAssigner: assigner,
+		errorWriter:    errWriter,
+	}
+}
+
+func (h AssignSenderHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	clientID := senderClientID(req)
+
+	var assignment SenderIdentityAssignment
+	err := json.NewDecoder(req.Body).Decode(&assignment)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	err = h.senderAssigner.AssignToClient(database.Connection(), clientID, collections.SenderIdentity{
+		Name:    assignment.Name,
+		Address: assignment.Address,
+		ReplyTo: assignment.ReplyTo,
+	})
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type UnassignSenderHandler struct {
+	senderAssigner assignsSenderIdentities
+	errorWriter    errorWriter
+}
+
+func NewUnassignSenderHandler(assigner assignsSenderIdentities, errWriter errorWriter) UnassignSenderHandler {
+	return UnassignSenderHandler{
+		senderAssigner: assigner,
+		errorWriter:    errWriter,
+	}
+}
+
+func (h UnassignSenderHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	database := context.Get("database").(DatabaseInterface)
+	err := h.senderAssigner.UnassignFromClient(database.Connection(), senderClientID(req))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func senderClientID(req *http.Request) string {
+	return regexp.MustCompile("/clients/(.*)/sender").FindStringSubmatch(req.URL.Path)[1]
+}
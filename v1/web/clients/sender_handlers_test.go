@@ -0,0 +1,115 @@
+package clients_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/clients"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sender handlers", func() {
+	var (
+		senderAssigner *mocks.SenderIdentityAssigner
+		errorWriter    *mocks.ErrorWriter
+		context        stack.Context
+		database       *mocks.Database
+		connection     *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		senderAssigner = mocks.NewSenderIdentityAssigner()
+		errorWriter = mocks.NewErrorWriter()
+		connection = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+		context = stack.NewContext()
+		context.Set("database", database)
+	})
+
+	Describe("AssignSenderHandler", func() {
+		var handler clients.AssignSenderHandler
+
+		BeforeEach(func() {
+			handler = clients.NewAssignSenderHandler(senderAssigner, errorWriter)
+		})
+
+		It("associates a sender identity with a client", func() {
+			body := []byte(`{"name": "Autoscaler", "address": "autoscaler@example.com", "reply_to": "support@example.com"}`)
+
+			w := httptest.NewRecorder()
+			request, err := http.NewRequest("PUT", "/clients/my-client/sender", bytes.NewBuffer(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(w, request, context)
+
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+			Expect(senderAssigner.AssignToClientCall.Receives.Connection).To(Equal(connection))
+			Expect(senderAssigner.AssignToClientCall.Receives.ClientID).To(Equal("my-client"))
+			Expect(senderAssigner.AssignToClientCall.Receives.SenderIdentity).To(Equal(collections.SenderIdentity{
+				Name:    "Autoscaler",
+				Address: "autoscaler@example.com",
+				ReplyTo: "support@example.com",
+			}))
+		})
+
+		It("delegates to the error writer when the assigner errors", func() {
+			senderAssigner.AssignToClientCall.Returns.Error = errors.New("banana")
+
+			w := httptest.NewRecorder()
+			request, err := http.NewRequest("PUT", "/clients/my-client/sender", bytes.NewBuffer([]byte(`{"address": "me@example.com"}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(w, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("banana")))
+		})
+
+		It("writes a ParseError to the error writer when request body is invalid", func() {
+			w := httptest.NewRecorder()
+			request, err := http.NewRequest("PUT", "/clients/my-client/sender", bytes.NewBuffer([]byte(`{ "this is" : not-valid-json }`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(w, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
+		})
+	})
+
+	Describe("UnassignSenderHandler", func() {
+		var handler clients.UnassignSenderHandler
+
+		BeforeEach(func() {
+			handler = clients.NewUnassignSenderHandler(senderAssigner, errorWriter)
+		})
+
+		It("removes the sender identity from a client", func() {
+			w := httptest.NewRecorder()
+			request, err := http.NewRequest("DELETE", "/clients/my-client/sender", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(w, request, context)
+
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+			Expect(senderAssigner.UnassignFromClientCall.Receives.Connection).To(Equal(connection))
+			Expect(senderAssigner.UnassignFromClientCall.Receives.ClientID).To(Equal("my-client"))
+		})
+
+		It("delegates to the error writer when the assigner errors", func() {
+			senderAssigner.UnassignFromClientCall.Returns.Error = errors.New("banana")
+
+			w := httptest.NewRecorder()
+			request, err := http.NewRequest("DELETE", "/clients/my-client/sender", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(w, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("banana")))
+		})
+	})
+})
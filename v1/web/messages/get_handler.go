@@ -2,13 +2,53 @@ package messages
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 )
 
+type MessageDocument struct {
+	ID              string     `json:"id,omitempty"`
+	Status          string     `json:"status"`
+	Error           string     `json:"error,omitempty"`
+	TemplateID      string     `json:"template_id,omitempty"`
+	TemplateVersion int        `json:"template_version,omitempty"`
+	UserGUID        string     `json:"user_guid,omitempty"`
+	Email           string     `json:"email,omitempty"`
+	ClientID        string     `json:"client_id,omitempty"`
+	KindID          string     `json:"kind_id,omitempty"`
+	VCAPRequestID   string     `json:"vcap_request_id,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+}
+
+func newMessageDocument(message services.Message) MessageDocument {
+	document := MessageDocument{
+		ID:              message.ID,
+		Status:          message.Status,
+		Error:           message.Error,
+		TemplateID:      message.TemplateID,
+		TemplateVersion: message.TemplateVersion,
+		UserGUID:        message.UserGUID,
+		Email:           message.Email,
+		ClientID:        message.ClientID,
+		KindID:          message.KindID,
+		VCAPRequestID:   message.VCAPRequestID,
+	}
+
+	if !message.CreatedAt.IsZero() {
+		createdAt := message.CreatedAt
+		document.CreatedAt = &createdAt
+	}
+
+	return document
+}
+
 type GetHandler struct {
 	finder      messageFinder
 	errorWriter errorWriter
@@ -38,12 +78,17 @@ func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 		return
 	}
 
-	var document struct {
-		Status string `json:"status"`
+	// Clients only see their own messages; the messages of other clients
+	// are reported as missing so that their IDs reveal nothing. Messages
+	// recorded before their client was stored have no client ID and stay
+	// visible to every client, as they were then.
+	token := context.Get("token").(*jwt.Token)
+	if message.ClientID != "" && message.ClientID != token.Claims["client_id"].(string) {
+		h.errorWriter.Write(w, models.NotFoundError{Err: fmt.Errorf("Message with ID %q could not be found", messageID)})
+		return
 	}
-	document.Status = message.Status
 
-	writeJSON(w, http.StatusOK, document)
+	writeJSON(w, http.StatusOK, newMessageDocument(message))
 }
 
 func writeJSON(w http.ResponseWriter, status int, object interface{}) {
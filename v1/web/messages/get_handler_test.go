@@ -4,10 +4,13 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -35,6 +38,7 @@ var _ = Describe("GetHandler", func() {
 		database = mocks.NewDatabase()
 		context = stack.NewContext()
 		context.Set("database", database)
+		context.Set("token", &jwt.Token{Claims: map[string]interface{}{"client_id": "some-client"}})
 
 		request, err = http.NewRequest("GET", "/messages/"+messageID, nil)
 		if err != nil {
@@ -47,20 +51,112 @@ var _ = Describe("GetHandler", func() {
 	Describe("ServeHTTP", func() {
 		It("Returns the status of the given message from the finder", func() {
 			messageFinder.FindCall.Returns.Message = services.Message{
-				Status: "The generic status returned",
+				Status:   "The generic status returned",
+				ClientID: "some-client",
 			}
 
 			handler.ServeHTTP(writer, request, context)
 
 			Expect(writer.Code).To(Equal(http.StatusOK))
 			Expect(writer.Body.Bytes()).To(MatchJSON(`{
-				"status": "The generic status returned"
+				"status": "The generic status returned",
+				"client_id": "some-client"
 			}`))
 
 			Expect(messageFinder.FindCall.Receives.Database).To(Equal(database))
 			Expect(messageFinder.FindCall.Receives.MessageID).To(Equal(messageID))
 		})
 
+		It("includes the template version that rendered the message", func() {
+			messageFinder.FindCall.Returns.Message = services.Message{
+				Status:          "delivered",
+				TemplateID:      "some-template-id",
+				TemplateVersion: 3,
+				ClientID:        "some-client",
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.Bytes()).To(MatchJSON(`{
+				"status": "delivered",
+				"template_id": "some-template-id",
+				"template_version": 3,
+				"client_id": "some-client"
+			}`))
+		})
+
+		It("includes what the message was sent to and by", func() {
+			messageFinder.FindCall.Returns.Message = services.Message{
+				ID:            "message-123",
+				Status:        "delivered",
+				UserGUID:      "some-user",
+				Email:         "user@example.com",
+				ClientID:      "some-client",
+				KindID:        "some-kind",
+				VCAPRequestID: "some-request-id",
+				CreatedAt:     time.Date(2015, time.June, 8, 14, 32, 11, 0, time.UTC),
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.Bytes()).To(MatchJSON(`{
+				"id": "message-123",
+				"status": "delivered",
+				"user_guid": "some-user",
+				"email": "user@example.com",
+				"client_id": "some-client",
+				"kind_id": "some-kind",
+				"vcap_request_id": "some-request-id",
+				"created_at": "2015-06-08T14:32:11Z"
+			}`))
+		})
+
+		It("includes the error recorded for a failed message", func() {
+			messageFinder.FindCall.Returns.Message = services.Message{
+				Status:   "failed",
+				Error:    "template: compileTemplate:1:2: executing failed",
+				ClientID: "some-client",
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.Bytes()).To(MatchJSON(`{
+				"status": "failed",
+				"error": "template: compileTemplate:1:2: executing failed",
+				"client_id": "some-client"
+			}`))
+		})
+
+		It("reports the messages of other clients as missing", func() {
+			messageFinder.FindCall.Returns.Message = services.Message{
+				ID:       "message-123",
+				Status:   "delivered",
+				UserGUID: "some-user",
+				ClientID: "other-client",
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New(`Message with ID "message-123" could not be found`)}))
+			Expect(writer.Body.Len()).To(BeZero())
+		})
+
+		It("shows messages recorded without a client to every client", func() {
+			messageFinder.FindCall.Returns.Message = services.Message{
+				ID:     "message-123",
+				Status: "delivered",
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeNil())
+			Expect(writer.Body.Bytes()).To(MatchJSON(`{"id": "message-123", "status": "delivered"}`))
+		})
+
 		Context("When the finder errors", func() {
 			It("Delegates to the error writer", func() {
 				findError := errors.New("The finder returns a generic error")
@@ -0,0 +1,116 @@
+package messages
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/address"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type messageLister interface {
+	List(services.DatabaseInterface, models.MessagesFilter, models.Page) ([]services.Message, []string, error)
+}
+
+type ListHandler struct {
+	lister      messageLister
+	errorWriter errorWriter
+}
+
+func NewListHandler(lister messageLister, errWriter errorWriter) ListHandler {
+	return ListHandler{
+		lister:      lister,
+		errorWriter: errWriter,
+	}
+}
+
+// ServeHTTP lists the messages sent by the client the token was issued to,
+// narrowed by the filters given in the query.
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	query := req.URL.Query()
+
+	page, err := webutil.NewPage(query)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	token := context.Get("token").(*jwt.Token)
+	filter := models.MessagesFilter{
+		ClientID:      token.Claims["client_id"].(string),
+		UserGUID:      query.Get("user_guid"),
+		KindID:        query.Get("kind_id"),
+		VCAPRequestID: query.Get("vcap_request_id"),
+		Status:        query.Get("status"),
+	}
+
+	filter.Email, err = parseEmail(query)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	filter.CreatedSince, err = parseTime(query, "created_since")
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	filter.CreatedBefore, err = parseTime(query, "created_before")
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	messages, next, err := h.lister.List(context.Get("database").(DatabaseInterface), filter, page)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := map[string][]MessageDocument{
+		"messages": {},
+	}
+	for _, message := range messages {
+		document["messages"] = append(document["messages"], newMessageDocument(message))
+	}
+
+	webutil.WriteNextPageLink(w, req, page, next)
+	writeJSON(w, http.StatusOK, document)
+}
+
+// parseEmail normalizes the email address to filter by in the same way the
+// addresses of messages are normalized when they are stored.
+func parseEmail(query url.Values) (string, error) {
+	value := query.Get("email")
+	if value == "" {
+		return "", nil
+	}
+
+	email, err := address.Parse(value)
+	if err != nil {
+		return "", webutil.ValidationError{Err: fmt.Errorf(`"email" is improperly formatted: %s`, err)}
+	}
+
+	return email.Email(), nil
+}
+
+func parseTime(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, webutil.ValidationError{Err: fmt.Errorf("%q must be an RFC 3339 timestamp", name)}
+	}
+
+	return t, nil
+}
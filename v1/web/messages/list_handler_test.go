@@ -0,0 +1,171 @@
+package messages_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler     messages.ListHandler
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		finder      *mocks.MessageFinder
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		errorWriter = mocks.NewErrorWriter()
+		finder = mocks.NewMessageFinder()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("token", &jwt.Token{Claims: map[string]interface{}{"client_id": "some-client"}})
+
+		handler = messages.NewListHandler(finder, errorWriter)
+	})
+
+	It("lists the messages of the client of the token", func() {
+		finder.ListCall.Returns.Messages = []services.Message{
+			{
+				ID:            "some-message-id",
+				Status:        "delivered",
+				UserGUID:      "some-user",
+				Email:         "user@example.com",
+				ClientID:      "some-client",
+				KindID:        "some-kind",
+				VCAPRequestID: "some-request-id",
+				CreatedAt:     time.Date(2015, time.June, 8, 14, 32, 11, 0, time.UTC),
+			},
+		}
+
+		request, err := http.NewRequest("GET", "/messages?client_id=other-client", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"messages": [{
+				"id": "some-message-id",
+				"status": "delivered",
+				"user_guid": "some-user",
+				"email": "user@example.com",
+				"client_id": "some-client",
+				"kind_id": "some-kind",
+				"vcap_request_id": "some-request-id",
+				"created_at": "2015-06-08T14:32:11Z"
+			}]
+		}`))
+
+		Expect(finder.ListCall.Receives.Database).To(Equal(database))
+		Expect(finder.ListCall.Receives.Filter).To(Equal(models.MessagesFilter{ClientID: "some-client"}))
+		Expect(finder.ListCall.Receives.Page).To(Equal(models.Page{Limit: webutil.DefaultPageLimit}))
+	})
+
+	It("filters the messages by the query", func() {
+		request, err := http.NewRequest("GET", "/messages?user_guid=some-user&email=user@example.com&kind_id=some-kind&vcap_request_id=some-request-id&status=failed&created_since=2015-06-08T00:00:00Z&created_before=2015-06-09T02:00:00%2B02:00", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+
+		filter := finder.ListCall.Receives.Filter
+		Expect(filter.ClientID).To(Equal("some-client"))
+		Expect(filter.UserGUID).To(Equal("some-user"))
+		Expect(filter.Email).To(Equal("user@example.com"))
+		Expect(filter.KindID).To(Equal("some-kind"))
+		Expect(filter.VCAPRequestID).To(Equal("some-request-id"))
+		Expect(filter.Status).To(Equal("failed"))
+		Expect(filter.CreatedSince).To(BeTemporally("==", time.Date(2015, time.June, 8, 0, 0, 0, 0, time.UTC)))
+		Expect(filter.CreatedBefore).To(BeTemporally("==", time.Date(2015, time.June, 9, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("normalizes the email address to filter by", func() {
+		request, err := http.NewRequest("GET", "/messages?email=User@EXAMPLE.com", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(finder.ListCall.Receives.Filter.Email).To(Equal("User@example.com"))
+	})
+
+	It("writes validation errors for a bad email address", func() {
+		request, err := http.NewRequest("GET", "/messages?email=not-an-address", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+		Expect(finder.ListCall.Receives.Filter).To(Equal(models.MessagesFilter{}))
+	})
+
+	It("writes an empty list when there are no messages", func() {
+		request, err := http.NewRequest("GET", "/messages", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Body.String()).To(MatchJSON(`{"messages": []}`))
+	})
+
+	It("links to the next page when more messages follow", func() {
+		finder.ListCall.Returns.Messages = []services.Message{{ID: "some-message-id"}}
+		finder.ListCall.Returns.Next = []string{"2015-06-08 14:32:11", "some-message-id"}
+
+		request, err := http.NewRequest("GET", "/messages?limit=1&kind_id=some-kind", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(finder.ListCall.Receives.Page).To(Equal(models.Page{Limit: 1}))
+		Expect(writer.Header().Get("Link")).To(ContainSubstring("kind_id=some-kind"))
+		Expect(writer.Header().Get("Link")).To(ContainSubstring(`rel="next"`))
+	})
+
+	It("writes validation errors for a bad page", func() {
+		request, err := http.NewRequest("GET", "/messages?limit=0", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+	})
+
+	It("writes validation errors for a bad time", func() {
+		request, err := http.NewRequest("GET", "/messages?created_since=yesterday", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"created_since" must be an RFC 3339 timestamp`)}))
+	})
+
+	It("delegates errors to the error writer", func() {
+		finder.ListCall.Returns.Error = errors.New("BOOM!")
+
+		request, err := http.NewRequest("GET", "/messages", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError("BOOM!"))
+	})
+})
@@ -6,16 +6,22 @@ type muxer interface {
 	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
 }
 
+type finder interface {
+	messageFinder
+	messageLister
+}
+
 type Routes struct {
 	RequestCounter                               stack.Middleware
 	RequestLogging                               stack.Middleware
 	NotificationsWriteOrEmailsWriteAuthenticator stack.Middleware
 	DatabaseAllocator                            stack.Middleware
 
-	MessageFinder messageFinder
+	MessageFinder finder
 	ErrorWriter   errorWriter
 }
 
 func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/messages", NewListHandler(r.MessageFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/messages/{message_id}", NewGetHandler(r.MessageFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteAuthenticator, r.DatabaseAllocator)
 }
@@ -30,6 +30,18 @@ var _ = Describe("Routes", func() {
 		}.Register(muxer)
 	})
 
+	It("routes GET /messages", func() {
+		request, err := http.NewRequest("GET", "/messages", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(messages.ListHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
+	})
+
 	It("routes GET /messages/{message_id}", func() {
 		request, err := http.NewRequest("GET", "/messages/some-message-id", nil)
 		Expect(err).NotTo(HaveOccurred())
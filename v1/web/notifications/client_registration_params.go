@@ -8,18 +8,25 @@ import (
 	"io"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 )
 
 type ClientRegistrationParams struct {
 	SourceName    string                           `json:"source_name"`
+	Sender        *SenderIdentityAssignment        `json:"sender"`
 	Notifications map[string](*NotificationStruct) `json:"notifications"`
 }
 
 type NotificationStruct struct {
 	ID          string
-	Description string `json:"description"`
-	Critical    bool   `json:"critical"`
+	Description string                    `json:"description"`
+	Critical    bool                      `json:"critical"`
+	Sender      *SenderIdentityAssignment `json:"sender"`
+
+	SkipTextAlternative bool `json:"skip_text_alternative"`
+	TTL                 int  `json:"ttl"`
+	DedupWindow         int  `json:"dedup_window"`
 }
 
 func NewClientRegistrationParams(body io.Reader) (ClientRegistrationParams, error) {
@@ -56,6 +63,11 @@ func strictValidateJSON(bytes []byte) error {
 	for key := range untypedClientRegistration {
 		if key == "source_name" {
 			continue
+		} else if key == "sender" {
+			err = strictValidateSender(untypedClientRegistration[key])
+			if err != nil {
+				return err
+			}
 		} else if key == "notifications" {
 			if untypedClientRegistration[key] == nil {
 				return webutil.SchemaError{Err: errors.New("only include \"notifications\" key when adding a notification")}
@@ -67,8 +79,13 @@ func strictValidateJSON(bytes []byte) error {
 				}
 				notificationMap := notificationData.(map[string]interface{})
 				for propertyName := range notificationMap {
-					if propertyName == "description" || propertyName == "critical" {
+					if propertyName == "description" || propertyName == "critical" || propertyName == "skip_text_alternative" || propertyName == "ttl" || propertyName == "dedup_window" {
 						continue
+					} else if propertyName == "sender" {
+						err = strictValidateSender(notificationMap[propertyName])
+						if err != nil {
+							return err
+						}
 					} else {
 						return webutil.SchemaError{Err: fmt.Errorf("%q is not a valid property", propertyName)}
 					}
@@ -81,6 +98,22 @@ func strictValidateJSON(bytes []byte) error {
 	return nil
 }
 
+func strictValidateSender(sender interface{}) error {
+	senderMap, ok := sender.(map[string]interface{})
+	if !ok {
+		return webutil.SchemaError{Err: errors.New("sender must be an object")}
+	}
+
+	for propertyName := range senderMap {
+		if propertyName == "name" || propertyName == "address" || propertyName == "reply_to" {
+			continue
+		}
+		return webutil.SchemaError{Err: fmt.Errorf("%q is not a valid sender property", propertyName)}
+	}
+
+	return nil
+}
+
 func (clientRegistration ClientRegistrationParams) Validate() error {
 	var errs []string
 	if clientRegistration.SourceName == "" {
@@ -97,6 +130,15 @@ func (clientRegistration ClientRegistrationParams) Validate() error {
 		if value.Description == "" {
 			errs = append(errs, fmt.Sprintf(`notification "%+v" is missing required field "Description"`, id))
 		}
+		if value.TTL < 0 {
+			errs = append(errs, fmt.Sprintf(`notification "%+v" must not have a negative "ttl"`, id))
+		}
+		if value.TTL > models.MaxTTL {
+			errs = append(errs, fmt.Sprintf(`notification "%+v" must not have a "ttl" of more than %d seconds`, id, models.MaxTTL))
+		}
+		if value.DedupWindow < 0 {
+			errs = append(errs, fmt.Sprintf(`notification "%+v" must not have a negative "dedup_window"`, id))
+		}
 	}
 
 	if len(errs) > 0 {
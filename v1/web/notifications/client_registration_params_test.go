@@ -24,7 +24,10 @@ var _ = Describe("ClientRegistrationParams", func() {
 						"critical":    true,
 					},
 					"feeding_time": map[string]interface{}{
-						"description": "Feeding Time",
+						"description":           "Feeding Time",
+						"skip_text_alternative": true,
+						"ttl":                   300,
+						"dedup_window":          60,
 					},
 				},
 			})
@@ -41,9 +44,34 @@ var _ = Describe("ClientRegistrationParams", func() {
 				Critical:    true,
 			}))
 			Expect(parameters.Notifications).To(ContainElement(&notifications.NotificationStruct{
-				ID:          "feeding_time",
-				Description: "Feeding Time",
-				Critical:    false,
+				ID:                  "feeding_time",
+				Description:         "Feeding Time",
+				Critical:            false,
+				SkipTextAlternative: true,
+				TTL:                 300,
+				DedupWindow:         60,
+			}))
+		})
+
+		It("constructs sender identities from a reader", func() {
+			someJson := `{
+				"source_name": "Autoscaler",
+				"sender": {"name": "Autoscaler", "address": "autoscaler@example.com", "reply_to": "support@example.com"},
+				"notifications": {
+					"scaled": {"description": "Scaled", "sender": {"address": "scaling@example.com"}}
+				}
+			}`
+
+			parameters, err := notifications.NewClientRegistrationParams(strings.NewReader(someJson))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parameters.Sender).To(Equal(&notifications.SenderIdentityAssignment{
+				Name:    "Autoscaler",
+				Address: "autoscaler@example.com",
+				ReplyTo: "support@example.com",
+			}))
+			Expect(parameters.Notifications["scaled"].Sender).To(Equal(&notifications.SenderIdentityAssignment{
+				Address: "scaling@example.com",
 			}))
 		})
 
@@ -72,6 +100,13 @@ var _ = Describe("ClientRegistrationParams", func() {
 					_, err := notifications.NewClientRegistrationParams(strings.NewReader(someJson))
 					Expect(err).To(MatchError(webutil.SchemaError{Err: errors.New("\"invalid_property\" is not a valid property")}))
 				})
+
+				It("returns an error for invalid sender keys", func() {
+					someJson := `{ "source_name" : "Raptor", "sender": { "address": "raptor@example.com", "signature": "rawr" } }`
+
+					_, err := notifications.NewClientRegistrationParams(strings.NewReader(someJson))
+					Expect(err).To(MatchError(webutil.SchemaError{Err: errors.New("\"signature\" is not a valid sender property")}))
+				})
 			})
 
 			Context("when the JSON contains null values", func() {
@@ -118,5 +153,47 @@ var _ = Describe("ClientRegistrationParams", func() {
 				Err: errors.New("notification \"perimeter_breach\" is missing required field \"ID\", notification \"perimeter_breach\" is missing required field \"Description\""),
 			}))
 		})
+
+		It("returns an error if a notification has a negative ttl", func() {
+			cr := notifications.ClientRegistrationParams{
+				SourceName: "jurassic_park",
+				Notifications: map[string](*notifications.NotificationStruct){
+					"perimeter_breach": {ID: "perimeter_breach", Description: "Perimeter Breach", TTL: -1},
+				},
+			}
+
+			err := cr.Validate()
+			Expect(err).To(MatchError(webutil.ValidationError{
+				Err: errors.New(`notification "perimeter_breach" must not have a negative "ttl"`),
+			}))
+		})
+
+		It("returns an error if a notification has a ttl of more than a year", func() {
+			cr := notifications.ClientRegistrationParams{
+				SourceName: "jurassic_park",
+				Notifications: map[string](*notifications.NotificationStruct){
+					"perimeter_breach": {ID: "perimeter_breach", Description: "Perimeter Breach", TTL: 9300000000},
+				},
+			}
+
+			err := cr.Validate()
+			Expect(err).To(MatchError(webutil.ValidationError{
+				Err: errors.New(`notification "perimeter_breach" must not have a "ttl" of more than 31536000 seconds`),
+			}))
+		})
+
+		It("returns an error if a notification has a negative dedup window", func() {
+			cr := notifications.ClientRegistrationParams{
+				SourceName: "jurassic_park",
+				Notifications: map[string](*notifications.NotificationStruct){
+					"perimeter_breach": {ID: "perimeter_breach", Description: "Perimeter Breach", DedupWindow: -1},
+				},
+			}
+
+			err := cr.Validate()
+			Expect(err).To(MatchError(webutil.ValidationError{
+				Err: errors.New(`notification "perimeter_breach" must not have a negative "dedup_window"`),
+			}))
+		})
 	})
 })
@@ -2,10 +2,14 @@ package notifications
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/ryanmoran/stack"
 )
 
@@ -17,22 +21,26 @@ type Client struct {
 	Notifications map[string]Notification `json:"notifications"`
 }
 
-type listsAllClientsAndNotifications interface {
-	AllClientsAndNotifications(services.DatabaseInterface) ([]models.Client, []models.Kind, error)
+type listsClientsAndNotifications interface {
+	ListClientsAndNotifications(database services.DatabaseInterface, filter models.KindsFilter, page models.Page) (clients []models.Client, kinds []models.Kind, next []string, err error)
 }
 
 type Notification struct {
 	Description string `json:"description"`
 	Template    string `json:"template"`
 	Critical    bool   `json:"critical"`
+
+	SkipTextAlternative bool `json:"skip_text_alternative"`
+	TTL                 int  `json:"ttl"`
+	DedupWindow         int  `json:"dedup_window"`
 }
 
 type ListHandler struct {
-	finder      listsAllClientsAndNotifications
+	finder      listsClientsAndNotifications
 	errorWriter errorWriter
 }
 
-func NewListHandler(notificationsFinder listsAllClientsAndNotifications, errWriter errorWriter) ListHandler {
+func NewListHandler(notificationsFinder listsClientsAndNotifications, errWriter errorWriter) ListHandler {
 	return ListHandler{
 		finder:      notificationsFinder,
 		errorWriter: errWriter,
@@ -40,17 +48,49 @@ func NewListHandler(notificationsFinder listsAllClientsAndNotifications, errWrit
 }
 
 func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
-	clients, notifications, err := h.finder.AllClientsAndNotifications(context.Get("database").(DatabaseInterface))
+	query := req.URL.Query()
+
+	page, err := webutil.NewLegacyPage(query)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
+	filter, err := newKindsFilter(query)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	clients, notifications, next, err := h.finder.ListClientsAndNotifications(context.Get("database").(DatabaseInterface), filter, page)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	webutil.WriteNextPageLink(w, req, page, next)
+
 	notificationsByClient := h.constructNotifications(clients, notifications)
 
 	writeJSON(w, http.StatusOK, notificationsByClient)
 }
 
+func newKindsFilter(query url.Values) (models.KindsFilter, error) {
+	filter := models.KindsFilter{
+		ClientID: query.Get("client_id"),
+	}
+
+	if critical := query.Get("critical"); critical != "" {
+		value, err := strconv.ParseBool(critical)
+		if err != nil {
+			return filter, webutil.ValidationError{Err: errors.New(`"critical" must be true or false`)}
+		}
+		filter.Critical = &value
+	}
+
+	return filter, nil
+}
+
 func (h ListHandler) constructNotifications(clients []models.Client, notifications []models.Kind) NotificationsByClient {
 	notificationsByClient := NotificationsByClient{}
 
@@ -67,6 +107,10 @@ func (h ListHandler) constructNotifications(clients []models.Client, notificatio
 					Description: notification.Description,
 					Template:    notification.TemplateToUse(),
 					Critical:    notification.Critical,
+
+					SkipTextAlternative: notification.SkipTextAlternative,
+					TTL:                 notification.TTL,
+					DedupWindow:         notification.DedupWindow,
 				}
 			}
 		}
@@ -8,6 +8,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notifications"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/ryanmoran/stack"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -42,7 +43,7 @@ var _ = Describe("ListHandler", func() {
 
 	Describe("ServeHTTP", func() {
 		It("receives the clients/notifications from the finder", func() {
-			notificationsFinder.AllClientsAndNotificationsCall.Returns.Clients = []models.Client{
+			notificationsFinder.ListClientsAndNotificationsCall.Returns.Clients = []models.Client{
 				{
 					ID:          "client-123",
 					Description: "Jurassic Park",
@@ -53,7 +54,7 @@ var _ = Describe("ListHandler", func() {
 				},
 			}
 
-			notificationsFinder.AllClientsAndNotificationsCall.Returns.Kinds = []models.Kind{
+			notificationsFinder.ListClientsAndNotificationsCall.Returns.Kinds = []models.Kind{
 				{
 					ID:          "perimeter-breach",
 					Description: "very bad",
@@ -65,6 +66,8 @@ var _ = Describe("ListHandler", func() {
 					Description: "even worse",
 					Critical:    true,
 					ClientID:    "client-123",
+					TTL:         300,
+					DedupWindow: 60,
 				},
 				{
 					ID:          "perimeter-is-good",
@@ -92,12 +95,18 @@ var _ = Describe("ListHandler", func() {
 						"perimeter-breach": {
 							"description": "very bad",
 							"template": "default",
-							"critical": true
+							"critical": true,
+							"skip_text_alternative": false,
+							"ttl": 0,
+							"dedup_window": 0
 						},
 						"fence-broken": {
 							"description": "even worse",
 							"template": "default",
-							"critical": true
+							"critical": true,
+							"skip_text_alternative": false,
+							"ttl": 300,
+							"dedup_window": 60
 						}
 					}
 				},
@@ -108,23 +117,59 @@ var _ = Describe("ListHandler", func() {
 						"perimeter-is-good": {
 							"description": "very good",
 							"template": "default",
-							"critical": false
+							"critical": false,
+							"skip_text_alternative": false,
+							"ttl": 0,
+							"dedup_window": 0
 						},
 						"fence-works": {
 							"description": "even better",
 							"template": "default",
-							"critical": true
+							"critical": true,
+							"skip_text_alternative": false,
+							"ttl": 0,
+							"dedup_window": 0
 						}
 					}
 				}
 			}`))
 
-			Expect(notificationsFinder.AllClientsAndNotificationsCall.Receives.Database).To(Equal(database))
+			Expect(notificationsFinder.ListClientsAndNotificationsCall.Receives.Database).To(Equal(database))
+			Expect(notificationsFinder.ListClientsAndNotificationsCall.Receives.Filter).To(Equal(models.KindsFilter{}))
+			Expect(notificationsFinder.ListClientsAndNotificationsCall.Receives.Page).To(Equal(models.Page{}))
+			Expect(writer.Header()).NotTo(HaveKey("Link"))
+		})
+
+		It("filters and paginates the notifications", func() {
+			notificationsFinder.ListClientsAndNotificationsCall.Returns.Next = []string{"some-client", "some-kind"}
+
+			request, err = http.NewRequest("GET", "/notifications?client_id=some-client&critical=true&limit=10", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusOK))
+
+			critical := true
+			Expect(notificationsFinder.ListClientsAndNotificationsCall.Receives.Filter).To(Equal(models.KindsFilter{
+				ClientID: "some-client",
+				Critical: &critical,
+			}))
+			Expect(notificationsFinder.ListClientsAndNotificationsCall.Receives.Page).To(Equal(models.Page{Limit: 10}))
+			Expect(writer.Header().Get("Link")).To(MatchRegexp(`^</notifications\?client_id=some-client&critical=true&cursor=[\w-]+&limit=10>; rel="next"$`))
+		})
+
+		It("rejects critical filters that are not booleans", func() {
+			request, err = http.NewRequest("GET", "/notifications?critical=sometimes", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"critical" must be true or false`)}))
 		})
 
 		Context("when the notifications finder errors", func() {
 			It("delegates to the error writer", func() {
-				notificationsFinder.AllClientsAndNotificationsCall.Returns.Error = errors.New("BANANA!!!")
+				notificationsFinder.ListClientsAndNotificationsCall.Returns.Error = errors.New("BANANA!!!")
 
 				handler.ServeHTTP(writer, request, context)
 
@@ -17,14 +17,16 @@ type registrar interface {
 }
 
 type PutHandler struct {
-	registrar   registrar
-	errorWriter errorWriter
+	registrar      registrar
+	senderAssigner assignsSenderIdentities
+	errorWriter    errorWriter
 }
 
-func NewPutHandler(registrar registrar, errWriter errorWriter) PutHandler {
+func NewPutHandler(registrar registrar, senderAssigner assignsSenderIdentities, errWriter errorWriter) PutHandler {
 	return PutHandler{
-		registrar:   registrar,
-		errorWriter: errWriter,
+		registrar:      registrar,
+		senderAssigner: senderAssigner,
+		errorWriter:    errWriter,
 	}
 }
 
@@ -51,6 +53,12 @@ func (h PutHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 			Description: notification.Description,
 			Critical:    notification.Critical,
 			TemplateID:  models.DoNotSetTemplateID,
+
+			SkipTextAlternative: notification.SkipTextAlternative,
+			TTL:                 notification.TTL,
+			DedupWindow:         notification.DedupWindow,
+
+			DeliverySettingsGiven: true,
 		})
 	}
 
@@ -88,6 +96,13 @@ func (h PutHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 		}
 	}
 
+	err = h.assignSenders(transaction, clientID, parameters)
+	if err != nil {
+		transaction.Rollback()
+		h.errorWriter.Write(w, err)
+		return
+	}
+
 	err = transaction.Commit()
 	if err != nil {
 		h.errorWriter.Write(w, err)
@@ -97,6 +112,28 @@ func (h PutHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h PutHandler) assignSenders(connection services.ConnectionInterface, clientID string, parameters ClientRegistrationParams) error {
+	if parameters.Sender != nil {
+		err := h.senderAssigner.AssignToClient(connection, clientID, parameters.Sender.SenderIdentity())
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, notification := range parameters.Notifications {
+		if notification.Sender == nil {
+			continue
+		}
+
+		err := h.senderAssigner.AssignToNotification(connection, clientID, notification.ID, notification.Sender.SenderIdentity())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (h PutHandler) ValidateCriticalScopes(scopes interface{}, kinds []models.Kind, client models.Client) ([]models.Kind, error) {
 	hasCriticalWrite := false
 	for _, scope := range scopes.([]interface{}) {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notifications"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
@@ -30,6 +31,7 @@ var _ = Describe("PutHandler", func() {
 		conn        *mocks.Connection
 		transaction *mocks.Transaction
 		registrar   *mocks.Registrar
+		senders     *mocks.SenderIdentityAssigner
 		client      models.Client
 		kinds       []models.Kind
 		context     stack.Context
@@ -46,6 +48,7 @@ var _ = Describe("PutHandler", func() {
 
 		errorWriter = mocks.NewErrorWriter()
 		registrar = mocks.NewRegistrar()
+		senders = mocks.NewSenderIdentityAssigner()
 		writer = httptest.NewRecorder()
 		requestBody, err := json.Marshal(map[string]interface{}{
 			"source_name": "Raptor Containment Unit",
@@ -55,7 +58,10 @@ var _ = Describe("PutHandler", func() {
 					"critical":    true,
 				},
 				"feeding_time": map[string]interface{}{
-					"description": "Feeding Time",
+					"description":           "Feeding Time",
+					"skip_text_alternative": true,
+					"ttl":                   300,
+					"dedup_window":          60,
 				},
 			},
 		})
@@ -95,15 +101,22 @@ var _ = Describe("PutHandler", func() {
 				Description: "Perimeter Breach",
 				Critical:    true,
 				ClientID:    client.ID,
+
+				DeliverySettingsGiven: true,
 			},
 			{
-				ID:          "feeding_time",
-				Description: "Feeding Time",
-				ClientID:    client.ID,
+				ID:                  "feeding_time",
+				Description:         "Feeding Time",
+				ClientID:            client.ID,
+				SkipTextAlternative: true,
+				TTL:                 300,
+				DedupWindow:         60,
+
+				DeliverySettingsGiven: true,
 			},
 		}
 
-		handler = notifications.NewPutHandler(registrar, errorWriter)
+		handler = notifications.NewPutHandler(registrar, senders, errorWriter)
 	})
 
 	Describe("Execute", func() {
@@ -148,6 +161,47 @@ var _ = Describe("PutHandler", func() {
 			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
 		})
 
+		It("assigns sender identities included in the request", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"source_name": "Raptor Containment Unit",
+				"sender": map[string]interface{}{
+					"name":    "Raptor Containment",
+					"address": "raptors@example.com",
+				},
+				"notifications": map[string]interface{}{
+					"feeding_time": map[string]interface{}{
+						"description": "Feeding Time",
+						"sender": map[string]interface{}{
+							"address":  "feeding@example.com",
+							"reply_to": "keepers@example.com",
+						},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Body = ioutil.NopCloser(bytes.NewBuffer(requestBody))
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(senders.AssignToClientCall.Receives.Connection).To(Equal(transaction))
+			Expect(senders.AssignToClientCall.Receives.ClientID).To(Equal("raptors"))
+			Expect(senders.AssignToClientCall.Receives.SenderIdentity).To(Equal(collections.SenderIdentity{
+				Name:    "Raptor Containment",
+				Address: "raptors@example.com",
+			}))
+
+			Expect(senders.AssignToNotificationCall.Receives.Connection).To(Equal(transaction))
+			Expect(senders.AssignToNotificationCall.Receives.ClientID).To(Equal("raptors"))
+			Expect(senders.AssignToNotificationCall.Receives.NotificationID).To(Equal("feeding_time"))
+			Expect(senders.AssignToNotificationCall.Receives.SenderIdentity).To(Equal(collections.SenderIdentity{
+				Address: "feeding@example.com",
+				ReplyTo: "keepers@example.com",
+			}))
+
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
 		Context("failure cases", func() {
 			It("rejects entire request and returns 404 error if notification is critical without scope", func() {
 				requestBody, err := json.Marshal(map[string]interface{}{
@@ -246,6 +300,27 @@ var _ = Describe("PutHandler", func() {
 				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
 			})
 
+			It("delegates sender assignment errors to the ErrorWriter", func() {
+				senders.AssignToClientCall.Returns.Error = errors.New("BOOM!")
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"source_name": "Raptor Containment Unit",
+					"sender": map[string]interface{}{
+						"address": "raptors@evil.com",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request.Body = ioutil.NopCloser(bytes.NewBuffer(requestBody))
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("BOOM!")))
+
+				Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+
 			It("delegates transaction errors to the ErrorWriter", func() {
 				transaction.CommitCall.Returns.Error = errors.New("transaction commit error")
 				handler.ServeHTTP(writer, request, context)
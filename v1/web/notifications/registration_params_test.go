@@ -60,6 +60,25 @@ var _ = Describe("RegistrationParams", func() {
 			Expect(parameters.IncludesKinds).To(BeTrue())
 		})
 
+		It("leaves the delivery settings of the kinds to the stored ones", func() {
+			body, err := json.Marshal(map[string]interface{}{
+				"source_description": "Raptor Containment Unit",
+				"kinds": []map[string]interface{}{
+					{
+						"id":                    "feeding_time",
+						"description":           "Feeding Time",
+						"DeliverySettingsGiven": true,
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			parameters, err := notifications.NewRegistrationParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parameters.Kinds).To(HaveLen(1))
+			Expect(parameters.Kinds[0].DeliverySettingsGiven).To(BeFalse())
+		})
+
 		It("sets the IncludesKinds flag to false when the kinds are missing", func() {
 			body, err := json.Marshal(map[string]interface{}{
 				"source_description": "Raptor Containment Unit",
@@ -16,14 +16,17 @@ type Routes struct {
 	ErrorWriter          errorWriter
 	Registrar            registrar
 	TemplateAssigner     assignsTemplates
-	NotificationsFinder  listsAllClientsAndNotifications
+	SenderAssigner       assignsSenderIdentities
+	NotificationsFinder  listsClientsAndNotifications
 	NotificationsUpdater notificationsUpdater
 }
 
 func (r Routes) Register(m muxer) {
 	m.Handle("PUT", "/registration", NewRegistrationHandler(r.Registrar, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
-	m.Handle("PUT", "/notifications", NewPutHandler(r.Registrar, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/notifications", NewPutHandler(r.Registrar, r.SenderAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/notifications", NewListHandler(r.NotificationsFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
 	m.Handle("PUT", "/clients/{client_id}/notifications/{notification_id}", NewUpdateHandler(r.NotificationsUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
 	m.Handle("PUT", "/clients/{client_id}/notifications/{notification_id}/template", NewAssignTemplateHandler(r.TemplateAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/clients/{client_id}/notifications/{notification_id}/sender", NewAssignSenderHandler(r.SenderAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/clients/{client_id}/notifications/{notification_id}/sender", NewUnassignSenderHandler(r.SenderAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
 }
@@ -30,6 +30,7 @@ var _ = Describe("Routes", func() {
 			ErrorWriter:          mocks.NewErrorWriter(),
 			NotificationsFinder:  mocks.NewNotificationsFinder(),
 			NotificationsUpdater: &mocks.NotificationUpdater{},
+			SenderAssigner:       mocks.NewSenderIdentityAssigner(),
 		}.Register(muxer)
 	})
 
@@ -81,6 +82,30 @@ var _ = Describe("Routes", func() {
 			authenticator := s.Middleware[2].(middleware.Authenticator)
 			Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
 		})
+
+		It("routes PUT /clients/{client_id}/notifications/{notification_id}/sender", func() {
+			request, err := http.NewRequest("PUT", "/clients/{client_id}/notifications/{notification_id}/sender", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(notifications.AssignSenderHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+		})
+
+		It("routes DELETE /clients/{client_id}/notifications/{notification_id}/sender", func() {
+			request, err := http.NewRequest("DELETE", "/clients/{client_id}/notifications/{notification_id}/sender", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(notifications.UnassignSenderHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+		})
 	})
 
 	Describe("/registration", func() {
@@ -0,0 +1,94 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type SenderIdentityAssignment struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	ReplyTo string `json:"reply_to"`
+}
+
+func (a SenderIdentityAssignment) SenderIdentity() collections.SenderIdentity {
+	return collections.SenderIdentity{
+		Name:    a.Name,
+		Address: a.Address,
+		ReplyTo: a.ReplyTo,
+	}
+}
+
+type assignsSenderIdentities interface {
+	AssignToClient(connection collections.ConnectionInterface, clientID string, identity collections.SenderIdentity) error
+	AssignToNotification(connection collections.ConnectionInterface, clientID, notificationID string, identity collections.SenderIdentity) error
+	UnassignFromNotification(connection collections.ConnectionInterface, clientID, notificationID string) error
+}
+
+type AssignSenderHandler struct {
+	senderAssigner assignsSenderIdentities
+	errorWriter    errorWriter
+}
+
+func NewAssignSenderHandler(assigner assignsSenderIdentities, errWriter errorWriter) AssignSenderHandler {
+	return AssignSenderHandler{
+		senderAssigner: assigner,
+		errorWriter:    errWriter,
+	}
+}
+
+func (h AssignSenderHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	clientID, notificationID := parseSenderURL(req.URL.Path)
+
+	var assignment SenderIdentityAssignment
+	err := json.NewDecoder(req.Body).Decode(&assignment)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	err = h.senderAssigner.AssignToNotification(database.Connection(), clientID, notificationID, assignment.SenderIdentity())
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type UnassignSenderHandler struct {
+	senderAssigner assignsSenderIdentities
+	errorWriter    errorWriter
+}
+
+func NewUnassignSenderHandler(assigner assignsSenderIdentities, errWriter errorWriter) UnassignSenderHandler {
+	return UnassignSenderHandler{
+		senderAssigner: assigner,
+		errorWriter:    errWriter,
+	}
+}
+
+func (h UnassignSenderHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	clientID, notificationID := parseSenderURL(req.URL.Path)
+
+	database := context.Get("database").(DatabaseInterface)
+	err := h.senderAssigner.UnassignFromNotification(database.Connection(), clientID, notificationID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseSenderURL(path string) (string, string) {
+	routeMatches := regexp.MustCompile("/clients/(.*)/notifications/(.*)/sender").FindStringSubmatch(path)
+
+	return routeMatches[1], routeMatches[2]
+}
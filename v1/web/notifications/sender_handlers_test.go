@@ -0,0 +1,117 @@
+package notifications_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/notifications"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sender handlers", func() {
+	var (
+		senderAssigner *mocks.SenderIdentityAssigner
+		errorWriter    *mocks.ErrorWriter
+		context        stack.Context
+		database       *mocks.Database
+		connection     *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		senderAssigner = mocks.NewSenderIdentityAssigner()
+		errorWriter = mocks.NewErrorWriter()
+		connection = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+		context = stack.NewContext()
+		context.Set("database", database)
+	})
+
+	Describe("AssignSenderHandler", func() {
+		var handler notifications.AssignSenderHandler
+
+		BeforeEach(func() {
+			handler = notifications.NewAssignSenderHandler(senderAssigner, errorWriter)
+		})
+
+		It("associates a sender identity with a notification", func() {
+			body := []byte(`{"name": "Autoscaler", "address": "autoscaler@example.com", "reply_to": "support@example.com"}`)
+
+			w := httptest.NewRecorder()
+			request, err := http.NewRequest("PUT", "/clients/my-client/notifications/my-notification/sender", bytes.NewBuffer(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(w, request, context)
+
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+			Expect(senderAssigner.AssignToNotificationCall.Receives.Connection).To(Equal(connection))
+			Expect(senderAssigner.AssignToNotificationCall.Receives.ClientID).To(Equal("my-client"))
+			Expect(senderAssigner.AssignToNotificationCall.Receives.NotificationID).To(Equal("my-notification"))
+			Expect(senderAssigner.AssignToNotificationCall.Receives.SenderIdentity).To(Equal(collections.SenderIdentity{
+				Name:    "Autoscaler",
+				Address: "autoscaler@example.com",
+				ReplyTo: "support@example.com",
+			}))
+		})
+
+		It("delegates to the error writer when the assigner errors", func() {
+			senderAssigner.AssignToNotificationCall.Returns.Error = errors.New("banana")
+
+			w := httptest.NewRecorder()
+			request, err := http.NewRequest("PUT", "/clients/my-client/notifications/my-notification/sender", bytes.NewBuffer([]byte(`{"address": "me@example.com"}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(w, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("banana")))
+		})
+
+		It("writes a ParseError to the error writer when request body is invalid", func() {
+			w := httptest.NewRecorder()
+			request, err := http.NewRequest("PUT", "/clients/my-client/notifications/my-notification/sender", bytes.NewBuffer([]byte(`{ "this is" : not-valid-json }`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(w, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
+		})
+	})
+
+	Describe("UnassignSenderHandler", func() {
+		var handler notifications.UnassignSenderHandler
+
+		BeforeEach(func() {
+			handler = notifications.NewUnassignSenderHandler(senderAssigner, errorWriter)
+		})
+
+		It("removes the sender identity from a notification", func() {
+			w := httptest.NewRecorder()
+			request, err := http.NewRequest("DELETE", "/clients/my-client/notifications/my-notification/sender", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(w, request, context)
+
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+			Expect(senderAssigner.UnassignFromNotificationCall.Receives.Connection).To(Equal(connection))
+			Expect(senderAssigner.UnassignFromNotificationCall.Receives.ClientID).To(Equal("my-client"))
+			Expect(senderAssigner.UnassignFromNotificationCall.Receives.NotificationID).To(Equal("my-notification"))
+		})
+
+		It("delegates to the error writer when the assigner errors", func() {
+			senderAssigner.UnassignFromNotificationCall.Returns.Error = errors.New("banana")
+
+			w := httptest.NewRecorder()
+			request, err := http.NewRequest("DELETE", "/clients/my-client/notifications/my-notification/sender", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(w, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("banana")))
+		})
+	})
+})
@@ -55,6 +55,8 @@ var _ = Describe("UpdateHandler", func() {
 				TemplateID:  "template-name",
 				ClientID:    "this-client",
 				ID:          "this-kind",
+
+				DeliverySettingsGiven: true,
 			}))
 		})
 
@@ -1,6 +1,8 @@
 package notifications
 
 import (
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -12,6 +14,10 @@ type NotificationUpdateParams struct {
 	Description string `json:"description" validate-required:"true"`
 	Critical    bool   `json:"critical"    validate-required:"true"`
 	TemplateID  string `json:"template"    validate-required:"true"`
+
+	SkipTextAlternative bool `json:"skip_text_alternative"`
+	TTL                 int  `json:"ttl"`
+	DedupWindow         int  `json:"dedup_window"`
 }
 
 func NewNotificationParams(body io.Reader) (NotificationUpdateParams, error) {
@@ -27,6 +33,19 @@ func NewNotificationParams(body io.Reader) (NotificationUpdateParams, error) {
 			return params, webutil.ParseError{}
 		}
 	}
+
+	if params.TTL < 0 {
+		return params, webutil.ValidationError{Err: errors.New(`"ttl" must not be negative`)}
+	}
+
+	if params.TTL > models.MaxTTL {
+		return params, webutil.ValidationError{Err: fmt.Errorf(`"ttl" must not be more than %d seconds`, models.MaxTTL)}
+	}
+
+	if params.DedupWindow < 0 {
+		return params, webutil.ValidationError{Err: errors.New(`"dedup_window" must not be negative`)}
+	}
+
 	return params, nil
 }
 
@@ -37,5 +56,11 @@ func (params NotificationUpdateParams) ToModel(clientID, notificationID string)
 		TemplateID:  params.TemplateID,
 		ClientID:    clientID,
 		ID:          notificationID,
+
+		SkipTextAlternative: params.SkipTextAlternative,
+		TTL:                 params.TTL,
+		DedupWindow:         params.DedupWindow,
+
+		DeliverySettingsGiven: true,
 	}
 }
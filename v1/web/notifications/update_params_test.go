@@ -1,6 +1,7 @@
 package notifications_test
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notifications"
@@ -30,6 +31,30 @@ var _ = Describe("Notification", func() {
 				})
 			})
 
+			Context("when the ttl is negative", func() {
+				It("returns a validation error", func() {
+					body := strings.NewReader(`{"description":"my awesome notification", "critical":true, "template":"my-awesome-template", "ttl":-60}`)
+					_, err := notifications.NewNotificationParams(body)
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"ttl" must not be negative`)}))
+				})
+			})
+
+			Context("when the ttl is more than a year", func() {
+				It("returns a validation error", func() {
+					body := strings.NewReader(`{"description":"my awesome notification", "critical":true, "template":"my-awesome-template", "ttl":31536001}`)
+					_, err := notifications.NewNotificationParams(body)
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"ttl" must not be more than 31536000 seconds`)}))
+				})
+			})
+
+			Context("when the dedup window is negative", func() {
+				It("returns a validation error", func() {
+					body := strings.NewReader(`{"description":"my awesome notification", "critical":true, "template":"my-awesome-template", "dedup_window":-60}`)
+					_, err := notifications.NewNotificationParams(body)
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"dedup_window" must not be negative`)}))
+				})
+			})
+
 			Context("when the json is malformed", func() {
 				It("returns a parse error", func() {
 					body := strings.NewReader(`{"description":"my awesome notification", "critical":true, "template":"my-awesome-template}`)
@@ -42,7 +67,7 @@ var _ = Describe("Notification", func() {
 
 	Describe("ToModel", func() {
 		It("returns a model.Kind composed of the NotificationUpdateParams", func() {
-			body := strings.NewReader(`{"description":"my awesome notification", "critical":true, "template":"my-awesome-template"}`)
+			body := strings.NewReader(`{"description":"my awesome notification", "critical":true, "template":"my-awesome-template", "skip_text_alternative":true, "ttl":300, "dedup_window":60}`)
 			updateParams, err := notifications.NewNotificationParams(body)
 			Expect(err).NotTo(HaveOccurred())
 
@@ -52,6 +77,9 @@ var _ = Describe("Notification", func() {
 			Expect(notification.TemplateID).To(Equal("my-awesome-template"))
 			Expect(notification.ClientID).To(Equal("client-id"))
 			Expect(notification.ID).To(Equal("notification-id"))
+			Expect(notification.SkipTextAlternative).To(BeTrue())
+			Expect(notification.TTL).To(Equal(300))
+			Expect(notification.DedupWindow).To(Equal(60))
 		})
 	})
 })
@@ -0,0 +1,284 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type audienceStore interface {
+	Create(conn services.ConnectionInterface, clientID string, audience services.Audience) (services.Audience, error)
+	Get(conn services.ConnectionInterface, clientID, id string) (services.Audience, error)
+	List(conn services.ConnectionInterface, clientID string, page models.Page) ([]services.Audience, []string, error)
+	Update(conn services.ConnectionInterface, clientID string, audience services.Audience) (services.Audience, error)
+	Delete(conn services.ConnectionInterface, clientID, id string) error
+}
+
+type audienceExecutor interface {
+	ExecuteAudience(conn ConnectionInterface, req *http.Request, context stack.Context, audience services.Audience, dispatcher BatchDispatcher, vcapRequestID string) (response []byte, err error)
+	DryRunAudience(conn ConnectionInterface, req *http.Request, context stack.Context, audience services.Audience, dispatcher BatchDispatcher, vcapRequestID string, page models.Page) (response []byte, next []string, err error)
+}
+
+type AudienceOutput struct {
+	ID        string              `json:"id"`
+	Name      string              `json:"name"`
+	Targets   []BatchTargetParams `json:"targets"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+var audiencePath = regexp.MustCompile(`/audiences/([^/]*)$`)
+
+type CreateAudienceHandler struct {
+	store       audienceStore
+	errorWriter errorWriter
+}
+
+func NewCreateAudienceHandler(store audienceStore, errWriter errorWriter) CreateAudienceHandler {
+	return CreateAudienceHandler{
+		store:       store,
+		errorWriter: errWriter,
+	}
+}
+
+func (h CreateAudienceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	params, err := NewAudienceParams(req.Body)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	conn := context.Get("database").(DatabaseInterface).Connection()
+	audience, err := h.store.Create(conn, audienceClientID(context), params.ToAudience())
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newAudienceOutput(audience))
+}
+
+type ListAudiencesHandler struct {
+	store       audienceStore
+	errorWriter errorWriter
+}
+
+func NewListAudiencesHandler(store audienceStore, errWriter errorWriter) ListAudiencesHandler {
+	return ListAudiencesHandler{
+		store:       store,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListAudiencesHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	page, err := webutil.NewPage(req.URL.Query())
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	conn := context.Get("database").(DatabaseInterface).Connection()
+	audiences, next, err := h.store.List(conn, audienceClientID(context), page)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := map[string][]AudienceOutput{
+		"audiences": {},
+	}
+	for _, audience := range audiences {
+		document["audiences"] = append(document["audiences"], newAudienceOutput(audience))
+	}
+
+	webutil.WriteNextPageLink(w, req, page, next)
+
+	writeJSON(w, http.StatusOK, document)
+}
+
+type GetAudienceHandler struct {
+	store       audienceStore
+	errorWriter errorWriter
+}
+
+func NewGetAudienceHandler(store audienceStore, errWriter errorWriter) GetAudienceHandler {
+	return GetAudienceHandler{
+		store:       store,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetAudienceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	conn := context.Get("database").(DatabaseInterface).Connection()
+	audience, err := h.store.Get(conn, audienceClientID(context), parseAudiencePath(req))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAudienceOutput(audience))
+}
+
+type UpdateAudienceHandler struct {
+	store       audienceStore
+	errorWriter errorWriter
+}
+
+func NewUpdateAudienceHandler(store audienceStore, errWriter errorWriter) UpdateAudienceHandler {
+	return UpdateAudienceHandler{
+		store:       store,
+		errorWriter: errWriter,
+	}
+}
+
+func (h UpdateAudienceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	params, err := NewAudienceParams(req.Body)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	audience := params.ToAudience()
+	audience.ID = parseAudiencePath(req)
+
+	conn := context.Get("database").(DatabaseInterface).Connection()
+	audience, err = h.store.Update(conn, audienceClientID(context), audience)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAudienceOutput(audience))
+}
+
+type DeleteAudienceHandler struct {
+	store       audienceStore
+	errorWriter errorWriter
+}
+
+func NewDeleteAudienceHandler(store audienceStore, errWriter errorWriter) DeleteAudienceHandler {
+	return DeleteAudienceHandler{
+		store:       store,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DeleteAudienceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	conn := context.Get("database").(DatabaseInterface).Connection()
+	err := h.store.Delete(conn, audienceClientID(context), parseAudiencePath(req))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AudienceHandler sends a notification to the targets of a saved audience,
+// resolving them at the time of sending.
+type AudienceHandler struct {
+	errorWriter errorWriter
+	notify      audienceExecutor
+	store       audienceStore
+	dispatcher  BatchDispatcher
+}
+
+func NewAudienceHandler(notify audienceExecutor, errWriter errorWriter, store audienceStore, dispatcher BatchDispatcher) AudienceHandler {
+	return AudienceHandler{
+		errorWriter: errWriter,
+		notify:      notify,
+		store:       store,
+		dispatcher:  dispatcher,
+	}
+}
+
+func (h AudienceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	conn := context.Get("database").(DatabaseInterface).Connection()
+	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
+
+	query := req.URL.Query()
+
+	dryRun, err := parseDryRun(query.Get("dry_run"))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	audience, err := h.store.Get(conn, audienceClientID(context), parseAudiencePath(req))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	if !dryRun {
+		output, err := h.notify.ExecuteAudience(conn, req, context, audience, h.dispatcher, vcapRequestID)
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(output)
+		return
+	}
+
+	page, err := webutil.NewPage(query)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output, next, err := h.notify.DryRunAudience(conn, req, context, audience, h.dispatcher, vcapRequestID, page)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	webutil.WriteNextPageLink(w, req, page, next)
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+// audienceClientID returns the client the audiences of a request belong
+// to: the client the token was issued to.
+func audienceClientID(context stack.Context) string {
+	token := context.Get("token").(*jwt.Token)
+	return token.Claims["client_id"].(string)
+}
+
+func parseAudiencePath(req *http.Request) string {
+	return audiencePath.FindStringSubmatch(req.URL.Path)[1]
+}
+
+func newAudienceOutput(audience services.Audience) AudienceOutput {
+	output := AudienceOutput{
+		ID:        audience.ID,
+		Name:      audience.Name,
+		Targets:   []BatchTargetParams{},
+		CreatedAt: audience.CreatedAt,
+		UpdatedAt: audience.UpdatedAt,
+	}
+	for _, target := range audience.Targets {
+		output.Targets = append(output.Targets, newBatchTargetParams(target))
+	}
+
+	return output
+}
+
+func writeJSON(w http.ResponseWriter, status int, object interface{}) {
+	output, err := json.Marshal(object)
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(status)
+	w.Write(output)
+}
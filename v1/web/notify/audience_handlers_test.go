@@ -0,0 +1,388 @@
+package notify_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audience handlers", func() {
+	var (
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		connection  *mocks.Connection
+		store       *mocks.AudienceStore
+		errorWriter *mocks.ErrorWriter
+		audience    services.Audience
+	)
+
+	BeforeEach(func() {
+		writer = httptest.NewRecorder()
+		store = mocks.NewAudienceStore()
+		errorWriter = mocks.NewErrorWriter()
+
+		database := mocks.NewDatabase()
+		connection = mocks.NewConnection()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("token", &jwt.Token{Claims: map[string]interface{}{"client_id": "some-client"}})
+		context.Set(notify.VCAPRequestIDKey, "some-request-id")
+
+		createdAt := time.Date(2015, time.June, 8, 14, 32, 11, 0, time.UTC)
+		audience = services.Audience{
+			ID:   "audience-123",
+			Name: "Operators",
+			Targets: []services.BatchTarget{
+				{Type: services.BatchTargetUser, ID: "user-123"},
+				{Type: services.BatchTargetOrganization, ID: "org-123", Role: "OrgManager"},
+			},
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}
+	})
+
+	audienceJSON := `{
+		"id": "audience-123",
+		"name": "Operators",
+		"targets": [
+			{"user": "user-123"},
+			{"organization": "org-123", "role": "OrgManager"}
+		],
+		"created_at": "2015-06-08T14:32:11Z",
+		"updated_at": "2015-06-08T14:32:11Z"
+	}`
+
+	Describe("CreateAudienceHandler", func() {
+		var handler notify.CreateAudienceHandler
+
+		BeforeEach(func() {
+			handler = notify.NewCreateAudienceHandler(store, errorWriter)
+		})
+
+		It("creates the audience for the client of the token", func() {
+			store.CreateCall.Returns.Audience = audience
+
+			request, err := http.NewRequest("POST", "/audiences", strings.NewReader(`{
+				"name": "Operators",
+				"targets": [{"user": "user-123"}, {"organization": "org-123", "role": "OrgManager"}]
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusCreated))
+			Expect(writer.Body.String()).To(MatchJSON(audienceJSON))
+
+			Expect(reflect.ValueOf(store.CreateCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
+			Expect(store.CreateCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(store.CreateCall.Receives.Audience).To(Equal(services.Audience{
+				Name:    "Operators",
+				Targets: audience.Targets,
+			}))
+		})
+
+		It("writes validation errors", func() {
+			request, err := http.NewRequest("POST", "/audiences", strings.NewReader(`{"targets": [{"user": "user-123"}]}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"name" is a required field`)}))
+			Expect(store.CreateCall.WasCalled).To(BeFalse())
+		})
+
+		It("propagates errors from the store", func() {
+			store.CreateCall.Returns.Error = errors.New("BOOM!")
+
+			request, err := http.NewRequest("POST", "/audiences", strings.NewReader(`{"name": "Operators", "targets": [{"user": "user-123"}]}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+
+	Describe("ListAudiencesHandler", func() {
+		var handler notify.ListAudiencesHandler
+
+		BeforeEach(func() {
+			handler = notify.NewListAudiencesHandler(store, errorWriter)
+		})
+
+		It("lists the audiences of the client", func() {
+			store.ListCall.Returns.Audiences = []services.Audience{audience}
+
+			request, err := http.NewRequest("GET", "/audiences", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.String()).To(MatchJSON(`{"audiences": [` + audienceJSON + `]}`))
+			Expect(store.ListCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(store.ListCall.Receives.Page).To(Equal(models.Page{Limit: webutil.DefaultPageLimit}))
+			Expect(writer.Header().Get("Link")).To(BeEmpty())
+		})
+
+		It("links to the next page when there are more audiences", func() {
+			store.ListCall.Returns.Audiences = []services.Audience{audience}
+			store.ListCall.Returns.Next = []string{"audience-123"}
+
+			request, err := http.NewRequest("GET", "/audiences?limit=1", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(store.ListCall.Receives.Page).To(Equal(models.Page{Limit: 1}))
+			Expect(writer.Header().Get("Link")).To(ContainSubstring("/audiences?cursor="))
+			Expect(writer.Header().Get("Link")).To(ContainSubstring(`rel="next"`))
+		})
+
+		It("writes validation errors for an invalid limit", func() {
+			request, err := http.NewRequest("GET", "/audiences?limit=0", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+		})
+
+		It("writes an empty list when the client has no audiences", func() {
+			request, err := http.NewRequest("GET", "/audiences", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Body.String()).To(MatchJSON(`{"audiences": []}`))
+		})
+
+		It("propagates errors from the store", func() {
+			store.ListCall.Returns.Error = errors.New("BOOM!")
+
+			request, err := http.NewRequest("GET", "/audiences", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+
+	Describe("GetAudienceHandler", func() {
+		var handler notify.GetAudienceHandler
+
+		BeforeEach(func() {
+			handler = notify.NewGetAudienceHandler(store, errorWriter)
+		})
+
+		It("writes the audience", func() {
+			store.GetCall.Returns.Audience = audience
+
+			request, err := http.NewRequest("GET", "/audiences/audience-123", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.String()).To(MatchJSON(audienceJSON))
+			Expect(store.GetCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(store.GetCall.Receives.ID).To(Equal("audience-123"))
+		})
+
+		It("propagates errors from the store", func() {
+			store.GetCall.Returns.Error = errors.New("BOOM!")
+
+			request, err := http.NewRequest("GET", "/audiences/audience-123", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+
+	Describe("UpdateAudienceHandler", func() {
+		var handler notify.UpdateAudienceHandler
+
+		BeforeEach(func() {
+			handler = notify.NewUpdateAudienceHandler(store, errorWriter)
+		})
+
+		It("replaces the name and targets of the audience", func() {
+			store.UpdateCall.Returns.Audience = audience
+
+			request, err := http.NewRequest("PUT", "/audiences/audience-123", strings.NewReader(`{
+				"name": "Operators",
+				"targets": [{"user": "user-123"}, {"organization": "org-123", "role": "OrgManager"}]
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.String()).To(MatchJSON(audienceJSON))
+			Expect(store.UpdateCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(store.UpdateCall.Receives.Audience).To(Equal(services.Audience{
+				ID:      "audience-123",
+				Name:    "Operators",
+				Targets: audience.Targets,
+			}))
+		})
+
+		It("writes validation errors", func() {
+			request, err := http.NewRequest("PUT", "/audiences/audience-123", strings.NewReader(`{"name": "Operators"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"targets" is a required field`)}))
+			Expect(store.UpdateCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Describe("DeleteAudienceHandler", func() {
+		var handler notify.DeleteAudienceHandler
+
+		BeforeEach(func() {
+			handler = notify.NewDeleteAudienceHandler(store, errorWriter)
+		})
+
+		It("deletes the audience", func() {
+			request, err := http.NewRequest("DELETE", "/audiences/audience-123", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+			Expect(store.DeleteCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(store.DeleteCall.Receives.ID).To(Equal("audience-123"))
+		})
+
+		It("propagates errors from the store", func() {
+			store.DeleteCall.Returns.Error = errors.New("BOOM!")
+
+			request, err := http.NewRequest("DELETE", "/audiences/audience-123", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+
+	Describe("AudienceHandler", func() {
+		var (
+			handler    notify.AudienceHandler
+			notifyObj  *mocks.Notify
+			dispatcher *mocks.BatchDispatcher
+			request    *http.Request
+		)
+
+		BeforeEach(func() {
+			var err error
+			request, err = http.NewRequest("POST", "/audiences/audience-123", strings.NewReader(`{"kind_id": "some-kind", "text": "hello"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			store.GetCall.Returns.Audience = audience
+			notifyObj = mocks.NewNotify()
+			dispatcher = mocks.NewBatchDispatcher()
+			handler = notify.NewAudienceHandler(notifyObj, errorWriter, store, dispatcher)
+		})
+
+		It("sends the notification to the audience", func() {
+			notifyObj.ExecuteAudienceCall.Returns.Response = []byte("whut")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.String()).To(Equal("whut"))
+
+			Expect(store.GetCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(store.GetCall.Receives.ID).To(Equal("audience-123"))
+
+			Expect(reflect.ValueOf(notifyObj.ExecuteAudienceCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
+			Expect(notifyObj.ExecuteAudienceCall.Receives.Request).To(Equal(request))
+			Expect(notifyObj.ExecuteAudienceCall.Receives.Context).To(Equal(context))
+			Expect(notifyObj.ExecuteAudienceCall.Receives.Audience).To(Equal(audience))
+			Expect(notifyObj.ExecuteAudienceCall.Receives.Dispatcher).To(Equal(dispatcher))
+			Expect(notifyObj.ExecuteAudienceCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+		})
+
+		It("propagates errors finding the audience", func() {
+			store.GetCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+			Expect(notifyObj.ExecuteAudienceCall.Receives.Request).To(BeNil())
+		})
+
+		It("propagates errors sending the notification", func() {
+			notifyObj.ExecuteAudienceCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+		})
+
+		Context("when a dry run is requested", func() {
+			BeforeEach(func() {
+				request.URL.RawQuery = "dry_run=true&limit=10"
+			})
+
+			It("previews the recipients of the audience instead of sending the notification", func() {
+				notifyObj.DryRunAudienceCall.Returns.Response = []byte("preview")
+				notifyObj.DryRunAudienceCall.Returns.Next = []string{"user-123"}
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusOK))
+				Expect(writer.Body.String()).To(Equal("preview"))
+				Expect(writer.Header().Get("Link")).To(ContainSubstring("dry_run=true"))
+
+				Expect(reflect.ValueOf(notifyObj.DryRunAudienceCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
+				Expect(notifyObj.DryRunAudienceCall.Receives.Request).To(Equal(request))
+				Expect(notifyObj.DryRunAudienceCall.Receives.Audience).To(Equal(audience))
+				Expect(notifyObj.DryRunAudienceCall.Receives.Dispatcher).To(Equal(dispatcher))
+				Expect(notifyObj.DryRunAudienceCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+				Expect(notifyObj.DryRunAudienceCall.Receives.Page).To(Equal(models.Page{Limit: 10}))
+				Expect(notifyObj.ExecuteAudienceCall.Receives.Request).To(BeNil())
+			})
+
+			It("rejects values that are not booleans", func() {
+				request.URL.RawQuery = "dry_run=maybe"
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"dry_run" must be true or false`)}))
+				Expect(store.GetCall.Receives.ID).To(BeEmpty())
+			})
+
+			It("propagates the error", func() {
+				notifyObj.DryRunAudienceCall.Returns.Error = errors.New("BOOM!")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+			})
+		})
+	})
+})
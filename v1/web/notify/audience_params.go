@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+)
+
+// MaxAudienceNameLength is the longest name an audience may be given.
+const MaxAudienceNameLength = 255
+
+// AudienceParams is the body of a request creating or replacing an
+// audience. Its targets take the same form as the targets of a batch.
+type AudienceParams struct {
+	Name    string              `json:"name"`
+	Targets []BatchTargetParams `json:"targets"`
+}
+
+func NewAudienceParams(body io.ReadCloser) (AudienceParams, error) {
+	defer body.Close()
+
+	params := AudienceParams{}
+
+	buffer := bytes.NewBuffer([]byte{})
+	buffer.ReadFrom(body)
+	if buffer.Len() > 0 {
+		err := json.Unmarshal(buffer.Bytes(), &params)
+		if err != nil {
+			return params, webutil.ParseError{}
+		}
+	}
+
+	var errs []string
+	switch {
+	case params.Name == "":
+		errs = append(errs, `"name" is a required field`)
+	case utf8.RuneCountInString(params.Name) > MaxAudienceNameLength:
+		errs = append(errs, fmt.Sprintf(`"name" must not be longer than %d characters`, MaxAudienceNameLength))
+	}
+
+	errs = append(errs, checkTargets(params.Targets)...)
+
+	if len(errs) > 0 {
+		return params, webutil.ValidationError{Err: errors.New(strings.Join(errs, ","))}
+	}
+
+	return params, nil
+}
+
+func (params AudienceParams) ToAudience() services.Audience {
+	return services.Audience{
+		Name:    params.Name,
+		Targets: toBatchTargets(params.Targets),
+	}
+}
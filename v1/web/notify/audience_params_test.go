@@ -0,0 +1,83 @@
+package notify_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AudienceParams", func() {
+	Describe("NewAudienceParams", func() {
+		It("parses the name and targets", func() {
+			body := io.NopCloser(strings.NewReader(`{
+				"name": "Operators",
+				"targets": [
+					{"user": "user-123"},
+					{"email": "Someone <someone@example.com>"},
+					{"organization": "org-123", "role": "OrgManager"}
+				]
+			}`))
+
+			params, err := notify.NewAudienceParams(body)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(params.Name).To(Equal("Operators"))
+			Expect(params.Targets).To(Equal([]notify.BatchTargetParams{
+				{User: "user-123"},
+				{Email: "someone@example.com"},
+				{Organization: "org-123", Role: "OrgManager"},
+			}))
+		})
+
+		It("returns a parse error when the body is not JSON", func() {
+			_, err := notify.NewAudienceParams(io.NopCloser(strings.NewReader("not json")))
+			Expect(err).To(Equal(webutil.ParseError{}))
+		})
+
+		It("requires a name and targets", func() {
+			_, err := notify.NewAudienceParams(io.NopCloser(strings.NewReader(`{}`)))
+			Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"name" is a required field,"targets" is a required field`)}))
+		})
+
+		It("rejects names that are too long", func() {
+			body := io.NopCloser(strings.NewReader(`{"name": "` + strings.Repeat("a", 256) + `", "targets": [{"user": "user-123"}]}`))
+
+			_, err := notify.NewAudienceParams(body)
+			Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"name" must not be longer than 255 characters`)}))
+		})
+
+		It("validates each target", func() {
+			body := io.NopCloser(strings.NewReader(`{"name": "Operators", "targets": [{"user": "user-123", "scope": "some.scope"}]}`))
+
+			_, err := notify.NewAudienceParams(body)
+			Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"targets[0]" must name exactly one of "user", "email", "space", "organization" or "scope"`)}))
+		})
+	})
+
+	Describe("ToAudience", func() {
+		It("returns the audience for the store", func() {
+			params := notify.AudienceParams{
+				Name: "Operators",
+				Targets: []notify.BatchTargetParams{
+					{Space: "space-123", Role: "SpaceManager"},
+					{Scope: "some.scope"},
+				},
+			}
+
+			Expect(params.ToAudience()).To(Equal(services.Audience{
+				Name: "Operators",
+				Targets: []services.BatchTarget{
+					{Type: services.BatchTargetSpace, ID: "space-123", Role: "SpaceManager"},
+					{Type: services.BatchTargetScope, ID: "some.scope"},
+				},
+			}))
+		})
+	})
+})
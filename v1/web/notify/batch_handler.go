@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type batchExecutor interface {
+	ExecuteBatch(conn ConnectionInterface, req *http.Request, context stack.Context, dispatcher BatchDispatcher, vcapRequestID string) (response []byte, err error)
+	DryRunBatch(conn ConnectionInterface, req *http.Request, context stack.Context, dispatcher BatchDispatcher, vcapRequestID string, page models.Page) (response []byte, next []string, err error)
+}
+
+type BatchHandler struct {
+	errorWriter errorWriter
+	notify      batchExecutor
+	dispatcher  BatchDispatcher
+}
+
+func NewBatchHandler(notify batchExecutor, errWriter errorWriter, dispatcher BatchDispatcher) BatchHandler {
+	return BatchHandler{
+		errorWriter: errWriter,
+		notify:      notify,
+		dispatcher:  dispatcher,
+	}
+}
+
+func (h BatchHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	conn := context.Get("database").(DatabaseInterface).Connection()
+	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
+	query := req.URL.Query()
+
+	dryRun, err := parseDryRun(query.Get("dry_run"))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	if !dryRun {
+		output, err := h.notify.ExecuteBatch(conn, req, context, h.dispatcher, vcapRequestID)
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(output)
+		return
+	}
+
+	page, err := webutil.NewPage(query)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output, next, err := h.notify.DryRunBatch(conn, req, context, h.dispatcher, vcapRequestID, page)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	webutil.WriteNextPageLink(w, req, page, next)
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
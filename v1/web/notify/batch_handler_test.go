@@ -0,0 +1,114 @@
+package notify_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BatchHandler", func() {
+	var (
+		handler     notify.BatchHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		notifyObj   *mocks.Notify
+		context     stack.Context
+		connection  *mocks.Connection
+		dispatcher  *mocks.BatchDispatcher
+		errorWriter *mocks.ErrorWriter
+	)
+
+	BeforeEach(func() {
+		writer = httptest.NewRecorder()
+		request = &http.Request{URL: &url.URL{Path: "/batches"}}
+		dispatcher = mocks.NewBatchDispatcher()
+		errorWriter = mocks.NewErrorWriter()
+
+		database := mocks.NewDatabase()
+		connection = mocks.NewConnection()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set(notify.VCAPRequestIDKey, "some-request-id")
+
+		notifyObj = mocks.NewNotify()
+		handler = notify.NewBatchHandler(notifyObj, errorWriter, dispatcher)
+	})
+
+	It("writes the response of the batch", func() {
+		notifyObj.ExecuteBatchCall.Returns.Response = []byte("whut")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(Equal("whut"))
+	})
+
+	It("delegates to the notifyObj object with the correct arguments", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(reflect.ValueOf(notifyObj.ExecuteBatchCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
+		Expect(notifyObj.ExecuteBatchCall.Receives.Request).To(Equal(request))
+		Expect(notifyObj.ExecuteBatchCall.Receives.Context).To(Equal(context))
+		Expect(notifyObj.ExecuteBatchCall.Receives.Dispatcher).To(Equal(dispatcher))
+		Expect(notifyObj.ExecuteBatchCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+	})
+
+	It("propagates errors", func() {
+		notifyObj.ExecuteBatchCall.Returns.Error = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+	})
+
+	Context("when a dry run is requested", func() {
+		BeforeEach(func() {
+			request.URL.RawQuery = "dry_run=true"
+		})
+
+		It("previews the recipients instead of sending the batch", func() {
+			notifyObj.DryRunBatchCall.Returns.Response = []byte("preview")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.String()).To(Equal("preview"))
+
+			Expect(reflect.ValueOf(notifyObj.DryRunBatchCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
+			Expect(notifyObj.DryRunBatchCall.Receives.Request).To(Equal(request))
+			Expect(notifyObj.DryRunBatchCall.Receives.Dispatcher).To(Equal(dispatcher))
+			Expect(notifyObj.DryRunBatchCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+			Expect(notifyObj.DryRunBatchCall.Receives.Page).To(Equal(models.Page{Limit: webutil.DefaultPageLimit}))
+			Expect(notifyObj.ExecuteBatchCall.Receives.Request).To(BeNil())
+		})
+
+		It("links to the next page of recipients", func() {
+			notifyObj.DryRunBatchCall.Returns.Next = []string{"user-123"}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Header().Get("Link")).To(ContainSubstring(`rel="next"`))
+		})
+
+		It("propagates the error", func() {
+			notifyObj.DryRunBatchCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+})
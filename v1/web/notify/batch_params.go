@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cloudfoundry-incubator/notifications/address"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+)
+
+// MaxBatchTargets is the largest number of targets a single batch may name.
+const MaxBatchTargets = 1000
+
+// BatchParams is a notify request sent to a list of targets. The message
+// fields are those of any other notify request.
+type BatchParams struct {
+	NotifyParams
+	Targets []BatchTargetParams `json:"targets"`
+}
+
+// BatchTargetParams names exactly one of a user GUID, an email address, a
+// space or organization GUID with an optional role, or a UAA scope.
+type BatchTargetParams struct {
+	User         string `json:"user,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Space        string `json:"space,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Role         string `json:"role,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+func NewBatchParams(body io.ReadCloser) (BatchParams, error) {
+	defer body.Close()
+
+	batch := BatchParams{}
+
+	buffer := bytes.NewBuffer([]byte{})
+	buffer.ReadFrom(body)
+	if buffer.Len() > 0 {
+		err := json.Unmarshal(buffer.Bytes(), &batch)
+		if err != nil {
+			return batch, webutil.ParseError{}
+		}
+	}
+
+	err := batch.FormatEmailAndExtractHTML()
+	if err != nil {
+		return batch, err
+	}
+
+	return batch, nil
+}
+
+// ToTargets returns the targets of the batch in the form the batch
+// dispatcher takes them.
+func (batch BatchParams) ToTargets() []services.BatchTarget {
+	return toBatchTargets(batch.Targets)
+}
+
+func toBatchTargets(params []BatchTargetParams) []services.BatchTarget {
+	var targets []services.BatchTarget
+	for _, target := range params {
+		targets = append(targets, target.toTarget())
+	}
+
+	return targets
+}
+
+func (target BatchTargetParams) toTarget() services.BatchTarget {
+	switch {
+	case target.User != "":
+		return services.BatchTarget{Type: services.BatchTargetUser, ID: target.User}
+	case target.Email != "":
+		return services.BatchTarget{Type: services.BatchTargetEmail, ID: target.Email}
+	case target.Space != "":
+		return services.BatchTarget{Type: services.BatchTargetSpace, ID: target.Space, Role: target.Role}
+	case target.Organization != "":
+		return services.BatchTarget{Type: services.BatchTargetOrganization, ID: target.Organization, Role: target.Role}
+	default:
+		return services.BatchTarget{Type: services.BatchTargetScope, ID: target.Scope}
+	}
+}
+
+func newBatchTargetParams(target services.BatchTarget) BatchTargetParams {
+	switch target.Type {
+	case services.BatchTargetUser:
+		return BatchTargetParams{User: target.ID}
+	case services.BatchTargetEmail:
+		return BatchTargetParams{Email: target.ID}
+	case services.BatchTargetSpace:
+		return BatchTargetParams{Space: target.ID, Role: target.Role}
+	case services.BatchTargetOrganization:
+		return BatchTargetParams{Organization: target.ID, Role: target.Role}
+	default:
+		return BatchTargetParams{Scope: target.ID}
+	}
+}
+
+type BatchValidator struct{}
+
+func (validator BatchValidator) Validate(batch *BatchParams) bool {
+	batch.Errors = []string{}
+
+	GUIDValidator{}.checkKindIDField(&batch.NotifyParams)
+
+	if missingTextOrHTMLFields(&batch.NotifyParams) {
+		batch.Errors = append(batch.Errors, `"text", "html" or "markdown" fields must be supplied`)
+	}
+
+	batch.Errors = append(batch.Errors, checkTargets(batch.Targets)...)
+
+	checkLocaleField(&batch.NotifyParams)
+	checkDataField(&batch.NotifyParams)
+	checkExpiryFields(&batch.NotifyParams)
+	checkDedupKeyField(&batch.NotifyParams)
+
+	return len(batch.Errors) == 0
+}
+
+// checkTargets validates a list of targets, as sent in a batch or kept in
+// an audience, and canonicalizes the email addresses it names.
+func checkTargets(targets []BatchTargetParams) []string {
+	var errs []string
+
+	switch {
+	case len(targets) == 0:
+		errs = append(errs, `"targets" is a required field`)
+	case len(targets) > MaxBatchTargets:
+		errs = append(errs, fmt.Sprintf(`"targets" must not name more than %d targets`, MaxBatchTargets))
+	}
+
+	for i := range targets {
+		if err := checkTarget(&targets[i], i); err != "" {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func checkTarget(target *BatchTargetParams, index int) string {
+	field := fmt.Sprintf(`"targets[%d]"`, index)
+
+	var named int
+	for _, value := range []string{target.User, target.Email, target.Space, target.Organization, target.Scope} {
+		if value != "" {
+			named++
+		}
+	}
+
+	if named != 1 {
+		return field + ` must name exactly one of "user", "email", "space", "organization" or "scope"`
+	}
+
+	if target.Role != "" {
+		var roleValidator GUIDValidator
+		switch {
+		case target.Organization != "":
+			roleValidator = GUIDValidator{Roles: validOrganizationRoles}
+		case target.Space != "":
+			roleValidator = GUIDValidator{Roles: validSpaceRoles}
+		default:
+			return field + ` may only name a "role" for a space or an organization`
+		}
+
+		if roleValidator.invalidRoleField(target.Role) {
+			return field + ` "role" must be ` + roleValidator.rolesDescription()
+		}
+	}
+
+	if target.Email != "" {
+		email, err := address.Parse(target.Email)
+		if err != nil {
+			return fmt.Sprintf(`%s "email" is improperly formatted: %s`, field, err)
+		}
+
+		target.Email = email.Email()
+	}
+
+	return ""
+}
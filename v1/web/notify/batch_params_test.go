@@ -0,0 +1,165 @@
+package notify_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BatchParams", func() {
+	Describe("NewBatchParams", func() {
+		It("parses the message fields and targets", func() {
+			body := io.NopCloser(strings.NewReader(`{
+				"kind_id": "some-kind",
+				"subject": "Hello",
+				"html": "<html><body><p>Hi</p></body></html>",
+				"targets": [
+					{"user": "user-123"},
+					{"email": "someone@example.com"},
+					{"organization": "org-123", "role": "OrgManager"}
+				]
+			}`))
+
+			params, err := notify.NewBatchParams(body)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(params.KindID).To(Equal("some-kind"))
+			Expect(params.Subject).To(Equal("Hello"))
+			Expect(params.ParsedHTML.BodyContent).To(Equal("<p>Hi</p>"))
+			Expect(params.Targets).To(Equal([]notify.BatchTargetParams{
+				{User: "user-123"},
+				{Email: "someone@example.com"},
+				{Organization: "org-123", Role: "OrgManager"},
+			}))
+		})
+
+		It("returns a parse error when the body is not JSON", func() {
+			_, err := notify.NewBatchParams(io.NopCloser(strings.NewReader("not json")))
+			Expect(err).To(Equal(webutil.ParseError{}))
+		})
+	})
+
+	Describe("ToTargets", func() {
+		It("returns the targets for the batch dispatcher", func() {
+			params := notify.BatchParams{
+				Targets: []notify.BatchTargetParams{
+					{User: "user-123"},
+					{Email: "someone@example.com"},
+					{Space: "space-123", Role: "SpaceManager"},
+					{Organization: "org-123", Role: "OrgAuditor"},
+					{Scope: "some.scope"},
+				},
+			}
+
+			Expect(params.ToTargets()).To(Equal([]services.BatchTarget{
+				{Type: services.BatchTargetUser, ID: "user-123"},
+				{Type: services.BatchTargetEmail, ID: "someone@example.com"},
+				{Type: services.BatchTargetSpace, ID: "space-123", Role: "SpaceManager"},
+				{Type: services.BatchTargetOrganization, ID: "org-123", Role: "OrgAuditor"},
+				{Type: services.BatchTargetScope, ID: "some.scope"},
+			}))
+		})
+	})
+})
+
+var _ = Describe("BatchValidator", func() {
+	var (
+		params    *notify.BatchParams
+		validator notify.BatchValidator
+	)
+
+	BeforeEach(func() {
+		params = &notify.BatchParams{
+			NotifyParams: notify.NotifyParams{
+				KindID: "some-kind",
+				Text:   "some text",
+			},
+			Targets: []notify.BatchTargetParams{
+				{User: "user-123"},
+			},
+		}
+		validator = notify.BatchValidator{}
+	})
+
+	It("accepts a valid batch", func() {
+		Expect(validator.Validate(params)).To(BeTrue())
+		Expect(params.Errors).To(BeEmpty())
+	})
+
+	It("validates the message fields", func() {
+		params.KindID = ""
+		params.Text = ""
+
+		Expect(validator.Validate(params)).To(BeFalse())
+		Expect(params.Errors).To(ConsistOf(
+			`"kind_id" is a required field`,
+			`"text", "html" or "markdown" fields must be supplied`,
+		))
+	})
+
+	It("requires at least one target", func() {
+		params.Targets = nil
+
+		Expect(validator.Validate(params)).To(BeFalse())
+		Expect(params.Errors).To(ConsistOf(`"targets" is a required field`))
+	})
+
+	It("limits the number of targets", func() {
+		params.Targets = nil
+		for i := 0; i <= notify.MaxBatchTargets; i++ {
+			params.Targets = append(params.Targets, notify.BatchTargetParams{User: fmt.Sprintf("user-%d", i)})
+		}
+
+		Expect(validator.Validate(params)).To(BeFalse())
+		Expect(params.Errors).To(ConsistOf(`"targets" must not name more than 1000 targets`))
+	})
+
+	It("requires each target to name exactly one recipient", func() {
+		params.Targets = []notify.BatchTargetParams{
+			{},
+			{User: "user-123", Space: "space-123"},
+		}
+
+		Expect(validator.Validate(params)).To(BeFalse())
+		Expect(params.Errors).To(ConsistOf(
+			`"targets[0]" must name exactly one of "user", "email", "space", "organization" or "scope"`,
+			`"targets[1]" must name exactly one of "user", "email", "space", "organization" or "scope"`,
+		))
+	})
+
+	It("validates roles", func() {
+		params.Targets = []notify.BatchTargetParams{
+			{User: "user-123", Role: "OrgManager"},
+			{Organization: "org-123", Role: "Janitor"},
+			{Organization: "org-123", Role: "BillingManager"},
+			{Space: "space-123", Role: "OrgManager"},
+			{Space: "space-123", Role: "SpaceDeveloper"},
+		}
+
+		Expect(validator.Validate(params)).To(BeFalse())
+		Expect(params.Errors).To(ConsistOf(
+			`"targets[0]" may only name a "role" for a space or an organization`,
+			`"targets[1]" "role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`,
+			`"targets[3]" "role" must be "SpaceDeveloper", "SpaceManager", "SpaceAuditor" or unset`,
+		))
+	})
+
+	It("validates and normalizes email addresses", func() {
+		params.Targets = []notify.BatchTargetParams{
+			{Email: "Someone <someone@example.com>"},
+			{Email: "not-an-email"},
+		}
+
+		Expect(validator.Validate(params)).To(BeFalse())
+		Expect(params.Errors).To(HaveLen(1))
+		Expect(params.Errors[0]).To(HavePrefix(`"targets[1]" "email" is improperly formatted`))
+		Expect(params.Targets[0].Email).To(Equal("someone@example.com"))
+	})
+})
@@ -3,6 +3,7 @@ package notify
 import (
 	"net/http"
 
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/ryanmoran/stack"
 )
@@ -14,6 +15,7 @@ const (
 
 type notifyExecutor interface {
 	Execute(conn ConnectionInterface, req *http.Request, context stack.Context, guid string, strategy Dispatcher, validator ValidatorInterface, vcapRequestID string) (response []byte, err error)
+	DryRun(conn ConnectionInterface, req *http.Request, context stack.Context, guid string, strategy Dispatcher, validator ValidatorInterface, vcapRequestID string, page models.Page) (response []byte, next []string, err error)
 }
 
 type errorWriter interface {
@@ -22,6 +24,7 @@ type errorWriter interface {
 
 type Dispatcher interface {
 	Dispatch(dispatch services.Dispatch) ([]services.Response, error)
+	Resolve(dispatch services.Dispatch) (services.Resolution, error)
 }
 
 type EmailHandler struct {
@@ -39,16 +42,5 @@ func NewEmailHandler(notify notifyExecutor, errWriter errorWriter, strategy Disp
 }
 
 func (h EmailHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
-	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
-	database := context.Get("database").(DatabaseInterface)
-	conn := database.Connection()
-
-	output, err := h.notify.Execute(conn, req, context, "", h.strategy, EmailValidator{}, vcapRequestID)
-	if err != nil {
-		h.errorWriter.Write(w, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(output)
+	serveNotify(w, req, context, h.notify, h.errorWriter, "", h.strategy, EmailValidator{})
 }
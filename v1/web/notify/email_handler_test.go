@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
@@ -36,7 +37,7 @@ var _ = Describe("EmailHandler", func() {
 			database = mocks.NewDatabase()
 			database.ConnectionCall.Returns.Connection = connection
 
-			request = &http.Request{}
+			request = &http.Request{URL: &url.URL{Path: "/emails"}}
 			strategy = mocks.NewStrategy()
 
 			context = stack.NewContext()
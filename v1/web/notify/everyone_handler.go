@@ -21,15 +21,5 @@ func NewEveryoneHandler(notify notifyExecutor, errWriter errorWriter, strategy D
 }
 
 func (h EveryoneHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
-	connection := context.Get("database").(DatabaseInterface).Connection()
-	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
-
-	output, err := h.notify.Execute(connection, req, context, "", h.strategy, GUIDValidator{}, vcapRequestID)
-	if err != nil {
-		h.errorWriter.Write(w, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(output)
+	serveNotify(w, req, context, h.notify, h.errorWriter, "", h.strategy, GUIDValidator{})
 }
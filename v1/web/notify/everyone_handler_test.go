@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
@@ -30,7 +31,7 @@ var _ = Describe("EveryoneHandler", func() {
 		BeforeEach(func() {
 			errorWriter = mocks.NewErrorWriter()
 			writer = httptest.NewRecorder()
-			request = &http.Request{}
+			request = &http.Request{URL: &url.URL{Path: "/everyone"}}
 			strategy = mocks.NewStrategy()
 
 			connection = mocks.NewConnection()
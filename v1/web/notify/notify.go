@@ -24,15 +24,21 @@ type registrar interface {
 	Prune(services.ConnectionInterface, models.Client, []models.Kind) error
 }
 
+type recipientsPreviewer interface {
+	Preview(conn services.ConnectionInterface, dispatch services.Dispatch, resolution services.Resolution, page models.Page) (services.RecipientsPreview, []string, error)
+}
+
 type Notify struct {
 	finder    clientAndKindFinder
 	registrar registrar
+	previewer recipientsPreviewer
 }
 
-func NewNotify(finder clientAndKindFinder, registrar registrar) Notify {
+func NewNotify(finder clientAndKindFinder, registrar registrar, previewer recipientsPreviewer) Notify {
 	return Notify{
 		finder:    finder,
 		registrar: registrar,
+		previewer: previewer,
 	}
 }
 
@@ -40,6 +46,50 @@ type ValidatorInterface interface {
 	Validate(*NotifyParams) bool
 }
 
+type BatchDispatcher interface {
+	Dispatch(dispatch services.Dispatch, targets []services.BatchTarget) (services.BatchResult, error)
+	Resolve(dispatch services.Dispatch, targets []services.BatchTarget) (services.Resolution, []services.BatchTargetResult)
+}
+
+type BatchOutput struct {
+	BatchID    string              `json:"batch_id"`
+	AudienceID string              `json:"audience_id,omitempty"`
+	Targets    []BatchTargetOutput `json:"targets"`
+}
+
+type BatchTargetOutput struct {
+	BatchTargetParams
+	Notifications []services.Response `json:"notifications"`
+	Duplicates    int                 `json:"duplicates"`
+	Error         string              `json:"error,omitempty"`
+}
+
+// DryRunOutput lists the recipients of a dry run. Dry runs of batches also
+// report what each target resolved to.
+type DryRunOutput struct {
+	DryRun      bool                    `json:"dry_run"`
+	AudienceID  string                  `json:"audience_id,omitempty"`
+	Total       int                     `json:"total"`
+	Deliverable int                     `json:"deliverable"`
+	Suppressed  int                     `json:"suppressed"`
+	Skipped     map[string]int          `json:"skipped"`
+	Targets     []DryRunTargetOutput    `json:"targets,omitempty"`
+	Recipients  []DryRunRecipientOutput `json:"recipients"`
+}
+
+type DryRunTargetOutput struct {
+	BatchTargetParams
+	Duplicates int    `json:"duplicates"`
+	Error      string `json:"error,omitempty"`
+}
+
+type DryRunRecipientOutput struct {
+	Recipient  string `json:"recipient"`
+	Email      string `json:"email,omitempty"`
+	Status     string `json:"status"`
+	SkipReason string `json:"skip_reason,omitempty"`
+}
+
 func (h Notify) Execute(connection ConnectionInterface, req *http.Request, context stack.Context,
 	guid string, strategy Dispatcher, validator ValidatorInterface, vcapRequestID string) ([]byte, error) {
 
@@ -52,6 +102,323 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 		return []byte{}, webutil.ValidationError{Err: errors.New(strings.Join(parameters.Errors, ","))}
 	}
 
+	dispatch, err := h.newDispatch(connection, context, parameters, vcapRequestID)
+	if err != nil {
+		return []byte{}, err
+	}
+	dispatch.GUID = guid
+
+	responses, err := strategy.Dispatch(dispatch)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	output, err := json.Marshal(responses)
+	if err != nil {
+		panic(err)
+	}
+
+	return output, nil
+}
+
+// DryRun resolves the recipients of a notify request the way Execute does
+// and reports which of them would be skipped, without registering the
+// client or enqueuing anything. It returns a page of the recipients along
+// with the key of the next page.
+func (h Notify) DryRun(connection ConnectionInterface, req *http.Request, context stack.Context,
+	guid string, strategy Dispatcher, validator ValidatorInterface, vcapRequestID string, page models.Page) ([]byte, []string, error) {
+
+	parameters, err := NewNotifyParams(req.Body)
+	if err != nil {
+		return []byte{}, nil, err
+	}
+
+	if !validator.Validate(&parameters) {
+		return []byte{}, nil, webutil.ValidationError{Err: errors.New(strings.Join(parameters.Errors, ","))}
+	}
+
+	dispatch, _, _, err := h.prepareDispatch(connection, context, parameters, vcapRequestID)
+	if err != nil {
+		return []byte{}, nil, err
+	}
+	dispatch.GUID = guid
+
+	resolution, err := strategy.Resolve(dispatch)
+	if err != nil {
+		return []byte{}, nil, err
+	}
+
+	preview, next, err := h.previewer.Preview(connection, dispatch, resolution, page)
+	if err != nil {
+		return []byte{}, nil, err
+	}
+
+	output, err := json.Marshal(newDryRunOutput(preview))
+	if err != nil {
+		panic(err)
+	}
+
+	return output, next, nil
+}
+
+// ExecuteBatch sends one notification to every target of a batch. Sending
+// to email addresses takes the same emails.write scope as the /emails
+// endpoint.
+func (h Notify) ExecuteBatch(connection ConnectionInterface, req *http.Request, context stack.Context,
+	dispatcher BatchDispatcher, vcapRequestID string) ([]byte, error) {
+
+	parameters, err := NewBatchParams(req.Body)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	batch, err := h.executeBatch(connection, context, parameters, dispatcher, vcapRequestID)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	output, err := json.Marshal(batch)
+	if err != nil {
+		panic(err)
+	}
+
+	return output, nil
+}
+
+// DryRunBatch resolves the targets of a batch the way ExecuteBatch does and
+// previews their recipients the way DryRun does, without registering the
+// client or enqueuing anything.
+func (h Notify) DryRunBatch(connection ConnectionInterface, req *http.Request, context stack.Context,
+	dispatcher BatchDispatcher, vcapRequestID string, page models.Page) ([]byte, []string, error) {
+
+	parameters, err := NewBatchParams(req.Body)
+	if err != nil {
+		return []byte{}, nil, err
+	}
+
+	dryRun, next, err := h.dryRunBatch(connection, context, parameters, dispatcher, vcapRequestID, page)
+	if err != nil {
+		return []byte{}, nil, err
+	}
+
+	output, err := json.Marshal(dryRun)
+	if err != nil {
+		panic(err)
+	}
+
+	return output, next, nil
+}
+
+// ExecuteAudience sends one notification to the targets of a saved
+// audience, exactly as if they had been sent as a batch.
+func (h Notify) ExecuteAudience(connection ConnectionInterface, req *http.Request, context stack.Context,
+	audience services.Audience, dispatcher BatchDispatcher, vcapRequestID string) ([]byte, error) {
+
+	parameters, err := newAudienceBatchParams(req, audience)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	batch, err := h.executeBatch(connection, context, parameters, dispatcher, vcapRequestID)
+	if err != nil {
+		return []byte{}, err
+	}
+	batch.AudienceID = audience.ID
+
+	output, err := json.Marshal(batch)
+	if err != nil {
+		panic(err)
+	}
+
+	return output, nil
+}
+
+// DryRunAudience previews the recipients of a saved audience the way
+// DryRunBatch previews those of a batch.
+func (h Notify) DryRunAudience(connection ConnectionInterface, req *http.Request, context stack.Context,
+	audience services.Audience, dispatcher BatchDispatcher, vcapRequestID string, page models.Page) ([]byte, []string, error) {
+
+	parameters, err := newAudienceBatchParams(req, audience)
+	if err != nil {
+		return []byte{}, nil, err
+	}
+
+	dryRun, next, err := h.dryRunBatch(connection, context, parameters, dispatcher, vcapRequestID, page)
+	if err != nil {
+		return []byte{}, nil, err
+	}
+	dryRun.AudienceID = audience.ID
+
+	output, err := json.Marshal(dryRun)
+	if err != nil {
+		panic(err)
+	}
+
+	return output, next, nil
+}
+
+func newAudienceBatchParams(req *http.Request, audience services.Audience) (BatchParams, error) {
+	parameters, err := NewBatchParams(req.Body)
+	if err != nil {
+		return BatchParams{}, err
+	}
+
+	if len(parameters.Targets) > 0 {
+		return BatchParams{}, webutil.ValidationError{Err: errors.New(`"targets" must not be given when sending to an audience`)}
+	}
+
+	for _, target := range audience.Targets {
+		parameters.Targets = append(parameters.Targets, newBatchTargetParams(target))
+	}
+
+	return parameters, nil
+}
+
+// validateBatch checks the parameters of a batch and that the client may
+// send to every target of it.
+func validateBatch(context stack.Context, parameters *BatchParams) error {
+	if !(BatchValidator{}).Validate(parameters) {
+		return webutil.ValidationError{Err: errors.New(strings.Join(parameters.Errors, ","))}
+	}
+
+	token := context.Get("token").(*jwt.Token)
+	for _, target := range parameters.Targets {
+		if target.Email != "" && !hasScope(token.Claims["scope"], "emails.write") {
+			return webutil.UAAScopesError{Err: errors.New("UAA Scopes Error: Client does not have authority to send notifications to email addresses.")}
+		}
+	}
+
+	return nil
+}
+
+func (h Notify) dryRunBatch(connection ConnectionInterface, context stack.Context, parameters BatchParams,
+	dispatcher BatchDispatcher, vcapRequestID string, page models.Page) (DryRunOutput, []string, error) {
+
+	if err := validateBatch(context, &parameters); err != nil {
+		return DryRunOutput{}, nil, err
+	}
+
+	dispatch, _, _, err := h.prepareDispatch(connection, context, parameters.NotifyParams, vcapRequestID)
+	if err != nil {
+		return DryRunOutput{}, nil, err
+	}
+
+	resolution, targetResults := dispatcher.Resolve(dispatch, parameters.ToTargets())
+
+	preview, next, err := h.previewer.Preview(connection, dispatch, resolution, page)
+	if err != nil {
+		return DryRunOutput{}, nil, err
+	}
+
+	dryRun := newDryRunOutput(preview)
+	dryRun.Targets = []DryRunTargetOutput{}
+	for _, targetResult := range targetResults {
+		target := DryRunTargetOutput{
+			BatchTargetParams: newBatchTargetParams(targetResult.Target),
+			Duplicates:        targetResult.Duplicates,
+		}
+		if targetResult.Error != nil {
+			target.Error = targetResult.Error.Error()
+		}
+
+		dryRun.Targets = append(dryRun.Targets, target)
+	}
+
+	return dryRun, next, nil
+}
+
+func (h Notify) executeBatch(connection ConnectionInterface, context stack.Context, parameters BatchParams,
+	dispatcher BatchDispatcher, vcapRequestID string) (BatchOutput, error) {
+
+	if err := validateBatch(context, &parameters); err != nil {
+		return BatchOutput{}, err
+	}
+
+	dispatch, err := h.newDispatch(connection, context, parameters.NotifyParams, vcapRequestID)
+	if err != nil {
+		return BatchOutput{}, err
+	}
+
+	result, err := dispatcher.Dispatch(dispatch, parameters.ToTargets())
+	if err != nil {
+		return BatchOutput{}, err
+	}
+
+	batch := BatchOutput{
+		BatchID: result.ID,
+		Targets: []BatchTargetOutput{},
+	}
+
+	for _, targetResult := range result.Targets {
+		target := BatchTargetOutput{
+			BatchTargetParams: newBatchTargetParams(targetResult.Target),
+			Notifications:     targetResult.Responses,
+			Duplicates:        targetResult.Duplicates,
+		}
+		if targetResult.Error != nil {
+			target.Error = targetResult.Error.Error()
+		}
+
+		batch.Targets = append(batch.Targets, target)
+	}
+
+	return batch, nil
+}
+
+// newDryRunOutput reports recipients skipped because the user unsubscribed
+// as suppressed, and those that cannot be delivered to as skipped.
+func newDryRunOutput(preview services.RecipientsPreview) DryRunOutput {
+	dryRun := DryRunOutput{
+		DryRun:      true,
+		Total:       preview.Total,
+		Deliverable: preview.Deliverable,
+		Suppressed:  preview.Suppressed,
+		Skipped:     preview.Skipped,
+		Recipients:  []DryRunRecipientOutput{},
+	}
+
+	for _, recipient := range preview.Recipients {
+		output := DryRunRecipientOutput{
+			Recipient:  recipient.GUID,
+			Email:      recipient.Email,
+			Status:     "deliverable",
+			SkipReason: recipient.SkipReason,
+		}
+		if output.Recipient == "" {
+			output.Recipient = recipient.Email
+		}
+		switch {
+		case recipient.Suppressed():
+			output.Status = "suppressed"
+		case recipient.SkipReason != "":
+			output.Status = "skipped"
+		}
+
+		dryRun.Recipients = append(dryRun.Recipients, output)
+	}
+
+	return dryRun
+}
+
+// newDispatch looks up the client and kind of a notify request, checks that
+// the client may send it, and registers them before anything is sent.
+func (h Notify) newDispatch(connection ConnectionInterface, context stack.Context, parameters NotifyParams, vcapRequestID string) (services.Dispatch, error) {
+	dispatch, client, kind, err := h.prepareDispatch(connection, context, parameters, vcapRequestID)
+	if err != nil {
+		return services.Dispatch{}, err
+	}
+
+	err = h.registrar.Register(connection, client, []models.Kind{kind})
+	if err != nil {
+		return services.Dispatch{}, err
+	}
+
+	return dispatch, nil
+}
+
+// prepareDispatch builds the dispatch of a notify request without
+// registering its client and kind.
+func (h Notify) prepareDispatch(connection ConnectionInterface, context stack.Context, parameters NotifyParams, vcapRequestID string) (services.Dispatch, models.Client, models.Kind, error) {
 	requestReceivedTime, ok := context.Get(RequestReceivedTime).(time.Time)
 	if !ok {
 		panic("programmer error: missing RequestReceivedTime in http context")
@@ -61,28 +428,32 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 
 	tokenIssuerURL, err := url.Parse(token.Claims["iss"].(string))
 	if err != nil {
-		return []byte{}, errors.New("Token issuer URL invalid")
+		return services.Dispatch{}, models.Client{}, models.Kind{}, errors.New("Token issuer URL invalid")
 	}
 	uaaHost := tokenIssuerURL.Scheme + "://" + tokenIssuerURL.Host
 
 	client, kind, err := h.finder.ClientAndKind(context.Get("database").(DatabaseInterface), clientID, parameters.KindID)
 	if err != nil {
-		return []byte{}, err
+		return services.Dispatch{}, models.Client{}, models.Kind{}, err
 	}
 
-	if kind.Critical && !h.hasCriticalNotificationsWriteScope(token.Claims["scope"]) {
-		return []byte{}, webutil.NewCriticalNotificationError(kind.ID)
+	if kind.Critical && !hasScope(token.Claims["scope"], "critical_notifications.write") {
+		return services.Dispatch{}, models.Client{}, models.Kind{}, webutil.NewCriticalNotificationError(kind.ID)
 	}
 
-	err = h.registrar.Register(connection, client, []models.Kind{kind})
-	if err != nil {
-		return []byte{}, err
-	}
+	expiresAt := parameters.ExpiresAt
+	if expiresAt.IsZero() {
+		ttl := kind.TTL
+		if parameters.TTL != nil {
+			ttl = *parameters.TTL
+		}
 
-	var responses []services.Response
+		if ttl > 0 {
+			expiresAt = requestReceivedTime.Add(time.Duration(ttl) * time.Second).UTC()
+		}
+	}
 
-	responses, err = strategy.Dispatch(services.Dispatch{
-		GUID:       guid,
+	return services.Dispatch{
 		Connection: connection,
 		Role:       parameters.Role,
 		Client: services.DispatchClient{
@@ -90,8 +461,10 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 			Description: client.Description,
 		},
 		Kind: services.DispatchKind{
-			ID:          parameters.KindID,
-			Description: kind.Description,
+			ID:                  parameters.KindID,
+			Description:         kind.Description,
+			SkipTextAlternative: kind.SkipTextAlternative,
+			DedupWindow:         time.Duration(kind.DedupWindow) * time.Second,
 		},
 		UAAHost: uaaHost,
 		VCAPRequest: services.DispatchVCAPRequest{
@@ -99,10 +472,15 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 			ReceiptTime: requestReceivedTime,
 		},
 		Message: services.DispatchMessage{
-			To:      parameters.To,
-			ReplyTo: parameters.ReplyTo,
-			Subject: parameters.Subject,
-			Text:    parameters.Text,
+			To:        parameters.To,
+			ReplyTo:   parameters.ReplyTo,
+			Subject:   parameters.Subject,
+			Text:      parameters.Text,
+			Markdown:  parameters.Markdown,
+			Locale:    parameters.Locale,
+			Data:      parameters.Data,
+			ExpiresAt: expiresAt,
+			DedupKey:  parameters.DedupKey,
 			HTML: services.HTML{
 				BodyContent:    parameters.ParsedHTML.BodyContent,
 				BodyAttributes: parameters.ParsedHTML.BodyAttributes,
@@ -110,22 +488,12 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 				Doctype:        parameters.ParsedHTML.Doctype,
 			},
 		},
-	})
-	if err != nil {
-		return []byte{}, err
-	}
-
-	output, err := json.Marshal(responses)
-	if err != nil {
-		panic(err)
-	}
-
-	return output, nil
+	}, client, kind, nil
 }
 
-func (h Notify) hasCriticalNotificationsWriteScope(elements interface{}) bool {
+func hasScope(elements interface{}, scope string) bool {
 	for _, elem := range elements.([]interface{}) {
-		if elem.(string) == "critical_notifications.write" {
+		if elem.(string) == scope {
 			return true
 		}
 	}
@@ -3,33 +3,54 @@ package notify
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/cloudfoundry-incubator/notifications/address"
+	"github.com/cloudfoundry-incubator/notifications/locale"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 )
 
-const InvalidEmail = "<>InvalidEmail<>"
-
 var (
 	validOrganizationRoles = []string{"OrgManager", "OrgAuditor", "BillingManager"}
-	emailRegexp            = regexp.MustCompile("[^<]*<([^@]*@[^@]*)>|([^<][^@]*@[^@]*)")
+	validSpaceRoles        = []string{"SpaceDeveloper", "SpaceManager", "SpaceAuditor"}
 )
 
-type NotifyParams struct {
-	ReplyTo string `json:"reply_to"`
-	Subject string `json:"subject"`
-	Text    string `json:"text"`
-	RawHTML string `json:"html"`
-	KindID  string `json:"kind_id"`
-	To      string `json:"to"`
-	Role    string `json:"role"`
+// MaxDataSize is the largest encoded "data" object, in bytes, that a notify
+// request may carry through to the templates.
+const MaxDataSize = 64 * 1024
 
+type NotifyParams struct {
+	ReplyTo  string          `json:"reply_to"`
+	Subject  string          `json:"subject"`
+	Text     string          `json:"text"`
+	RawHTML  string          `json:"html"`
+	Markdown string          `json:"markdown"`
+	KindID   string          `json:"kind_id"`
+	To       string          `json:"to"`
+	Role     string          `json:"role"`
+	Locale   string          `json:"locale"`
+	RawData  json.RawMessage `json:"data"`
+
+	RawExpiresAt string `json:"expires_at"`
+	TTL          *int   `json:"ttl"`
+	DedupKey     string `json:"dedup_key"`
+
+	Data              map[string]interface{} `json:"-"`
+	ExpiresAt         time.Time              `json:"-"`
 	ParsedHTML        HTML
 	KindDescription   string
 	SourceDescription string
+	ToError           error `json:"-"`
+	LocaleError       error `json:"-"`
+	DataError         error `json:"-"`
+	ExpiryError       error `json:"-"`
 	Errors            []string
 }
 
@@ -71,7 +92,10 @@ func (notify *NotifyParams) parseRequestBody(body io.ReadCloser) error {
 }
 
 func (notify *NotifyParams) FormatEmailAndExtractHTML() error {
-	notify.To = EmailFormatter{}.Format(notify.To)
+	notify.formatTo()
+	notify.formatLocale()
+	notify.formatData()
+	notify.formatExpiry()
 
 	doctype, head, bodyContent, bodyAttributes, err := HTMLExtractor{}.Extract(notify.RawHTML)
 	if err != nil {
@@ -86,24 +110,100 @@ func (notify *NotifyParams) FormatEmailAndExtractHTML() error {
 	return nil
 }
 
-type EmailFormatter struct{}
+func (notify *NotifyParams) formatTo() {
+	notify.ToError = nil
+	if notify.To == "" {
+		return
+	}
 
-func (EmailFormatter) Format(email string) string {
-	if email == "" {
-		return email
+	to, err := address.Parse(notify.To)
+	if err != nil {
+		notify.ToError = err
+		return
 	}
 
-	matches := emailRegexp.FindStringSubmatch(email)
+	notify.To = to.Email()
+}
+
+func (notify *NotifyParams) formatLocale() {
+	notify.LocaleError = nil
+	if notify.Locale == "" {
+		return
+	}
 
-	if len(matches) == 0 {
-		return InvalidEmail
+	tag, err := locale.Parse(notify.Locale)
+	if err != nil {
+		notify.LocaleError = err
+		return
+	}
+
+	notify.Locale = tag
+}
+
+func (notify *NotifyParams) formatData() {
+	notify.Data = nil
+	notify.DataError = nil
+
+	raw := bytes.TrimSpace(notify.RawData)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return
+	}
+
+	if len(raw) > MaxDataSize {
+		notify.DataError = fmt.Errorf("must not exceed %d bytes", MaxDataSize)
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil {
+		notify.DataError = errors.New("must be a JSON object")
+		return
+	}
+
+	notify.Data = data
+}
+
+// formatExpiry parses when the notification stops being worth delivering.
+// A "ttl" is counted from when the request is received, so it is resolved
+// into a time once the dispatch is built.
+func (notify *NotifyParams) formatExpiry() {
+	notify.ExpiresAt = time.Time{}
+	notify.ExpiryError = nil
+
+	if notify.RawExpiresAt != "" && notify.TTL != nil {
+		notify.ExpiryError = errors.New(`"expires_at" and "ttl" must not both be given`)
+		return
+	}
+
+	if notify.TTL != nil && *notify.TTL <= 0 {
+		notify.ExpiryError = errors.New(`"ttl" must be a positive number of seconds`)
+		return
+	}
+
+	if notify.TTL != nil && *notify.TTL > models.MaxTTL {
+		notify.ExpiryError = fmt.Errorf(`"ttl" must not be more than %d seconds`, models.MaxTTL)
+		return
+	}
+
+	if notify.RawExpiresAt == "" {
+		return
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, notify.RawExpiresAt)
+	if err != nil {
+		notify.ExpiryError = errors.New(`"expires_at" must be a time such as "2016-03-04T15:04:05Z"`)
+		return
 	}
 
-	if matches[1] != "" {
-		return matches[1]
+	if !expiresAt.After(time.Now()) {
+		notify.ExpiryError = errors.New(`"expires_at" must be in the future`)
+		return
 	}
 
-	return matches[2]
+	notify.ExpiresAt = expiresAt.UTC()
 }
 
 type HTMLExtractor struct{}
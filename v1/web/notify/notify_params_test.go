@@ -1,10 +1,15 @@
 package notify_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/address"
+	"github.com/cloudfoundry-incubator/notifications/locale"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -53,12 +58,22 @@ var _ = Describe("NotifyParams", func() {
 				Expect(parameters.To).To(Equal("user@example.com"))
 			})
 
-			It("sets the to field to InvalidEmail cannot be parsed", func() {
+			It("records an error naming the address when it cannot be parsed", func() {
 				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
                     "to": "<The User"
 				}`)))
 				Expect(err).NotTo(HaveOccurred())
-				Expect(parameters.To).To(Equal(notify.InvalidEmail))
+				Expect(parameters.To).To(Equal("<The User"))
+				Expect(parameters.ToError).To(MatchError(address.ParseError{Address: "<The User"}))
+			})
+
+			It("normalizes quoted local parts and IDN domains", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "to": "\"The User\"@Bücher.example"
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.To).To(Equal(`"The User"@xn--bcher-kva.example`))
+				Expect(parameters.ToError).NotTo(HaveOccurred())
 			})
 
 			It("Sets the To field to empty of if it is not specified", func() {
@@ -87,6 +102,133 @@ var _ = Describe("NotifyParams", func() {
 			})
 		})
 
+		Describe("locale field parsing", func() {
+			It("canonicalizes the locale", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "locale": "de_ch"
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.Locale).To(Equal("de-CH"))
+				Expect(parameters.LocaleError).NotTo(HaveOccurred())
+			})
+
+			It("records an error naming the locale when it cannot be parsed", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "locale": "not a locale"
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.LocaleError).To(MatchError(locale.ParseError{Locale: "not a locale"}))
+			})
+		})
+
+		Describe("expiry field parsing", func() {
+			It("parses the expires_at time", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "expires_at": "2099-03-04T15:04:05-08:00"
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.ExpiryError).NotTo(HaveOccurred())
+				Expect(parameters.ExpiresAt).To(Equal(time.Date(2099, time.March, 4, 23, 4, 5, 0, time.UTC)))
+			})
+
+			It("keeps the ttl to be resolved when the request is received", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "ttl": 300
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.ExpiryError).NotTo(HaveOccurred())
+				Expect(*parameters.TTL).To(Equal(300))
+				Expect(parameters.ExpiresAt.IsZero()).To(BeTrue())
+			})
+
+			It("records an error when both expires_at and ttl are given", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "expires_at": "2099-03-04T15:04:05Z",
+                    "ttl": 300
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.ExpiryError).To(MatchError(`"expires_at" and "ttl" must not both be given`))
+			})
+
+			It("records an error when the ttl is not positive", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "ttl": 0
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.ExpiryError).To(MatchError(`"ttl" must be a positive number of seconds`))
+			})
+
+			It("records an error when the ttl is more than a year", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "ttl": 9300000000
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.ExpiryError).To(MatchError(`"ttl" must not be more than 31536000 seconds`))
+			})
+
+			It("records an error when expires_at cannot be parsed", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "expires_at": "tomorrow"
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.ExpiryError).To(MatchError(`"expires_at" must be a time such as "2016-03-04T15:04:05Z"`))
+			})
+
+			It("records an error when expires_at has already passed", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "expires_at": "2001-03-04T15:04:05Z"
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.ExpiryError).To(MatchError(`"expires_at" must be in the future`))
+			})
+		})
+
+		Describe("data field parsing", func() {
+			It("decodes the data object, keeping numbers as they were written", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "data": {
+                        "app_name": "banana",
+                        "instances": [{"index": 0}, {"index": 1}]
+                    }
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.DataError).NotTo(HaveOccurred())
+				Expect(parameters.Data).To(Equal(map[string]interface{}{
+					"app_name": "banana",
+					"instances": []interface{}{
+						map[string]interface{}{"index": json.Number("0")},
+						map[string]interface{}{"index": json.Number("1")},
+					},
+				}))
+			})
+
+			It("leaves the data empty when it is not supplied or null", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "data": null
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.DataError).NotTo(HaveOccurred())
+				Expect(parameters.Data).To(BeNil())
+			})
+
+			It("records an error when the data is not an object", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "data": ["banana"]
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.DataError).To(MatchError("must be a JSON object"))
+				Expect(parameters.Data).To(BeNil())
+			})
+
+			It("records an error when the data is too large", func() {
+				body := fmt.Sprintf(`{"data": {"blob": %q}}`, strings.Repeat("a", notify.MaxDataSize))
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(body)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.DataError).To(MatchError("must not exceed 65536 bytes"))
+				Expect(parameters.Data).To(BeNil())
+			})
+		})
+
 		Describe("html parsing", func() {
 			Context("when a doctype is passed in", func() {
 				It("pulls out the doctype", func() {
@@ -209,24 +351,24 @@ var _ = Describe("NotifyParams", func() {
 
 			Context("when the to field is invalid", func() {
 				Context("when it has unmatched <", func() {
-					It("assigns <>invalidEmail<>", func() {
+					It("records a parse error", func() {
 						parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
 							"to": "<invalid email",
 							"text": "Contents of the email message"
 						}`)))
 						Expect(err).NotTo(HaveOccurred())
-						Expect(parameters.To).To(Equal(notify.InvalidEmail))
+						Expect(parameters.ToError).To(MatchError(address.ParseError{Address: "<invalid email"}))
 					})
 				})
 
 				Context("when it is missing an @", func() {
-					It("assigns <>invalidEmail<>", func() {
+					It("records a parse error", func() {
 						parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
 							"to": "invalidemail.com",
 							"text": "Contents of the email message"
 						}`)))
 						Expect(err).NotTo(HaveOccurred())
-						Expect(parameters.To).To(Equal(notify.InvalidEmail))
+						Expect(parameters.ToError).To(MatchError(address.ParseError{Address: "invalidemail.com"}))
 					})
 				})
 			})
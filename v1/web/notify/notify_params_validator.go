@@ -1,9 +1,17 @@
 package notify
 
-import "regexp"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
 
 var kindIDFormat = regexp.MustCompile(`^[0-9a-zA-Z_\-.]+$`)
 
+// MaxDedupKeyLength is the longest "dedup_key" a notify request may give.
+const MaxDedupKeyLength = 255
+
 type EmailValidator struct{}
 
 func (validator EmailValidator) Validate(notify *NotifyParams) bool {
@@ -13,18 +21,27 @@ func (validator EmailValidator) Validate(notify *NotifyParams) bool {
 		notify.Errors = append(notify.Errors, `"to" is a required field`)
 	}
 
-	if notify.To == InvalidEmail {
-		notify.Errors = append(notify.Errors, `"to" is improperly formatted`)
+	if notify.ToError != nil {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"to" is improperly formatted: %s`, notify.ToError))
 	}
 
 	if missingTextOrHTMLFields(notify) {
-		notify.Errors = append(notify.Errors, `"text" or "html" fields must be supplied`)
+		notify.Errors = append(notify.Errors, `"text", "html" or "markdown" fields must be supplied`)
 	}
 
+	checkLocaleField(notify)
+	checkDataField(notify)
+	checkExpiryFields(notify)
+	checkDedupKeyField(notify)
+
 	return len(notify.Errors) == 0
 }
 
-type GUIDValidator struct{}
+// GUIDValidator validates notify requests sent to a GUID. Roles lists the
+// roles the request may name and defaults to the organization roles.
+type GUIDValidator struct {
+	Roles []string
+}
 
 func (validator GUIDValidator) Validate(notify *NotifyParams) bool {
 	notify.Errors = []string{}
@@ -32,18 +49,59 @@ func (validator GUIDValidator) Validate(notify *NotifyParams) bool {
 	validator.checkKindIDField(notify)
 
 	if missingTextOrHTMLFields(notify) {
-		notify.Errors = append(notify.Errors, `"text" or "html" fields must be supplied`)
+		notify.Errors = append(notify.Errors, `"text", "html" or "markdown" fields must be supplied`)
 	}
 
 	if validator.invalidRoleField(notify.Role) {
-		notify.Errors = append(notify.Errors, `"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`)
+		notify.Errors = append(notify.Errors, `"role" must be `+validator.rolesDescription())
 	}
 
+	checkLocaleField(notify)
+	checkDataField(notify)
+	checkExpiryFields(notify)
+	checkDedupKeyField(notify)
+
 	return len(notify.Errors) == 0
 }
 
 func missingTextOrHTMLFields(notify *NotifyParams) bool {
-	return notify.Text == "" && notify.ParsedHTML.BodyContent == ""
+	return notify.Text == "" && notify.ParsedHTML.BodyContent == "" && notify.Markdown == ""
+}
+
+func checkLocaleField(notify *NotifyParams) {
+	if notify.LocaleError != nil {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"locale" is improperly formatted: %s`, notify.LocaleError))
+	}
+}
+
+func checkDataField(notify *NotifyParams) {
+	if notify.DataError != nil {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"data" %s`, notify.DataError))
+	}
+}
+
+func checkExpiryFields(notify *NotifyParams) {
+	if notify.ExpiryError != nil {
+		notify.Errors = append(notify.Errors, notify.ExpiryError.Error())
+	}
+}
+
+func checkDedupKeyField(notify *NotifyParams) {
+	if utf8.RuneCountInString(notify.DedupKey) > MaxDedupKeyLength {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"dedup_key" must not be longer than %d characters`, MaxDedupKeyLength))
+	}
+}
+
+func (validator GUIDValidator) roles() []string {
+	if validator.Roles == nil {
+		return validOrganizationRoles
+	}
+
+	return validator.Roles
+}
+
+func (validator GUIDValidator) rolesDescription() string {
+	return `"` + strings.Join(validator.roles(), `", "`) + `" or unset`
 }
 
 func (validator GUIDValidator) invalidRoleField(roleName string) bool {
@@ -51,7 +109,7 @@ func (validator GUIDValidator) invalidRoleField(roleName string) bool {
 		return false
 	}
 
-	for _, role := range validOrganizationRoles {
+	for _, role := range validator.roles() {
 		if roleName == role {
 			return false
 		}
@@ -1,6 +1,11 @@
 package notify_test
 
 import (
+	"errors"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/address"
+	"github.com/cloudfoundry-incubator/notifications/locale"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -38,9 +43,14 @@ var _ = Describe("Validator", func() {
 				Expect(validator.Validate(params)).To(BeFalse())
 				Expect(len(params.Errors)).To(Equal(2))
 				Expect(params.Errors).To(ContainElement(`"to" is a required field`))
-				Expect(params.Errors).To(ContainElement(`"text" or "html" fields must be supplied`))
+				Expect(params.Errors).To(ContainElement(`"text", "html" or "markdown" fields must be supplied`))
 
 				params.To = "otherUser@example.com"
+				params.Markdown = "Contents of **this** email message"
+
+				Expect(validator.Validate(params)).To(BeTrue())
+
+				params.Markdown = ""
 				params.ParsedHTML = notify.HTML{BodyContent: "<p>Contents of this email message</p>"}
 
 				Expect(validator.Validate(params)).To(BeTrue())
@@ -49,11 +59,53 @@ var _ = Describe("Validator", func() {
 
 			Context("When the notify params object finds an invalid email", func() {
 				It("Reports a validation error", func() {
-					params.To = notify.InvalidEmail
+					params.To = "invalidemail.com"
+					params.ToError = address.ParseError{Address: "invalidemail.com"}
+
+					Expect(validator.Validate(params)).To(BeFalse())
+					Expect(len(params.Errors)).To(Equal(1))
+					Expect(params.Errors).To(ContainElement(`"to" is improperly formatted: "invalidemail.com" is not a valid email address`))
+				})
+			})
+
+			Context("When the notify params object finds an invalid locale", func() {
+				It("Reports a validation error", func() {
+					params.Locale = "german"
+					params.LocaleError = locale.ParseError{Locale: "german"}
+
+					Expect(validator.Validate(params)).To(BeFalse())
+					Expect(len(params.Errors)).To(Equal(1))
+					Expect(params.Errors).To(ContainElement(`"locale" is improperly formatted: "german" is not a valid locale`))
+				})
+			})
+
+			Context("When the notify params object finds an invalid expiry", func() {
+				It("Reports a validation error", func() {
+					params.ExpiryError = errors.New(`"expires_at" must be in the future`)
+
+					Expect(validator.Validate(params)).To(BeFalse())
+					Expect(len(params.Errors)).To(Equal(1))
+					Expect(params.Errors).To(ContainElement(`"expires_at" must be in the future`))
+				})
+			})
+
+			Context("When the dedup key is too long", func() {
+				It("Reports a validation error", func() {
+					params.DedupKey = strings.Repeat("k", 256)
 
 					Expect(validator.Validate(params)).To(BeFalse())
 					Expect(len(params.Errors)).To(Equal(1))
-					Expect(params.Errors).To(ContainElement(`"to" is improperly formatted`))
+					Expect(params.Errors).To(ContainElement(`"dedup_key" must not be longer than 255 characters`))
+				})
+			})
+
+			Context("When the notify params object finds invalid data", func() {
+				It("Reports a validation error", func() {
+					params.DataError = errors.New("must be a JSON object")
+
+					Expect(validator.Validate(params)).To(BeFalse())
+					Expect(len(params.Errors)).To(Equal(1))
+					Expect(params.Errors).To(ContainElement(`"data" must be a JSON object`))
 				})
 			})
 		})
@@ -90,9 +142,14 @@ var _ = Describe("Validator", func() {
 				Expect(validator.Validate(params)).To(BeFalse())
 				Expect(len(params.Errors)).To(Equal(2))
 				Expect(params.Errors).To(ContainElement(`"kind_id" is a required field`))
-				Expect(params.Errors).To(ContainElement(`"text" or "html" fields must be supplied`))
+				Expect(params.Errors).To(ContainElement(`"text", "html" or "markdown" fields must be supplied`))
 
 				params.KindID = "something"
+				params.Markdown = "*banana*"
+
+				Expect(validator.Validate(params)).To(BeTrue())
+
+				params.Markdown = ""
 				params.ParsedHTML.BodyContent = "<p>banana</p>"
 
 				Expect(validator.Validate(params)).To(BeTrue())
@@ -124,6 +181,37 @@ var _ = Describe("Validator", func() {
 				Expect(len(params.Errors)).To(Equal(1))
 				Expect(params.Errors).To(ContainElement(`"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`))
 			})
+
+			It("validates the role against the roles it was given", func() {
+				validator = notify.GUIDValidator{Roles: []string{"SpaceDeveloper", "SpaceManager", "SpaceAuditor"}}
+
+				for _, role := range []string{"SpaceDeveloper", "SpaceManager", "SpaceAuditor", ""} {
+					params.Role = role
+					Expect(validator.Validate(params)).To(BeTrue())
+					Expect(len(params.Errors)).To(Equal(0))
+				}
+
+				params.Role = "OrgManager"
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(len(params.Errors)).To(Equal(1))
+				Expect(params.Errors).To(ContainElement(`"role" must be "SpaceDeveloper", "SpaceManager", "SpaceAuditor" or unset`))
+			})
+
+			It("validates that the locale is properly formatted", func() {
+				params.LocaleError = locale.ParseError{Locale: "german"}
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(len(params.Errors)).To(Equal(1))
+				Expect(params.Errors).To(ContainElement(`"locale" is improperly formatted: "german" is not a valid locale`))
+			})
+
+			It("validates the data", func() {
+				params.DataError = errors.New("must not exceed 65536 bytes")
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(len(params.Errors)).To(Equal(1))
+				Expect(params.Errors).To(ContainElement(`"data" must not exceed 65536 bytes`))
+			})
 		})
 	})
 })
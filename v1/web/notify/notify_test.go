@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
@@ -29,6 +30,7 @@ var _ = Describe("Notify", func() {
 				finder          *mocks.NotificationsFinder
 				validator       *mocks.Validator
 				registrar       *mocks.Registrar
+				previewer       *mocks.RecipientsPreviewer
 				request         *http.Request
 				rawToken        string
 				client          models.Client
@@ -49,23 +51,30 @@ var _ = Describe("Notify", func() {
 					Description: "Health Monitor",
 				}
 				kind = models.Kind{
-					ID:          "test_email",
-					Description: "Instance Down",
-					ClientID:    "mister-client",
-					Critical:    true,
+					ID:                  "test_email",
+					Description:         "Instance Down",
+					ClientID:            "mister-client",
+					Critical:            true,
+					SkipTextAlternative: true,
+					DedupWindow:         60,
 				}
 				finder = mocks.NewNotificationsFinder()
 				finder.ClientAndKindCall.Returns.Client = client
 				finder.ClientAndKindCall.Returns.Kind = kind
 
 				registrar = mocks.NewRegistrar()
-
-				body, err := json.Marshal(map[string]string{
-					"kind_id":  "test_email",
-					"text":     "This is the plain text body of the email",
-					"html":     "<!DOCTYPE html><html><head><script type='javascript'></script></head><body class='hello'><p>This is the HTML Body of the email</p><body></html>",
-					"subject":  "Your instance is down",
-					"reply_to": "me@example.com",
+				previewer = mocks.NewRecipientsPreviewer()
+
+				body, err := json.Marshal(map[string]interface{}{
+					"kind_id":   "test_email",
+					"text":      "This is the plain text body of the email",
+					"html":      "<!DOCTYPE html><html><head><script type='javascript'></script></head><body class='hello'><p>This is the HTML Body of the email</p><body></html>",
+					"subject":   "Your instance is down",
+					"reply_to":  "me@example.com",
+					"locale":    "de-CH",
+					"data":      map[string]interface{}{"app_name": "banana"},
+					"markdown":  "# Your instance is down",
+					"dedup_key": "instance-0",
 				})
 				if err != nil {
 					panic(err)
@@ -108,7 +117,7 @@ var _ = Describe("Notify", func() {
 				validator = mocks.NewValidator()
 				validator.ValidateCall.Returns.Valid = true
 
-				handler = notify.NewNotify(finder, registrar)
+				handler = notify.NewNotify(finder, registrar, previewer)
 			})
 
 			It("delegates to the strategy", func() {
@@ -124,8 +133,10 @@ var _ = Describe("Notify", func() {
 						Description: "Health Monitor",
 					},
 					Kind: services.DispatchKind{
-						ID:          "test_email",
-						Description: "Instance Down",
+						ID:                  "test_email",
+						Description:         "Instance Down",
+						SkipTextAlternative: true,
+						DedupWindow:         time.Minute,
 					},
 					UAAHost: "http://zone-uaa-host",
 					VCAPRequest: services.DispatchVCAPRequest{
@@ -133,9 +144,13 @@ var _ = Describe("Notify", func() {
 						ReceiptTime: reqReceivedTime,
 					},
 					Message: services.DispatchMessage{
-						ReplyTo: "me@example.com",
-						Subject: "Your instance is down",
-						Text:    "This is the plain text body of the email",
+						ReplyTo:  "me@example.com",
+						Subject:  "Your instance is down",
+						Text:     "This is the plain text body of the email",
+						Locale:   "de-CH",
+						Data:     map[string]interface{}{"app_name": "banana"},
+						Markdown: "# Your instance is down",
+						DedupKey: "instance-0",
 						HTML: services.HTML{
 							BodyContent:    "<p>This is the HTML Body of the email</p>",
 							BodyAttributes: `class="hello"`,
@@ -146,6 +161,53 @@ var _ = Describe("Notify", func() {
 				}))
 			})
 
+			Context("when the notification should expire", func() {
+				BeforeEach(func() {
+					kind.TTL = 600
+					finder.ClientAndKindCall.Returns.Kind = kind
+				})
+
+				It("expires it after the kind's ttl", func() {
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+
+					expiresAt := strategy.DispatchCalls[0].Receives.Dispatch.Message.ExpiresAt
+					Expect(expiresAt).To(Equal(reqReceivedTime.Add(10 * time.Minute).UTC()))
+				})
+
+				It("prefers the ttl given on the request", func() {
+					body, err := json.Marshal(map[string]interface{}{
+						"kind_id": "test_email",
+						"text":    "This is the plain text body of the email",
+						"ttl":     60,
+					})
+					Expect(err).NotTo(HaveOccurred())
+					request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+
+					_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+
+					expiresAt := strategy.DispatchCalls[0].Receives.Dispatch.Message.ExpiresAt
+					Expect(expiresAt).To(Equal(reqReceivedTime.Add(time.Minute).UTC()))
+				})
+
+				It("prefers the expires_at time given on the request", func() {
+					body, err := json.Marshal(map[string]interface{}{
+						"kind_id":    "test_email",
+						"text":       "This is the plain text body of the email",
+						"expires_at": "2099-03-04T15:04:05Z",
+					})
+					Expect(err).NotTo(HaveOccurred())
+					request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+
+					_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+
+					expiresAt := strategy.DispatchCalls[0].Receives.Dispatch.Message.ExpiresAt
+					Expect(expiresAt).To(Equal(time.Date(2099, time.March, 4, 15, 4, 5, 0, time.UTC)))
+				})
+			})
+
 			It("registers the client and kind", func() {
 				_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
 				Expect(err).NotTo(HaveOccurred())
@@ -251,4 +313,567 @@ var _ = Describe("Notify", func() {
 			})
 		})
 	})
+
+	Describe("ExecuteBatch", func() {
+		var (
+			handler         notify.Notify
+			finder          *mocks.NotificationsFinder
+			registrar       *mocks.Registrar
+			previewer       *mocks.RecipientsPreviewer
+			dispatcher      *mocks.BatchDispatcher
+			conn            *mocks.Connection
+			context         stack.Context
+			tokenHeader     map[string]interface{}
+			tokenClaims     map[string]interface{}
+			reqReceivedTime time.Time
+			body            map[string]interface{}
+		)
+
+		setToken := func() {
+			rawToken := helpers.BuildToken(tokenHeader, tokenClaims)
+			token, err := jwt.Parse(rawToken, func(*jwt.Token) (interface{}, error) {
+				return []byte(helpers.UAAPublicKey), nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			context.Set("token", token)
+		}
+
+		newRequest := func() *http.Request {
+			content, err := json.Marshal(body)
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := http.NewRequest("POST", "/batches", bytes.NewBuffer(content))
+			Expect(err).NotTo(HaveOccurred())
+
+			return request
+		}
+
+		BeforeEach(func() {
+			finder = mocks.NewNotificationsFinder()
+			finder.ClientAndKindCall.Returns.Client = models.Client{ID: "mister-client", Description: "Health Monitor"}
+			finder.ClientAndKindCall.Returns.Kind = models.Kind{ID: "test_email", Description: "Instance Down", ClientID: "mister-client"}
+
+			registrar = mocks.NewRegistrar()
+			previewer = mocks.NewRecipientsPreviewer()
+			dispatcher = mocks.NewBatchDispatcher()
+			conn = mocks.NewConnection()
+
+			reqReceivedTime, _ = time.Parse(time.RFC3339Nano, "2015-06-08T14:32:11.660762586-07:00")
+
+			context = stack.NewContext()
+			context.Set("database", mocks.NewDatabase())
+			context.Set(notify.RequestReceivedTime, reqReceivedTime)
+
+			tokenHeader = map[string]interface{}{
+				"alg": "RS256",
+			}
+			tokenClaims = map[string]interface{}{
+				"client_id": "mister-client",
+				"iss":       "http://zone-uaa-host/oauth/token",
+				"exp":       int64(3404281214),
+				"scope":     []string{"notifications.write", "emails.write"},
+			}
+			setToken()
+
+			body = map[string]interface{}{
+				"kind_id": "test_email",
+				"subject": "Your instance is down",
+				"text":    "This is the plain text body of the email",
+				"targets": []map[string]string{
+					{"user": "user-123"},
+					{"email": "Someone <someone@example.com>"},
+					{"organization": "org-123", "role": "OrgManager"},
+				},
+			}
+
+			handler = notify.NewNotify(finder, registrar, previewer)
+		})
+
+		It("dispatches the notification to every target of the batch", func() {
+			dispatcher.DispatchCall.Returns.Result = services.BatchResult{
+				ID: "batch-123",
+				Targets: []services.BatchTargetResult{
+					{
+						Target:    services.BatchTarget{Type: services.BatchTargetUser, ID: "user-123"},
+						Responses: []services.Response{{Status: "queued", Recipient: "user-123", NotificationID: "message-1"}},
+					},
+					{
+						Target:     services.BatchTarget{Type: services.BatchTargetEmail, ID: "someone@example.com"},
+						Responses:  []services.Response{},
+						Duplicates: 1,
+					},
+					{
+						Target:    services.BatchTarget{Type: services.BatchTargetOrganization, ID: "org-123", Role: "OrgManager"},
+						Responses: []services.Response{},
+						Error:     errors.New("CloudController Failure: Organization could not be found"),
+					},
+				},
+			}
+
+			output, err := handler.ExecuteBatch(conn, newRequest(), context, dispatcher, "some-request-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchJSON(`{
+				"batch_id": "batch-123",
+				"targets": [
+					{
+						"user": "user-123",
+						"notifications": [{"status": "queued", "recipient": "user-123", "notification_id": "message-1", "vcap_request_id": ""}],
+						"duplicates": 0
+					},
+					{
+						"email": "someone@example.com",
+						"notifications": [],
+						"duplicates": 1
+					},
+					{
+						"organization": "org-123",
+						"role": "OrgManager",
+						"notifications": [],
+						"duplicates": 0,
+						"error": "CloudController Failure: Organization could not be found"
+					}
+				]
+			}`))
+
+			Expect(dispatcher.DispatchCall.Receives.Targets).To(Equal([]services.BatchTarget{
+				{Type: services.BatchTargetUser, ID: "user-123"},
+				{Type: services.BatchTargetEmail, ID: "someone@example.com"},
+				{Type: services.BatchTargetOrganization, ID: "org-123", Role: "OrgManager"},
+			}))
+
+			dispatch := dispatcher.DispatchCall.Receives.Dispatch
+			Expect(dispatch.Connection).To(Equal(conn))
+			Expect(dispatch.Client).To(Equal(services.DispatchClient{ID: "mister-client", Description: "Health Monitor"}))
+			Expect(dispatch.Kind).To(Equal(services.DispatchKind{ID: "test_email", Description: "Instance Down"}))
+			Expect(dispatch.UAAHost).To(Equal("http://zone-uaa-host"))
+			Expect(dispatch.VCAPRequest).To(Equal(services.DispatchVCAPRequest{ID: "some-request-id", ReceiptTime: reqReceivedTime}))
+			Expect(dispatch.Message.Subject).To(Equal("Your instance is down"))
+			Expect(dispatch.Message.Text).To(Equal("This is the plain text body of the email"))
+
+			Expect(registrar.RegisterCall.Receives.Connection).To(Equal(conn))
+		})
+
+		It("returns a validation error when the batch is invalid", func() {
+			delete(body, "targets")
+
+			_, err := handler.ExecuteBatch(conn, newRequest(), context, dispatcher, "some-request-id")
+			Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"targets" is a required field`)}))
+			Expect(dispatcher.DispatchCall.WasCalled).To(BeFalse())
+		})
+
+		It("returns a parse error when the body is not JSON", func() {
+			request, err := http.NewRequest("POST", "/batches", strings.NewReader("this is not JSON"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = handler.ExecuteBatch(conn, request, context, dispatcher, "some-request-id")
+			Expect(err).To(Equal(webutil.ParseError{}))
+		})
+
+		It("requires the emails.write scope to send to email addresses", func() {
+			tokenClaims["scope"] = []string{"notifications.write"}
+			setToken()
+
+			_, err := handler.ExecuteBatch(conn, newRequest(), context, dispatcher, "some-request-id")
+			Expect(err).To(BeAssignableToTypeOf(webutil.UAAScopesError{}))
+			Expect(dispatcher.DispatchCall.WasCalled).To(BeFalse())
+		})
+
+		It("returns errors from the dispatcher", func() {
+			dispatcher.DispatchCall.Returns.Error = errors.New("BOOM!")
+
+			_, err := handler.ExecuteBatch(conn, newRequest(), context, dispatcher, "some-request-id")
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+
+		Describe("ExecuteAudience", func() {
+			var audience services.Audience
+
+			BeforeEach(func() {
+				delete(body, "targets")
+
+				audience = services.Audience{
+					ID:   "audience-123",
+					Name: "Operators",
+					Targets: []services.BatchTarget{
+						{Type: services.BatchTargetUser, ID: "user-123"},
+						{Type: services.BatchTargetSpace, ID: "space-123", Role: "SpaceDeveloper"},
+					},
+				}
+
+				dispatcher.DispatchCall.Returns.Result = services.BatchResult{
+					ID: "batch-123",
+					Targets: []services.BatchTargetResult{
+						{
+							Target:    services.BatchTarget{Type: services.BatchTargetUser, ID: "user-123"},
+							Responses: []services.Response{{Status: "queued", Recipient: "user-123", NotificationID: "message-1"}},
+						},
+						{
+							Target:     services.BatchTarget{Type: services.BatchTargetSpace, ID: "space-123", Role: "SpaceDeveloper"},
+							Responses:  []services.Response{},
+							Duplicates: 1,
+						},
+					},
+				}
+			})
+
+			It("dispatches the notification to the targets of the audience", func() {
+				output, err := handler.ExecuteAudience(conn, newRequest(), context, audience, dispatcher, "some-request-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output).To(MatchJSON(`{
+					"batch_id": "batch-123",
+					"audience_id": "audience-123",
+					"targets": [
+						{
+							"user": "user-123",
+							"notifications": [{"status": "queued", "recipient": "user-123", "notification_id": "message-1", "vcap_request_id": ""}],
+							"duplicates": 0
+						},
+						{
+							"space": "space-123",
+							"role": "SpaceDeveloper",
+							"notifications": [],
+							"duplicates": 1
+						}
+					]
+				}`))
+
+				Expect(dispatcher.DispatchCall.Receives.Targets).To(Equal(audience.Targets))
+				Expect(dispatcher.DispatchCall.Receives.Dispatch.Message.Subject).To(Equal("Your instance is down"))
+			})
+
+			It("rejects targets given in the body", func() {
+				body["targets"] = []map[string]string{{"user": "user-456"}}
+
+				_, err := handler.ExecuteAudience(conn, newRequest(), context, audience, dispatcher, "some-request-id")
+				Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"targets" must not be given when sending to an audience`)}))
+				Expect(dispatcher.DispatchCall.WasCalled).To(BeFalse())
+			})
+
+			It("requires the emails.write scope when the audience names email addresses", func() {
+				audience.Targets = append(audience.Targets, services.BatchTarget{Type: services.BatchTargetEmail, ID: "someone@example.com"})
+				tokenClaims["scope"] = []string{"notifications.write"}
+				setToken()
+
+				_, err := handler.ExecuteAudience(conn, newRequest(), context, audience, dispatcher, "some-request-id")
+				Expect(err).To(BeAssignableToTypeOf(webutil.UAAScopesError{}))
+				Expect(dispatcher.DispatchCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		Describe("DryRunBatch", func() {
+			var page models.Page
+
+			BeforeEach(func() {
+				page = models.Page{Limit: 2}
+
+				dispatcher.ResolveCall.Returns.Resolution = services.Resolution{
+					Users: []services.User{{GUID: "user-123"}, {Email: "someone@example.com"}},
+				}
+				dispatcher.ResolveCall.Returns.TargetResults = []services.BatchTargetResult{
+					{
+						Target:    services.BatchTarget{Type: services.BatchTargetUser, ID: "user-123"},
+						Responses: []services.Response{},
+					},
+					{
+						Target:     services.BatchTarget{Type: services.BatchTargetEmail, ID: "someone@example.com"},
+						Responses:  []services.Response{},
+						Duplicates: 1,
+					},
+					{
+						Target:    services.BatchTarget{Type: services.BatchTargetOrganization, ID: "org-123", Role: "OrgManager"},
+						Responses: []services.Response{},
+						Error:     errors.New("CloudController Failure: Organization could not be found"),
+					},
+				}
+
+				previewer.PreviewCall.Returns.Preview = services.RecipientsPreview{
+					Total:       2,
+					Deliverable: 1,
+					Suppressed:  1,
+					Skipped:     map[string]int{services.SkipReasonUnsubscribed: 1},
+					Recipients: []services.RecipientPreview{
+						{Email: "someone@example.com"},
+						{GUID: "user-123", Email: "user@example.com", SkipReason: services.SkipReasonUnsubscribed},
+					},
+				}
+				previewer.PreviewCall.Returns.Next = []string{"user-123"}
+			})
+
+			It("previews the recipients of every target without dispatching anything", func() {
+				output, next, err := handler.DryRunBatch(conn, newRequest(), context, dispatcher, "some-request-id", page)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(next).To(Equal([]string{"user-123"}))
+
+				Expect(output).To(MatchJSON(`{
+					"dry_run": true,
+					"total": 2,
+					"deliverable": 1,
+					"suppressed": 1,
+					"skipped": {"unsubscribed": 1},
+					"targets": [
+						{"user": "user-123", "duplicates": 0},
+						{"email": "someone@example.com", "duplicates": 1},
+						{
+							"organization": "org-123",
+							"role": "OrgManager",
+							"duplicates": 0,
+							"error": "CloudController Failure: Organization could not be found"
+						}
+					],
+					"recipients": [
+						{"recipient": "someone@example.com", "email": "someone@example.com", "status": "deliverable"},
+						{"recipient": "user-123", "email": "user@example.com", "status": "suppressed", "skip_reason": "unsubscribed"}
+					]
+				}`))
+
+				Expect(dispatcher.ResolveCall.Receives.Targets).To(Equal([]services.BatchTarget{
+					{Type: services.BatchTargetUser, ID: "user-123"},
+					{Type: services.BatchTargetEmail, ID: "someone@example.com"},
+					{Type: services.BatchTargetOrganization, ID: "org-123", Role: "OrgManager"},
+				}))
+				Expect(dispatcher.ResolveCall.Receives.Dispatch.Kind.ID).To(Equal("test_email"))
+				Expect(dispatcher.DispatchCall.WasCalled).To(BeFalse())
+
+				Expect(previewer.PreviewCall.Receives.Connection).To(Equal(conn))
+				Expect(previewer.PreviewCall.Receives.Dispatch).To(Equal(dispatcher.ResolveCall.Receives.Dispatch))
+				Expect(previewer.PreviewCall.Receives.Resolution).To(Equal(dispatcher.ResolveCall.Returns.Resolution))
+				Expect(previewer.PreviewCall.Receives.Page).To(Equal(page))
+
+				Expect(registrar.RegisterCall.Receives.Connection).To(BeNil())
+			})
+
+			It("returns a validation error when the batch is invalid", func() {
+				delete(body, "targets")
+
+				_, _, err := handler.DryRunBatch(conn, newRequest(), context, dispatcher, "some-request-id", page)
+				Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"targets" is a required field`)}))
+				Expect(dispatcher.ResolveCall.WasCalled).To(BeFalse())
+			})
+
+			It("requires the emails.write scope to preview email addresses", func() {
+				tokenClaims["scope"] = []string{"notifications.write"}
+				setToken()
+
+				_, _, err := handler.DryRunBatch(conn, newRequest(), context, dispatcher, "some-request-id", page)
+				Expect(err).To(BeAssignableToTypeOf(webutil.UAAScopesError{}))
+				Expect(dispatcher.ResolveCall.WasCalled).To(BeFalse())
+			})
+
+			It("returns errors from the previewer", func() {
+				previewer.PreviewCall.Returns.Error = errors.New("BOOM!")
+
+				_, _, err := handler.DryRunBatch(conn, newRequest(), context, dispatcher, "some-request-id", page)
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
+
+			Describe("DryRunAudience", func() {
+				var audience services.Audience
+
+				BeforeEach(func() {
+					delete(body, "targets")
+
+					audience = services.Audience{
+						ID:   "audience-123",
+						Name: "Operators",
+						Targets: []services.BatchTarget{
+							{Type: services.BatchTargetUser, ID: "user-123"},
+							{Type: services.BatchTargetSpace, ID: "space-123", Role: "SpaceDeveloper"},
+						},
+					}
+
+					dispatcher.ResolveCall.Returns.TargetResults = []services.BatchTargetResult{
+						{
+							Target:    services.BatchTarget{Type: services.BatchTargetUser, ID: "user-123"},
+							Responses: []services.Response{},
+						},
+						{
+							Target:     services.BatchTarget{Type: services.BatchTargetSpace, ID: "space-123", Role: "SpaceDeveloper"},
+							Responses:  []services.Response{},
+							Duplicates: 1,
+						},
+					}
+				})
+
+				It("previews the recipients of the targets of the audience", func() {
+					output, _, err := handler.DryRunAudience(conn, newRequest(), context, audience, dispatcher, "some-request-id", page)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(output).To(MatchJSON(`{
+						"dry_run": true,
+						"audience_id": "audience-123",
+						"total": 2,
+						"deliverable": 1,
+						"suppressed": 1,
+						"skipped": {"unsubscribed": 1},
+						"targets": [
+							{"user": "user-123", "duplicates": 0},
+							{"space": "space-123", "role": "SpaceDeveloper", "duplicates": 1}
+						],
+						"recipients": [
+							{"recipient": "someone@example.com", "email": "someone@example.com", "status": "deliverable"},
+							{"recipient": "user-123", "email": "user@example.com", "status": "suppressed", "skip_reason": "unsubscribed"}
+						]
+					}`))
+
+					Expect(dispatcher.ResolveCall.Receives.Targets).To(Equal(audience.Targets))
+					Expect(dispatcher.DispatchCall.WasCalled).To(BeFalse())
+				})
+
+				It("rejects targets given in the body", func() {
+					body["targets"] = []map[string]string{{"user": "user-456"}}
+
+					_, _, err := handler.DryRunAudience(conn, newRequest(), context, audience, dispatcher, "some-request-id", page)
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"targets" must not be given when sending to an audience`)}))
+					Expect(dispatcher.ResolveCall.WasCalled).To(BeFalse())
+				})
+			})
+		})
+	})
+
+	Describe("DryRun", func() {
+		var (
+			handler         notify.Notify
+			finder          *mocks.NotificationsFinder
+			registrar       *mocks.Registrar
+			previewer       *mocks.RecipientsPreviewer
+			strategy        *mocks.Strategy
+			validator       *mocks.Validator
+			conn            *mocks.Connection
+			context         stack.Context
+			reqReceivedTime time.Time
+			page            models.Page
+		)
+
+		newRequest := func() *http.Request {
+			content, err := json.Marshal(map[string]interface{}{
+				"kind_id": "test_email",
+				"subject": "Your instance is down",
+				"text":    "This is the plain text body of the email",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := http.NewRequest("POST", "/spaces/space-001?dry_run=true", bytes.NewBuffer(content))
+			Expect(err).NotTo(HaveOccurred())
+
+			return request
+		}
+
+		BeforeEach(func() {
+			finder = mocks.NewNotificationsFinder()
+			finder.ClientAndKindCall.Returns.Client = models.Client{ID: "mister-client", Description: "Health Monitor"}
+			finder.ClientAndKindCall.Returns.Kind = models.Kind{ID: "test_email", Description: "Instance Down", ClientID: "mister-client"}
+
+			registrar = mocks.NewRegistrar()
+			previewer = mocks.NewRecipientsPreviewer()
+			validator = mocks.NewValidator()
+			validator.ValidateCall.Returns.Valid = true
+			conn = mocks.NewConnection()
+
+			strategy = mocks.NewStrategy()
+			strategy.ResolveCalls = []mocks.StrategyResolveCall{
+				mocks.NewStrategyResolveCall(services.Resolution{
+					Users: []services.User{{GUID: "user-123"}, {GUID: "user-456"}},
+				}, nil),
+			}
+
+			reqReceivedTime, _ = time.Parse(time.RFC3339Nano, "2015-06-08T14:32:11.660762586-07:00")
+
+			rawToken := helpers.BuildToken(map[string]interface{}{
+				"alg": "RS256",
+			}, map[string]interface{}{
+				"client_id": "mister-client",
+				"iss":       "http://zone-uaa-host/oauth/token",
+				"exp":       int64(3404281214),
+				"scope":     []string{"notifications.write"},
+			})
+			token, err := jwt.Parse(rawToken, func(*jwt.Token) (interface{}, error) {
+				return []byte(helpers.UAAPublicKey), nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			context = stack.NewContext()
+			context.Set("token", token)
+			context.Set("database", mocks.NewDatabase())
+			context.Set(notify.RequestReceivedTime, reqReceivedTime)
+
+			page = models.Page{Limit: 2}
+
+			handler = notify.NewNotify(finder, registrar, previewer)
+		})
+
+		It("previews the recipients the strategy resolves", func() {
+			previewer.PreviewCall.Returns.Preview = services.RecipientsPreview{
+				Total:       3,
+				Deliverable: 1,
+				Suppressed:  1,
+				Skipped:     map[string]int{services.SkipReasonUnsubscribed: 1, services.SkipReasonNoEmail: 1},
+				Recipients: []services.RecipientPreview{
+					{Email: "someone@example.com"},
+					{GUID: "user-123", SkipReason: services.SkipReasonNoEmail},
+					{GUID: "user-456", Email: "user@example.com", SkipReason: services.SkipReasonUnsubscribed},
+				},
+			}
+			previewer.PreviewCall.Returns.Next = []string{"user-123"}
+
+			output, next, err := handler.DryRun(conn, newRequest(), context, "space-001", strategy, validator, "some-request-id", page)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal([]string{"user-123"}))
+
+			Expect(output).To(MatchJSON(`{
+				"dry_run": true,
+				"total": 3,
+				"deliverable": 1,
+				"suppressed": 1,
+				"skipped": {"unsubscribed": 1, "no_email": 1},
+				"recipients": [
+					{"recipient": "someone@example.com", "email": "someone@example.com", "status": "deliverable"},
+					{"recipient": "user-123", "status": "skipped", "skip_reason": "no_email"},
+					{"recipient": "user-456", "email": "user@example.com", "status": "suppressed", "skip_reason": "unsubscribed"}
+				]
+			}`))
+
+			Expect(strategy.ResolveCallsCount).To(Equal(1))
+			Expect(strategy.ResolveCalls[0].Receives.Dispatch.GUID).To(Equal("space-001"))
+			Expect(strategy.ResolveCalls[0].Receives.Dispatch.Kind.ID).To(Equal("test_email"))
+			Expect(strategy.DispatchCallsCount).To(Equal(0))
+
+			Expect(previewer.PreviewCall.Receives.Connection).To(Equal(conn))
+			Expect(previewer.PreviewCall.Receives.Dispatch).To(Equal(strategy.ResolveCalls[0].Receives.Dispatch))
+			Expect(previewer.PreviewCall.Receives.Resolution).To(Equal(services.Resolution{
+				Users: []services.User{{GUID: "user-123"}, {GUID: "user-456"}},
+			}))
+			Expect(previewer.PreviewCall.Receives.Page).To(Equal(page))
+		})
+
+		It("does not register the client and kind", func() {
+			_, _, err := handler.DryRun(conn, newRequest(), context, "space-001", strategy, validator, "some-request-id", page)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(registrar.RegisterCall.Receives.Connection).To(BeNil())
+		})
+
+		It("returns a validation error when the params are invalid", func() {
+			validator.ValidateCall.Returns.Valid = false
+			validator.ValidateCall.ErrorsToApply = []string{"boom"}
+
+			_, _, err := handler.DryRun(conn, newRequest(), context, "space-001", strategy, validator, "some-request-id", page)
+			Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("boom")}))
+			Expect(previewer.PreviewCall.WasCalled).To(BeFalse())
+		})
+
+		It("returns errors from the strategy", func() {
+			strategy.ResolveCalls[0].Returns.Error = errors.New("BOOM!")
+
+			_, _, err := handler.DryRun(conn, newRequest(), context, "space-001", strategy, validator, "some-request-id", page)
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+			Expect(previewer.PreviewCall.WasCalled).To(BeFalse())
+		})
+
+		It("returns errors from the previewer", func() {
+			previewer.PreviewCall.Returns.Error = errors.New("BOOM!")
+
+			_, _, err := handler.DryRun(conn, newRequest(), context, "space-001", strategy, validator, "some-request-id", page)
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+	})
 })
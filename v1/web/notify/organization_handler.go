@@ -22,16 +22,7 @@ func NewOrganizationHandler(notify notifyExecutor, errWriter errorWriter, strate
 }
 
 func (h OrganizationHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
-	conn := context.Get("database").(DatabaseInterface).Connection()
 	orgGUID := strings.TrimPrefix(req.URL.Path, "/organizations/")
-	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
 
-	output, err := h.notify.Execute(conn, req, context, orgGUID, h.strategy, GUIDValidator{}, vcapRequestID)
-	if err != nil {
-		h.errorWriter.Write(w, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(output)
+	serveNotify(w, req, context, h.notify, h.errorWriter, orgGUID, h.strategy, GUIDValidator{})
 }
@@ -6,6 +6,12 @@ type muxer interface {
 	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
 }
 
+type notifier interface {
+	notifyExecutor
+	batchExecutor
+	audienceExecutor
+}
+
 type Routes struct {
 	RequestCounter                  stack.Middleware
 	RequestLogging                  stack.Middleware
@@ -13,7 +19,7 @@ type Routes struct {
 	NotificationsWriteAuthenticator stack.Middleware
 	EmailsWriteAuthenticator        stack.Middleware
 
-	Notify               notifyExecutor
+	Notify               notifier
 	ErrorWriter          errorWriter
 	UserStrategy         Dispatcher
 	SpaceStrategy        Dispatcher
@@ -21,6 +27,8 @@ type Routes struct {
 	EveryoneStrategy     Dispatcher
 	UAAScopeStrategy     Dispatcher
 	EmailStrategy        Dispatcher
+	BatchDispatcher      BatchDispatcher
+	AudienceStore        audienceStore
 }
 
 func (r Routes) Register(m muxer) {
@@ -30,4 +38,11 @@ func (r Routes) Register(m muxer) {
 	m.Handle("POST", "/everyone", NewEveryoneHandler(r.Notify, r.ErrorWriter, r.EveryoneStrategy), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("POST", "/uaa_scopes/{scope}", NewUAAScopeHandler(r.Notify, r.ErrorWriter, r.UAAScopeStrategy), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("POST", "/emails", NewEmailHandler(r.Notify, r.ErrorWriter, r.EmailStrategy), r.RequestLogging, r.RequestCounter, r.EmailsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/batches", NewBatchHandler(r.Notify, r.ErrorWriter, r.BatchDispatcher), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/audiences", NewCreateAudienceHandler(r.AudienceStore, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/audiences", NewListAudiencesHandler(r.AudienceStore, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/audiences/{audience_id}", NewGetAudienceHandler(r.AudienceStore, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/audiences/{audience_id}", NewUpdateAudienceHandler(r.AudienceStore, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/audiences/{audience_id}", NewDeleteAudienceHandler(r.AudienceStore, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/audiences/{audience_id}", NewAudienceHandler(r.Notify, r.ErrorWriter, r.AudienceStore, r.BatchDispatcher), r.RequestLogging, r.RequestCounter, r.NotificationsWriteAuthenticator, r.DatabaseAllocator)
 }
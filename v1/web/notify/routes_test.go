@@ -28,6 +28,8 @@ var _ = Describe("Routes", func() {
 			EveryoneStrategy:     mocks.NewStrategy(),
 			UAAScopeStrategy:     mocks.NewStrategy(),
 			EmailStrategy:        mocks.NewStrategy(),
+			BatchDispatcher:      mocks.NewBatchDispatcher(),
+			AudienceStore:        mocks.NewAudienceStore(),
 
 			RequestCounter:                  middleware.RequestCounter{},
 			RequestLogging:                  middleware.RequestLogging{},
@@ -108,4 +110,88 @@ var _ = Describe("Routes", func() {
 		authenticator := s.Middleware[2].(middleware.Authenticator)
 		Expect(authenticator.Scopes).To(Equal([]string{"emails.write"}))
 	})
+
+	It("routes POST /batches", func() {
+		request, err := http.NewRequest("POST", "/batches", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(notify.BatchHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.write"}))
+	})
+
+	It("routes POST /audiences", func() {
+		request, err := http.NewRequest("POST", "/audiences", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(notify.CreateAudienceHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.write"}))
+	})
+
+	It("routes GET /audiences", func() {
+		request, err := http.NewRequest("GET", "/audiences", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(notify.ListAudiencesHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.write"}))
+	})
+
+	It("routes GET /audiences/{audience_id}", func() {
+		request, err := http.NewRequest("GET", "/audiences/{audience_id}", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(notify.GetAudienceHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.write"}))
+	})
+
+	It("routes PUT /audiences/{audience_id}", func() {
+		request, err := http.NewRequest("PUT", "/audiences/{audience_id}", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(notify.UpdateAudienceHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.write"}))
+	})
+
+	It("routes DELETE /audiences/{audience_id}", func() {
+		request, err := http.NewRequest("DELETE", "/audiences/{audience_id}", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(notify.DeleteAudienceHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.write"}))
+	})
+
+	It("routes POST /audiences/{audience_id}", func() {
+		request, err := http.NewRequest("POST", "/audiences/{audience_id}", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(notify.AudienceHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.write"}))
+	})
 })
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+// serveNotify sends the notification of a notify request, or only lists
+// its recipients when the request sets the dry_run query parameter. Dry
+// runs are always paginated since they may resolve to every user.
+func serveNotify(w http.ResponseWriter, req *http.Request, context stack.Context, notify notifyExecutor, errorWriter errorWriter,
+	guid string, strategy Dispatcher, validator ValidatorInterface) {
+
+	conn := context.Get("database").(DatabaseInterface).Connection()
+	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
+	query := req.URL.Query()
+
+	dryRun, err := parseDryRun(query.Get("dry_run"))
+	if err != nil {
+		errorWriter.Write(w, err)
+		return
+	}
+
+	if !dryRun {
+		output, err := notify.Execute(conn, req, context, guid, strategy, validator, vcapRequestID)
+		if err != nil {
+			errorWriter.Write(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(output)
+		return
+	}
+
+	page, err := webutil.NewPage(query)
+	if err != nil {
+		errorWriter.Write(w, err)
+		return
+	}
+
+	output, next, err := notify.DryRun(conn, req, context, guid, strategy, validator, vcapRequestID, page)
+	if err != nil {
+		errorWriter.Write(w, err)
+		return
+	}
+
+	webutil.WriteNextPageLink(w, req, page, next)
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
+
+func parseDryRun(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, webutil.ValidationError{Err: errors.New(`"dry_run" must be true or false`)}
+	}
+
+	return dryRun, nil
+}
@@ -22,16 +22,7 @@ func NewSpaceHandler(notify notifyExecutor, errWriter errorWriter, strategy Disp
 }
 
 func (h SpaceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
-	conn := context.Get("database").(DatabaseInterface).Connection()
 	spaceGUID := strings.TrimPrefix(req.URL.Path, "/spaces/")
-	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
 
-	output, err := h.notify.Execute(conn, req, context, spaceGUID, h.strategy, GUIDValidator{}, vcapRequestID)
-	if err != nil {
-		h.errorWriter.Write(w, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(output)
+	serveNotify(w, req, context, h.notify, h.errorWriter, spaceGUID, h.strategy, GUIDValidator{Roles: validSpaceRoles})
 }
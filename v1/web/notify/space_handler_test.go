@@ -8,7 +8,9 @@ import (
 	"reflect"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/ryanmoran/stack"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -63,7 +65,9 @@ var _ = Describe("SpaceHandler", func() {
 				Expect(notifyObj.ExecuteCall.Receives.Context).To(Equal(context))
 				Expect(notifyObj.ExecuteCall.Receives.GUID).To(Equal("space-001"))
 				Expect(notifyObj.ExecuteCall.Receives.Strategy).To(Equal(strategy))
-				Expect(notifyObj.ExecuteCall.Receives.Validator).To(BeAssignableToTypeOf(notify.GUIDValidator{}))
+				Expect(notifyObj.ExecuteCall.Receives.Validator).To(Equal(notify.GUIDValidator{
+					Roles: []string{"SpaceDeveloper", "SpaceManager", "SpaceAuditor"},
+				}))
 				Expect(notifyObj.ExecuteCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
 			})
 		})
@@ -76,5 +80,67 @@ var _ = Describe("SpaceHandler", func() {
 				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(notifyObj.ExecuteCall.Returns.Error))
 			})
 		})
+
+		Context("when a dry run is requested", func() {
+			BeforeEach(func() {
+				request.URL.RawQuery = "dry_run=true&limit=10"
+			})
+
+			It("previews the recipients instead of sending the notification", func() {
+				notifyObj.DryRunCall.Returns.Response = []byte("preview")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusOK))
+				Expect(writer.Body.String()).To(Equal("preview"))
+				Expect(writer.Header().Get("Link")).To(BeEmpty())
+
+				Expect(reflect.ValueOf(notifyObj.DryRunCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
+				Expect(notifyObj.DryRunCall.Receives.Request).To(Equal(request))
+				Expect(notifyObj.DryRunCall.Receives.GUID).To(Equal("space-001"))
+				Expect(notifyObj.DryRunCall.Receives.Strategy).To(Equal(strategy))
+				Expect(notifyObj.DryRunCall.Receives.Validator).To(Equal(notify.GUIDValidator{
+					Roles: []string{"SpaceDeveloper", "SpaceManager", "SpaceAuditor"},
+				}))
+				Expect(notifyObj.DryRunCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+				Expect(notifyObj.DryRunCall.Receives.Page).To(Equal(models.Page{Limit: 10}))
+				Expect(notifyObj.ExecuteCall.Receives.Request).To(BeNil())
+			})
+
+			It("pages the recipients by default", func() {
+				request.URL.RawQuery = "dry_run=true"
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(notifyObj.DryRunCall.Receives.Page).To(Equal(models.Page{Limit: webutil.DefaultPageLimit}))
+			})
+
+			It("links to the next page of recipients", func() {
+				notifyObj.DryRunCall.Returns.Next = []string{"user-123"}
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Header().Get("Link")).To(ContainSubstring(`rel="next"`))
+				Expect(writer.Header().Get("Link")).To(ContainSubstring("dry_run=true"))
+			})
+
+			It("rejects values that are not booleans", func() {
+				request.URL.RawQuery = "dry_run=maybe"
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"dry_run" must be true or false`)}))
+				Expect(notifyObj.DryRunCall.Receives.Request).To(BeNil())
+				Expect(notifyObj.ExecuteCall.Receives.Request).To(BeNil())
+			})
+
+			It("propagates the error", func() {
+				notifyObj.DryRunCall.Returns.Error = errors.New("the error")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("the error")))
+			})
+		})
 	})
 })
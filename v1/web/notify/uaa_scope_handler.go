@@ -22,16 +22,7 @@ func NewUAAScopeHandler(notify notifyExecutor, errWriter errorWriter, strategy D
 }
 
 func (h UAAScopeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
-	conn := context.Get("database").(DatabaseInterface).Connection()
 	scope := strings.TrimPrefix(req.URL.Path, "/uaa_scopes/")
-	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
 
-	output, err := h.notify.Execute(conn, req, context, scope, h.strategy, GUIDValidator{}, vcapRequestID)
-	if err != nil {
-		h.errorWriter.Write(w, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(output)
+	serveNotify(w, req, context, h.notify, h.errorWriter, scope, h.strategy, GUIDValidator{})
 }
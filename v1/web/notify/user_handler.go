@@ -22,16 +22,7 @@ func NewUserHandler(notify notifyExecutor, errWriter errorWriter, strategy Dispa
 }
 
 func (h UserHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
-	conn := context.Get("database").(DatabaseInterface).Connection()
 	userGUID := strings.TrimPrefix(req.URL.Path, "/users/")
-	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
 
-	output, err := h.notify.Execute(conn, req, context, userGUID, h.strategy, GUIDValidator{}, vcapRequestID)
-	if err != nil {
-		h.errorWriter.Write(w, err)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(output)
+	serveNotify(w, req, context, h.notify, h.errorWriter, userGUID, h.strategy, GUIDValidator{})
 }
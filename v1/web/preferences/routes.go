@@ -12,6 +12,10 @@ type muxer interface {
 
 type preferenceUpdater interface {
 	Update(connection services.ConnectionInterface, preferences []models.Preference, globallyUnsubscribe bool, userID string) error
+	SetLocale(connection services.ConnectionInterface, userID, locale string) error
+	SetDigest(connection services.ConnectionInterface, userID, digest string) error
+	SetTimezone(connection services.ConnectionInterface, userID, timezone string) error
+	SetQuietHours(connection services.ConnectionInterface, userID, start, end string) error
 }
 
 type Routes struct {
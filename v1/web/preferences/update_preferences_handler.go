@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/cloudfoundry-incubator/notifications/locale"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
@@ -51,6 +52,36 @@ func (h UpdatePreferencesHandler) ServeHTTP(w http.ResponseWriter, req *http.Req
 		return
 	}
 
+	userLocale, err := canonicalLocale(builder.Locale)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+		return
+	}
+
+	if builder.Digest != nil {
+		err = services.ValidateDigest(*builder.Digest)
+		if err != nil {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+			return
+		}
+	}
+
+	if builder.Timezone != nil {
+		err = services.ValidateTimezone(*builder.Timezone)
+		if err != nil {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+			return
+		}
+	}
+
+	if builder.QuietHours != nil {
+		err = services.ValidateQuietHours(*builder.QuietHours)
+		if err != nil {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+			return
+		}
+	}
+
 	transaction := connection.Transaction()
 	transaction.Begin()
 	err = h.preferences.Update(transaction, preferences, builder.GlobalUnsubscribe, userID)
@@ -66,6 +97,42 @@ func (h UpdatePreferencesHandler) ServeHTTP(w http.ResponseWriter, req *http.Req
 		return
 	}
 
+	if builder.Locale != nil {
+		err = h.preferences.SetLocale(transaction, userID, userLocale)
+		if err != nil {
+			transaction.Rollback()
+			h.errorWriter.Write(w, err)
+			return
+		}
+	}
+
+	if builder.Digest != nil {
+		err = h.preferences.SetDigest(transaction, userID, *builder.Digest)
+		if err != nil {
+			transaction.Rollback()
+			h.errorWriter.Write(w, err)
+			return
+		}
+	}
+
+	if builder.Timezone != nil {
+		err = h.preferences.SetTimezone(transaction, userID, *builder.Timezone)
+		if err != nil {
+			transaction.Rollback()
+			h.errorWriter.Write(w, err)
+			return
+		}
+	}
+
+	if builder.QuietHours != nil {
+		err = h.preferences.SetQuietHours(transaction, userID, builder.QuietHours.Start, builder.QuietHours.End)
+		if err != nil {
+			transaction.Rollback()
+			h.errorWriter.Write(w, err)
+			return
+		}
+	}
+
 	err = transaction.Commit()
 	if err != nil {
 		h.errorWriter.Write(w, models.TransactionCommitError{Err: err})
@@ -74,3 +141,13 @@ func (h UpdatePreferencesHandler) ServeHTTP(w http.ResponseWriter, req *http.Req
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// canonicalLocale validates the locale given in a preferences update. An
+// empty locale clears the user's choice.
+func canonicalLocale(userLocale *string) (string, error) {
+	if userLocale == nil || *userLocale == "" {
+		return "", nil
+	}
+
+	return locale.Parse(*userLocale)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 
+	"github.com/cloudfoundry-incubator/notifications/locale"
 	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -127,6 +128,156 @@ var _ = Describe("UpdatePreferencesHandler", func() {
 			Expect(writer.Code).To(Equal(http.StatusNoContent))
 		})
 
+		It("leaves the locale alone when none is given", func() {
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(updater.SetLocaleCall.WasCalled).To(BeFalse())
+		})
+
+		Context("when a locale is given", func() {
+			BeforeEach(func() {
+				var err error
+				request, err = http.NewRequest("PATCH", "/user_preferences", bytes.NewBufferString(`{"clients": {}, "locale": "de_ch"}`))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("stores the canonical locale within the transaction", func() {
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusNoContent))
+				Expect(reflect.ValueOf(updater.SetLocaleCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(transaction).Pointer()))
+				Expect(updater.SetLocaleCall.Receives.UserID).To(Equal("correct-user"))
+				Expect(updater.SetLocaleCall.Receives.Locale).To(Equal("de-CH"))
+				Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			})
+
+			It("clears the locale when it is empty", func() {
+				var err error
+				request, err = http.NewRequest("PATCH", "/user_preferences", bytes.NewBufferString(`{"clients": {}, "locale": ""}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(updater.SetLocaleCall.WasCalled).To(BeTrue())
+				Expect(updater.SetLocaleCall.Receives.Locale).To(BeEmpty())
+			})
+
+			It("delegates invalid locales as validation errors to the ErrorWriter", func() {
+				var err error
+				request, err = http.NewRequest("PATCH", "/user_preferences", bytes.NewBufferString(`{"clients": {}, "locale": "german"}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: locale.ParseError{Locale: "german"}}))
+				Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+			})
+
+			It("rolls back the transaction when the locale cannot be stored", func() {
+				updater.SetLocaleCall.Returns.Error = errors.New("BOOM!")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		Context("when a digest is given", func() {
+			BeforeEach(func() {
+				var err error
+				request, err = http.NewRequest("PATCH", "/user_preferences", bytes.NewBufferString(`{"clients": {}, "digest": "daily"}`))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("stores the digest within the transaction", func() {
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusNoContent))
+				Expect(reflect.ValueOf(updater.SetDigestCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(transaction).Pointer()))
+				Expect(updater.SetDigestCall.Receives.UserID).To(Equal("correct-user"))
+				Expect(updater.SetDigestCall.Receives.Digest).To(Equal("daily"))
+				Expect(updater.SetLocaleCall.WasCalled).To(BeFalse())
+				Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			})
+
+			It("delegates unknown digests as validation errors to the ErrorWriter", func() {
+				var err error
+				request, err = http.NewRequest("PATCH", "/user_preferences", bytes.NewBufferString(`{"clients": {}, "digest": "weekly"}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"digest" must be one of "immediate", "hourly" or "daily"`)}))
+				Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+			})
+
+			It("rolls back the transaction when the digest cannot be stored", func() {
+				updater.SetDigestCall.Returns.Error = errors.New("BOOM!")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		Context("when a timezone and quiet hours are given", func() {
+			BeforeEach(func() {
+				var err error
+				request, err = http.NewRequest("PATCH", "/user_preferences", bytes.NewBufferString(`{"clients": {}, "timezone": "America/New_York", "quiet_hours": {"start": "22:00", "end": "07:00"}}`))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("stores them within the transaction", func() {
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusNoContent))
+				Expect(reflect.ValueOf(updater.SetTimezoneCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(transaction).Pointer()))
+				Expect(updater.SetTimezoneCall.Receives.UserID).To(Equal("correct-user"))
+				Expect(updater.SetTimezoneCall.Receives.Timezone).To(Equal("America/New_York"))
+				Expect(reflect.ValueOf(updater.SetQuietHoursCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(transaction).Pointer()))
+				Expect(updater.SetQuietHoursCall.Receives.UserID).To(Equal("correct-user"))
+				Expect(updater.SetQuietHoursCall.Receives.Start).To(Equal("22:00"))
+				Expect(updater.SetQuietHoursCall.Receives.End).To(Equal("07:00"))
+				Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			})
+
+			It("delegates unknown time zones as validation errors to the ErrorWriter", func() {
+				var err error
+				request, err = http.NewRequest("PATCH", "/user_preferences", bytes.NewBufferString(`{"clients": {}, "timezone": "Mars/Olympus_Mons"}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"timezone" must be a known time zone such as "America/New_York"`)}))
+				Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+			})
+
+			It("delegates malformed quiet hours as validation errors to the ErrorWriter", func() {
+				var err error
+				request, err = http.NewRequest("PATCH", "/user_preferences", bytes.NewBufferString(`{"clients": {}, "quiet_hours": {"start": "22:00"}}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"quiet_hours" must have a "start" and an "end" such as "22:00"`)}))
+				Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+			})
+
+			It("rolls back the transaction when the quiet hours cannot be stored", func() {
+				updater.SetQuietHoursCall.Returns.Error = errors.New("BOOM!")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+			})
+		})
+
 		Context("Failure cases", func() {
 			It("returns an error when the clients key is missing", func() {
 				jsonBody := `{"raptor-client": {"containment-unit-breach": {"email": false}}}`
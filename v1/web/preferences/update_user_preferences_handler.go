@@ -44,6 +44,36 @@ func (h UpdateUserPreferencesHandler) ServeHTTP(w http.ResponseWriter, req *http
 		return
 	}
 
+	userLocale, err := canonicalLocale(builder.Locale)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+		return
+	}
+
+	if builder.Digest != nil {
+		err = services.ValidateDigest(*builder.Digest)
+		if err != nil {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+			return
+		}
+	}
+
+	if builder.Timezone != nil {
+		err = services.ValidateTimezone(*builder.Timezone)
+		if err != nil {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+			return
+		}
+	}
+
+	if builder.QuietHours != nil {
+		err = services.ValidateQuietHours(*builder.QuietHours)
+		if err != nil {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+			return
+		}
+	}
+
 	transaction := connection.Transaction()
 	transaction.Begin()
 	err = h.preferences.Update(transaction, preferences, builder.GlobalUnsubscribe, userGUID)
@@ -59,6 +89,42 @@ func (h UpdateUserPreferencesHandler) ServeHTTP(w http.ResponseWriter, req *http
 		return
 	}
 
+	if builder.Locale != nil {
+		err = h.preferences.SetLocale(transaction, userGUID, userLocale)
+		if err != nil {
+			transaction.Rollback()
+			h.errorWriter.Write(w, err)
+			return
+		}
+	}
+
+	if builder.Digest != nil {
+		err = h.preferences.SetDigest(transaction, userGUID, *builder.Digest)
+		if err != nil {
+			transaction.Rollback()
+			h.errorWriter.Write(w, err)
+			return
+		}
+	}
+
+	if builder.Timezone != nil {
+		err = h.preferences.SetTimezone(transaction, userGUID, *builder.Timezone)
+		if err != nil {
+			transaction.Rollback()
+			h.errorWriter.Write(w, err)
+			return
+		}
+	}
+
+	if builder.QuietHours != nil {
+		err = h.preferences.SetQuietHours(transaction, userGUID, builder.QuietHours.Start, builder.QuietHours.End)
+		if err != nil {
+			transaction.Rollback()
+			h.errorWriter.Write(w, err)
+			return
+		}
+	}
+
 	err = transaction.Commit()
 	if err != nil {
 		h.errorWriter.Write(w, models.TransactionCommitError{Err: err})
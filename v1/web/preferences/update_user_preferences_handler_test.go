@@ -127,6 +127,45 @@ var _ = Describe("UpdateUserPreferencesHandler", func() {
 			Expect(writer.Code).To(Equal(http.StatusNoContent))
 		})
 
+		It("stores the user's locale when one is given", func() {
+			var err error
+			request, err = http.NewRequest("PATCH", "domain/user_preferences/"+userGUID, bytes.NewBufferString(`{"clients": {}, "locale": "fr-ca"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+			Expect(updater.SetLocaleCall.Receives.UserID).To(Equal(userGUID))
+			Expect(updater.SetLocaleCall.Receives.Locale).To(Equal("fr-CA"))
+		})
+
+		It("stores the user's digest when one is given", func() {
+			var err error
+			request, err = http.NewRequest("PATCH", "domain/user_preferences/"+userGUID, bytes.NewBufferString(`{"clients": {}, "digest": "hourly"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+			Expect(updater.SetDigestCall.Receives.UserID).To(Equal(userGUID))
+			Expect(updater.SetDigestCall.Receives.Digest).To(Equal("hourly"))
+		})
+
+		It("stores the user's timezone and quiet hours when they are given", func() {
+			var err error
+			request, err = http.NewRequest("PATCH", "domain/user_preferences/"+userGUID, bytes.NewBufferString(`{"clients": {}, "timezone": "Europe/Berlin", "quiet_hours": {"start": "23:00", "end": "06:30"}}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+			Expect(updater.SetTimezoneCall.Receives.UserID).To(Equal(userGUID))
+			Expect(updater.SetTimezoneCall.Receives.Timezone).To(Equal("Europe/Berlin"))
+			Expect(updater.SetQuietHoursCall.Receives.UserID).To(Equal(userGUID))
+			Expect(updater.SetQuietHoursCall.Receives.Start).To(Equal("23:00"))
+			Expect(updater.SetQuietHoursCall.Receives.End).To(Equal("06:30"))
+		})
+
 		Context("Failure cases", func() {
 			Context("when global_unsubscribe is not set", func() {
 				It("returns an error when the clients key is missing", func() {
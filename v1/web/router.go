@@ -8,11 +8,13 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/batches"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/clients"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/info"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
@@ -47,6 +49,7 @@ type Config struct {
 	CORSOrigin           string
 	SQLDB                *sql.DB
 	QueueWaitMaxDuration int
+	AllowedSenderDomains []string
 }
 
 func NewRouter(mx muxer, config Config) http.Handler {
@@ -58,29 +61,43 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	globalUnsubscribesRepo := models.NewGlobalUnsubscribesRepo()
 	preferencesRepo := models.NewPreferencesRepo()
 	unsubscribesRepo := models.NewUnsubscribesRepo()
+	digestPreferencesRepo := models.NewDigestPreferencesRepo()
 	messagesRepo := models.NewMessagesRepo(guidGenerator.Generate)
 	templatesRepo := models.NewTemplatesRepo()
+	templateVersionsRepo := models.NewTemplateVersionsRepo()
+	senderIdentitiesRepo := models.NewSenderIdentitiesRepo()
+	templateLocalesRepo := models.NewTemplateLocalesRepo()
+	templatePartialsRepo := models.NewTemplatePartialsRepo()
+	userSettingsRepo := models.NewUserSettingsRepo()
+	dedupEntriesRepo := models.NewDedupEntriesRepo()
+	audiencesRepo := models.NewAudiencesRepo(guidGenerator.Generate)
+	batchesRepo := models.NewBatchesRepo(guidGenerator.Generate)
 
 	registrar := services.NewRegistrar(clientsRepo, kindsRepo)
 	notificationsFinder := services.NewNotificationsFinder(clientsRepo, kindsRepo)
-	preferencesFinder := services.NewPreferencesFinder(preferencesRepo, globalUnsubscribesRepo)
-	preferenceUpdater := services.NewPreferenceUpdater(globalUnsubscribesRepo, unsubscribesRepo, kindsRepo)
+	preferencesFinder := services.NewPreferencesFinder(preferencesRepo, globalUnsubscribesRepo, userSettingsRepo)
+	preferenceUpdater := services.NewPreferenceUpdater(globalUnsubscribesRepo, unsubscribesRepo, digestPreferencesRepo, kindsRepo, userSettingsRepo)
 	notificationsUpdater := services.NewNotificationsUpdater(kindsRepo)
 	messageFinder := services.NewMessageFinder(messagesRepo)
+	batchFinder := services.NewBatchFinder(batchesRepo)
+	audienceStore := services.NewAudienceStore(audiencesRepo)
 
-	templatesCollection := collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo)
+	templatesCollection := collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, templateVersionsRepo, templateLocalesRepo)
+	senderIdentitiesCollection := collections.NewSenderIdentitiesCollection(clientsRepo, kindsRepo, senderIdentitiesRepo, config.AllowedSenderDomains)
 
+	templateVersionsCollection := collections.NewTemplateVersionsCollection(templatesRepo, templateVersionsRepo, templatePartialsRepo, templateLocalesRepo)
+	templateLocalesCollection := collections.NewTemplateLocalesCollection(templatesRepo, templateLocalesRepo)
+	templatePartialsCollection := collections.NewTemplatePartialsCollection(templatePartialsRepo, templatesRepo, templateLocalesRepo)
 	templateFinder := services.NewTemplateFinder(templatesRepo)
-	templateUpdater := services.NewTemplateUpdater(templatesRepo)
+	templateUpdater := services.NewTemplateUpdater(templatesRepo, templateVersionsRepo)
 	templateLister := services.NewTemplateLister(templatesRepo)
-
-	notifyObj := notify.NewNotify(notificationsFinder, registrar)
+	templatePreviewer := services.NewTemplatePreviewer(common.Packager{})
 
 	gobbleQueue := gobble.NewQueue(gobble.NewDatabase(config.SQLDB), clock, gobble.Config{
 		WaitMaxDuration: time.Duration(config.QueueWaitMaxDuration) * time.Millisecond,
 	})
 
-	v1enqueuer := services.NewEnqueuer(gobbleQueue, messagesRepo, gobble.Initializer{})
+	v1enqueuer := services.NewEnqueuer(gobbleQueue, messagesRepo, dedupEntriesRepo, batchesRepo, gobble.Initializer{})
 
 	uaaClient := uaa.NewZonedUAAClient(config.UAAClientID, config.UAAClientSecret, config.VerifySSL, config.UAATokenValidator)
 	cloudController := cf.NewCloudController(config.CCHost, !config.VerifySSL)
@@ -89,6 +106,9 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	organizationLoader := services.NewOrganizationLoader(cloudController)
 	findsUserIDs := services.NewFindsUserIDs(cloudController, uaaClient)
 	allUsers := services.NewAllUsers(uaaClient)
+	recipientsPreviewer := services.NewRecipientsPreviewer(tokenLoader, common.NewUserLoader(uaaClient), kindsRepo, unsubscribesRepo, globalUnsubscribesRepo)
+
+	notifyObj := notify.NewNotify(notificationsFinder, registrar, recipientsPreviewer)
 
 	emailStrategy := services.NewEmailStrategy(v1enqueuer)
 	userStrategy := services.NewUserStrategy(v1enqueuer)
@@ -96,6 +116,7 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	organizationStrategy := services.NewOrganizationStrategy(tokenLoader, organizationLoader, findsUserIDs, v1enqueuer)
 	everyoneStrategy := services.NewEveryoneStrategy(tokenLoader, allUsers, v1enqueuer)
 	uaaScopeStrategy := services.NewUAAScopeStrategy(tokenLoader, findsUserIDs, v1enqueuer, config.DefaultUAAScopes)
+	batchDispatcher := services.NewBatchDispatcher(userStrategy, emailStrategy, spaceStrategy, organizationStrategy, uaaScopeStrategy, v1enqueuer, guidGenerator)
 
 	errorWriter := webutil.NewErrorWriter()
 
@@ -136,6 +157,7 @@ func NewRouter(mx muxer, config Config) http.Handler {
 
 		ErrorWriter:      errorWriter,
 		TemplateAssigner: templatesCollection,
+		SenderAssigner:   senderIdentitiesCollection,
 	}.Register(mx)
 
 	messages.Routes{
@@ -148,6 +170,17 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		MessageFinder: messageFinder,
 	}.Register(mx)
 
+	batches.Routes{
+		RequestCounter:    requestCounter,
+		RequestLogging:    requestLogging,
+		DatabaseAllocator: databaseAllocator,
+		NotificationsWriteOrEmailsWriteAuthenticator: auth("notifications.write", "emails.write"),
+		NotificationsManageAuthenticator:             auth("notifications.manage"),
+
+		ErrorWriter: errorWriter,
+		BatchFinder: batchFinder,
+	}.Register(mx)
+
 	templates.Routes{
 		RequestCounter:                          requestCounter,
 		RequestLogging:                          requestLogging,
@@ -163,6 +196,11 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		TemplateDeleter:           templatesCollection,
 		TemplateLister:            templateLister,
 		TemplateAssociationLister: templatesCollection,
+		TemplatePreviewer:         templatePreviewer,
+		TemplateVersions:          templateVersionsCollection,
+		TemplateLocales:           templateLocalesCollection,
+		TemplatePartials:          templatePartialsCollection,
+		TemplateBundles:           templatesCollection,
 	}.Register(mx)
 
 	notifications.Routes{
@@ -177,6 +215,7 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		NotificationsFinder:  notificationsFinder,
 		NotificationsUpdater: notificationsUpdater,
 		TemplateAssigner:     templatesCollection,
+		SenderAssigner:       senderIdentitiesCollection,
 	}.Register(mx)
 
 	notify.Routes{
@@ -194,6 +233,8 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		EveryoneStrategy:     everyoneStrategy,
 		UAAScopeStrategy:     uaaScopeStrategy,
 		EmailStrategy:        emailStrategy,
+		BatchDispatcher:      batchDispatcher,
+		AudienceStore:        audienceStore,
 	}.Register(mx)
 
 	return mx
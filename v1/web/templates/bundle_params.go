@@ -0,0 +1,150 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+)
+
+// TemplateBundleParams is the body of an import, in the format the export
+// writes.
+type TemplateBundleParams struct {
+	Version   int                     `json:"version"`
+	Templates []BundledTemplateParams `json:"templates"`
+}
+
+type BundledTemplateParams struct {
+	ID string `json:"id"`
+	TemplateParams
+	Associations []TemplateAssociation `json:"associations"`
+}
+
+// NewTemplateBundleParams parses a bundle and checks each of its templates
+// as creating the template would.
+func NewTemplateBundleParams(body io.ReadCloser) (TemplateBundleParams, error) {
+	defer body.Close()
+
+	var bundle TemplateBundleParams
+	err := json.NewDecoder(body).Decode(&bundle)
+	if err != nil {
+		return TemplateBundleParams{}, webutil.ParseError{}
+	}
+
+	for i := range bundle.Templates {
+		template := &bundle.Templates[i]
+
+		err := template.validate()
+		if err != nil {
+			return TemplateBundleParams{}, template.wrapError(err)
+		}
+	}
+
+	return bundle, nil
+}
+
+// validatePartials checks the layout and partials of every template of the
+// bundle against the stored partials.
+func (b TemplateBundleParams) validatePartials(conn collections.ConnectionInterface, partials templatePartialsLister) error {
+	for _, template := range b.Templates {
+		err := template.TemplateParams.validatePartials(conn, partials)
+		if err != nil {
+			return template.wrapError(err)
+		}
+	}
+
+	return nil
+}
+
+func (t *BundledTemplateParams) validate() error {
+	if t.Name == "" {
+		return webutil.ValidationError{Err: errors.New("Missing required field 'name'")}
+	}
+
+	for _, association := range t.Associations {
+		if association.Client == "" {
+			return webutil.ValidationError{Err: errors.New("associations must name a client")}
+		}
+	}
+
+	return t.TemplateParams.validate()
+}
+
+func (t BundledTemplateParams) wrapError(err error) error {
+	validationError, ok := err.(webutil.ValidationError)
+	if !ok {
+		return err
+	}
+
+	name := t.ID
+	if name == "" {
+		name = t.Name
+	}
+
+	return webutil.ValidationError{Err: fmt.Errorf("template %q: %s", name, validationError.Err)}
+}
+
+func (b TemplateBundleParams) ToBundle() collections.TemplateBundle {
+	bundle := collections.TemplateBundle{
+		Version: b.Version,
+	}
+
+	for _, template := range b.Templates {
+		var associations []collections.TemplateAssociation
+		for _, association := range template.Associations {
+			associations = append(associations, collections.TemplateAssociation{
+				ClientID:       association.Client,
+				NotificationID: association.Notification,
+			})
+		}
+
+		bundle.Templates = append(bundle.Templates, collections.BundledTemplate{
+			Template: collections.Template{
+				ID:       template.ID,
+				Name:     template.Name,
+				Text:     template.Text,
+				HTML:     template.HTML,
+				Markdown: template.Markdown,
+				Layout:   template.Layout,
+				Subject:  template.Subject,
+				Metadata: string(template.Metadata),
+			},
+			Associations: associations,
+		})
+	}
+
+	return bundle
+}
+
+// NewTemplateImportOptions reads the conflict strategy and whether the
+// import is a dry run from the query. Conflicts are skipped unless asked
+// otherwise.
+func NewTemplateImportOptions(query url.Values) (collections.TemplateImportOptions, error) {
+	options := collections.TemplateImportOptions{
+		Conflict: collections.ImportConflictSkip,
+	}
+
+	if conflict := query.Get("conflict"); conflict != "" {
+		switch conflict {
+		case collections.ImportConflictSkip, collections.ImportConflictOverwrite, collections.ImportConflictRename:
+			options.Conflict = conflict
+		default:
+			return options, webutil.ValidationError{Err: fmt.Errorf(`"conflict" must be one of %q, %q or %q`, collections.ImportConflictSkip, collections.ImportConflictOverwrite, collections.ImportConflictRename)}
+		}
+	}
+
+	if dryRun := query.Get("dry_run"); dryRun != "" {
+		var err error
+		options.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			return options, webutil.ValidationError{Err: errors.New(`"dry_run" must be true or false`)}
+		}
+	}
+
+	return options, nil
+}
@@ -0,0 +1,182 @@
+package templates
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type templateBundlesCollection interface {
+	Export(conn collections.ConnectionInterface) (collections.TemplateBundle, error)
+	Import(conn collections.ConnectionInterface, bundle collections.TemplateBundle, options collections.TemplateImportOptions, clientID string) (collections.TemplateImportResult, error)
+}
+
+type TemplateBundleOutput struct {
+	Version   int                     `json:"version"`
+	Templates []BundledTemplateOutput `json:"templates"`
+}
+
+type BundledTemplateOutput struct {
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Subject      string                 `json:"subject"`
+	Text         string                 `json:"text"`
+	HTML         string                 `json:"html"`
+	Markdown     string                 `json:"markdown"`
+	Layout       string                 `json:"layout"`
+	Metadata     map[string]interface{} `json:"metadata"`
+	Associations []TemplateAssociation  `json:"associations"`
+}
+
+type TemplateImportOutput struct {
+	DryRun    bool                         `json:"dry_run"`
+	Templates []TemplateImportChangeOutput `json:"templates"`
+}
+
+type TemplateImportChangeOutput struct {
+	ID           string                            `json:"id"`
+	ImportedID   string                            `json:"imported_id"`
+	Name         string                            `json:"name"`
+	Action       string                            `json:"action"`
+	Changes      map[string]string                 `json:"changes,omitempty"`
+	Associations []TemplateAssociationImportOutput `json:"associations"`
+}
+
+type TemplateAssociationImportOutput struct {
+	Client       string `json:"client"`
+	Notification string `json:"notification,omitempty"`
+	Action       string `json:"action"`
+}
+
+type ExportHandler struct {
+	collection  templateBundlesCollection
+	errorWriter errorWriter
+}
+
+func NewExportHandler(collection templateBundlesCollection, errWriter errorWriter) ExportHandler {
+	return ExportHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ExportHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	database := context.Get("database").(DatabaseInterface)
+
+	bundle, err := h.collection.Export(database.Connection())
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output := TemplateBundleOutput{
+		Version:   bundle.Version,
+		Templates: []BundledTemplateOutput{},
+	}
+
+	for _, template := range bundle.Templates {
+		var metadata map[string]interface{}
+		err = json.Unmarshal([]byte(template.Metadata), &metadata)
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		associations := []TemplateAssociation{}
+		for _, association := range template.Associations {
+			associations = append(associations, TemplateAssociation{
+				Client:       association.ClientID,
+				Notification: association.NotificationID,
+			})
+		}
+
+		output.Templates = append(output.Templates, BundledTemplateOutput{
+			ID:           template.ID,
+			Name:         template.Name,
+			Subject:      template.Subject,
+			Text:         template.Text,
+			HTML:         template.HTML,
+			Markdown:     template.Markdown,
+			Layout:       template.Layout,
+			Metadata:     metadata,
+			Associations: associations,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
+
+type ImportHandler struct {
+	collection  templateBundlesCollection
+	partials    templatePartialsLister
+	errorWriter errorWriter
+}
+
+func NewImportHandler(collection templateBundlesCollection, partials templatePartialsLister, errWriter errorWriter) ImportHandler {
+	return ImportHandler{
+		collection:  collection,
+		partials:    partials,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ImportHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	options, err := NewTemplateImportOptions(req.URL.Query())
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	bundle, err := NewTemplateBundleParams(req.Body)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	connection := context.Get("database").(DatabaseInterface).Connection()
+
+	err = bundle.validatePartials(connection, h.partials)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	result, err := h.collection.Import(connection, bundle.ToBundle(), options, context.Get("client_id").(string))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output := TemplateImportOutput{
+		DryRun:    result.DryRun,
+		Templates: []TemplateImportChangeOutput{},
+	}
+
+	for _, change := range result.Templates {
+		associations := []TemplateAssociationImportOutput{}
+		for _, association := range change.Associations {
+			associations = append(associations, TemplateAssociationImportOutput{
+				Client:       association.ClientID,
+				Notification: association.NotificationID,
+				Action:       association.Action,
+			})
+		}
+
+		changeOutput := TemplateImportChangeOutput{
+			ID:           change.ID,
+			ImportedID:   change.ImportedID,
+			Name:         change.Name,
+			Action:       change.Action,
+			Associations: associations,
+		}
+		if change.Action == collections.ImportActionUpdate {
+			changeOutput.Changes = diffChanges(change.Diff)
+		}
+
+		output.Templates = append(output.Templates, changeOutput)
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
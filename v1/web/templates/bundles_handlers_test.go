@@ -0,0 +1,260 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template bundle handlers", func() {
+	var (
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		collection  *mocks.TemplateBundlesCollection
+		partials    *mocks.TemplatePartialsCollection
+		errorWriter *mocks.ErrorWriter
+		conn        *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		collection = mocks.NewTemplateBundlesCollection()
+		partials = mocks.NewTemplatePartialsCollection()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
+	})
+
+	Describe("ExportHandler", func() {
+		It("writes the bundle", func() {
+			collection.ExportCall.Returns.Bundle = collections.TemplateBundle{
+				Version: 1,
+				Templates: []collections.BundledTemplate{
+					{
+						Template: collections.Template{
+							ID:       "some-template-id",
+							Name:     "Some template",
+							Subject:  "{{.Subject}}",
+							HTML:     "<p>{{.HTML}}</p>",
+							Metadata: `{"inline_css": true}`,
+						},
+						Associations: []collections.TemplateAssociation{
+							{ClientID: "some-client"},
+							{ClientID: "some-client", NotificationID: "some-kind"},
+						},
+					},
+				},
+			}
+
+			request, err := http.NewRequest("GET", "/templates/export", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewExportHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{
+				"version": 1,
+				"templates": [
+					{
+						"id": "some-template-id",
+						"name": "Some template",
+						"subject": "{{.Subject}}",
+						"text": "",
+						"html": "<p>{{.HTML}}</p>",
+						"markdown": "",
+						"layout": "",
+						"metadata": {"inline_css": true},
+						"associations": [
+							{"client": "some-client"},
+							{"client": "some-client", "notification": "some-kind"}
+						]
+					}
+				]
+			}`))
+
+			Expect(collection.ExportCall.Receives.Connection).To(Equal(conn))
+		})
+
+		It("delegates errors to the error writer", func() {
+			collection.ExportCall.Returns.Error = errors.New("BOOM!")
+
+			request, err := http.NewRequest("GET", "/templates/export", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewExportHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+
+	Describe("ImportHandler", func() {
+		var body string
+
+		BeforeEach(func() {
+			body = `{
+				"version": 1,
+				"templates": [
+					{
+						"id": "some-template-id",
+						"name": "Some template",
+						"html": "<p>{{.HTML}}</p>",
+						"metadata": {"inline_css": true},
+						"associations": [{"client": "some-client", "notification": "some-kind"}]
+					}
+				]
+			}`
+		})
+
+		It("imports the bundle and writes what changed", func() {
+			collection.ImportCall.Returns.Result = collections.TemplateImportResult{
+				DryRun: true,
+				Templates: []collections.TemplateImportChange{
+					{
+						ID:         "some-template-id",
+						ImportedID: "some-template-id",
+						Name:       "Some template",
+						Action:     collections.ImportActionUpdate,
+						Diff: collections.TemplateDiff{
+							TemplateID: "some-template-id",
+							HTML:       "-<p>old</p>\n+<p>{{.HTML}}</p>",
+						},
+						Associations: []collections.TemplateAssociationChange{
+							{
+								TemplateAssociation: collections.TemplateAssociation{ClientID: "some-client", NotificationID: "some-kind"},
+								Action:              collections.ImportActionMissing,
+							},
+						},
+					},
+				},
+			}
+
+			request, err := http.NewRequest("POST", "/templates/import?conflict=overwrite&dry_run=true", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewImportHandler(collection, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{
+				"dry_run": true,
+				"templates": [
+					{
+						"id": "some-template-id",
+						"imported_id": "some-template-id",
+						"name": "Some template",
+						"action": "update",
+						"changes": {"html": "-<p>old</p>\n+<p>{{.HTML}}</p>"},
+						"associations": [
+							{"client": "some-client", "notification": "some-kind", "action": "missing"}
+						]
+					}
+				]
+			}`))
+
+			Expect(collection.ImportCall.Receives.Connection).To(Equal(conn))
+			Expect(collection.ImportCall.Receives.ClientID).To(Equal("some-client-id"))
+			Expect(collection.ImportCall.Receives.Options).To(Equal(collections.TemplateImportOptions{
+				Conflict: collections.ImportConflictOverwrite,
+				DryRun:   true,
+			}))
+			Expect(collection.ImportCall.Receives.Bundle).To(Equal(collections.TemplateBundle{
+				Version: 1,
+				Templates: []collections.BundledTemplate{
+					{
+						Template: collections.Template{
+							ID:       "some-template-id",
+							Name:     "Some template",
+							Subject:  "{{.Subject}}",
+							HTML:     "<p>{{.HTML}}</p>",
+							Metadata: `{"inline_css": true}`,
+						},
+						Associations: []collections.TemplateAssociation{
+							{ClientID: "some-client", NotificationID: "some-kind"},
+						},
+					},
+				},
+			}))
+		})
+
+		It("skips conflicting templates by default", func() {
+			request, err := http.NewRequest("POST", "/templates/import", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewImportHandler(collection, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(collection.ImportCall.Receives.Options).To(Equal(collections.TemplateImportOptions{
+				Conflict: collections.ImportConflictSkip,
+			}))
+		})
+
+		It("rejects unknown conflict strategies", func() {
+			request, err := http.NewRequest("POST", "/templates/import?conflict=merge", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewImportHandler(collection, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"conflict" must be one of "skip", "overwrite" or "rename"`)}))
+			Expect(collection.ImportCall.WasCalled).To(BeFalse())
+		})
+
+		It("rejects templates that could not be created", func() {
+			body = `{"version": 1, "templates": [{"id": "some-template-id", "name": "Some template", "html": "{{.HTML"}]}`
+
+			request, err := http.NewRequest("POST", "/templates/import", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewImportHandler(collection, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`template "some-template-id": HTML syntax is malformed please check your braces`)}))
+			Expect(collection.ImportCall.WasCalled).To(BeFalse())
+		})
+
+		It("rejects templates whose partials do not exist", func() {
+			body = `{"version": 1, "templates": [{"id": "some-template-id", "name": "Some template", "html": "{{.HTML}}", "layout": "branded"}]}`
+
+			request, err := http.NewRequest("POST", "/templates/import", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewImportHandler(collection, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`template "some-template-id": layout "branded" does not exist`)}))
+			Expect(collection.ImportCall.WasCalled).To(BeFalse())
+		})
+
+		It("rejects bodies that are not JSON", func() {
+			request, err := http.NewRequest("POST", "/templates/import", bytes.NewBufferString("not json"))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewImportHandler(collection, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
+		})
+
+		It("delegates errors to the error writer", func() {
+			collection.ImportCall.Returns.Error = collections.TemplateImportError{Err: errors.New("bundle version 2 is not supported, expected 1")}
+
+			request, err := http.NewRequest("POST", "/templates/import", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewImportHandler(collection, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(collections.TemplateImportError{Err: errors.New("bundle version 2 is not supported, expected 1")}))
+		})
+	})
+})
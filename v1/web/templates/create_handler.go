@@ -14,17 +14,19 @@ type errorWriter interface {
 }
 
 type templateCreator interface {
-	Create(connection collections.ConnectionInterface, template collections.Template) (collections.Template, error)
+	Create(connection collections.ConnectionInterface, template collections.Template, clientID string) (collections.Template, error)
 }
 
 type CreateHandler struct {
 	creator     templateCreator
+	partials    templatePartialsLister
 	errorWriter errorWriter
 }
 
-func NewCreateHandler(creator templateCreator, errWriter errorWriter) CreateHandler {
+func NewCreateHandler(creator templateCreator, partials templatePartialsLister, errWriter errorWriter) CreateHandler {
 	return CreateHandler{
 		creator:     creator,
+		partials:    partials,
 		errorWriter: errWriter,
 	}
 }
@@ -38,13 +40,21 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 
 	connection := context.Get("database").(DatabaseInterface).Connection()
 
+	err = templateParams.validatePartials(connection, h.partials)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
 	template, err := h.creator.Create(connection, collections.Template{
 		Name:     templateParams.Name,
 		Text:     templateParams.Text,
 		HTML:     templateParams.HTML,
+		Markdown: templateParams.Markdown,
+		Layout:   templateParams.Layout,
 		Subject:  templateParams.Subject,
 		Metadata: string(templateParams.Metadata),
-	})
+	}, context.Get("client_id").(string))
 	if err != nil {
 		h.errorWriter.Write(w, webutil.TemplateCreateError{})
 		return
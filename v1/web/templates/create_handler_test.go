@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
@@ -26,6 +27,7 @@ var _ = Describe("CreateHandler", func() {
 		request     *http.Request
 		context     stack.Context
 		creator     *mocks.TemplateCreator
+		partials    *mocks.TemplatePartialsCollection
 		errorWriter *mocks.ErrorWriter
 		connection  *mocks.Connection
 	)
@@ -42,14 +44,16 @@ var _ = Describe("CreateHandler", func() {
 				Metadata: "{}",
 			}
 
+			partials = mocks.NewTemplatePartialsCollection()
 			errorWriter = mocks.NewErrorWriter()
 			writer = httptest.NewRecorder()
 			body := bytes.NewBuffer([]byte{})
 			err := json.NewEncoder(body).Encode(map[string]interface{}{
-				"name":    "Emergency Template",
-				"text":    "Message to: {{.To}}. Raptor Alert.",
-				"html":    "<p>{{.ClientID}} you should run.</p>",
-				"subject": "Raptor Containment Unit Breached",
+				"name":     "Emergency Template",
+				"text":     "Message to: {{.To}}. Raptor Alert.",
+				"html":     "<p>{{.ClientID}} you should run.</p>",
+				"markdown": "**{{.ClientID}}** you should _run_.",
+				"subject":  "Raptor Containment Unit Breached",
 			})
 			Expect(err).NotTo(HaveOccurred())
 
@@ -59,11 +63,12 @@ var _ = Describe("CreateHandler", func() {
 
 			context = stack.NewContext()
 			context.Set("database", database)
+			context.Set("client_id", "some-client-id")
 
 			request, err = http.NewRequest("POST", "/templates", body)
 			Expect(err).NotTo(HaveOccurred())
 
-			handler = templates.NewCreateHandler(creator, errorWriter)
+			handler = templates.NewCreateHandler(creator, partials, errorWriter)
 		})
 
 		It("calls create on its Creator with the correct arguments", func() {
@@ -74,12 +79,59 @@ var _ = Describe("CreateHandler", func() {
 				Name:     "Emergency Template",
 				Text:     "Message to: {{.To}}. Raptor Alert.",
 				HTML:     "<p>{{.ClientID}} you should run.</p>",
+				Markdown: "**{{.ClientID}}** you should _run_.",
 				Subject:  "Raptor Containment Unit Breached",
 				Metadata: "{}",
 			}))
+			Expect(creator.CreateCall.Receives.ClientID).To(Equal("some-client-id"))
 
 			Expect(writer.Code).To(Equal(http.StatusCreated))
 			Expect(writer.Body.String()).To(MatchJSON(`{"template_id":"template-guid"}`))
+			Expect(partials.ListCall.CallCount).To(Equal(0))
+		})
+
+		Context("when the template uses partials", func() {
+			BeforeEach(func() {
+				request, err = http.NewRequest("POST", "/templates", bytes.NewBufferString(`{
+					"name": "Branded Template",
+					"layout": "branded",
+					"text": "{{.Text}}",
+					"html": "<p>{{.HTML}}</p>{{template \"footer\" .}}"
+				}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				partials.ListCall.Returns.Partials = []collections.TemplatePartial{
+					{Name: "branded", Text: `{{template "content" .}}`, HTML: `<main>{{template "content" .}}</main>`},
+					{Name: "footer", HTML: "<footer>{{.Domain}}</footer>"},
+				}
+			})
+
+			It("validates the template against the stored partials", func() {
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusCreated))
+				Expect(partials.ListCall.Receives.Connection).To(Equal(connection))
+				Expect(creator.CreateCall.Receives.Template.Layout).To(Equal("branded"))
+				Expect(creator.CreateCall.Receives.Template.HTML).To(Equal(`<p>{{.HTML}}</p>{{template "footer" .}}`))
+			})
+
+			It("writes a validation error when a partial does not exist", func() {
+				partials.ListCall.Returns.Partials = partials.ListCall.Returns.Partials[:1]
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: common.PartialError{Err: errors.New(`partial "footer" does not exist`)}}))
+				Expect(creator.CreateCall.Receives.Template).To(Equal(collections.Template{}))
+			})
+
+			It("writes a validation error when the resolved template cannot be rendered", func() {
+				partials.ListCall.Returns.Partials[1].HTML = "<footer>{{.Missing}}</footer>"
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+				Expect(errorWriter.WriteCall.Receives.Error.Error()).To(ContainSubstring("template could not be rendered"))
+			})
 		})
 
 		Context("when an errors occurs", func() {
@@ -43,7 +43,10 @@ func (h GetDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, c
 		Subject:  template.Subject,
 		HTML:     template.HTML,
 		Text:     template.Text,
+		Markdown: template.Markdown,
+		Layout:   template.Layout,
 		Metadata: metadata,
+		Version:  template.Version,
 	}
 
 	writeJSON(w, http.StatusOK, templateOutput)
@@ -33,6 +33,7 @@ var _ = Describe("GetDefaultHandler", func() {
 			Text:     "Default Template {{.Text}}",
 			HTML:     "<p>Default Template</p> {{.HTML}}",
 			Metadata: "{}",
+			Version:  2,
 		}
 
 		database = mocks.NewDatabase()
@@ -55,7 +56,10 @@ var _ = Describe("GetDefaultHandler", func() {
 			"subject": "CF Notification: {{.Subject}}",
 			"text": "Default Template {{.Text}}",
 			"html": "<p>Default Template</p> {{.HTML}}",
-			"metadata": {}
+			"markdown": "",
+			"layout": "",
+			"metadata": {},
+			"version": 2
 		}`))
 
 		Expect(templateFinder.FindByIDCall.Receives.Database).To(Equal(database))
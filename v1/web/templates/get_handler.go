@@ -13,7 +13,10 @@ type TemplateOutput struct {
 	Subject  string                 `json:"subject"`
 	HTML     string                 `json:"html"`
 	Text     string                 `json:"text"`
+	Markdown string                 `json:"markdown"`
+	Layout   string                 `json:"layout"`
 	Metadata map[string]interface{} `json:"metadata"`
+	Version  int                    `json:"version"`
 }
 
 type GetHandler struct {
@@ -49,7 +52,10 @@ func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 		Subject:  template.Subject,
 		HTML:     template.HTML,
 		Text:     template.Text,
+		Markdown: template.Markdown,
+		Layout:   template.Layout,
 		Metadata: metadata,
+		Version:  template.Version,
 	}
 
 	writeJSON(w, http.StatusOK, templateOutput)
@@ -39,7 +39,9 @@ var _ = Describe("GetHandler", func() {
 				Subject:  "All about the {{.Subject}}",
 				Text:     "the template {{variable}}",
 				HTML:     "<p> the template {{variable}} </p>",
+				Markdown: "the *template* {{variable}}",
 				Metadata: `{"hello": "world"}`,
+				Version:  3,
 			}
 			writer = httptest.NewRecorder()
 			errorWriter = mocks.NewErrorWriter()
@@ -74,11 +76,14 @@ var _ = Describe("GetHandler", func() {
 					panic(err)
 				}
 
-				Expect(template).To(HaveLen(5))
+				Expect(template).To(HaveLen(8))
 				Expect(template["name"]).To(Equal("The Name of The Template"))
 				Expect(template["subject"]).To(Equal("All about the {{.Subject}}"))
 				Expect(template["text"]).To(Equal("the template {{variable}}"))
+				Expect(template["version"]).To(Equal(float64(3)))
 				Expect(template["html"]).To(Equal("<p> the template {{variable}} </p>"))
+				Expect(template["markdown"]).To(Equal("the *template* {{variable}}"))
+				Expect(template["layout"]).To(Equal(""))
 				Expect(template["metadata"]).To(Equal(map[string]interface{}{"hello": "world"}))
 			})
 		})
@@ -5,6 +5,8 @@ import (
 	"regexp"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/ryanmoran/stack"
 )
 
@@ -14,7 +16,7 @@ type TemplateAssociation struct {
 }
 
 type templateAssociationLister interface {
-	ListAssociations(connection collections.ConnectionInterface, templateID string) ([]collections.TemplateAssociation, error)
+	ListAssociations(connection collections.ConnectionInterface, templateID string, page models.Page) (associations []collections.TemplateAssociation, next []string, err error)
 }
 
 type ListAssociationsHandler struct {
@@ -33,12 +35,20 @@ func (h ListAssociationsHandler) ServeHTTP(w http.ResponseWriter, req *http.Requ
 	templateID := h.parseTemplateID(req.URL.Path)
 	database := context.Get("database").(DatabaseInterface)
 
-	associations, err := h.lister.ListAssociations(database.Connection(), templateID)
+	page, err := webutil.NewLegacyPage(req.URL.Query())
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
+	associations, next, err := h.lister.ListAssociations(database.Connection(), templateID, page)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	webutil.WriteNextPageLink(w, req, page, next)
+
 	templateAssociationsDocument := h.mapToJSON(associations)
 	writeJSON(w, http.StatusOK, templateAssociationsDocument)
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
 	"github.com/ryanmoran/stack"
 
@@ -96,6 +97,21 @@ var _ = Describe("ListAssociationsHandler", func() {
 
 		Expect(lister.ListCall.Receives.Connection).To(Equal(connection))
 		Expect(lister.ListCall.Receives.TemplateID).To(Equal(templateID))
+		Expect(lister.ListCall.Receives.Page).To(Equal(models.Page{}))
+		Expect(writer.Header()).NotTo(HaveKey("Link"))
+	})
+
+	It("links to the next page of associations", func() {
+		lister.ListCall.Returns.Next = []string{"client", "some-client"}
+
+		request, err := http.NewRequest("GET", "/templates/"+templateID+"/associations?limit=1", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+		Expect(writer.Code).To(Equal(http.StatusOK))
+
+		Expect(lister.ListCall.Receives.Page).To(Equal(models.Page{Limit: 1}))
+		Expect(writer.Header().Get("Link")).To(MatchRegexp(`^</templates/banana-template/associations\?cursor=[\w-]+&limit=1>; rel="next"$`))
 	})
 
 	Context("when errors occur", func() {
@@ -1,14 +1,19 @@
 package templates
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/ryanmoran/stack"
 )
 
 type templateLister interface {
-	List(database services.DatabaseInterface) (templateSummaries map[string]services.TemplateSummary, err error)
+	List(database services.DatabaseInterface, filter models.TemplatesFilter, page models.Page) (templateSummaries map[string]services.TemplateSummary, next []string, err error)
 }
 
 type ListHandler struct {
@@ -24,11 +29,42 @@ func NewListHandler(lister templateLister, errWriter errorWriter) ListHandler {
 }
 
 func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
-	templates, err := h.lister.List(context.Get("database").(DatabaseInterface))
+	query := req.URL.Query()
+
+	page, err := webutil.NewLegacyPage(query)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
+	filter, err := newTemplatesFilter(query)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	templates, next, err := h.lister.List(context.Get("database").(DatabaseInterface), filter, page)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	webutil.WriteNextPageLink(w, req, page, next)
 	writeJSON(w, http.StatusOK, templates)
 }
+
+func newTemplatesFilter(query url.Values) (models.TemplatesFilter, error) {
+	filter := models.TemplatesFilter{
+		Name: query.Get("name"),
+	}
+
+	if updatedSince := query.Get("updated_since"); updatedSince != "" {
+		var err error
+		filter.UpdatedSince, err = time.Parse(time.RFC3339, updatedSince)
+		if err != nil {
+			return filter, webutil.ValidationError{Err: errors.New(`"updated_since" must be an RFC 3339 timestamp`)}
+		}
+	}
+
+	return filter, nil
+}
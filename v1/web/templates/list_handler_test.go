@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/ryanmoran/stack"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -81,8 +85,74 @@ var _ = Describe("ListHandler", func() {
 
 				Expect(len(templates)).To(Equal(4))
 				Expect(templates).To(Equal(testTemplates))
+				Expect(writer.Header()).NotTo(HaveKey("Link"))
 			})
 
+			It("lists every template when the request does not ask for a page", func() {
+				manyTemplates := map[string]services.TemplateSummary{}
+				for i := 0; i < webutil.DefaultPageLimit+10; i++ {
+					manyTemplates[fmt.Sprintf("template-%d", i)] = services.TemplateSummary{Name: fmt.Sprintf("Template %d", i)}
+				}
+				lister.ListCall.Returns.TemplateSummaries = manyTemplates
+
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(http.StatusOK))
+				Expect(lister.ListCall.Receives.Page).To(Equal(models.Page{}))
+
+				var templates map[string]services.TemplateSummary
+				err := json.Unmarshal(writer.Body.Bytes(), &templates)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(templates).To(HaveLen(webutil.DefaultPageLimit + 10))
+				Expect(writer.Header()).NotTo(HaveKey("Link"))
+			})
+		})
+
+		Context("when the request filters and paginates the templates", func() {
+			BeforeEach(func() {
+				lister.ListCall.Returns.Next = []string{"starvation-guid"}
+
+				var err error
+				request, err = http.NewRequest("GET", "/templates?name=star&updated_since=2015-06-01T12:00:00Z&limit=4", nil)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("passes the filter and page to the lister", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(http.StatusOK))
+
+				Expect(lister.ListCall.Receives.Filter).To(Equal(models.TemplatesFilter{
+					Name:         "star",
+					UpdatedSince: time.Date(2015, time.June, 1, 12, 0, 0, 0, time.UTC),
+				}))
+				Expect(lister.ListCall.Receives.Page).To(Equal(models.Page{Limit: 4}))
+			})
+
+			It("links to the next page", func() {
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Header().Get("Link")).To(MatchRegexp(`^</templates\?cursor=[\w-]+&limit=4&name=star&updated_since=2015-06-01T12%3A00%3A00Z>; rel="next"$`))
+			})
+		})
+
+		Context("when the request is not valid", func() {
+			It("rejects timestamps that are not RFC 3339", func() {
+				request, err := http.NewRequest("GET", "/templates?updated_since=yesterday", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"updated_since" must be an RFC 3339 timestamp`)}))
+			})
+
+			It("rejects limits that are out of range", func() {
+				request, err := http.NewRequest("GET", "/templates?limit=0", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+				Expect(lister.ListCall.Receives.Database).To(BeNil())
+			})
 		})
 
 		Context("when the lister errors", func() {
@@ -0,0 +1,86 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/cloudfoundry-incubator/notifications/valiant"
+)
+
+type LocaleParams struct {
+	Subject  string `json:"subject"`
+	Text     string `json:"text"`
+	HTML     string `json:"html"`
+	Markdown string `json:"markdown"`
+}
+
+// NewLocaleParams parses the body of a locale variant and checks the syntax
+// of its parts. Parts that are left empty fall back to the default template
+// when the message is rendered, so rendering the variant is left to
+// validateExecution, which knows that template.
+func NewLocaleParams(body io.ReadCloser) (LocaleParams, error) {
+	defer body.Close()
+
+	var params LocaleParams
+	err := valiant.NewValidator(body).Validate(&params)
+	if err != nil {
+		return params, webutil.ParseError{}
+	}
+
+	if params.Subject == "" && params.Text == "" && params.HTML == "" && params.Markdown == "" {
+		return params, webutil.ValidationError{Err: errors.New(`"subject", "text", "html" or "markdown" must be supplied`)}
+	}
+
+	err = params.templateParams().validateSyntax()
+	if err != nil {
+		return LocaleParams{}, err
+	}
+
+	return params, nil
+}
+
+// validateExecution renders the variant as it is applied to the template it
+// belongs to, so that it is checked against the layout, partials and escaping
+// of that template the same way the template itself is.
+func (l LocaleParams) validateExecution(conn collections.ConnectionInterface, partials templatePartialsLister, template models.Template) error {
+	localized := common.Localize(common.Templates{
+		Subject:  template.Subject,
+		Text:     template.Text,
+		HTML:     template.HTML,
+		Markdown: template.Markdown,
+	}, common.Templates{
+		Subject:  l.Subject,
+		Text:     l.Text,
+		HTML:     l.HTML,
+		Markdown: l.Markdown,
+	})
+
+	params := TemplateParams{
+		Subject:  localized.Subject,
+		Text:     localized.Text,
+		HTML:     localized.HTML,
+		Markdown: localized.Markdown,
+		Layout:   template.Layout,
+		Metadata: json.RawMessage(template.Metadata),
+	}
+
+	if params.usesPartials() {
+		return params.validatePartials(conn, partials)
+	}
+
+	return validateExecution(params.ToModel())
+}
+
+func (l LocaleParams) templateParams() TemplateParams {
+	return TemplateParams{
+		Subject:  l.Subject,
+		Text:     l.Text,
+		HTML:     l.HTML,
+		Markdown: l.Markdown,
+	}
+}
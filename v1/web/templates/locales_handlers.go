@@ -0,0 +1,204 @@
+package templates
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/locale"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type templateLocalesCollection interface {
+	List(conn collections.ConnectionInterface, templateID string) ([]collections.TemplateLocale, error)
+	Get(conn collections.ConnectionInterface, templateID, locale string) (collections.TemplateLocale, error)
+	Set(conn collections.ConnectionInterface, variant collections.TemplateLocale) (collections.TemplateLocale, error)
+	Delete(conn collections.ConnectionInterface, templateID, locale string) error
+}
+
+type TemplateLocaleOutput struct {
+	Locale    string    `json:"locale"`
+	Subject   string    `json:"subject"`
+	Text      string    `json:"text"`
+	HTML      string    `json:"html"`
+	Markdown  string    `json:"markdown"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var (
+	localesPath = regexp.MustCompile(`/templates/(.*)/locales$`)
+	localePath  = regexp.MustCompile(`/templates/(.*)/locales/([^/]*)$`)
+)
+
+type ListLocalesHandler struct {
+	collection  templateLocalesCollection
+	errorWriter errorWriter
+}
+
+func NewListLocalesHandler(collection templateLocalesCollection, errWriter errorWriter) ListLocalesHandler {
+	return ListLocalesHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListLocalesHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := localesPath.FindStringSubmatch(req.URL.Path)[1]
+	database := context.Get("database").(DatabaseInterface)
+
+	variants, err := h.collection.List(database.Connection(), templateID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := map[string][]TemplateLocaleOutput{
+		"locales": {},
+	}
+	for _, variant := range variants {
+		document["locales"] = append(document["locales"], newTemplateLocaleOutput(variant))
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
+
+type GetLocaleHandler struct {
+	collection  templateLocalesCollection
+	errorWriter errorWriter
+}
+
+func NewGetLocaleHandler(collection templateLocalesCollection, errWriter errorWriter) GetLocaleHandler {
+	return GetLocaleHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetLocaleHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, tag, err := parseLocalePath(req)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	variant, err := h.collection.Get(database.Connection(), templateID, tag)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTemplateLocaleOutput(variant))
+}
+
+type SetLocaleHandler struct {
+	collection  templateLocalesCollection
+	finder      templateFinder
+	partials    templatePartialsLister
+	errorWriter errorWriter
+}
+
+func NewSetLocaleHandler(collection templateLocalesCollection, finder templateFinder, partials templatePartialsLister, errWriter errorWriter) SetLocaleHandler {
+	return SetLocaleHandler{
+		collection:  collection,
+		finder:      finder,
+		partials:    partials,
+		errorWriter: errWriter,
+	}
+}
+
+func (h SetLocaleHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, tag, err := parseLocalePath(req)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	params, err := NewLocaleParams(req.Body)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	template, err := h.finder.FindByID(database, templateID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	err = params.validateExecution(database.Connection(), h.partials, template)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	variant, err := h.collection.Set(database.Connection(), collections.TemplateLocale{
+		TemplateID: templateID,
+		Locale:     tag,
+		Subject:    params.Subject,
+		Text:       params.Text,
+		HTML:       params.HTML,
+		Markdown:   params.Markdown,
+	})
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTemplateLocaleOutput(variant))
+}
+
+type DeleteLocaleHandler struct {
+	collection  templateLocalesCollection
+	errorWriter errorWriter
+}
+
+func NewDeleteLocaleHandler(collection templateLocalesCollection, errWriter errorWriter) DeleteLocaleHandler {
+	return DeleteLocaleHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DeleteLocaleHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, tag, err := parseLocalePath(req)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	err = h.collection.Delete(database.Connection(), templateID, tag)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseLocalePath(req *http.Request) (string, string, error) {
+	matches := localePath.FindStringSubmatch(req.URL.Path)
+
+	tag, err := locale.Parse(matches[2])
+	if err != nil {
+		return "", "", webutil.ValidationError{Err: err}
+	}
+
+	return matches[1], tag, nil
+}
+
+func newTemplateLocaleOutput(variant collections.TemplateLocale) TemplateLocaleOutput {
+	return TemplateLocaleOutput{
+		Locale:    variant.Locale,
+		Subject:   variant.Subject,
+		Text:      variant.Text,
+		HTML:      variant.HTML,
+		Markdown:  variant.Markdown,
+		UpdatedAt: variant.UpdatedAt,
+	}
+}
@@ -0,0 +1,287 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/locale"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template locale handlers", func() {
+	var (
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		collection  *mocks.TemplateLocalesCollection
+		finder      *mocks.TemplateFinder
+		partials    *mocks.TemplatePartialsCollection
+		errorWriter *mocks.ErrorWriter
+		conn        *mocks.Connection
+		updatedAt   time.Time
+	)
+
+	BeforeEach(func() {
+		collection = mocks.NewTemplateLocalesCollection()
+		finder = mocks.NewTemplateFinder()
+		finder.FindByIDCall.Returns.Template = models.Template{
+			ID:       "some-template-id",
+			Subject:  "{{.Subject}}",
+			Text:     "{{.Text}}",
+			HTML:     "<p>{{.HTML}}</p>",
+			Metadata: "{}",
+		}
+		partials = mocks.NewTemplatePartialsCollection()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		updatedAt = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+	})
+
+	Describe("ListLocalesHandler", func() {
+		It("lists the locale variants of the template", func() {
+			collection.ListCall.Returns.Locales = []collections.TemplateLocale{
+				{Locale: "de", Subject: "Betreff", Text: "Hallo", HTML: "<p>Hallo</p>", UpdatedAt: updatedAt},
+				{Locale: "fr", Text: "Bonjour", UpdatedAt: updatedAt},
+			}
+
+			request, err := http.NewRequest("GET", "/templates/some-template-id/locales", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewListLocalesHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{
+				"locales": [
+					{"locale": "de", "subject": "Betreff", "text": "Hallo", "html": "<p>Hallo</p>", "markdown": "", "updated_at": "2015-06-01T12:00:00Z"},
+					{"locale": "fr", "subject": "", "text": "Bonjour", "html": "", "markdown": "", "updated_at": "2015-06-01T12:00:00Z"}
+				]
+			}`))
+
+			Expect(collection.ListCall.Receives.Connection).To(Equal(conn))
+			Expect(collection.ListCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		It("delegates errors to the error writer", func() {
+			collection.ListCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			request, err := http.NewRequest("GET", "/templates/missing-template-id/locales", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewListLocalesHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("GetLocaleHandler", func() {
+		It("returns the locale variant, looking it up by its canonical tag", func() {
+			collection.GetCall.Returns.Locale = collections.TemplateLocale{Locale: "de-CH", Text: "Grüezi", UpdatedAt: updatedAt}
+
+			request, err := http.NewRequest("GET", "/templates/some-template-id/locales/de_ch", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewGetLocaleHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{"locale": "de-CH", "subject": "", "text": "Grüezi", "html": "", "markdown": "", "updated_at": "2015-06-01T12:00:00Z"}`))
+
+			Expect(collection.GetCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(collection.GetCall.Receives.Locale).To(Equal("de-CH"))
+		})
+
+		It("writes a validation error when the locale is malformed", func() {
+			request, err := http.NewRequest("GET", "/templates/some-template-id/locales/german", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewGetLocaleHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: locale.ParseError{Locale: "german"}}))
+		})
+	})
+
+	Describe("SetLocaleHandler", func() {
+		It("saves the locale variant", func() {
+			collection.SetCall.Returns.Locale = collections.TemplateLocale{Locale: "fr", Subject: "Alerte : {{.Subject}}", Text: "Bonjour {{.Text}}", Markdown: "*Bonjour*", UpdatedAt: updatedAt}
+
+			request, err := http.NewRequest("PUT", "/templates/some-template-id/locales/fr", bytes.NewBufferString(`{"subject": "Alerte : {{.Subject}}", "text": "Bonjour {{.Text}}", "markdown": "*Bonjour*"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{"locale": "fr", "subject": "Alerte : {{.Subject}}", "text": "Bonjour {{.Text}}", "html": "", "markdown": "*Bonjour*", "updated_at": "2015-06-01T12:00:00Z"}`))
+
+			Expect(collection.SetCall.Receives.Connection).To(Equal(conn))
+			Expect(collection.SetCall.Receives.Locale).To(Equal(collections.TemplateLocale{
+				TemplateID: "some-template-id",
+				Locale:     "fr",
+				Subject:    "Alerte : {{.Subject}}",
+				Text:       "Bonjour {{.Text}}",
+				Markdown:   "*Bonjour*",
+			}))
+		})
+
+		It("requires at least one part of the template", func() {
+			request, err := http.NewRequest("PUT", "/templates/some-template-id/locales/fr", bytes.NewBufferString(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"subject", "text", "html" or "markdown" must be supplied`)}))
+			Expect(collection.SetCall.WasCalled).To(BeFalse())
+		})
+
+		It("rejects variants that cannot be rendered", func() {
+			request, err := http.NewRequest("PUT", "/templates/some-template-id/locales/fr", bytes.NewBufferString(`{"text": "{{.Missing}}"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+			Expect(collection.SetCall.WasCalled).To(BeFalse())
+		})
+
+		It("renders the variant with the layout of the template", func() {
+			finder.FindByIDCall.Returns.Template.Layout = "branded"
+			partials.ListCall.Returns.Partials = []collections.TemplatePartial{
+				{Name: "branded", HTML: "<div>{{template \"content\" .}}{{.Missing}}</div>"},
+			}
+
+			request, err := http.NewRequest("PUT", "/templates/some-template-id/locales/fr", bytes.NewBufferString(`{"html": "<p>Bonjour</p>"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(finder.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(ContainSubstring("Missing")))
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+			Expect(collection.SetCall.WasCalled).To(BeFalse())
+		})
+
+		It("renders an HTML variant of a Markdown template in place of the Markdown", func() {
+			finder.FindByIDCall.Returns.Template.Markdown = "# {{.Subject}}"
+
+			request, err := http.NewRequest("PUT", "/templates/some-template-id/locales/fr", bytes.NewBufferString(`{"html": "<p>{{.Missing}}</p>"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+			Expect(collection.SetCall.WasCalled).To(BeFalse())
+		})
+
+		It("delegates errors finding the template to the error writer", func() {
+			finder.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			request, err := http.NewRequest("PUT", "/templates/some-template-id/locales/fr", bytes.NewBufferString(`{"text": "Bonjour"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+			Expect(collection.SetCall.WasCalled).To(BeFalse())
+		})
+
+		Context("when the variant uses partials", func() {
+			BeforeEach(func() {
+				partials.ListCall.Returns.Partials = []collections.TemplatePartial{
+					{Name: "footer", HTML: "<footer>{{.Domain}}</footer>"},
+				}
+			})
+
+			It("validates the variant against the stored partials", func() {
+				request, err := http.NewRequest("PUT", "/templates/some-template-id/locales/fr", bytes.NewBufferString(`{"html": "<p>Bonjour</p>{{template \"footer\" .}}"}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusOK))
+				Expect(partials.ListCall.Receives.Connection).To(Equal(conn))
+				Expect(collection.SetCall.Receives.Locale.HTML).To(Equal(`<p>Bonjour</p>{{template "footer" .}}`))
+			})
+
+			It("writes a validation error when a partial does not exist", func() {
+				request, err := http.NewRequest("PUT", "/templates/some-template-id/locales/fr", bytes.NewBufferString(`{"html": "<p>Bonjour</p>{{template \"header\" .}}"}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: common.PartialError{Err: errors.New(`partial "header" does not exist`)}}))
+				Expect(collection.SetCall.WasCalled).To(BeFalse())
+			})
+
+			It("writes a validation error when the variant cannot be rendered", func() {
+				request, err := http.NewRequest("PUT", "/templates/some-template-id/locales/fr", bytes.NewBufferString(`{"html": "<p>{{.Missing}}</p>{{template \"footer\" .}}"}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+				Expect(collection.SetCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		It("writes a parse error when the body is not valid JSON", func() {
+			request, err := http.NewRequest("PUT", "/templates/some-template-id/locales/fr", bytes.NewBufferString(`{`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
+		})
+
+		It("delegates errors from the collection to the error writer", func() {
+			collection.SetCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			request, err := http.NewRequest("PUT", "/templates/missing-template-id/locales/fr", bytes.NewBufferString(`{"text": "Bonjour"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetLocaleHandler(collection, finder, partials, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("DeleteLocaleHandler", func() {
+		It("removes the locale variant", func() {
+			request, err := http.NewRequest("DELETE", "/templates/some-template-id/locales/de-CH", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewDeleteLocaleHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+			Expect(collection.DeleteCall.Receives.Connection).To(Equal(conn))
+			Expect(collection.DeleteCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(collection.DeleteCall.Receives.Locale).To(Equal("de-CH"))
+		})
+
+		It("delegates errors to the error writer", func() {
+			collection.DeleteCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			request, err := http.NewRequest("DELETE", "/templates/some-template-id/locales/de", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewDeleteLocaleHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+})
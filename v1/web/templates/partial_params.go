@@ -0,0 +1,32 @@
+package templates
+
+import (
+	"errors"
+	"io"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/cloudfoundry-incubator/notifications/valiant"
+)
+
+type PartialParams struct {
+	Text string `json:"text"`
+	HTML string `json:"html"`
+}
+
+// NewPartialParams parses the body of a partial. Whether the partial parses
+// and resolves is checked by the collection against the other partials.
+func NewPartialParams(body io.ReadCloser) (PartialParams, error) {
+	defer body.Close()
+
+	var params PartialParams
+	err := valiant.NewValidator(body).Validate(&params)
+	if err != nil {
+		return params, webutil.ParseError{}
+	}
+
+	if params.Text == "" && params.HTML == "" {
+		return params, webutil.ValidationError{Err: errors.New(`"text" or "html" must be supplied`)}
+	}
+
+	return params, nil
+}
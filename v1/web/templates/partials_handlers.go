@@ -0,0 +1,223 @@
+package templates
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type templatePartialsLister interface {
+	List(conn collections.ConnectionInterface) ([]collections.TemplatePartial, error)
+}
+
+type templatePartialsCollection interface {
+	templatePartialsLister
+	Get(conn collections.ConnectionInterface, name string) (collections.TemplatePartial, error)
+	Set(conn collections.ConnectionInterface, partial collections.TemplatePartial) (collections.TemplatePartial, error)
+	Delete(conn collections.ConnectionInterface, name string) error
+}
+
+type TemplatePartialOutput struct {
+	Name      string    `json:"name"`
+	Text      string    `json:"text"`
+	HTML      string    `json:"html"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var (
+	partialPath = regexp.MustCompile(`/template_partials/([^/]*)$`)
+	partialName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+type ListPartialsHandler struct {
+	collection  templatePartialsCollection
+	errorWriter errorWriter
+}
+
+func NewListPartialsHandler(collection templatePartialsCollection, errWriter errorWriter) ListPartialsHandler {
+	return ListPartialsHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListPartialsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	database := context.Get("database").(DatabaseInterface)
+
+	partials, err := h.collection.List(database.Connection())
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := map[string][]TemplatePartialOutput{
+		"partials": {},
+	}
+	for _, partial := range partials {
+		document["partials"] = append(document["partials"], newTemplatePartialOutput(partial))
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
+
+type GetPartialHandler struct {
+	collection  templatePartialsCollection
+	errorWriter errorWriter
+}
+
+func NewGetPartialHandler(collection templatePartialsCollection, errWriter errorWriter) GetPartialHandler {
+	return GetPartialHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetPartialHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	name, err := parsePartialPath(req)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	partial, err := h.collection.Get(database.Connection(), name)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTemplatePartialOutput(partial))
+}
+
+type SetPartialHandler struct {
+	collection  templatePartialsCollection
+	errorWriter errorWriter
+}
+
+func NewSetPartialHandler(collection templatePartialsCollection, errWriter errorWriter) SetPartialHandler {
+	return SetPartialHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h SetPartialHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	name, err := parsePartialPath(req)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	params, err := NewPartialParams(req.Body)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	partial, err := h.collection.Set(database.Connection(), collections.TemplatePartial{
+		Name: name,
+		Text: params.Text,
+		HTML: params.HTML,
+	})
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTemplatePartialOutput(partial))
+}
+
+type DeletePartialHandler struct {
+	collection  templatePartialsCollection
+	errorWriter errorWriter
+}
+
+func NewDeletePartialHandler(collection templatePartialsCollection, errWriter errorWriter) DeletePartialHandler {
+	return DeletePartialHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DeletePartialHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	name, err := parsePartialPath(req)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	err = h.collection.Delete(database.Connection(), name)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parsePartialPath(req *http.Request) (string, error) {
+	name := partialPath.FindStringSubmatch(req.URL.Path)[1]
+	if !partialName.MatchString(name) {
+		return "", webutil.ValidationError{Err: errors.New("partial names may only contain letters, digits, \"_\", \".\" and \"-\"")}
+	}
+
+	return name, nil
+}
+
+// resolvePartials wraps the template into its layout and defines the
+// partials it includes, as the worker does before rendering a message.
+func resolvePartials(conn collections.ConnectionInterface, lister templatePartialsLister, template models.Template) (models.Template, error) {
+	templates := common.Templates{
+		Text:     template.Text,
+		HTML:     template.HTML,
+		Markdown: template.Markdown,
+		Layout:   template.Layout,
+	}
+
+	if !common.UsesPartials(templates) {
+		return template, nil
+	}
+
+	partials, err := lister.List(conn)
+	if err != nil {
+		return models.Template{}, err
+	}
+
+	byName := map[string]common.Partial{}
+	for _, partial := range partials {
+		byName[partial.Name] = common.Partial{
+			Name: partial.Name,
+			Text: partial.Text,
+			HTML: partial.HTML,
+		}
+	}
+
+	resolved, err := common.ResolvePartials(templates, byName)
+	if err != nil {
+		return models.Template{}, webutil.ValidationError{Err: err}
+	}
+
+	template.Text = resolved.Text
+	template.HTML = resolved.HTML
+	template.Markdown = resolved.Markdown
+
+	return template, nil
+}
+
+func newTemplatePartialOutput(partial collections.TemplatePartial) TemplatePartialOutput {
+	return TemplatePartialOutput{
+		Name:      partial.Name,
+		Text:      partial.Text,
+		HTML:      partial.HTML,
+		UpdatedAt: partial.UpdatedAt,
+	}
+}
@@ -0,0 +1,189 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template partial handlers", func() {
+	var (
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		collection  *mocks.TemplatePartialsCollection
+		errorWriter *mocks.ErrorWriter
+		conn        *mocks.Connection
+		updatedAt   time.Time
+	)
+
+	BeforeEach(func() {
+		collection = mocks.NewTemplatePartialsCollection()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		updatedAt = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+	})
+
+	Describe("ListPartialsHandler", func() {
+		It("lists the partials", func() {
+			collection.ListCall.Returns.Partials = []collections.TemplatePartial{
+				{Name: "footer", Text: "-- The Team", HTML: "<footer>The Team</footer>", UpdatedAt: updatedAt},
+				{Name: "layout", Text: `{{template "content" .}}`, UpdatedAt: updatedAt},
+			}
+
+			request, err := http.NewRequest("GET", "/template_partials", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewListPartialsHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{
+				"partials": [
+					{"name": "footer", "text": "-- The Team", "html": "<footer>The Team</footer>", "updated_at": "2015-06-01T12:00:00Z"},
+					{"name": "layout", "text": "{{template \"content\" .}}", "html": "", "updated_at": "2015-06-01T12:00:00Z"}
+				]
+			}`))
+
+			Expect(collection.ListCall.Receives.Connection).To(Equal(conn))
+		})
+
+		It("delegates errors to the error writer", func() {
+			collection.ListCall.Returns.Error = errors.New("BOOM!")
+
+			request, err := http.NewRequest("GET", "/template_partials", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewListPartialsHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("BOOM!")))
+		})
+	})
+
+	Describe("GetPartialHandler", func() {
+		It("returns the partial", func() {
+			collection.GetCall.Returns.Partial = collections.TemplatePartial{Name: "footer", Text: "-- The Team", UpdatedAt: updatedAt}
+
+			request, err := http.NewRequest("GET", "/template_partials/footer", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewGetPartialHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{"name": "footer", "text": "-- The Team", "html": "", "updated_at": "2015-06-01T12:00:00Z"}`))
+			Expect(collection.GetCall.Receives.Name).To(Equal("footer"))
+		})
+
+		It("writes a validation error when the name is malformed", func() {
+			request, err := http.NewRequest("GET", "/template_partials/foot%20er", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewGetPartialHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+		})
+
+		It("delegates errors to the error writer", func() {
+			collection.GetCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			request, err := http.NewRequest("GET", "/template_partials/missing", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewGetPartialHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("SetPartialHandler", func() {
+		It("saves the partial", func() {
+			collection.SetCall.Returns.Partial = collections.TemplatePartial{Name: "footer", Text: "-- {{.Domain}}", HTML: "<footer>{{.Domain}}</footer>", UpdatedAt: updatedAt}
+
+			request, err := http.NewRequest("PUT", "/template_partials/footer", bytes.NewBufferString(`{"text": "-- {{.Domain}}", "html": "<footer>{{.Domain}}</footer>"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetPartialHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{"name": "footer", "text": "-- {{.Domain}}", "html": "<footer>{{.Domain}}</footer>", "updated_at": "2015-06-01T12:00:00Z"}`))
+
+			Expect(collection.SetCall.Receives.Connection).To(Equal(conn))
+			Expect(collection.SetCall.Receives.Partial).To(Equal(collections.TemplatePartial{
+				Name: "footer",
+				Text: "-- {{.Domain}}",
+				HTML: "<footer>{{.Domain}}</footer>",
+			}))
+		})
+
+		It("requires a text or an HTML version", func() {
+			request, err := http.NewRequest("PUT", "/template_partials/footer", bytes.NewBufferString(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetPartialHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"text" or "html" must be supplied`)}))
+			Expect(collection.SetCall.WasCalled).To(BeFalse())
+		})
+
+		It("writes a parse error when the body is not valid JSON", func() {
+			request, err := http.NewRequest("PUT", "/template_partials/footer", bytes.NewBufferString(`{`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetPartialHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
+		})
+
+		It("delegates errors from the collection to the error writer", func() {
+			collection.SetCall.Returns.Error = collections.TemplatePartialError{Err: errors.New(`partial "logo" does not exist`)}
+
+			request, err := http.NewRequest("PUT", "/template_partials/footer", bytes.NewBufferString(`{"text": "{{template \"logo\"}}"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewSetPartialHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(collections.TemplatePartialError{Err: errors.New(`partial "logo" does not exist`)}))
+		})
+	})
+
+	Describe("DeletePartialHandler", func() {
+		It("removes the partial", func() {
+			request, err := http.NewRequest("DELETE", "/template_partials/footer", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewDeletePartialHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+			Expect(collection.DeleteCall.Receives.Connection).To(Equal(conn))
+			Expect(collection.DeleteCall.Receives.Name).To(Equal("footer"))
+		})
+
+		It("delegates errors to the error writer", func() {
+			collection.DeleteCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			request, err := http.NewRequest("DELETE", "/template_partials/missing", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewDeletePartialHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+})
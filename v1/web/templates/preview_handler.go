@@ -0,0 +1,105 @@
+package templates
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type templatePreviewer interface {
+	Preview(template models.Template, context common.MessageContext) services.TemplatePreview
+}
+
+type PreviewOutput struct {
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+	HTML    string   `json:"html"`
+	Errors  []string `json:"errors"`
+}
+
+type PreviewHandler struct {
+	finder      templateFinder
+	partials    templatePartialsLister
+	previewer   templatePreviewer
+	errorWriter errorWriter
+}
+
+func NewPreviewHandler(finder templateFinder, partials templatePartialsLister, previewer templatePreviewer, errWriter errorWriter) PreviewHandler {
+	return PreviewHandler{
+		finder:      finder,
+		partials:    partials,
+		previewer:   previewer,
+		errorWriter: errWriter,
+	}
+}
+
+func (h PreviewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := regexp.MustCompile("/templates/(.*)/preview").FindStringSubmatch(req.URL.Path)[1]
+
+	params, err := NewPreviewParams(req.Body)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	template, err := h.finder.FindByID(database, templateID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	template, err = resolvePartials(database.Connection(), h.partials, template)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writePreview(w, h.previewer.Preview(template, params.MessageContext()))
+}
+
+type InlinePreviewHandler struct {
+	partials    templatePartialsLister
+	previewer   templatePreviewer
+	errorWriter errorWriter
+}
+
+func NewInlinePreviewHandler(partials templatePartialsLister, previewer templatePreviewer, errWriter errorWriter) InlinePreviewHandler {
+	return InlinePreviewHandler{
+		partials:    partials,
+		previewer:   previewer,
+		errorWriter: errWriter,
+	}
+}
+
+func (h InlinePreviewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	params, err := NewPreviewParams(req.Body)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	template, err := resolvePartials(database.Connection(), h.partials, params.Template())
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writePreview(w, h.previewer.Preview(template, params.MessageContext()))
+}
+
+func writePreview(w http.ResponseWriter, preview services.TemplatePreview) {
+	writeJSON(w, http.StatusOK, PreviewOutput{
+		Subject: preview.Subject,
+		Text:    preview.Text,
+		HTML:    preview.HTML,
+		Errors:  preview.Errors,
+	})
+}
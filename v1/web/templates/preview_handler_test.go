@@ -0,0 +1,216 @@
+package templates_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Preview handlers", func() {
+	var (
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		finder      *mocks.TemplateFinder
+		previewer   *mocks.TemplatePreviewer
+		partials    *mocks.TemplatePartialsCollection
+		errorWriter *mocks.ErrorWriter
+		database    *mocks.Database
+	)
+
+	BeforeEach(func() {
+		finder = mocks.NewTemplateFinder()
+		previewer = mocks.NewTemplatePreviewer()
+		partials = mocks.NewTemplatePartialsCollection()
+		partials.ListCall.Returns.Partials = []collections.TemplatePartial{
+			{Name: "branded", Text: `-- {{template "content" .}}`, HTML: `<main>{{template "content" .}}</main>`},
+		}
+		previewer.PreviewCall.Returns.Preview = services.TemplatePreview{
+			Subject: "rendered subject",
+			Text:    "rendered text",
+			HTML:    "rendered html",
+			Errors:  []string{"html: something went wrong"},
+		}
+
+		writer = httptest.NewRecorder()
+		errorWriter = mocks.NewErrorWriter()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+	})
+
+	Describe("PreviewHandler", func() {
+		var handler templates.PreviewHandler
+
+		BeforeEach(func() {
+			finder.FindByIDCall.Returns.Template = models.Template{
+				ID:      "some-template-id",
+				Subject: "{{.Subject}}",
+				Text:    "{{.Text}}",
+				HTML:    "{{.HTML}}",
+			}
+
+			handler = templates.NewPreviewHandler(finder, partials, previewer, errorWriter)
+		})
+
+		It("renders the stored template against the supplied context merged over the sample", func() {
+			request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBufferString(`{
+				"context": {"subject": "Your app crashed", "text": "app dora crashed", "space": "dev", "data": {"instances": 2}}
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{
+				"subject": "rendered subject",
+				"text": "rendered text",
+				"html": "rendered html",
+				"errors": ["html: something went wrong"]
+			}`))
+
+			Expect(finder.FindByIDCall.Receives.Database).To(Equal(database))
+			Expect(finder.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(previewer.PreviewCall.Receives.Template.ID).To(Equal("some-template-id"))
+			Expect(previewer.PreviewCall.Receives.Context.Subject).To(Equal("Your app crashed"))
+			Expect(previewer.PreviewCall.Receives.Context.Text).To(Equal("app dora crashed"))
+			Expect(previewer.PreviewCall.Receives.Context.Space).To(Equal("dev"))
+			Expect(previewer.PreviewCall.Receives.Context.Data).To(Equal(common.Data{"instances": json.Number("2")}))
+			Expect(previewer.PreviewCall.Receives.Context.Organization).To(Equal("sample-organization"))
+			Expect(previewer.PreviewCall.Receives.Context.KindDescription).To(Equal("Sample notification"))
+			Expect(previewer.PreviewCall.Receives.Context.Domain).To(Equal("example.com"))
+		})
+
+		It("lets the supplied context clear fields of the sample", func() {
+			request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBufferString(`{
+				"context": {"organization": ""}
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(previewer.PreviewCall.Receives.Context.Organization).To(BeEmpty())
+			Expect(previewer.PreviewCall.Receives.Context.Space).To(Equal("sample-space"))
+		})
+
+		It("uses a sample context when none is supplied", func() {
+			request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer([]byte{}))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(previewer.PreviewCall.Receives.Context.Subject).To(Equal("Sample subject"))
+			Expect(previewer.PreviewCall.Receives.Context.HTML).NotTo(BeEmpty())
+			Expect(previewer.PreviewCall.Receives.Context.Organization).To(Equal("sample-organization"))
+		})
+
+		It("wraps the stored template into its layout", func() {
+			finder.FindByIDCall.Returns.Template.Layout = "branded"
+
+			request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer([]byte{}))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(previewer.PreviewCall.Receives.Template.Text).To(Equal(`{{.Text}}{{define "branded"}}-- {{template "content" .}}{{end}}`))
+			Expect(previewer.PreviewCall.Receives.Template.HTML).To(Equal(`{{.HTML}}{{define "branded"}}<main>{{template "content" .}}</main>{{end}}`))
+		})
+
+		It("writes a validation error when the template includes partials that do not exist", func() {
+			finder.FindByIDCall.Returns.Template.Text = `{{template "footer"}}`
+
+			request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer([]byte{}))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: common.PartialError{Err: errors.New(`partial "footer" does not exist`)}}))
+		})
+
+		It("delegates finder errors to the error writer", func() {
+			finder.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			request, err := http.NewRequest("POST", "/templates/missing-template-id/preview", bytes.NewBuffer([]byte{}))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+
+		It("writes a ParseError when the request body is invalid", func() {
+			request, err := http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBufferString(`{not-json`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
+		})
+	})
+
+	Describe("InlinePreviewHandler", func() {
+		var handler templates.InlinePreviewHandler
+
+		BeforeEach(func() {
+			handler = templates.NewInlinePreviewHandler(partials, previewer, errorWriter)
+		})
+
+		It("renders the template from the request body", func() {
+			request, err := http.NewRequest("POST", "/templates/preview", bytes.NewBufferString(`{
+				"text": "{{.KindDescription}}: {{.Text}}",
+				"html": "<p>{{.HTML}}</p>"
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(previewer.PreviewCall.Receives.Template).To(Equal(models.Template{
+				Subject: "{{.Subject}}",
+				Text:    "{{.KindDescription}}: {{.Text}}",
+				HTML:    "<p>{{.HTML}}</p>",
+			}))
+			Expect(previewer.PreviewCall.Receives.Context.KindDescription).To(Equal("Sample notification"))
+			Expect(partials.ListCall.CallCount).To(Equal(0))
+		})
+
+		It("wraps the template into the layout from the request body", func() {
+			request, err := http.NewRequest("POST", "/templates/preview", bytes.NewBufferString(`{
+				"layout": "branded",
+				"html": "<p>{{.HTML}}</p>"
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(previewer.PreviewCall.Receives.Template.Layout).To(Equal("branded"))
+			Expect(previewer.PreviewCall.Receives.Template.HTML).To(Equal(`<p>{{.HTML}}</p>{{define "branded"}}<main>{{template "content" .}}</main>{{end}}`))
+		})
+
+		It("writes a ParseError when the request body is invalid", func() {
+			request, err := http.NewRequest("POST", "/templates/preview", bytes.NewBufferString(`{not-json`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
+		})
+	})
+})
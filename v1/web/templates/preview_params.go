@@ -0,0 +1,138 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+)
+
+type PreviewContext struct {
+	From              string      `json:"from"`
+	ReplyTo           string      `json:"reply_to"`
+	To                string      `json:"to"`
+	Subject           string      `json:"subject"`
+	Text              string      `json:"text"`
+	HTML              string      `json:"html"`
+	KindDescription   string      `json:"kind_description"`
+	SourceDescription string      `json:"source_description"`
+	UserGUID          string      `json:"user_guid"`
+	ClientID          string      `json:"client_id"`
+	MessageID         string      `json:"message_id"`
+	Space             string      `json:"space"`
+	SpaceGUID         string      `json:"space_guid"`
+	Organization      string      `json:"organization"`
+	OrganizationGUID  string      `json:"organization_guid"`
+	UnsubscribeID     string      `json:"unsubscribe_id"`
+	Scope             string      `json:"scope"`
+	Endorsement       string      `json:"endorsement"`
+	OrganizationRole  string      `json:"organization_role"`
+	SpaceRole         string      `json:"space_role"`
+	Domain            string      `json:"domain"`
+	Data              common.Data `json:"data"`
+}
+
+type PreviewParams struct {
+	Subject  string          `json:"subject"`
+	Text     string          `json:"text"`
+	HTML     string          `json:"html"`
+	Markdown string          `json:"markdown"`
+	Layout   string          `json:"layout"`
+	Context  *PreviewContext `json:"context"`
+}
+
+var samplePreviewContext = PreviewContext{
+	From:              "no-reply@notifications.example.com",
+	To:                "user@example.com",
+	Subject:           "Sample subject",
+	Text:              "This is the sample text of a notification.",
+	HTML:              "<p>This is the sample HTML of a notification.</p>",
+	KindDescription:   "Sample notification",
+	SourceDescription: "Sample service",
+	UserGUID:          "sample-user-guid",
+	ClientID:          "sample-client",
+	MessageID:         "sample-message-id",
+	Space:             "sample-space",
+	SpaceGUID:         "sample-space-guid",
+	Organization:      "sample-organization",
+	OrganizationGUID:  "sample-organization-guid",
+	UnsubscribeID:     "sample-unsubscribe-id",
+	Endorsement:       "You received this message because you belong to the sample-space space in the sample-organization organization.",
+	Domain:            "example.com",
+}
+
+func NewPreviewParams(body io.ReadCloser) (PreviewParams, error) {
+	defer body.Close()
+
+	// The supplied context is decoded over a copy of the sample, so that the
+	// fields it leaves out keep their sample values.
+	sample := samplePreviewContext
+	params := PreviewParams{Context: &sample}
+
+	buffer := bytes.NewBuffer([]byte{})
+	buffer.ReadFrom(body)
+	if buffer.Len() > 0 {
+		err := json.Unmarshal(buffer.Bytes(), &params)
+		if err != nil {
+			return params, webutil.ParseError{}
+		}
+	}
+
+	return params, nil
+}
+
+func (p PreviewParams) Template() models.Template {
+	template := models.Template{
+		Subject:  p.Subject,
+		Text:     p.Text,
+		HTML:     p.HTML,
+		Markdown: p.Markdown,
+		Layout:   p.Layout,
+	}
+
+	if template.Subject == "" {
+		template.Subject = "{{.Subject}}"
+	}
+
+	return template
+}
+
+// MessageContext returns the context of the request, or the sample context
+// when there is none.
+func (p PreviewParams) MessageContext() common.MessageContext {
+	context := samplePreviewContext
+	if p.Context != nil {
+		context = *p.Context
+	}
+
+	return common.MessageContext{
+		From:              context.From,
+		ReplyTo:           context.ReplyTo,
+		To:                context.To,
+		Subject:           context.Subject,
+		Text:              context.Text,
+		HTML:              context.HTML,
+		HTMLComponents:    common.HTML{BodyContent: context.HTML},
+		KindDescription:   context.KindDescription,
+		SourceDescription: context.SourceDescription,
+		UserGUID:          context.UserGUID,
+		ClientID:          context.ClientID,
+		MessageID:         context.MessageID,
+		Space:             context.Space,
+		SpaceGUID:         context.SpaceGUID,
+		Organization:      context.Organization,
+		OrganizationGUID:  context.OrganizationGUID,
+		UnsubscribeID:     context.UnsubscribeID,
+		Scope:             context.Scope,
+		Endorsement:       context.Endorsement,
+		OrganizationRole:  context.OrganizationRole,
+		SpaceRole:         context.SpaceRole,
+		RequestReceived:   time.Now(),
+		Domain:            context.Domain,
+		Data:              context.Data,
+	}
+}
@@ -21,15 +21,36 @@ type Routes struct {
 	TemplateCreator           templateCreator
 	TemplateDeleter           templateDeleter
 	TemplateAssociationLister templateAssociationLister
+	TemplatePreviewer         templatePreviewer
+	TemplateVersions          templateVersionsCollection
+	TemplateLocales           templateLocalesCollection
+	TemplatePartials          templatePartialsCollection
+	TemplateBundles           templateBundlesCollection
 }
 
 func (r Routes) Register(m muxer) {
 	m.Handle("GET", "/default_template", NewGetDefaultHandler(r.TemplateFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
-	m.Handle("PUT", "/default_template", NewUpdateDefaultHandler(r.TemplateUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/default_template", NewUpdateDefaultHandler(r.TemplateUpdater, r.TemplatePartials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates", NewListHandler(r.TemplateLister, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
-	m.Handle("POST", "/templates", NewCreateHandler(r.TemplateCreator, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates", NewCreateHandler(r.TemplateCreator, r.TemplatePartials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/preview", NewInlinePreviewHandler(r.TemplatePartials, r.TemplatePreviewer, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/export", NewExportHandler(r.TemplateBundles, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/import", NewImportHandler(r.TemplateBundles, r.TemplatePartials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates/{template_id}", NewGetHandler(r.TemplateFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
-	m.Handle("PUT", "/templates/{template_id}", NewUpdateHandler(r.TemplateUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/templates/{template_id}", NewUpdateHandler(r.TemplateUpdater, r.TemplatePartials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("DELETE", "/templates/{template_id}", NewDeleteHandler(r.TemplateDeleter, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates/{template_id}/associations", NewListAssociationsHandler(r.TemplateAssociationLister, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/versions", NewListVersionsHandler(r.TemplateVersions, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/versions/{version}", NewGetVersionHandler(r.TemplateVersions, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/{template_id}/versions/{version}/rollback", NewRollbackHandler(r.TemplateVersions, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/diff", NewDiffVersionsHandler(r.TemplateVersions, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/locales", NewListLocalesHandler(r.TemplateLocales, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/locales/{locale}", NewGetLocaleHandler(r.TemplateLocales, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/templates/{template_id}/locales/{locale}", NewSetLocaleHandler(r.TemplateLocales, r.TemplateFinder, r.TemplatePartials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/templates/{template_id}/locales/{locale}", NewDeleteLocaleHandler(r.TemplateLocales, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/{template_id}/preview", NewPreviewHandler(r.TemplateFinder, r.TemplatePartials, r.TemplatePreviewer, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/template_partials", NewListPartialsHandler(r.TemplatePartials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/template_partials/{name}", NewGetPartialHandler(r.TemplatePartials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/template_partials/{name}", NewSetPartialHandler(r.TemplatePartials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/template_partials/{name}", NewDeletePartialHandler(r.TemplatePartials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
 }
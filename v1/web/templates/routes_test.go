@@ -27,6 +27,10 @@ var _ = Describe("Routes", func() {
 			TemplateDeleter:           mocks.NewTemplateDeleter(),
 			TemplateLister:            mocks.NewTemplateLister(),
 			TemplateAssociationLister: mocks.NewTemplateAssociationLister(),
+			TemplatePreviewer:         mocks.NewTemplatePreviewer(),
+			TemplateVersions:          mocks.NewTemplateVersionsCollection(),
+			TemplateLocales:           mocks.NewTemplateLocalesCollection(),
+			TemplateBundles:           mocks.NewTemplateBundlesCollection(),
 
 			RequestCounter:                          middleware.RequestCounter{},
 			RequestLogging:                          middleware.RequestLogging{},
@@ -61,6 +65,48 @@ var _ = Describe("Routes", func() {
 			authenticator := s.Middleware[2].(middleware.Authenticator)
 			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
 		})
+
+		It("routes GET /templates/export", func() {
+			request, err := http.NewRequest("GET", "/templates/export", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.ExportHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+
+			authenticator = s.Middleware[3].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+		})
+
+		It("routes POST /templates/import", func() {
+			request, err := http.NewRequest("POST", "/templates/import", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.ImportHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+
+			authenticator = s.Middleware[3].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+		})
+
+		It("routes POST /templates/preview", func() {
+			request, err := http.NewRequest("POST", "/templates/preview", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.InlinePreviewHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
 	})
 
 	Describe("/templates/{template_id}", func() {
@@ -111,6 +157,164 @@ var _ = Describe("Routes", func() {
 			authenticator := s.Middleware[2].(middleware.Authenticator)
 			Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
 		})
+
+		It("routes GET /templates/{template_id}/versions", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/versions", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.ListVersionsHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes GET /templates/{template_id}/versions/{version}", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/versions/{version}", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.GetVersionHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes POST /templates/{template_id}/versions/{version}/rollback", func() {
+			request, err := http.NewRequest("POST", "/templates/{template_id}/versions/{version}/rollback", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.RollbackHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+		})
+
+		It("routes GET /templates/{template_id}/diff", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/diff", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.DiffVersionsHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes GET /templates/{template_id}/locales", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/locales", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.ListLocalesHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes GET /templates/{template_id}/locales/{locale}", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/locales/{locale}", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.GetLocaleHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes PUT /templates/{template_id}/locales/{locale}", func() {
+			request, err := http.NewRequest("PUT", "/templates/{template_id}/locales/{locale}", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.SetLocaleHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+		})
+
+		It("routes DELETE /templates/{template_id}/locales/{locale}", func() {
+			request, err := http.NewRequest("DELETE", "/templates/{template_id}/locales/{locale}", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.DeleteLocaleHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+		})
+
+		It("routes POST /templates/{template_id}/preview", func() {
+			request, err := http.NewRequest("POST", "/templates/{template_id}/preview", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.PreviewHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+	})
+
+	Describe("/template_partials", func() {
+		It("routes GET /template_partials", func() {
+			request, err := http.NewRequest("GET", "/template_partials", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.ListPartialsHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes GET /template_partials/{name}", func() {
+			request, err := http.NewRequest("GET", "/template_partials/{name}", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.GetPartialHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes PUT /template_partials/{name}", func() {
+			request, err := http.NewRequest("PUT", "/template_partials/{name}", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.SetPartialHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+		})
+
+		It("routes DELETE /template_partials/{name}", func() {
+			request, err := http.NewRequest("DELETE", "/template_partials/{name}", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.DeletePartialHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+		})
 	})
 
 	Describe("/default_template", func() {
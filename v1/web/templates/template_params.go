@@ -2,11 +2,16 @@ package templates
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"text/template"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/cloudfoundry-incubator/notifications/valiant"
 )
@@ -14,7 +19,9 @@ import (
 type TemplateParams struct {
 	Name     string          `json:"name" validate-required:"true"`
 	Text     string          `json:"text"`
-	HTML     string          `json:"html" validate-required:"true"`
+	HTML     string          `json:"html"`
+	Markdown string          `json:"markdown"`
+	Layout   string          `json:"layout"`
 	Subject  string          `json:"subject"`
 	Metadata json.RawMessage `json:"metadata"`
 }
@@ -35,42 +42,137 @@ func NewTemplateParams(body io.ReadCloser) (TemplateParams, error) {
 		}
 	}
 
-	if template.Metadata == nil {
-		template.Metadata = json.RawMessage("{}")
+	err = template.validate()
+	if err != nil {
+		return template, err
+	}
+
+	return template, nil
+}
+
+// validate checks everything about a template that can be checked without
+// the stored partials and fills in the defaults.
+func (t *TemplateParams) validate() error {
+	// A Markdown template renders both the text and the HTML part, so the
+	// HTML template is only required when there is no Markdown.
+	if t.HTML == "" && t.Markdown == "" {
+		return webutil.ValidationError{Err: valiant.RequiredFieldError{ErrorMessage: "Missing required field 'html'"}}
 	}
 
-	err = template.validateSyntax()
+	if t.Metadata == nil {
+		t.Metadata = json.RawMessage("{}")
+	}
+
+	err := t.validateMetadata()
 	if err != nil {
-		return TemplateParams{}, err
+		return err
 	}
 
-	template.setDefaults()
+	err = t.validateSyntax()
+	if err != nil {
+		return err
+	}
 
-	return template, nil
+	// Templates that use partials can only be rendered once the partials
+	// are resolved, which validatePartials does.
+	if !t.usesPartials() {
+		err = validateExecution(t.ToModel())
+		if err != nil {
+			return err
+		}
+	}
+
+	t.setDefaults()
+
+	return nil
+}
+
+// validateMetadata checks that the metadata is an object and that the keys
+// that change how the template is rendered hold booleans.
+func (t TemplateParams) validateMetadata() error {
+	var metadata map[string]interface{}
+	err := json.Unmarshal(t.Metadata, &metadata)
+	if err != nil {
+		return webutil.ValidationError{Err: errors.New(`"metadata" must be a JSON object`)}
+	}
+
+	for _, key := range []string{models.InlineCSSMetadataKey, models.LegacyHTMLEscapingMetadataKey} {
+		if value, ok := metadata[key]; ok {
+			if _, isBool := value.(bool); !isBool {
+				return webutil.ValidationError{Err: fmt.Errorf("metadata %q must be a boolean", key)}
+			}
+		}
+	}
+
+	return nil
 }
 
 func (t TemplateParams) validateSyntax() error {
-	toValidate := map[string]string{
-		"Subject": t.Subject,
-		"Text":    t.Text,
-		"HTML":    t.HTML,
+	toValidate := []struct {
+		field    string
+		contents string
+	}{
+		{"Subject", t.Subject},
+		{"Text", t.Text},
+		{"HTML", t.HTML},
+		{"Markdown", t.Markdown},
 	}
 
-	for field, contents := range toValidate {
-		_, err := template.New("test").Parse(contents)
+	for _, part := range toValidate {
+		_, err := template.New("test").Funcs(common.TemplateFuncs()).Parse(part.contents)
 		if err != nil {
-			return webutil.ValidationError{Err: fmt.Errorf("%s syntax is malformed please check your braces", field)}
+			return webutil.ValidationError{Err: fmt.Errorf("%s syntax is malformed please check your braces", part.field)}
 		}
 	}
 
 	return nil
 }
 
+func (t TemplateParams) usesPartials() bool {
+	return common.UsesPartials(common.Templates{
+		Text:     t.Text,
+		HTML:     t.HTML,
+		Markdown: t.Markdown,
+		Layout:   t.Layout,
+	})
+}
+
+// validatePartials resolves the layout and partials of the template against
+// the stored partials and renders the result, as validate does for templates
+// that stand on their own.
+func (t TemplateParams) validatePartials(conn collections.ConnectionInterface, partials templatePartialsLister) error {
+	if !t.usesPartials() {
+		return nil
+	}
+
+	template, err := resolvePartials(conn, partials, t.ToModel())
+	if err != nil {
+		return err
+	}
+
+	return validateExecution(template)
+}
+
+// validateExecution renders the template against a sample message context so
+// that references to fields that do not exist are caught before the template
+// is used to send mail.
+func validateExecution(template models.Template) error {
+	previewer := services.NewTemplatePreviewer(common.Packager{})
+	preview := previewer.Preview(template, PreviewParams{}.MessageContext())
+	if len(preview.Errors) > 0 {
+		return webutil.ValidationError{Err: fmt.Errorf("template could not be rendered: %s", strings.Join(preview.Errors, "; "))}
+	}
+
+	return nil
+}
+
 func (t TemplateParams) ToModel() models.Template {
 	return models.Template{
 		Name:     t.Name,
 		Text:     t.Text,
 		HTML:     t.HTML,
+		Markdown: t.Markdown,
+		Layout:   t.Layout,
 		Subject:  t.Subject,
 		Metadata: string(t.Metadata),
 	}
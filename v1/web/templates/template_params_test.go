@@ -9,6 +9,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/cloudfoundry-incubator/notifications/valiant"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -62,6 +63,31 @@ var _ = Describe("TemplateParams", func() {
 				Expect(parameters.Metadata).To(Equal(json.RawMessage("{}")))
 			})
 
+			It("accepts a Markdown template in place of the HTML template", func() {
+				body, err := json.Marshal(map[string]interface{}{
+					"name":     "Foo Bar Baz",
+					"markdown": "# {{.Subject}}\n\nits **foobar**",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				parameters, err := templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.HTML).To(Equal(""))
+				Expect(parameters.Markdown).To(Equal("# {{.Subject}}\n\nits **foobar**"))
+			})
+
+			It("requires either an HTML or a Markdown template", func() {
+				body, err := json.Marshal(map[string]interface{}{
+					"name": "Foo Bar Baz",
+					"html": "",
+					"text": "its foobar",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+				Expect(err).To(MatchError(webutil.ValidationError{Err: valiant.RequiredFieldError{ErrorMessage: "Missing required field 'html'"}}))
+			})
+
 			Context("when the template has invalid syntax", func() {
 				Context("when subject template has invalid syntax", func() {
 					It("returns a validation error", func() {
@@ -101,6 +127,157 @@ var _ = Describe("TemplateParams", func() {
 						Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("HTML syntax is malformed please check your braces")}))
 					})
 				})
+
+				Context("when a template references a field that does not exist", func() {
+					It("returns a validation error", func() {
+						body := buildTemplateRequestBody(templates.TemplateParams{
+							Name:    "Template name",
+							Text:    "{{.Text}} in {{.Planet}}",
+							HTML:    "<p>{{.HTML}}</p>",
+							Subject: "{{.Subject}}",
+						})
+						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+						Expect(err).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+						Expect(err.Error()).To(HavePrefix("template could not be rendered: text: "))
+						Expect(err.Error()).To(ContainSubstring("can't evaluate field Planet"))
+					})
+				})
+
+				Context("when a template calls a function that does not exist", func() {
+					It("returns a validation error", func() {
+						body := buildTemplateRequestBody(templates.TemplateParams{
+							Name:    "Template name",
+							Text:    "{{.Text | shout}}",
+							HTML:    "<p>{{.HTML}}</p>",
+							Subject: "{{.Subject}}",
+						})
+						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+						Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("Text syntax is malformed please check your braces")}))
+					})
+				})
+			})
+
+			Context("when the metadata opts into CSS inlining", func() {
+				It("accepts a boolean", func() {
+					body, err := json.Marshal(map[string]interface{}{
+						"name":     "Foo Bar Baz",
+						"html":     "<p>its foobar</p>",
+						"metadata": map[string]interface{}{"inline_css": true},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					parameters, err := templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(parameters.ToModel().InlinesCSS()).To(BeTrue())
+				})
+
+				It("rejects any other value", func() {
+					body, err := json.Marshal(map[string]interface{}{
+						"name":     "Foo Bar Baz",
+						"html":     "<p>its foobar</p>",
+						"metadata": map[string]interface{}{"inline_css": "yes"},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`metadata "inline_css" must be a boolean`)}))
+				})
+			})
+
+			Context("when the metadata is not an object", func() {
+				It("returns a validation error", func() {
+					body, err := json.Marshal(map[string]interface{}{
+						"name":     "Foo Bar Baz",
+						"html":     "<p>its foobar</p>",
+						"metadata": []string{"inline_css"},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"metadata" must be a JSON object`)}))
+				})
+			})
+
+			Context("when the template uses partials", func() {
+				It("leaves rendering the template to the handler, which resolves them", func() {
+					body := buildTemplateRequestBody(templates.TemplateParams{
+						Name:   "Template name",
+						Layout: "branded",
+						Text:   `{{.Text}} {{template "footer" .}}`,
+						HTML:   "<p>{{.HTML}}</p>",
+					})
+					parameters, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(parameters.Layout).To(Equal("branded"))
+				})
+
+				It("still checks the syntax", func() {
+					body := buildTemplateRequestBody(templates.TemplateParams{
+						Name: "Template name",
+						HTML: `{{template "footer" .`,
+					})
+					_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("HTML syntax is malformed please check your braces")}))
+				})
+			})
+
+			Context("when the metadata opts into legacy html escaping", func() {
+				It("rejects a value that is not a boolean", func() {
+					body, err := json.Marshal(map[string]interface{}{
+						"name":     "Foo Bar Baz",
+						"html":     "<p>its foobar</p>",
+						"metadata": map[string]interface{}{"legacy_html_escaping": "true"},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`metadata "legacy_html_escaping" must be a boolean`)}))
+				})
+
+				It("accepts html that contextual escaping rejects", func() {
+					body, err := json.Marshal(map[string]interface{}{
+						"name":     "Foo Bar Baz",
+						"html":     `<a href="{{.Domain}}`,
+						"metadata": map[string]interface{}{"legacy_html_escaping": true},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					parameters, err := templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(parameters.ToModel().UsesLegacyHTMLEscaping()).To(BeTrue())
+
+					body, err = json.Marshal(map[string]interface{}{
+						"name": "Foo Bar Baz",
+						"html": `<a href="{{.Domain}}`,
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+					Expect(err).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+				})
+			})
+
+			Context("when the template uses the template functions", func() {
+				It("accepts it", func() {
+					body := buildTemplateRequestBody(templates.TemplateParams{
+						Name:    "Template name",
+						Text:    `{{.Text | truncate 80}} {{.Data.count | formatNumber}} {{pluralize .Data.count "app" "apps"}}`,
+						HTML:    `<a href="{{link .Domain "/apps" "guid" .Data.app_guid}}">{{.Data.name | default "your app"}}</a>`,
+						Subject: `{{.Subject | upper}} at {{.RequestReceived | inZone "UTC" | formatTime "15:04"}}`,
+					})
+					_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("accepts string functions applied to data keys", func() {
+					body := buildTemplateRequestBody(templates.TemplateParams{
+						Name: "Template name",
+						Text: `{{.Data.name | upper}} {{.Data.name | title | truncate 10}} {{.Data.name | trim | replace "-" " "}}`,
+						HTML: `<p>{{.Data.name | lower}}</p>`,
+					})
+					_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+					Expect(err).NotTo(HaveOccurred())
+				})
 			})
 		})
 	})
@@ -112,6 +289,7 @@ var _ = Describe("TemplateParams", func() {
 				Text:     "its foobar of course",
 				HTML:     "<p>its foobar</p>",
 				Subject:  "Foobar Yah",
+				Layout:   "branded",
 				Metadata: json.RawMessage(`{"some_property": "some_value"}`),
 			}
 			templateModel := templateParams.ToModel()
@@ -120,6 +298,7 @@ var _ = Describe("TemplateParams", func() {
 			Expect(templateModel.Text).To(Equal("its foobar of course"))
 			Expect(templateModel.HTML).To(Equal("<p>its foobar</p>"))
 			Expect(templateModel.Subject).To(Equal("Foobar Yah"))
+			Expect(templateModel.Layout).To(Equal("branded"))
 			Expect(templateModel.Metadata).To(MatchJSON(`{"some_property": "some_value"}`))
 			Expect(templateModel.CreatedAt).To(BeZero())
 			Expect(templateModel.UpdatedAt).To(BeZero())
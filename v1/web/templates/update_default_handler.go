@@ -9,17 +9,19 @@ import (
 )
 
 type templateUpdater interface {
-	Update(database services.DatabaseInterface, templateID string, template models.Template) error
+	Update(database services.DatabaseInterface, templateID string, template models.Template, clientID string) error
 }
 
 type UpdateDefaultHandler struct {
 	updater     templateUpdater
+	partials    templatePartialsLister
 	errorWriter errorWriter
 }
 
-func NewUpdateDefaultHandler(updater templateUpdater, errWriter errorWriter) UpdateDefaultHandler {
+func NewUpdateDefaultHandler(updater templateUpdater, partials templatePartialsLister, errWriter errorWriter) UpdateDefaultHandler {
 	return UpdateDefaultHandler{
 		updater:     updater,
+		partials:    partials,
 		errorWriter: errWriter,
 	}
 }
@@ -31,9 +33,20 @@ func (h UpdateDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 		return
 	}
 
-	err = h.updater.Update(context.Get("database").(DatabaseInterface), models.DefaultTemplateID, template.ToModel())
+	database := context.Get("database").(DatabaseInterface)
+
+	err = template.validatePartials(database.Connection(), h.partials)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	clientID := context.Get("client_id").(string)
+
+	err = h.updater.Update(database, models.DefaultTemplateID, template.ToModel(), clientID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
@@ -9,12 +9,14 @@ import (
 
 type UpdateHandler struct {
 	updater     templateUpdater
+	partials    templatePartialsLister
 	errorWriter errorWriter
 }
 
-func NewUpdateHandler(updater templateUpdater, errWriter errorWriter) UpdateHandler {
+func NewUpdateHandler(updater templateUpdater, partials templatePartialsLister, errWriter errorWriter) UpdateHandler {
 	return UpdateHandler{
 		updater:     updater,
+		partials:    partials,
 		errorWriter: errWriter,
 	}
 }
@@ -28,7 +30,17 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
-	err = h.updater.Update(context.Get("database").(DatabaseInterface), templateID, templateParams.ToModel())
+	database := context.Get("database").(DatabaseInterface)
+
+	err = templateParams.validatePartials(database.Connection(), h.partials)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	clientID := context.Get("client_id").(string)
+
+	err = h.updater.Update(database, templateID, templateParams.ToModel(), clientID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
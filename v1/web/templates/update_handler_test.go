@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
@@ -25,6 +27,7 @@ var _ = Describe("UpdateHandler", func() {
 		request     *http.Request
 		context     stack.Context
 		updater     *mocks.TemplateUpdater
+		partials    *mocks.TemplatePartialsCollection
 		errorWriter *mocks.ErrorWriter
 		database    *mocks.Database
 	)
@@ -32,6 +35,7 @@ var _ = Describe("UpdateHandler", func() {
 	Describe("ServeHTTP", func() {
 		BeforeEach(func() {
 			updater = mocks.NewTemplateUpdater()
+			partials = mocks.NewTemplatePartialsCollection()
 			errorWriter = mocks.NewErrorWriter()
 			writer = httptest.NewRecorder()
 			body := []byte(`{"name":"An Interesting Template", "subject":"very interesting subject", "text":"Here's the msg {{.Text}}", "html":"<p>turkey gobble</p>"}`)
@@ -41,8 +45,9 @@ var _ = Describe("UpdateHandler", func() {
 			database = mocks.NewDatabase()
 			context = stack.NewContext()
 			context.Set("database", database)
+			context.Set("client_id", "some-client-id")
 
-			handler = templates.NewUpdateHandler(updater, errorWriter)
+			handler = templates.NewUpdateHandler(updater, partials, errorWriter)
 		})
 
 		It("calls update on its updater with appropriate arguments", func() {
@@ -51,6 +56,7 @@ var _ = Describe("UpdateHandler", func() {
 
 			Expect(updater.UpdateCall.Receives.Database).To(Equal(database))
 			Expect(updater.UpdateCall.Receives.TemplateID).To(Equal("a-template-id"))
+			Expect(updater.UpdateCall.Receives.ClientID).To(Equal("some-client-id"))
 			Expect(updater.UpdateCall.Receives.Template).To(Equal(models.Template{
 				Name:     "An Interesting Template",
 				Subject:  "very interesting subject",
@@ -60,6 +66,30 @@ var _ = Describe("UpdateHandler", func() {
 			}))
 		})
 
+		It("validates templates with a layout against the stored partials", func() {
+			partials.ListCall.Returns.Partials = []collections.TemplatePartial{
+				{Name: "branded", HTML: `<main>{{template "content" .}}</main>`},
+			}
+
+			body := []byte(`{"name": "Branded", "layout": "branded", "html": "<p>{{.HTML}}</p>"}`)
+			request, err = http.NewRequest("PUT", "/templates/a-template-id", bytes.NewBuffer(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+			Expect(updater.UpdateCall.Receives.Template.Layout).To(Equal("branded"))
+		})
+
+		It("writes a validation error when the layout does not exist", func() {
+			body := []byte(`{"name": "Branded", "layout": "branded", "html": "<p>{{.HTML}}</p>"}`)
+			request, err = http.NewRequest("PUT", "/templates/a-template-id", bytes.NewBuffer(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: common.PartialError{Err: errors.New(`layout "branded" does not exist`)}}))
+			Expect(updater.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+		})
+
 		It("can update a template without a subject field", func() {
 			body := []byte(`{"name": "my template name", "html": "<p>gobble</p>", "text": "my awesome text"}`)
 			request, err = http.NewRequest("PUT", "/templates/a-template-id.", bytes.NewBuffer(body))
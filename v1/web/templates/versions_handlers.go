@@ -0,0 +1,262 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type templateVersionsCollection interface {
+	List(conn collections.ConnectionInterface, templateID string) ([]collections.TemplateVersion, error)
+	Get(conn collections.ConnectionInterface, templateID string, version int) (collections.TemplateVersion, error)
+	Diff(conn collections.ConnectionInterface, templateID string, from, to int) (collections.TemplateDiff, error)
+	Rollback(conn collections.ConnectionInterface, templateID string, version int, clientID string) (collections.TemplateVersion, error)
+}
+
+type TemplateVersionSummary struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	ClientID  string    `json:"client_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TemplateVersionOutput struct {
+	Version   int                    `json:"version"`
+	Name      string                 `json:"name"`
+	Subject   string                 `json:"subject"`
+	HTML      string                 `json:"html"`
+	Text      string                 `json:"text"`
+	Markdown  string                 `json:"markdown"`
+	Layout    string                 `json:"layout"`
+	Metadata  map[string]interface{} `json:"metadata"`
+	ClientID  string                 `json:"client_id"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type TemplateDiffOutput struct {
+	From    int               `json:"from"`
+	To      int               `json:"to"`
+	Changes map[string]string `json:"changes"`
+}
+
+var (
+	versionsPath        = regexp.MustCompile(`/templates/(.*)/versions$`)
+	versionPath         = regexp.MustCompile(`/templates/(.*)/versions/([^/]*)$`)
+	versionRollbackPath = regexp.MustCompile(`/templates/(.*)/versions/([^/]*)/rollback$`)
+	diffPath            = regexp.MustCompile(`/templates/(.*)/diff$`)
+)
+
+type ListVersionsHandler struct {
+	collection  templateVersionsCollection
+	errorWriter errorWriter
+}
+
+func NewListVersionsHandler(collection templateVersionsCollection, errWriter errorWriter) ListVersionsHandler {
+	return ListVersionsHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListVersionsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := versionsPath.FindStringSubmatch(req.URL.Path)[1]
+	database := context.Get("database").(DatabaseInterface)
+
+	versions, err := h.collection.List(database.Connection(), templateID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := map[string][]TemplateVersionSummary{
+		"versions": {},
+	}
+	for _, version := range versions {
+		document["versions"] = append(document["versions"], TemplateVersionSummary{
+			Version:   version.Version,
+			Name:      version.Name,
+			ClientID:  version.ClientID,
+			CreatedAt: version.CreatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
+
+type GetVersionHandler struct {
+	collection  templateVersionsCollection
+	errorWriter errorWriter
+}
+
+func NewGetVersionHandler(collection templateVersionsCollection, errWriter errorWriter) GetVersionHandler {
+	return GetVersionHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetVersionHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	matches := versionPath.FindStringSubmatch(req.URL.Path)
+	templateID := matches[1]
+
+	version, err := parseVersion(templateID, matches[2])
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	templateVersion, err := h.collection.Get(database.Connection(), templateID, version)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output, err := newTemplateVersionOutput(templateVersion)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
+
+type DiffVersionsHandler struct {
+	collection  templateVersionsCollection
+	errorWriter errorWriter
+}
+
+func NewDiffVersionsHandler(collection templateVersionsCollection, errWriter errorWriter) DiffVersionsHandler {
+	return DiffVersionsHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DiffVersionsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := diffPath.FindStringSubmatch(req.URL.Path)[1]
+	query := req.URL.Query()
+
+	from, err := strconv.Atoi(query.Get("from"))
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: fmt.Errorf(`"from" must be a version number`)})
+		return
+	}
+
+	to, err := strconv.Atoi(query.Get("to"))
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: fmt.Errorf(`"to" must be a version number`)})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	diff, err := h.collection.Diff(database.Connection(), templateID, from, to)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TemplateDiffOutput{
+		From:    diff.From,
+		To:      diff.To,
+		Changes: diffChanges(diff),
+	})
+}
+
+// diffChanges maps the fields that differ to their line diff.
+func diffChanges(diff collections.TemplateDiff) map[string]string {
+	changes := map[string]string{}
+	for field, change := range map[string]string{
+		"name":     diff.Name,
+		"subject":  diff.Subject,
+		"text":     diff.Text,
+		"html":     diff.HTML,
+		"markdown": diff.Markdown,
+		"layout":   diff.Layout,
+		"metadata": diff.Metadata,
+	} {
+		if change != "" {
+			changes[field] = change
+		}
+	}
+
+	return changes
+}
+
+type RollbackHandler struct {
+	collection  templateVersionsCollection
+	errorWriter errorWriter
+}
+
+func NewRollbackHandler(collection templateVersionsCollection, errWriter errorWriter) RollbackHandler {
+	return RollbackHandler{
+		collection:  collection,
+		errorWriter: errWriter,
+	}
+}
+
+func (h RollbackHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	matches := versionRollbackPath.FindStringSubmatch(req.URL.Path)
+	templateID := matches[1]
+
+	version, err := parseVersion(templateID, matches[2])
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	clientID := context.Get("client_id").(string)
+
+	templateVersion, err := h.collection.Rollback(database.Connection(), templateID, version, clientID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output, err := newTemplateVersionOutput(templateVersion)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
+
+func parseVersion(templateID, rawVersion string) (int, error) {
+	version, err := strconv.Atoi(rawVersion)
+	if err != nil {
+		return 0, models.NotFoundError{Err: fmt.Errorf("Version %q of template %q could not be found", rawVersion, templateID)}
+	}
+
+	return version, nil
+}
+
+func newTemplateVersionOutput(version collections.TemplateVersion) (TemplateVersionOutput, error) {
+	var metadata map[string]interface{}
+	err := json.Unmarshal([]byte(version.Metadata), &metadata)
+	if err != nil {
+		return TemplateVersionOutput{}, err
+	}
+
+	return TemplateVersionOutput{
+		Version:   version.Version,
+		Name:      version.Name,
+		Subject:   version.Subject,
+		HTML:      version.HTML,
+		Text:      version.Text,
+		Markdown:  version.Markdown,
+		Layout:    version.Layout,
+		Metadata:  metadata,
+		ClientID:  version.ClientID,
+		CreatedAt: version.CreatedAt,
+	}, nil
+}
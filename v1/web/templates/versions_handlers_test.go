@@ -0,0 +1,216 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template version handlers", func() {
+	var (
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		collection  *mocks.TemplateVersionsCollection
+		errorWriter *mocks.ErrorWriter
+		conn        *mocks.Connection
+		createdAt   time.Time
+	)
+
+	BeforeEach(func() {
+		collection = mocks.NewTemplateVersionsCollection()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		createdAt = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
+	})
+
+	Describe("ListVersionsHandler", func() {
+		It("lists the versions of the template", func() {
+			collection.ListCall.Returns.Versions = []collections.TemplateVersion{
+				{Version: 2, Name: "Raptors", ClientID: "second-client", CreatedAt: createdAt},
+				{Version: 1, Name: "Raptors", ClientID: "first-client", CreatedAt: createdAt},
+			}
+
+			request, err := http.NewRequest("GET", "/templates/some-template-id/versions", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewListVersionsHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{
+				"versions": [
+					{"version": 2, "name": "Raptors", "client_id": "second-client", "created_at": "2015-06-01T12:00:00Z"},
+					{"version": 1, "name": "Raptors", "client_id": "first-client", "created_at": "2015-06-01T12:00:00Z"}
+				]
+			}`))
+
+			Expect(collection.ListCall.Receives.Connection).To(Equal(conn))
+			Expect(collection.ListCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		It("delegates errors to the error writer", func() {
+			collection.ListCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			request, err := http.NewRequest("GET", "/templates/missing-template-id/versions", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewListVersionsHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("GetVersionHandler", func() {
+		It("returns the requested version", func() {
+			collection.GetCall.Returns.Version = collections.TemplateVersion{
+				Version:   3,
+				Name:      "Raptors",
+				Subject:   "{{.Subject}}",
+				Text:      "run",
+				HTML:      "<p>run</p>",
+				Markdown:  "*run*",
+				Metadata:  `{"tag": "raptor"}`,
+				ClientID:  "some-client",
+				CreatedAt: createdAt,
+			}
+
+			request, err := http.NewRequest("GET", "/templates/some-template-id/versions/3", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewGetVersionHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{
+				"version": 3,
+				"name": "Raptors",
+				"subject": "{{.Subject}}",
+				"text": "run",
+				"html": "<p>run</p>",
+				"markdown": "*run*",
+				"layout": "",
+				"metadata": {"tag": "raptor"},
+				"client_id": "some-client",
+				"created_at": "2015-06-01T12:00:00Z"
+			}`))
+
+			Expect(collection.GetCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(collection.GetCall.Receives.Version).To(Equal(3))
+		})
+
+		It("reports versions that are not numbers as not found", func() {
+			request, err := http.NewRequest("GET", "/templates/some-template-id/versions/latest", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewGetVersionHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+	})
+
+	Describe("DiffVersionsHandler", func() {
+		It("returns the fields that changed between the versions", func() {
+			collection.DiffCall.Returns.Diff = collections.TemplateDiff{
+				From:     1,
+				To:       2,
+				Subject:  "-{{.Subject}}\n+Alert: {{.Subject}}",
+				Markdown: " run\n+hide",
+			}
+
+			request, err := http.NewRequest("GET", "/templates/some-template-id/diff?from=1&to=2", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewDiffVersionsHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{
+				"from": 1,
+				"to": 2,
+				"changes": {
+					"subject": "-{{.Subject}}\n+Alert: {{.Subject}}",
+					"markdown": " run\n+hide"
+				}
+			}`))
+
+			Expect(collection.DiffCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(collection.DiffCall.Receives.From).To(Equal(1))
+			Expect(collection.DiffCall.Receives.To).To(Equal(2))
+		})
+
+		It("requires both versions", func() {
+			request, err := http.NewRequest("GET", "/templates/some-template-id/diff?from=1", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewDiffVersionsHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"to" must be a version number`)}))
+		})
+	})
+
+	Describe("RollbackHandler", func() {
+		It("rolls the template back to the version", func() {
+			collection.RollbackCall.Returns.Version = collections.TemplateVersion{
+				Version:   4,
+				Name:      "Raptors",
+				Subject:   "{{.Subject}}",
+				Text:      "run",
+				HTML:      "<p>run</p>",
+				Metadata:  "{}",
+				ClientID:  "some-client-id",
+				CreatedAt: createdAt,
+			}
+
+			request, err := http.NewRequest("POST", "/templates/some-template-id/versions/1/rollback", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewRollbackHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body).To(MatchJSON(`{
+				"version": 4,
+				"name": "Raptors",
+				"subject": "{{.Subject}}",
+				"text": "run",
+				"html": "<p>run</p>",
+				"markdown": "",
+				"layout": "",
+				"metadata": {},
+				"client_id": "some-client-id",
+				"created_at": "2015-06-01T12:00:00Z"
+			}`))
+
+			Expect(collection.RollbackCall.Receives.Connection).To(Equal(conn))
+			Expect(collection.RollbackCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(collection.RollbackCall.Receives.Version).To(Equal(1))
+			Expect(collection.RollbackCall.Receives.ClientID).To(Equal("some-client-id"))
+		})
+
+		It("delegates errors to the error writer", func() {
+			collection.RollbackCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			request, err := http.NewRequest("POST", "/templates/some-template-id/versions/9/rollback", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			templates.NewRollbackHandler(collection, errorWriter).ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+})
@@ -18,7 +18,7 @@ func NewErrorWriter() ErrorWriter {
 
 func (writer ErrorWriter) Write(w http.ResponseWriter, err error) {
 	switch err.(type) {
-	case UAAScopesError, CriticalNotificationError, collections.TemplateAssignmentError, MissingUserTokenError, ValidationError:
+	case UAAScopesError, CriticalNotificationError, collections.TemplateAssignmentError, collections.SenderIdentityError, collections.TemplatePartialError, collections.TemplateImportError, collections.TemplateRollbackError, models.PageKeyError, MissingUserTokenError, ValidationError:
 		w.WriteHeader(422)
 	case services.CCDownError:
 		w.WriteHeader(http.StatusBadGateway)
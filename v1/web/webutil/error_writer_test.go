@@ -130,6 +130,46 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 422 when a sender identity is invalid", func() {
+		writer.Write(recorder, collections.SenderIdentityError{Err: errors.New("The sender identity is invalid")})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["The sender identity is invalid"]
+		}`))
+	})
+
+	It("returns a 422 when template partials do not resolve", func() {
+		writer.Write(recorder, collections.TemplatePartialError{Err: errors.New(`partial "footer" does not exist`)})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["partial \"footer\" does not exist"]
+		}`))
+	})
+
+	It("returns a 422 when a template version cannot be restored", func() {
+		writer.Write(recorder, collections.TemplateRollbackError{Err: errors.New("locale variants were saved against versions newer than 1: de")})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["locale variants were saved against versions newer than 1: de"]
+		}`))
+	})
+
+	It("returns a 422 when a template bundle cannot be imported", func() {
+		writer.Write(recorder, collections.TemplateImportError{Err: errors.New("bundle version 2 is not supported, expected 1")})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["bundle version 2 is not supported, expected 1"]
+		}`))
+	})
+
+	It("returns a 422 when a cursor belongs to another listing", func() {
+		writer.Write(recorder, models.NewPageKeyError())
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["cursor does not belong to this listing"]
+		}`))
+	})
+
 	It("returns a 422 when a user token was expected but is not present", func() {
 		writer.Write(recorder, webutil.MissingUserTokenError{Err: errors.New("Missing user_id from token claims.")})
 		Expect(recorder.Code).To(Equal(422))
@@ -0,0 +1,105 @@
+package webutil
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+// NewPage reads the limit and cursor of a paginated listing from the query.
+// A request that does not name a limit gets DefaultPageLimit records.
+func NewPage(query url.Values) (models.Page, error) {
+	page := models.Page{Limit: DefaultPageLimit}
+
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		page.Limit, err = strconv.Atoi(limit)
+		if err != nil || page.Limit < 1 || page.Limit > MaxPageLimit {
+			return models.Page{}, ValidationError{Err: fmt.Errorf(`"limit" must be a number between 1 and %d`, MaxPageLimit)}
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return models.Page{}, ValidationError{Err: errors.New(`"cursor" is not valid`)}
+		}
+
+		page.After = after
+	}
+
+	return page, nil
+}
+
+// NewLegacyPage reads the page of a listing that returned every record before
+// listings were paginated. Such a listing is only paginated when the request
+// names a limit or a cursor, so that clients relying on complete responses
+// keep getting them.
+func NewLegacyPage(query url.Values) (models.Page, error) {
+	if query.Get("limit") == "" && query.Get("cursor") == "" {
+		return models.Page{}, nil
+	}
+
+	return NewPage(query)
+}
+
+// WriteNextPageLink points the Link header at the page following the
+// current one, keeping the other query parameters of the request. Nothing
+// is written for the last page.
+func WriteNextPageLink(w http.ResponseWriter, req *http.Request, page models.Page, next []string) {
+	if next == nil {
+		return
+	}
+
+	query := req.URL.Query()
+	query.Set("cursor", encodeCursor(next))
+	query.Set("limit", strconv.Itoa(page.Limit))
+
+	link := url.URL{
+		Path:     req.URL.Path,
+		RawQuery: query.Encode(),
+	}
+
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, link.String()))
+}
+
+// Cursors are opaque to clients: they encode the page key of the last
+// record of a page.
+func encodeCursor(key []string) string {
+	output, err := json.Marshal(key)
+	if err != nil {
+		panic(err) // A list of strings always marshals
+	}
+
+	return base64.RawURLEncoding.EncodeToString(output)
+}
+
+func decodeCursor(cursor string) ([]string, error) {
+	input, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var key []string
+	err = json.Unmarshal(input, &key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) == 0 {
+		return nil, errors.New("cursor is empty")
+	}
+
+	return key, nil
+}
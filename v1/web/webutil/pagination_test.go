@@ -0,0 +1,99 @@
+package webutil_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pagination", func() {
+	Describe("NewPage", func() {
+		It("returns the first page of the default size when neither limit nor cursor is given", func() {
+			page, err := webutil.NewPage(url.Values{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(Equal(models.Page{Limit: webutil.DefaultPageLimit}))
+		})
+
+		It("reads the limit", func() {
+			page, err := webutil.NewPage(url.Values{"limit": {"20"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(Equal(models.Page{Limit: 20}))
+		})
+
+		It("rejects limits that are out of range", func() {
+			for _, limit := range []string{"0", "-1", "501", "many"} {
+				_, err := webutil.NewPage(url.Values{"limit": {limit}})
+				Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"limit" must be a number between 1 and 500`)}))
+			}
+		})
+
+		It("rejects cursors it did not write", func() {
+			_, err := webutil.NewPage(url.Values{"cursor": {"not-a-cursor"}})
+			Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"cursor" is not valid`)}))
+		})
+	})
+
+	Describe("NewLegacyPage", func() {
+		It("selects every record when neither limit nor cursor is given", func() {
+			page, err := webutil.NewLegacyPage(url.Values{"name": {"raptor"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(Equal(models.Page{}))
+			Expect(page.Paginated()).To(BeFalse())
+		})
+
+		It("reads the page like NewPage when a limit is given", func() {
+			page, err := webutil.NewLegacyPage(url.Values{"limit": {"20"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(Equal(models.Page{Limit: 20}))
+
+			_, err = webutil.NewLegacyPage(url.Values{"limit": {"0"}})
+			Expect(err).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+		})
+
+		It("returns pages of the default size when only a cursor is given", func() {
+			page, err := webutil.NewLegacyPage(url.Values{"cursor": {"WyJzb21lLWlkIl0"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(Equal(models.Page{After: []string{"some-id"}, Limit: webutil.DefaultPageLimit}))
+		})
+	})
+
+	Describe("WriteNextPageLink", func() {
+		It("links to the next page with the rest of the query", func() {
+			request, err := http.NewRequest("GET", "/templates?name=raptor&limit=2", nil)
+			Expect(err).NotTo(HaveOccurred())
+			recorder := httptest.NewRecorder()
+
+			webutil.WriteNextPageLink(recorder, request, models.Page{Limit: 2}, []string{"some-client", "some-kind"})
+
+			link := recorder.Header().Get("Link")
+			Expect(link).To(MatchRegexp(`^</templates\?cursor=[\w-]+&limit=2&name=raptor>; rel="next"$`))
+
+			next, err := url.Parse(link[1 : len(link)-len(`>; rel="next"`)])
+			Expect(err).NotTo(HaveOccurred())
+
+			page, err := webutil.NewPage(next.Query())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page).To(Equal(models.Page{
+				After: []string{"some-client", "some-kind"},
+				Limit: 2,
+			}))
+		})
+
+		It("writes nothing on the last page", func() {
+			request, err := http.NewRequest("GET", "/templates?limit=2", nil)
+			Expect(err).NotTo(HaveOccurred())
+			recorder := httptest.NewRecorder()
+
+			webutil.WriteNextPageLink(recorder, request, models.Page{Limit: 2}, nil)
+
+			Expect(recorder.Header()).NotTo(HaveKey("Link"))
+		})
+	})
+})
@@ -27,6 +27,8 @@ type Config struct {
 	UAAClientSecret   string
 	DefaultUAAScopes  []string
 	CCHost            string
+
+	AllowedSenderDomains []string
 }
 
 type Server struct{}